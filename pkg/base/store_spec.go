@@ -185,6 +185,10 @@ type StoreSpec struct {
 	// ExtraOptions is a serialized protobuf set by Go CCL code and passed through
 	// to C CCL code.
 	ExtraOptions []byte
+	// SeparateRaftLog is true if the store keeps its Raft log, along with the
+	// sideloaded payloads of the log entries, in a dedicated engine with its own
+	// WAL, located in RaftLogEngineDir.
+	SeparateRaftLog bool
 }
 
 // String returns a fully parsable version of the store spec.
@@ -218,6 +222,9 @@ func (ss StoreSpec) String() string {
 		fmt.Fprint(&buffer, optsStr)
 		fmt.Fprint(&buffer, ",")
 	}
+	if ss.SeparateRaftLog {
+		fmt.Fprint(&buffer, "raftlog=separate,")
+	}
 	// Trim the extra comma from the end if it exists.
 	if l := buffer.Len(); l > 0 {
 		buffer.Truncate(l - 1)
@@ -255,6 +262,9 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - 20%             -> 20% of the available space
 //   - 0.2             -> 20% of the available space
 // - attrs=xxx:yyy:zzz A colon separated list of optional attributes.
+// - raftlog=separate Keep the Raft log in a dedicated engine (see
+//   SeparateRaftLog). The default, raftlog=shared, keeps it in the store's
+//   engine.
 // Note that commas are forbidden within any field name or value.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
@@ -328,6 +338,15 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 			} else {
 				return StoreSpec{}, fmt.Errorf("%s is not a valid store type", value)
 			}
+		case "raftlog":
+			switch value {
+			case "separate":
+				ss.SeparateRaftLog = true
+			case "shared":
+				ss.SeparateRaftLog = false
+			default:
+				return StoreSpec{}, fmt.Errorf("%s is not a valid raftlog value", value)
+			}
 		case "rocksdb":
 			ss.RocksDBOptions = value
 		case "pebble":
@@ -378,6 +397,9 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 		if ss.Size.Percent == 0 && ss.Size.InBytes == 0 {
 			return StoreSpec{}, fmt.Errorf("size must be specified for an in memory store")
 		}
+		if ss.SeparateRaftLog {
+			return StoreSpec{}, fmt.Errorf("raftlog=separate is not supported for an in memory store")
+		}
 	} else if ss.Path == "" {
 		return StoreSpec{}, fmt.Errorf("no path specified")
 	}
@@ -411,6 +433,11 @@ func (ssl StoreSpecList) String() string {
 // root directory. It must not be changed without a proper migration.
 const AuxiliaryDir = "auxiliary"
 
+// RaftLogEngineDir is the path of the dedicated Raft log engine relative to the
+// store's root directory, for stores using one (see StoreSpec.SeparateRaftLog).
+// It must not be changed without a proper migration.
+const RaftLogEngineDir = "raft-log"
+
 // PreventedStartupFile is the filename (relative to 'dir') used for files that
 // can block server startup.
 func PreventedStartupFile(dir string) string {
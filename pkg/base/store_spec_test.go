@@ -153,6 +153,12 @@ target_file_size=2097152`
 		{fmt.Sprintf("path=/,pebble=%s", examplePebbleOptions), "", StoreSpec{Path: "/", PebbleOptions: examplePebbleOptions}},
 		{"path=/mnt/hda1,pebble=[Options] not_a_real_option=10", "pebble: unknown option: Options.not_a_real_option", StoreSpec{}},
 
+		// Raft log
+		{"path=/mnt/hda1,raftlog=separate", "", StoreSpec{Path: "/mnt/hda1", SeparateRaftLog: true}},
+		{"path=/mnt/hda1,raftlog=shared", "", StoreSpec{Path: "/mnt/hda1"}},
+		{"path=/mnt/hda1,raftlog=other", "other is not a valid raftlog value", StoreSpec{}},
+		{"type=mem,size=20GiB,raftlog=separate", "raftlog=separate is not supported for an in memory store", StoreSpec{}},
+
 		// all together
 		{"path=/mnt/hda1,attrs=hdd:ssd,size=20GiB", "", StoreSpec{
 			Path:       "/mnt/hda1",
@@ -91,6 +91,14 @@ const (
 	SinkParamSASLHandshake    = `sasl_handshake`
 	SinkParamSASLUser         = `sasl_user`
 	SinkParamSASLPassword     = `sasl_password`
+
+	SinkSchemeWebhookHTTPS         = `webhook-https`
+	SinkParamWebhookAuthHeader     = `auth_header`
+	SinkParamWebhookClientTimeout  = `client_timeout`
+	SinkParamWebhookFlushBytes     = `flush_bytes`
+	SinkParamWebhookFlushFrequency = `flush_frequency`
+	SinkParamWebhookFlushMessages  = `flush_messages`
+	SinkParamWebhookMaxRetries     = `max_retries`
 )
 
 // ChangefeedOptionExpectValues is used to parse changefeed options using
@@ -200,6 +200,14 @@ func getSink(
 				opts, timestampOracle, makeExternalStorageFromURI, user,
 			)
 		}
+	case u.Scheme == changefeedbase.SinkSchemeWebhookHTTPS:
+		cfg, err := getWebhookSinkConfig(q)
+		if err != nil {
+			return nil, err
+		}
+		makeSink = func() (Sink, error) {
+			return makeWebhookSink(u, cfg, opts, targets)
+		}
 	case u.Scheme == changefeedbase.SinkSchemeExperimentalSQL:
 		// Swap the changefeed prefix for the sql connection one that sqlSink
 		// expects.
@@ -313,7 +321,7 @@ type kafkaSink struct {
 	cfg      kafkaSinkConfig
 	client   sarama.Client
 	producer sarama.AsyncProducer
-	topics   map[string]struct{}
+	topics   *TopicNamer
 
 	lastMetadataRefresh time.Time
 
@@ -333,10 +341,9 @@ type kafkaSink struct {
 func makeKafkaSink(
 	cfg kafkaSinkConfig, bootstrapServers string, targets jobspb.ChangefeedTargets,
 ) (Sink, error) {
-	sink := &kafkaSink{cfg: cfg}
-	sink.topics = make(map[string]struct{})
-	for _, t := range targets {
-		sink.topics[cfg.kafkaTopicPrefix+SQLNameToKafkaName(t.StatementTimeName)] = struct{}{}
+	sink := &kafkaSink{
+		cfg:    cfg,
+		topics: MakeTopicNamer(targets, cfg.kafkaTopicPrefix, SQLNameToKafkaName),
 	}
 
 	config := sarama.NewConfig()
@@ -466,9 +473,9 @@ func (s *kafkaSink) Close() error {
 func (s *kafkaSink) EmitRow(
 	ctx context.Context, table catalog.TableDescriptor, key, value []byte, updated hlc.Timestamp,
 ) error {
-	topic := s.cfg.kafkaTopicPrefix + SQLNameToKafkaName(table.GetName())
-	if _, ok := s.topics[topic]; !ok {
-		return errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	topic, err := s.topics.Name(table)
+	if err != nil {
+		return err
 	}
 
 	msg := &sarama.ProducerMessage{
@@ -492,17 +499,13 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 	// actively working on stability. At the same time, revisit this tuning.
 	const metadataRefreshMinDuration = time.Minute
 	if timeutil.Since(s.lastMetadataRefresh) > metadataRefreshMinDuration {
-		topics := make([]string, 0, len(s.topics))
-		for topic := range s.topics {
-			topics = append(topics, topic)
-		}
-		if err := s.client.RefreshMetadata(topics...); err != nil {
+		if err := s.client.RefreshMetadata(s.topics.Topics()...); err != nil {
 			return err
 		}
 		s.lastMetadataRefresh = timeutil.Now()
 	}
 
-	for topic := range s.topics {
+	for _, topic := range s.topics.Topics() {
 		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
 		if err != nil {
 			return err
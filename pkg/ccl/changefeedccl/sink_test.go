@@ -66,7 +66,9 @@ func TestKafkaSink(t *testing.T) {
 	}
 	sink := &kafkaSink{
 		producer: p,
-		topics:   map[string]struct{}{`t`: {}},
+		topics: MakeTopicNamer(jobspb.ChangefeedTargets{
+			0: {StatementTimeName: `t`},
+		}, `` /* prefix */, SQLNameToKafkaName),
 	}
 	sink.start()
 	defer func() {
@@ -156,7 +158,9 @@ func TestKafkaSinkEscaping(t *testing.T) {
 	}
 	sink := &kafkaSink{
 		producer: p,
-		topics:   map[string]struct{}{SQLNameToKafkaName(`☃`): {}},
+		topics: MakeTopicNamer(jobspb.ChangefeedTargets{
+			0: {StatementTimeName: `☃`},
+		}, `` /* prefix */, SQLNameToKafkaName),
 	}
 	sink.start()
 	defer func() { require.NoError(t, sink.Close()) }()
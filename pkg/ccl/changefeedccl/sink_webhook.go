@@ -0,0 +1,512 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	gojson "encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	defaultWebhookClientTimeout  = 3 * time.Second
+	defaultWebhookFlushMessages  = 100
+	defaultWebhookFlushBytes     = 1 << 20 // 1MB
+	defaultWebhookFlushFrequency = time.Second
+	defaultWebhookMaxRetries     = 3
+
+	// webhookSinkQueuedBatches is the number of batches which may be waiting to
+	// be sent before EmitRow blocks.
+	webhookSinkQueuedBatches = 8
+)
+
+type webhookSinkConfig struct {
+	topicPrefix    string
+	tlsSkipVerify  bool
+	caCert         []byte
+	clientCert     []byte
+	clientKey      []byte
+	authHeader     string
+	clientTimeout  time.Duration
+	flushMessages  int
+	flushBytes     int64
+	flushFrequency time.Duration
+	maxRetries     int
+}
+
+// getWebhookSinkConfig parses the configuration of a webhook sink from the
+// query parameters of its URI, removing every parameter it knows about.
+func getWebhookSinkConfig(q url.Values) (webhookSinkConfig, error) {
+	cfg := webhookSinkConfig{
+		clientTimeout:  defaultWebhookClientTimeout,
+		flushMessages:  defaultWebhookFlushMessages,
+		flushBytes:     defaultWebhookFlushBytes,
+		flushFrequency: defaultWebhookFlushFrequency,
+		maxRetries:     defaultWebhookMaxRetries,
+	}
+	var err error
+
+	cfg.topicPrefix = q.Get(changefeedbase.SinkParamTopicPrefix)
+	q.Del(changefeedbase.SinkParamTopicPrefix)
+
+	if tlsVerifyBool := q.Get(changefeedbase.SinkParamSkipTLSVerify); tlsVerifyBool != `` {
+		if cfg.tlsSkipVerify, err = strconv.ParseBool(tlsVerifyBool); err != nil {
+			return cfg, errors.Errorf(`param %s must be a bool: %s`, changefeedbase.SinkParamSkipTLSVerify, err)
+		}
+	}
+	q.Del(changefeedbase.SinkParamSkipTLSVerify)
+	for _, p := range []struct {
+		param string
+		dest  *[]byte
+	}{
+		{changefeedbase.SinkParamCACert, &cfg.caCert},
+		{changefeedbase.SinkParamClientCert, &cfg.clientCert},
+		{changefeedbase.SinkParamClientKey, &cfg.clientKey},
+	} {
+		if encoded := q.Get(p.param); encoded != `` {
+			if *p.dest, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+				return cfg, errors.Errorf(`param %s must be base 64 encoded: %s`, p.param, err)
+			}
+		}
+		q.Del(p.param)
+	}
+
+	cfg.authHeader = q.Get(changefeedbase.SinkParamWebhookAuthHeader)
+	q.Del(changefeedbase.SinkParamWebhookAuthHeader)
+
+	for _, p := range []struct {
+		param string
+		dest  *time.Duration
+	}{
+		{changefeedbase.SinkParamWebhookClientTimeout, &cfg.clientTimeout},
+		{changefeedbase.SinkParamWebhookFlushFrequency, &cfg.flushFrequency},
+	} {
+		if v := q.Get(p.param); v != `` {
+			if *p.dest, err = time.ParseDuration(v); err != nil {
+				return cfg, pgerror.Wrapf(err, pgcode.Syntax, `parsing %s`, p.param)
+			}
+			if *p.dest <= 0 {
+				return cfg, errors.Errorf(`param %s must be positive: %s`, p.param, v)
+			}
+		}
+		q.Del(p.param)
+	}
+
+	if v := q.Get(changefeedbase.SinkParamWebhookFlushBytes); v != `` {
+		if cfg.flushBytes, err = humanizeutil.ParseBytes(v); err != nil {
+			return cfg, pgerror.Wrapf(err, pgcode.Syntax, `parsing %s`, v)
+		}
+		if cfg.flushBytes <= 0 {
+			return cfg, errors.Errorf(`param %s must be positive: %s`,
+				changefeedbase.SinkParamWebhookFlushBytes, v)
+		}
+	}
+	q.Del(changefeedbase.SinkParamWebhookFlushBytes)
+
+	if v := q.Get(changefeedbase.SinkParamWebhookFlushMessages); v != `` {
+		if cfg.flushMessages, err = strconv.Atoi(v); err != nil || cfg.flushMessages <= 0 {
+			return cfg, errors.Errorf(`param %s must be a positive integer: %s`,
+				changefeedbase.SinkParamWebhookFlushMessages, v)
+		}
+	}
+	q.Del(changefeedbase.SinkParamWebhookFlushMessages)
+
+	if v := q.Get(changefeedbase.SinkParamWebhookMaxRetries); v != `` {
+		if cfg.maxRetries, err = strconv.Atoi(v); err != nil || cfg.maxRetries < 0 {
+			return cfg, errors.Errorf(`param %s must be a non-negative integer: %s`,
+				changefeedbase.SinkParamWebhookMaxRetries, v)
+		}
+	}
+	q.Del(changefeedbase.SinkParamWebhookMaxRetries)
+
+	return cfg, nil
+}
+
+// webhookSink emits to an HTTPS endpoint. Rows are batched into JSON payloads
+// of the form `{"payload": [<message>, ...], "length": <n>}` which are POSTed
+// in order by a single worker goroutine, retrying with backoff on failure.
+// Client errors are not retried, except for 408 and 429 responses. Each message
+// is an object with the `topic`, `key` and `value` of a row, where the topic is
+// named the same way as by the Kafka sink. A batch is sent once it reaches
+// flush_messages or flush_bytes, or once it is flush_frequency old, even if no
+// more rows are emitted. Resolved timestamps are POSTed on their own, but only
+// once every preceding batch has been acknowledged.
+//
+// It is not concurrency-safe; all calls to Emit and Flush should be from the
+// same goroutine.
+type webhookSink struct {
+	cfg       webhookSinkConfig
+	url       string
+	topics    *TopicNamer
+	client    *http.Client
+	retryOpts retry.Options
+
+	// batch holds the messages which have not yet been handed to the worker.
+	// It is shared between the client goroutine and the flush timer goroutine.
+	batch struct {
+		syncutil.Mutex
+		buf      bytes.Buffer
+		messages int
+		started  time.Time
+	}
+	// batchStartedCh is signaled when the first message of a batch is buffered,
+	// which starts the flush timer.
+	batchStartedCh chan struct{}
+
+	batchCh      chan []byte
+	cancelWorker context.CancelFunc
+	worker       sync.WaitGroup
+
+	// Only synchronized between the client goroutine and the worker goroutine.
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+// webhookMessage is the encoding of a single row in a webhook payload.
+type webhookMessage struct {
+	Topic string            `json:"topic"`
+	Key   gojson.RawMessage `json:"key"`
+	Value gojson.RawMessage `json:"value"`
+}
+
+func makeWebhookSink(
+	u *url.URL, cfg webhookSinkConfig, opts map[string]string, targets jobspb.ChangefeedTargets,
+) (Sink, error) {
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.tlsSkipVerify}
+	if cfg.caCert != nil {
+		caCertPool, err := x509.SystemCertPool()
+		if err != nil || caCertPool == nil {
+			caCertPool = x509.NewCertPool()
+		}
+		if !caCertPool.AppendCertsFromPEM(cfg.caCert) {
+			return nil, errors.Errorf(`invalid %s provided`, changefeedbase.SinkParamCACert)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	if cfg.clientCert != nil {
+		if cfg.clientKey == nil {
+			return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+		}
+		cert, err := tls.X509KeyPair(cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			return nil, errors.Errorf(`invalid client certificate data provided: %s`, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if cfg.clientKey != nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+
+	endpoint := *u
+	endpoint.Scheme = `https`
+	endpoint.RawQuery = ``
+
+	sink := &webhookSink{
+		cfg:    cfg,
+		url:    endpoint.String(),
+		topics: MakeTopicNamer(targets, cfg.topicPrefix, SQLNameToKafkaName),
+		client: &http.Client{
+			Timeout:   cfg.clientTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		retryOpts: retry.Options{
+			InitialBackoff: 500 * time.Millisecond,
+			MaxBackoff:     30 * time.Second,
+			Multiplier:     2,
+		},
+	}
+	sink.start()
+	return sink, nil
+}
+
+func (s *webhookSink) start() {
+	s.batchCh = make(chan []byte, webhookSinkQueuedBatches)
+	s.batchStartedCh = make(chan struct{}, 1)
+	var ctx context.Context
+	ctx, s.cancelWorker = context.WithCancel(context.Background())
+	s.worker.Add(2)
+	go s.workerLoop(ctx)
+	go s.flushTimerLoop(ctx)
+}
+
+// Close implements the Sink interface.
+func (s *webhookSink) Close() error {
+	s.cancelWorker()
+	s.worker.Wait()
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *webhookSink) EmitRow(
+	ctx context.Context, table catalog.TableDescriptor, key, value []byte, updated hlc.Timestamp,
+) error {
+	topic, err := s.topics.Name(table)
+	if err != nil {
+		return err
+	}
+	msg, err := gojson.Marshal(webhookMessage{
+		Topic: topic,
+		Key:   key,
+		Value: value,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.batch.Lock()
+	defer s.batch.Unlock()
+	if s.batch.messages == 0 {
+		s.batch.started = timeutil.Now()
+		select {
+		case s.batchStartedCh <- struct{}{}:
+		default:
+		}
+	} else {
+		s.batch.buf.WriteByte(',')
+	}
+	s.batch.buf.Write(msg)
+	s.batch.messages++
+
+	if s.batch.messages >= s.cfg.flushMessages ||
+		int64(s.batch.buf.Len()) >= s.cfg.flushBytes ||
+		timeutil.Since(s.batch.started) >= s.cfg.flushFrequency {
+		return s.sendBatchLocked(ctx)
+	}
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *webhookSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	// A resolved timestamp promises that every row at or below it has been
+	// delivered, so it may only be sent once all the preceding batches have
+	// been acknowledged.
+	if err := s.Flush(ctx); err != nil {
+		return err
+	}
+	payload, err := encoder.EncodeResolvedTimestamp(ctx, `` /* topic */, resolved)
+	if err != nil {
+		return err
+	}
+	return s.sendWithRetry(ctx, payload)
+}
+
+// Flush implements the Sink interface.
+func (s *webhookSink) Flush(ctx context.Context) error {
+	s.batch.Lock()
+	err := s.sendBatchLocked(ctx)
+	s.batch.Unlock()
+	if err != nil {
+		return err
+	}
+
+	flushCh := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	flushErr := s.mu.flushErr
+	s.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight batches", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		s.mu.Lock()
+		flushErr := s.mu.flushErr
+		s.mu.flushErr = nil
+		s.mu.Unlock()
+		return flushErr
+	}
+}
+
+// sendBatchLocked hands the buffered messages, if any, to the worker as a
+// single payload. The batch lock must be held, which keeps the payloads in the
+// order their messages were emitted.
+func (s *webhookSink) sendBatchLocked(ctx context.Context) error {
+	if s.batch.messages == 0 {
+		return nil
+	}
+	payload := make([]byte, 0, s.batch.buf.Len()+32)
+	payload = append(payload, `{"payload":[`...)
+	payload = append(payload, s.batch.buf.Bytes()...)
+	payload = append(payload, `],"length":`...)
+	payload = strconv.AppendInt(payload, int64(s.batch.messages), 10)
+	payload = append(payload, '}')
+	s.batch.buf.Reset()
+	s.batch.messages = 0
+
+	s.mu.Lock()
+	s.mu.inflight++
+	s.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		s.mu.Lock()
+		s.mu.inflight--
+		s.mu.Unlock()
+		return ctx.Err()
+	case s.batchCh <- payload:
+	}
+	return nil
+}
+
+// flushTimerLoop sends the buffered batch once it is flush_frequency old, so
+// that rows are delivered promptly even when no more are emitted after them.
+func (s *webhookSink) flushTimerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	timer := timeutil.NewTimer()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.batchStartedCh:
+			timer.Reset(s.cfg.flushFrequency)
+		case <-timer.C:
+			timer.Read = true
+			s.batch.Lock()
+			if s.batch.messages > 0 {
+				if age := timeutil.Since(s.batch.started); age < s.cfg.flushFrequency {
+					// The batch which started the timer has already been sent;
+					// wait for the current one to come due.
+					timer.Reset(s.cfg.flushFrequency - age)
+				} else if err := s.sendBatchLocked(ctx); err != nil {
+					// The only error is the cancellation of the context, which
+					// happens when the sink is closed.
+					s.batch.Unlock()
+					return
+				}
+			}
+			s.batch.Unlock()
+		}
+	}
+}
+
+func (s *webhookSink) workerLoop(ctx context.Context) {
+	defer s.worker.Done()
+
+	for {
+		var payload []byte
+		select {
+		case <-ctx.Done():
+			return
+		case payload = <-s.batchCh:
+		}
+
+		err := s.sendWithRetry(ctx, payload)
+
+		s.mu.Lock()
+		if err != nil && s.mu.flushErr == nil {
+			s.mu.flushErr = err
+		}
+		s.mu.inflight--
+		if s.mu.inflight == 0 && s.mu.flushCh != nil {
+			s.mu.flushCh <- struct{}{}
+			s.mu.flushCh = nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+// errWebhookRequestRejected marks responses which indicate that the endpoint
+// will never accept the request, so that it is not retried.
+var errWebhookRequestRejected = errors.New(`webhook request rejected`)
+
+func (s *webhookSink) sendWithRetry(ctx context.Context, payload []byte) error {
+	var err error
+	// Next always returns true on its first call, so err is set when the loop
+	// exits. The attempts are counted here since a zero MaxRetries means
+	// retrying forever.
+	for r, attempt := retry.StartWithCtx(ctx, s.retryOpts), 0; r.Next(); attempt++ {
+		if err = s.send(ctx, payload); err == nil {
+			return nil
+		}
+		log.Warningf(ctx, "webhook sink request failed: %v", err)
+		if errors.Is(err, errWebhookRequestRejected) || attempt >= s.cfg.maxRetries {
+			break
+		}
+	}
+	return err
+}
+
+func (s *webhookSink) send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.authHeader != `` {
+		req.Header.Set("Authorization", s.cfg.authHeader)
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1<<10))
+		err := errors.Errorf(`webhook sink responded with %s: %s`, res.Status, body)
+		// Client errors are permanent, except for timeouts and rate limiting.
+		if res.StatusCode >= http.StatusBadRequest && res.StatusCode < http.StatusInternalServerError &&
+			res.StatusCode != http.StatusRequestTimeout && res.StatusCode != http.StatusTooManyRequests {
+			return errors.Mark(err, errWebhookRequestRejected)
+		}
+		return err
+	}
+	_, err = io.Copy(ioutil.Discard, res.Body)
+	return err
+}
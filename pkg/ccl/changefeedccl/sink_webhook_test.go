@@ -0,0 +1,338 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/securitytest"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookEndpoint is an HTTPS server which records the payloads POSTed to it.
+type webhookEndpoint struct {
+	*httptest.Server
+
+	mu struct {
+		syncutil.Mutex
+		payloads []string
+		auth     []string
+		// failures is the number of requests to fail before succeeding.
+		failures int
+		// failureStatus is the status of failed requests, 503 by default.
+		failureStatus int
+		requests      int
+	}
+}
+
+func makeWebhookEndpoint(t *testing.T, tlsConfig *tls.Config) *webhookEndpoint {
+	e := &webhookEndpoint{}
+	e.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.mu.requests++
+		if e.mu.failures > 0 {
+			e.mu.failures--
+			status := e.mu.failureStatus
+			if status == 0 {
+				status = http.StatusServiceUnavailable
+			}
+			http.Error(w, `try again`, status)
+			return
+		}
+		e.mu.payloads = append(e.mu.payloads, string(body))
+		e.mu.auth = append(e.mu.auth, r.Header.Get(`Authorization`))
+	}))
+	e.Server.TLS = tlsConfig
+	e.Server.StartTLS()
+	return e
+}
+
+func (e *webhookEndpoint) sinkURI(params url.Values) string {
+	u, _ := url.Parse(e.URL)
+	u.Scheme = changefeedbase.SinkSchemeWebhookHTTPS
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+func (e *webhookEndpoint) caCert() string {
+	cert := pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: e.Certificate().Raw})
+	return base64.StdEncoding.EncodeToString(cert)
+}
+
+func (e *webhookEndpoint) payloads() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.mu.payloads...)
+}
+
+func TestWebhookSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	table := tabledesc.NewImmutable(descpb.TableDescriptor{ID: 52, Name: `foo`})
+	targets := jobspb.ChangefeedTargets{52: {StatementTimeName: `foo`}}
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	makeSink := func(sinkURI string) (Sink, error) {
+		return getSink(ctx, sinkURI, 0 /* nodeID */, opts, targets, nil, /* settings */
+			nil /* timestampOracle */, nil /* makeExternalStorageFromURI */, security.RootUserName())
+	}
+
+	t.Run(`batches`, func(t *testing.T) {
+		e := makeWebhookEndpoint(t, nil /* tlsConfig */)
+		defer e.Close()
+
+		sink, err := makeSink(e.sinkURI(url.Values{
+			changefeedbase.SinkParamCACert:               {e.caCert()},
+			changefeedbase.SinkParamWebhookAuthHeader:    {`Basic dXNlcjpwYXNz`},
+			changefeedbase.SinkParamWebhookFlushMessages: {`2`},
+		}))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		// The first two rows fill a batch, which is sent without waiting for a
+		// flush.
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{"after": {"a": 1}}`), zeroTS))
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[2]`), []byte(`{"after": {"a": 2}}`), zeroTS))
+		testutils.SucceedsSoon(t, func() error {
+			if len(e.payloads()) != 1 {
+				return errors.New(`waiting for batch`)
+			}
+			return nil
+		})
+		// The third row is only sent by Flush. Rows without a value, such as
+		// those of key-only changefeeds, still produce valid JSON.
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[3]`), nil /* value */, zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+
+		// The resolved timestamp comes after all the rows.
+		encoder, err := makeJSONEncoder(opts)
+		require.NoError(t, err)
+		require.NoError(t, sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 1}))
+
+		require.Equal(t, []string{
+			`{"payload":[` +
+				`{"topic":"foo","key":[1],"value":{"after":{"a":1}}},` +
+				`{"topic":"foo","key":[2],"value":{"after":{"a":2}}}` +
+				`],"length":2}`,
+			`{"payload":[{"topic":"foo","key":[3],"value":null}],"length":1}`,
+			`{"resolved":"1.0000000000"}`,
+		}, e.payloads())
+		e.mu.Lock()
+		require.Equal(t, []string{`Basic dXNlcjpwYXNz`, `Basic dXNlcjpwYXNz`, `Basic dXNlcjpwYXNz`}, e.mu.auth)
+		e.mu.Unlock()
+	})
+
+	t.Run(`flush frequency`, func(t *testing.T) {
+		e := makeWebhookEndpoint(t, nil /* tlsConfig */)
+		defer e.Close()
+
+		sink, err := makeSink(e.sinkURI(url.Values{
+			changefeedbase.SinkParamSkipTLSVerify:         {`true`},
+			changefeedbase.SinkParamTopicPrefix:           {`pre_`},
+			changefeedbase.SinkParamWebhookFlushFrequency: {`10ms`},
+			changefeedbase.SinkParamWebhookFlushMessages:  {`100`},
+		}))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sink.Close()) }()
+
+		// A batch is sent once it is flush_frequency old, even though no more
+		// rows are emitted and Flush is never called.
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		testutils.SucceedsSoon(t, func() error {
+			if len(e.payloads()) != 1 {
+				return errors.New(`waiting for batch`)
+			}
+			return nil
+		})
+		require.Equal(t, []string{
+			`{"payload":[{"topic":"pre_foo","key":[1],"value":{}}],"length":1}`,
+		}, e.payloads())
+
+		// Rows of tables which are not targets of the changefeed are rejected.
+		other := tabledesc.NewImmutable(descpb.TableDescriptor{ID: 53, Name: `bar`})
+		require.EqualError(t, sink.EmitRow(ctx, other, []byte(`[1]`), []byte(`{}`), zeroTS),
+			`cannot emit to undeclared topic: pre_bar`)
+	})
+
+	t.Run(`retries`, func(t *testing.T) {
+		e := makeWebhookEndpoint(t, nil /* tlsConfig */)
+		defer e.Close()
+
+		sink, err := makeSink(e.sinkURI(url.Values{
+			changefeedbase.SinkParamSkipTLSVerify:     {`true`},
+			changefeedbase.SinkParamWebhookMaxRetries: {`2`},
+		}))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sink.Close()) }()
+		s := sink.(*webhookSink)
+		s.retryOpts.InitialBackoff = time.Millisecond
+		s.retryOpts.MaxBackoff = time.Millisecond
+
+		// Two failures are retried.
+		e.mu.Lock()
+		e.mu.failures = 2
+		e.mu.Unlock()
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.Len(t, e.payloads(), 1)
+
+		// Three failures exhaust the retries.
+		e.mu.Lock()
+		e.mu.failures = 3
+		e.mu.Unlock()
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[2]`), []byte(`{}`), zeroTS))
+		require.Regexp(t, `503 Service Unavailable: try again`, sink.Flush(ctx))
+		require.Len(t, e.payloads(), 1)
+
+		// A failed batch prevents a resolved timestamp from being emitted.
+		e.mu.Lock()
+		e.mu.failures = 3
+		e.mu.Unlock()
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[3]`), []byte(`{}`), zeroTS))
+		encoder, err := makeJSONEncoder(opts)
+		require.NoError(t, err)
+		require.Regexp(t, `503 Service Unavailable`,
+			sink.EmitResolvedTimestamp(ctx, encoder, hlc.Timestamp{WallTime: 1}))
+		require.Len(t, e.payloads(), 1)
+	})
+
+	t.Run(`client errors`, func(t *testing.T) {
+		e := makeWebhookEndpoint(t, nil /* tlsConfig */)
+		defer e.Close()
+
+		sink, err := makeSink(e.sinkURI(url.Values{
+			changefeedbase.SinkParamSkipTLSVerify:     {`true`},
+			changefeedbase.SinkParamWebhookMaxRetries: {`2`},
+		}))
+		require.NoError(t, err)
+		defer func() { require.NoError(t, sink.Close()) }()
+		s := sink.(*webhookSink)
+		s.retryOpts.InitialBackoff = time.Millisecond
+		s.retryOpts.MaxBackoff = time.Millisecond
+
+		// Timeouts and rate limiting are retried.
+		for i, status := range []int{http.StatusRequestTimeout, http.StatusTooManyRequests} {
+			e.mu.Lock()
+			e.mu.failures, e.mu.failureStatus, e.mu.requests = 2, status, 0
+			e.mu.Unlock()
+			require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+			require.NoError(t, sink.Flush(ctx))
+			require.Len(t, e.payloads(), i+1)
+			e.mu.Lock()
+			require.Equal(t, 3, e.mu.requests)
+			e.mu.Unlock()
+		}
+
+		// Other client errors are not.
+		e.mu.Lock()
+		e.mu.failures, e.mu.failureStatus, e.mu.requests = 1, http.StatusBadRequest, 0
+		e.mu.Unlock()
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[2]`), []byte(`{}`), zeroTS))
+		require.Regexp(t, `400 Bad Request: try again`, sink.Flush(ctx))
+		require.Len(t, e.payloads(), 2)
+		e.mu.Lock()
+		require.Equal(t, 1, e.mu.requests)
+		e.mu.Unlock()
+	})
+
+	t.Run(`client certs`, func(t *testing.T) {
+		e := makeWebhookEndpoint(t, &tls.Config{ClientAuth: tls.RequireAnyClientCert})
+		defer e.Close()
+
+		readAsset := func(name string) string {
+			b, err := securitytest.EmbeddedAssets.ReadFile(filepath.Join(security.EmbeddedCertsDir, name))
+			require.NoError(t, err)
+			return base64.StdEncoding.EncodeToString(b)
+		}
+
+		// Without a client certificate, the server rejects the connection.
+		sink, err := makeSink(e.sinkURI(url.Values{
+			changefeedbase.SinkParamCACert:            {e.caCert()},
+			changefeedbase.SinkParamWebhookMaxRetries: {`0`},
+		}))
+		require.NoError(t, err)
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		require.Regexp(t, `tls|certificate|EOF`, sink.Flush(ctx))
+		require.NoError(t, sink.Close())
+
+		sink, err = makeSink(e.sinkURI(url.Values{
+			changefeedbase.SinkParamCACert:     {e.caCert()},
+			changefeedbase.SinkParamClientCert: {readAsset(security.EmbeddedRootCert)},
+			changefeedbase.SinkParamClientKey:  {readAsset(security.EmbeddedRootKey)},
+		}))
+		require.NoError(t, err)
+		require.NoError(t, sink.EmitRow(ctx, table, []byte(`[1]`), []byte(`{}`), zeroTS))
+		require.NoError(t, sink.Flush(ctx))
+		require.NoError(t, sink.Close())
+		require.Len(t, e.payloads(), 1)
+	})
+
+	t.Run(`validation`, func(t *testing.T) {
+		for _, tc := range []struct {
+			params url.Values
+			err    string
+		}{
+			{
+				params: url.Values{changefeedbase.SinkParamClientCert: {`Zm9v`}},
+				err:    `client_cert requires client_key to be set`,
+			},
+			{
+				params: url.Values{changefeedbase.SinkParamWebhookFlushMessages: {`0`}},
+				err:    `param flush_messages must be a positive integer: 0`,
+			},
+			{
+				params: url.Values{changefeedbase.SinkParamWebhookFlushFrequency: {`soon`}},
+				err:    `parsing flush_frequency`,
+			},
+			{
+				params: url.Values{`foo`: {`bar`}},
+				err:    `unknown sink query parameter: foo`,
+			},
+		} {
+			u := url.URL{Scheme: changefeedbase.SinkSchemeWebhookHTTPS, Host: `localhost`, RawQuery: tc.params.Encode()}
+			_, err := makeSink(u.String())
+			require.Regexp(t, tc.err, err)
+		}
+
+		avroOpts := map[string]string{changefeedbase.OptFormat: string(changefeedbase.OptFormatAvro)}
+		_, err := getSink(ctx, `webhook-https://localhost`, 0 /* nodeID */, avroOpts, nil, /* targets */
+			nil /* settings */, nil /* timestampOracle */, nil /* makeExternalStorageFromURI */, security.RootUserName())
+		require.Regexp(t, `this sink is incompatible with format=experimental_avro`, err)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/errors"
+)
+
+// TopicNamer maps the tables watched by a changefeed to the names of the
+// topics their rows are emitted to. Sinks which have a notion of a topic share
+// it so that the `topic_prefix` sink parameter and the escaping of table names
+// are applied consistently.
+type TopicNamer struct {
+	prefix   string
+	sanitize func(string) string
+	// topics is the sorted list of the topics of every target.
+	topics []string
+}
+
+// MakeTopicNamer returns a TopicNamer for the given targets. Each topic is
+// named by the prefix followed by the table name, passed through sanitize if
+// it is non-nil.
+func MakeTopicNamer(
+	targets jobspb.ChangefeedTargets, prefix string, sanitize func(string) string,
+) *TopicNamer {
+	tn := &TopicNamer{prefix: prefix, sanitize: sanitize}
+	seen := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		topic := tn.nameFromSQLName(t.StatementTimeName)
+		if _, ok := seen[topic]; !ok {
+			seen[topic] = struct{}{}
+			tn.topics = append(tn.topics, topic)
+		}
+	}
+	sort.Strings(tn.topics)
+	return tn
+}
+
+func (tn *TopicNamer) nameFromSQLName(name string) string {
+	if tn.sanitize != nil {
+		name = tn.sanitize(name)
+	}
+	return tn.prefix + name
+}
+
+// Name returns the topic that rows of the given table are emitted to. An error
+// is returned if the topic is not one of those of the targets, which happens
+// if the table was renamed after the changefeed was created.
+func (tn *TopicNamer) Name(table catalog.TableDescriptor) (string, error) {
+	topic := tn.nameFromSQLName(table.GetName())
+	i := sort.SearchStrings(tn.topics, topic)
+	if i == len(tn.topics) || tn.topics[i] != topic {
+		return ``, errors.Errorf(`cannot emit to undeclared topic: %s`, topic)
+	}
+	return topic, nil
+}
+
+// Topics returns the sorted names of the topics of every target. The returned
+// slice must not be modified.
+func (tn *TopicNamer) Topics() []string {
+	return tn.topics
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicNamer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	table := func(name string) *tabledesc.Immutable {
+		return tabledesc.NewImmutable(descpb.TableDescriptor{Name: name})
+	}
+	targets := jobspb.ChangefeedTargets{
+		52: {StatementTimeName: `foo`},
+		53: {StatementTimeName: `☃`},
+	}
+
+	tn := MakeTopicNamer(targets, `` /* prefix */, nil /* sanitize */)
+	require.Equal(t, []string{`foo`, `☃`}, tn.Topics())
+	topic, err := tn.Name(table(`☃`))
+	require.NoError(t, err)
+	require.Equal(t, `☃`, topic)
+
+	tn = MakeTopicNamer(targets, `pre_`, SQLNameToKafkaName)
+	require.Equal(t, []string{`pre__u2603_`, `pre_foo`}, tn.Topics())
+	topic, err = tn.Name(table(`☃`))
+	require.NoError(t, err)
+	require.Equal(t, `pre__u2603_`, topic)
+
+	_, err = tn.Name(table(`bar`))
+	require.EqualError(t, err, `cannot emit to undeclared topic: pre_bar`)
+}
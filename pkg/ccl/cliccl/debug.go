@@ -23,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/cliccl/cliflagsccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
 	"github.com/cockroachdb/cockroach/pkg/cli"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -48,7 +50,7 @@ var encryptionStatusOpts struct {
 
 func init() {
 	encryptionStatusCmd := &cobra.Command{
-		Use:   "encryption-status <directory>",
+		Use:   "encryption-status [<directory>]",
 		Short: "show encryption status of a store",
 		Long: `
 Shows encryption status of the store located in 'directory'.
@@ -57,8 +59,13 @@ Encryption keys must be specified in the '--enterprise-encryption' flag.
 Displays all store and data keys as well as files encrypted with each.
 Specifying --active-store-key-id-only prints the key ID of the active store key
 and exits.
+
+If no directory is specified, shows the encryption status of the stores of the
+running node given by the '--host' flag instead. For each store, displays the
+active keys as well as the number of files and bytes encrypted with each data
+key.
 `,
-		Args: cobra.ExactArgs(1),
+		Args: cobra.MaximumNArgs(1),
 		RunE: cli.MaybeDecorateGRPCError(runEncryptionStatus),
 	}
 
@@ -88,6 +95,7 @@ AES128_CTR:be235...   # AES-128 encryption with store key ID
 	// And other flags.
 	f.BoolVar(&encryptionStatusOpts.activeStoreIDOnly, "active-store-key-id-only", false,
 		"print active store key ID and exit")
+	cli.AddClientConnFlags(encryptionStatusCmd)
 
 	// Add encryption flag to all OSS debug commands that want it.
 	for _, cmd := range cli.DebugCmdsForRocksDB {
@@ -147,7 +155,32 @@ type PrettyStoreKey struct {
 	DataKeys []PrettyDataKey `json:",omitempty"`
 }
 
+// PrettyKeyStats is the final json-exportable struct for the files encrypted
+// with a data key.
+type PrettyKeyStats struct {
+	ID    string
+	Files uint64
+	Bytes uint64
+}
+
+// PrettyStoreStatus is the final json-exportable struct for the encryption
+// status of a store of a running node.
+type PrettyStoreStatus struct {
+	StoreID        roachpb.StoreID
+	ActiveStoreKey string `json:",omitempty"`
+	ActiveDataKey  string `json:",omitempty"`
+	TotalFiles     uint64
+	TotalBytes     uint64
+	ActiveKeyFiles uint64
+	ActiveKeyBytes uint64
+	Keys           []PrettyKeyStats `json:",omitempty"`
+}
+
 func runEncryptionStatus(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return runEncryptionStatusForNode()
+	}
+
 	stopper := stop.NewStopper()
 	defer stopper.Stop(context.Background())
 
@@ -277,6 +310,69 @@ func runEncryptionStatus(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runEncryptionStatusForNode prints the encryption status of the stores of a
+// running node.
+func runEncryptionStatusForNode() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, finish, err := cli.GetStatusClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer finish()
+
+	resp, err := c.Stores(ctx, &serverpb.StoresRequest{NodeId: "local"})
+	if err != nil {
+		return err
+	}
+
+	stores := make([]PrettyStoreStatus, 0, len(resp.Stores))
+	for _, store := range resp.Stores {
+		storeNode := PrettyStoreStatus{
+			StoreID:        store.StoreID,
+			TotalFiles:     store.TotalFiles,
+			TotalBytes:     store.TotalBytes,
+			ActiveKeyFiles: store.ActiveKeyFiles,
+			ActiveKeyBytes: store.ActiveKeyBytes,
+		}
+		if len(store.EncryptionStatus) > 0 {
+			var status enginepbccl.EncryptionStatus
+			if err := protoutil.Unmarshal(store.EncryptionStatus, &status); err != nil {
+				return err
+			}
+			if status.ActiveStoreKey != nil {
+				storeNode.ActiveStoreKey = status.ActiveStoreKey.KeyId
+			}
+			if status.ActiveDataKey != nil {
+				storeNode.ActiveDataKey = status.ActiveDataKey.KeyId
+			}
+		}
+		if encryptionStatusOpts.activeStoreIDOnly {
+			fmt.Println(storeNode.ActiveStoreKey)
+			continue
+		}
+		for _, ks := range store.KeyStats {
+			storeNode.Keys = append(storeNode.Keys, PrettyKeyStats{
+				ID:    ks.KeyID,
+				Files: ks.Files,
+				Bytes: ks.Bytes,
+			})
+		}
+		stores = append(stores, storeNode)
+	}
+	if encryptionStatusOpts.activeStoreIDOnly {
+		return nil
+	}
+
+	j, err := json.MarshalIndent(stores, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", j)
+	return nil
+}
+
 func runEncryptionActiveKey(cmd *cobra.Command, args []string) error {
 	keyType, keyID, err := getActiveEncryptionkey(args[0])
 	if err != nil {
@@ -0,0 +1,423 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage/cloudimpl"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+// parquetEncodeFn converts a non-NULL datum to the value expected by the
+// parquet.Writer for its column.
+type parquetEncodeFn func(tree.Datum) (interface{}, error)
+
+// newParquetColumn returns the Parquet column used to export a SQL column of
+// the given type, and the function converting the column's datums to values of
+// the Parquet column. Types which have no Parquet equivalent are exported as
+// strings in the same format as CSV exports.
+func newParquetColumn(name string, typ *types.T) (parquet.Column, parquetEncodeFn, error) {
+	if typ.Family() == types.ArrayFamily {
+		col, encodeElem, err := newParquetColumn(name, typ.ArrayContents())
+		if err != nil {
+			return parquet.Column{}, nil, err
+		}
+		if col.List {
+			return parquet.Column{}, nil, errors.Errorf(
+				"column %q: nested arrays cannot be exported to Parquet", name)
+		}
+		col.List = true
+		return col, func(d tree.Datum) (interface{}, error) {
+			arr, ok := d.(*tree.DArray)
+			if !ok {
+				return nil, errors.AssertionFailedf("unexpected datum of type %T", d)
+			}
+			elems := make([]interface{}, len(arr.Array))
+			for i, e := range arr.Array {
+				if e == tree.DNull {
+					continue
+				}
+				var err error
+				if elems[i], err = encodeElem(e); err != nil {
+					return nil, err
+				}
+			}
+			return elems, nil
+		}, nil
+	}
+
+	col := parquet.Column{Name: name}
+	var encode parquetEncodeFn
+	switch typ.Family() {
+	case types.BoolFamily:
+		col.Type = parquet.TypeBoolean
+		encode = func(d tree.Datum) (interface{}, error) {
+			return bool(*d.(*tree.DBool)), nil
+		}
+	case types.IntFamily:
+		switch typ.Width() {
+		case 16, 32:
+			col.Type, col.Logical = parquet.TypeInt32, parquet.LogicalInt32
+			if typ.Width() == 16 {
+				col.Logical = parquet.LogicalInt16
+			}
+			encode = func(d tree.Datum) (interface{}, error) {
+				return int32(*d.(*tree.DInt)), nil
+			}
+		default:
+			col.Type, col.Logical = parquet.TypeInt64, parquet.LogicalInt64
+			encode = func(d tree.Datum) (interface{}, error) {
+				return int64(*d.(*tree.DInt)), nil
+			}
+		}
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			col.Type = parquet.TypeFloat
+			encode = func(d tree.Datum) (interface{}, error) {
+				return float32(*d.(*tree.DFloat)), nil
+			}
+		} else {
+			col.Type = parquet.TypeDouble
+			encode = func(d tree.Datum) (interface{}, error) {
+				return float64(*d.(*tree.DFloat)), nil
+			}
+		}
+	case types.DecimalFamily:
+		if typ.Precision() == 0 {
+			// Parquet decimals need a fixed precision and scale, which
+			// unconstrained decimals lack. Picking a scale would round away
+			// digits of some values, so they are exported as strings instead,
+			// which preserves them exactly.
+			return newParquetStringColumn(name)
+		}
+		col.Type, col.Logical = parquet.TypeByteArray, parquet.LogicalDecimal
+		col.Precision, col.Scale = typ.Precision(), typ.Scale()
+		scale := typ.Scale()
+		encode = func(d tree.Datum) (interface{}, error) {
+			return encodeParquetDecimal(&d.(*tree.DDecimal).Decimal, scale)
+		}
+	case types.StringFamily, types.CollatedStringFamily:
+		col.Type, col.Logical = parquet.TypeByteArray, parquet.LogicalString
+		encode = func(d tree.Datum) (interface{}, error) {
+			if s, ok := d.(*tree.DCollatedString); ok {
+				return []byte(s.Contents), nil
+			}
+			return []byte(string(*d.(*tree.DString))), nil
+		}
+	case types.BytesFamily:
+		col.Type = parquet.TypeByteArray
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(*d.(*tree.DBytes)), nil
+		}
+	case types.DateFamily:
+		col.Type, col.Logical = parquet.TypeInt32, parquet.LogicalDate
+		encode = func(d tree.Datum) (interface{}, error) {
+			date := d.(*tree.DDate).Date
+			days := date.UnixEpochDays()
+			if !date.IsFinite() || days < math.MinInt32 || days > math.MaxInt32 {
+				return nil, errors.Errorf("date %s cannot be exported to Parquet", d)
+			}
+			return int32(days), nil
+		}
+	case types.TimestampFamily:
+		col.Type, col.Logical = parquet.TypeInt64, parquet.LogicalTimestampMicros
+		encode = func(d tree.Datum) (interface{}, error) {
+			t := d.(*tree.DTimestamp).Time
+			return t.Unix()*1e6 + int64(t.Nanosecond()/1e3), nil
+		}
+	case types.TimestampTZFamily:
+		col.Type, col.Logical = parquet.TypeInt64, parquet.LogicalTimestampMicrosUTC
+		encode = func(d tree.Datum) (interface{}, error) {
+			t := d.(*tree.DTimestampTZ).Time
+			return t.Unix()*1e6 + int64(t.Nanosecond()/1e3), nil
+		}
+	case types.TimeFamily:
+		col.Type, col.Logical = parquet.TypeInt64, parquet.LogicalTimeMicros
+		encode = func(d tree.Datum) (interface{}, error) {
+			return int64(*d.(*tree.DTime)), nil
+		}
+	case types.UuidFamily:
+		col.Type, col.Logical = parquet.TypeFixedLenByteArray, parquet.LogicalUUID
+		col.TypeLength = 16
+		encode = func(d tree.Datum) (interface{}, error) {
+			return d.(*tree.DUuid).GetBytes(), nil
+		}
+	case types.JsonFamily:
+		col.Type, col.Logical = parquet.TypeByteArray, parquet.LogicalJSON
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DJSON).JSON.String()), nil
+		}
+	case types.EnumFamily:
+		col.Type, col.Logical = parquet.TypeByteArray, parquet.LogicalEnum
+		encode = func(d tree.Datum) (interface{}, error) {
+			return []byte(d.(*tree.DEnum).LogicalRep), nil
+		}
+	default:
+		return newParquetStringColumn(name)
+	}
+	return col, func(d tree.Datum) (interface{}, error) {
+		return encode(tree.UnwrapDatum(nil /* evalCtx */, d))
+	}, nil
+}
+
+// newParquetStringColumn returns a string column holding the datums formatted
+// with tree.FmtExport.
+func newParquetStringColumn(name string) (parquet.Column, parquetEncodeFn, error) {
+	col := parquet.Column{Name: name, Type: parquet.TypeByteArray, Logical: parquet.LogicalString}
+	return col, func(d tree.Datum) (interface{}, error) {
+		return []byte(tree.AsStringWithFlags(d, tree.FmtExport)), nil
+	}, nil
+}
+
+// encodeParquetDecimal returns the unscaled value of d at the given scale as a
+// big-endian two's complement integer.
+func encodeParquetDecimal(d *apd.Decimal, scale int32) ([]byte, error) {
+	if d.Form != apd.Finite {
+		return nil, errors.Errorf("decimal %s cannot be exported to Parquet", d)
+	}
+	if d.Exponent != -scale {
+		var rounded apd.Decimal
+		if _, err := tree.HighPrecisionCtx.Quantize(&rounded, d, -scale); err != nil {
+			return nil, err
+		}
+		d = &rounded
+	}
+	unscaled := new(big.Int).Set(&d.Coeff)
+	if d.Negative {
+		unscaled.Neg(unscaled)
+	}
+	if unscaled.Sign() >= 0 {
+		b := unscaled.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return b, nil
+	}
+	// A negative value x is represented in n bytes as 2^(8n) + x, where n is
+	// the smallest number of bytes which can hold x.
+	n := (new(big.Int).Not(unscaled).BitLen() + 8) / 8
+	res := new(big.Int).Lsh(big.NewInt(1), uint(8*n))
+	return res.Add(res, unscaled).Bytes(), nil
+}
+
+func newParquetWriterProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ParquetWriterSpec,
+	input execinfra.RowSource,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	c := &parquetWriterProcessor{
+		flowCtx:     flowCtx,
+		processorID: processorID,
+		spec:        spec,
+		input:       input,
+		output:      output,
+	}
+	semaCtx := tree.MakeSemaContext()
+	if err := c.out.Init(&execinfrapb.PostProcessSpec{}, c.OutputTypes(), &semaCtx, flowCtx.NewEvalCtx(), output); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type parquetWriterProcessor struct {
+	flowCtx     *execinfra.FlowCtx
+	processorID int32
+	spec        execinfrapb.ParquetWriterSpec
+	input       execinfra.RowSource
+	out         execinfra.ProcOutputHelper
+	output      execinfra.RowReceiver
+}
+
+var _ execinfra.Processor = &parquetWriterProcessor{}
+
+func (sp *parquetWriterProcessor) OutputTypes() []*types.T {
+	res := make([]*types.T, len(colinfo.ExportColumns))
+	for i := range res {
+		res[i] = colinfo.ExportColumns[i].Typ
+	}
+	return res
+}
+
+func (sp *parquetWriterProcessor) codec() (parquet.Codec, error) {
+	switch sp.spec.CompressionCodec {
+	case execinfrapb.FileCompression_None:
+		return parquet.CodecUncompressed, nil
+	case execinfrapb.FileCompression_Gzip:
+		return parquet.CodecGzip, nil
+	case execinfrapb.FileCompression_Snappy:
+		return parquet.CodecSnappy, nil
+	default:
+		return 0, errors.Errorf("unsupported compression codec %s", sp.spec.CompressionCodec)
+	}
+}
+
+func (sp *parquetWriterProcessor) fileName(part string) string {
+	pattern := sql.ExportFilePatternParquet
+	if sp.spec.NamePattern != "" {
+		pattern = sp.spec.NamePattern
+	}
+	return strings.Replace(pattern, exportFilePatternPart, part, -1)
+}
+
+func (sp *parquetWriterProcessor) Run(ctx context.Context) {
+	ctx, span := tracing.ChildSpan(ctx, "parquetWriter")
+	defer span.Finish()
+
+	err := func() error {
+		typs := sp.input.OutputTypes()
+		sp.input.Start(ctx)
+		input := execinfra.MakeNoMetadataRowSource(sp.input, sp.output)
+
+		alloc := &rowenc.DatumAlloc{}
+
+		if len(sp.spec.ColumnNames) != len(typs) {
+			return errors.AssertionFailedf("expected %d column names, found %d",
+				len(typs), len(sp.spec.ColumnNames))
+		}
+		cols := make([]parquet.Column, len(typs))
+		encoders := make([]parquetEncodeFn, len(typs))
+		for i := range typs {
+			var err error
+			cols[i], encoders[i], err = newParquetColumn(sp.spec.ColumnNames[i], typs[i])
+			if err != nil {
+				return err
+			}
+		}
+		codec, err := sp.codec()
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		parquetRow := make([]interface{}, len(typs))
+
+		chunk := 0
+		done := false
+		for {
+			buf.Reset()
+			writer, err := parquet.NewWriter(&buf, cols, codec)
+			if err != nil {
+				return err
+			}
+			for {
+				if sp.spec.ChunkRows > 0 && writer.NumRows() >= sp.spec.ChunkRows {
+					break
+				}
+				row, err := input.NextRow()
+				if err != nil {
+					return err
+				}
+				if row == nil {
+					done = true
+					break
+				}
+
+				for i, ed := range row {
+					if ed.IsNull() {
+						parquetRow[i] = nil
+						continue
+					}
+					if err := ed.EnsureDecoded(typs[i], alloc); err != nil {
+						return err
+					}
+					if parquetRow[i], err = encoders[i](ed.Datum); err != nil {
+						return err
+					}
+				}
+				if err := writer.AddRow(parquetRow); err != nil {
+					return err
+				}
+			}
+			rows := writer.NumRows()
+			if rows < 1 {
+				break
+			}
+			if err := writer.Close(); err != nil {
+				return errors.Wrap(err, "failed to close parquet writer")
+			}
+
+			conf, err := cloudimpl.ExternalStorageConfFromURI(sp.spec.Destination, sp.spec.User())
+			if err != nil {
+				return err
+			}
+			es, err := sp.flowCtx.Cfg.ExternalStorage(ctx, conf)
+			if err != nil {
+				return err
+			}
+			defer es.Close()
+
+			nodeID, err := sp.flowCtx.EvalCtx.NodeID.OptionalNodeIDErr(47970)
+			if err != nil {
+				return err
+			}
+
+			part := fmt.Sprintf("n%d.%d", nodeID, chunk)
+			chunk++
+			filename := sp.fileName(part)
+			size := buf.Len()
+
+			if err := es.WriteFile(ctx, filename, bytes.NewReader(buf.Bytes())); err != nil {
+				return err
+			}
+			res := rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(
+					types.String,
+					tree.NewDString(filename),
+				),
+				rowenc.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(rows)),
+				),
+				rowenc.DatumToEncDatum(
+					types.Int,
+					tree.NewDInt(tree.DInt(size)),
+				),
+			}
+
+			cs, err := sp.out.EmitRow(ctx, res)
+			if err != nil {
+				return err
+			}
+			if cs != execinfra.NeedMoreRows {
+				return errors.New("unexpected closure of consumer")
+			}
+			if done {
+				break
+			}
+		}
+
+		return nil
+	}()
+
+	execinfra.DrainAndClose(
+		ctx, sp.output, err, func(context.Context) {} /* pushTrailingMeta */, sp.input)
+}
+
+func init() {
+	rowexec.NewParquetWriterProcessor = newParquetWriterProcessor
+}
@@ -0,0 +1,290 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/parquet"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	mustDecimal := func(s string) tree.Datum {
+		d, err := tree.ParseDDecimal(s)
+		require.NoError(t, err)
+		return d
+	}
+	ts := time.Date(2021, 3, 4, 5, 6, 7, 8000, time.UTC)
+	tsMicros := ts.UnixNano() / 1000
+	u := uuid.MakeV4()
+	date, err := tree.NewDDateFromTime(ts)
+	require.NoError(t, err)
+	json, err := tree.ParseDJSON(`{"a": [1, 2]}`)
+	require.NoError(t, err)
+	interval, err := tree.ParseDInterval("1 day")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		typ      *types.T
+		datum    tree.Datum
+		expCol   parquet.Column
+		expected interface{}
+	}{
+		{
+			typ:      types.Bool,
+			datum:    tree.DBoolTrue,
+			expCol:   parquet.Column{Type: parquet.TypeBoolean},
+			expected: true,
+		},
+		{
+			typ:      types.Int2,
+			datum:    tree.NewDInt(-3),
+			expCol:   parquet.Column{Type: parquet.TypeInt32, Logical: parquet.LogicalInt16},
+			expected: int32(-3),
+		},
+		{
+			typ:      types.Int4,
+			datum:    tree.NewDInt(4),
+			expCol:   parquet.Column{Type: parquet.TypeInt32, Logical: parquet.LogicalInt32},
+			expected: int32(4),
+		},
+		{
+			typ:      types.Int,
+			datum:    tree.NewDInt(1 << 40),
+			expCol:   parquet.Column{Type: parquet.TypeInt64, Logical: parquet.LogicalInt64},
+			expected: int64(1 << 40),
+		},
+		{
+			typ:      types.Float4,
+			datum:    tree.NewDFloat(1.5),
+			expCol:   parquet.Column{Type: parquet.TypeFloat},
+			expected: float32(1.5),
+		},
+		{
+			typ:      types.Float,
+			datum:    tree.NewDFloat(2.5),
+			expCol:   parquet.Column{Type: parquet.TypeDouble},
+			expected: 2.5,
+		},
+		{
+			typ:   types.MakeDecimal(10, 2),
+			datum: mustDecimal("1.5"),
+			expCol: parquet.Column{
+				Type: parquet.TypeByteArray, Logical: parquet.LogicalDecimal, Precision: 10, Scale: 2,
+			},
+			expected: []byte{0, 150},
+		},
+		{
+			typ:   types.MakeDecimal(10, 2),
+			datum: mustDecimal("-1.29"),
+			expCol: parquet.Column{
+				Type: parquet.TypeByteArray, Logical: parquet.LogicalDecimal, Precision: 10, Scale: 2,
+			},
+			expected: []byte{0xff, 0x7f},
+		},
+		{
+			typ:      types.Decimal,
+			datum:    mustDecimal("1.50"),
+			expCol:   parquet.Column{Type: parquet.TypeByteArray, Logical: parquet.LogicalString},
+			expected: []byte("1.50"),
+		},
+		{
+			typ:      types.String,
+			datum:    tree.NewDString("foo"),
+			expCol:   parquet.Column{Type: parquet.TypeByteArray, Logical: parquet.LogicalString},
+			expected: []byte("foo"),
+		},
+		{
+			typ:      types.Bytes,
+			datum:    tree.NewDBytes("\x00\x01"),
+			expCol:   parquet.Column{Type: parquet.TypeByteArray},
+			expected: []byte{0, 1},
+		},
+		{
+			typ:      types.Date,
+			datum:    date,
+			expCol:   parquet.Column{Type: parquet.TypeInt32, Logical: parquet.LogicalDate},
+			expected: int32(18690),
+		},
+		{
+			typ:      types.Timestamp,
+			datum:    tree.MustMakeDTimestamp(ts, time.Microsecond),
+			expCol:   parquet.Column{Type: parquet.TypeInt64, Logical: parquet.LogicalTimestampMicros},
+			expected: tsMicros,
+		},
+		{
+			typ:      types.TimestampTZ,
+			datum:    tree.MustMakeDTimestampTZ(ts, time.Microsecond),
+			expCol:   parquet.Column{Type: parquet.TypeInt64, Logical: parquet.LogicalTimestampMicrosUTC},
+			expected: tsMicros,
+		},
+		{
+			typ:      types.Time,
+			datum:    tree.MakeDTime(timeofday.New(1, 2, 3, 4)),
+			expCol:   parquet.Column{Type: parquet.TypeInt64, Logical: parquet.LogicalTimeMicros},
+			expected: int64(3723000004),
+		},
+		{
+			typ:   types.Uuid,
+			datum: tree.NewDUuid(tree.DUuid{UUID: u}),
+			expCol: parquet.Column{
+				Type: parquet.TypeFixedLenByteArray, Logical: parquet.LogicalUUID, TypeLength: 16,
+			},
+			expected: u.GetBytes(),
+		},
+		{
+			typ:      types.Jsonb,
+			datum:    json,
+			expCol:   parquet.Column{Type: parquet.TypeByteArray, Logical: parquet.LogicalJSON},
+			expected: []byte(`{"a": [1, 2]}`),
+		},
+		{
+			typ:      types.Interval,
+			datum:    interval,
+			expCol:   parquet.Column{Type: parquet.TypeByteArray, Logical: parquet.LogicalString},
+			expected: []byte("1 day"),
+		},
+		{
+			typ: types.MakeArray(types.Int),
+			datum: &tree.DArray{
+				ParamTyp: types.Int,
+				Array:    tree.Datums{tree.NewDInt(1), tree.DNull, tree.NewDInt(3)},
+				HasNulls: true,
+			},
+			expCol:   parquet.Column{Type: parquet.TypeInt64, Logical: parquet.LogicalInt64, List: true},
+			expected: []interface{}{int64(1), nil, int64(3)},
+		},
+	} {
+		t.Run(tc.typ.SQLString(), func(t *testing.T) {
+			col, encode, err := newParquetColumn("c", tc.typ)
+			require.NoError(t, err)
+			tc.expCol.Name = "c"
+			require.Equal(t, tc.expCol, col)
+			v, err := encode(tc.datum)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v)
+		})
+	}
+
+	_, _, err = newParquetColumn("c", types.MakeArray(types.MakeArray(types.Int)))
+	require.EqualError(t, err, `column "c": nested arrays cannot be exported to Parquet`)
+}
+
+func TestExportParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `
+CREATE TYPE greeting AS ENUM ('hello', 'hi');
+CREATE TABLE foo (
+	i INT PRIMARY KEY,
+	d DECIMAL(10, 2),
+	ts TIMESTAMPTZ,
+	j JSONB,
+	a STRING[],
+	g greeting
+);
+INSERT INTO foo VALUES
+	(1, 1.25, '2021-01-01 00:00:00+00', '{"a": 1}', ARRAY['x', NULL], 'hello'),
+	(2, NULL, NULL, NULL, NULL, NULL),
+	(3, -3.5, '2021-01-03 00:00:00+00', '[]', ARRAY[]::STRING[], 'hi');
+`)
+
+	for _, compression := range []string{``, `gzip`, `snappy`} {
+		t.Run(compression, func(t *testing.T) {
+			path := `export` + compression
+			var rows [][]string
+			if compression == `` {
+				rows = sqlDB.QueryStr(t, `EXPORT INTO PARQUET 'nodelocal://0/`+path+`'
+					WITH chunk_rows = '2' FROM SELECT * FROM foo ORDER BY i`)
+			} else {
+				rows = sqlDB.QueryStr(t, `EXPORT INTO PARQUET 'nodelocal://0/`+path+`'
+					WITH chunk_rows = '2', compression = $1 FROM SELECT * FROM foo ORDER BY i`, compression)
+			}
+			require.Len(t, rows, 2)
+			require.Regexp(t, `^export.*-n1\.0\.parquet$`, rows[0][0])
+			require.Equal(t, `2`, rows[0][1])
+			require.Regexp(t, `^export.*-n1\.1\.parquet$`, rows[1][0])
+			require.Equal(t, `1`, rows[1][1])
+
+			for _, row := range rows {
+				contents, err := ioutil.ReadFile(filepath.Join(dir, path, row[0]))
+				require.NoError(t, err)
+				require.Equal(t, row[2], strconv.Itoa(len(contents)))
+				require.Equal(t, `PAR1`, string(contents[:4]))
+				require.Equal(t, `PAR1`, string(contents[len(contents)-4:]))
+			}
+		})
+	}
+
+	sqlDB.ExpectErr(t, `delimiter option is not supported for PARQUET exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' WITH delimiter = '|' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `nullas option is not supported for PARQUET exports`,
+		`EXPORT INTO PARQUET 'nodelocal://0/bad' WITH nullas = '' FROM SELECT * FROM foo`)
+	sqlDB.ExpectErr(t, `unsupported compression codec snappy`,
+		`EXPORT INTO CSV 'nodelocal://0/bad' WITH compression = 'snappy' FROM SELECT * FROM foo`)
+}
+
+func TestExportParquetMixedVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		ExternalIODir: dir,
+		Knobs: base.TestingKnobs{
+			Server: &server.TestingKnobs{
+				BinaryVersionOverride:          clusterversion.ByKey(clusterversion.ExportParquet - 1),
+				DisableAutomaticVersionUpgrade: 1,
+			},
+		},
+	})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	// Older nodes cannot run ParquetWriter processors, so Parquet exports are
+	// rejected until the upgrade is finalized. CSV exports are unaffected.
+	sqlDB.ExpectErr(t, `version .* must be finalized to export to PARQUET`,
+		`EXPORT INTO PARQUET 'nodelocal://0/before' FROM SELECT 1`)
+	sqlDB.Exec(t, `EXPORT INTO CSV 'nodelocal://0/before' FROM SELECT 1`)
+
+	sqlDB.Exec(t, `SET CLUSTER SETTING version = $1`,
+		clusterversion.ByKey(clusterversion.ExportParquet).String())
+	sqlDB.Exec(t, `EXPORT INTO PARQUET 'nodelocal://0/after' FROM SELECT 1`)
+}
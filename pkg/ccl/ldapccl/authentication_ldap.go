@@ -0,0 +1,517 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The "ldap" authentication method asks the client for a cleartext
+// password, and checks it against an LDAP directory using the
+// search+bind scheme of PostgreSQL: the server binds to the directory
+// (anonymously or using ldapbinddn and ldapbindpasswd), looks up the
+// entry of the user using ldapsearchfilter under ldapbasedn, and then
+// binds as that entry using the password supplied by the client.
+//
+// The SQL user must already exist; the directory is only used to check
+// the password. If ldapgroupattribute is specified, the values of that
+// attribute in the entry of the user (typically memberOf) are treated as
+// the DNs of the groups the user belongs to. Each ldapgrouprole option
+// maps a group to a SQL role, in the form <role>:<group DN>, and the
+// option may be repeated. On every login, the memberships of the user in
+// the mapped roles are synchronized with the directory: the user is
+// granted the roles of its groups, and loses the mapped roles whose groups
+// it no longer belongs to, if they were granted by the synchronization.
+// Memberships granted with GRANT, and memberships of roles which are not
+// mapped, are left untouched. The reserved roles (admin, root, public and node) cannot
+// be mapped, and roles which are members of admin are never granted.
+//
+// The options which contain commas or spaces must be quoted, for example:
+//
+//   host all all all ldap ldapurl=ldaps://ldap.example.com "ldapbasedn=ou=users,dc=example,dc=com"
+//       ldapgroupattribute=memberOf "ldapgrouprole=analysts:cn=analysts,ou=groups,dc=example,dc=com"
+
+const (
+	authTypeCleartextPassword int32 = 3
+
+	// defaultSearchFilter matches the user entries by uid, like
+	// PostgreSQL's default ldapsearchattribute.
+	defaultSearchFilter = "(uid=$username)"
+	// usernamePlaceholder is replaced by the escaped SQL user name in the
+	// search filter.
+	usernamePlaceholder = "$username"
+
+	// ldapTimeout bounds the duration of the exchange with the directory.
+	ldapTimeout = 10 * time.Second
+)
+
+// testingRootCAs, if set, replaces the system root certificates when
+// verifying the certificate of the LDAP server.
+var testingRootCAs *x509.CertPool
+
+// ldapConfig is the configuration of an HBA entry using the ldap method.
+type ldapConfig struct {
+	serverURL      *url.URL
+	startTLS       bool
+	baseDN         string
+	bindDN         string
+	bindPassword   string
+	searchFilter   string
+	groupAttribute string
+	groupRoles     []ldapGroupRole
+}
+
+// ldapGroupRole maps the members of a directory group to a SQL role.
+type ldapGroupRole struct {
+	role security.SQLUsername
+	// groupDN is the DN of the group, normalized by normalizeDN.
+	groupDN string
+}
+
+func parseLDAPConfig(entry hba.Entry) (*ldapConfig, error) {
+	cfg := &ldapConfig{searchFilter: defaultSearchFilter}
+	for _, op := range entry.Options {
+		switch op[0] {
+		case "ldapurl":
+			u, err := url.Parse(op[1])
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid ldapurl")
+			}
+			if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+				return nil, errors.Newf("unsupported scheme in ldapurl: %q", u.Scheme)
+			}
+			if u.Hostname() == "" {
+				return nil, errors.New("missing host in ldapurl")
+			}
+			if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+				return nil, errors.WithHint(errors.New("ldapurl must only specify the scheme, host and port"),
+					"Use ldapbasedn and ldapsearchfilter to configure the search.")
+			}
+			cfg.serverURL = u
+		case "ldaptls":
+			switch op[1] {
+			case "0":
+				cfg.startTLS = false
+			case "1":
+				cfg.startTLS = true
+			default:
+				return nil, errors.Newf("ldaptls must be set to 0 or 1: %s", op[1])
+			}
+		case "ldapbasedn":
+			cfg.baseDN = op[1]
+		case "ldapbinddn":
+			cfg.bindDN = op[1]
+		case "ldapbindpasswd":
+			cfg.bindPassword = op[1]
+		case "ldapsearchfilter":
+			cfg.searchFilter = op[1]
+		case "ldapgroupattribute":
+			cfg.groupAttribute = op[1]
+		case "ldapgrouprole":
+			gr, err := parseGroupRole(op[1])
+			if err != nil {
+				return nil, err
+			}
+			cfg.groupRoles = append(cfg.groupRoles, gr)
+		default:
+			return nil, errors.Errorf("unsupported option %s", op[0])
+		}
+	}
+	if cfg.serverURL == nil {
+		return nil, errors.New(`missing "ldapurl" option in LDAP entry`)
+	}
+	if cfg.baseDN == "" {
+		return nil, errors.New(`missing "ldapbasedn" option in LDAP entry`)
+	}
+	if cfg.startTLS && cfg.serverURL.Scheme == "ldaps" {
+		return nil, errors.New("ldaptls cannot be used with an ldaps URL")
+	}
+	if (cfg.bindDN == "") != (cfg.bindPassword == "") {
+		return nil, errors.New("ldapbinddn and ldapbindpasswd must be specified together")
+	}
+	if cfg.groupAttribute != "" && !validAttributeDescription(cfg.groupAttribute) {
+		return nil, errors.Newf("invalid ldapgroupattribute: %q", cfg.groupAttribute)
+	}
+	if (cfg.groupAttribute == "") != (len(cfg.groupRoles) == 0) {
+		return nil, errors.New("ldapgroupattribute and ldapgrouprole must be specified together")
+	}
+	if _, err := cfg.compileSearchFilter("user"); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// parseGroupRole parses the value of an ldapgrouprole option, of the form
+// <role>:<group DN>.
+func parseGroupRole(val string) (ldapGroupRole, error) {
+	colon := strings.IndexByte(val, ':')
+	if colon < 0 {
+		return ldapGroupRole{}, errors.WithHint(
+			errors.Newf("invalid ldapgrouprole: %q", val),
+			"The expected format is <role>:<group DN>.")
+	}
+	role, err := security.MakeSQLUsernameFromUserInput(
+		strings.TrimSpace(val[:colon]), security.UsernameValidation)
+	if err != nil {
+		return ldapGroupRole{}, errors.Wrapf(err, "invalid ldapgrouprole: %q", val)
+	}
+	if role.IsAdminRole() || role.IsRootUser() || role.IsPublicRole() || role.IsNodeUser() {
+		return ldapGroupRole{}, errors.Newf(
+			"ldapgrouprole cannot grant the reserved role %s", role)
+	}
+	groupDN := normalizeDN(val[colon+1:])
+	if groupDN == "" {
+		return ldapGroupRole{}, errors.Newf("invalid ldapgrouprole: %q", val)
+	}
+	return ldapGroupRole{role: role, groupDN: groupDN}, nil
+}
+
+// normalizeDN returns a canonical form of a DN for comparisons: attribute
+// types and values are lowercased and the spaces around them are removed, so
+// that "CN=Analysts, OU=Groups" and "cn=analysts,ou=groups" compare equal.
+func normalizeDN(dn string) string {
+	var buf strings.Builder
+	start := 0
+	appendRDN := func(rdn string) {
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		if eq := strings.IndexByte(rdn, '='); eq >= 0 {
+			buf.WriteString(strings.ToLower(strings.TrimSpace(rdn[:eq])))
+			buf.WriteByte('=')
+			rdn = rdn[eq+1:]
+		}
+		buf.WriteString(strings.ToLower(strings.TrimSpace(rdn)))
+	}
+	for i := 0; i < len(dn); i++ {
+		if dn[i] == '\\' {
+			i++
+		} else if dn[i] == ',' {
+			appendRDN(dn[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(dn) != "" {
+		appendRDN(dn[start:])
+	}
+	return buf.String()
+}
+
+// groupRolesOf returns the mapped roles which the user must be granted given
+// the DNs of the groups it belongs to.
+func (cfg *ldapConfig) groupRolesOf(groups []string) map[security.SQLUsername]bool {
+	memberOf := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		memberOf[normalizeDN(g)] = true
+	}
+	roles := make(map[security.SQLUsername]bool, len(cfg.groupRoles))
+	for _, gr := range cfg.groupRoles {
+		if memberOf[gr.groupDN] {
+			roles[gr.role] = true
+		}
+	}
+	return roles
+}
+
+func (cfg *ldapConfig) compileSearchFilter(username string) (berElement, error) {
+	return compileFilter(strings.Replace(
+		cfg.searchFilter, usernamePlaceholder, escapeFilterValue(username), -1))
+}
+
+// dial connects to the LDAP server, using TLS if configured.
+func (cfg *ldapConfig) dial(ctx context.Context) (*ldapConn, error) {
+	hostname := cfg.serverURL.Hostname()
+	port := cfg.serverURL.Port()
+	if port == "" {
+		port = "389"
+		if cfg.serverURL.Scheme == "ldaps" {
+			port = "636"
+		}
+	}
+	tlsConfig := &tls.Config{ServerName: hostname, RootCAs: testingRootCAs}
+
+	deadline := timeutil.Now().Add(ldapTimeout)
+	dialCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	var d net.Dialer
+	netConn, err := d.DialContext(dialCtx, "tcp", net.JoinHostPort(hostname, port))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.serverURL.Scheme == "ldaps" {
+		netConn = tls.Client(netConn, tlsConfig)
+	}
+	if err := netConn.SetDeadline(deadline); err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	c := newLDAPConn(netConn)
+	if cfg.startTLS {
+		if err := c.startTLS(tlsConfig); err != nil {
+			_ = netConn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// authenticate checks the password of the user against the directory. It
+// returns the values of the group attribute of the entry of the user, if
+// configured.
+func (cfg *ldapConfig) authenticate(
+	ctx context.Context, username string, password string,
+) (groups []string, _ error) {
+	conn, err := cfg.dial(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "connecting to the LDAP server")
+	}
+	defer conn.close()
+
+	if cfg.bindDN != "" {
+		if err := conn.bind(cfg.bindDN, cfg.bindPassword); err != nil {
+			return nil, errors.Wrapf(err, "binding as %q to search the directory", cfg.bindDN)
+		}
+	}
+	filter, err := cfg.compileSearchFilter(username)
+	if err != nil {
+		return nil, err
+	}
+	var attrs []string
+	if cfg.groupAttribute != "" {
+		attrs = []string{cfg.groupAttribute}
+	}
+	// Look for a second entry to detect ambiguous filters.
+	entries, err := conn.search(
+		cfg.baseDN, filter, attrs, 2 /* sizeLimit */, int64(ldapTimeout/time.Second),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "searching the directory")
+	}
+	if len(entries) != 1 {
+		if len(entries) == 0 {
+			return nil, errors.Newf("user %q not found in the directory", username)
+		}
+		return nil, errors.Newf("user %q is not unique in the directory", username)
+	}
+	if err := conn.bind(entries[0].dn, password); err != nil {
+		return nil, errors.Wrapf(err, "binding as %q", entries[0].dn)
+	}
+	return entries[0].attrs[strings.ToLower(cfg.groupAttribute)], nil
+}
+
+func authLDAP(
+	ctx context.Context,
+	c pgwire.AuthConn,
+	_ tls.ConnectionState,
+	_ pgwire.PasswordRetrievalFn,
+	_ pgwire.PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	entry *hba.Entry,
+) (security.UserAuthHook, error) {
+	cfg, err := parseLDAPConfig(*entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.SendAuthRequest(authTypeCleartextPassword, nil /* data */); err != nil {
+		return nil, err
+	}
+	pwdData, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(pwdData, 0) != len(pwdData)-1 {
+		return nil, errors.New("expected 0-terminated byte array")
+	}
+	password := string(pwdData[:len(pwdData)-1])
+
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		if requestedUser.Undefined() {
+			return nil, errors.New("user is missing")
+		}
+		// An empty password would perform an unauthenticated bind, which
+		// always succeeds.
+		if len(password) == 0 {
+			c.Logf(ctx, "empty password")
+			return nil, errors.Errorf(security.ErrPasswordUserAuthFailed, requestedUser)
+		}
+		groups, err := cfg.authenticate(ctx, requestedUser.Normalized(), password)
+		if err != nil {
+			c.Logf(ctx, "LDAP authentication failed: %v", err)
+			return nil, errors.Errorf(security.ErrPasswordUserAuthFailed, requestedUser)
+		}
+
+		// Do the license check after the directory has been consulted, so that
+		// administrators can test their LDAP configuration.
+		if err := utilccl.CheckEnterpriseEnabled(
+			execCfg.Settings, execCfg.ClusterID(), execCfg.Organization(), "LDAP authentication",
+		); err != nil {
+			return nil, err
+		}
+
+		if len(cfg.groupRoles) > 0 {
+			if err := syncGroupRoles(ctx, execCfg, cfg, requestedUser, groups); err != nil {
+				// The user is authenticated; their role memberships will be
+				// synchronized on the next login.
+				log.Warningf(ctx, "unable to synchronize the roles of the LDAP groups of %s: %v", requestedUser, err)
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// syncGroupRoles synchronizes the memberships of the user in the roles mapped
+// by ldapgrouprole with the groups it belongs to in the directory. The user is
+// granted the mapped roles of its groups which exist, and is revoked from the
+// other mapped roles, but only if the membership was granted by a previous
+// synchronization: memberships granted by other means, and memberships of
+// roles which are not mapped, are left untouched. Roles which are members of
+// admin, directly or not, are never granted, so that the directory cannot
+// confer admin privileges.
+//
+// The roles granted by the synchronization are recorded in system.role_options
+// under roleoption.LDAPGroupRoles. The memberships and the record are updated
+// in a single transaction.
+func syncGroupRoles(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	cfg *ldapConfig,
+	user security.SQLUsername,
+	groups []string,
+) error {
+	ie := execCfg.InternalExecutor
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+	wanted := cfg.groupRolesOf(groups)
+
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		rows, err := ie.QueryEx(ctx, "ldap-get-roles", txn, override,
+			`SELECT role FROM system.role_members WHERE member = $1`, user)
+		if err != nil {
+			return err
+		}
+		current := make(map[security.SQLUsername]bool, len(rows))
+		for _, row := range rows {
+			current[security.MakeSQLUsernameFromPreNormalizedString(string(tree.MustBeDString(row[0])))] = true
+		}
+
+		// The roles granted by previous synchronizations. Those which the user
+		// is no longer a member of, because they were revoked or dropped since,
+		// are forgotten.
+		synced := make(map[security.SQLUsername]bool)
+		row, err := ie.QueryRowEx(ctx, "ldap-get-synced-roles", txn, override,
+			`SELECT value FROM system.role_options WHERE username = $1 AND option = $2`,
+			user, roleoption.LDAPGroupRoles)
+		if err != nil {
+			return err
+		}
+		if row != nil && row[0] != tree.DNull {
+			for _, r := range strings.Split(string(tree.MustBeDString(row[0])), ",") {
+				role := security.MakeSQLUsernameFromPreNormalizedString(r)
+				if current[role] {
+					synced[role] = true
+				}
+			}
+		}
+
+		seen := make(map[security.SQLUsername]bool, len(cfg.groupRoles))
+		for _, gr := range cfg.groupRoles {
+			role := gr.role
+			if seen[role] || role == user {
+				continue
+			}
+			seen[role] = true
+
+			switch {
+			case wanted[role] && !current[role]:
+				row, err := ie.QueryRowEx(ctx, "ldap-check-role", txn, override,
+					`WITH RECURSIVE ancestors(role) AS (
+					   SELECT $1::STRING
+					   UNION ALL SELECT rm.role FROM system.role_members AS rm
+					     JOIN ancestors AS a ON rm.member = a.role
+					 )
+					 SELECT EXISTS(SELECT 1 FROM system.users WHERE username = $1 AND "isRole"),
+					        EXISTS(SELECT 1 FROM ancestors WHERE role = $2)`,
+					role, security.AdminRoleName())
+				if err != nil {
+					return err
+				}
+				if row == nil || row[0] != tree.DBoolTrue {
+					// Groups without a matching role are ignored.
+					continue
+				}
+				if row[1] == tree.DBoolTrue {
+					log.Warningf(ctx, "not granting %s to %s: LDAP groups cannot grant roles which are members of %s",
+						role, user, security.AdminRoleName())
+					continue
+				}
+				if _, err := ie.ExecEx(ctx, "ldap-grant-role", txn, override,
+					fmt.Sprintf("GRANT %s TO %s", role.SQLIdentifier(), user.SQLIdentifier()),
+				); err != nil {
+					return err
+				}
+				synced[role] = true
+
+			case !wanted[role] && synced[role]:
+				if _, err := ie.ExecEx(ctx, "ldap-revoke-role", txn, override,
+					fmt.Sprintf("REVOKE %s FROM %s", role.SQLIdentifier(), user.SQLIdentifier()),
+				); err != nil {
+					return err
+				}
+				delete(synced, role)
+			}
+		}
+
+		if len(synced) == 0 {
+			_, err = ie.ExecEx(ctx, "ldap-delete-synced-roles", txn, override,
+				`DELETE FROM system.role_options WHERE username = $1 AND option = $2`,
+				user, roleoption.LDAPGroupRoles)
+			return err
+		}
+		roles := make([]string, 0, len(synced))
+		for role := range synced {
+			roles = append(roles, role.Normalized())
+		}
+		sort.Strings(roles)
+		// Role names cannot contain commas.
+		_, err = ie.ExecEx(ctx, "ldap-set-synced-roles", txn, override,
+			`UPSERT INTO system.role_options (username, option, value) VALUES ($1, $2, $3)`,
+			user, roleoption.LDAPGroupRoles, strings.Join(roles, ","))
+		return err
+	})
+}
+
+func checkEntry(entry hba.Entry) error {
+	_, err := parseLDAPConfig(entry)
+	return err
+}
+
+func init() {
+	pgwire.RegisterAuthMethod("ldap", authLDAP, hba.ConnAny, checkEntry)
+}
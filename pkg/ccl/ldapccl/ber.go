@@ -0,0 +1,250 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements the subset of the Basic Encoding Rules (BER) of
+// ASN.1 needed by the LDAP protocol, as restricted by section 5.1 of
+// RFC 4511: only the definite form of length encoding is used, and all
+// the tags used by LDAP fit in a single identifier octet.
+
+// Identifier octets used by LDAP. The class and the primitive/constructed
+// bit are included.
+const (
+	berTagBoolean     byte = 0x01
+	berTagInteger     byte = 0x02
+	berTagOctetString byte = 0x04
+	berTagEnumerated  byte = 0x0a
+	berTagSequence    byte = 0x30
+	berTagSet         byte = 0x31
+
+	berClassApplication byte = 0x40
+	berClassContext     byte = 0x80
+	berConstructed      byte = 0x20
+)
+
+// maxBERLength bounds the size of the messages accepted from the network.
+const maxBERLength = 16 << 20
+
+// maxBERDepth bounds the nesting of the constructed elements accepted from
+// the network, so that a malicious or broken server cannot exhaust the stack
+// of the decoder. LDAP messages are nested a handful of levels deep, and the
+// search filters we send are limited to the same depth.
+const maxBERDepth = 32
+
+// berElement is a decoded BER element. Constructed elements have children;
+// primitive elements have a value.
+type berElement struct {
+	tag      byte
+	value    []byte
+	children []berElement
+}
+
+func (e *berElement) constructed() bool {
+	return e.tag&berConstructed != 0
+}
+
+func berSequence(tag byte, children ...berElement) berElement {
+	return berElement{tag: tag | berConstructed, children: children}
+}
+
+func berString(tag byte, s string) berElement {
+	return berElement{tag: tag, value: []byte(s)}
+}
+
+func berBool(tag byte, b bool) berElement {
+	v := byte(0)
+	if b {
+		v = 0xff
+	}
+	return berElement{tag: tag, value: []byte{v}}
+}
+
+// berInt encodes an INTEGER or ENUMERATED value using the minimal number of
+// octets in two's complement form.
+func berInt(tag byte, v int64) berElement {
+	var buf []byte
+	for {
+		buf = append([]byte{byte(v)}, buf...)
+		v >>= 8
+		if (v == 0 && buf[0]&0x80 == 0) || (v == -1 && buf[0]&0x80 != 0) {
+			break
+		}
+	}
+	return berElement{tag: tag, value: buf}
+}
+
+// encode appends the encoding of the element to buf.
+func (e *berElement) encode(buf []byte) []byte {
+	content := e.value
+	if e.constructed() {
+		content = nil
+		for i := range e.children {
+			content = e.children[i].encode(content)
+		}
+	}
+	buf = append(buf, e.tag)
+	if n := len(content); n < 0x80 {
+		buf = append(buf, byte(n))
+	} else {
+		var lenBytes []byte
+		for ; n > 0; n >>= 8 {
+			lenBytes = append([]byte{byte(n)}, lenBytes...)
+		}
+		buf = append(buf, 0x80|byte(len(lenBytes)))
+		buf = append(buf, lenBytes...)
+	}
+	return append(buf, content...)
+}
+
+// readBERElement reads a complete element from r.
+func readBERElement(r *bufio.Reader) (berElement, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return berElement{}, err
+	}
+	n, err := readBERLength(r)
+	if err != nil {
+		return berElement{}, err
+	}
+	content := make([]byte, n)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return berElement{}, err
+	}
+	return makeBERElement(tag, content, 0 /* depth */)
+}
+
+func readBERLength(r io.ByteReader) (int, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b < 0x80 {
+		return int(b), nil
+	}
+	numBytes := int(b &^ 0x80)
+	if numBytes == 0 {
+		return 0, errors.New("indefinite BER lengths are not supported by LDAP")
+	}
+	if numBytes > 4 {
+		return 0, errors.Newf("BER length too large: %d octets", numBytes)
+	}
+	n := 0
+	for i := 0; i < numBytes; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		n = n<<8 | int(b)
+	}
+	if n > maxBERLength {
+		return 0, errors.Newf("BER element too large: %d bytes", n)
+	}
+	return n, nil
+}
+
+// decodeBERElements decodes a sequence of elements which exactly fills buf.
+func decodeBERElements(buf []byte) ([]berElement, error) {
+	return decodeNestedBERElements(buf, 0 /* depth */)
+}
+
+// decodeNestedBERElements decodes a sequence of elements which exactly fills
+// buf, and which are nested in depth constructed elements.
+func decodeNestedBERElements(buf []byte, depth int) ([]berElement, error) {
+	var elems []berElement
+	for len(buf) > 0 {
+		if len(buf) < 2 {
+			return nil, errors.New("truncated BER element")
+		}
+		tag := buf[0]
+		r := byteSliceReader{buf: buf[1:]}
+		n, err := readBERLength(&r)
+		if err != nil {
+			return nil, err
+		}
+		if n > len(r.buf) {
+			return nil, errors.New("truncated BER element")
+		}
+		e, err := makeBERElement(tag, r.buf[:n], depth)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, e)
+		buf = r.buf[n:]
+	}
+	return elems, nil
+}
+
+// makeBERElement decodes an element with the given tag and content, which is
+// nested in depth constructed elements.
+func makeBERElement(tag byte, content []byte, depth int) (berElement, error) {
+	e := berElement{tag: tag}
+	if !e.constructed() {
+		e.value = content
+		return e, nil
+	}
+	if depth >= maxBERDepth {
+		return berElement{}, errors.Newf("BER elements nested more than %d levels deep", maxBERDepth)
+	}
+	var err error
+	e.children, err = decodeNestedBERElements(content, depth+1)
+	return e, err
+}
+
+// byteSliceReader is an io.ByteReader over a byte slice, which exposes the
+// remaining bytes.
+type byteSliceReader struct {
+	buf []byte
+}
+
+func (r *byteSliceReader) ReadByte() (byte, error) {
+	if len(r.buf) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b, nil
+}
+
+// int decodes the value of an INTEGER or ENUMERATED element.
+func (e *berElement) int() (int64, error) {
+	if e.constructed() || len(e.value) == 0 || len(e.value) > 8 {
+		return 0, errors.Newf("invalid BER integer with tag 0x%x", e.tag)
+	}
+	// Sign-extend the first octet.
+	v := int64(int8(e.value[0]))
+	for _, b := range e.value[1:] {
+		v = v<<8 | int64(b)
+	}
+	return v, nil
+}
+
+// str returns the value of an OCTET STRING element.
+func (e *berElement) str() string {
+	return string(e.value)
+}
+
+// child returns the i-th child of a constructed element, or an error if there
+// is no such child or if it does not have the expected tag.
+func (e *berElement) child(i int, tag byte) (*berElement, error) {
+	if i >= len(e.children) {
+		return nil, errors.Newf("missing element %d in BER element with tag 0x%x", i, e.tag)
+	}
+	c := &e.children[i]
+	if c.tag != tag {
+		return nil, errors.Newf("unexpected BER tag 0x%x, expected 0x%x", c.tag, tag)
+	}
+	return c, nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements the client side of the few LDAPv3 operations
+// (RFC 4511) needed to authenticate users: simple bind, search and the
+// StartTLS extended operation.
+
+// Tags of the LDAP protocol operations.
+const (
+	opBindRequest          = berClassApplication | 0
+	opBindResponse         = berClassApplication | 1
+	opUnbindRequest        = berClassApplication | 2
+	opSearchRequest        = berClassApplication | 3
+	opSearchResultEntry    = berClassApplication | 4
+	opSearchResultDone     = berClassApplication | 5
+	opSearchResultRef      = berClassApplication | 19
+	opExtendedRequest      = berClassApplication | 23
+	opExtendedResponse     = berClassApplication | 24
+	extendedRequestNameTag = berClassContext | 0
+	simpleAuthTag          = berClassContext | 0
+)
+
+// startTLSOID is the name of the StartTLS extended operation (RFC 4511,
+// section 4.14).
+const startTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// LDAP result codes used below.
+const (
+	resultSuccess           = 0
+	resultSizeLimitExceeded = 4
+)
+
+// Parameters of search requests.
+const (
+	scopeWholeSubtree = 2
+	neverDerefAliases = 0
+	// noAttributes is the special attribute selector which requests that no
+	// attributes be returned (RFC 4511, section 4.5.1.8).
+	noAttributes = "1.1"
+)
+
+// ldapResultError is returned when the server reports that an operation
+// failed.
+type ldapResultError struct {
+	code    int64
+	message string
+}
+
+func (e *ldapResultError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("LDAP result code %d", e.code)
+	}
+	return fmt.Sprintf("LDAP result code %d: %s", e.code, e.message)
+}
+
+// ldapEntry is an entry returned by a search. The attribute names are
+// lowercased.
+type ldapEntry struct {
+	dn    string
+	attrs map[string][]string
+}
+
+// ldapConn is a connection to an LDAP server. Operations are performed
+// synchronously, one at a time.
+type ldapConn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	lastID int64
+}
+
+func newLDAPConn(conn net.Conn) *ldapConn {
+	return &ldapConn{conn: conn, r: bufio.NewReader(conn)}
+}
+
+// roundTrip sends a request and returns the protocol operation of the
+// first response to it.
+func (c *ldapConn) roundTrip(op berElement) (berElement, error) {
+	if err := c.send(op); err != nil {
+		return berElement{}, err
+	}
+	return c.receive()
+}
+
+func (c *ldapConn) send(op berElement) error {
+	c.lastID++
+	msg := berSequence(berTagSequence, berInt(berTagInteger, c.lastID), op)
+	_, err := c.conn.Write(msg.encode(nil))
+	return err
+}
+
+// receive reads the next message sent by the server in response to the last
+// request, and returns its protocol operation.
+func (c *ldapConn) receive() (berElement, error) {
+	msg, err := readBERElement(c.r)
+	if err != nil {
+		return berElement{}, err
+	}
+	if msg.tag != berTagSequence || len(msg.children) < 2 {
+		return berElement{}, errors.Newf("malformed LDAP message with tag 0x%x", msg.tag)
+	}
+	id, err := msg.children[0].int()
+	if err != nil {
+		return berElement{}, err
+	}
+	if id == 0 {
+		// Unsolicited notifications are only used to announce that the server
+		// is closing the connection.
+		return berElement{}, errors.Newf("LDAP server closed the connection: %v", parseResult(&msg.children[1]))
+	}
+	if id != c.lastID {
+		return berElement{}, errors.Newf("unexpected LDAP message ID %d, expected %d", id, c.lastID)
+	}
+	return msg.children[1], nil
+}
+
+// parseResult decodes the LDAPResult part of a response, returning an error
+// unless the operation succeeded.
+func parseResult(op *berElement) error {
+	codeElem, err := op.child(0, berTagEnumerated)
+	if err != nil {
+		return err
+	}
+	code, err := codeElem.int()
+	if err != nil {
+		return err
+	}
+	if code == resultSuccess {
+		return nil
+	}
+	resErr := &ldapResultError{code: code}
+	if msg, err := op.child(2, berTagOctetString); err == nil {
+		resErr.message = msg.str()
+	}
+	return resErr
+}
+
+func checkOp(op *berElement, expected byte) error {
+	if op.tag&^berConstructed != expected {
+		return errors.Newf("unexpected LDAP operation with tag 0x%x, expected 0x%x",
+			op.tag, expected|berConstructed)
+	}
+	return nil
+}
+
+// startTLS upgrades the connection to TLS using the StartTLS extended
+// operation.
+func (c *ldapConn) startTLS(tlsConfig *tls.Config) error {
+	op, err := c.roundTrip(berSequence(opExtendedRequest,
+		berString(extendedRequestNameTag, startTLSOID),
+	))
+	if err != nil {
+		return err
+	}
+	if err := checkOp(&op, opExtendedResponse); err != nil {
+		return err
+	}
+	if err := parseResult(&op); err != nil {
+		return errors.Wrap(err, "StartTLS")
+	}
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = tlsConn
+	c.r = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// bind performs a simple bind. The caller must ensure that the password is
+// not empty, since that would perform an unauthenticated bind which always
+// succeeds (RFC 4513, section 5.1.2).
+func (c *ldapConn) bind(dn, password string) error {
+	op, err := c.roundTrip(berSequence(opBindRequest,
+		berInt(berTagInteger, 3 /* version */),
+		berString(berTagOctetString, dn),
+		berString(simpleAuthTag, password),
+	))
+	if err != nil {
+		return err
+	}
+	if err := checkOp(&op, opBindResponse); err != nil {
+		return err
+	}
+	return parseResult(&op)
+}
+
+// search returns the entries below baseDN which match the filter, with the
+// requested attributes. At most sizeLimit entries are returned, even if more
+// entries match.
+func (c *ldapConn) search(
+	baseDN string, filter berElement, attrs []string, sizeLimit int64, timeLimitSecs int64,
+) ([]ldapEntry, error) {
+	if len(attrs) == 0 {
+		attrs = []string{noAttributes}
+	}
+	attrList := make([]berElement, len(attrs))
+	for i, a := range attrs {
+		attrList[i] = berString(berTagOctetString, a)
+	}
+	if err := c.send(berSequence(opSearchRequest,
+		berString(berTagOctetString, baseDN),
+		berInt(berTagEnumerated, scopeWholeSubtree),
+		berInt(berTagEnumerated, neverDerefAliases),
+		berInt(berTagInteger, sizeLimit),
+		berInt(berTagInteger, timeLimitSecs),
+		berBool(berTagBoolean, false /* typesOnly */),
+		filter,
+		berSequence(berTagSequence, attrList...),
+	)); err != nil {
+		return nil, err
+	}
+
+	var entries []ldapEntry
+	for {
+		op, err := c.receive()
+		if err != nil {
+			return nil, err
+		}
+		switch op.tag &^ berConstructed {
+		case opSearchResultEntry:
+			entry, err := parseSearchResultEntry(&op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case opSearchResultRef:
+			// Referrals to other servers are not followed.
+		case opSearchResultDone:
+			err := parseResult(&op)
+			if resErr := (*ldapResultError)(nil); errors.As(err, &resErr) &&
+				resErr.code == resultSizeLimitExceeded {
+				// The caller finds out from the number of entries.
+				err = nil
+			}
+			return entries, err
+		default:
+			return nil, errors.Newf("unexpected LDAP operation with tag 0x%x in search results", op.tag)
+		}
+	}
+}
+
+func parseSearchResultEntry(op *berElement) (ldapEntry, error) {
+	dn, err := op.child(0, berTagOctetString)
+	if err != nil {
+		return ldapEntry{}, err
+	}
+	attrs, err := op.child(1, berTagSequence)
+	if err != nil {
+		return ldapEntry{}, err
+	}
+	entry := ldapEntry{dn: dn.str(), attrs: make(map[string][]string)}
+	for i := range attrs.children {
+		attr := &attrs.children[i]
+		typ, err := attr.child(0, berTagOctetString)
+		if err != nil {
+			return ldapEntry{}, err
+		}
+		vals, err := attr.child(1, berTagSet)
+		if err != nil {
+			return ldapEntry{}, err
+		}
+		name := strings.ToLower(typ.str())
+		for j := range vals.children {
+			entry.attrs[name] = append(entry.attrs[name], vals.children[j].str())
+		}
+	}
+	return entry, nil
+}
+
+// close sends an UnbindRequest and closes the connection.
+func (c *ldapConn) close() {
+	_ = c.send(berElement{tag: opUnbindRequest})
+	_ = c.conn.Close()
+}
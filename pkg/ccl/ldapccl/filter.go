@@ -0,0 +1,236 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Tags of the Filter CHOICE of a SearchRequest, see section 4.5.1 of
+// RFC 4511. The constructed bit is added by berSequence where needed.
+const (
+	filterAnd            = berClassContext | 0
+	filterOr             = berClassContext | 1
+	filterNot            = berClassContext | 2
+	filterEquality       = berClassContext | 3
+	filterSubstrings     = berClassContext | 4
+	filterGreaterOrEqual = berClassContext | 5
+	filterLessOrEqual    = berClassContext | 6
+	filterPresent        = berClassContext | 7
+	filterApprox         = berClassContext | 8
+
+	substringInitial = berClassContext | 0
+	substringAny     = berClassContext | 1
+	substringFinal   = berClassContext | 2
+)
+
+// escapeFilterValue escapes the characters of s which have a special
+// meaning in the string representation of search filters, as described in
+// section 3 of RFC 4515.
+func escapeFilterValue(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '*', '(', ')', '\\', 0:
+			sb.WriteByte('\\')
+			sb.WriteString(hex.EncodeToString([]byte{c}))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// compileFilter parses the string representation of a search filter
+// (RFC 4515) into its BER encoding. Extensible matches are not supported.
+func compileFilter(s string) (berElement, error) {
+	p := filterParser{s: s}
+	f, err := p.parseFilter()
+	if err != nil {
+		return berElement{}, errors.Wrapf(err, "invalid LDAP search filter %q", s)
+	}
+	if p.pos != len(s) {
+		return berElement{}, errors.Newf(
+			"invalid LDAP search filter %q: unexpected characters after position %d", s, p.pos)
+	}
+	return f, nil
+}
+
+type filterParser struct {
+	s   string
+	pos int
+	// depth is the number of filters being parsed which enclose the current
+	// position.
+	depth int
+}
+
+func (p *filterParser) parseFilter() (berElement, error) {
+	if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+		return berElement{}, errors.Newf("expected ( at position %d", p.pos)
+	}
+	// Bound the recursion of the parser, like the decoding of BER elements.
+	if p.depth >= maxBERDepth {
+		return berElement{}, errors.Newf("filters nested more than %d levels deep", maxBERDepth)
+	}
+	p.depth++
+	defer func() { p.depth-- }()
+	p.pos++
+	if p.pos >= len(p.s) {
+		return berElement{}, errors.New("unexpected end of filter")
+	}
+	var f berElement
+	var err error
+	switch p.s[p.pos] {
+	case '&':
+		p.pos++
+		f, err = p.parseList(filterAnd)
+	case '|':
+		p.pos++
+		f, err = p.parseList(filterOr)
+	case '!':
+		p.pos++
+		var inner berElement
+		inner, err = p.parseFilter()
+		f = berSequence(filterNot, inner)
+	default:
+		f, err = p.parseItem()
+	}
+	if err != nil {
+		return berElement{}, err
+	}
+	if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+		return berElement{}, errors.Newf("expected ) at position %d", p.pos)
+	}
+	p.pos++
+	return f, nil
+}
+
+func (p *filterParser) parseList(tag byte) (berElement, error) {
+	var children []berElement
+	for p.pos < len(p.s) && p.s[p.pos] == '(' {
+		f, err := p.parseFilter()
+		if err != nil {
+			return berElement{}, err
+		}
+		children = append(children, f)
+	}
+	if len(children) == 0 {
+		return berElement{}, errors.Newf("empty filter list at position %d", p.pos)
+	}
+	return berSequence(tag, children...), nil
+}
+
+func (p *filterParser) parseItem() (berElement, error) {
+	end := strings.IndexByte(p.s[p.pos:], ')')
+	if end < 0 {
+		return berElement{}, errors.New("unexpected end of filter")
+	}
+	item := p.s[p.pos : p.pos+end]
+	p.pos += end
+
+	eq := strings.IndexByte(item, '=')
+	if eq <= 0 {
+		return berElement{}, errors.Newf("invalid filter item %q", item)
+	}
+	attr, value := item[:eq], item[eq+1:]
+	tag := filterEquality
+	switch attr[len(attr)-1] {
+	case '~':
+		tag = filterApprox
+	case '>':
+		tag = filterGreaterOrEqual
+	case '<':
+		tag = filterLessOrEqual
+	}
+	if tag != filterEquality {
+		attr = attr[:len(attr)-1]
+	}
+	if !validAttributeDescription(attr) {
+		return berElement{}, errors.Newf("invalid attribute description %q", attr)
+	}
+
+	if tag == filterEquality && value == "*" {
+		return berString(filterPresent, attr), nil
+	}
+	if tag == filterEquality && strings.Contains(value, "*") {
+		parts := strings.Split(value, "*")
+		var substrings []berElement
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			v, err := unescapeFilterValue(part)
+			if err != nil {
+				return berElement{}, err
+			}
+			subTag := substringAny
+			if i == 0 {
+				subTag = substringInitial
+			} else if i == len(parts)-1 {
+				subTag = substringFinal
+			}
+			substrings = append(substrings, berString(subTag, v))
+		}
+		if len(substrings) == 0 {
+			return berElement{}, errors.Newf("invalid substring filter %q", item)
+		}
+		return berSequence(filterSubstrings,
+			berString(berTagOctetString, attr),
+			berSequence(berTagSequence, substrings...),
+		), nil
+	}
+	v, err := unescapeFilterValue(value)
+	if err != nil {
+		return berElement{}, err
+	}
+	return berSequence(tag,
+		berString(berTagOctetString, attr),
+		berString(berTagOctetString, v),
+	), nil
+}
+
+func validAttributeDescription(attr string) bool {
+	if attr == "" {
+		return false
+	}
+	for _, c := range attr {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '.' || c == ';') {
+			return false
+		}
+	}
+	return true
+}
+
+// unescapeFilterValue decodes the \XX escape sequences of an assertion value.
+func unescapeFilterValue(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		if i+3 > len(s) {
+			return "", errors.Newf("invalid escape sequence in %q", s)
+		}
+		b, err := hex.DecodeString(s[i+1 : i+3])
+		if err != nil {
+			return "", errors.Newf("invalid escape sequence in %q", s)
+		}
+		sb.Write(b)
+		i += 2
+	}
+	return sb.String(), nil
+}
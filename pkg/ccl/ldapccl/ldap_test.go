@@ -0,0 +1,371 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/securitytest"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+var testEntries = []testLDAPEntry{
+	{
+		dn:       "cn=service,dc=example,dc=com",
+		password: "servicepw",
+	},
+	{
+		dn:       "uid=alice,ou=users,dc=example,dc=com",
+		password: "alicepw",
+		attrs: map[string][]string{
+			"uid": {"alice"},
+			"memberOf": {
+				"cn=analysts,ou=groups,dc=example,dc=com",
+				"cn=unknown,ou=groups,dc=example,dc=com",
+			},
+		},
+	},
+	{
+		dn:       "uid=bob,ou=users,dc=example,dc=com",
+		password: "bobpw",
+		attrs:    map[string][]string{"uid": {"bob"}, "mail": {"bob@example.com"}},
+	},
+	// Two entries match uid=dup.
+	{
+		dn:       "uid=dup,ou=users,dc=example,dc=com",
+		password: "duppw",
+		attrs:    map[string][]string{"uid": {"dup"}},
+	},
+	{
+		dn:       "cn=dup,ou=users,dc=example,dc=com",
+		password: "duppw",
+		attrs:    map[string][]string{"uid": {"dup"}},
+	},
+}
+
+func TestCompileFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	bob := &testEntries[2]
+	for _, tc := range []struct {
+		filter  string
+		matches bool
+		err     string
+	}{
+		{filter: `(uid=bob)`, matches: true},
+		{filter: `(UID=BOB)`, matches: true},
+		{filter: `(uid=alice)`, matches: false},
+		{filter: `(mail=*)`, matches: true},
+		{filter: `(cn=*)`, matches: false},
+		{filter: `(mail=bob@*)`, matches: true},
+		{filter: `(mail=*@example.com)`, matches: true},
+		{filter: `(mail=b*@*.com)`, matches: true},
+		{filter: `(mail=b*z*.com)`, matches: false},
+		{filter: `(&(uid=bob)(mail=*))`, matches: true},
+		{filter: `(&(uid=bob)(!(mail=*)))`, matches: false},
+		{filter: `(|(uid=alice)(uid=bob))`, matches: true},
+		{filter: `(uid>=b)`, matches: true},
+		{filter: `(uid<=b)`, matches: false},
+		{filter: `(uid~=bob)`, matches: true},
+		{filter: `(uid=\62ob)`, matches: true},
+		{filter: `uid=bob`, err: `expected ( at position 0`},
+		{filter: `(uid=bob`, err: `unexpected end of filter`},
+		{filter: `(uid=bob))`, err: `unexpected characters after position 9`},
+		{filter: `(&)`, err: `empty filter list`},
+		{filter: `(=bob)`, err: `invalid filter item`},
+		{filter: `(u id=bob)`, err: `invalid attribute description`},
+		{filter: `(uid=\6)`, err: `invalid escape sequence`},
+		{filter: `(uid=**)`, err: `invalid substring filter`},
+		{filter: strings.Repeat("(!", 40) + "(uid=bob)" + strings.Repeat(")", 40), err: `filters nested more than 32 levels deep`},
+	} {
+		t.Run(tc.filter, func(t *testing.T) {
+			f, err := compileFilter(tc.filter)
+			if tc.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			// Round-trip the filter through its BER encoding.
+			elems, err := decodeBERElements(f.encode(nil))
+			require.NoError(t, err)
+			require.Len(t, elems, 1)
+			require.Equal(t, tc.matches, matchFilter(&elems[0], bob))
+		})
+	}
+
+	require.Equal(t, `a\2ab\28c\29d\5ce\00`, escapeFilterValue("a*b(c)d\\e\x00"))
+	f, err := compileFilter("(uid=" + escapeFilterValue("*") + ")")
+	require.NoError(t, err)
+	require.False(t, matchFilter(&f, bob))
+}
+
+func TestBERInt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		v       int64
+		encoded []byte
+	}{
+		{0, []byte{0}},
+		{3, []byte{3}},
+		{127, []byte{0x7f}},
+		{128, []byte{0, 0x80}},
+		{256, []byte{1, 0}},
+		{-1, []byte{0xff}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xff, 0x7f}},
+	} {
+		e := berInt(berTagInteger, tc.v)
+		require.Equal(t, tc.encoded, e.value)
+		v, err := e.int()
+		require.NoError(t, err)
+		require.Equal(t, tc.v, v)
+	}
+
+	// Long form lengths.
+	long := berString(berTagOctetString, string(make([]byte, 300)))
+	buf := long.encode(nil)
+	require.Equal(t, []byte{berTagOctetString, 0x82, 0x01, 0x2c}, buf[:4])
+	elems, err := decodeBERElements(buf)
+	require.NoError(t, err)
+	require.Equal(t, long, elems[0])
+	_, err = decodeBERElements(buf[:100])
+	require.EqualError(t, err, "truncated BER element")
+
+	// Deeply nested elements are rejected.
+	nested := func(depth int) []byte {
+		e := berString(berTagOctetString, "x")
+		for i := 0; i < depth; i++ {
+			e = berSequence(berTagSequence, e)
+		}
+		return e.encode(nil)
+	}
+	_, err = decodeBERElements(nested(maxBERDepth))
+	require.NoError(t, err)
+	_, err = decodeBERElements(nested(maxBERDepth + 1))
+	require.EqualError(t, err, "BER elements nested more than 32 levels deep")
+}
+
+// testTLSConfigs returns a server TLS configuration using the test node
+// certificate, and the pool containing the CA which signed it.
+func testTLSConfigs(t *testing.T) (*tls.Config, *x509.CertPool) {
+	certPEM, err := securitytest.Asset(filepath.Join(security.EmbeddedCertsDir, security.EmbeddedNodeCert))
+	require.NoError(t, err)
+	keyPEM, err := securitytest.Asset(filepath.Join(security.EmbeddedCertsDir, security.EmbeddedNodeKey))
+	require.NoError(t, err)
+	caPEM, err := securitytest.Asset(filepath.Join(security.EmbeddedCertsDir, security.EmbeddedCACert))
+	require.NoError(t, err)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(caPEM))
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, pool
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	serverTLS, rootCAs := testTLSConfigs(t)
+	defer func(old *x509.CertPool) { testingRootCAs = old }(testingRootCAs)
+	testingRootCAs = rootCAs
+
+	ctx := context.Background()
+	for _, mode := range []string{"plain", "starttls", "ldaps"} {
+		t.Run(mode, func(t *testing.T) {
+			var s *testLDAPServer
+			opts := [][2]string{{"ldapbasedn", "ou=users,dc=example,dc=com"}}
+			switch mode {
+			case "plain":
+				s = startTestLDAPServer(t, testEntries, nil /* ldapsConfig */)
+				opts = append(opts, [2]string{"ldapurl", "ldap://" + s.addr()})
+			case "starttls":
+				s = startTestLDAPServer(t, testEntries, nil /* ldapsConfig */)
+				s.tlsConfig = serverTLS
+				opts = append(opts, [2]string{"ldapurl", "ldap://" + s.addr()}, [2]string{"ldaptls", "1"})
+			case "ldaps":
+				s = startTestLDAPServer(t, testEntries, serverTLS)
+				opts = append(opts, [2]string{"ldapurl", "ldaps://" + s.addr()})
+			}
+			defer s.stop()
+
+			cfg, err := parseLDAPConfig(hba.Entry{Options: opts})
+			require.NoError(t, err)
+
+			_, err = cfg.authenticate(ctx, "bob", "bobpw")
+			require.NoError(t, err)
+			_, err = cfg.authenticate(ctx, "bob", "alicepw")
+			require.EqualError(t, err,
+				`binding as "uid=bob,ou=users,dc=example,dc=com": LDAP result code 49`)
+			_, err = cfg.authenticate(ctx, "carl", "carlpw")
+			require.EqualError(t, err, `user "carl" not found in the directory`)
+			_, err = cfg.authenticate(ctx, "dup", "duppw")
+			require.EqualError(t, err, `user "dup" is not unique in the directory`)
+			// The user name cannot be used to inject filter expressions.
+			_, err = cfg.authenticate(ctx, "*", "bobpw")
+			require.EqualError(t, err, `user "*" not found in the directory`)
+
+			// Searches which require credentials.
+			s.requireBind = true
+			_, err = cfg.authenticate(ctx, "bob", "bobpw")
+			require.EqualError(t, err, `searching the directory: LDAP result code 50`)
+			cfg.bindDN = "cn=service,dc=example,dc=com"
+			cfg.bindPassword = "servicepw"
+			cfg.groupAttribute = "memberof"
+			groups, err := cfg.authenticate(ctx, "alice", "alicepw")
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				"cn=analysts,ou=groups,dc=example,dc=com",
+				"cn=unknown,ou=groups,dc=example,dc=com",
+			}, groups)
+			s.requireBind = false
+		})
+	}
+}
+
+func TestParseLDAPConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		conf string
+		err  string
+	}{
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example`},
+		{conf: `ldapurl=ldaps://localhost:1636 "ldapbasedn=ou=users,dc=example" ldapsearchfilter=(mail=$username@example.com)`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldaptls=1 ldapbinddn=cn=admin ldapbindpasswd=pw ldapgroupattribute=memberOf ldapgrouprole=analysts:cn=analysts`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf "ldapgrouprole=analysts:cn=analysts,dc=example" "ldapgrouprole=Auditors: CN=Auditors, DC=example"`},
+		{conf: `ldapbasedn=dc=example`, err: `missing "ldapurl" option in LDAP entry`},
+		{conf: `ldapurl=ldap://localhost`, err: `missing "ldapbasedn" option in LDAP entry`},
+		{conf: `ldapurl=http://localhost ldapbasedn=dc=example`, err: `unsupported scheme in ldapurl: "http"`},
+		{conf: `ldapurl=ldap:///dc=example ldapbasedn=dc=example`, err: `missing host in ldapurl`},
+		{conf: `ldapurl=ldap://localhost/dc=example ldapbasedn=dc=example`, err: `ldapurl must only specify the scheme, host and port`},
+		{conf: `ldapurl=ldaps://localhost ldapbasedn=dc=example ldaptls=1`, err: `ldaptls cannot be used with an ldaps URL`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldaptls=yes`, err: `ldaptls must be set to 0 or 1: yes`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapbinddn=cn=admin`, err: `ldapbinddn and ldapbindpasswd must be specified together`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapsearchfilter=uid=$username`, err: `invalid LDAP search filter`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=member(of) ldapgrouprole=analysts:cn=analysts`, err: `invalid ldapgroupattribute`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf`, err: `ldapgroupattribute and ldapgrouprole must be specified together`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgrouprole=analysts:cn=analysts`, err: `ldapgroupattribute and ldapgrouprole must be specified together`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf ldapgrouprole=analysts`, err: `invalid ldapgrouprole: "analysts"`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf ldapgrouprole=analysts:`, err: `invalid ldapgrouprole: "analysts:"`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf ldapgrouprole=admin:cn=admins`, err: `ldapgrouprole cannot grant the reserved role admin`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf ldapgrouprole=ROOT:cn=admins`, err: `ldapgrouprole cannot grant the reserved role root`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapgroupattribute=memberOf ldapgrouprole=public:cn=everyone`, err: `ldapgrouprole cannot grant the reserved role public`},
+		{conf: `ldapurl=ldap://localhost ldapbasedn=dc=example ldapport=389`, err: `unsupported option ldapport`},
+	} {
+		t.Run(tc.conf, func(t *testing.T) {
+			conf, err := hba.ParseAndNormalize("host all all all ldap " + tc.conf)
+			require.NoError(t, err)
+			err = checkEntry(conf.Entries[0])
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestLDAPAuthenticationMethod(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// The entries are copied since the groups of alice are changed below.
+	entries := append([]testLDAPEntry(nil), testEntries...)
+	ldapServer := startTestLDAPServer(t, entries, nil /* ldapsConfig */)
+	defer ldapServer.stop()
+	ldapServer.requireBind = true
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE USER alice`)
+	sqlDB.Exec(t, `CREATE USER bob WITH PASSWORD 'sqlpw'`)
+	for _, role := range []string{"analysts", "auditors", "developers", "ops"} {
+		sqlDB.Exec(t, `CREATE ROLE `+role)
+	}
+	// ops is mapped to a group of alice but is a member of admin, so it must
+	// never be granted by the directory.
+	sqlDB.Exec(t, `GRANT admin TO ops`)
+	// auditors is mapped to a group alice does not belong to, but it was not
+	// granted by the directory, so it is left untouched like developers, which
+	// is not mapped.
+	sqlDB.Exec(t, `GRANT auditors, developers TO alice`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING server.host_based_authentication.configuration = $1`,
+		fmt.Sprintf(`host all alice,bob all ldap ldapurl=ldap://%s "ldapbasedn=ou=users,dc=example,dc=com" `+
+			`"ldapbinddn=cn=service,dc=example,dc=com" ldapbindpasswd=servicepw ldapgroupattribute=memberOf `+
+			`"ldapgrouprole=analysts:CN=Analysts, OU=Groups,dc=example,dc=com" `+
+			`"ldapgrouprole=auditors:cn=auditors,ou=groups,dc=example,dc=com" `+
+			`"ldapgrouprole=ops:cn=unknown,ou=groups,dc=example,dc=com"`,
+			ldapServer.addr()))
+
+	connect := func(user, password string) error {
+		pgURL, cleanup := sqlutils.PGUrlWithOptionalClientCerts(
+			t, s.ServingSQLAddr(), t.Name(), url.UserPassword(user, password), false /* withClientCerts */)
+		defer cleanup()
+		userDB, err := gosql.Open("postgres", pgURL.String())
+		require.NoError(t, err)
+		defer userDB.Close()
+		_, err = userDB.Exec(`SELECT 1`)
+		return err
+	}
+
+	testutils.SucceedsSoon(t, func() error {
+		return connect("alice", "alicepw")
+	})
+	require.Regexp(t, "password authentication failed for user alice", connect("alice", "bobpw"))
+	require.Regexp(t, "password authentication failed for user alice", connect("alice", ""))
+	// The SQL password is not used.
+	require.Regexp(t, "password authentication failed for user bob", connect("bob", "sqlpw"))
+	require.NoError(t, connect("bob", "bobpw"))
+
+	// Alice was granted the role of the group analysts, and the grant was
+	// recorded.
+	const rolesQuery = `SELECT role, member FROM system.role_members WHERE member IN ('alice', 'bob') ORDER BY 1`
+	const syncedQuery = `SELECT username, value FROM system.role_options WHERE option = 'LDAPGROUPROLES'`
+	sqlDB.CheckQueryResults(t, rolesQuery,
+		[][]string{{"analysts", "alice"}, {"auditors", "alice"}, {"developers", "alice"}})
+	sqlDB.CheckQueryResults(t, syncedQuery, [][]string{{"alice", "analysts"}})
+	// The record is not a role option: it can't be set, and isn't shown.
+	sqlDB.ExpectErr(t, "syntax error", `ALTER ROLE alice WITH LDAPGROUPROLES = 'ops'`)
+	sqlDB.CheckQueryResults(t, `SELECT options FROM [SHOW ROLES] WHERE username = 'alice'`,
+		[][]string{{""}})
+
+	// Alice leaves the group analysts, and loses the role on the next login.
+	// auditors, which was granted by hand, is kept.
+	ldapServer.findEntry("uid=alice,ou=users,dc=example,dc=com").attrs = map[string][]string{
+		"uid":      {"alice"},
+		"memberOf": {"cn=unknown,ou=groups,dc=example,dc=com"},
+	}
+	require.NoError(t, connect("alice", "alicepw"))
+	sqlDB.CheckQueryResults(t, rolesQuery,
+		[][]string{{"auditors", "alice"}, {"developers", "alice"}})
+	sqlDB.CheckQueryResults(t, syncedQuery, [][]string{})
+}
@@ -0,0 +1,300 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package ldapccl
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Result codes returned by the test server, in addition to those used by
+// the client.
+const (
+	resultProtocolError           = 2
+	resultInvalidCredentials      = 49
+	resultInsufficientAccessRight = 50
+)
+
+// testLDAPEntry is an entry of the directory served by testLDAPServer.
+type testLDAPEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// testLDAPServer is a minimal in-process LDAP server which supports simple
+// binds, searches and StartTLS. It is just enough to exercise the ldap
+// authentication method.
+type testLDAPServer struct {
+	ln      net.Listener
+	entries []testLDAPEntry
+	// tlsConfig, if set, is used to serve StartTLS requests.
+	tlsConfig *tls.Config
+	// requireBind, if set, requires a non-anonymous bind before searches.
+	requireBind bool
+	wg          sync.WaitGroup
+}
+
+// startTestLDAPServer starts serving the entries on a local port. If
+// ldapsConfig is set, connections use TLS from the start, as with ldaps://
+// URLs.
+func startTestLDAPServer(
+	t *testing.T, entries []testLDAPEntry, ldapsConfig *tls.Config,
+) *testLDAPServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if ldapsConfig != nil {
+		ln = tls.NewListener(ln, ldapsConfig)
+	}
+	s := &testLDAPServer{ln: ln, entries: entries}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer conn.Close()
+				s.serve(conn)
+			}()
+		}
+	}()
+	return s
+}
+
+func (s *testLDAPServer) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *testLDAPServer) stop() {
+	_ = s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *testLDAPServer) findEntry(dn string) *testLDAPEntry {
+	for i := range s.entries {
+		if strings.EqualFold(s.entries[i].dn, dn) {
+			return &s.entries[i]
+		}
+	}
+	return nil
+}
+
+func (s *testLDAPServer) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	bound := false
+	for {
+		msg, err := readBERElement(r)
+		if err != nil || len(msg.children) < 2 {
+			return
+		}
+		id, err := msg.children[0].int()
+		if err != nil {
+			return
+		}
+		op := &msg.children[1]
+		reply := func(ops ...berElement) bool {
+			var buf []byte
+			for _, op := range ops {
+				resp := berSequence(berTagSequence, berInt(berTagInteger, id), op)
+				buf = resp.encode(buf)
+			}
+			_, err := conn.Write(buf)
+			return err == nil
+		}
+		result := func(tag byte, code int64) berElement {
+			return berSequence(tag,
+				berInt(berTagEnumerated, code),
+				berString(berTagOctetString, ""),
+				berString(berTagOctetString, ""),
+			)
+		}
+
+		switch op.tag &^ berConstructed {
+		case opBindRequest:
+			code := int64(resultInvalidCredentials)
+			name, password := op.children[1].str(), op.children[2].str()
+			if name == "" && password == "" {
+				code = resultSuccess
+				bound = false
+			} else if e := s.findEntry(name); e != nil && password != "" && e.password == password {
+				code = resultSuccess
+				bound = true
+			}
+			if !reply(result(opBindResponse, code)) {
+				return
+			}
+
+		case opSearchRequest:
+			if s.requireBind && !bound {
+				if !reply(result(opSearchResultDone, resultInsufficientAccessRight)) {
+					return
+				}
+				continue
+			}
+			baseDN := strings.ToLower(op.children[0].str())
+			sizeLimit, _ := op.children[3].int()
+			filter := &op.children[6]
+			var requested []string
+			for _, a := range op.children[7].children {
+				requested = append(requested, strings.ToLower(a.str()))
+			}
+			var resps []berElement
+			code := int64(resultSuccess)
+			for i := range s.entries {
+				e := &s.entries[i]
+				if !strings.HasSuffix(strings.ToLower(e.dn), baseDN) || !matchFilter(filter, e) {
+					continue
+				}
+				if sizeLimit > 0 && int64(len(resps)) == sizeLimit {
+					code = resultSizeLimitExceeded
+					break
+				}
+				var attrs []berElement
+				for name, vals := range e.attrs {
+					found := false
+					for _, a := range requested {
+						found = found || a == strings.ToLower(name)
+					}
+					if !found {
+						continue
+					}
+					var valElems []berElement
+					for _, v := range vals {
+						valElems = append(valElems, berString(berTagOctetString, v))
+					}
+					attrs = append(attrs, berSequence(berTagSequence,
+						berString(berTagOctetString, name),
+						berSequence(berTagSet, valElems...),
+					))
+				}
+				resps = append(resps, berSequence(opSearchResultEntry,
+					berString(berTagOctetString, e.dn),
+					berSequence(berTagSequence, attrs...),
+				))
+			}
+			resps = append(resps, result(opSearchResultDone, code))
+			if !reply(resps...) {
+				return
+			}
+
+		case opExtendedRequest:
+			if s.tlsConfig == nil || op.children[0].str() != startTLSOID {
+				if !reply(result(opExtendedResponse, resultProtocolError)) {
+					return
+				}
+				continue
+			}
+			if !reply(result(opExtendedResponse, resultSuccess)) {
+				return
+			}
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(tlsConn)
+
+		default:
+			// Unbind requests, and everything else, close the connection.
+			return
+		}
+	}
+}
+
+// matchFilter evaluates a search filter against an entry. Attribute names
+// and values are compared case-insensitively.
+func matchFilter(f *berElement, e *testLDAPEntry) bool {
+	values := func(attr string) []string {
+		for name, vals := range e.attrs {
+			if strings.EqualFold(name, attr) {
+				return vals
+			}
+		}
+		return nil
+	}
+	switch f.tag &^ berConstructed {
+	case filterAnd:
+		for i := range f.children {
+			if !matchFilter(&f.children[i], e) {
+				return false
+			}
+		}
+		return true
+	case filterOr:
+		for i := range f.children {
+			if matchFilter(&f.children[i], e) {
+				return true
+			}
+		}
+		return false
+	case filterNot:
+		return !matchFilter(&f.children[0], e)
+	case filterPresent:
+		return len(values(f.str())) > 0
+	case filterEquality, filterApprox, filterGreaterOrEqual, filterLessOrEqual:
+		assertion := strings.ToLower(f.children[1].str())
+		for _, v := range values(f.children[0].str()) {
+			v = strings.ToLower(v)
+			switch f.tag &^ berConstructed {
+			case filterGreaterOrEqual:
+				if v >= assertion {
+					return true
+				}
+			case filterLessOrEqual:
+				if v <= assertion {
+					return true
+				}
+			default:
+				if v == assertion {
+					return true
+				}
+			}
+		}
+		return false
+	case filterSubstrings:
+	nextValue:
+		for _, v := range values(f.children[0].str()) {
+			v = strings.ToLower(v)
+			for _, sub := range f.children[1].children {
+				s := strings.ToLower(sub.str())
+				switch sub.tag {
+				case substringInitial:
+					if !strings.HasPrefix(v, s) {
+						continue nextValue
+					}
+					v = v[len(s):]
+				case substringAny:
+					i := strings.Index(v, s)
+					if i < 0 {
+						continue nextValue
+					}
+					v = v[i+len(s):]
+				case substringFinal:
+					if !strings.HasSuffix(v, s) {
+						continue nextValue
+					}
+				}
+			}
+			return true
+		}
+		return false
+	}
+	return false
+}
@@ -11,6 +11,8 @@ package engineccl
 import (
 	"context"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/baseccl"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl/engineccl/enginepbccl"
@@ -18,6 +20,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
 	"github.com/cockroachdb/pebble/vfs"
 )
 
@@ -64,6 +68,13 @@ import (
 // - The store-FS wraps a mem-FS for reading/writing data keys.
 // - The DataKeyManager uses the store-FS.
 // - The data-FS wraps a base-FS for reading/writing data.
+//
+// Files written with a data key keep using it until they are deleted. To retire old keys,
+// sstables that are no longer written to can be re-encrypted with the active data key (see
+// encryptedFS.reencryptFile): the file is copied to a temporary file, which is then renamed
+// over the original file, after which the registry entry of the temporary file replaces that
+// of the original file. A crash in the middle of this is cleaned up on the next start by
+// recoverReencryption.
 
 // encryptedFile implements vfs.File.
 type encryptedFile struct {
@@ -104,11 +115,20 @@ func (f *encryptedFile) ReadAt(p []byte, off int64) (n int, err error) {
 	return n, err
 }
 
+// reencryptSuffix is the suffix of the temporary file a file is copied to while it is
+// being re-encrypted.
+const reencryptSuffix = ".reencrypt"
+
 // encryptedFS implements vfs.FS.
 type encryptedFS struct {
 	vfs.FS
 	fileRegistry  *storage.PebbleFileRegistry
 	streamCreator *FileCipherStreamCreator
+
+	// mu is held for writing while a re-encrypted file replaces the original, and
+	// for reading by operations on existing files, so that they see a file and
+	// its registry entry that match.
+	mu syncutil.RWMutex
 }
 
 // Create implements vfs.FS.Create.
@@ -137,6 +157,8 @@ func (fs *encryptedFS) Create(name string) (vfs.File, error) {
 
 // Link implements vfs.FS.Link.
 func (fs *encryptedFS) Link(oldname, newname string) error {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	if err := fs.FS.Link(oldname, newname); err != nil {
 		return err
 	}
@@ -145,6 +167,8 @@ func (fs *encryptedFS) Link(oldname, newname string) error {
 
 // Open implements vfs.FS.Open.
 func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	f, err := fs.FS.Open(name, opts...)
 	if err != nil {
 		return f, err
@@ -173,6 +197,8 @@ func (fs *encryptedFS) Open(name string, opts ...vfs.OpenOption) (vfs.File, erro
 
 // Remove implements vfs.FS.Remove.
 func (fs *encryptedFS) Remove(name string) error {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	if err := fs.FS.Remove(name); err != nil {
 		return err
 	}
@@ -181,6 +207,8 @@ func (fs *encryptedFS) Remove(name string) error {
 
 // Rename implements vfs.FS.Rename.
 func (fs *encryptedFS) Rename(oldname, newname string) error {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
 	if err := fs.FS.Rename(oldname, newname); err != nil {
 		return err
 	}
@@ -202,9 +230,94 @@ func (fs *encryptedFS) ReuseForWrite(oldname, newname string) (vfs.File, error)
 	return fs.Create(newname)
 }
 
+// reencryptFile rewrites the file in dir, which must not be written to anymore, so that it is
+// encrypted with the active key. Readers that opened the file before it is replaced keep
+// reading the original contents. If the file is removed concurrently, an error satisfying
+// oserror.IsNotExist is returned.
+func (fs *encryptedFS) reencryptFile(dir, name string) error {
+	tmpName := name + reencryptSuffix
+	if err := fs.copyFile(name, tmpName); err != nil {
+		_ = fs.Remove(tmpName)
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if _, err := fs.FS.Stat(name); err != nil {
+		// The file may have been removed while it was being copied.
+		return errors.CombineErrors(err, fs.removeLocked(tmpName))
+	}
+	if err := fs.FS.Rename(tmpName, name); err != nil {
+		return errors.CombineErrors(err, fs.removeLocked(tmpName))
+	}
+	// The rename must be durable before the registry refers to the new encryption
+	// settings, see recoverReencryption.
+	d, err := fs.FS.OpenDir(dir)
+	if err == nil {
+		err = errors.CombineErrors(d.Sync(), d.Close())
+	}
+	// Even if the directory could not be synced, the registry has to match the
+	// file that is now in place.
+	return errors.CombineErrors(err, fs.fileRegistry.MaybeRenameEntry(tmpName, name))
+}
+
+// removeLocked is like Remove, but requires fs.mu to be held.
+func (fs *encryptedFS) removeLocked(name string) error {
+	if err := fs.FS.Remove(name); err != nil && !oserror.IsNotExist(err) {
+		return err
+	}
+	return fs.fileRegistry.MaybeDeleteEntry(name)
+}
+
+// copyFile copies the contents of the file into a newly created file.
+func (fs *encryptedFS) copyFile(name, newName string) error {
+	src, err := fs.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := fs.Create(newName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return errors.CombineErrors(dst.Sync(), dst.Close())
+}
+
+// recoverReencryption cleans up after a crash during reencryptFile. If the temporary file
+// still exists, the original file was not replaced and the temporary file is removed.
+// Otherwise the original file was replaced, but the registry entry of the temporary file may
+// not have been moved over yet.
+func (fs *encryptedFS) recoverReencryption(dir string) error {
+	names, err := fs.FS.List(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		path := fs.FS.PathJoin(dir, name)
+		if strings.HasSuffix(name, reencryptSuffix) {
+			if err := fs.Remove(path); err != nil {
+				return err
+			}
+			continue
+		}
+		if fs.fileRegistry.GetFileEntry(path+reencryptSuffix) != nil {
+			if err := fs.fileRegistry.MaybeRenameEntry(path+reencryptSuffix, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 type encryptionStatsHandler struct {
 	storeKM *StoreKeyManager
 	dataKM  *DataKeyManager
+	dataFS  *encryptedFS
+	dbDir   string
 }
 
 func (e *encryptionStatsHandler) GetEncryptionStatus() ([]byte, error) {
@@ -245,6 +358,10 @@ func (e *encryptionStatsHandler) GetActiveStoreKeyType() int32 {
 	return int32(enginepbccl.EncryptionType_Plaintext)
 }
 
+func (e *encryptionStatsHandler) ReencryptFile(filename string) error {
+	return e.dataFS.reencryptFile(e.dbDir, filename)
+}
+
 func (e *encryptionStatsHandler) GetKeyIDFromSettings(settings []byte) (string, error) {
 	var s enginepbccl.EncryptionSettings
 	if err := protoutil.Unmarshal(settings, &s); err != nil {
@@ -314,6 +431,11 @@ func newEncryptedEnv(
 		if err := dataKeyManager.SetActiveStoreKeyInfo(context.TODO(), key.Info); err != nil {
 			return nil, nil, err
 		}
+		if err := dataFS.recoverReencryption(dbDir); err != nil {
+			return nil, nil, err
+		}
 	}
-	return dataFS, &encryptionStatsHandler{storeKM: storeKeyManager, dataKM: dataKeyManager}, nil
+	return dataFS, &encryptionStatsHandler{
+		storeKM: storeKeyManager, dataKM: dataKeyManager, dataFS: dataFS, dbDir: dbDir,
+	}, nil
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors/oserror"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/vfs"
 	"github.com/stretchr/testify/require"
@@ -280,3 +281,85 @@ func TestPebbleEncryption(t *testing.T) {
 
 	db.Close()
 }
+
+// TestPebbleReencryption verifies that files written with a data key that is
+// no longer active are re-encrypted with the active data key, and that a
+// re-encryption interrupted by a crash is cleaned up when reopening.
+func TestPebbleReencryption(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	memFS := vfs.NewMem()
+	writeToFile(t, memFS, "16.key", []byte("111111111111111111111111111111111234567890123456"))
+	writeToFile(t, memFS, "24.key", []byte("22222222222222222222222222222222123456789012345678901234"))
+	open := func(currentKey, oldKey string) storage.Engine {
+		var encOptions baseccl.EncryptionOptions
+		encOptions.KeySource = baseccl.EncryptionKeySource_KeyFiles
+		encOptions.KeyFiles = &baseccl.EncryptionKeyFiles{CurrentKey: currentKey, OldKey: oldKey}
+		encOptions.DataKeyRotationPeriod = 1000 // arbitrary seconds
+		encOptionsBytes, err := protoutil.Marshal(&encOptions)
+		require.NoError(t, err)
+		opts := storage.DefaultPebbleOptions()
+		opts.Cache = pebble.NewCache(1 << 20)
+		defer opts.Cache.Unref()
+		opts.FS = memFS
+		db, err := storage.NewPebble(ctx, storage.PebbleConfig{
+			StorageConfig: base.StorageConfig{
+				MaxSize:         512 << 20,
+				UseFileRegistry: true,
+				ExtraOptions:    encOptionsBytes,
+			},
+			Opts: opts,
+		})
+		require.NoError(t, err)
+		return db
+	}
+	// sstKeyIDs returns the IDs of the keys used by sstables.
+	sstKeyIDs := func(db storage.Engine) []string {
+		stats, err := db.GetEnvStats()
+		require.NoError(t, err)
+		var keyIDs []string
+		for _, ks := range stats.KeyStats {
+			if ks.Bytes > 0 {
+				keyIDs = append(keyIDs, ks.KeyID)
+			}
+		}
+		return keyIDs
+	}
+
+	db := open("16.key", "plain")
+	require.NoError(t, db.PutUnversioned(roachpb.Key("a"), []byte("a")))
+	require.NoError(t, db.Flush())
+	oldKeyIDs := sstKeyIDs(db)
+	require.Len(t, oldKeyIDs, 1)
+	n, err := db.ReencryptFiles(ctx, 1<<20)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	db.Close()
+
+	// Changing the store key generates a new data key. The existing sstable
+	// keeps using the old one until it is re-encrypted.
+	db = open("24.key", "16.key")
+	require.Equal(t, oldKeyIDs, sstKeyIDs(db))
+	n, err = db.ReencryptFiles(ctx, 1<<20)
+	require.NoError(t, err)
+	require.NotZero(t, n)
+	newKeyIDs := sstKeyIDs(db)
+	require.Len(t, newKeyIDs, 1)
+	require.NotEqual(t, oldKeyIDs, newKeyIDs)
+	val, err := db.MVCCGet(storage.MVCCKey{Key: roachpb.Key("a")})
+	require.NoError(t, err)
+	require.Equal(t, "a", string(val))
+	db.Close()
+
+	// Leave a temporary file behind, as if a crash happened while copying.
+	writeToFile(t, memFS, "000123.sst"+reencryptSuffix, []byte("partial"))
+	db = open("24.key", "16.key")
+	defer db.Close()
+	_, err = memFS.Stat("000123.sst" + reencryptSuffix)
+	require.True(t, oserror.IsNotExist(err), "%v", err)
+	require.Equal(t, newKeyIDs, sstKeyIDs(db))
+	val, err = db.MVCCGet(storage.MVCCKey{Key: roachpb.Key("a")})
+	require.NoError(t, err)
+	require.Equal(t, "a", string(val))
+}
@@ -64,6 +64,7 @@ func declareKeysExport(
 ) {
 	batcheval.DefaultDeclareIsolatedKeys(desc, header, req, latchSpans, lockSpans)
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeLastGCKey(header.RangeID)})
+	batcheval.DeclareRangeTombstoneKeys(spanset.SpanReadOnly, desc, latchSpans)
 }
 
 // evalExport dumps the requested keys into files of non-overlapping key ranges
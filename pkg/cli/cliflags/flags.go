@@ -812,6 +812,17 @@ memory that the store may consume, for example:
   --store=type=mem,size=20GiB
   --store=type=mem,size=90%
 
+</PRE>
+The "raftlog" field can be set to "separate" to keep the store's Raft log, and
+the payloads of bulk-ingested data awaiting replication, in a dedicated storage
+engine with its own write-ahead log, located in the "raft-log" subdirectory of
+the store. An existing store is migrated to this layout when it is first
+started with this option; once migrated, it cannot be started without it. This
+option is not supported for in-memory stores. For example:
+<PRE>
+
+  --store=path=/mnt/ssd01,raftlog=separate
+
 </PRE>
 Commas are forbidden in all values, since they are used to separate fields.
 Also, if you use equal signs in the file path to a store, you must use the
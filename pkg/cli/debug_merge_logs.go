@@ -436,6 +436,9 @@ type fileLogStream struct {
 	d        *log.EntryDecoder
 	read     bool
 	editMode log.EditSensitiveData
+	// format is the log format family of the file, detected upon
+	// the first open.
+	format string
 
 	e   logpb.Entry
 	err error
@@ -477,11 +480,20 @@ func (s *fileLogStream) open() bool {
 	if s.f, s.err = os.Open(s.fi.path); s.err != nil {
 		return false
 	}
-	if s.err = seekToFirstAfterFrom(s.f, s.from, s.editMode); s.err != nil {
+	if s.format == "" {
+		if s.format, s.err = log.DetectEntryFormat(s.f); s.err != nil {
+			return false
+		}
+		if _, s.err = s.f.Seek(0, io.SeekStart); s.err != nil {
+			return false
+		}
+	}
+	if s.err = seekToFirstAfterFrom(s.f, s.from, s.editMode, s.format); s.err != nil {
 		return false
 	}
-	s.d = log.NewEntryDecoder(bufio.NewReaderSize(s.f, readBufSize), s.editMode)
-	return true
+	s.d, s.err = log.NewEntryDecoderWithFormat(
+		bufio.NewReaderSize(s.f, readBufSize), s.editMode, s.format)
+	return s.err == nil
 }
 
 func (s *fileLogStream) peek() (logpb.Entry, bool) {
@@ -535,7 +547,9 @@ func (s *fileLogStream) error() error        { return s.err }
 
 // seekToFirstAfterFrom uses binary search to seek to an offset after all
 // entries which occur before from.
-func seekToFirstAfterFrom(f *os.File, from time.Time, editMode log.EditSensitiveData) (err error) {
+func seekToFirstAfterFrom(
+	f *os.File, from time.Time, editMode log.EditSensitiveData, format string,
+) (err error) {
 	if from.IsZero() {
 		return nil
 	}
@@ -553,8 +567,12 @@ func seekToFirstAfterFrom(f *os.File, from time.Time, editMode log.EditSensitive
 		if _, err := f.Seek(int64(i), io.SeekStart); err != nil {
 			panic(err)
 		}
+		d, err := log.NewEntryDecoderWithFormat(f, editMode, format)
+		if err != nil {
+			panic(err)
+		}
 		var e logpb.Entry
-		err := log.NewEntryDecoder(f, editMode).Decode(&e)
+		err = d.Decode(&e)
 		if err != nil {
 			if err == io.EOF {
 				return true
@@ -566,8 +584,12 @@ func seekToFirstAfterFrom(f *os.File, from time.Time, editMode log.EditSensitive
 	if _, err := f.Seek(int64(offset), io.SeekStart); err != nil {
 		return err
 	}
+	d, err := log.NewEntryDecoderWithFormat(f, editMode, format)
+	if err != nil {
+		return err
+	}
 	var e logpb.Entry
-	if err := log.NewEntryDecoder(f, editMode).Decode(&e); err != nil {
+	if err := d.Decode(&e); err != nil {
 		return err
 	}
 	_, err = f.Seek(int64(offset), io.SeekStart)
@@ -146,6 +146,21 @@ var cases = []testCase{
 		args:  []string{"testdata/merge_logs/5/redactable.log"},
 		flags: []string{"--redact=true", "--redactable-output=true", "--file-pattern", ".*"},
 	},
+	{
+		name:  "6.json-redact-off",
+		args:  []string{"testdata/merge_logs/6/*/*"},
+		flags: []string{"--redact=false", "--redactable-output=false"},
+	},
+	{
+		name:  "6.json-redact-on",
+		args:  []string{"testdata/merge_logs/6/*/*"},
+		flags: []string{"--redact=true", "--redactable-output=true"},
+	},
+	{
+		name:  "6.json-seek",
+		args:  []string{"testdata/merge_logs/6/*/*"},
+		flags: []string{"--redact=false", "--redactable-output=false", "--from", "210301 10:00:00.250000"},
+	},
 }
 
 func (c testCase) run(t *testing.T) {
@@ -540,18 +540,7 @@ func init() {
 	clientCmds = append(clientCmds, stmtDiagCmds...)
 	clientCmds = append(clientCmds, debugResetQuorumCmd)
 	for _, cmd := range clientCmds {
-		f := cmd.PersistentFlags()
-		varFlag(f, addrSetter{&cliCtx.clientConnHost, &cliCtx.clientConnPort}, cliflags.ClientHost)
-		stringFlag(f, &cliCtx.clientConnPort, cliflags.ClientPort)
-		_ = f.MarkHidden(cliflags.ClientPort.Name)
-
-		// NB: Insecure is deprecated. See #53404.
-		boolFlag(f, &baseCfg.Insecure, cliflags.ClientInsecure)
-
-		// Certificate flags.
-		stringFlag(f, &baseCfg.SSLCertsDir, cliflags.CertsDir)
-		// Certificate principal map.
-		stringSliceFlag(f, &cliCtx.certPrincipalMap, cliflags.CertPrincipalMap)
+		AddClientConnFlags(cmd)
 	}
 
 	// Auth commands.
@@ -1061,3 +1050,20 @@ func extraClientFlagInit() error {
 
 // VarFlag is exported for use in package cliccl.
 var VarFlag = varFlag
+
+// AddClientConnFlags adds the flags used to connect to a node to the command.
+// It is exported for use in package cliccl.
+func AddClientConnFlags(cmd *cobra.Command) {
+	f := cmd.PersistentFlags()
+	varFlag(f, addrSetter{&cliCtx.clientConnHost, &cliCtx.clientConnPort}, cliflags.ClientHost)
+	stringFlag(f, &cliCtx.clientConnPort, cliflags.ClientPort)
+	_ = f.MarkHidden(cliflags.ClientPort.Name)
+
+	// NB: Insecure is deprecated. See #53404.
+	boolFlag(f, &baseCfg.Insecure, cliflags.ClientInsecure)
+
+	// Certificate flags.
+	stringFlag(f, &baseCfg.SSLCertsDir, cliflags.CertsDir)
+	// Certificate principal map.
+	stringSliceFlag(f, &cliCtx.certPrincipalMap, cliflags.CertPrincipalMap)
+}
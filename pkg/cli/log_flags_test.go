@@ -35,11 +35,22 @@ func TestSetupLogging(t *testing.T) {
 	reSimplify := regexp.MustCompile(`(?ms:^\s*(auditable: false|redact: false|exit-on-error: true|max-group-size: 100MiB)\n)`)
 
 	const defaultFluentConfig = `fluent-defaults: {` +
+		`max-buffer-size: 1.0MiB, ` +
 		`filter: INFO, ` +
 		`format: json-fluent-compact, ` +
 		`redactable: true, ` +
 		`exit-on-error: false` +
 		`}, `
+	const defaultHTTPConfig = `http-defaults: {` +
+		`timeout: 2s, ` +
+		`unsafe-tls: false, ` +
+		`disable-keep-alives: false, ` +
+		`max-buffer-size: 1.0MiB, ` +
+		`filter: INFO, ` +
+		`format: json-compact, ` +
+		`redactable: true, ` +
+		`exit-on-error: false` +
+		`}, `
 	stdFileDefaultsRe := regexp.MustCompile(
 		`file-defaults: \{dir: (?P<path>[^,]+), max-file-size: 10MiB, filter: INFO, format: crdb-v1, redactable: true\}`)
 	fileDefaultsNoMaxSizeRe := regexp.MustCompile(
@@ -106,6 +117,7 @@ func TestSetupLogging(t *testing.T) {
 
 		// Shorten the configuration for legibility during reviews of test changes.
 		actual = strings.ReplaceAll(actual, defaultFluentConfig, "")
+		actual = strings.ReplaceAll(actual, defaultHTTPConfig, "")
 		actual = stdFileDefaultsRe.ReplaceAllString(actual, "<stdFileDefaults($path)>")
 		actual = fileDefaultsNoMaxSizeRe.ReplaceAllString(actual, "<fileDefaultsNoMaxSize($path)>")
 		actual = strings.ReplaceAll(actual, fileDefaultsNoDir, "<fileDefaultsNoDir>")
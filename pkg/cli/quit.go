@@ -252,3 +252,14 @@ func getAdminClient(ctx context.Context, cfg server.Config) (serverpb.AdminClien
 	}
 	return serverpb.NewAdminClient(conn), finish, nil
 }
+
+// GetStatusClient returns a StatusClient for the node given by the client
+// connection flags and a closure that must be invoked to free associated
+// resources. It is exported for use in package cliccl.
+func GetStatusClient(ctx context.Context) (serverpb.StatusClient, func(), error) {
+	conn, _, finish, err := getClientGRPCConn(ctx, serverCfg)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to connect to the node")
+	}
+	return serverpb.NewStatusClient(conn), finish, nil
+}
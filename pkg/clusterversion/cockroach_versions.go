@@ -206,6 +206,42 @@ const (
 	VirtualComputedColumns
 	// CPutInline is conditional put support for inline values.
 	CPutInline
+	// SCRAMAuthentication is when passwords can be stored as SCRAM-SHA-256
+	// credentials, and the scram-sha-256 authentication method is available.
+	SCRAMAuthentication
+	// MVCCRangeTombstones is when DeleteRange requests can delete keys by
+	// writing MVCC range tombstones.
+	MVCCRangeTombstones
+	// RangeStatsRespHasMaxQPS adds the RangeStatsResponse.MaxQueriesPerSecond
+	// field, which the merge queue uses to only merge ranges whose load has
+	// stayed low for a while.
+	RangeStatsRespHasMaxQPS
+
+	// UserDefinedFunctions is when function descriptors can be created with
+	// CREATE FUNCTION, and views can record the functions they call.
+	UserDefinedFunctions
+
+	// TableTriggers is when table descriptors can hold triggers, which nodes
+	// running older versions would silently not fire.
+	TableTriggers
+
+	// DeferrableConstraints is when foreign key and unique constraints can be
+	// declared DEFERRABLE, which nodes running older versions would check
+	// immediately.
+	DeferrableConstraints
+
+	// RowLevelSecurity is when row-level security policies can be created and
+	// enforced, which nodes running older versions would ignore.
+	RowLevelSecurity
+
+	// TrigramInvertedIndexes is when inverted indexes can be created with the
+	// gin_trgm_ops and gist_trgm_ops operator classes, whose keys nodes running
+	// older versions would not maintain.
+	TrigramInvertedIndexes
+
+	// ExportParquet is when EXPORT INTO PARQUET is supported, which plans
+	// ParquetWriter processors and may use the snappy FileCompression.
+	ExportParquet
 
 	// Step (1): Add new versions here.
 )
@@ -340,6 +376,42 @@ var versionsSingleton = keyedVersions([]keyedVersion{
 		Key:     CPutInline,
 		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 10},
 	},
+	{
+		Key:     SCRAMAuthentication,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 12},
+	},
+	{
+		Key:     MVCCRangeTombstones,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 14},
+	},
+	{
+		Key:     RangeStatsRespHasMaxQPS,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 16},
+	},
+	{
+		Key:     UserDefinedFunctions,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 18},
+	},
+	{
+		Key:     TableTriggers,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 20},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 22},
+	},
+	{
+		Key:     RowLevelSecurity,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 24},
+	},
+	{
+		Key:     TrigramInvertedIndexes,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 26},
+	},
+	{
+		Key:     ExportParquet,
+		Version: roachpb.Version{Major: 20, Minor: 2, Internal: 28},
+	},
 
 	// Step (2): Add new versions here.
 })
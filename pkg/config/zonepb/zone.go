@@ -395,6 +395,11 @@ func (z *ZoneConfig) InheritFromParent(parent *ZoneConfig) {
 			z.InheritedLeasePreferences = false
 		}
 	}
+	if z.LeasePlacement == nil {
+		if parent.LeasePlacement != nil {
+			z.LeasePlacement = parent.LeasePlacement.Enum()
+		}
+	}
 }
 
 // CopyFromZone copies over the specified fields from the other zone.
@@ -433,9 +438,22 @@ func (z *ZoneConfig) CopyFromZone(other ZoneConfig, fieldList []tree.Name) {
 			z.LeasePreferences = other.LeasePreferences
 			z.InheritedLeasePreferences = other.InheritedLeasePreferences
 		}
+		if fieldName == "lease_placement" {
+			z.LeasePlacement = nil
+			if other.LeasePlacement != nil {
+				z.LeasePlacement = other.LeasePlacement.Enum()
+			}
+		}
 	}
 }
 
+// FollowsWorkload returns whether the leases of the zone's ranges are placed
+// close to the locality their requests come from, regardless of lease counts.
+// A zone that doesn't set the lease placement uses the default placement.
+func (z *ZoneConfig) FollowsWorkload() bool {
+	return z.LeasePlacement != nil && *z.LeasePlacement == LeasePlacement_FOLLOW_WORKLOAD
+}
+
 // StoreSatisfiesConstraint checks whether a store satisfies the given constraint.
 // If the constraint is of the PROHIBITED type, satisfying it means the store
 // not matching the constraint's spec.
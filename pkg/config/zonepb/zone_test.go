@@ -656,6 +656,35 @@ lease_preferences: [[+duck=bar1, +duck=bar2], [-duck=foo]]
 	}
 }
 
+// TestLeasePlacementYAML verifies that the lease placement is marshaled by
+// name and inherited from the parent zone if not set.
+func TestLeasePlacementYAML(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	zone := ZoneConfig{LeasePlacement: LeasePlacement_FOLLOW_WORKLOAD.Enum()}
+	body, err := yaml.Marshal(zone)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "lease_placement: follow_workload\n")
+
+	var unmarshaled ZoneConfig
+	require.NoError(t, yaml.UnmarshalStrict(body, &unmarshaled))
+	require.True(t, unmarshaled.FollowsWorkload())
+
+	require.NoError(t, yaml.UnmarshalStrict([]byte("lease_placement: default"), &unmarshaled))
+	require.Equal(t, LeasePlacement_DEFAULT.Enum(), unmarshaled.LeasePlacement)
+	require.False(t, unmarshaled.FollowsWorkload())
+
+	err = yaml.UnmarshalStrict([]byte("lease_placement: nearest"), &unmarshaled)
+	require.True(t, testutils.IsError(err, `unknown lease placement "nearest"`), "%v", err)
+
+	child := ZoneConfig{}
+	child.InheritFromParent(&zone)
+	require.True(t, child.FollowsWorkload())
+	child.LeasePlacement = LeasePlacement_DEFAULT.Enum()
+	child.InheritFromParent(&zone)
+	require.False(t, child.FollowsWorkload())
+}
+
 // TestExperimentalLeasePreferencesYAML makes sure that we accept the
 // lease_preferences YAML field both with and without the "experimental_"
 // prefix.
@@ -49,6 +49,39 @@ func (l *LeasePreference) UnmarshalYAML(unmarshal func(interface{}) error) error
 	return nil
 }
 
+var _ yaml.Marshaler = LeasePlacement(0)
+var _ yaml.Unmarshaler = new(LeasePlacement)
+
+// MarshalYAML implements yaml.Marshaler.
+func (l LeasePlacement) MarshalYAML() (interface{}, error) {
+	return strings.ToLower(l.String()), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (l *LeasePlacement) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	v, ok := LeasePlacement_value[strings.ToUpper(s)]
+	if !ok {
+		return errors.Errorf("unknown lease placement %q, expected one of %s",
+			s, strings.Join(leasePlacementNames(), ", "))
+	}
+	*l = LeasePlacement(v)
+	return nil
+}
+
+// leasePlacementNames returns the names of the lease placements as they appear
+// in YAML.
+func leasePlacementNames() []string {
+	names := make([]string, 0, len(LeasePlacement_name))
+	for i := 0; i < len(LeasePlacement_name); i++ {
+		names = append(names, strings.ToLower(LeasePlacement_name[int32(i)]))
+	}
+	return names
+}
+
 var _ yaml.Marshaler = ConstraintsConjunction{}
 var _ yaml.Unmarshaler = &ConstraintsConjunction{}
 
@@ -206,6 +239,7 @@ type marshalableZoneConfig struct {
 	Constraints                  ConstraintsList   `json:"constraints" yaml:"constraints,flow"`
 	LeasePreferences             []LeasePreference `json:"lease_preferences" yaml:"lease_preferences,flow"`
 	ExperimentalLeasePreferences []LeasePreference `json:"experimental_lease_preferences" yaml:"experimental_lease_preferences,flow,omitempty"`
+	LeasePlacement               *LeasePlacement   `json:"lease_placement,omitempty" yaml:"lease_placement,omitempty"`
 	Subzones                     []Subzone         `json:"subzones" yaml:"-"`
 	SubzoneSpans                 []SubzoneSpan     `json:"subzone_spans" yaml:"-"`
 }
@@ -231,6 +265,9 @@ func zoneConfigToMarshalable(c ZoneConfig) marshalableZoneConfig {
 	}
 	// We intentionally do not round-trip ExperimentalLeasePreferences. We never
 	// want to return yaml containing it.
+	if c.LeasePlacement != nil {
+		m.LeasePlacement = c.LeasePlacement.Enum()
+	}
 	m.Subzones = c.Subzones
 	m.SubzoneSpans = c.SubzoneSpans
 	return m
@@ -271,6 +308,9 @@ func zoneConfigFromMarshalable(m marshalableZoneConfig, c ZoneConfig) ZoneConfig
 	if m.LeasePreferences != nil || m.ExperimentalLeasePreferences != nil {
 		c.InheritedLeasePreferences = false
 	}
+	if m.LeasePlacement != nil {
+		c.LeasePlacement = m.LeasePlacement.Enum()
+	}
 	c.Subzones = m.Subzones
 	c.SubzoneSpans = m.SubzoneSpans
 	return c
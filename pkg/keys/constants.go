@@ -52,9 +52,9 @@ var (
 	// key suffixes.
 	localSuffixLength = 4
 
-	// There are five types of local key data enumerated below: replicated
-	// range-ID, unreplicated range-ID, range local, range lock, and
-	// store-local keys.
+	// There are six types of local key data enumerated below: replicated
+	// range-ID, unreplicated range-ID, range local, range lock, MVCC range
+	// tombstone, and store-local keys.
 
 	// 1. Replicated Range-ID keys
 	//
@@ -117,6 +117,10 @@ var (
 	localRaftLastIndexSuffix = []byte("rfti")
 	// LocalRaftLogSuffix is the suffix for the raft log.
 	LocalRaftLogSuffix = []byte("rftl")
+	// LocalRaftSideloadedPayloadSuffix is the suffix for the payloads of
+	// sideloaded raft log entries, when these are stored in a dedicated Raft log
+	// engine rather than in files.
+	LocalRaftSideloadedPayloadSuffix = []byte("rfts")
 	// LocalRangeLastReplicaGCTimestampSuffix is the suffix for a range's last
 	// replica GC timestamp (for GC of old replicas).
 	LocalRangeLastReplicaGCTimestampSuffix = []byte("rlrt")
@@ -169,7 +173,19 @@ var (
 	LockTableSingleKeyEnd = roachpb.Key(
 		makeKey(LocalRangeLockTablePrefix, roachpb.Key(LockTableSingleKeyInfix).PrefixEnd()))
 
-	// 5. Store local keys
+	// 5. MVCC range tombstone keys
+	//
+	// LocalMVCCRangeTombstonePrefix specifies the key prefix for the
+	// fragments of MVCC range tombstones. It is followed by the start key of
+	// the fragment, and the timestamp of each tombstone covering the
+	// fragment is in the versioned part of the key. The value is the end key
+	// of the tombstone.
+	LocalMVCCRangeTombstonePrefix = roachpb.Key(makeKey(localPrefix, roachpb.RKey("m")))
+	// LocalMVCCRangeTombstoneMax is the exclusive end key of the MVCC range
+	// tombstone keyspace.
+	LocalMVCCRangeTombstoneMax = LocalMVCCRangeTombstonePrefix.PrefixEnd()
+
+	// 6. Store local keys
 	//
 	// localStorePrefix is the prefix identifying per-store data.
 	localStorePrefix = makeKey(localPrefix, roachpb.Key("s"))
@@ -192,6 +208,9 @@ var (
 	// localStoreNodeTombstoneSuffix stores key value pairs that map
 	// nodeIDs to time of removal from cluster.
 	localStoreNodeTombstoneSuffix = []byte("ntmb")
+	// localStoreRaftLogEngineSuffix marks a store whose Raft log has been moved
+	// into a dedicated Raft log engine.
+	localStoreRaftLogEngineSuffix = []byte("rlog")
 	// localStoreLastUpSuffix stores the last timestamp that a store's node
 	// acknowledged that it was still running. This value will be regularly
 	// refreshed on all stores for a running node; the intention of this value
@@ -156,11 +156,11 @@ package keys
 var _ = [...]interface{}{
 	MinKey,
 
-	// There are five types of local key data enumerated below: replicated
-	// range-ID, unreplicated range-ID, range local, range lock, and
-	// store-local keys.
+	// There are six types of local key data enumerated below: replicated
+	// range-ID, unreplicated range-ID, range local, range lock, MVCC range
+	// tombstone, and store-local keys.
 	// Local keys are constructed using a prefix, an optional infix, and a
-	// suffix. The prefix and infix are used to disambiguate between the four
+	// suffix. The prefix and infix are used to disambiguate between the six
 	// types of local keys listed above, and determines inter-group ordering.
 	// The string comment next to each symbol below is the suffix pertaining to
 	// the corresponding key (and determines intra-group ordering).
@@ -171,10 +171,12 @@ var _ = [...]interface{}{
 	// 	  - Range local keys all share `LocalRangePrefix`.
 	// 	  - Range lock (which are also local keys) all share
 	//	  `LocalRangeLockTablePrefix`.
+	//	  - MVCC range tombstone keys all share `LocalMVCCRangeTombstonePrefix`.
 	//	  - Store keys all share `localStorePrefix`.
 	//
 	// `LocalRangeIDPrefix`, `localRangePrefix`, `LocalRangeLockTablePrefix`,
-	// and `localStorePrefix` all in turn share `localPrefix`. `localPrefix` was
+	// `LocalMVCCRangeTombstonePrefix`, and `localStorePrefix` all in turn share
+	// `localPrefix`. `localPrefix` was
 	// chosen arbitrarily. Local keys would work just as well with a different
 	// prefix, like 0xff, or even with a suffix.
 
@@ -198,6 +200,7 @@ var _ = [...]interface{}{
 	RangeTombstoneKey,              // "rftb"
 	RaftHardStateKey,               // "rfth"
 	RaftLogKey,                     // "rftl"
+	RaftSideloadedPayloadKey,       // "rfts"
 	RaftTruncatedStateKey,          // "rftt"
 	RangeLastReplicaGCTimestampKey, // "rlrt"
 
@@ -219,7 +222,13 @@ var _ = [...]interface{}{
 	//   separate from (future) range locks.
 	LockTableSingleKey,
 
-	//   5. Store local keys: These contain metadata about an individual store.
+	//   5. MVCC range tombstone keys: These hold the fragments of the MVCC
+	//   range tombstones written over global keys. They are replicated and
+	//   addressable through the start key of the fragment, and they all share
+	//   `LocalMVCCRangeTombstonePrefix`.
+	MVCCRangeTombstoneKey,
+
+	//   6. Store local keys: These contain metadata about an individual store.
 	//   They are unreplicated and unaddressable. The typical example is the
 	//   store 'ident' record. They all share `localStorePrefix`.
 	StoreClusterVersionKey, // "cver"
@@ -227,6 +236,7 @@ var _ = [...]interface{}{
 	StoreHLCUpperBoundKey,  // "hlcu"
 	StoreIdentKey,          // "iden"
 	StoreNodeTombstoneKey,  // "ntmb"
+	StoreRaftLogEngineKey,  // "rlog"
 	StoreLastUpKey,         // "uptm"
 	StoreCachedSettingsKey, // "stng"
 
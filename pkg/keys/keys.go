@@ -76,6 +76,12 @@ func StoreHLCUpperBoundKey() roachpb.Key {
 	return MakeStoreKey(localStoreHLCUpperBoundSuffix, nil)
 }
 
+// StoreRaftLogEngineKey returns the store-local key marking a store whose Raft
+// log lives in a dedicated Raft log engine.
+func StoreRaftLogEngineKey() roachpb.Key {
+	return MakeStoreKey(localStoreRaftLogEngineSuffix, nil)
+}
+
 // StoreNodeTombstoneKey returns the key for storing a node tombstone for nodeID.
 func StoreNodeTombstoneKey(nodeID roachpb.NodeID) roachpb.Key {
 	return MakeStoreKey(localStoreNodeTombstoneSuffix, encoding.EncodeUint32Ascending(nil, uint32(nodeID)))
@@ -333,6 +339,18 @@ func RaftLogKey(rangeID roachpb.RangeID, logIndex uint64) roachpb.Key {
 	return MakeRangeIDPrefixBuf(rangeID).RaftLogKey(logIndex)
 }
 
+// RaftSideloadedPayloadPrefix returns the system-local prefix shared by all
+// sideloaded payloads of a Raft log.
+func RaftSideloadedPayloadPrefix(rangeID roachpb.RangeID) roachpb.Key {
+	return MakeRangeIDPrefixBuf(rangeID).RaftSideloadedPayloadPrefix()
+}
+
+// RaftSideloadedPayloadKey returns a system-local key for the sideloaded
+// payload of the Raft log entry at the given index and term.
+func RaftSideloadedPayloadKey(rangeID roachpb.RangeID, logIndex, term uint64) roachpb.Key {
+	return MakeRangeIDPrefixBuf(rangeID).RaftSideloadedPayloadKey(logIndex, term)
+}
+
 // RangeLastReplicaGCTimestampKey returns a range-local key for
 // the range's last replica GC timestamp.
 func RangeLastReplicaGCTimestampKey(rangeID roachpb.RangeID) roachpb.Key {
@@ -463,6 +481,33 @@ func DecodeLockTableSingleKey(key roachpb.Key) (lockedKey roachpb.Key, err error
 	return lockedKey, err
 }
 
+// MVCCRangeTombstoneKey returns the key under which the fragment of the MVCC
+// range tombstones starting at the given key is stored.
+func MVCCRangeTombstoneKey(key roachpb.Key) roachpb.Key {
+	buf := make(roachpb.Key, 0, len(LocalMVCCRangeTombstonePrefix)+len(key)+3)
+	buf = append(buf, LocalMVCCRangeTombstonePrefix...)
+	return encoding.EncodeBytesAscending(buf, key)
+}
+
+// DecodeMVCCRangeTombstoneKey decodes the start key of the MVCC range
+// tombstone fragment stored under the given key.
+func DecodeMVCCRangeTombstoneKey(key roachpb.Key) (startKey roachpb.Key, err error) {
+	if !bytes.HasPrefix(key, LocalMVCCRangeTombstonePrefix) {
+		return nil, errors.Errorf("key %q does not have %q prefix",
+			key, LocalMVCCRangeTombstonePrefix)
+	}
+	b := key[len(LocalMVCCRangeTombstonePrefix):]
+	b, startKey, err = encoding.DecodeBytesAscending(b, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != 0 {
+		return nil, errors.Errorf("key %q has left-over bytes %d after decoding",
+			key, len(b))
+	}
+	return startKey, nil
+}
+
 // IsLocal performs a cheap check that returns true iff a range-local key is
 // passed, that is, a key for which `Addr` would return a non-identical RKey
 // (or a decoding error).
@@ -496,7 +541,8 @@ func IsLocalStoreKey(k roachpb.Key) bool {
 //
 // However, not all local keys are addressable in the global map. Only range
 // local keys incorporating a range key (start key or transaction key) are
-// addressable (e.g. range metadata and txn records). Range local keys
+// addressable (e.g. range metadata and txn records), as are the fragments of
+// MVCC range tombstones, which address to their start key. Range local keys
 // incorporating the Range ID are not (e.g. AbortSpan Entries, and range
 // stats).
 //
@@ -513,15 +559,21 @@ func Addr(k roachpb.Key) (roachpb.RKey, error) {
 		if bytes.HasPrefix(k, LocalRangeIDPrefix) {
 			return nil, errors.Errorf("local range ID key %q is not addressable", k)
 		}
-		if !bytes.HasPrefix(k, LocalRangePrefix) {
+		var err error
+		if bytes.HasPrefix(k, LocalMVCCRangeTombstonePrefix) {
+			// The fragments of MVCC range tombstones address to their start key.
+			if k, err = DecodeMVCCRangeTombstoneKey(k); err != nil {
+				return nil, err
+			}
+		} else if !bytes.HasPrefix(k, LocalRangePrefix) {
 			return nil, errors.Errorf("local key %q malformed; should contain prefix %q",
 				k, LocalRangePrefix)
-		}
-		k = k[len(LocalRangePrefix):]
-		var err error
-		// Decode the encoded key, throw away the suffix and detail.
-		if _, k, err = encoding.DecodeBytesAscending(k, nil); err != nil {
-			return nil, err
+		} else {
+			k = k[len(LocalRangePrefix):]
+			// Decode the encoded key, throw away the suffix and detail.
+			if _, k, err = encoding.DecodeBytesAscending(k, nil); err != nil {
+				return nil, err
+			}
 		}
 		if !IsLocal(k) {
 			break
@@ -990,6 +1042,19 @@ func (b RangeIDPrefixBuf) RaftLogKey(logIndex uint64) roachpb.Key {
 	return encoding.EncodeUint64Ascending(b.RaftLogPrefix(), logIndex)
 }
 
+// RaftSideloadedPayloadPrefix returns the system-local prefix shared by all
+// sideloaded payloads of a Raft log.
+func (b RangeIDPrefixBuf) RaftSideloadedPayloadPrefix() roachpb.Key {
+	return append(b.unreplicatedPrefix(), LocalRaftSideloadedPayloadSuffix...)
+}
+
+// RaftSideloadedPayloadKey returns a system-local key for the sideloaded
+// payload of the Raft log entry at the given index and term.
+func (b RangeIDPrefixBuf) RaftSideloadedPayloadKey(logIndex, term uint64) roachpb.Key {
+	key := encoding.EncodeUint64Ascending(b.RaftSideloadedPayloadPrefix(), logIndex)
+	return encoding.EncodeUint64Ascending(key, term)
+}
+
 // RangeLastReplicaGCTimestampKey returns a range-local key for
 // the range's last replica GC timestamp.
 func (b RangeIDPrefixBuf) RangeLastReplicaGCTimestampKey() roachpb.Key {
@@ -36,6 +36,7 @@ func TestStoreKeyEncodeDecode(t *testing.T) {
 		{key: StoreClusterVersionKey(), expSuffix: localStoreClusterVersionSuffix, expDetail: nil},
 		{key: StoreLastUpKey(), expSuffix: localStoreLastUpSuffix, expDetail: nil},
 		{key: StoreHLCUpperBoundKey(), expSuffix: localStoreHLCUpperBoundSuffix, expDetail: nil},
+		{key: StoreRaftLogEngineKey(), expSuffix: localStoreRaftLogEngineSuffix, expDetail: nil},
 	}
 	for _, test := range testCases {
 		t.Run("", func(t *testing.T) {
@@ -110,6 +111,8 @@ func TestKeyAddress(t *testing.T) {
 		{TransactionKey(roachpb.Key("baz"), uuid.MakeV4()), roachpb.RKey("baz")},
 		{TransactionKey(roachpb.KeyMax, uuid.MakeV4()), roachpb.RKeyMax},
 		{RangeDescriptorKey(roachpb.RKey(TransactionKey(roachpb.Key("doubleBaz"), uuid.MakeV4()))), roachpb.RKey("doubleBaz")},
+		{MVCCRangeTombstoneKey(roachpb.Key("qux")), roachpb.RKey("qux")},
+		{MVCCRangeTombstoneKey(roachpb.Key{}), roachpb.RKeyMin},
 		{nil, nil},
 	}
 	for i, test := range testCases {
@@ -137,6 +140,7 @@ func TestKeyAddressError(t *testing.T) {
 			RaftHardStateKey(0),
 			RaftLogPrefix(0),
 			RaftLogKey(0, 0),
+			RaftSideloadedPayloadKey(0, 0, 0),
 			RangeLastReplicaGCTimestampKey(0),
 		},
 		"local key .* malformed": {
@@ -734,3 +738,25 @@ func TestLockTableKeyEncodeDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestMVCCRangeTombstoneKeyEncodeDecode(t *testing.T) {
+	testCases := []roachpb.Key{
+		roachpb.Key("foo"),
+		roachpb.Key("a\x00b"),
+		roachpb.Key(""),
+	}
+	for _, key := range testCases {
+		t.Run("", func(t *testing.T) {
+			tombKey := MVCCRangeTombstoneKey(key)
+			require.True(t, bytes.HasPrefix(tombKey, LocalMVCCRangeTombstonePrefix))
+			k, err := DecodeMVCCRangeTombstoneKey(tombKey)
+			require.NoError(t, err)
+			require.Equal(t, key, k)
+		})
+	}
+	// The encoding preserves the order of the start keys.
+	require.True(t, MVCCRangeTombstoneKey(roachpb.Key("a")).Compare(
+		MVCCRangeTombstoneKey(roachpb.Key("a").Next())) < 0)
+	require.True(t, MVCCRangeTombstoneKey(roachpb.Key("a").Next()).Compare(
+		MVCCRangeTombstoneKey(roachpb.Key("b"))) < 0)
+}
@@ -79,6 +79,8 @@ var (
 				PSFunc: parseUnsupported},
 			{Name: "/Lock", prefix: LocalRangeLockTablePrefix, ppFunc: localRangeLockTablePrint,
 				PSFunc: parseUnsupported},
+			{Name: "/RangeTombstone", prefix: LocalMVCCRangeTombstonePrefix,
+				ppFunc: localMVCCRangeTombstonePrint, PSFunc: parseUnsupported},
 		}},
 		{Name: "/Meta1", start: Meta1Prefix, end: Meta1KeyMax, Entries: []DictEntry{
 			{Name: "", prefix: Meta1Prefix, ppFunc: print,
@@ -166,6 +168,9 @@ var (
 			ppFunc: raftLogKeyPrint,
 			psFunc: raftLogKeyParse,
 		},
+		{name: "RaftSideloadedPayload", suffix: LocalRaftSideloadedPayloadSuffix,
+			ppFunc: raftSideloadedPayloadKeyPrint,
+		},
 		{name: "RaftTruncatedState", suffix: LocalRaftTruncatedStateLegacySuffix},
 		{name: "RangeLastReplicaGCTimestamp", suffix: LocalRangeLastReplicaGCTimestampSuffix},
 		{name: "RangeLease", suffix: LocalRangeLeaseSuffix},
@@ -194,6 +199,7 @@ var constSubKeyDict = []struct {
 	{"/nodeTombstone", localStoreNodeTombstoneSuffix},
 	{"/suggestedCompaction", localStoreSuggestedCompactionSuffix},
 	{"/cachedSettings", localStoreCachedSettingsSuffix},
+	{"/raftLogEngine", localStoreRaftLogEngineSuffix},
 }
 
 func nodeTombstoneKeyPrint(key roachpb.Key) string {
@@ -355,6 +361,18 @@ func raftLogKeyPrint(key roachpb.Key) string {
 	return fmt.Sprintf("%s%d", strLogIndex, logIndex)
 }
 
+func raftSideloadedPayloadKeyPrint(key roachpb.Key) string {
+	key, logIndex, err := encoding.DecodeUint64Ascending(key)
+	if err != nil {
+		return fmt.Sprintf("/err<%v:%q>", err, []byte(key))
+	}
+	key, term, err := encoding.DecodeUint64Ascending(key)
+	if err != nil {
+		return fmt.Sprintf("/err<%v:%q>", err, []byte(key))
+	}
+	return fmt.Sprintf("%s%d/term:%d", strLogIndex, logIndex, term)
+}
+
 func mustShiftSlash(in string) string {
 	slash, out := mustShift(in)
 	if slash != "/" {
@@ -546,6 +564,14 @@ func localRangeLockTablePrint(valDirs []encoding.Direction, key roachpb.Key) str
 	return buf.String()
 }
 
+func localMVCCRangeTombstonePrint(valDirs []encoding.Direction, key roachpb.Key) string {
+	b, startKey, err := encoding.DecodeBytesAscending(key, nil)
+	if err != nil || len(b) != 0 {
+		return fmt.Sprintf("/\"%x\"", key)
+	}
+	return lockTablePrintLockedKey(valDirs, startKey, true)
+}
+
 // ErrUglifyUnsupported is returned when UglyPrint doesn't know how to process a
 // key.
 type ErrUglifyUnsupported struct {
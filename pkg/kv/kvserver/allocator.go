@@ -91,6 +91,19 @@ var leaseRebalancingAggressiveness = settings.RegisterFloatSetting(
 	settings.NonNegativeFloat,
 )
 
+// followWorkloadLeaseTransferThreshold is the hysteresis applied to lease
+// transfers of ranges whose zone has the FOLLOW_WORKLOAD lease placement. The
+// lease is only moved to another replica if the request rate from localities
+// near that replica exceeds the rate near the leaseholder by this fraction,
+// which prevents leases from bouncing between replicas that see similar load.
+var followWorkloadLeaseTransferThreshold = settings.RegisterFloatSetting(
+	"kv.allocator.follow_workload.lease_transfer_threshold",
+	"minimum fraction by which the request rate near a replica must exceed the rate near "+
+		"the leaseholder before the lease of a range that follows the workload is moved to it",
+	0.5,
+	settings.NonNegativeFloat,
+)
+
 // AllocatorAction enumerates the various replication adjustments that may be
 // recommended by the allocator.
 type AllocatorAction int
@@ -867,13 +880,21 @@ func (a *Allocator) TransferLeaseTarget(
 	// Try to pick a replica to transfer the lease to while also determining
 	// whether we actually should be transferring the lease. The transfer
 	// decision is only needed if we've been asked to check the source.
-	transferDec, repl := a.shouldTransferLeaseUsingStats(
-		ctx, sl, source, existing, stats, nil,
-	)
+	var transferDec transferDecision
+	var repl roachpb.ReplicaDescriptor
+	if zone.FollowsWorkload() {
+		transferDec, repl = a.shouldTransferLeaseFollowingWorkload(ctx, source, existing, stats)
+	} else {
+		transferDec, repl = a.shouldTransferLeaseUsingStats(
+			ctx, sl, source, existing, stats, nil,
+		)
+	}
 	if checkTransferLeaseSource {
 		switch transferDec {
 		case shouldNotTransfer:
-			if !alwaysAllowDecisionWithoutStats {
+			// Balancing lease counts would fight with the workload, so never
+			// fall back to it for ranges that follow the workload.
+			if !alwaysAllowDecisionWithoutStats || zone.FollowsWorkload() {
 				return roachpb.ReplicaDescriptor{}
 			}
 			fallthrough
@@ -967,7 +988,12 @@ func (a *Allocator) ShouldTransferLease(
 		return false
 	}
 
-	transferDec, _ := a.shouldTransferLeaseUsingStats(ctx, sl, source, existing, stats, nil)
+	var transferDec transferDecision
+	if zone.FollowsWorkload() {
+		transferDec, _ = a.shouldTransferLeaseFollowingWorkload(ctx, source, existing, stats)
+	} else {
+		transferDec, _ = a.shouldTransferLeaseUsingStats(ctx, sl, source, existing, stats, nil)
+	}
 	var result bool
 	switch transferDec {
 	case shouldNotTransfer:
@@ -986,12 +1012,30 @@ func (a *Allocator) ShouldTransferLease(
 
 func (a Allocator) followTheWorkloadPrefersLocal(
 	ctx context.Context,
+	zone *zonepb.ZoneConfig,
 	sl StoreList,
 	source roachpb.StoreDescriptor,
 	candidate roachpb.StoreID,
 	existing []roachpb.ReplicaDescriptor,
 	stats *replicaStats,
 ) bool {
+	if zone.FollowsWorkload() {
+		// Don't move the lease any farther from the workload than it is.
+		weights, _ := a.replicaRequestWeights(ctx, existing, stats)
+		if weights == nil {
+			return false
+		}
+		for _, repl := range existing {
+			if repl.StoreID == candidate && weights[repl.NodeID] < weights[source.Node.NodeID] {
+				log.VEventf(ctx, 3,
+					"s%d is a better fit than s%d due to the follow_workload lease placement "+
+						"(weight: %.2f; candidate weight: %.2f)",
+					source.StoreID, candidate, weights[source.Node.NodeID], weights[repl.NodeID])
+				return true
+			}
+		}
+		return false
+	}
 	adjustments := make(map[roachpb.StoreID]float64)
 	decision, _ := a.shouldTransferLeaseUsingStats(ctx, sl, source, existing, stats, adjustments)
 	if decision == decideWithoutStats {
@@ -1007,23 +1051,23 @@ func (a Allocator) followTheWorkloadPrefersLocal(
 	return false
 }
 
-func (a Allocator) shouldTransferLeaseUsingStats(
-	ctx context.Context,
-	sl StoreList,
-	source roachpb.StoreDescriptor,
-	existing []roachpb.ReplicaDescriptor,
-	stats *replicaStats,
-	rebalanceAdjustments map[roachpb.StoreID]float64,
-) (transferDecision, roachpb.ReplicaDescriptor) {
+// replicaRequestWeights assigns a weight to the node of each of the existing
+// replicas, based on the number of requests for the range that have been
+// coming from that node's locality and neighboring localities. If the weights
+// can't be determined, it returns a nil map along with the transfer decision
+// to use instead.
+func (a Allocator) replicaRequestWeights(
+	ctx context.Context, existing []roachpb.ReplicaDescriptor, stats *replicaStats,
+) (map[roachpb.NodeID]float64, transferDecision) {
 	// Only use load-based rebalancing if it's enabled and we have both
 	// stats and locality information to base our decision on.
 	if stats == nil || !enableLoadBasedLeaseRebalancing.Get(&a.storePool.st.SV) {
-		return decideWithoutStats, roachpb.ReplicaDescriptor{}
+		return nil, decideWithoutStats
 	}
 	replicaLocalities := a.storePool.getLocalitiesByNode(existing)
 	for _, locality := range replicaLocalities {
 		if len(locality.Tiers) == 0 {
-			return decideWithoutStats, roachpb.ReplicaDescriptor{}
+			return nil, decideWithoutStats
 		}
 	}
 
@@ -1036,14 +1080,14 @@ func (a Allocator) shouldTransferLeaseUsingStats(
 	// thrashing, since leases cannot transfer more frequently than this threshold
 	// (because replica stats get reset upon lease transfer).
 	if qpsStatsDur < MinLeaseTransferStatsDuration {
-		return shouldNotTransfer, roachpb.ReplicaDescriptor{}
+		return nil, shouldNotTransfer
 	}
 
 	// On the other hand, if we don't have any stats with associated localities,
 	// then do fall back to the algorithm that doesn't use request stats.
 	delete(qpsStats, "")
 	if len(qpsStats) == 0 {
-		return decideWithoutStats, roachpb.ReplicaDescriptor{}
+		return nil, decideWithoutStats
 	}
 
 	replicaWeights := make(map[roachpb.NodeID]float64)
@@ -1063,6 +1107,21 @@ func (a Allocator) shouldTransferLeaseUsingStats(
 	log.VEventf(ctx, 1,
 		"shouldTransferLease qpsStats: %+v, replicaLocalities: %+v, replicaWeights: %+v",
 		qpsStats, replicaLocalities, replicaWeights)
+	return replicaWeights, 0
+}
+
+func (a Allocator) shouldTransferLeaseUsingStats(
+	ctx context.Context,
+	sl StoreList,
+	source roachpb.StoreDescriptor,
+	existing []roachpb.ReplicaDescriptor,
+	stats *replicaStats,
+	rebalanceAdjustments map[roachpb.StoreID]float64,
+) (transferDecision, roachpb.ReplicaDescriptor) {
+	replicaWeights, decision := a.replicaRequestWeights(ctx, existing, stats)
+	if replicaWeights == nil {
+		return decision, roachpb.ReplicaDescriptor{}
+	}
 	sourceWeight := math.Max(minReplicaWeight, replicaWeights[source.Node.NodeID])
 
 	// TODO(a-robinson): This may not have enough protection against all leases
@@ -1110,6 +1169,45 @@ func (a Allocator) shouldTransferLeaseUsingStats(
 	return shouldNotTransfer, bestRepl
 }
 
+// shouldTransferLeaseFollowingWorkload is the counterpart of
+// shouldTransferLeaseUsingStats for ranges whose zone has the FOLLOW_WORKLOAD
+// lease placement. It picks the replica closest to the localities the range's
+// requests come from, ignoring lease counts and latencies. The lease is only
+// transferred if that replica's weight exceeds the leaseholder's by
+// kv.allocator.follow_workload.lease_transfer_threshold.
+func (a Allocator) shouldTransferLeaseFollowingWorkload(
+	ctx context.Context,
+	source roachpb.StoreDescriptor,
+	existing []roachpb.ReplicaDescriptor,
+	stats *replicaStats,
+) (transferDecision, roachpb.ReplicaDescriptor) {
+	replicaWeights, decision := a.replicaRequestWeights(ctx, existing, stats)
+	if replicaWeights == nil {
+		return decision, roachpb.ReplicaDescriptor{}
+	}
+	sourceWeight := replicaWeights[source.Node.NodeID]
+
+	var bestRepl roachpb.ReplicaDescriptor
+	bestWeight := -1.0
+	for _, repl := range existing {
+		if repl.NodeID == source.Node.NodeID {
+			continue
+		}
+		if weight := replicaWeights[repl.NodeID]; weight > bestWeight {
+			bestRepl, bestWeight = repl, weight
+		}
+	}
+
+	threshold := followWorkloadLeaseTransferThreshold.Get(&a.storePool.st.SV)
+	log.VEventf(ctx, 1,
+		"follow_workload: source s%d weight: %.2f, best s%d weight: %.2f, threshold: %.2f",
+		source.StoreID, sourceWeight, bestRepl.StoreID, bestWeight, threshold)
+	if bestWeight > math.Max(minReplicaWeight, sourceWeight)*(1+threshold) {
+		return shouldTransfer, bestRepl
+	}
+	return shouldNotTransfer, bestRepl
+}
+
 // loadBasedLeaseRebalanceScore attempts to give a score to how desirable it
 // would be to transfer a range lease from the local store to a remote store.
 // It does so using a formula based on the latency between the stores and
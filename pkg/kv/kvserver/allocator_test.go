@@ -4029,6 +4029,126 @@ func TestAllocatorTransferLeaseTargetLoadBased(t *testing.T) {
 	}
 }
 
+// TestAllocatorTransferLeaseTargetFollowWorkload verifies that the leases of
+// ranges with the FOLLOW_WORKLOAD lease placement move toward the locality
+// their requests come from regardless of lease counts, but only once the
+// difference in load exceeds the transfer threshold.
+func TestAllocatorTransferLeaseTargetFollowWorkload(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	stopper, g, _, storePool, _ := createTestStorePool(
+		TestTimeUntilStoreDeadOff, true, /* deterministic */
+		func() int { return 10 }, /* nodeCount */
+		livenesspb.NodeLivenessStatus_LIVE)
+	defer stopper.Stop(context.Background())
+
+	// 3 stores where the lease count for each store is equal to 10x the store
+	// ID, so that balancing lease counts would never move leases to s3.
+	var stores []*roachpb.StoreDescriptor
+	for i := 1; i <= 3; i++ {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node: roachpb.NodeDescriptor{
+				NodeID:  roachpb.NodeID(i),
+				Address: util.MakeUnresolvedAddr("tcp", strconv.Itoa(i)),
+				Locality: roachpb.Locality{
+					Tiers: []roachpb.Tier{
+						{Key: "l", Value: strconv.Itoa(i)},
+					},
+				},
+			},
+			Capacity: roachpb.StoreCapacity{LeaseCount: int32(10 * i)},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+	for _, store := range stores {
+		if err := g.SetNodeDescriptor(&store.Node); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	localityFn := func(nodeID roachpb.NodeID) string {
+		return fmt.Sprintf("l=%d", nodeID)
+	}
+	manual := hlc.NewManualClock(123)
+	clock := hlc.NewClock(manual.UnixNano, time.Nanosecond)
+
+	// In mostlyFrom3, l=3 sends twice as many requests as l=1. In
+	// slightlyFrom3, it sends 25% more, which is below the default threshold.
+	mostlyFrom3 := newReplicaStats(clock, localityFn)
+	slightlyFrom3 := newReplicaStats(clock, localityFn)
+	for i := 0; i < 100*int(MinLeaseTransferStatsDuration.Seconds()); i++ {
+		mostlyFrom3.record(3)
+		slightlyFrom3.record(3)
+		if i%2 == 0 {
+			mostlyFrom3.record(1)
+		}
+		if i%5 != 0 {
+			slightlyFrom3.record(1)
+		}
+	}
+	manual.Increment(int64(MinLeaseTransferStatsDuration))
+
+	existing := []roachpb.ReplicaDescriptor{
+		{NodeID: 1, StoreID: 1},
+		{NodeID: 2, StoreID: 2},
+		{NodeID: 3, StoreID: 3},
+	}
+	zone := zonepb.EmptyCompleteZoneConfig()
+	zone.LeasePlacement = zonepb.LeasePlacement_FOLLOW_WORKLOAD.Enum()
+
+	testCases := []struct {
+		leaseholder roachpb.StoreID
+		stats       *replicaStats
+		expected    roachpb.StoreID
+	}{
+		{leaseholder: 1, stats: mostlyFrom3, expected: 3},
+		{leaseholder: 2, stats: mostlyFrom3, expected: 3},
+		{leaseholder: 3, stats: mostlyFrom3, expected: 0},
+		{leaseholder: 1, stats: slightlyFrom3, expected: 0},
+		{leaseholder: 2, stats: slightlyFrom3, expected: 3},
+		{leaseholder: 3, stats: slightlyFrom3, expected: 0},
+	}
+
+	a := MakeAllocator(storePool, func(string) (time.Duration, bool) {
+		return 0, true
+	})
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			target := a.TransferLeaseTarget(
+				context.Background(),
+				zone,
+				existing,
+				c.leaseholder,
+				c.stats,
+				true, /* checkTransferLeaseSource */
+				true, /* checkCandidateFullness */
+				true, /* alwaysAllowDecisionWithoutStats */
+			)
+			require.Equal(t, c.expected, target.StoreID)
+			require.Equal(t, c.expected != 0, a.ShouldTransferLease(
+				context.Background(), zone, existing, c.leaseholder, c.stats,
+			))
+		})
+	}
+
+	// Without the FOLLOW_WORKLOAD lease placement, s3's lease count keeps the
+	// lease from moving there.
+	target := a.TransferLeaseTarget(
+		context.Background(),
+		zonepb.EmptyCompleteZoneConfig(),
+		existing,
+		1, /* leaseStoreID */
+		mostlyFrom3,
+		true,  /* checkTransferLeaseSource */
+		true,  /* checkCandidateFullness */
+		false, /* alwaysAllowDecisionWithoutStats */
+	)
+	require.Equal(t, roachpb.StoreID(0), target.StoreID)
+}
+
 func TestLoadBasedLeaseRebalanceScore(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
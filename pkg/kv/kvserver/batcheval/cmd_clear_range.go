@@ -45,6 +45,7 @@ func declareKeysClearRange(
 	// We look up the range descriptor key to check whether the span
 	// is equal to the entire range for fast stats updating.
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
+	DeclareRangeTombstoneKeys(spanset.SpanReadWrite, desc, latchSpans)
 }
 
 // ClearRange wipes all MVCC versions of keys covered by the specified
@@ -81,6 +82,12 @@ func ClearRange(
 	}
 	cArgs.Stats.Subtract(statsDelta)
 
+	// The range tombstones in the span are cleared as well. Their fragments
+	// are range-local, so they're accounted for separately.
+	if err := storage.MVCCClearRangeTombstones(readWriter, cArgs.Stats, from, to); err != nil {
+		return result.Result{}, err
+	}
+
 	// If the total size of data to be cleared is less than
 	// clearRangeBytesThreshold, clear the individual values manually,
 	// instead of using a range tombstone (inefficient for small ranges).
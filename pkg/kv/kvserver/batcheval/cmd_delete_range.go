@@ -13,11 +13,13 @@ package batcheval
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -36,6 +38,9 @@ func declareKeysDeleteRange(
 	} else {
 		DefaultDeclareIsolatedKeys(desc, header, req, latchSpans, lockSpans)
 	}
+	if args.UseRangeTombstone {
+		DeclareRangeTombstoneKeys(spanset.SpanReadWrite, desc, latchSpans)
+	}
 }
 
 // DeleteRange deletes the range of key/value pairs specified by
@@ -47,6 +52,23 @@ func DeleteRange(
 	h := cArgs.Header
 	reply := resp.(*roachpb.DeleteRangeResponse)
 
+	if args.UseRangeTombstone {
+		if !cArgs.EvalCtx.ClusterSettings().Version.IsActive(ctx, clusterversion.MVCCRangeTombstones) {
+			return result.Result{}, errors.Errorf(
+				"range tombstones require the cluster version %s", clusterversion.MVCCRangeTombstones)
+		}
+		if h.Txn != nil {
+			return result.Result{}, errors.AssertionFailedf(
+				"range tombstones cannot be written transactionally")
+		}
+		if args.Inline || args.ReturnKeys {
+			return result.Result{}, errors.AssertionFailedf(
+				"range tombstones cannot be written with Inline or ReturnKeys")
+		}
+		return result.Result{}, storage.MVCCDeleteRangeUsingTombstone(
+			ctx, readWriter, cArgs.Stats, args.Key, args.EndKey, h.Timestamp)
+	}
+
 	var timestamp hlc.Timestamp
 	if !args.Inline {
 		timestamp = h.Timestamp
@@ -0,0 +1,97 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package batcheval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDeleteRangeUsingTombstone verifies that DeleteRange writes an MVCC
+// range tombstone when UseRangeTombstone is set, and that the stats are
+// updated accordingly.
+func TestDeleteRangeUsingTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+
+	ts1 := hlc.Timestamp{WallTime: 1}
+	ts2 := hlc.Timestamp{WallTime: 2}
+	ts3 := hlc.Timestamp{WallTime: 3}
+	desc := roachpb.RangeDescriptor{
+		RangeID:  99,
+		StartKey: roachpb.RKey("a"),
+		EndKey:   roachpb.RKey("z"),
+	}
+
+	var ms enginepb.MVCCStats
+	for _, key := range []string{"b", "c", "d"} {
+		require.NoError(t, storage.MVCCPut(ctx, eng, &ms, roachpb.Key(key), ts1,
+			roachpb.MakeValueFromString(key), nil))
+	}
+
+	evalCtx := (&MockEvalCtx{
+		ClusterSettings: cluster.MakeTestingClusterSettings(),
+		Desc:            &desc,
+	}).EvalContext()
+	deleteRange := func(rw storage.ReadWriter, h roachpb.Header) error {
+		_, err := DeleteRange(ctx, rw, CommandArgs{
+			EvalCtx: evalCtx,
+			Header:  h,
+			Args: &roachpb.DeleteRangeRequest{
+				RequestHeader: roachpb.RequestHeader{
+					Key:    roachpb.Key("c"),
+					EndKey: roachpb.Key("z"),
+				},
+				UseRangeTombstone: true,
+			},
+			Stats: &ms,
+		}, &roachpb.DeleteRangeResponse{})
+		return err
+	}
+
+	// Range tombstones can't be written transactionally.
+	txn := roachpb.MakeTransaction("test", roachpb.Key("c"), roachpb.NormalUserPriority, ts2, 0)
+	err := deleteRange(eng, roachpb.Header{Timestamp: ts2, Txn: &txn})
+	require.True(t, testutils.IsError(err, "cannot be written transactionally"), "%v", err)
+
+	require.NoError(t, deleteRange(eng, roachpb.Header{Timestamp: ts2}))
+
+	// The deleted keys are visible below the range tombstone.
+	for _, key := range []string{"b", "c", "d"} {
+		value, _, err := storage.MVCCGet(ctx, eng, roachpb.Key(key), ts3, storage.MVCCGetOptions{})
+		require.NoError(t, err)
+		require.Equal(t, key == "b", value != nil, "key %s", key)
+		value, _, err = storage.MVCCGet(ctx, eng, roachpb.Key(key), ts1, storage.MVCCGetOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, value, "key %s", key)
+	}
+
+	iter := eng.NewMVCCIterator(storage.MVCCKeyAndIntentsIterKind, storage.IterOptions{UpperBound: roachpb.KeyMax})
+	defer iter.Close()
+	expMS, err := storage.ComputeStatsForRange(iter, roachpb.KeyMin, roachpb.KeyMax, ts2.WallTime)
+	require.NoError(t, err)
+	ms.AgeTo(ts2.WallTime)
+	require.Equal(t, expMS, ms)
+}
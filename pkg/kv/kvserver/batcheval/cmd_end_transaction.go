@@ -148,6 +148,9 @@ func declareKeysEndTxn(
 					Key:    abortspan.MinKey(header.RangeID),
 					EndKey: abortspan.MaxKey(header.RangeID),
 				})
+
+				// Splits truncate the range tombstones at the split key.
+				DeclareRangeTombstoneKeys(spanset.SpanReadWrite, desc, latchSpans)
 			}
 			if mt := et.InternalCommitTrigger.MergeTrigger; mt != nil {
 				// Merges copy over the RHS abort span to the LHS, and compute
@@ -817,6 +820,14 @@ func splitTrigger(
 			split.RightDesc.StartKey, split.RightDesc.EndKey, desc)
 	}
 
+	// The range tombstones straddling the split key are split, since their
+	// fragments must not extend beyond the bounds of the LHS.
+	if err := storage.MVCCSplitRangeTombstones(
+		batch, &bothDeltaMS, split.RightDesc.StartKey.AsRawKey(),
+	); err != nil {
+		return enginepb.MVCCStats{}, result.Result{}, errors.Wrap(err, "unable to split range tombstones")
+	}
+
 	// Compute the absolute stats for the (post-split) LHS. No more
 	// modifications to it are allowed after this line.
 
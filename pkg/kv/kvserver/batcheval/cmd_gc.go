@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -41,6 +42,18 @@ func declareKeysGC(
 			latchSpans.AddMVCC(spanset.SpanReadWrite, roachpb.Span{Key: key.Key}, header.Timestamp)
 		}
 	}
+	// Garbage collecting a range tombstone clears all the versions it shadows,
+	// which is not an MVCC operation. The keys are also checked for range
+	// tombstones covering them.
+	for _, rangeKey := range gcr.RangeKeys {
+		latchSpans.AddNonMVCC(spanset.SpanReadWrite,
+			roachpb.Span{Key: rangeKey.StartKey, EndKey: rangeKey.EndKey})
+	}
+	if len(gcr.RangeKeys) > 0 {
+		DeclareRangeTombstoneKeys(spanset.SpanReadWrite, desc, latchSpans)
+	} else if len(gcr.Keys) > 0 {
+		DeclareRangeTombstoneKeys(spanset.SpanReadOnly, desc, latchSpans)
+	}
 	// Be smart here about blocking on the threshold keys. The GC queue can send an empty
 	// request first to bump the thresholds, and then another one that actually does work
 	// but can avoid declaring these keys below.
@@ -73,6 +86,34 @@ func GC(
 		}
 	}
 
+	// The range tombstones are garbage collected first, along with the
+	// versions they shadow. They must be below the GC threshold, since they
+	// delete all the versions of the keys in their span.
+	if len(args.RangeKeys) > 0 {
+		threshold := cArgs.EvalCtx.GetGCThreshold()
+		threshold.Forward(args.Threshold)
+		desc := cArgs.EvalCtx.Desc()
+		rangeKeys := make([]storage.MVCCRangeKey, 0, len(args.RangeKeys))
+		for _, k := range args.RangeKeys {
+			if !desc.ContainsKeyRange(roachpb.RKey(k.StartKey), roachpb.RKey(k.EndKey)) {
+				continue
+			}
+			if threshold.Less(k.Timestamp) {
+				return result.Result{}, errors.Errorf(
+					"range tombstone %s is above the GC threshold %s",
+					roachpb.Span{Key: k.StartKey, EndKey: k.EndKey}, threshold)
+			}
+			rangeKeys = append(rangeKeys, storage.MVCCRangeKey{
+				StartKey: k.StartKey, EndKey: k.EndKey, Timestamp: k.Timestamp,
+			})
+		}
+		if err := storage.MVCCGarbageCollectRangeKeys(
+			ctx, readWriter, cArgs.Stats, rangeKeys, h.Timestamp,
+		); err != nil {
+			return result.Result{}, err
+		}
+	}
+
 	// Garbage collect the specified keys by expiration timestamps.
 	if err := storage.MVCCGarbageCollect(
 		ctx, readWriter, cArgs.Stats, keys, h.Timestamp,
@@ -12,6 +12,7 @@ package batcheval
 
 import (
 	"context"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval/result"
@@ -43,6 +44,20 @@ func RangeStats(
 	reply := resp.(*roachpb.RangeStatsResponse)
 	reply.MVCCStats = cArgs.EvalCtx.GetMVCCStats()
 	reply.QueriesPerSecond = cArgs.EvalCtx.GetSplitQPS()
+	if qps, ok := cArgs.EvalCtx.GetMaxSplitQPS(); ok {
+		reply.MaxQueriesPerSecond = qps
+	} else {
+		// The range was recently split due to load and has not been collecting
+		// load measurements for long enough since.
+		reply.MaxQueriesPerSecond = -1
+	}
+	for locality, qps := range cArgs.EvalCtx.GetLocalityQPS() {
+		reply.LocalityQueriesPerSecond = append(reply.LocalityQueriesPerSecond,
+			roachpb.RangeStatsResponse_LocalityQueriesPerSecond{Locality: locality, QueriesPerSecond: qps})
+	}
+	sort.Slice(reply.LocalityQueriesPerSecond, func(i, j int) bool {
+		return reply.LocalityQueriesPerSecond[i].Locality < reply.LocalityQueriesPerSecond[j].Locality
+	})
 	desc, lease := cArgs.EvalCtx.GetDescAndLease(ctx)
 	reply.RangeInfo = &roachpb.RangeInfo{Desc: desc, Lease: lease}
 	return result.Result{}, nil
@@ -38,6 +38,7 @@ func declareKeysRevertRange(
 	// is equal to the entire range for fast stats updating.
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeDescriptorKey(desc.StartKey)})
 	latchSpans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{Key: keys.RangeLastGCKey(desc.RangeID)})
+	DeclareRangeTombstoneKeys(spanset.SpanReadWrite, desc, latchSpans)
 }
 
 // isEmptyKeyTimeRange checks if the span has no writes in (since,until].
@@ -54,8 +55,21 @@ func isEmptyKeyTimeRange(
 	})
 	defer iter.Close()
 	iter.SeekGE(storage.MVCCKey{Key: from})
-	ok, err := iter.Valid()
-	return !ok, err
+	if ok, err := iter.Valid(); err != nil || ok {
+		return false, err
+	}
+	// The iterator doesn't observe the range tombstones, which are checked
+	// separately.
+	rangeTombstones, err := storage.MVCCScanRangeTombstones(readWriter, from, to)
+	if err != nil {
+		return false, err
+	}
+	for _, rangeKey := range rangeTombstones {
+		if since.Less(rangeKey.Timestamp) && rangeKey.Timestamp.LessEq(until) {
+			return false, nil
+		}
+	}
+	return true, nil
 }
 
 // RevertRange wipes all MVCC versions more recent than TargetTime (up to the
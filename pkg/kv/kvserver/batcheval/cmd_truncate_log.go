@@ -113,7 +113,15 @@ func TruncateLog(
 	// bugs that let it diverge. It might be easier to compute the stats
 	// from scratch, stopping when 4mb (defaultRaftLogTruncationThreshold)
 	// is reached as at that point we'll truncate aggressively anyway.
-	iter := readWriter.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{UpperBound: end})
+	//
+	// A dedicated Raft log engine isn't visible through readWriter, so read
+	// from it directly. This isn't a consistent view, but neither the
+	// computation nor the first index it's based on are exact to begin with.
+	logReader := storage.Reader(readWriter)
+	if raftEng := cArgs.EvalCtx.RaftLogEngine(); raftEng != nil {
+		logReader = raftEng
+	}
+	iter := logReader.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{UpperBound: end})
 	defer iter.Close()
 	// We can pass zero as nowNanos because we're only interested in SysBytes.
 	ms, err := iter.ComputeStats(start, end, 0 /* nowNanos */)
@@ -90,6 +90,19 @@ func DefaultDeclareIsolatedKeys(
 	lockSpans.AddNonMVCC(access, req.Header().Span())
 }
 
+// DeclareRangeTombstoneKeys declares the fragments of the MVCC range
+// tombstones in the range's span, which are stored in a non-MVCC keyspace.
+// This includes the fragment starting at the range's end key, since seeking
+// and iterating over the fragments can observe it.
+func DeclareRangeTombstoneKeys(
+	access spanset.SpanAccess, desc *roachpb.RangeDescriptor, latchSpans *spanset.SpanSet,
+) {
+	latchSpans.AddNonMVCC(access, roachpb.Span{
+		Key:    keys.MVCCRangeTombstoneKey(desc.StartKey.AsRawKey()),
+		EndKey: keys.MVCCRangeTombstoneKey(desc.EndKey.AsRawKey().Next()),
+	})
+}
+
 // DeclareKeysForBatch adds all keys that the batch with the provided header
 // touches to the given SpanSet. This does not include keys touched during the
 // processing of the batch's individual commands.
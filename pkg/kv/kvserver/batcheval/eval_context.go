@@ -52,6 +52,9 @@ type EvalContext interface {
 	EvalKnobs() kvserverbase.BatchEvalTestingKnobs
 
 	Engine() storage.Engine
+	// RaftLogEngine returns the dedicated engine holding the Raft log, or nil
+	// if the Raft log is kept in Engine().
+	RaftLogEngine() storage.Engine
 	Clock() *hlc.Clock
 	DB() *kv.DB
 	AbortSpan() *abortspan.AbortSpan
@@ -92,6 +95,19 @@ type EvalContext interface {
 	// setting is disabled.
 	GetSplitQPS() float64
 
+	// GetMaxSplitQPS returns the maximum queries/s request rate for this range
+	// over the kv.range_split.by_load_merge_delay period. The boolean is false
+	// if the range was recently split due to load and does not have
+	// measurements for the whole period since.
+	//
+	// NOTE: This should not be used when the load based splitting cluster
+	// setting is disabled.
+	GetMaxSplitQPS() (float64, bool)
+
+	// GetLocalityQPS returns the queries/s request rate for this range at the
+	// leaseholder, by the locality of the node the requests were sent from.
+	GetLocalityQPS() map[string]float64
+
 	GetGCThreshold() hlc.Timestamp
 	GetLastReplicaGCTimestamp(context.Context) (hlc.Timestamp, error)
 	GetLease() (roachpb.Lease, roachpb.Lease)
@@ -142,6 +158,9 @@ func (m *mockEvalCtxImpl) EvalKnobs() kvserverbase.BatchEvalTestingKnobs {
 func (m *mockEvalCtxImpl) Engine() storage.Engine {
 	panic("unimplemented")
 }
+func (m *mockEvalCtxImpl) RaftLogEngine() storage.Engine {
+	return nil
+}
 func (m *mockEvalCtxImpl) Clock() *hlc.Clock {
 	return m.MockEvalCtx.Clock
 }
@@ -196,6 +215,12 @@ func (m *mockEvalCtxImpl) GetMVCCStats() enginepb.MVCCStats {
 func (m *mockEvalCtxImpl) GetSplitQPS() float64 {
 	return m.QPS
 }
+func (m *mockEvalCtxImpl) GetMaxSplitQPS() (float64, bool) {
+	return m.QPS, true
+}
+func (m *mockEvalCtxImpl) GetLocalityQPS() map[string]float64 {
+	return nil
+}
 func (m *mockEvalCtxImpl) CanCreateTxnRecord(
 	uuid.UUID, []byte, hlc.Timestamp,
 ) (bool, hlc.Timestamp, roachpb.TransactionAbortedReason) {
@@ -3092,11 +3092,12 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 		// TODO(sumeer): fix this test (and others in this file) when
 		// DisallowSeparatedIntents=false
 
-		// The seven to nine SSTs we are expecting to ingest are in the following order:
+		// The eight to ten SSTs we are expecting to ingest are in the following order:
 		// - Replicated range-id local keys of the range in the snapshot.
 		// - Range-local keys of the range in the snapshot.
 		// - Optionally, two SSTs for the lock table keys of the range in the
 		//   snapshot
+		// - MVCC range tombstone keys of the range in the snapshot.
 		// - User keys of the range in the snapshot.
 		// - Unreplicated range-id local keys of the range in the snapshot.
 		// - SST to clear range-id local keys of the subsumed replica with
@@ -3105,10 +3106,10 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 		//   RangeID 4.
 		// - SST to clear the user keys of the subsumed replicas.
 		//
-		// NOTE: There are no range-local keys or lock table keys, in [d, /Max) in
-		// the store we're sending a snapshot to, so we aren't expecting SSTs to
-		// clear those keys.
-		expectedSSTCount := 7
+		// NOTE: There are no range-local keys, lock table keys or MVCC range
+		// tombstone keys, in [d, /Max) in the store we're sending a snapshot to,
+		// so we aren't expecting SSTs to clear those keys.
+		expectedSSTCount := 8
 		indexAdjustment := 0
 		if !storage.DisallowSeparatedIntents {
 			expectedSSTCount += 2
@@ -3124,23 +3125,23 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 		// - Clearing rhe range-id local keys of the subsumed replicas.
 		// - Clearing the user keys of the subsumed replicas.
 		// The snapshot SSTs that are excluded from this checking are the
-		// replicated range-id, range-local keys, lock table keys in the snapshot,
-		// and the unreplicated range-id local keys in the snapshot. The latter is
-		// excluded since the state of the Raft log can be non-deterministic with
-		// extra entries being appended to the sender's log after the snapshot has
-		// already been sent.
+		// replicated range-id, range-local keys, lock table keys, MVCC range
+		// tombstone keys in the snapshot, and the unreplicated range-id local
+		// keys in the snapshot. The latter is excluded since the state of the
+		// Raft log can be non-deterministic with extra entries being appended to
+		// the sender's log after the snapshot has already been sent.
 		var sstNamesSubset []string
 		// The SST with the user keys in the snapshot.
-		sstNamesSubset = append(sstNamesSubset, sstNames[2+indexAdjustment])
+		sstNamesSubset = append(sstNamesSubset, sstNames[3+indexAdjustment])
 		// Remaining ones from the predict list above.
-		sstNamesSubset = append(sstNamesSubset, sstNames[4+indexAdjustment:]...)
+		sstNamesSubset = append(sstNamesSubset, sstNames[5+indexAdjustment:]...)
 
 		// Construct the expected SSTs and ensure that they are byte-by-byte
 		// equal. This verification ensures that the SSTs have the same
 		// tombstones and range deletion tombstones.
 		var expectedSSTs [][]byte
 
-		// Construct SSTs for the the first 4 bullets as numbered above, but only
+		// Construct SSTs for the the first 5 bullets as numbered above, but only
 		// ultimately keep the last one.
 		keyRanges := rditer.MakeReplicatedKeyRanges(inSnap.State.Desc)
 		it := rditer.NewReplicaEngineDataIterator(inSnap.State.Desc, sendingEng, true /* replicatedOnly */)
@@ -3174,9 +3175,9 @@ func TestStoreRangeMergeRaftSnapshot(t *testing.T) {
 				}
 			}
 		}
-		if len(expectedSSTs) != 3+indexAdjustment {
+		if len(expectedSSTs) != 4+indexAdjustment {
 			return errors.Errorf("len of expectedSSTs should expected to be %d, but got %d",
-				3+indexAdjustment, len(expectedSSTs))
+				4+indexAdjustment, len(expectedSSTs))
 		}
 		// Keep the last one which contains the user keys.
 		expectedSSTs = expectedSSTs[len(expectedSSTs)-1:]
@@ -169,6 +169,19 @@ func (h *mtcStoreRaftMessageHandler) HandleSnapshot(
 	return store.HandleSnapshot(header, respStream)
 }
 
+func (h *mtcStoreRaftMessageHandler) HandleDelegatedSnapshot(
+	ctx context.Context, req *kvserver.DelegateSnapshotRequest,
+) *kvserver.DelegateSnapshotResponse {
+	store := h.mtc.Store(h.storeIdx)
+	if store == nil {
+		return &kvserver.DelegateSnapshotResponse{
+			Status:  kvserver.SnapshotResponse_ERROR,
+			Message: "store not found",
+		}
+	}
+	return store.HandleDelegatedSnapshot(ctx, req)
+}
+
 // mtcPartitionedRange is a convenient abstraction to create a range on a node
 // in a multiTestContext which can be partitioned and unpartitioned.
 type mtcPartitionedRange struct {
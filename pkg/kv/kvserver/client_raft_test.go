@@ -3424,6 +3424,12 @@ func (errorChannelTestHandler) HandleSnapshot(
 	panic("unimplemented")
 }
 
+func (errorChannelTestHandler) HandleDelegatedSnapshot(
+	_ context.Context, _ *kvserver.DelegateSnapshotRequest,
+) *kvserver.DelegateSnapshotResponse {
+	panic("unimplemented")
+}
+
 // This test simulates a scenario where one replica has been removed from the
 // range's Raft group but it is unaware of the fact. We check that this replica
 // coming back from the dead cannot cause elections.
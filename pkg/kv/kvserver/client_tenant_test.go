@@ -171,24 +171,46 @@ func TestTenantRateLimiter(t *testing.T) {
 		return encoding.EncodeUUIDValue(tablePrefix, 1, uuid.MakeV4())
 	}
 
-	// Ensure that the qps rate limit does not affect the system tenant even for
+	// Configure the cost model such that every write request costs exactly one
+	// request unit, regardless of its size and of the time spent evaluating it.
+	runner := sqlutils.MakeSQLRunner(sqlDB)
+	runner.Exec(t, `SET CLUSTER SETTING kv.tenant_rate_limiter.burst_limit = 100`)
+	runner.Exec(t, `SET CLUSTER SETTING kv.tenant_rate_limiter.write_cost_per_megabyte = 0`)
+	runner.Exec(t, `SET CLUSTER SETTING kv.tenant_rate_limiter.read_cost_per_megabyte = 0`)
+	runner.Exec(t, `SET CLUSTER SETTING kv.tenant_rate_limiter.cpu_cost_per_second = 0`)
+	testutils.SucceedsSoon(t, func() error {
+		costs := tenantrate.CostModelFromSettings(s.ClusterSettings())
+		if costs.WriteCostPerMegabyte != 0 || costs.ReadCostPerMegabyte != 0 ||
+			costs.CPUCostPerSecond != 0 {
+			return errors.Errorf("cost model not yet updated: %+v", costs)
+		}
+		if cfg := tenantrate.LimitConfigFromSettings(s.ClusterSettings()); cfg.Burst != 100 {
+			return errors.Errorf("limits not yet updated: %+v", cfg)
+		}
+		return nil
+	})
+	cfg := tenantrate.LimitConfigFromSettings(s.ClusterSettings())
+	costs := tenantrate.CostModelFromSettings(s.ClusterSettings())
+	writesPerBurst := int64(cfg.Burst / costs.WriteRequestCost)
+
+	// Ensure that the rate limit does not affect the system tenant even for
 	// the tenant range.
 	tenantCtx := roachpb.NewContextForTenant(ctx, tenantID)
-	cfg := tenantrate.LimitConfigsFromSettings(s.ClusterSettings())
-	for i := 0; i < int(cfg.WriteRequests.Burst); i++ {
+	for i := int64(0); i < writesPerBurst; i++ {
 		require.NoError(t, db.Put(ctx, mkKey(), 0))
 	}
-	// Now ensure that in the same instant the write QPS limit does affect the
+	// Now ensure that in the same instant the rate limit does affect the
 	// tenant. Issuing up to the burst limit of requests can happen without
 	// blocking.
-	for i := 0; i < int(cfg.WriteRequests.Burst); i++ {
+	for i := int64(0); i < writesPerBurst; i++ {
 		require.NoError(t, db.Put(tenantCtx, mkKey(), 0))
 	}
 	// Attempt to issue another request, make sure that it gets blocked by
 	// observing a timer.
 	errCh := make(chan error, 1)
 	go func() { errCh <- db.Put(tenantCtx, mkKey(), 0) }()
-	expectedTimer := t0.Add(time.Duration(float64(1/cfg.WriteRequests.Rate) * float64(time.Second)))
+	expectedTimer := t0.Add(time.Duration(
+		float64(costs.WriteRequestCost) / float64(cfg.Rate) * float64(time.Second)))
 	testutils.SucceedsSoon(t, func() error {
 		timers := timeSource.Timers()
 		if len(timers) != 1 {
@@ -200,8 +222,7 @@ func TestTenantRateLimiter(t *testing.T) {
 
 	// Create some tooling to read and verify metrics off of the prometheus
 	// endpoint.
-	sqlutils.MakeSQLRunner(sqlDB).Exec(t,
-		`SET CLUSTER SETTING server.child_metrics.enabled = true`)
+	runner.Exec(t, `SET CLUSTER SETTING server.child_metrics.enabled = true`)
 	httpClient, err := s.GetHTTPClient()
 	require.NoError(t, err)
 	getMetrics := func() string {
@@ -219,12 +240,12 @@ func TestTenantRateLimiter(t *testing.T) {
 
 	// Ensure that the metric for the admitted requests is equal to the number of
 	// requests which we've admitted.
-	require.Contains(t, getMetrics(), makeMetricStr(cfg.WriteRequests.Burst))
+	require.Contains(t, getMetrics(), makeMetricStr(writesPerBurst))
 
 	// Allow the blocked request to proceed.
 	timeSource.Advance(time.Second)
 	require.NoError(t, <-errCh)
 
 	// Ensure that it is now reflected in the metrics.
-	require.Contains(t, getMetrics(), makeMetricStr(cfg.WriteRequests.Burst+1))
+	require.Contains(t, getMetrics(), makeMetricStr(writesPerBurst+1))
 }
@@ -42,6 +42,15 @@ var consistencyCheckRate = settings.RegisterByteSizeSetting(
 	validatePositive,
 ).WithPublic()
 
+var consistencyCheckRepairEnabled = settings.RegisterBoolSetting(
+	"server.consistency_check.repair.enabled",
+	"if enabled, replicas found to be inconsistent with the leaseholder and a majority of "+
+		"the replicas are replaced with a copy of the leaseholder's replica, instead of "+
+		"terminating the nodes they are on. Checkpoints of all replicas are kept for "+
+		"investigation. Inconsistencies without such a majority still terminate nodes.",
+	false,
+).WithPublic()
+
 // consistencyCheckRateBurstFactor we use this to set the burst parameter on the
 // quotapool.RateLimiter. It seems overkill to provide a user setting for this,
 // so we use a factor to scale the burst setting based on the rate defined above.
@@ -466,6 +466,73 @@ func TestCheckConsistencyInconsistent(t *testing.T) {
 	require.NotEmpty(t, b)
 }
 
+// TestCheckConsistencyInconsistentRepair verifies that with repairs enabled, a
+// replica that diverged from the leaseholder and the other replica is
+// replaced, instead of terminating the node it is on.
+func TestCheckConsistencyInconsistentRepair(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	testKnobs := kvserver.StoreTestingKnobs{DisableConsistencyQueue: true}
+	testKnobs.ConsistencyTestingKnobs.OnBadChecksumFatal = func(s roachpb.StoreIdent) {
+		t.Errorf("OnBadChecksumFatal called from %v", s)
+	}
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{
+		ReplicationMode: base.ReplicationManual,
+		ServerArgs: base.TestServerArgs{
+			Knobs: base.TestingKnobs{Store: &testKnobs},
+		},
+	})
+	defer tc.Stopper().Stop(ctx)
+	_, err := tc.ServerConn(0).Exec(`SET CLUSTER SETTING server.consistency_check.repair.enabled = true`)
+	require.NoError(t, err)
+
+	key := tc.ScratchRange(t)
+	desc := tc.AddVotersOrFatal(t, key, tc.Targets(1, 2)...)
+	store := tc.GetFirstStoreFromServer(t, 0)
+	_, pErr := kv.SendWrapped(ctx, store.DB().NonTransactionalSender(), putArgs(key, []byte("a")))
+	require.NoError(t, pErr.GoError())
+
+	runCheck := func() roachpb.CheckConsistencyResponse_Result {
+		checkArgs := roachpb.CheckConsistencyRequest{
+			RequestHeader: roachpb.RequestHeader{Key: key, EndKey: key.PrefixEnd()},
+			Mode:          roachpb.ChecksumMode_CHECK_VIA_QUEUE,
+		}
+		resp, pErr := kv.SendWrapped(ctx, store.DB().NonTransactionalSender(), &checkArgs)
+		require.NoError(t, pErr.GoError())
+		results := resp.(*roachpb.CheckConsistencyResponse).Result
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	// Write a key only to the replica on s2.
+	store1 := tc.GetFirstStoreFromServer(t, 1)
+	var val roachpb.Value
+	val.SetInt(42)
+	require.NoError(t, storage.MVCCPut(
+		ctx, store1.Engine(), nil, key.Next(), tc.Servers[0].Clock().Now(), val, nil,
+	))
+	oldRepl, ok := desc.GetReplicaDescriptor(store1.StoreID())
+	require.True(t, ok)
+
+	res := runCheck()
+	require.Equal(t, roachpb.CheckConsistencyResponse_RANGE_INCONSISTENT, res.Status)
+	require.Contains(t, res.Detail, "repaired")
+
+	// The replica on s2 was replaced with a new one, which is consistent.
+	desc = tc.LookupRangeOrFatal(t, key)
+	newRepl, ok := desc.GetReplicaDescriptor(store1.StoreID())
+	require.True(t, ok)
+	require.Greater(t, int(newRepl.ReplicaID), int(oldRepl.ReplicaID))
+	testutils.SucceedsSoon(t, func() error {
+		if res := runCheck(); res.Status != roachpb.CheckConsistencyResponse_RANGE_CONSISTENT {
+			return fmt.Errorf("unexpected status %s: %s", res.Status, res.Detail)
+		}
+		return nil
+	})
+}
+
 // TestConsistencyQueueRecomputeStats is an end-to-end test of the mechanism CockroachDB
 // employs to adjust incorrect MVCCStats ("incorrect" meaning not an inconsistency of
 // these stats between replicas, but a delta between persisted stats and those one
@@ -72,10 +72,16 @@ type PureGCer interface {
 	GC(context.Context, []roachpb.GCRequest_GCKey) error
 }
 
+// RangeKeyGCer is part of the GCer interface.
+type RangeKeyGCer interface {
+	GCRangeKeys(context.Context, []roachpb.GCRequest_GCRangeKey) error
+}
+
 // A GCer is an abstraction used by the GC queue to carry out chunked deletions.
 type GCer interface {
 	Thresholder
 	PureGCer
+	RangeKeyGCer
 }
 
 // NoopGCer implements GCer by doing nothing.
@@ -89,6 +95,9 @@ func (NoopGCer) SetGCThreshold(context.Context, Threshold) error { return nil }
 // GC implements storage.GCer.
 func (NoopGCer) GC(context.Context, []roachpb.GCRequest_GCKey) error { return nil }
 
+// GCRangeKeys implements storage.GCer.
+func (NoopGCer) GCRangeKeys(context.Context, []roachpb.GCRequest_GCRangeKey) error { return nil }
+
 // Threshold holds the key and txn span GC thresholds, respectively.
 type Threshold struct {
 	Key hlc.Timestamp
@@ -105,6 +114,9 @@ type Info struct {
 	// keys with GC'able data, the number of "old" intents and the number of
 	// associated distinct transactions.
 	NumKeysAffected, IntentsConsidered, IntentTxns int
+	// NumRangeKeysAffected is the number of MVCC range tombstones found
+	// GCable.
+	NumRangeKeysAffected int
 	// TransactionSpanTotal is the total number of entries in the transaction span.
 	TransactionSpanTotal int
 	// Summary of transactions which were found GCable (assuming that
@@ -187,6 +199,12 @@ func Run(
 		return Info{}, err
 	}
 
+	// Process the MVCC range tombstones. The versions they shadow were
+	// garbage collected along with the other versions above.
+	if err := processRangeTombstones(ctx, desc, snap, newThreshold, gcer, &info); err != nil {
+		return Info{}, err
+	}
+
 	// From now on, all keys processed are range-local and inline (zero timestamp).
 
 	// Process local range key entries (txn records, queue last processed times).
@@ -222,6 +240,39 @@ func Run(
 	return info, nil
 }
 
+// processRangeTombstones sends GC requests for the MVCC range tombstones
+// at or below the GC threshold.
+func processRangeTombstones(
+	ctx context.Context,
+	desc *roachpb.RangeDescriptor,
+	snap storage.Reader,
+	threshold hlc.Timestamp,
+	gcer RangeKeyGCer,
+	info *Info,
+) error {
+	rangeTombstones, err := storage.MVCCScanRangeTombstones(
+		snap, desc.StartKey.AsRawKey(), desc.EndKey.AsRawKey())
+	if err != nil {
+		return err
+	}
+	var gcRangeKeys []roachpb.GCRequest_GCRangeKey
+	for _, rangeKey := range rangeTombstones {
+		if threshold.Less(rangeKey.Timestamp) {
+			continue
+		}
+		gcRangeKeys = append(gcRangeKeys, roachpb.GCRequest_GCRangeKey{
+			StartKey:  rangeKey.StartKey,
+			EndKey:    rangeKey.EndKey,
+			Timestamp: rangeKey.Timestamp,
+		})
+	}
+	if len(gcRangeKeys) == 0 {
+		return nil
+	}
+	info.NumRangeKeysAffected += len(gcRangeKeys)
+	return gcer.GCRangeKeys(ctx, gcRangeKeys)
+}
+
 // processReplicatedKeyRange identifies garbage and sends GC requests to
 // remove it.
 //
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -61,6 +62,15 @@ var MergeQueueInterval = settings.RegisterDurationSetting(
 // size threshold, and b) the merged range would not need to be immediately
 // split, e.g. because the new range would exceed the maximum size threshold.
 //
+// When load based splitting is enabled, the merged range must also not need to
+// be split due to load. To avoid merging away ranges that were split due to
+// load as soon as a traffic spike subsides, the load is measured as the
+// maximum QPS of each side over the kv.range_split.by_load_merge_delay period,
+// and a range split due to load is not merged until its leaseholder has been
+// collecting measurements for the whole period since the split. Before the
+// RangeStatsRespHasMaxQPS version is active, the current QPS is used instead,
+// and load based splits set a sticky bit that expires after the merge delay.
+//
 // Note that the merge queue is not capable of initiating all possible merges.
 // Consider the example below:
 //
@@ -172,9 +182,19 @@ func (rangeMergePurgatoryError) purgatoryErrorMarker() {}
 
 var _ purgatoryError = rangeMergePurgatoryError{}
 
+// requestRangeStats returns the descriptor, MVCC stats and QPS of the range
+// containing the key. The QPS is the maximum QPS over the merge delay period;
+// the boolean is false if the range was split due to load and does not have
+// measurements for the whole period since.
 func (mq *mergeQueue) requestRangeStats(
 	ctx context.Context, key roachpb.Key,
-) (*roachpb.RangeDescriptor, enginepb.MVCCStats, float64, error) {
+) (
+	desc *roachpb.RangeDescriptor,
+	stats enginepb.MVCCStats,
+	qps float64,
+	qpsOK bool,
+	err error,
+) {
 
 	var ba roachpb.BatchRequest
 	ba.Add(&roachpb.RangeStatsRequest{
@@ -187,22 +207,34 @@ func (mq *mergeQueue) requestRangeStats(
 
 	br, pErr := mq.db.NonTransactionalSender().Send(ctx, ba)
 	if pErr != nil {
-		return nil, enginepb.MVCCStats{}, 0, pErr.GoError()
+		return nil, enginepb.MVCCStats{}, 0, false, pErr.GoError()
 	}
 	res := br.Responses[0].GetInner().(*roachpb.RangeStatsResponse)
 
-	var desc *roachpb.RangeDescriptor
 	if res.RangeInfo != nil {
 		desc = &res.RangeInfo.Desc
 	} else {
 		if len(br.RangeInfos) != 1 {
-			return nil, enginepb.MVCCStats{}, 0, errors.AssertionFailedf(
+			return nil, enginepb.MVCCStats{}, 0, false, errors.AssertionFailedf(
 				"mergeQueue.requestRangeStats: response had %d range infos but exactly one was expected",
 				len(br.RangeInfos))
 		}
 		desc = &br.RangeInfos[0].Desc
 	}
-	return desc, res.MVCCStats, res.QueriesPerSecond, nil
+	if !mq.store.ClusterSettings().Version.IsActive(ctx, clusterversion.RangeStatsRespHasMaxQPS) {
+		return desc, res.MVCCStats, res.QueriesPerSecond, true, nil
+	}
+	qps = res.MaxQueriesPerSecond
+	return desc, res.MVCCStats, qps, qps >= 0, nil
+}
+
+// getLHSQPS returns the QPS of the left-hand range, matching what
+// requestRangeStats returns for the right-hand range.
+func (mq *mergeQueue) getLHSQPS(ctx context.Context, lhsRepl *Replica) (float64, bool) {
+	if !mq.store.ClusterSettings().Version.IsActive(ctx, clusterversion.RangeStatsRespHasMaxQPS) {
+		return lhsRepl.GetSplitQPS(), true
+	}
+	return lhsRepl.GetMaxSplitQPS()
 }
 
 func (mq *mergeQueue) process(
@@ -222,8 +254,8 @@ func (mq *mergeQueue) process(
 	}
 
 	lhsDesc := lhsRepl.Desc()
-	lhsQPS := lhsRepl.GetSplitQPS()
-	rhsDesc, rhsStats, rhsQPS, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
+	lhsQPS, lhsQPSOK := mq.getLHSQPS(ctx, lhsRepl)
+	rhsDesc, rhsStats, rhsQPS, rhsQPSOK, err := mq.requestRangeStats(ctx, lhsDesc.EndKey.AsRawKey())
 	if err != nil {
 		return false, err
 	}
@@ -251,6 +283,17 @@ func (mq *mergeQueue) process(
 
 	var mergedQPS float64
 	if lhsRepl.SplitByLoadEnabled() {
+		// Don't merge ranges split due to load until they have been collecting
+		// load measurements for the whole merge delay, so that they aren't
+		// merged away right after the load that caused the split subsides.
+		if !lhsQPSOK {
+			log.VEventf(ctx, 2, "skipping merge: LHS QPS measurement not yet reliable")
+			return false, nil
+		}
+		if !rhsQPSOK {
+			log.VEventf(ctx, 2, "skipping merge: RHS QPS measurement not yet reliable")
+			return false, nil
+		}
 		mergedQPS = lhsQPS + rhsQPS
 	}
 
@@ -353,6 +396,12 @@ func (mq *mergeQueue) process(
 		// as purgatory-worthy.
 		return false, rangeMergePurgatoryError{err}
 	}
+	// The merged range carries the load of both sides. Record it so that its
+	// QPS history doesn't underestimate the load should it be considered for
+	// another merge.
+	if lhsRepl.SplitByLoadEnabled() {
+		lhsRepl.loadBasedSplitter.RecordMax(timeutil.Now(), mergedQPS)
+	}
 	if testingAggressiveConsistencyChecks {
 		if _, err := mq.store.consistencyQueue.process(ctx, lhsRepl, sysCfg); err != nil {
 			log.Warningf(ctx, "%v", err)
@@ -461,6 +461,18 @@ var (
 		Measurement: "Snapshots",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaRangeSnapshotsDelegateSuccesses = metric.Metadata{
+		Name:        "range.snapshots.delegate.successes",
+		Help:        "Number of snapshots that were delegated to and sent by a follower replica",
+		Measurement: "Snapshots",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRangeSnapshotsDelegateFailures = metric.Metadata{
+		Name:        "range.snapshots.delegate.failures",
+		Help:        "Number of snapshots that were delegated to a follower replica but failed to be sent by it",
+		Measurement: "Snapshots",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRangeRaftLeaderTransfers = metric.Metadata{
 		Name:        "range.raftleadertransfers",
 		Help:        "Number of raft leader transfers",
@@ -1121,6 +1133,8 @@ type StoreMetrics struct {
 	RangeSnapshotsAppliedByVoters                *metric.Counter
 	RangeSnapshotsAppliedForInitialUpreplication *metric.Counter
 	RangeSnapshotsAppliedByNonVoters             *metric.Counter
+	RangeSnapshotsDelegateSuccesses              *metric.Counter
+	RangeSnapshotsDelegateFailures               *metric.Counter
 	RangeRaftLeaderTransfers                     *metric.Counter
 
 	// Raft processing metrics.
@@ -1487,6 +1501,8 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RangeSnapshotsAppliedByVoters: metric.NewCounter(metaRangeSnapshotsAppliedByVoters),
 		RangeSnapshotsAppliedForInitialUpreplication: metric.NewCounter(metaRangeSnapshotsAppliedForInitialUpreplication),
 		RangeSnapshotsAppliedByNonVoters:             metric.NewCounter(metaRangeSnapshotsAppliedByNonVoter),
+		RangeSnapshotsDelegateSuccesses:              metric.NewCounter(metaRangeSnapshotsDelegateSuccesses),
+		RangeSnapshotsDelegateFailures:               metric.NewCounter(metaRangeSnapshotsDelegateFailures),
 		RangeRaftLeaderTransfers:                     metric.NewCounter(metaRangeRaftLeaderTransfers),
 
 		// Raft processing metrics.
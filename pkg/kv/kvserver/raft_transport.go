@@ -109,6 +109,10 @@ type RaftMessageHandler interface {
 	// HandleSnapshot is called for each new incoming snapshot stream, after
 	// parsing the initial SnapshotRequest_Header on the stream.
 	HandleSnapshot(header *SnapshotRequest_Header, respStream SnapshotResponseStream) error
+
+	// HandleDelegatedSnapshot is called for each incoming request to send a
+	// snapshot on behalf of the Raft leader of a range.
+	HandleDelegatedSnapshot(ctx context.Context, req *DelegateSnapshotRequest) *DelegateSnapshotResponse
 }
 
 type raftTransportStats struct {
@@ -424,6 +428,20 @@ func (t *RaftTransport) RaftSnapshot(stream MultiRaft_RaftSnapshotServer) error
 	}
 }
 
+// DelegateRaftSnapshot handles incoming requests to send a snapshot on
+// behalf of the Raft leader of a range.
+func (t *RaftTransport) DelegateRaftSnapshot(
+	ctx context.Context, req *DelegateSnapshotRequest,
+) (*DelegateSnapshotResponse, error) {
+	handler, ok := t.getHandler(req.DelegatedSender.StoreID)
+	if !ok {
+		log.Warningf(ctx, "unable to accept delegated snapshot from %+v: no handler registered for %+v",
+			req.CoordinatorReplica, req.DelegatedSender)
+		return nil, roachpb.NewStoreNotFoundError(req.DelegatedSender.StoreID)
+	}
+	return handler.HandleDelegatedSnapshot(ctx, req), nil
+}
+
 // Listen registers a raftMessageHandler to receive proxied messages.
 func (t *RaftTransport) Listen(storeID roachpb.StoreID, handler RaftMessageHandler) {
 	t.handlers.Store(int64(storeID), unsafe.Pointer(&handler))
@@ -679,3 +697,16 @@ func (t *RaftTransport) SendSnapshot(
 		ctx, t.st, stream, storePool, header, snap, newBatch, sent,
 	)
 }
+
+// DelegateSnapshot asks the delegated sender of the request to send a
+// snapshot on behalf of the Raft leader. It returns once the snapshot has
+// been applied by the recipient or has failed.
+func (t *RaftTransport) DelegateSnapshot(
+	ctx context.Context, req *DelegateSnapshotRequest,
+) (*DelegateSnapshotResponse, error) {
+	conn, err := t.dialer.Dial(ctx, req.DelegatedSender.NodeID, rpc.DefaultClass)
+	if err != nil {
+		return nil, err
+	}
+	return NewMultiRaftClient(conn).DelegateRaftSnapshot(ctx, req)
+}
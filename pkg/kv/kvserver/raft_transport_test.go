@@ -93,6 +93,12 @@ func (s channelServer) HandleSnapshot(
 	panic("unexpected HandleSnapshot")
 }
 
+func (s channelServer) HandleDelegatedSnapshot(
+	ctx context.Context, req *kvserver.DelegateSnapshotRequest,
+) *kvserver.DelegateSnapshotResponse {
+	panic("unexpected HandleDelegatedSnapshot")
+}
+
 // raftTransportTestContext contains objects needed to test RaftTransport.
 // Typical usage will add multiple nodes with AddNode, attach channels
 // to at least one store with ListenStore, and send messages with Send.
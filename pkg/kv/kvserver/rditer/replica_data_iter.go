@@ -69,7 +69,8 @@ func MakeAllKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 // 1. Replicated range-id local key range
 // 2. Range-local key range
 // 3. Lock-table key ranges (optional)
-// 4. User key range
+// 4. MVCC range tombstone key range
+// 5. User key range
 func MakeReplicatedKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 	return makeRangeKeyRanges(d, true /* replicatedOnly */)
 }
@@ -77,16 +78,18 @@ func MakeReplicatedKeyRanges(d *roachpb.RangeDescriptor) []KeyRange {
 func makeRangeKeyRanges(d *roachpb.RangeDescriptor, replicatedOnly bool) []KeyRange {
 	rangeIDLocal := MakeRangeIDLocalKeyRange(d.RangeID, replicatedOnly)
 	rangeLocal := makeRangeLocalKeyRange(d)
+	rangeTombstones := makeRangeTombstoneKeyRange(d)
 	user := MakeUserKeyRange(d)
 	if storage.DisallowSeparatedIntents {
 		return []KeyRange{
 			rangeIDLocal,
 			rangeLocal,
+			rangeTombstones,
 			user,
 		}
 	}
 	rangeLockTable := makeRangeLockTableKeyRanges(d)
-	ranges := make([]KeyRange, 3+len(rangeLockTable))
+	ranges := make([]KeyRange, 4+len(rangeLockTable))
 	ranges[0] = rangeIDLocal
 	ranges[1] = rangeLocal
 	i := 2
@@ -94,7 +97,8 @@ func makeRangeKeyRanges(d *roachpb.RangeDescriptor, replicatedOnly bool) []KeyRa
 		ranges[i] = rangeLockTable[j]
 		i++
 	}
-	ranges[i] = user
+	ranges[i] = rangeTombstones
+	ranges[i+1] = user
 	return ranges
 }
 
@@ -103,11 +107,13 @@ func makeRangeKeyRanges(d *roachpb.RangeDescriptor, replicatedOnly bool) []KeyRa
 // returned in the following sorted order:
 // 1. Replicated range-id local key range
 // 2. Range-local key range
-// 3. User key range
+// 3. MVCC range tombstone key range
+// 4. User key range
 func MakeReplicatedKeyRangesExceptLockTable(d *roachpb.RangeDescriptor) []KeyRange {
 	return []KeyRange{
 		MakeRangeIDLocalKeyRange(d.RangeID, true /* replicatedOnly */),
 		makeRangeLocalKeyRange(d),
+		makeRangeTombstoneKeyRange(d),
 		MakeUserKeyRange(d),
 	}
 }
@@ -117,25 +123,29 @@ func MakeReplicatedKeyRangesExceptLockTable(d *roachpb.RangeDescriptor) []KeyRan
 // These are returned in the following sorted order:
 // 1. Range-local key range
 // 2. Lock-table key ranges (optional)
-// 3. User key range
+// 3. MVCC range tombstone key range
+// 4. User key range
 func MakeReplicatedKeyRangesExceptRangeID(d *roachpb.RangeDescriptor) []KeyRange {
 	rangeLocal := makeRangeLocalKeyRange(d)
+	rangeTombstones := makeRangeTombstoneKeyRange(d)
 	user := MakeUserKeyRange(d)
 	if storage.DisallowSeparatedIntents {
 		return []KeyRange{
 			rangeLocal,
+			rangeTombstones,
 			user,
 		}
 	}
 	rangeLockTable := makeRangeLockTableKeyRanges(d)
-	ranges := make([]KeyRange, 2+len(rangeLockTable))
+	ranges := make([]KeyRange, 3+len(rangeLockTable))
 	ranges[0] = rangeLocal
 	i := 1
 	for j := range rangeLockTable {
 		ranges[i] = rangeLockTable[j]
 		i++
 	}
-	ranges[i] = user
+	ranges[i] = rangeTombstones
+	ranges[i+1] = user
 	return ranges
 }
 
@@ -195,6 +205,15 @@ func makeRangeLockTableKeyRanges(d *roachpb.RangeDescriptor) [2]KeyRange {
 	}
 }
 
+// makeRangeTombstoneKeyRange returns the key range of the fragments of the
+// MVCC range tombstones in the range's span.
+func makeRangeTombstoneKeyRange(d *roachpb.RangeDescriptor) KeyRange {
+	return KeyRange{
+		Start: storage.MakeMVCCMetadataKey(keys.MVCCRangeTombstoneKey(d.StartKey.AsRawKey())),
+		End:   storage.MakeMVCCMetadataKey(keys.MVCCRangeTombstoneKey(d.EndKey.AsRawKey())),
+	}
+}
+
 // MakeUserKeyRange returns the user key range.
 func MakeUserKeyRange(d *roachpb.RangeDescriptor) KeyRange {
 	// The first range in the keyspace starts at KeyMin, which includes the
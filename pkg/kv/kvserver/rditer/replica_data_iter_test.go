@@ -96,6 +96,8 @@ func createRangeData(
 		{keys.TransactionKey(roachpb.Key(desc.StartKey), uuid.MakeV4()), ts0},
 		{keys.TransactionKey(roachpb.Key(desc.StartKey.Next()), uuid.MakeV4()), ts0},
 		{keys.TransactionKey(fakePrevKey(desc.EndKey), uuid.MakeV4()), ts0},
+		{keys.MVCCRangeTombstoneKey(desc.StartKey.AsRawKey()), ts},
+		{keys.MVCCRangeTombstoneKey(fakePrevKey(desc.EndKey)), ts},
 		// TODO(bdarnell): KeyMin.Next() results in a key in the reserved system-local space.
 		// Once we have resolved https://github.com/cockroachdb/cockroach/issues/437,
 		// replace this with something that reliably generates the first valid key in the range.
@@ -143,6 +145,10 @@ func verifyRDReplicatedOnlyMVCCIter(
 				Key:    keys.MakeRangeKeyPrefix(desc.StartKey),
 				EndKey: keys.MakeRangeKeyPrefix(desc.EndKey),
 			})
+			spans.AddNonMVCC(spanset.SpanReadOnly, roachpb.Span{
+				Key:    keys.MVCCRangeTombstoneKey(desc.StartKey.AsRawKey()),
+				EndKey: keys.MVCCRangeTombstoneKey(desc.EndKey.AsRawKey()),
+			})
 			spans.AddMVCC(spanset.SpanReadOnly, roachpb.Span{
 				Key:    desc.StartKey.AsRawKey(),
 				EndKey: desc.EndKey.AsRawKey(),
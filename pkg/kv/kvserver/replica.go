@@ -761,6 +761,15 @@ func (r *Replica) Engine() storage.Engine {
 	return r.store.Engine()
 }
 
+// RaftLogEngine returns the Store's dedicated Raft log engine, or nil if the
+// Raft log is kept in Engine().
+func (r *Replica) RaftLogEngine() storage.Engine {
+	if !r.store.separateRaftEngine() {
+		return nil
+	}
+	return r.store.raftEngine
+}
+
 // AbortSpan returns the Replica's AbortSpan.
 func (r *Replica) AbortSpan() *abortspan.AbortSpan {
 	// Despite its name, the AbortSpan doesn't hold on-disk data in
@@ -940,6 +949,28 @@ func (r *Replica) GetSplitQPS() float64 {
 	return r.loadBasedSplitter.LastQPS(timeutil.Now())
 }
 
+// GetMaxSplitQPS returns the Replica's maximum queries/s request rate over the
+// kv.range_split.by_load_merge_delay period. The boolean is false if the
+// range was recently split due to load and the replica has not been collecting
+// measurements for the whole period since.
+//
+// NOTE: This should only be used for load based splitting and merging, only
+// works when the load based splitting cluster setting is enabled.
+func (r *Replica) GetMaxSplitQPS() (float64, bool) {
+	return r.loadBasedSplitter.MaxQPS(timeutil.Now())
+}
+
+// GetLocalityQPS returns the Replica's queries/s rate by the locality of the
+// node the requests were sent from, as used for lease placement. Requests
+// from nodes with an unknown locality are reported under the empty locality.
+func (r *Replica) GetLocalityQPS() map[string]float64 {
+	if r.leaseholderStats == nil {
+		return nil
+	}
+	qps, _ := r.leaseholderStats.perLocalityDecayingQPS()
+	return qps
+}
+
 // ContainsKey returns whether this range contains the specified key.
 //
 // TODO(bdarnell): This is not the same as RangeDescriptor.ContainsKey.
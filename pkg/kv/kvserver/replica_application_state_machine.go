@@ -366,6 +366,10 @@ type replicaAppBatch struct {
 
 	// batch accumulates writes implied by the raft entries in this batch.
 	batch storage.Batch
+	// raftBatch accumulates Raft log truncations when the store uses a
+	// dedicated Raft log engine. It is created lazily and committed after
+	// batch, see handleTruncatedStateBelowRaft.
+	raftBatch storage.Batch
 	// state is this batch's view of the replica's state. It is copied from
 	// under the Replica.mu when the batch is initialized and is updated in
 	// stageTrivialReplicatedEvalResult.
@@ -701,6 +705,7 @@ func (b *replicaAppBatch) runPreApplyTriggersAfterStagingWriteBatch(
 	if res.State != nil && res.State.TruncatedState != nil {
 		if apply, err := handleTruncatedStateBelowRaft(
 			ctx, b.state.TruncatedState, res.State.TruncatedState, b.r.raftMu.stateLoader, b.batch,
+			b.raftLogWriter(),
 		); err != nil {
 			return wrapWithNonDeterministicFailure(err, "unable to handle truncated state")
 		} else if !apply {
@@ -845,6 +850,16 @@ func (b *replicaAppBatch) ApplyToStateMachine(ctx context.Context) error {
 	}
 	b.batch.Close()
 	b.batch = nil
+	if b.raftBatch != nil {
+		// The truncated state has been durably advanced above, so the entries
+		// removed here are no longer referenced. A crash before this commit
+		// leaves them behind for the sweep on the next start.
+		if err := b.raftBatch.Commit(false /* sync */); err != nil {
+			return wrapWithNonDeterministicFailure(err, "unable to commit Raft log truncation batch")
+		}
+		b.raftBatch.Close()
+		b.raftBatch = nil
+	}
 
 	// Update the replica's applied indexes and mvcc stats.
 	r.mu.Lock()
@@ -946,11 +961,26 @@ func (b *replicaAppBatch) recordStatsOnCommit() {
 	b.r.store.metrics.RaftCommandCommitLatency.RecordValue(elapsed.Nanoseconds())
 }
 
+// raftLogWriter returns the writer to which Raft log truncations are staged.
+// This is the batch itself, unless the store uses a dedicated Raft log engine.
+func (b *replicaAppBatch) raftLogWriter() storage.Writer {
+	if !b.r.store.separateRaftEngine() {
+		return b.batch
+	}
+	if b.raftBatch == nil {
+		b.raftBatch = b.r.store.raftEngine.NewWriteOnlyBatch()
+	}
+	return b.raftBatch
+}
+
 // Close implements the apply.Batch interface.
 func (b *replicaAppBatch) Close() {
 	if b.batch != nil {
 		b.batch.Close()
 	}
+	if b.raftBatch != nil {
+		b.raftBatch.Close()
+	}
 	*b = replicaAppBatch{}
 }
 
@@ -2018,6 +2018,17 @@ func (r *Replica) sendSnapshot(
 		r.reportSnapshotStatus(ctx, recipient.ReplicaID, retErr)
 	}()
 
+	// If a follower is closer to the recipient, let it send the snapshot. If
+	// that fails, fall back to sending the snapshot from here.
+	if req, ok := r.makeDelegateSnapshotRequest(ctx, recipient, snapType, priority); ok {
+		err := r.delegateSnapshot(ctx, req)
+		if err == nil {
+			return nil
+		}
+		log.Infof(ctx, "failed to delegate snapshot to %s, sending it directly: %v",
+			req.DelegatedSender, err)
+	}
+
 	snap, err := r.GetSnapshot(ctx, snapType, recipient.StoreID)
 	if err != nil {
 		return errors.Wrapf(err, "%s: failed to generate %s snapshot", r, snapType)
@@ -2048,7 +2059,20 @@ func (r *Replica) sendSnapshot(
 		// haven't woken up yet.
 		return &benignError{errors.New("raft status not initialized")}
 	}
+	return r.streamSnapshot(ctx, snap, sender, recipient, status.Term, snapType, priority)
+}
 
+// streamSnapshot streams the snapshot to the recipient. The snapshot is sent
+// as a Raft message of the given term from the sender, which is either this
+// replica or the Raft leader on whose behalf this replica sends the snapshot.
+func (r *Replica) streamSnapshot(
+	ctx context.Context,
+	snap *OutgoingSnapshot,
+	sender, recipient roachpb.ReplicaDescriptor,
+	term uint64,
+	snapType SnapshotRequest_Type,
+	priority SnapshotRequest_Priority,
+) error {
 	usesReplicatedTruncatedState, err := storage.MVCCGetProto(
 		ctx, snap.EngineSnap, keys.RaftTruncatedStateLegacyKey(r.RangeID), hlc.Timestamp{}, nil, storage.MVCCGetOptions{},
 	)
@@ -2093,7 +2117,7 @@ func (r *Replica) sendSnapshot(
 				Type:     raftpb.MsgSnap,
 				To:       uint64(recipient.ReplicaID),
 				From:     uint64(sender.ReplicaID),
-				Term:     status.Term,
+				Term:     term,
 				Snapshot: snap.RaftSnap,
 			},
 		},
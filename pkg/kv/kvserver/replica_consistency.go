@@ -11,6 +11,7 @@
 package kvserver
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha512"
 	"encoding/binary"
@@ -23,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/rditer"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -74,8 +76,10 @@ type ReplicaChecksum struct {
 //
 // When args.Mode is CHECK_VIA_QUEUE and an inconsistency is detected and no
 // diff was requested, the consistency check will be re-run to collect a diff,
-// which is then printed before calling `log.Fatal`. This behavior should be
-// lifted to the consistency checker queue in the future.
+// which is then printed before calling `log.Fatal`. If repairs are enabled and
+// the leaseholder agrees with a majority of the replicas, the divergent
+// replicas are replaced instead, see repairInconsistentReplicas. This behavior
+// should be lifted to the consistency checker queue in the future.
 func (r *Replica) CheckConsistency(
 	ctx context.Context, args roachpb.CheckConsistencyRequest,
 ) (roachpb.CheckConsistencyResponse, *roachpb.Error) {
@@ -278,6 +282,36 @@ func (r *Replica) CheckConsistency(
 	// branch above.
 	args.WithDiff = true
 	args.Checkpoint = true
+
+	// We've noticed in practice that if the snapshot diff is large, the
+	// log file to which it is printed is promptly rotated away, so up
+	// the limits while the diff printing occurs.
+	//
+	// See:
+	// https://github.com/cockroachdb/cockroach/issues/36861
+	defer log.TemporarilyDisableFileGCForMainLogger()()
+
+	// If enabled, replace the divergent replicas instead of terminating the
+	// nodes they're on. The checkpoints and the diff are kept for
+	// investigation all the same.
+	if consistencyCheckRepairEnabled.Get(&r.store.ClusterSettings().SV) {
+		if repair := replicasToRepair(results); len(repair) > 0 {
+			{
+				var tmp redact.SafeFormatter = roachpb.MakeReplicaDescriptors(repair)
+				log.Errorf(ctx, "consistency check failed; fetching details and repairing %v", tmp)
+			}
+			if _, pErr := r.CheckConsistency(ctx, args); pErr != nil {
+				log.Errorf(ctx, "replica inconsistency detected; could not obtain actual diff: %s", pErr)
+			}
+			if err := r.repairInconsistentReplicas(ctx, repair); err != nil {
+				return resp, roachpb.NewError(err)
+			}
+			resp.Result[0].Detail += fmt.Sprintf("repaired %s\n", roachpb.MakeReplicaDescriptors(repair))
+			return resp, nil
+		}
+		log.Errorf(ctx, "consistency check failed; unable to tell the divergent replicas apart")
+	}
+
 	for _, idxs := range shaToIdxs[minoritySHA] {
 		args.Terminate = append(args.Terminate, results[idxs].Replica)
 	}
@@ -292,14 +326,6 @@ func (r *Replica) CheckConsistency(
 		log.Errorf(ctx, "consistency check failed; fetching details and shutting down minority %v", tmp)
 	}
 
-	// We've noticed in practice that if the snapshot diff is large, the
-	// log file to which it is printed is promptly rotated away, so up
-	// the limits while the diff printing occurs.
-	//
-	// See:
-	// https://github.com/cockroachdb/cockroach/issues/36861
-	defer log.TemporarilyDisableFileGCForMainLogger()()
-
 	if _, pErr := r.CheckConsistency(ctx, args); pErr != nil {
 		log.Errorf(ctx, "replica inconsistency detected; could not obtain actual diff: %s", pErr)
 	}
@@ -307,6 +333,71 @@ func (r *Replica) CheckConsistency(
 	return resp, nil
 }
 
+// replicasToRepair returns the replicas whose checksum differs from that of
+// the local replica (the leaseholder), provided that the local replica's
+// checksum is shared by a strict majority of the replicas of the range,
+// including those whose checksum could not be collected. Otherwise, or if one
+// of the divergent replicas is not a full voter or a non-voter, the healthy
+// replicas can't be safely told apart from the divergent ones and nil is
+// returned.
+func replicasToRepair(results []ConsistencyCheckResult) []roachpb.ReplicaDescriptor {
+	localSHA := results[0].Response.Checksum
+	if len(localSHA) == 0 {
+		return nil
+	}
+	var agree int
+	var divergent []roachpb.ReplicaDescriptor
+	for _, result := range results {
+		if result.Err != nil || len(result.Response.Checksum) == 0 {
+			continue
+		}
+		if bytes.Equal(result.Response.Checksum, localSHA) {
+			agree++
+			continue
+		}
+		if typ := result.Replica.GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
+			return nil
+		}
+		divergent = append(divergent, result.Replica)
+	}
+	if agree*2 <= len(results) {
+		return nil
+	}
+	return divergent
+}
+
+// repairInconsistentReplicas replaces the given replicas, whose data diverged
+// from that of the leaseholder, with new replicas on the same stores. Removing
+// a replica from the range causes it to be garbage collected, and the replica
+// added in its place is populated by a snapshot from the leaseholder. Both
+// changes are recorded in the range log. If the replica can't be added back,
+// the replicate queue will up-replicate the range.
+func (r *Replica) repairInconsistentReplicas(
+	ctx context.Context, replicas []roachpb.ReplicaDescriptor,
+) error {
+	for _, rDesc := range replicas {
+		target := roachpb.ReplicationTarget{NodeID: rDesc.NodeID, StoreID: rDesc.StoreID}
+		removeType, addType := roachpb.REMOVE_VOTER, roachpb.ADD_VOTER
+		if rDesc.GetType() == roachpb.NON_VOTER {
+			removeType, addType = roachpb.REMOVE_NON_VOTER, roachpb.ADD_NON_VOTER
+		}
+		details := fmt.Sprintf("replica %s diverged from the leaseholder and a majority of the replicas", rDesc)
+		desc, err := r.ChangeReplicas(ctx, r.Desc(), SnapshotRequest_RECOVERY,
+			kvserverpb.ReasonReplicaInconsistent, details,
+			roachpb.MakeReplicationChanges(removeType, target))
+		if err != nil {
+			return errors.Wrapf(err, "removing inconsistent replica %s", rDesc)
+		}
+		if _, err := r.ChangeReplicas(ctx, desc, SnapshotRequest_RECOVERY,
+			kvserverpb.ReasonReplicaInconsistent, details,
+			roachpb.MakeReplicationChanges(addType, target)); err != nil {
+			return errors.Wrapf(err, "replacing inconsistent replica %s", rDesc)
+		}
+		log.Warningf(ctx, "replaced inconsistent replica %s", rDesc)
+	}
+	return nil
+}
+
 // A ConsistencyCheckResult contains the outcome of a CollectChecksum call.
 type ConsistencyCheckResult struct {
 	Replica  roachpb.ReplicaDescriptor
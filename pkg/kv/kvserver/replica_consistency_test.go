@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -118,3 +119,80 @@ func TestGetChecksumNotSuccessfulExitConditions(t *testing.T) {
 	}
 	require.Nil(t, rc.Checksum)
 }
+
+func TestReplicasToRepair(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	nonVoter := roachpb.NON_VOTER
+	learner := roachpb.LEARNER
+	result := func(id int, checksum string, typ *roachpb.ReplicaType) ConsistencyCheckResult {
+		res := ConsistencyCheckResult{Replica: roachpb.ReplicaDescriptor{
+			NodeID:    roachpb.NodeID(id),
+			StoreID:   roachpb.StoreID(id),
+			ReplicaID: roachpb.ReplicaID(id),
+			Type:      typ,
+		}}
+		if checksum == "" {
+			res.Err = errors.New("unreachable")
+		} else {
+			res.Response.Checksum = []byte(checksum)
+		}
+		return res
+	}
+	ids := func(descs []roachpb.ReplicaDescriptor) []roachpb.ReplicaID {
+		var res []roachpb.ReplicaID
+		for _, desc := range descs {
+			res = append(res, desc.ReplicaID)
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		name    string
+		results []ConsistencyCheckResult
+		exp     []roachpb.ReplicaID
+	}{
+		{
+			name:    "minority follower",
+			results: []ConsistencyCheckResult{result(1, "a", nil), result(2, "b", nil), result(3, "a", nil)},
+			exp:     []roachpb.ReplicaID{2},
+		},
+		{
+			name:    "minority leaseholder",
+			results: []ConsistencyCheckResult{result(1, "a", nil), result(2, "b", nil), result(3, "b", nil)},
+		},
+		{
+			name:    "no majority",
+			results: []ConsistencyCheckResult{result(1, "a", nil), result(2, "b", nil), result(3, "c", nil)},
+		},
+		{
+			name:    "unreachable replica",
+			results: []ConsistencyCheckResult{result(1, "a", nil), result(2, "b", nil), result(3, "", nil)},
+		},
+		{
+			name: "unreachable replica with majority",
+			results: []ConsistencyCheckResult{
+				result(1, "a", nil), result(2, "b", nil), result(3, "", nil), result(4, "a", nil), result(5, "a", nil),
+			},
+			exp: []roachpb.ReplicaID{2},
+		},
+		{
+			name: "minority non-voter",
+			results: []ConsistencyCheckResult{
+				result(1, "a", nil), result(2, "a", nil), result(3, "a", nil), result(4, "b", &nonVoter),
+			},
+			exp: []roachpb.ReplicaID{4},
+		},
+		{
+			name: "minority learner",
+			results: []ConsistencyCheckResult{
+				result(1, "a", nil), result(2, "a", nil), result(3, "a", nil), result(4, "b", &learner),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exp, ids(replicasToRepair(tc.results)))
+		})
+	}
+}
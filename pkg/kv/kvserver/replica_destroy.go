@@ -112,6 +112,16 @@ func (r *Replica) postDestroyRaftMuLocked(ctx context.Context, ms enginepb.MVCCS
 	// directories belonging to replicas which aren't present. A crash before a
 	// call to postDestroyRaftMuLocked will currently leave the files around
 	// forever.
+	if r.store.separateRaftEngine() {
+		// The Raft state in the state engine is gone, so the log entries in the
+		// Raft log engine are unreferenced. A crash before this point leaves
+		// them behind for sweepRaftLogEngine. Any sideloaded payloads are
+		// removed together with the sideloaded storage below.
+		prefix := keys.RaftLogPrefix(r.RangeID)
+		if err := r.store.raftEngine.ClearRawRange(prefix, prefix.PrefixEnd()); err != nil {
+			return err
+		}
+	}
 	if r.raftMu.sideloaded != nil {
 		return r.raftMu.sideloaded.Clear(ctx)
 	}
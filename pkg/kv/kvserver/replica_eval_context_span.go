@@ -96,6 +96,11 @@ func (rec *SpanSetReplicaEvalContext) Engine() storage.Engine {
 	return rec.i.Engine()
 }
 
+// RaftLogEngine returns the dedicated Raft log engine, if any.
+func (rec *SpanSetReplicaEvalContext) RaftLogEngine() storage.Engine {
+	return rec.i.RaftLogEngine()
+}
+
 // GetFirstIndex returns the first index.
 func (rec *SpanSetReplicaEvalContext) GetFirstIndex() (uint64, error) {
 	return rec.i.GetFirstIndex()
@@ -153,6 +158,17 @@ func (rec SpanSetReplicaEvalContext) GetSplitQPS() float64 {
 	return rec.i.GetSplitQPS()
 }
 
+// GetMaxSplitQPS returns the Replica's maximum queries/s rate for splitting
+// and merging purposes.
+func (rec SpanSetReplicaEvalContext) GetMaxSplitQPS() (float64, bool) {
+	return rec.i.GetMaxSplitQPS()
+}
+
+// GetLocalityQPS returns the Replica's queries/s rate by request locality.
+func (rec SpanSetReplicaEvalContext) GetLocalityQPS() map[string]float64 {
+	return rec.i.GetLocalityQPS()
+}
+
 // CanCreateTxnRecord determines whether a transaction record can be created
 // for the provided transaction information. See Replica.CanCreateTxnRecord
 // for details about its arguments, return values, and preconditions.
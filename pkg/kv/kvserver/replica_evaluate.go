@@ -152,6 +152,7 @@ func evaluateBatch(
 	ba *roachpb.BatchRequest,
 	readOnly bool,
 ) (_ *roachpb.BatchResponse, _ result.Result, retErr *roachpb.Error) {
+	defer startEvalDuration(ctx)()
 
 	defer func() {
 		// Ensure that errors don't carry the WriteTooOld flag set. The client
@@ -92,6 +92,8 @@ func newUnloadedReplica(
 	r.mu.replicaID = replicaID
 	split.Init(&r.loadBasedSplitter, rand.Intn, func() float64 {
 		return float64(SplitByLoadQPSThreshold.Get(&store.cfg.Settings.SV))
+	}, func() time.Duration {
+		return splitByLoadMergeDelay(&store.cfg.Settings.SV)
 	})
 	r.mu.proposals = map[kvserverbase.CmdIDKey]*ProposalData{}
 	r.mu.checksums = map[uuid.UUID]ReplicaChecksum{}
@@ -152,7 +154,7 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 	if r.mu.state, err = r.mu.stateLoader.Load(ctx, r.Engine(), desc); err != nil {
 		return err
 	}
-	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine())
+	r.mu.lastIndex, err = r.mu.stateLoader.LoadLastIndex(ctx, r.Engine(), r.store.raftEngine)
 	if err != nil {
 		return err
 	}
@@ -192,7 +194,11 @@ func (r *Replica) loadRaftMuLockedReplicaMuLocked(desc *roachpb.RangeDescriptor)
 	}
 
 	ssBase := r.Engine().GetAuxiliaryDir()
-	if r.raftMu.sideloaded, err = newDiskSideloadStorage(
+	if r.store.separateRaftEngine() {
+		r.raftMu.sideloaded = newEngineSideloadStorage(
+			desc.RangeID, ssBase, r.store.limiters.BulkIOWriteRate, r.store.raftEngine,
+		)
+	} else if r.raftMu.sideloaded, err = newDiskSideloadStorage(
 		r.store.cfg.Settings,
 		desc.RangeID,
 		replicaID,
@@ -388,6 +388,11 @@ func (r *Replica) leasePostApply(ctx context.Context, newLease roachpb.Lease, pe
 		if r.leaseholderStats != nil {
 			r.leaseholderStats.resetRequestCounts()
 		}
+
+		// Likewise reset the load based splitting state. This replica hasn't
+		// been serving most of the range's requests before acquiring the lease,
+		// so its QPS history doesn't reflect the load of the range.
+		r.loadBasedSplitter.Reset(timeutil.Now())
 	}
 
 	// Inform the concurrency manager that the lease holder has been updated.
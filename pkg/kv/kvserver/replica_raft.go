@@ -658,9 +658,20 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	// which passes the reads through to the underlying DB.
 	batch := r.store.Engine().NewWriteOnlyBatch()
 	defer batch.Close()
+	// With a dedicated Raft log engine, the log entries go into a batch of
+	// their own, which is committed ahead of the one holding the HardState.
+	raftBatch := batch
+	if r.store.separateRaftEngine() {
+		raftBatch = r.store.raftEngine.NewWriteOnlyBatch()
+		defer raftBatch.Close()
+	}
 
 	// We know that all of the writes from here forward will be to distinct keys.
 	writer := batch.Distinct()
+	raftWriter := writer
+	if raftBatch != batch {
+		raftWriter = raftBatch.Distinct()
+	}
 	prevLastIndex := lastIndex
 	if len(rd.Entries) > 0 {
 		// All of the entries are appended to distinct keys, returning a new
@@ -672,7 +683,7 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 		}
 		raftLogSize += sideLoadedEntriesSize
 		if lastIndex, lastTerm, raftLogSize, err = r.append(
-			ctx, writer, lastIndex, lastTerm, raftLogSize, thinEntries,
+			ctx, raftWriter, lastIndex, lastTerm, raftLogSize, thinEntries,
 		); err != nil {
 			const expl = "during append"
 			return stats, expl, errors.Wrap(err, expl)
@@ -688,14 +699,18 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 		// Ready. If we persist the HardState but happen to lose the Entries,
 		// assertions can be tripped.
 		//
-		// We have both in the same batch, so there's no problem. If that ever
-		// changes, we must write and sync the Entries before the HardState.
+		// We have both in the same batch, so there's no problem, unless the
+		// store uses a dedicated Raft log engine. In that case, the Entries are
+		// written and synced before the HardState below.
 		if err := r.raftMu.stateLoader.SetHardState(ctx, writer, rd.HardState); err != nil {
 			const expl = "during setHardState"
 			return stats, expl, errors.Wrap(err, expl)
 		}
 	}
 	writer.Close()
+	if raftWriter != writer {
+		raftWriter.Close()
+	}
 	// Synchronously commit the batch with the Raft log entries and Raft hard
 	// state as we're promising not to lose this data.
 	//
@@ -710,9 +725,18 @@ func (r *Replica) handleRaftReadyRaftMuLocked(
 	// were not persisted to disk, it wouldn't be a problem because raft does not
 	// infer the that entries are persisted on the node that sends a snapshot.
 	commitStart := timeutil.Now()
-	if err := batch.Commit(rd.MustSync && !disableSyncRaftLog.Get(&r.store.cfg.Settings.SV)); err != nil {
-		const expl = "while committing batch"
-		return stats, expl, errors.Wrap(err, expl)
+	sync := rd.MustSync && !disableSyncRaftLog.Get(&r.store.cfg.Settings.SV)
+	if raftBatch != batch {
+		if err := raftBatch.Commit(sync); err != nil {
+			const expl = "while committing raft log batch"
+			return stats, expl, errors.Wrap(err, expl)
+		}
+	}
+	if raftBatch == batch || !batch.Empty() {
+		if err := batch.Commit(sync); err != nil {
+			const expl = "while committing batch"
+			return stats, expl, errors.Wrap(err, expl)
+		}
 	}
 	if rd.MustSync {
 		elapsed := timeutil.Since(commitStart)
@@ -1672,6 +1696,7 @@ func handleTruncatedStateBelowRaft(
 	oldTruncatedState, newTruncatedState *roachpb.RaftTruncatedState,
 	loader stateloader.StateLoader,
 	readWriter storage.ReadWriter,
+	raftLogWriter storage.Writer,
 ) (_apply bool, _ error) {
 	// If this is a log truncation, load the resulting unreplicated or legacy
 	// replicated truncated state (in that order). If the migration is happening
@@ -1698,12 +1723,17 @@ func handleTruncatedStateBelowRaft(
 	// deletion tombstones. There is a chance that ClearRange will
 	// perform well here because the tombstones could be "collapsed",
 	// but it is hardly worth the risk at this point.
+	//
+	// When the store uses a dedicated Raft log engine, raftLogWriter targets
+	// that engine and is committed only after readWriter, so that a crash in
+	// between leaves (harmless) entries below the truncated index rather than
+	// a gap above it. See Store.sweepRaftLogEngine.
 	prefixBuf := &loader.RangeIDPrefixBuf
 	for idx := oldTruncatedState.Index + 1; idx <= newTruncatedState.Index; idx++ {
 		// NB: RangeIDPrefixBufs have sufficient capacity (32 bytes) to
 		// avoid allocating when constructing Raft log keys (16 bytes).
 		unsafeKey := prefixBuf.RaftLogKey(idx)
-		if err := readWriter.ClearUnversioned(unsafeKey); err != nil {
+		if err := raftLogWriter.ClearUnversioned(unsafeKey); err != nil {
 			return false, errors.Wrapf(err, "unable to clear truncated Raft entries for %+v", newTruncatedState)
 		}
 	}
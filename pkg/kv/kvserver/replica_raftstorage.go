@@ -77,29 +77,49 @@ func (r *replicaRaftStorage) InitialState() (raftpb.HardState, raftpb.ConfState,
 // and this method will always return at least one entry even if it exceeds
 // maxBytes. Sideloaded proposals count towards maxBytes with their payloads inlined.
 func (r *replicaRaftStorage) Entries(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
-	readonly := r.store.Engine().NewReadOnly()
+	readonly, raftReadonly := r.newRaftReadOnly()
 	defer readonly.Close()
+	if raftReadonly != readonly {
+		defer raftReadonly.Close()
+	}
 	ctx := r.AnnotateCtx(context.TODO())
 	if r.raftMu.sideloaded == nil {
 		return nil, errors.New("sideloaded storage is uninitialized")
 	}
-	return entries(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache,
+	return entries(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache,
 		r.raftMu.sideloaded, lo, hi, maxBytes)
 }
 
+// newRaftReadOnly returns read-only views of the state engine and of the
+// engine holding the Raft log. Unless the store uses a dedicated Raft log
+// engine, the two are the same and must only be closed once.
+func (r *replicaRaftStorage) newRaftReadOnly() (storage.ReadWriter, storage.ReadWriter) {
+	readonly := r.store.Engine().NewReadOnly()
+	if !r.store.separateRaftEngine() {
+		return readonly, readonly
+	}
+	return readonly, r.store.raftEngine.NewReadOnly()
+}
+
 // raftEntriesLocked requires that r.mu is held.
 func (r *Replica) raftEntriesLocked(lo, hi, maxBytes uint64) ([]raftpb.Entry, error) {
 	return (*replicaRaftStorage)(r).Entries(lo, hi, maxBytes)
 }
 
-// entries retrieves entries from the engine. To accommodate loading the term,
+// entries retrieves entries from the engine. The log entries are read from
+// raftReader while the truncated state is read from reader; these are the same
+// unless the store uses a dedicated Raft log engine. In that case, the Raft log
+// engine may still contain entries at or below the TruncatedState, which are
+// removed after it is advanced, or which belonged to the log replaced by a
+// snapshot. These are not part of the log anymore, so they are neither
+// returned nor added to the raft entry cache. To accommodate loading the term,
 // `sideloaded` can be supplied as nil, in which case sideloaded entries will
 // not be inlined, the raft entry cache will not be populated with *any* of the
 // loaded entries, and maxBytes will not be applied to the payloads.
 func entries(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	reader storage.Reader,
+	reader, raftReader storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	sideloaded SideloadStorage,
@@ -109,6 +129,16 @@ func entries(
 		return nil, errors.Errorf("lo:%d is greater than hi:%d", lo, hi)
 	}
 
+	if raftReader != reader {
+		ts, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
+		if err != nil {
+			return nil, err
+		}
+		if lo <= ts.Index {
+			return nil, raft.ErrCompacted
+		}
+	}
+
 	n := hi - lo
 	if n > 100 {
 		n = 100
@@ -175,7 +205,7 @@ func entries(
 		return nil
 	}
 
-	if err := iterateEntries(ctx, reader, rangeID, expectedIndex, hi, scanFunc); err != nil {
+	if err := iterateEntries(ctx, raftReader, rangeID, expectedIndex, hi, scanFunc); err != nil {
 		return nil, err
 	}
 	// Cache the fetched entries, if we may.
@@ -201,7 +231,7 @@ func entries(
 		}
 
 		// Was the missing index after the last index?
-		lastIndex, err := rsl.LoadLastIndex(ctx, reader)
+		lastIndex, err := rsl.LoadLastIndex(ctx, reader, raftReader)
 		if err != nil {
 			return nil, err
 		}
@@ -260,10 +290,13 @@ func (r *replicaRaftStorage) Term(i uint64) (uint64, error) {
 	if e, ok := r.store.raftEntryCache.Get(r.RangeID, i); ok {
 		return e.Term, nil
 	}
-	readonly := r.store.Engine().NewReadOnly()
+	readonly, raftReadonly := r.newRaftReadOnly()
 	defer readonly.Close()
+	if raftReadonly != readonly {
+		defer raftReadonly.Close()
+	}
 	ctx := r.AnnotateCtx(context.TODO())
-	return term(ctx, r.mu.stateLoader, readonly, r.RangeID, r.store.raftEntryCache, i)
+	return term(ctx, r.mu.stateLoader, readonly, raftReadonly, r.RangeID, r.store.raftEntryCache, i)
 }
 
 // raftTermLocked requires that r.mu is locked for reading.
@@ -274,14 +307,14 @@ func (r *Replica) raftTermRLocked(i uint64) (uint64, error) {
 func term(
 	ctx context.Context,
 	rsl stateloader.StateLoader,
-	reader storage.Reader,
+	reader, raftReader storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	i uint64,
 ) (uint64, error) {
 	// entries() accepts a `nil` sideloaded storage and will skip inlining of
 	// sideloaded entries. We only need the term, so this is what we do.
-	ents, err := entries(ctx, rsl, reader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
+	ents, err := entries(ctx, rsl, reader, raftReader, rangeID, eCache, nil /* sideloaded */, i, i+1, math.MaxUint64 /* maxBytes */)
 	if errors.Is(err, raft.ErrCompacted) {
 		ts, _, err := rsl.LoadRaftTruncatedState(ctx, reader)
 		if err != nil {
@@ -402,6 +435,12 @@ func (r *Replica) GetSnapshot(
 	// the corresponding Raft command not applied yet).
 	r.raftMu.Lock()
 	snap := r.store.engine.NewSnapshot()
+	raftSnap := snap
+	if r.store.separateRaftEngine() {
+		// Since raftMu is held, no Raft log writes or truncations can be in
+		// flight, so this is consistent with the state engine snapshot.
+		raftSnap = r.store.raftEngine.NewSnapshot()
+	}
 	r.mu.Lock()
 	appliedIndex := r.mu.state.RaftAppliedIndex
 	// Cleared when OutgoingSnapshot closes.
@@ -418,6 +457,9 @@ func (r *Replica) GetSnapshot(
 		if err != nil {
 			release()
 			snap.Close()
+			if raftSnap != snap {
+				raftSnap.Close()
+			}
 		}
 	}()
 
@@ -444,7 +486,7 @@ func (r *Replica) GetSnapshot(
 	// create a new state loader.
 	snapData, err := snapshot(
 		ctx, snapUUID, stateloader.Make(rangeID), snapType,
-		snap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
+		snap, raftSnap, rangeID, r.store.raftEntryCache, withSideloaded, startKey,
 	)
 	if err != nil {
 		log.Errorf(ctx, "error generating snapshot: %+v", err)
@@ -463,6 +505,9 @@ type OutgoingSnapshot struct {
 	RaftSnap raftpb.Snapshot
 	// The RocksDB snapshot that will be streamed from.
 	EngineSnap storage.Reader
+	// The snapshot of the Raft log engine. Same as EngineSnap unless the store
+	// uses a dedicated Raft log engine.
+	RaftEngineSnap storage.Reader
 	// The complete range iterator for the snapshot to stream.
 	Iter *rditer.ReplicaEngineDataIterator
 	// The replica state within the snapshot.
@@ -490,6 +535,9 @@ func (s *OutgoingSnapshot) SafeFormat(w redact.SafePrinter, _ rune) {
 func (s *OutgoingSnapshot) Close() {
 	s.Iter.Close()
 	s.EngineSnap.Close()
+	if s.RaftEngineSnap != nil && s.RaftEngineSnap != s.EngineSnap {
+		s.RaftEngineSnap.Close()
+	}
 	if s.onClose != nil {
 		s.onClose()
 	}
@@ -527,12 +575,13 @@ func (s *IncomingSnapshot) SafeFormat(w redact.SafePrinter, _ rune) {
 
 // snapshot creates an OutgoingSnapshot containing a rocksdb snapshot for the
 // given range. Note that snapshot() is called without Replica.raftMu held.
+// The Raft log is read from raftSnap, which is usually the same as snap.
 func snapshot(
 	ctx context.Context,
 	snapUUID uuid.UUID,
 	rsl stateloader.StateLoader,
 	snapType SnapshotRequest_Type,
-	snap storage.Reader,
+	snap, raftSnap storage.Reader,
 	rangeID roachpb.RangeID,
 	eCache *raftentry.Cache,
 	withSideloaded func(func(SideloadStorage) error) error,
@@ -558,7 +607,7 @@ func snapshot(
 		return OutgoingSnapshot{}, err
 	}
 
-	term, err := term(ctx, rsl, snap, rangeID, eCache, appliedIndex)
+	term, err := term(ctx, rsl, snap, raftSnap, rangeID, eCache, appliedIndex)
 	if err != nil {
 		return OutgoingSnapshot{}, errors.Errorf("failed to fetch term of %d: %s", appliedIndex, err)
 	}
@@ -576,6 +625,7 @@ func snapshot(
 		RaftEntryCache: eCache,
 		WithSideloaded: withSideloaded,
 		EngineSnap:     snap,
+		RaftEngineSnap: raftSnap,
 		Iter:           iter,
 		State:          state,
 		SnapUUID:       snapUUID,
@@ -861,10 +911,28 @@ func (r *Replica) applySnapshot(
 		return errors.Wrapf(err, "unable to write HardState to unreplicated SST writer")
 	}
 
+	// With a dedicated Raft log engine, the unreplicated state cleared above
+	// doesn't include the Raft log. Instead of replacing the log with the
+	// entries in the snapshot, the replica starts out with an empty log that
+	// is truncated at the snapshot index. The log entries past the snapshot
+	// index are removed (durably) before the new state is ingested, as they
+	// may not match the new TruncatedState. The remaining entries are
+	// unreferenced once the new state is ingested and are removed afterwards.
+	separateRaftEngine := r.store.separateRaftEngine()
+	if separateRaftEngine {
+		if err := r.clearRaftLogEngineRaftMuLocked(snap.Metadata.Index+1, 0 /* hi */, true /* sync */); err != nil {
+			return errors.Wrapf(err, "unable to clear Raft log")
+		}
+		s.TruncatedState = &roachpb.RaftTruncatedState{
+			Index: snap.Metadata.Index,
+			Term:  snap.Metadata.Term,
+		}
+	}
+
 	// Update Raft entries.
 	var lastTerm uint64
 	var raftLogSize int64
-	if len(inSnap.LogEntries) > 0 {
+	if len(inSnap.LogEntries) > 0 && !separateRaftEngine {
 		logEntries := make([]raftpb.Entry, len(inSnap.LogEntries))
 		for i, bytes := range inSnap.LogEntries {
 			if err := protoutil.Unmarshal(bytes, &logEntries[i]); err != nil {
@@ -887,8 +955,10 @@ func (r *Replica) applySnapshot(
 	}
 	r.store.raftEntryCache.Drop(r.RangeID)
 
-	// Update TruncatedState if it is unreplicated.
-	if inSnap.UsesUnreplicatedTruncatedState {
+	// Update TruncatedState if it is unreplicated. With a dedicated Raft log
+	// engine, the unreplicated key is written regardless, and takes precedence
+	// over the replicated legacy key.
+	if inSnap.UsesUnreplicatedTruncatedState || separateRaftEngine {
 		if err := r.raftMu.stateLoader.SetRaftTruncatedState(
 			ctx, &unreplicatedSST, s.TruncatedState,
 		); err != nil {
@@ -912,7 +982,7 @@ func (r *Replica) applySnapshot(
 			s.RaftAppliedIndex, snap.Metadata.Index)
 	}
 
-	if expLen := s.RaftAppliedIndex - s.TruncatedState.Index; expLen != uint64(len(inSnap.LogEntries)) {
+	if expLen := s.RaftAppliedIndex - inSnap.State.TruncatedState.Index; expLen != uint64(len(inSnap.LogEntries)) {
 		entriesRange, err := extractRangeFromEntries(inSnap.LogEntries)
 		if err != nil {
 			return err
@@ -928,7 +998,7 @@ func (r *Replica) applySnapshot(
 
 		log.Fatalf(ctx, "missing log entries in snapshot (%s): got %d entries, expected %d "+
 			"(TruncatedState.Index=%d, HardState=%s, LogEntries=%s)",
-			inSnap.String(), len(inSnap.LogEntries), expLen, s.TruncatedState.Index,
+			inSnap.String(), len(inSnap.LogEntries), expLen, inSnap.State.TruncatedState.Index,
 			hs.String(), entriesRange)
 	}
 
@@ -955,6 +1025,13 @@ func (r *Replica) applySnapshot(
 	}
 	stats.ingestion = timeutil.Now()
 
+	if separateRaftEngine {
+		// A crash before this point leaves the entries for sweepRaftLogEngine.
+		if err := r.clearRaftLogEngineRaftMuLocked(0 /* lo */, snap.Metadata.Index+1, false /* sync */); err != nil {
+			log.Fatalf(ctx, "unable to clear Raft log while applying snapshot: %+v", err)
+		}
+	}
+
 	// The on-disk state is now committed, but the corresponding in-memory state
 	// has not yet been updated. Any errors past this point must therefore be
 	// treated as fatal.
@@ -995,6 +1072,14 @@ func (r *Replica) applySnapshot(
 	r.mu.lastIndex = s.RaftAppliedIndex
 	r.mu.lastTerm = lastTerm
 	r.mu.raftLogSize = raftLogSize
+	if separateRaftEngine {
+		// The entries of the replaced log may have been added to the raft entry
+		// cache by readers since it was dropped above, while they were still
+		// in the Raft log engine. Drop them again now that they are below the
+		// TruncatedState, which entries() checks first. Raft reads the log
+		// while holding r.mu, so it can't add them back.
+		r.store.raftEntryCache.Drop(r.RangeID)
+	}
 	// Update the store stats for the data in the snapshot.
 	r.store.metrics.subtractMVCCStats(ctx, r.mu.tenantID, *r.mu.state.Stats)
 	r.store.metrics.addMVCCStats(ctx, r.mu.tenantID, *s.Stats)
@@ -12,8 +12,10 @@ package kvserver
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 )
 
 // maybeRateLimitBatch may block the batch waiting to be rate-limited. Note that
@@ -42,13 +44,45 @@ func bytesWrittenFromRequest(ba *roachpb.BatchRequest) int64 {
 	return writeBytes
 }
 
-// recordImpactOnRateLimiter is used to record a read against the tenant rate limiter.
-func (r *Replica) recordImpactOnRateLimiter(ctx context.Context, br *roachpb.BatchResponse) {
+// recordImpactOnRateLimiter is used to record the bytes read by a batch and
+// the time spent evaluating it against the tenant rate limiter.
+func (r *Replica) recordImpactOnRateLimiter(
+	ctx context.Context, br *roachpb.BatchResponse, evalDuration time.Duration,
+) {
 	if r.tenantLimiter == nil || br == nil {
 		return
 	}
 
-	r.tenantLimiter.RecordRead(ctx, bytesReadFromResponse(br))
+	r.tenantLimiter.RecordResponse(ctx, bytesReadFromResponse(br), evalDuration)
+}
+
+// evalDurationKey is the context key of the *time.Duration in which
+// evaluateBatch records the time spent evaluating a batch.
+type evalDurationKey struct{}
+
+// withEvalDuration returns a context in which evaluateBatch records the time
+// spent evaluating a batch, and a pointer to the recorded duration. Only the
+// last evaluation of the batch is recorded: the time spent waiting for latches
+// and locks, evaluations which are retried and the replication of writes are
+// not included, so that tenants are only charged for the work their requests
+// actually require.
+func withEvalDuration(ctx context.Context) (context.Context, *time.Duration) {
+	d := new(time.Duration)
+	return context.WithValue(ctx, evalDurationKey{}, d), d
+}
+
+// startEvalDuration starts timing the evaluation of a batch. The returned
+// function must be called once the evaluation is complete, and records its
+// duration if the context was prepared by withEvalDuration.
+func startEvalDuration(ctx context.Context) func() {
+	d, ok := ctx.Value(evalDurationKey{}).(*time.Duration)
+	if !ok {
+		return func() {}
+	}
+	start := timeutil.Now()
+	return func() {
+		*d = timeutil.Since(start)
+	}
 }
 
 func bytesReadFromResponse(br *roachpb.BatchResponse) int64 {
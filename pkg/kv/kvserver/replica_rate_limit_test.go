@@ -0,0 +1,44 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEvalDuration verifies that only the last evaluation of a batch is
+// recorded, and that evaluations are not timed outside of a context prepared
+// by withEvalDuration.
+func TestEvalDuration(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// Timing an evaluation without a prepared context is a no-op.
+	startEvalDuration(context.Background())()
+
+	ctx, d := withEvalDuration(context.Background())
+	require.Zero(t, *d)
+
+	stop := startEvalDuration(ctx)
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	require.GreaterOrEqual(t, int64(*d), int64(10*time.Millisecond))
+
+	// A retried evaluation replaces the duration of the previous one.
+	startEvalDuration(ctx)()
+	require.Less(t, int64(*d), int64(10*time.Millisecond))
+}
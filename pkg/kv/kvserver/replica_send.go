@@ -13,6 +13,7 @@ package kvserver
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
@@ -72,6 +73,10 @@ func (r *Replica) sendWithRangeID(
 	if err := r.maybeRateLimitBatch(ctx, ba); err != nil {
 		return nil, roachpb.NewError(err)
 	}
+	var evalDuration *time.Duration
+	if r.tenantLimiter != nil {
+		ctx, evalDuration = withEvalDuration(ctx)
+	}
 
 	// NB: must be performed before collecting request spans.
 	ba, err := maybeStripInFlightWrites(ba)
@@ -122,7 +127,9 @@ func (r *Replica) sendWithRangeID(
 		r.maybeAddRangeInfoToResponse(ctx, ba, br)
 	}
 
-	r.recordImpactOnRateLimiter(ctx, br)
+	if evalDuration != nil {
+		r.recordImpactOnRateLimiter(ctx, br, *evalDuration)
+	}
 	return br, pErr
 }
 
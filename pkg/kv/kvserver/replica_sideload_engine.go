@@ -0,0 +1,182 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+)
+
+var _ SideloadStorage = &engineSideloadStorage{}
+
+// engineSideloadStorage is a SideloadStorage that keeps the sideloaded
+// payloads in the dedicated Raft log engine, next to the log entries that
+// reference them, rather than in individual files. This keeps all of the
+// Raft log's durable state in a single engine.
+type engineSideloadStorage struct {
+	rangeID roachpb.RangeID
+	limiter *rate.Limiter
+	// dir is only used to hand out filenames to addSSTablePreApply. No files
+	// are ever written to it by this storage.
+	dir string
+	eng storage.Engine
+}
+
+func newEngineSideloadStorage(
+	rangeID roachpb.RangeID, baseDir string, limiter *rate.Limiter, eng storage.Engine,
+) *engineSideloadStorage {
+	return &engineSideloadStorage{
+		rangeID: rangeID,
+		limiter: limiter,
+		dir:     sideloadedPath(baseDir, rangeID),
+		eng:     eng,
+	}
+}
+
+// Dir implements SideloadStorage.
+func (ss *engineSideloadStorage) Dir() string {
+	return ss.dir
+}
+
+// Put implements SideloadStorage.
+func (ss *engineSideloadStorage) Put(
+	ctx context.Context, index, term uint64, contents []byte,
+) error {
+	limitBulkIOWrite(ctx, ss.limiter, len(contents))
+	// NB: writes directly to the engine are synced.
+	return ss.eng.PutUnversioned(keys.RaftSideloadedPayloadKey(ss.rangeID, index, term), contents)
+}
+
+// Get implements SideloadStorage.
+func (ss *engineSideloadStorage) Get(ctx context.Context, index, term uint64) ([]byte, error) {
+	key := keys.RaftSideloadedPayloadKey(ss.rangeID, index, term)
+	iter := ss.eng.NewEngineIterator(storage.IterOptions{UpperBound: key.Next()})
+	defer iter.Close()
+	valid, err := iter.SeekEngineKeyGE(storage.EngineKey{Key: key})
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, errSideloadedFileNotFound
+	}
+	return iter.Value(), nil
+}
+
+// Filename implements SideloadStorage. The returned file never exists, which
+// makes addSSTablePreApply fall back to writing out a copy of the payload.
+func (ss *engineSideloadStorage) Filename(
+	ctx context.Context, index, term uint64,
+) (string, error) {
+	return filepath.Join(ss.dir, fmt.Sprintf("i%d.t%d", index, term)), nil
+}
+
+// Purge implements SideloadStorage.
+func (ss *engineSideloadStorage) Purge(ctx context.Context, index, term uint64) (int64, error) {
+	b, err := ss.Get(ctx, index, term)
+	if err != nil {
+		return 0, err
+	}
+	if err := ss.eng.ClearUnversioned(keys.RaftSideloadedPayloadKey(ss.rangeID, index, term)); err != nil {
+		return 0, err
+	}
+	return int64(len(b)), nil
+}
+
+// Clear implements SideloadStorage.
+func (ss *engineSideloadStorage) Clear(_ context.Context) error {
+	prefix := keys.RaftSideloadedPayloadPrefix(ss.rangeID)
+	return ss.eng.ClearRawRange(prefix, prefix.PrefixEnd())
+}
+
+// TruncateTo implements SideloadStorage.
+func (ss *engineSideloadStorage) TruncateTo(
+	ctx context.Context, firstIndex uint64,
+) (bytesFreed, bytesRetained int64, _ error) {
+	batch := ss.eng.NewWriteOnlyBatch()
+	defer batch.Close()
+	if err := ss.forEach(func(index uint64, key roachpb.Key, size int64) error {
+		if index >= firstIndex {
+			bytesRetained += size
+			return nil
+		}
+		bytesFreed += size
+		return batch.ClearUnversioned(key)
+	}); err != nil {
+		return 0, 0, err
+	}
+	if batch.Empty() {
+		return bytesFreed, bytesRetained, nil
+	}
+	// The log entries referencing the payloads were truncated already, so
+	// losing this batch in a crash only leaves garbage behind.
+	if err := batch.Commit(false /* sync */); err != nil {
+		return 0, 0, err
+	}
+	return bytesFreed, bytesRetained, nil
+}
+
+// forEach visits the payloads in the storage in ascending index order.
+func (ss *engineSideloadStorage) forEach(
+	visit func(index uint64, key roachpb.Key, size int64) error,
+) error {
+	prefix := keys.RaftSideloadedPayloadPrefix(ss.rangeID)
+	iter := ss.eng.NewEngineIterator(storage.IterOptions{UpperBound: prefix.PrefixEnd()})
+	defer iter.Close()
+	valid, err := iter.SeekEngineKeyGE(storage.EngineKey{Key: prefix})
+	for ; valid; valid, err = iter.NextEngineKey() {
+		key, err := iter.EngineKey()
+		if err != nil {
+			return err
+		}
+		index, err := decodeRaftSideloadedPayloadIndex(prefix, key.Key)
+		if err != nil {
+			return err
+		}
+		if err := visit(index, key.Key, int64(len(iter.UnsafeValue()))); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// decodeRaftSideloadedPayloadIndex returns the log index encoded in a key
+// created by keys.RaftSideloadedPayloadKey.
+func decodeRaftSideloadedPayloadIndex(prefix, key roachpb.Key) (uint64, error) {
+	if !bytes.HasPrefix(key, prefix) {
+		return 0, errors.AssertionFailedf("key %s does not have prefix %s", key, prefix)
+	}
+	_, index, err := encoding.DecodeUint64Ascending(key[len(prefix):])
+	return index, err
+}
+
+// String lists the payloads in the storage.
+func (ss *engineSideloadStorage) String() string {
+	var buf bytes.Buffer
+	var count int
+	if err := ss.forEach(func(index uint64, key roachpb.Key, size int64) error {
+		count++
+		fmt.Fprintf(&buf, "%s (%d bytes)\n", key, size)
+		return nil
+	}); err != nil {
+		return err.Error()
+	}
+	fmt.Fprintf(&buf, "(%d payloads)\n", count)
+	return buf.String()
+}
@@ -98,6 +98,14 @@ func TestSideloadingSideloadedStorage(t *testing.T) {
 		}
 		testSideloadingSideloadedStorage(t, maker)
 	})
+	t.Run("Engine", func(t *testing.T) {
+		maker := func(
+			s *cluster.Settings, rangeID roachpb.RangeID, rep roachpb.ReplicaID, name string, eng storage.Engine,
+		) (SideloadStorage, error) {
+			return newEngineSideloadStorage(rangeID, name, rate.NewLimiter(rate.Inf, math.MaxInt64), eng), nil
+		}
+		testSideloadingSideloadedStorage(t, maker)
+	})
 }
 
 func testSideloadingSideloadedStorage(
@@ -119,9 +127,10 @@ func testSideloadingSideloadedStorage(
 		t.Fatal(err)
 	}
 	_, isInMem := ss.(*inMemSideloadStorage) // some things don't make sense for inMem
+	_, isDisk := ss.(*diskSideloadStorage)   // directories only exist on disk
 
 	assertCreated := func(isCreated bool) {
-		if isInMem {
+		if !isDisk {
 			return
 		}
 		if is := ss.(*diskSideloadStorage).dirCreated; is != isCreated {
@@ -277,7 +286,7 @@ func testSideloadingSideloadedStorage(
 	}
 
 	func() {
-		if isInMem {
+		if !isDisk {
 			return
 		}
 		// First add a file that shouldn't be in the sideloaded storage to ensure
@@ -886,7 +895,7 @@ func TestRaftSSTableSideloadingSnapshot(t *testing.T) {
 			}
 			rsl := stateloader.Make(tc.repl.RangeID)
 			entries, err := entries(
-				ctx, rsl, tc.store.Engine(), tc.repl.RangeID, tc.store.raftEntryCache,
+				ctx, rsl, tc.store.Engine(), tc.store.RaftEngine(), tc.repl.RangeID, tc.store.raftEntryCache,
 				ss, sideloadedIndex, sideloadedIndex+1, 1<<20,
 			)
 			if err != nil {
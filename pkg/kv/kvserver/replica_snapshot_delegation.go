@@ -0,0 +1,206 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"go.etcd.io/etcd/raft/v3"
+	"go.etcd.io/etcd/raft/v3/tracker"
+)
+
+// snapshotDelegationEnabled controls whether the Raft leader of a range may
+// delegate sending a snapshot to a follower that is closer to the recipient.
+//
+// In multi-region clusters, a replica that needs a snapshot often has a peer
+// in its own region while the leader is in a different one. Streaming the
+// snapshot from that peer instead of the leader avoids sending the range's
+// data across expensive cross-region links.
+var snapshotDelegationEnabled = settings.RegisterBoolSetting(
+	"kv.snapshot_delegation.enabled",
+	"set to true to allow the Raft leader to delegate sending snapshots to a follower "+
+		"replica that is closer to the recipient, based on locality",
+	false,
+).WithPublic()
+
+// makeDelegateSnapshotRequest returns the request to delegate the snapshot
+// for the recipient to a follower, if there is a suitable one. This is only
+// the case if this replica is the Raft leader; see chooseSnapshotDelegate.
+func (r *Replica) makeDelegateSnapshotRequest(
+	ctx context.Context,
+	recipient roachpb.ReplicaDescriptor,
+	snapType SnapshotRequest_Type,
+	priority SnapshotRequest_Priority,
+) (*DelegateSnapshotRequest, bool) {
+	if !snapshotDelegationEnabled.Get(&r.store.ClusterSettings().SV) {
+		return nil, false
+	}
+	storePool := r.store.allocator.storePool
+	if storePool == nil {
+		return nil, false
+	}
+	status := r.RaftStatus()
+	if status == nil || status.RaftState != raft.StateLeader {
+		return nil, false
+	}
+	sender, err := r.GetReplicaDescriptor()
+	if err != nil {
+		return nil, false
+	}
+	firstIndex, err := r.GetFirstIndex()
+	if err != nil {
+		log.Warningf(ctx, "unable to determine first index: %v", err)
+		return nil, false
+	}
+	desc := r.Desc()
+	replicas := desc.Replicas().All()
+	delegate, ok := chooseSnapshotDelegate(
+		sender, recipient, replicas, storePool.getLocalitiesByNode(replicas),
+		status.Progress, firstIndex,
+	)
+	if !ok {
+		return nil, false
+	}
+	return &DelegateSnapshotRequest{
+		RangeID:              r.RangeID,
+		CoordinatorReplica:   sender,
+		RecipientReplica:     recipient,
+		DelegatedSender:      delegate,
+		Priority:             priority,
+		Type:                 snapType,
+		Term:                 status.Term,
+		FirstIndex:           firstIndex,
+		DescriptorGeneration: desc.Generation,
+	}, true
+}
+
+// chooseSnapshotDelegate picks the replica that sends a snapshot to the
+// recipient on behalf of the sender, the Raft leader. A follower is only
+// chosen if its locality is strictly closer to the recipient than that of the
+// sender, and if it is known to be caught up to the first index of the
+// sender's log. A snapshot at an older index would not allow the sender to
+// catch up the recipient from its log afterwards. Among the closest
+// followers, the one that is furthest along is chosen.
+func chooseSnapshotDelegate(
+	sender, recipient roachpb.ReplicaDescriptor,
+	replicas []roachpb.ReplicaDescriptor,
+	localities map[roachpb.NodeID]roachpb.Locality,
+	progress map[uint64]tracker.Progress,
+	firstIndex uint64,
+) (roachpb.ReplicaDescriptor, bool) {
+	recipientLocality := localities[recipient.NodeID]
+	bestScore := recipientLocality.DiversityScore(localities[sender.NodeID])
+	var best roachpb.ReplicaDescriptor
+	var bestMatch uint64
+	for _, repl := range replicas {
+		if repl.ReplicaID == sender.ReplicaID || repl.ReplicaID == recipient.ReplicaID {
+			continue
+		}
+		// Learners and replicas in the middle of a configuration change are
+		// likely to need a snapshot themselves.
+		if typ := repl.GetType(); typ != roachpb.VOTER_FULL && typ != roachpb.NON_VOTER {
+			continue
+		}
+		pr, ok := progress[uint64(repl.ReplicaID)]
+		if !ok || pr.State != tracker.StateReplicate || pr.Match < firstIndex {
+			continue
+		}
+		score := recipientLocality.DiversityScore(localities[repl.NodeID])
+		if score < bestScore || (score == bestScore && best.ReplicaID != 0 && pr.Match > bestMatch) {
+			best, bestScore, bestMatch = repl, score, pr.Match
+		}
+	}
+	return best, best.ReplicaID != 0
+}
+
+// delegateSnapshot asks the delegated sender of the request to send the
+// snapshot, and waits for the recipient to apply it.
+func (r *Replica) delegateSnapshot(ctx context.Context, req *DelegateSnapshotRequest) error {
+	// The delegated sender may send a snapshot at any index past the first
+	// index of our log. Make sure that the log is not truncated beyond it in
+	// the meantime, so that the recipient can be caught up from the log.
+	snapUUID := uuid.MakeV4()
+	r.addSnapshotLogTruncationConstraint(ctx, snapUUID, req.FirstIndex, req.RecipientReplica.StoreID)
+	defer func() {
+		r.completeSnapshotLogTruncationConstraint(ctx, snapUUID, timeutil.Now())
+	}()
+
+	log.VEventf(ctx, 1, "delegating snapshot for %s to %s", req.RecipientReplica, req.DelegatedSender)
+	resp, err := r.store.cfg.Transport.DelegateSnapshot(ctx, req)
+	if err == nil && resp.Status != SnapshotResponse_APPLIED {
+		err = errors.Newf("delegated snapshot failed: %s", resp.Message)
+	}
+	if err != nil {
+		r.store.metrics.RangeSnapshotsDelegateFailures.Inc(1)
+		return err
+	}
+	r.store.metrics.RangeSnapshotsDelegateSuccesses.Inc(1)
+	return nil
+}
+
+// sendDelegatedSnapshot generates a snapshot and sends it to the recipient
+// on behalf of the Raft leader that delegated it. The snapshot is only sent
+// if this replica is caught up to the first index of the leader's log and
+// its descriptor and term match those of the leader.
+func (r *Replica) sendDelegatedSnapshot(ctx context.Context, req *DelegateSnapshotRequest) error {
+	sender, err := r.GetReplicaDescriptor()
+	if err != nil {
+		return err
+	}
+	if sender.ReplicaID != req.DelegatedSender.ReplicaID {
+		return errors.Errorf("%s: delegated snapshot for replica %s", r, req.DelegatedSender)
+	}
+	status := r.RaftStatus()
+	if status == nil {
+		return errors.Errorf("%s: raft status not initialized", r)
+	}
+	if status.Term != req.Term {
+		return errors.Errorf("%s: term %d does not match leader term %d", r, status.Term, req.Term)
+	}
+	// Check the applied index up front to avoid generating a snapshot that
+	// would be rejected below.
+	r.mu.RLock()
+	applied := r.mu.state.RaftAppliedIndex
+	r.mu.RUnlock()
+	if applied < req.FirstIndex {
+		return errors.Errorf("%s: applied index %d is behind leader first index %d",
+			r, applied, req.FirstIndex)
+	}
+
+	snap, err := r.GetSnapshot(ctx, req.Type, req.RecipientReplica.StoreID)
+	if err != nil {
+		return errors.Wrapf(err, "%s: failed to generate %s snapshot", r, req.Type)
+	}
+	defer snap.Close()
+	log.Event(ctx, "generated delegated snapshot")
+
+	if gen := snap.State.Desc.Generation; gen != req.DescriptorGeneration {
+		return errors.Errorf("%s: descriptor generation %d does not match leader generation %d",
+			r, gen, req.DescriptorGeneration)
+	}
+	if _, ok := snap.State.Desc.GetReplicaDescriptor(req.RecipientReplica.StoreID); !ok {
+		return errors.Errorf("%s: snapshot does not contain the recipient %s as a replica: %s",
+			r, req.RecipientReplica, snap.State.Desc)
+	}
+	if applied := snap.State.RaftAppliedIndex; applied < req.FirstIndex {
+		return errors.Errorf("%s: snapshot index %d is behind leader first index %d",
+			r, applied, req.FirstIndex)
+	}
+	return r.streamSnapshot(
+		ctx, snap, req.CoordinatorReplica, req.RecipientReplica, req.Term, req.Type, req.Priority,
+	)
+}
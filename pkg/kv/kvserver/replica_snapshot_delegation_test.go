@@ -0,0 +1,137 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft/v3/tracker"
+)
+
+func TestChooseSnapshotDelegate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	region := func(r string) roachpb.Locality {
+		return roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: r}}}
+	}
+	// n1 (the leader) is in us-east, n2 and n4 in eu-west and n3, n5 and n6 in
+	// us-west.
+	localities := map[roachpb.NodeID]roachpb.Locality{
+		1: region("us-east"),
+		2: region("eu-west"),
+		3: region("us-west"),
+		4: region("eu-west"),
+		5: region("us-west"),
+		6: region("us-west"),
+	}
+	replica := func(id int, typ roachpb.ReplicaType) roachpb.ReplicaDescriptor {
+		return roachpb.ReplicaDescriptor{
+			NodeID:    roachpb.NodeID(id),
+			StoreID:   roachpb.StoreID(id),
+			ReplicaID: roachpb.ReplicaID(id),
+			Type:      &typ,
+		}
+	}
+	sender := replica(1, roachpb.VOTER_FULL)
+	const firstIndex = 10
+	replicate := func(match uint64) tracker.Progress {
+		return tracker.Progress{State: tracker.StateReplicate, Match: match}
+	}
+
+	testCases := []struct {
+		name      string
+		recipient roachpb.ReplicaDescriptor
+		replicas  []roachpb.ReplicaDescriptor
+		progress  map[uint64]tracker.Progress
+		expected  roachpb.ReplicaID // 0 if no delegate
+	}{
+		{
+			name:      "follower in recipient region",
+			recipient: replica(4, roachpb.LEARNER),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.VOTER_FULL), replica(3, roachpb.VOTER_FULL), replica(4, roachpb.LEARNER),
+			},
+			progress: map[uint64]tracker.Progress{2: replicate(20), 3: replicate(20)},
+			expected: 2,
+		},
+		{
+			name:      "non-voter in recipient region",
+			recipient: replica(4, roachpb.LEARNER),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.NON_VOTER), replica(4, roachpb.LEARNER),
+			},
+			progress: map[uint64]tracker.Progress{2: replicate(20)},
+			expected: 2,
+		},
+		{
+			name:      "no follower closer than the leader",
+			recipient: replica(3, roachpb.VOTER_FULL),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.VOTER_FULL), replica(3, roachpb.VOTER_FULL),
+			},
+			progress: map[uint64]tracker.Progress{2: replicate(20), 3: {State: tracker.StateSnapshot}},
+			expected: 0,
+		},
+		{
+			name:      "follower behind the leader's first index",
+			recipient: replica(4, roachpb.LEARNER),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.VOTER_FULL), replica(4, roachpb.LEARNER),
+			},
+			progress: map[uint64]tracker.Progress{2: replicate(firstIndex - 1)},
+			expected: 0,
+		},
+		{
+			name:      "follower probing",
+			recipient: replica(4, roachpb.LEARNER),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.VOTER_FULL), replica(4, roachpb.LEARNER),
+			},
+			progress: map[uint64]tracker.Progress{2: {State: tracker.StateProbe, Match: 20}},
+			expected: 0,
+		},
+		{
+			name:      "learner in recipient region",
+			recipient: replica(4, roachpb.VOTER_FULL),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.LEARNER), replica(4, roachpb.VOTER_FULL),
+			},
+			progress: map[uint64]tracker.Progress{2: replicate(20)},
+			expected: 0,
+		},
+		{
+			name:      "furthest along of the closest followers",
+			recipient: replica(3, roachpb.VOTER_FULL),
+			replicas: []roachpb.ReplicaDescriptor{
+				sender, replica(2, roachpb.VOTER_FULL), replica(3, roachpb.VOTER_FULL),
+				replica(4, roachpb.VOTER_FULL), replica(5, roachpb.VOTER_FULL), replica(6, roachpb.VOTER_FULL),
+			},
+			progress: map[uint64]tracker.Progress{
+				2: replicate(20), 4: replicate(30), 5: replicate(20), 6: replicate(25),
+			},
+			expected: 6,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delegate, ok := chooseSnapshotDelegate(
+				sender, tc.recipient, tc.replicas, localities, tc.progress, firstIndex,
+			)
+			require.Equal(t, tc.expected != 0, ok)
+			require.Equal(t, tc.expected, delegate.ReplicaID)
+		})
+	}
+}
@@ -42,6 +42,22 @@ var SplitByLoadMergeDelay = settings.RegisterDurationSetting(
 	settings.NonNegativeDuration,
 )
 
+// minSplitByLoadMergeDelay is the shortest period over which the maximum QPS
+// of a range is tracked for the purpose of merging, regardless of
+// SplitByLoadMergeDelay. Shorter periods would make the measurements too
+// noisy, and a zero period would retain them forever.
+const minSplitByLoadMergeDelay = 5 * time.Second
+
+// splitByLoadMergeDelay returns the period over which the maximum QPS of a
+// range is tracked, which is SplitByLoadMergeDelay clamped to
+// minSplitByLoadMergeDelay.
+func splitByLoadMergeDelay(sv *settings.Values) time.Duration {
+	if d := SplitByLoadMergeDelay.Get(sv); d > minSplitByLoadMergeDelay {
+		return d
+	}
+	return minSplitByLoadMergeDelay
+}
+
 // SplitByLoadQPSThreshold returns the QPS request rate for a given replica.
 func (r *Replica) SplitByLoadQPSThreshold() float64 {
 	return float64(SplitByLoadQPSThreshold.Get(&r.store.cfg.Settings.SV))
@@ -226,11 +226,29 @@ func (i *MVCCIterator) SupportsPrev() bool {
 	return i.i.SupportsPrev()
 }
 
+// RangeTombstonesAt is part of the storage.MVCCIterator interface.
+func (i *MVCCIterator) RangeTombstonesAt(key roachpb.Key) ([]hlc.Timestamp, error) {
+	return i.i.RangeTombstonesAt(key)
+}
+
 // EngineIterator wraps a storage.EngineIterator and ensures that it can
 // only be used to access spans in a SpanSet.
 type EngineIterator struct {
 	i     storage.EngineIterator
 	spans *SpanSet
+
+	// spansOnly controls whether or not timestamps associated with the
+	// spans are considered when ensuring access. If not set, the access is
+	// considered non-MVCC, since EngineIterators are used for the keyspaces
+	// which don't hold MVCC keys, such as the lock table.
+	spansOnly bool
+}
+
+func (i *EngineIterator) checkAllowed(span roachpb.Span) error {
+	if i.spansOnly {
+		return i.spans.CheckAllowed(SpanReadOnly, span)
+	}
+	return i.spans.CheckAllowedAt(SpanReadOnly, span, hlc.Timestamp{})
 }
 
 // Close is part of the storage.EngineIterator interface.
@@ -244,7 +262,7 @@ func (i *EngineIterator) SeekEngineKeyGE(key storage.EngineKey) (valid bool, err
 	if !valid {
 		return valid, err
 	}
-	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{Key: key.Key}); err != nil {
+	if err = i.checkAllowed(roachpb.Span{Key: key.Key}); err != nil {
 		return false, err
 	}
 	return valid, err
@@ -256,7 +274,7 @@ func (i *EngineIterator) SeekEngineKeyLT(key storage.EngineKey) (valid bool, err
 	if !valid {
 		return valid, err
 	}
-	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{EndKey: key.Key}); err != nil {
+	if err = i.checkAllowed(roachpb.Span{EndKey: key.Key}); err != nil {
 		return false, err
 	}
 	return valid, err
@@ -285,7 +303,7 @@ func (i *EngineIterator) checkKeyAllowed() (valid bool, err error) {
 	if err != nil {
 		return false, err
 	}
-	if err = i.spans.CheckAllowed(SpanReadOnly, roachpb.Span{Key: key.Key}); err != nil {
+	if err = i.checkAllowed(roachpb.Span{Key: key.Key}); err != nil {
 		// Invalid, but no error.
 		return false, nil // nolint:returnerrcheck
 	}
@@ -407,12 +425,10 @@ func (s spanSetReader) NewMVCCIterator(
 }
 
 func (s spanSetReader) NewEngineIterator(opts storage.IterOptions) storage.EngineIterator {
-	if !s.spansOnly {
-		panic("cannot do timestamp checking for EngineIterator")
-	}
 	return &EngineIterator{
-		i:     s.r.NewEngineIterator(opts),
-		spans: s.spans,
+		i:         s.r.NewEngineIterator(opts),
+		spans:     s.spans,
+		spansOnly: s.spansOnly,
 	}
 }
 
@@ -13,6 +13,7 @@
 package split
 
 import (
+	"math"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -36,9 +37,16 @@ const minSplitSuggestionInterval = time.Minute
 // to carry out a split. When the split is initiated, it can obtain the suggested
 // split point from MaybeSplitKey (which may have disappeared either due to a drop
 // in qps or a change in the workload).
+//
+// The Decider also keeps track of the maximum qps measured over a sliding
+// window of the last qpsRetention, see MaxQPS. This is used to decide whether
+// the range can be merged with its neighbors without being split again right
+// away. After a split due to load, the maximum is not reported until it covers
+// the whole window again, see RecordLoadSplit.
 type Decider struct {
-	intn         func(n int) int // supplied to Init
-	qpsThreshold func() float64  // supplied to Init
+	intn         func(n int) int      // supplied to Init
+	qpsThreshold func() float64       // supplied to Init
+	qpsRetention func() time.Duration // supplied to Init
 
 	mu struct {
 		syncutil.Mutex
@@ -48,6 +56,11 @@ type Decider struct {
 		count               int64     // number of requests recorded since last rollover
 		splitFinder         *Finder   // populated when engaged or decided
 		lastSplitSuggestion time.Time // last stipulation to client to carry out split
+
+		maxQPS maxQPSTracker
+		// awaitingMaxQPS is set when the range was split due to load, until the
+		// maximum QPS covers the whole retention period.
+		awaitingMaxQPS bool
 	}
 }
 
@@ -55,9 +68,15 @@ type Decider struct {
 // embedding the Decider into a larger struct outside of the scope of this package
 // without incurring a pointer reference. This is relevant since many Deciders
 // may exist in the system at any given point in time.
-func Init(lbs *Decider, intn func(n int) int, qpsThreshold func() float64) {
+func Init(
+	lbs *Decider,
+	intn func(n int) int,
+	qpsThreshold func() float64,
+	qpsRetention func() time.Duration,
+) {
 	lbs.intn = intn
 	lbs.qpsThreshold = qpsThreshold
+	lbs.qpsRetention = qpsRetention
 }
 
 // Record notifies the Decider that 'n' operations are being carried out which
@@ -90,6 +109,7 @@ func (d *Decider) recordLocked(now time.Time, n int, span func() roachpb.Span) b
 		d.mu.qps = (float64(d.mu.count) / float64(elapsedSinceLastQPS)) * 1e9
 		d.mu.lastQPSRollover = now
 		d.mu.count = 0
+		d.mu.maxQPS.record(now, d.qpsRetention(), d.mu.qps)
 
 		// If the QPS for the range exceeds the threshold, start actively
 		// tracking potential for splitting this range based on load.
@@ -129,6 +149,36 @@ func (d *Decider) LastQPS(now time.Time) float64 {
 	return qps
 }
 
+// MaxQPS returns the maximum QPS measurement recorded over the retention
+// period, or since the Decider was last reset if that is more recent. The
+// boolean is false if the range was split due to load and the measurements do
+// not cover the full retention period since, in which case the returned QPS
+// should not be relied upon.
+func (d *Decider) MaxQPS(now time.Time) (float64, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.recordLocked(now, 0, nil)
+	qps, full := d.mu.maxQPS.maxQPS(now, d.qpsRetention())
+	if full {
+		d.mu.awaitingMaxQPS = false
+	}
+	if d.mu.awaitingMaxQPS {
+		return 0, false
+	}
+	return qps, true
+}
+
+// RecordMax records the provided QPS as if it had been measured on the range.
+// This is used after a merge, to account for the load of the right-hand range
+// on the merged range.
+func (d *Decider) RecordMax(now time.Time, qps float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.mu.maxQPS.record(now, d.qpsRetention(), qps)
+}
+
 // MaybeSplitKey returns a key to perform a split at. The return value will be
 // nil if either the Decider hasn't decided that a split should be carried out
 // or if it wasn't able to determine a suitable split key.
@@ -182,10 +232,98 @@ func (d *Decider) MaybeSplitKey(now time.Time) roachpb.Key {
 	return key
 }
 
-// Reset deactivates any current attempt at determining a split key.
-func (d *Decider) Reset() {
+// Reset deactivates any current attempt at determining a split key and
+// discards the QPS history.
+func (d *Decider) Reset(now time.Time) {
+	d.mu.Lock()
+	d.resetLocked(now)
+	d.mu.Unlock()
+}
+
+// RecordLoadSplit resets the Decider after the range was split due to load.
+// Until the QPS history covers the retention period again, MaxQPS reports that
+// the load of the range is unknown, so that the range isn't merged away as soon
+// as the load which caused the split subsides.
+func (d *Decider) RecordLoadSplit(now time.Time) {
 	d.mu.Lock()
+	d.resetLocked(now)
+	d.mu.awaitingMaxQPS = true
+	d.mu.Unlock()
+}
+
+func (d *Decider) resetLocked(now time.Time) {
 	d.mu.splitFinder = nil
 	d.mu.count = 0
-	d.mu.Unlock()
+	d.mu.maxQPS.reset(now, d.qpsRetention())
+}
+
+// maxQPSWindows is the number of windows the retention period of a
+// maxQPSTracker is split into.
+const maxQPSWindows = 6
+
+// maxQPSTracker collects the maximum QPS measured over a sliding window of
+// time. The window is kept as a ring buffer of the maxima of maxQPSWindows
+// consecutive sub-windows, the oldest of which is dropped as time passes.
+type maxQPSTracker struct {
+	windows   [maxQPSWindows]float64
+	curIdx    int       // index of the current window
+	curStart  time.Time // start of the current window
+	lastReset time.Time // start of the measurements
+	retention time.Duration
+}
+
+// record adds the QPS measurement to the current window.
+func (t *maxQPSTracker) record(now time.Time, retention time.Duration, qps float64) {
+	t.maybeReset(now, retention)
+	t.maybeRotate(now)
+	t.windows[t.curIdx] = math.Max(t.windows[t.curIdx], qps)
+}
+
+// maxQPS returns the maximum QPS measured over the retention period, and
+// whether measurements have been collected for the whole period.
+func (t *maxQPSTracker) maxQPS(now time.Time, retention time.Duration) (float64, bool) {
+	t.maybeReset(now, retention)
+	t.maybeRotate(now)
+	var qps float64
+	for _, w := range t.windows {
+		qps = math.Max(qps, w)
+	}
+	return qps, now.Sub(t.lastReset) >= t.retention
+}
+
+func (t *maxQPSTracker) reset(now time.Time, retention time.Duration) {
+	*t = maxQPSTracker{
+		curStart:  now,
+		lastReset: now,
+		retention: retention,
+	}
+}
+
+// maybeReset starts over if the tracker hasn't been used yet or if the
+// retention period changed.
+func (t *maxQPSTracker) maybeReset(now time.Time, retention time.Duration) {
+	if retention != t.retention {
+		t.reset(now, retention)
+	}
+}
+
+// maybeRotate moves on to a new window, clearing the oldest one, for each
+// window width that has passed since the current window started. The width of
+// a window is such that the windows other than the current one cover the
+// retention period.
+func (t *maxQPSTracker) maybeRotate(now time.Time) {
+	width := t.retention / (maxQPSWindows - 1)
+	if width <= 0 {
+		return
+	}
+	elapsed := now.Sub(t.curStart)
+	if elapsed < width {
+		return
+	}
+	shift := int(elapsed / width)
+	for i := 0; i < shift && i < maxQPSWindows; i++ {
+		t.curIdx = (t.curIdx + 1) % maxQPSWindows
+		t.windows[t.curIdx] = 0
+	}
+	t.curStart = t.curStart.Add(time.Duration(shift) * width)
 }
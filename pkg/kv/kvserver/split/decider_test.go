@@ -30,7 +30,7 @@ func TestDecider(t *testing.T) {
 	intn := rand.New(rand.NewSource(12)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 10.0 })
+	Init(&d, intn, func() float64 { return 10.0 }, func() time.Duration { return 10 * time.Second })
 
 	ms := func(i int) time.Time {
 		ts, err := time.Parse(time.RFC3339, "2000-01-01T00:00:00Z")
@@ -170,17 +170,111 @@ func TestDecider(t *testing.T) {
 	// back up at zero.
 	assert.True(t, d.mu.splitFinder.Ready(ms(tick)))
 	assert.Equal(t, roachpb.Key("z"), d.MaybeSplitKey(ms(tick)))
-	d.Reset()
+	d.Reset(ms(tick))
 	assert.Nil(t, d.MaybeSplitKey(ms(tick)))
 	assert.Nil(t, d.mu.splitFinder)
 }
 
+func TestDeciderMaxQPS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	intn := rand.New(rand.NewSource(11)).Intn
+
+	var d Decider
+	Init(&d, intn, func() float64 { return 100.0 }, func() time.Duration { return 10 * time.Second })
+
+	var now time.Time
+	recordFor := func(d time.Duration, qps int, record func(time.Time, int, func() roachpb.Span) bool) {
+		for end := now.Add(d); now.Before(end); {
+			now = now.Add(time.Second)
+			record(now, qps, func() roachpb.Span { return roachpb.Span{Key: roachpb.Key("a")} })
+		}
+	}
+
+	// Without a split due to load, the maximum since the last reset is
+	// reported right away.
+	d.Reset(now)
+	recordFor(2*time.Second, 30, d.Record)
+	qps, ok := d.MaxQPS(now)
+	require.True(t, ok)
+	require.InDelta(t, 30.0, qps, 0.01)
+
+	// After a split due to load, there's no measurement until the retention
+	// period has passed, even if the decider is reset in between.
+	d.RecordLoadSplit(now)
+	recordFor(5*time.Second, 20, d.Record)
+	d.Reset(now)
+	recordFor(9*time.Second, 20, d.Record)
+	_, ok = d.MaxQPS(now)
+	require.False(t, ok)
+	recordFor(time.Second, 20, d.Record)
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 20.0, qps)
+
+	// A spike is remembered for the retention period.
+	recordFor(time.Second, 50, d.Record)
+	recordFor(5*time.Second, 10, d.Record)
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 50.0, qps)
+	recordFor(10*time.Second, 10, d.Record)
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 10.0, qps)
+
+	// Load recorded through RecordMax counts as well.
+	d.RecordMax(now, 30)
+	qps, _ = d.MaxQPS(now)
+	require.Equal(t, 30.0, qps)
+
+	// The measurement is discarded when the decider is reset.
+	d.Reset(now)
+	qps, ok = d.MaxQPS(now)
+	require.True(t, ok)
+	require.Equal(t, 0.0, qps)
+}
+
+func TestMaxQPSTracker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const retention = 10 * time.Second
+	width := retention / (maxQPSWindows - 1)
+	start := time.Unix(1000, 0)
+	var tr maxQPSTracker
+
+	// The first use starts the measurements.
+	tr.record(start, retention, 5)
+	qps, ok := tr.maxQPS(start.Add(retention-1), retention)
+	require.False(t, ok)
+	require.Equal(t, 5.0, qps)
+	qps, ok = tr.maxQPS(start.Add(retention), retention)
+	require.True(t, ok)
+	require.Equal(t, 5.0, qps)
+
+	// Each measurement is retained for at least the retention period, but no
+	// longer than the retention period plus one window width.
+	tr.record(start.Add(retention), retention, 7)
+	qps, _ = tr.maxQPS(start.Add(2*retention), retention)
+	require.Equal(t, 7.0, qps)
+	qps, _ = tr.maxQPS(start.Add(2*retention+width), retention)
+	require.Equal(t, 0.0, qps)
+
+	// Skipping over more than all windows clears them.
+	tr.record(start.Add(3*retention), retention, 3)
+	qps, _ = tr.maxQPS(start.Add(10*retention), retention)
+	require.Equal(t, 0.0, qps)
+
+	// Changing the retention period starts over.
+	_, ok = tr.maxQPS(start.Add(10*retention), 2*retention)
+	require.False(t, ok)
+}
+
 func TestDeciderCallsEnsureSafeSplitKey(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	intn := rand.New(rand.NewSource(11)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 1.0 })
+	Init(&d, intn, func() float64 { return 1.0 }, func() time.Duration { return 10 * time.Second })
 
 	baseKey := keys.SystemSQLCodec.TablePrefix(51)
 	for i := 0; i < 4; i++ {
@@ -213,7 +307,7 @@ func TestDeciderIgnoresEnsureSafeSplitKeyOnError(t *testing.T) {
 	intn := rand.New(rand.NewSource(11)).Intn
 
 	var d Decider
-	Init(&d, intn, func() float64 { return 1.0 })
+	Init(&d, intn, func() float64 { return 1.0 }, func() time.Duration { return 10 * time.Second })
 
 	baseKey := keys.SystemSQLCodec.TablePrefix(51)
 	for i := 0; i < 4; i++ {
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/gossip"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -228,17 +229,19 @@ func (sq *splitQueue) processAttempt(
 			batchHandledQPS,
 			raftAppliedQPS,
 		)
-		// Add a small delay (default of 5m) to any subsequent attempt to merge
-		// this range split away. While the merge queue does takes into account
-		// load to avoids merging ranges that would be immediately re-split due
-		// to load-based splitting, it doesn't take into account historical
-		// load. So this small delay is the only thing that prevents split
-		// points created due to load from being immediately merged away after
-		// load is stopped, which can be a problem for benchmarks where data is
-		// first imported and then the workload begins after a small delay.
+		// Until all nodes report the maximum QPS of ranges over the merge delay,
+		// the merge queue only considers their current QPS. Add a small delay
+		// (default of 5m) to any subsequent attempt to merge this range split
+		// away, so that split points created due to load are not immediately
+		// merged away after load is stopped, which can be a problem for
+		// benchmarks where data is first imported and then the workload begins
+		// after a small delay. Once the version is active, RecordLoadSplit
+		// below holds off merges instead.
 		var expTime hlc.Timestamp
-		if expDelay := SplitByLoadMergeDelay.Get(&sq.store.cfg.Settings.SV); expDelay > 0 {
-			expTime = sq.store.Clock().Now().Add(expDelay.Nanoseconds(), 0)
+		if !sq.store.ClusterSettings().Version.IsActive(ctx, clusterversion.RangeStatsRespHasMaxQPS) {
+			if expDelay := SplitByLoadMergeDelay.Get(&sq.store.cfg.Settings.SV); expDelay > 0 {
+				expTime = sq.store.Clock().Now().Add(expDelay.Nanoseconds(), 0)
+			}
 		}
 		if _, pErr := r.adminSplitWithDescriptor(
 			ctx,
@@ -258,8 +261,14 @@ func (sq *splitQueue) processAttempt(
 
 		telemetry.Inc(sq.loadBasedCount)
 
-		// Reset the splitter now that the bounds of the range changed.
-		r.loadBasedSplitter.Reset()
+		// Reset the splitter now that the bounds of the range changed, and keep
+		// the merge queue from merging either side away until their load has
+		// been observed for the merge delay. The right-hand side was created on
+		// this store, along with its lease, when the split was applied.
+		r.loadBasedSplitter.RecordLoadSplit(now)
+		if rhs := sq.store.LookupReplica(roachpb.RKey(splitByLoadKey)); rhs != nil && rhs != r {
+			rhs.loadBasedSplitter.RecordLoadSplit(now)
+		}
 		return true, nil
 	}
 	return false, nil
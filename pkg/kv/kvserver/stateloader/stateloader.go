@@ -507,11 +507,15 @@ func (rsl StateLoader) SetGCThreshold(
 
 // The rest is not technically part of ReplicaState.
 
-// LoadLastIndex loads the last index.
-func (rsl StateLoader) LoadLastIndex(ctx context.Context, reader storage.Reader) (uint64, error) {
+// LoadLastIndex loads the last index. The Raft log is read from raftReader,
+// which differs from reader only if the store keeps its Raft log in a
+// dedicated engine.
+func (rsl StateLoader) LoadLastIndex(
+	ctx context.Context, reader, raftReader storage.Reader,
+) (uint64, error) {
 	prefix := rsl.RaftLogPrefix()
 	// NB: raft log has no intents.
-	iter := reader.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{LowerBound: prefix})
+	iter := raftReader.NewMVCCIterator(storage.MVCCKeyIterKind, storage.IterOptions{LowerBound: prefix})
 	defer iter.Close()
 
 	var lastIndex uint64
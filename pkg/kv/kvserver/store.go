@@ -417,6 +417,7 @@ type Store struct {
 	cfg                StoreConfig
 	db                 *kv.DB
 	engine             storage.Engine // The underlying key-value store
+	raftEngine         storage.Engine // Holds the Raft log; see storage.RaftLogEngine
 	tsCache            tscache.Cache  // Most recent timestamps for keys / key ranges
 	allocator          Allocator      // Makes allocation decisions
 	replRankings       *replicaRankings
@@ -816,11 +817,12 @@ func NewStore(
 		log.Fatalf(ctx, "invalid store configuration: %+v", &cfg)
 	}
 	s := &Store{
-		cfg:      cfg,
-		db:       cfg.DB, // TODO(tschottdorf): remove redundancy.
-		engine:   eng,
-		nodeDesc: nodeDesc,
-		metrics:  newStoreMetrics(cfg.HistogramWindowInterval),
+		cfg:        cfg,
+		db:         cfg.DB, // TODO(tschottdorf): remove redundancy.
+		engine:     eng,
+		raftEngine: storage.RaftLogEngine(eng),
+		nodeDesc:   nodeDesc,
+		metrics:    newStoreMetrics(cfg.HistogramWindowInterval),
 	}
 	if cfg.RPCContext != nil {
 		s.allocator = MakeAllocator(cfg.StorePool, cfg.RPCContext.RemoteClocks.Latency)
@@ -1453,6 +1455,11 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 
 	s.rangeIDAlloc = idAlloc
 
+	// Set up the Raft log engine, if any, before replicas load their logs.
+	if err := s.initRaftLogEngine(ctx); err != nil {
+		return err
+	}
+
 	now := s.cfg.Clock.Now()
 	s.startedAt = now.WallTime
 
@@ -1591,6 +1598,9 @@ func (s *Store) Start(ctx context.Context, stopper *stop.Stopper) error {
 	// Connect rangefeeds to closed timestamp updates.
 	s.startClosedTimestampRangefeedSubscriber(ctx)
 
+	// Rewrite files that use retired encryption keys.
+	s.startReencryption(ctx)
+
 	if s.replicateQueue != nil {
 		s.storeRebalancer = NewStoreRebalancer(
 			s.cfg.AmbientCtx, s.cfg.Settings, s.replicateQueue, s.replRankings)
@@ -2314,6 +2324,13 @@ func (s *Store) Clock() *hlc.Clock { return s.cfg.Clock }
 // Engine accessor.
 func (s *Store) Engine() storage.Engine { return s.engine }
 
+// RaftEngine accessor. Unless the store uses a dedicated Raft log engine, this
+// is the same as Engine().
+func (s *Store) RaftEngine() storage.Engine { return s.raftEngine }
+
+// separateRaftEngine returns whether the Raft log lives in a dedicated engine.
+func (s *Store) separateRaftEngine() bool { return s.raftEngine != s.engine }
+
 // DB accessor.
 func (s *Store) DB() *kv.DB { return s.cfg.DB }
 
@@ -2451,6 +2468,11 @@ func (s *Store) Metrics() *StoreMetrics {
 	return s.metrics
 }
 
+// TenantRateLimiters returns the factory of the store's tenant rate limiters.
+func (s *Store) TenantRateLimiters() *tenantrate.LimiterFactory {
+	return s.tenantRateLimiters
+}
+
 // Descriptor returns a StoreDescriptor including current store
 // capacity information.
 func (s *Store) Descriptor(ctx context.Context, useCached bool) (*roachpb.StoreDescriptor, error) {
@@ -84,6 +84,31 @@ func (s *Store) HandleSnapshot(
 	})
 }
 
+// HandleDelegatedSnapshot generates a snapshot of a replica on this store and
+// sends it to the recipient on behalf of the Raft leader of the range.
+func (s *Store) HandleDelegatedSnapshot(
+	ctx context.Context, req *DelegateSnapshotRequest,
+) *DelegateSnapshotResponse {
+	ctx = s.AnnotateCtx(ctx)
+	const name = "storage.Store: handle delegated snapshot"
+	if err := s.stopper.RunTaskWithErr(ctx, name, func(ctx context.Context) error {
+		if s.IsDraining() {
+			return errors.New(storeDrainingMsg)
+		}
+		repl, err := s.GetReplica(req.RangeID)
+		if err != nil {
+			return err
+		}
+		return repl.sendDelegatedSnapshot(ctx, req)
+	}); err != nil {
+		return &DelegateSnapshotResponse{
+			Status:  SnapshotResponse_ERROR,
+			Message: err.Error(),
+		}
+	}
+	return &DelegateSnapshotResponse{Status: SnapshotResponse_APPLIED}
+}
+
 func (s *Store) uncoalesceBeats(
 	ctx context.Context,
 	beats []RaftHeartbeat,
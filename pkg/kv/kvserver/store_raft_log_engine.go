@@ -0,0 +1,311 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+)
+
+// A store may keep its Raft log (the log entries and sideloaded payloads, but
+// not the HardState or TruncatedState) in a dedicated storage engine, see
+// storage.RaftLogEngine. Since writes to the two engines are not atomic, the
+// Raft log engine is allowed to contain log entries the state engine no longer
+// references, but never the other way around:
+//
+// - appends commit to the Raft log engine before the HardState is written,
+// - truncations advance the TruncatedState before removing log entries,
+// - snapshots remove the log suffix they replace before ingesting the new
+//   state, and the remaining prefix afterwards,
+// - replica removal removes the state before the log.
+//
+// Unreferenced log entries left behind by a crash are removed on the next
+// start by sweepRaftLogEngine.
+//
+// The raft entry cache must not serve unreferenced entries either: entries()
+// checks the TruncatedState before reading the Raft log engine, truncations
+// clear the cache once the entries are removed, and snapshots drop the cache
+// both before and after ingesting the new state.
+
+// raftLogMigrationBatchSize is the size at which the batch copying the Raft
+// log into a newly added Raft log engine is committed. It is a variable for
+// testing.
+var raftLogMigrationBatchSize = 64 << 20 // 64 MB
+
+// initRaftLogEngine runs on store start, before any replicas are created. It
+// moves the Raft log into the dedicated Raft log engine the first time the
+// store is started with one, and removes unreferenced log entries left behind
+// by a crash.
+func (s *Store) initRaftLogEngine(ctx context.Context) error {
+	migrated, _, err := storage.MVCCGet(
+		ctx, s.engine, keys.StoreRaftLogEngineKey(), hlc.Timestamp{}, storage.MVCCGetOptions{},
+	)
+	if err != nil {
+		return err
+	}
+	if !s.separateRaftEngine() {
+		if migrated != nil {
+			return errors.WithHint(
+				errors.Errorf("store %s keeps its Raft log in a dedicated engine", s),
+				"Restart the node with raftlog=separate in the --store flag.",
+			)
+		}
+		return nil
+	}
+	if migrated == nil {
+		if err := s.migrateRaftLogToRaftLogEngine(ctx); err != nil {
+			return errors.Wrap(err, "moving Raft log into dedicated engine")
+		}
+	}
+	// The sideloaded payloads now live in the Raft log engine. Remove the
+	// directory, which may have been left behind by a crash during the
+	// migration or contain empty directories created during SSTable ingestion.
+	if err := s.engine.RemoveAll(filepath.Join(s.engine.GetAuxiliaryDir(), "sideloading")); err != nil &&
+		!oserror.IsNotExist(err) {
+		return err
+	}
+	return s.sweepRaftLogEngine(ctx)
+}
+
+// migrateRaftLogToRaftLogEngine copies the Raft log of all replicas into the
+// Raft log engine and then atomically removes it from the state engine and
+// marks the store as migrated.
+func (s *Store) migrateRaftLogToRaftLogEngine(ctx context.Context) error {
+	raftBatch := s.raftEngine.NewWriteOnlyBatch()
+	defer func() { raftBatch.Close() }()
+	// maybeCommitRaftBatch bounds the memory held by the migration. Nothing
+	// references the copied log until the state engine is updated below, so
+	// the intermediate batches don't need to be synced.
+	maybeCommitRaftBatch := func() error {
+		if raftBatch.Len() < raftLogMigrationBatchSize {
+			return nil
+		}
+		if err := raftBatch.Commit(false /* sync */); err != nil {
+			return err
+		}
+		raftBatch.Close()
+		raftBatch = s.raftEngine.NewWriteOnlyBatch()
+		return nil
+	}
+	stateBatch := s.engine.NewWriteOnlyBatch()
+	defer stateBatch.Close()
+
+	// A previous attempt may have crashed part way through, after which the
+	// store may have run with its Raft log in the state engine again. Whatever
+	// it copied is stale: a log suffix left above the current last index would
+	// be taken as the tail of the log.
+	if err := forEachRangeID(s.raftEngine, func(_ storage.EngineIterator, rangeID roachpb.RangeID) error {
+		return clearRaftLogEngineRange(raftBatch, rangeID, 0, 0 /* hi */)
+	}); err != nil {
+		return err
+	}
+
+	var rangeIDs []roachpb.RangeID
+	if err := forEachRangeID(s.engine, func(it storage.EngineIterator, rangeID roachpb.RangeID) error {
+		prefix := keys.RaftLogPrefix(rangeID)
+		var found bool
+		valid, err := it.SeekEngineKeyGE(storage.EngineKey{Key: prefix})
+		for ; valid; valid, err = it.NextEngineKey() {
+			key, err := it.UnsafeEngineKey()
+			if err != nil {
+				return err
+			}
+			if !bytes.HasPrefix(key.Key, prefix) {
+				break
+			}
+			found = true
+			if err := raftBatch.PutEngineKey(key, it.UnsafeValue()); err != nil {
+				return err
+			}
+			if err := maybeCommitRaftBatch(); err != nil {
+				return err
+			}
+		}
+		if err != nil || !found {
+			return err
+		}
+		rangeIDs = append(rangeIDs, rangeID)
+		return stateBatch.ClearRawRange(prefix, prefix.PrefixEnd())
+	}); err != nil {
+		return err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(
+		s.engine.GetAuxiliaryDir(), "sideloading", "r*XXXX", "r*", "i*.t*",
+	))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		var rangeID roachpb.RangeID
+		var index, term uint64
+		if _, err := fmt.Sscanf(filepath.Base(filepath.Dir(match)), "r%d", &rangeID); err != nil {
+			return errors.Wrapf(err, "while parsing %q", match)
+		}
+		if _, err := fmt.Sscanf(filepath.Base(match), "i%d.t%d", &index, &term); err != nil {
+			return errors.Wrapf(err, "while parsing %q", match)
+		}
+		b, err := s.engine.ReadFile(match)
+		if err != nil {
+			return err
+		}
+		if err := raftBatch.PutUnversioned(keys.RaftSideloadedPayloadKey(rangeID, index, term), b); err != nil {
+			return err
+		}
+		if err := maybeCommitRaftBatch(); err != nil {
+			return err
+		}
+	}
+
+	// The Raft log engine must contain the log before the state engine stops
+	// doing so.
+	if err := raftBatch.Commit(true /* sync */); err != nil {
+		return err
+	}
+	var v roachpb.Value
+	v.SetBool(true)
+	if err := storage.MVCCPut(
+		ctx, stateBatch, nil /* ms */, keys.StoreRaftLogEngineKey(), hlc.Timestamp{}, v, nil, /* txn */
+	); err != nil {
+		return err
+	}
+	if err := stateBatch.Commit(true /* sync */); err != nil {
+		return err
+	}
+	log.Infof(ctx, "moved Raft log of %d replicas and %d sideloaded payloads into dedicated engine",
+		len(rangeIDs), len(matches))
+	return nil
+}
+
+// sweepRaftLogEngine removes log entries and sideloaded payloads that are no
+// longer referenced by the Raft state in the state engine. Such entries are
+// left behind when the store crashes between writes to the two engines.
+func (s *Store) sweepRaftLogEngine(ctx context.Context) error {
+	batch := s.raftEngine.NewWriteOnlyBatch()
+	defer batch.Close()
+	var swept int
+	if err := forEachRangeID(s.raftEngine, func(it storage.EngineIterator, rangeID roachpb.RangeID) error {
+		truncState, _, err := stateloader.Make(rangeID).LoadRaftTruncatedState(ctx, s.engine)
+		if err != nil {
+			return err
+		}
+		if truncState.Index == 0 {
+			// The replica is gone or was never initialized.
+			swept++
+			return clearRaftLogEngineRange(batch, rangeID, 0, 0 /* hi */)
+		}
+		// Only clear the prefixes if they actually exist, to avoid creating
+		// range tombstones on every start.
+		for _, bounds := range [][2]roachpb.Key{
+			{keys.RaftLogPrefix(rangeID), keys.RaftLogKey(rangeID, truncState.Index+1)},
+			{
+				keys.RaftSideloadedPayloadPrefix(rangeID),
+				keys.RaftSideloadedPayloadKey(rangeID, truncState.Index+1, 0 /* term */),
+			},
+		} {
+			valid, err := it.SeekEngineKeyGE(storage.EngineKey{Key: bounds[0]})
+			if err != nil {
+				return err
+			}
+			if !valid {
+				continue
+			}
+			key, err := it.UnsafeEngineKey()
+			if err != nil {
+				return err
+			}
+			if key.Key.Compare(bounds[1]) < 0 {
+				swept++
+				if err := batch.ClearRawRange(bounds[0], bounds[1]); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if batch.Empty() {
+		return nil
+	}
+	log.Infof(ctx, "removed unreferenced Raft log entries of %d replicas", swept)
+	// If this batch is lost, the next start will sweep again.
+	return batch.Commit(false /* sync */)
+}
+
+// forEachRangeID calls the provided function once for each range ID with
+// range ID local keys in the reader. The iterator is positioned at the first
+// such key and may be moved by the function.
+func forEachRangeID(
+	reader storage.Reader, fn func(it storage.EngineIterator, rangeID roachpb.RangeID) error,
+) error {
+	it := reader.NewEngineIterator(storage.IterOptions{UpperBound: keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey()})
+	defer it.Close()
+	valid, err := it.SeekEngineKeyGE(storage.EngineKey{Key: keys.LocalRangeIDPrefix.AsRawKey()})
+	for valid {
+		key, err := it.UnsafeEngineKey()
+		if err != nil {
+			return err
+		}
+		rangeID, _, _, _, err := keys.DecodeRangeIDKey(key.Key)
+		if err != nil {
+			return err
+		}
+		if err := fn(it, rangeID); err != nil {
+			return err
+		}
+		valid, err = it.SeekEngineKeyGE(storage.EngineKey{Key: keys.MakeRangeIDPrefix(rangeID + 1)})
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// clearRaftLogEngineRange clears the log entries and sideloaded payloads of
+// the given range with indexes in [lo, hi). A zero hi clears everything from
+// lo onwards.
+func clearRaftLogEngineRange(w storage.Writer, rangeID roachpb.RangeID, lo, hi uint64) error {
+	logStart, logEnd := keys.RaftLogKey(rangeID, lo), keys.RaftLogPrefix(rangeID).PrefixEnd()
+	slStart := keys.RaftSideloadedPayloadKey(rangeID, lo, 0 /* term */)
+	slEnd := keys.RaftSideloadedPayloadPrefix(rangeID).PrefixEnd()
+	if hi != 0 {
+		logEnd = keys.RaftLogKey(rangeID, hi)
+		slEnd = keys.RaftSideloadedPayloadKey(rangeID, hi, 0 /* term */)
+	}
+	if err := w.ClearRawRange(logStart, logEnd); err != nil {
+		return err
+	}
+	return w.ClearRawRange(slStart, slEnd)
+}
+
+// clearRaftLogEngineRaftMuLocked clears the replica's log entries and
+// sideloaded payloads with indexes in [lo, hi) from the Raft log engine, see
+// clearRaftLogEngineRange.
+func (r *Replica) clearRaftLogEngineRaftMuLocked(lo, hi uint64, sync bool) error {
+	batch := r.store.raftEngine.NewWriteOnlyBatch()
+	defer batch.Close()
+	if err := clearRaftLogEngineRange(batch, r.RangeID, lo, hi); err != nil {
+		return err
+	}
+	return batch.Commit(sync)
+}
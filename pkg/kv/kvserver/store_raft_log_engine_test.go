@@ -0,0 +1,263 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/raftentry"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/stateloader"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft/v3"
+	"go.etcd.io/etcd/raft/v3/raftpb"
+	"golang.org/x/time/rate"
+)
+
+// TestRaftLogEngineInit verifies that the Raft log is moved into a newly
+// added Raft log engine, that unreferenced log entries and sideloaded
+// payloads are swept on start, and that a store which has been moved to a
+// dedicated Raft log engine refuses to start without it.
+func TestRaftLogEngineInit(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+	raftEng := storage.NewDefaultInMem()
+	defer raftEng.Close()
+
+	rangeIDLocalKeys := func(reader storage.Reader) []string {
+		var res []string
+		it := reader.NewEngineIterator(storage.IterOptions{
+			UpperBound: keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey(),
+		})
+		defer it.Close()
+		valid, err := it.SeekEngineKeyGE(storage.EngineKey{Key: keys.LocalRangeIDPrefix.AsRawKey()})
+		for ; valid; valid, err = it.NextEngineKey() {
+			key, err := it.UnsafeEngineKey()
+			require.NoError(t, err)
+			res = append(res, key.Key.String())
+		}
+		require.NoError(t, err)
+		return res
+	}
+	logKeys := func(rangeID roachpb.RangeID, indexes ...uint64) []string {
+		var res []string
+		for _, index := range indexes {
+			res = append(res, keys.RaftLogKey(rangeID, index).String())
+		}
+		return res
+	}
+
+	// r1 is truncated at index 10 and has entries on either side of that. r2
+	// has log entries but no Raft state, as if it had been removed.
+	require.NoError(t, stateloader.Make(1).SetRaftTruncatedState(
+		ctx, eng, &roachpb.RaftTruncatedState{Index: 10, Term: 5},
+	))
+	for index := uint64(8); index <= 12; index++ {
+		require.NoError(t, eng.PutUnversioned(keys.RaftLogKey(1, index), []byte("entry")))
+		require.NoError(t, eng.PutUnversioned(keys.RaftLogKey(2, index), []byte("entry")))
+	}
+
+	s := &Store{
+		Ident:      &roachpb.StoreIdent{NodeID: 1, StoreID: 1},
+		engine:     eng,
+		raftEngine: raftEng,
+	}
+	require.NoError(t, s.initRaftLogEngine(ctx))
+
+	// The log entries were moved, and those no longer referenced swept.
+	require.Equal(t, []string{keys.RaftTruncatedStateKey(1).String()}, rangeIDLocalKeys(eng))
+	require.Equal(t, logKeys(1, 11, 12), rangeIDLocalKeys(raftEng))
+	migrated, _, err := storage.MVCCGet(
+		ctx, eng, keys.StoreRaftLogEngineKey(), hlc.Timestamp{}, storage.MVCCGetOptions{},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, migrated)
+
+	// Simulate a crash that left a sideloaded payload behind during a
+	// truncation. Restarting sweeps it, but leaves everything else alone.
+	require.NoError(t, raftEng.PutUnversioned(keys.RaftSideloadedPayloadKey(1, 9, 5), []byte("sst")))
+	require.NoError(t, raftEng.PutUnversioned(keys.RaftSideloadedPayloadKey(1, 11, 5), []byte("sst")))
+	require.NoError(t, s.initRaftLogEngine(ctx))
+	require.Equal(t,
+		append(logKeys(1, 11, 12), keys.RaftSideloadedPayloadKey(1, 11, 5).String()),
+		rangeIDLocalKeys(raftEng),
+	)
+
+	// The store can't be opened without the Raft log engine anymore.
+	s.raftEngine = eng
+	err = s.initRaftLogEngine(ctx)
+	require.True(t, testutils.IsError(err, "keeps its Raft log in a dedicated engine"), "%v", err)
+}
+
+// TestRaftLogEngineMigrateSideloaded verifies that sideloaded payloads are
+// moved into a newly added Raft log engine, committing the copy in bounded
+// batches.
+func TestRaftLogEngineMigrateSideloaded(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer func(old int) { raftLogMigrationBatchSize = old }(raftLogMigrationBatchSize)
+	raftLogMigrationBatchSize = 1
+
+	ctx := context.Background()
+	cleanup, eng := newEngine(t)
+	defer cleanup()
+	defer eng.Close()
+	raftEng := storage.NewDefaultInMem()
+	defer raftEng.Close()
+
+	require.NoError(t, stateloader.Make(1).SetRaftTruncatedState(
+		ctx, eng, &roachpb.RaftTruncatedState{Index: 10, Term: 5},
+	))
+	limiter := rate.NewLimiter(rate.Inf, math.MaxInt64)
+	diskSS, err := newDiskSideloadStorage(
+		cluster.MakeTestingClusterSettings(), 1, 1, eng.GetAuxiliaryDir(), limiter, eng,
+	)
+	require.NoError(t, err)
+	for index := uint64(11); index <= 13; index++ {
+		require.NoError(t, eng.PutUnversioned(keys.RaftLogKey(1, index), []byte("entry")))
+		require.NoError(t, diskSS.Put(ctx, index, 5, []byte(fmt.Sprintf("sst%d", index))))
+	}
+
+	s := &Store{
+		Ident:      &roachpb.StoreIdent{NodeID: 1, StoreID: 1},
+		engine:     eng,
+		raftEngine: raftEng,
+	}
+	require.NoError(t, s.initRaftLogEngine(ctx))
+
+	engineSS := newEngineSideloadStorage(1, eng.GetAuxiliaryDir(), limiter, raftEng)
+	for index := uint64(11); index <= 13; index++ {
+		payload, err := engineSS.Get(ctx, index, 5)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("sst%d", index), string(payload))
+	}
+	_, err = eng.Stat(filepath.Join(eng.GetAuxiliaryDir(), "sideloading"))
+	require.True(t, oserror.IsNotExist(err), "%v", err)
+}
+
+// TestRaftLogEngineMigrateAfterCrash verifies that the log entries and
+// sideloaded payloads copied by a migration that crashed are removed when the
+// migration is run again, so that a stale log suffix does not survive it.
+func TestRaftLogEngineMigrateAfterCrash(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+	raftEng := storage.NewDefaultInMem()
+	defer raftEng.Close()
+
+	// The crashed migration copied the log of r1 up to index 15, together
+	// with a sideloaded payload at index 14. The store then ran without the
+	// Raft log engine, and a snapshot replaced the log of r1, which now ends
+	// at index 12.
+	for index := uint64(11); index <= 15; index++ {
+		require.NoError(t, raftEng.PutUnversioned(keys.RaftLogKey(1, index), []byte("stale")))
+	}
+	require.NoError(t, raftEng.PutUnversioned(keys.RaftSideloadedPayloadKey(1, 14, 5), []byte("sst")))
+	require.NoError(t, stateloader.Make(1).SetRaftTruncatedState(
+		ctx, eng, &roachpb.RaftTruncatedState{Index: 10, Term: 6},
+	))
+	for index := uint64(11); index <= 12; index++ {
+		require.NoError(t, eng.PutUnversioned(keys.RaftLogKey(1, index), []byte("entry")))
+	}
+
+	s := &Store{
+		Ident:      &roachpb.StoreIdent{NodeID: 1, StoreID: 1},
+		engine:     eng,
+		raftEngine: raftEng,
+	}
+	require.NoError(t, s.initRaftLogEngine(ctx))
+
+	var res []string
+	it := raftEng.NewEngineIterator(storage.IterOptions{
+		UpperBound: keys.LocalRangeIDPrefix.PrefixEnd().AsRawKey(),
+	})
+	defer it.Close()
+	valid, err := it.SeekEngineKeyGE(storage.EngineKey{Key: keys.LocalRangeIDPrefix.AsRawKey()})
+	for ; valid; valid, err = it.NextEngineKey() {
+		key, err := it.UnsafeEngineKey()
+		require.NoError(t, err)
+		res = append(res, fmt.Sprintf("%s=%s", key.Key, it.UnsafeValue()))
+	}
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		fmt.Sprintf("%s=entry", keys.RaftLogKey(1, 11)),
+		fmt.Sprintf("%s=entry", keys.RaftLogKey(1, 12)),
+	}, res)
+}
+
+// TestRaftLogEngineEntries verifies that the log entries left in the Raft log
+// engine at or below the TruncatedState are neither returned nor cached.
+func TestRaftLogEngineEntries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	eng := storage.NewDefaultInMem()
+	defer eng.Close()
+	raftEng := storage.NewDefaultInMem()
+	defer raftEng.Close()
+
+	// The entries up to index 10 are left over from a log replaced by a
+	// snapshot at index 10 and term 5, and have a different term.
+	const rangeID = 1
+	rsl := stateloader.Make(rangeID)
+	require.NoError(t, rsl.SetRaftTruncatedState(
+		ctx, eng, &roachpb.RaftTruncatedState{Index: 10, Term: 5},
+	))
+	for index := uint64(8); index <= 12; index++ {
+		ent := raftpb.Entry{Index: index, Term: 4}
+		if index > 10 {
+			ent.Term = 5
+		}
+		require.NoError(t, storage.MVCCPutProto(
+			ctx, raftEng, nil /* ms */, keys.RaftLogKey(rangeID, index), hlc.Timestamp{}, nil /* txn */, &ent,
+		))
+	}
+	eCache := raftentry.NewCache(1 << 20)
+
+	_, err := entries(ctx, rsl, eng, raftEng, rangeID, eCache, nil /* sideloaded */, 9, 12, math.MaxUint64)
+	require.Equal(t, raft.ErrCompacted, err)
+	_, ok := eCache.Get(rangeID, 9)
+	require.False(t, ok)
+
+	ents, err := entries(ctx, rsl, eng, raftEng, rangeID, eCache, nil /* sideloaded */, 11, 13, math.MaxUint64)
+	require.NoError(t, err)
+	require.Len(t, ents, 2)
+	_, ok = eCache.Get(rangeID, 11)
+	require.True(t, ok)
+
+	// The term at the TruncatedState comes from the state engine.
+	entTerm, err := term(ctx, rsl, eng, raftEng, rangeID, eCache, 10)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), entTerm)
+	_, err = term(ctx, rsl, eng, raftEng, rangeID, eCache, 9)
+	require.Equal(t, raft.ErrCompacted, err)
+}
@@ -441,6 +441,7 @@ func (sr *StoreRebalancer) chooseLeaseToTransfer(
 			filteredStoreList := storeList.filter(zone.Constraints)
 			if sr.rq.allocator.followTheWorkloadPrefersLocal(
 				ctx,
+				zone,
 				filteredStoreList,
 				*localDesc,
 				candidate.StoreID,
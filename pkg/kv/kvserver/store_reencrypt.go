@@ -0,0 +1,76 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvserver
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// reencryptionRate is the rate at which sstables encrypted with a data key
+// other than the active one are rewritten with the active data key. Data keys
+// are rotated periodically, but files keep using the key they were written
+// with until they are compacted away, which may never happen for cold data.
+var reencryptionRate = settings.RegisterByteSizeSetting(
+	"kv.store.reencryption.max_rate",
+	"the rate limit (bytes/sec) at which files encrypted with a data key other than "+
+		"the active one are rewritten to use the active data key; 0 disables re-encryption",
+	4<<20, // 4 MB
+	settings.NonNegativeInt,
+).WithPublic()
+
+// reencryptionInterval is the interval at which the store re-encrypts files.
+// Each time, up to reencryptionRate*reencryptionInterval bytes are rewritten.
+const reencryptionInterval = 10 * time.Second
+
+// startReencryption starts a goroutine that rewrites the files of the store's
+// engines that use retired encryption keys. It is a noop for engines without
+// encryption-at-rest.
+func (s *Store) startReencryption(ctx context.Context) {
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(reencryptionInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-s.stopper.ShouldQuiesce():
+				return
+			}
+			rate := reencryptionRate.Get(&s.ClusterSettings().SV)
+			if rate == 0 {
+				continue
+			}
+			maxBytes := rate * int64(reencryptionInterval/time.Second)
+			ctx, cancel := s.stopper.WithCancelOnQuiesce(ctx)
+			engines := []storage.Engine{s.engine}
+			if s.separateRaftEngine() {
+				engines = append(engines, s.raftEngine)
+			}
+			for _, eng := range engines {
+				start := timeutil.Now()
+				n, err := eng.ReencryptFiles(ctx, maxBytes)
+				if err != nil {
+					log.Warningf(ctx, "failed to re-encrypt files: %v", err)
+				} else if n > 0 {
+					log.VEventf(ctx, 1, "re-encrypted %s in %s",
+						humanizeutil.IBytes(n), timeutil.Since(start))
+				}
+			}
+			cancel()
+		}
+	})
+}
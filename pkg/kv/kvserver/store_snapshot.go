@@ -393,7 +393,7 @@ func (kvSS *kvBatchSnapshotStrategy) Send(
 
 	rangeID := header.State.Desc.RangeID
 
-	if err := iterateEntries(ctx, snap.EngineSnap, rangeID, firstIndex, endIndex, scanFunc); err != nil {
+	if err := iterateEntries(ctx, snap.RaftEngineSnap, rangeID, firstIndex, endIndex, scanFunc); err != nil {
 		return 0, err
 	}
 
@@ -967,6 +967,7 @@ func SendEmptySnapshot(
 		// quickly.
 		SnapshotRequest_VIA_SNAPSHOT_QUEUE,
 		eng,
+		eng,
 		desc.RangeID,
 		raftentry.NewCache(1), // cache is not used
 		func(func(SideloadStorage) error) error { return nil }, // this is used for sstables, not needed here as there are no logs
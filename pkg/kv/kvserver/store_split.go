@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"go.etcd.io/etcd/raft/v3"
 	"go.etcd.io/etcd/raft/v3/raftpb"
@@ -321,12 +322,21 @@ func (s *Store) SplitRange(
 	// spans that are now owned by the new range.
 	leftRepl.leaseholderStats.resetRequestCounts()
 
+	// Clear the LHS's load based splitting state for the same reason. This
+	// also restarts the collection of its QPS history, as does that of the RHS
+	// below. If the split was due to load, the split queue then keeps the merge
+	// queue from merging either side away until it has observed their load for
+	// the merge delay; see Decider.RecordLoadSplit.
+	now := timeutil.Now()
+	leftRepl.loadBasedSplitter.Reset(now)
+
 	if rightReplOrNil == nil {
 		throwawayRightWriteStats := new(replicaStats)
 		leftRepl.writeStats.splitRequestCounts(throwawayRightWriteStats)
 	} else {
 		rightRepl := rightReplOrNil
 		leftRepl.writeStats.splitRequestCounts(rightRepl.writeStats)
+		rightRepl.loadBasedSplitter.Reset(now)
 		if err := s.addReplicaInternalLocked(rightRepl); err != nil {
 			return errors.Errorf("unable to add replica %v: %s", rightRepl, err)
 		}
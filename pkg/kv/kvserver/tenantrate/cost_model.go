@@ -0,0 +1,56 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tenantrate
+
+import "time"
+
+// RequestUnits is the unit in which the resources consumed by the KV requests
+// of tenants are measured, throttled and billed. The number of request units
+// charged for a request is given by the CostModel.
+type RequestUnits float64
+
+// CostModel converts the resources consumed by a KV request into request
+// units. A request is charged a fixed cost depending on whether it is a read
+// or a write, a cost proportional to the number of bytes it reads or writes,
+// and a cost proportional to its CPU usage. The CPU usage is approximated by
+// the time spent in the last evaluation of the request, which excludes the
+// time spent waiting for latches and locks, evaluations which were retried,
+// and the replication of writes.
+//
+// The cost model is configured by cluster settings, so that the requests of a
+// tenant are charged consistently across the nodes of the cluster.
+type CostModel struct {
+	ReadRequestCost      RequestUnits
+	ReadCostPerMegabyte  RequestUnits
+	WriteRequestCost     RequestUnits
+	WriteCostPerMegabyte RequestUnits
+	CPUCostPerSecond     RequestUnits
+}
+
+const bytesPerMegabyte = 1 << 20
+
+// RequestCost returns the cost of a request, which is known before the request
+// is evaluated.
+func (c *CostModel) RequestCost(isWrite bool, writeBytes int64) RequestUnits {
+	if isWrite {
+		return c.WriteRequestCost +
+			c.WriteCostPerMegabyte*RequestUnits(writeBytes)/bytesPerMegabyte
+	}
+	return c.ReadRequestCost
+}
+
+// ResponseCost returns the cost of a request which is only known once it has
+// been evaluated, given the number of bytes it read and the time spent
+// evaluating it.
+func (c *CostModel) ResponseCost(readBytes int64, cpuTime time.Duration) RequestUnits {
+	return c.ReadCostPerMegabyte*RequestUnits(readBytes)/bytesPerMegabyte +
+		c.CPUCostPerSecond*RequestUnits(cpuTime.Seconds())
+}
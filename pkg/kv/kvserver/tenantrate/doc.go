@@ -11,6 +11,12 @@
 // Package tenantrate contains logic for rate limiting client requests on a
 // per-tenant basis.
 //
+// Requests are charged in request units, according to a CostModel which
+// accounts for the requests themselves, the bytes they read and write, and
+// their CPU usage. Each tenant is limited by a token bucket of request units,
+// whose rate and burst limits are configured by cluster settings unless the
+// tenant has resource limits of its own in the system.tenants table.
+//
 // The package exposes a Factory which can be used to acquire a reference to a
 // per-tenant Limiter. See the comment on Limiter for more details on the
 // implementation and behavior.
@@ -32,8 +32,13 @@ type LimiterFactory struct {
 	systemLimiter systemLimiter
 	mu            struct {
 		syncutil.RWMutex
-		limits  LimitConfigs
-		tenants map[roachpb.TenantID]*refCountedLimiter
+		// defaultLimits are the limits of the tenants without resource limits
+		// of their own, derived from cluster settings.
+		defaultLimits LimitConfig
+		// tenantLimits are the resource limits of the tenants which have them,
+		// as configured in the system.tenants table.
+		tenantLimits map[roachpb.TenantID]LimitConfig
+		tenants      map[roachpb.TenantID]*refCountedLimiter
 	}
 }
 
@@ -53,8 +58,9 @@ func NewLimiterFactory(st *cluster.Settings, knobs *TestingKnobs) *LimiterFactor
 		rl.knobs = *knobs
 	}
 	rl.mu.tenants = make(map[roachpb.TenantID]*refCountedLimiter)
-	rl.mu.limits = LimitConfigsFromSettings(st)
+	rl.mu.defaultLimits = LimitConfigFromSettings(st)
 	rl.systemLimiter = systemLimiter{
+		parent:        rl,
 		tenantMetrics: rl.metrics.tenantMetrics(roachpb.SystemTenantID),
 	}
 	for _, setOnChange := range settingsSetOnChangeFuncs {
@@ -86,7 +92,7 @@ func (rl *LimiterFactory) GetTenant(tenantID roachpb.TenantID, closer <-chan str
 			options = append(options, quotapool.WithCloser(closer))
 		}
 		rcLim = new(refCountedLimiter)
-		rcLim.lim.init(rl, tenantID, rl.mu.limits, rl.metrics.tenantMetrics(tenantID), options...)
+		rcLim.lim.init(rl, tenantID, rl.limitsLocked(tenantID), rl.metrics.tenantMetrics(tenantID), options...)
 		rl.mu.tenants[tenantID] = rcLim
 	}
 	rcLim.refCount++
@@ -114,15 +120,52 @@ func (rl *LimiterFactory) Release(lim Limiter) {
 	}
 }
 
+// UpdateTenantLimits replaces the resource limits of the tenants which have
+// limits of their own. The tenants which are not present in the map are
+// subject to the limits configured by cluster settings.
+func (rl *LimiterFactory) UpdateTenantLimits(limits map[roachpb.TenantID]LimitConfig) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	prev := rl.mu.tenantLimits
+	rl.mu.tenantLimits = limits
+	for tenantID, rcLim := range rl.mu.tenants {
+		prevLimits, hadLimits := prev[tenantID]
+		newLimits, hasLimits := limits[tenantID]
+		if hadLimits != hasLimits || prevLimits != newLimits {
+			rcLim.lim.updateLimits(rl.limitsLocked(tenantID))
+		}
+	}
+}
+
 func (rl *LimiterFactory) updateLimits() {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
-	rl.mu.limits = LimitConfigsFromSettings(rl.settings)
-	for _, rcLim := range rl.mu.tenants {
-		rcLim.lim.updateLimits(rl.mu.limits)
+	rl.mu.defaultLimits = LimitConfigFromSettings(rl.settings)
+	for tenantID, rcLim := range rl.mu.tenants {
+		rcLim.lim.updateLimits(rl.limitsLocked(tenantID))
 	}
 }
 
+// TenantLimits returns the limits which apply to the given tenant.
+func (rl *LimiterFactory) TenantLimits(tenantID roachpb.TenantID) LimitConfig {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return rl.limitsLocked(tenantID)
+}
+
+// limitsLocked returns the limits which apply to the given tenant.
+func (rl *LimiterFactory) limitsLocked(tenantID roachpb.TenantID) LimitConfig {
+	if limits, ok := rl.mu.tenantLimits[tenantID]; ok {
+		return limits
+	}
+	return rl.mu.defaultLimits
+}
+
+// costModel returns the CostModel used to charge the requests of tenants.
+func (rl *LimiterFactory) costModel() CostModel {
+	return CostModelFromSettings(rl.settings)
+}
+
 // Metrics returns the LimiterFactory's metric.Struct.
 func (rl *LimiterFactory) Metrics() *Metrics {
 	return &rl.metrics
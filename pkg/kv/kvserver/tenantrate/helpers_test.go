@@ -12,15 +12,14 @@ package tenantrate
 
 import "github.com/cockroachdb/cockroach/pkg/settings/cluster"
 
-// OverrideSettingsWithRateLimits utilizes LimitConfigs from the values stored in the
-// settings.
-func OverrideSettingsWithRateLimits(settings *cluster.Settings, rl LimitConfigs) {
-	readRequestRateLimit.Override(&settings.SV, float64(rl.ReadRequests.Rate))
-	readRequestBurstLimit.Override(&settings.SV, rl.ReadRequests.Burst)
-	writeRequestRateLimit.Override(&settings.SV, float64(rl.WriteRequests.Rate))
-	writeRequestBurstLimit.Override(&settings.SV, rl.WriteRequests.Burst)
-	readRateLimit.Override(&settings.SV, int64(rl.ReadBytes.Rate))
-	readBurstLimit.Override(&settings.SV, rl.ReadBytes.Burst)
-	writeRateLimit.Override(&settings.SV, int64(rl.WriteBytes.Rate))
-	writeBurstLimit.Override(&settings.SV, rl.WriteBytes.Burst)
+// OverrideSettings stores the provided default LimitConfig and CostModel in
+// the settings.
+func OverrideSettings(settings *cluster.Settings, limits LimitConfig, costs CostModel) {
+	rateLimit.Override(&settings.SV, float64(limits.Rate))
+	burstLimit.Override(&settings.SV, float64(limits.Burst))
+	readRequestCost.Override(&settings.SV, float64(costs.ReadRequestCost))
+	readCostPerMegabyte.Override(&settings.SV, float64(costs.ReadCostPerMegabyte))
+	writeRequestCost.Override(&settings.SV, float64(costs.WriteRequestCost))
+	writeCostPerMegabyte.Override(&settings.SV, float64(costs.WriteCostPerMegabyte))
+	cpuCostPerSecond.Override(&settings.SV, float64(costs.CPUCostPerSecond))
 }
@@ -26,43 +26,41 @@ import (
 // The use of an interface permits a different implementation for the system
 // tenant and other tenants. The remaining commentary will pertain to the
 // implementation used for non-system tenants. The limiter is implemented as
-// a multi-dimensional token-bucket. The dimensions are Requests, WriteBytes,
-// and ReadBytes. Each dimension has a rate and burst limit per tenant. These
-// limits are controlled via cluster settings.
+// a token-bucket of request units. The cost of each request in request units
+// is determined by the CostModel, and the rate and burst limits of the bucket
+// are controlled via cluster settings, unless the tenant has resource limits
+// of its own in the system.tenants table.
 //
-// Calls to Wait interact with all three dimensions. The Wait call takes a
-// quantity of writeBytes which it attempts to consume from the Limiter. It also
-// attempts to consume 1 request and 1 readByte. This readByte "courtesy"
-// allocation is used in conjunction with the RecordRead method to control the
-// rate of reads despite not knowing how much data a request will read a priori.
-// If a request attempts to consume more than the burst limit in any dimension,
-// it can proceed only if the token bucket is completely full in that dimension.
-// In that case, the acquisition will put the Limiter into debt in that
-// dimension, meaning that its current quota is negative. Future acquisitions
-// will need to wait until the debt is paid off. Calls to RecordRead subtract
-// the indicated byte quantity from the token bucket regardless of its current
-// value. RecordRead can push the limiter into debt, blocking future requests
-// until that debt is paid.
+// Calls to Wait consume the part of the cost of a request which is known
+// before the request is evaluated: the cost of the request itself and of the
+// bytes it writes. The part of the cost which depends on the outcome of the
+// request, i.e. the bytes it reads and the time spent evaluating it, is
+// subtracted from the token bucket by RecordResponse, regardless of its
+// current value. If a request attempts to consume more than the burst limit,
+// it can proceed only if the token bucket is completely full. In that case,
+// and when RecordResponse is called, the Limiter can be put into debt, meaning
+// that its current quota is negative. Future acquisitions will need to wait
+// until the debt is paid off.
 //
 // The Limiter is backed by a FIFO queue which provides fairness.
 type Limiter interface {
 
-	// Wait acquires n quota from the limiter. This acquisition cannot be
-	// released. Each call to wait will consume 1 read or write request
-	// depending on isWrite, 1 read byte, and writeBytes from the token buckets.
-	// Calls to Wait will block until the buckets contain adequate resources. If
-	// a request attempts to write more than the burst limit, it will wait until
-	// the bucket is completely full before acquiring the requested quantity and
-	// putting the limiter in debt.
+	// Wait acquires the request units needed by a request from the limiter.
+	// This acquisition cannot be released. Calls to Wait will block until the
+	// bucket contains adequate resources. If a request attempts to consume
+	// more than the burst limit, it will wait until the bucket is completely
+	// full before acquiring the requested quantity and putting the limiter in
+	// debt.
 	//
 	// The only errors which should be returned are due to the context.
 	Wait(ctx context.Context, isWrite bool, writeBytes int64) error
 
-	// RecordRead subtracts the bytes read by a request from the token bucket.
-	// This call may push the Limiter into debt in the ReadBytes dimensions
-	// forcing subsequent Wait calls to block until the debt is paid.
-	// However, RecordRead itself will never block.
-	RecordRead(ctx context.Context, readBytes int64)
+	// RecordResponse subtracts the request units consumed by a request, as
+	// determined by the bytes it read and the time spent evaluating it, from
+	// the token bucket. This call may push the Limiter into debt, forcing
+	// subsequent Wait calls to block until the debt is paid. However,
+	// RecordResponse itself will never block.
+	RecordResponse(ctx context.Context, readBytes int64, cpuTime time.Duration)
 }
 
 type limiter struct {
@@ -76,7 +74,7 @@ type limiter struct {
 func (rl *limiter) init(
 	parent *LimiterFactory,
 	tenantID roachpb.TenantID,
-	conf LimitConfigs,
+	conf LimitConfig,
 	metrics tenantMetrics,
 	options ...quotapool.Option,
 ) {
@@ -85,39 +83,33 @@ func (rl *limiter) init(
 		tenantID: tenantID,
 		metrics:  metrics,
 	}
-	buckets := tokenBuckets{
-		readRequests:  makeTokenBucket(conf.ReadRequests),
-		writeRequests: makeTokenBucket(conf.WriteRequests),
-		readBytes:     makeTokenBucket(conf.ReadBytes),
-		writeBytes:    makeTokenBucket(conf.WriteBytes),
+	bucket := &tokenBucket{
+		config: conf,
+		tokens: conf.Burst,
 	}
 	options = append(options, quotapool.OnAcquisition(func(
 		ctx context.Context, poolName string, r quotapool.Request, start time.Time,
 	) {
 		req := r.(*waitRequest)
-		if req.readRequests > 0 {
-			rl.metrics.readRequestsAdmitted.Inc(req.readRequests)
-		}
-		if req.writeRequests > 0 {
-			rl.metrics.writeRequestsAdmitted.Inc(req.writeRequests)
-		}
-		// Accounted for in limiter.RecordRead.
-		// if req.readBytes > 0 {
-		// 	rl.metrics.readBytesAdmitted.Inc(req.readBytes)
-		// }
-		if req.writeBytes > 0 {
+		if req.isWrite {
+			rl.metrics.writeRequestsAdmitted.Inc(1)
 			rl.metrics.writeBytesAdmitted.Inc(req.writeBytes)
+		} else {
+			rl.metrics.readRequestsAdmitted.Inc(1)
 		}
+		rl.metrics.requestUnitsConsumed.Inc(float64(req.units))
 	}))
-	rl.qp = quotapool.New(tenantID.String(), &buckets, options...)
-	buckets.clock = rl.qp.TimeSource()
-	buckets.lastUpdated = buckets.clock.Now()
+	rl.qp = quotapool.New(tenantID.String(), bucket, options...)
+	bucket.clock = rl.qp.TimeSource()
+	bucket.lastUpdated = bucket.clock.Now()
 }
 
+// Wait is part of the Limiter interface.
 func (rl *limiter) Wait(ctx context.Context, isWrite bool, writeBytes int64) error {
 	rl.metrics.currentBlocked.Inc(1)
 	defer rl.metrics.currentBlocked.Dec(1)
-	r := newWaitRequest(isWrite, writeBytes)
+	costModel := rl.parent.costModel()
+	r := newWaitRequest(isWrite, writeBytes, costModel.RequestCost(isWrite, writeBytes))
 	defer putWaitRequest(r)
 	if err := rl.qp.Acquire(ctx, r); err != nil {
 		return err
@@ -125,88 +117,88 @@ func (rl *limiter) Wait(ctx context.Context, isWrite bool, writeBytes int64) err
 	return nil
 }
 
-func (rl *limiter) RecordRead(ctx context.Context, readBytes int64) {
-	rb := newReadBytesResource(readBytes)
-	defer putReadBytesResource(rb)
+// RecordResponse is part of the Limiter interface.
+func (rl *limiter) RecordResponse(ctx context.Context, readBytes int64, cpuTime time.Duration) {
+	costModel := rl.parent.costModel()
+	units := costModel.ResponseCost(readBytes, cpuTime)
 	rl.metrics.readBytesAdmitted.Inc(readBytes)
-	rl.qp.Add(rb)
+	rl.metrics.requestUnitsConsumed.Inc(float64(units))
+	ru := newConsumedUnitsResource(units)
+	defer putConsumedUnitsResource(ru)
+	rl.qp.Add(ru)
 }
 
 // updateLimits is used by the factory to inform the limiter of a new
 // configuration.
-func (rl *limiter) updateLimits(limits LimitConfigs) {
+func (rl *limiter) updateLimits(limits LimitConfig) {
 	rl.qp.Add(limits)
 }
 
-// tokenBuckets is the implementation of Resource which remains in the quotapool
-// for a limiter.
-type tokenBuckets struct {
-	clock         timeutil.TimeSource
-	lastUpdated   time.Time
-	readRequests  tokenBucket
-	writeRequests tokenBucket
-	readBytes     tokenBucket
-	writeBytes    tokenBucket
+// tokenBucket represents the token bucket of request units of a tenant.
+// It implements quotapool.Resource.
+type tokenBucket struct {
+	clock       timeutil.TimeSource
+	lastUpdated time.Time
+	config      LimitConfig
+	tokens      RequestUnits
 }
 
-var _ quotapool.Resource = (*tokenBuckets)(nil)
+var _ quotapool.Resource = (*tokenBucket)(nil)
 
-func (rb *tokenBuckets) update() {
-	now := rb.clock.Now()
+// update accounts for the passage of time since the last update.
+func (tb *tokenBucket) update() {
+	now := tb.clock.Now()
 
 	// Update token bucket capacity given the passage of clock.
 	// TODO(ajwerner): Consider instituting a minimum update frequency to avoid
 	// spinning too fast on timers for tons of tiny allocations at a fast rate.
-	if since := now.Sub(rb.lastUpdated); since > 0 {
-		rb.readRequests.update(since)
-		rb.writeRequests.update(since)
-		rb.readBytes.update(since)
-		rb.writeBytes.update(since)
-		rb.lastUpdated = now
+	if since := now.Sub(tb.lastUpdated); since > 0 {
+		tb.tokens += RequestUnits(float64(tb.config.Rate) * since.Seconds())
+		tb.clampTokens()
+		tb.lastUpdated = now
 	}
 }
 
-// check determines whether a request can be fulfilled by the given tokens in
-// the bucket. If not, it determines when the buckets will be adequately full
-// to fulfill the request.
-func (rb *tokenBuckets) check(req *waitRequest) (fulfilled bool, tryAgainAfter time.Duration) {
-	fulfilled = true
-	check := func(t *tokenBucket, needed int64) {
-		if ok, after := t.check(needed); !ok {
-			fulfilled = false
-			if after > tryAgainAfter {
-				tryAgainAfter = after
-			}
-		}
+// check returns whether needed will be satisfied by the tokens in the bucket.
+// Note that the definition of satisfied is either that the tokens exceed
+// needed or that the bucket is full. This is because we want to have requests
+// put the rate limiter in debt rather than prevent execution of requests.
+//
+// If the request is not satisfied, the amount of clock that must be waited for
+// the request to be satisfied at the current rate is returned.
+func (tb *tokenBucket) check(needed RequestUnits) (fulfilled bool, tryAgainAfter time.Duration) {
+	if needed <= tb.tokens || tb.tokens >= tb.config.Burst {
+		return true, 0
 	}
-	check(&rb.readRequests, req.readRequests)
-	check(&rb.writeRequests, req.writeRequests)
-	check(&rb.readBytes, req.readBytes)
-	check(&rb.writeBytes, req.writeBytes)
-	return fulfilled, tryAgainAfter
-}
 
-func (rb *tokenBuckets) subtract(req *waitRequest) {
-	rb.readRequests.tokens -= float64(req.readRequests)
-	rb.writeRequests.tokens -= float64(req.writeRequests)
-	rb.readBytes.tokens -= float64(req.readBytes)
-	rb.writeBytes.tokens -= float64(req.writeBytes)
+	// We'll calculate the amount of clock until the quota is full if we're
+	// requesting more than the burst limit.
+	if needed > tb.config.Burst {
+		needed = tb.config.Burst
+	}
+	delta := float64(needed - tb.tokens)
+	tryAgainAfter = time.Duration((delta * float64(time.Second)) / float64(tb.config.Rate))
+	return false, tryAgainAfter
 }
 
-func (rb *tokenBuckets) Merge(val interface{}) (shouldNotify bool) {
+// Merge is part of quotapool.Resource.
+func (tb *tokenBucket) Merge(val interface{}) (shouldNotify bool) {
 	switch toAdd := val.(type) {
-	case LimitConfigs:
+	case LimitConfig:
 		// Account for the accumulation since lastUpdate and now under the old
 		// configuration.
-		rb.update()
-
-		rb.readRequests.setConf(toAdd.ReadRequests)
-		rb.writeRequests.setConf(toAdd.WriteRequests)
-		rb.readBytes.setConf(toAdd.ReadBytes)
-		rb.writeBytes.setConf(toAdd.WriteBytes)
+		tb.update()
+
+		// TODO(ajwerner): It seems possible that when adding or reducing the burst
+		// values that we might want to remove those values from the token bucket.
+		// It's not obvious that we want to add tokens when increasing the burst as
+		// that might lead to a big spike in load immediately upon increasing this
+		// limit.
+		tb.config = toAdd
+		tb.clampTokens()
 		return true
-	case *readBytesResource:
-		rb.readBytes.tokens -= float64(*toAdd)
+	case *consumedUnitsResource:
+		tb.tokens -= RequestUnits(*toAdd)
 		// Do not notify the head of the queue. In the best case we did not disturb
 		// the time at which it can be fulfilled and in the worst case, we made it
 		// further in the future.
@@ -216,94 +208,34 @@ func (rb *tokenBuckets) Merge(val interface{}) (shouldNotify bool) {
 	}
 }
 
-// tokenBucket represents a token bucket for a given resource and its associated
-// configuration.
-type tokenBucket struct {
-	LimitConfig
-	tokens float64
-}
-
-func makeTokenBucket(rl LimitConfig) tokenBucket {
-	return tokenBucket{
-		LimitConfig: rl,
-		tokens:      float64(rl.Burst),
-	}
-}
-
-// update applies the positive time delta update for the resource.
-func (t *tokenBucket) update(deltaT time.Duration) {
-	t.tokens += float64(t.Rate) * deltaT.Seconds()
-	t.clampTokens()
-}
-
-// checkQuota returns whether needed will be satisfied by quota. Note that the
-// definition of satisfied is either that the integer part of quota exceeds
-// needed or that quota is equal to the burst. This is because we want to
-// have request put the rate limiter in debt rather than prevent execution of
-// requests.
-//
-// If the request is not satisfied, the amount of clock that must be waited for
-// the request to be satisfied at the current rate is returned.
-func (t *tokenBucket) check(needed int64) (fulfilled bool, tryAgainAfter time.Duration) {
-	if q := int64(t.tokens); needed <= q || q == t.Burst {
-		return true, 0
-	}
-
-	// We'll calculate the amount of clock until the quota is full if we're
-	// requesting more than the burst limit.
-	if needed > t.Burst {
-		needed = t.Burst
-	}
-	delta := float64(needed) - t.tokens
-	tryAgainAfter = time.Duration((delta * float64(time.Second)) / float64(t.Rate))
-	return false, tryAgainAfter
-}
-
-// setConf updates the configuration for a tokenBucket.
-//
-// TODO(ajwerner): It seems possible that when adding or reducing the burst
-// values that we might want to remove those values from the token bucket.
-// It's not obvious that we want to add tokens when increasing the burst as
-// that might lead to a big spike in load immediately upon increasing this
-// limit.
-func (t *tokenBucket) setConf(rl LimitConfig) {
-	t.LimitConfig = rl
-	t.clampTokens()
-}
-
 // clampTokens ensures that tokens does not exceed burst.
-func (t *tokenBucket) clampTokens() {
-	if burst := float64(t.Burst); t.tokens > burst {
-		t.tokens = burst
+func (tb *tokenBucket) clampTokens() {
+	if tb.tokens > tb.config.Burst {
+		tb.tokens = tb.config.Burst
 	}
 }
 
-// waitRequest is used to wait for adequate resources in the tokenBuckets.
+// waitRequest is used to wait for adequate resources in the tokenBucket.
 type waitRequest struct {
-	readRequests  int64
-	writeRequests int64
-	writeBytes    int64
-	readBytes     int64
+	isWrite    bool
+	writeBytes int64
+	units      RequestUnits
 }
 
+var _ quotapool.Request = (*waitRequest)(nil)
+
 var waitRequestSyncPool = sync.Pool{
 	New: func() interface{} { return new(waitRequest) },
 }
 
 // newWaitRequest allocates a waitRequest from the sync.Pool.
 // It should be returned with putWaitRequest.
-func newWaitRequest(isWrite bool, writeBytes int64) *waitRequest {
+func newWaitRequest(isWrite bool, writeBytes int64, units RequestUnits) *waitRequest {
 	r := waitRequestSyncPool.Get().(*waitRequest)
 	*r = waitRequest{
-		readRequests:  0,
-		writeRequests: 0,
-		readBytes:     1,
-		writeBytes:    writeBytes,
-	}
-	if isWrite {
-		r.writeRequests = 1
-	} else {
-		r.readRequests = 1
+		isWrite:    isWrite,
+		writeBytes: writeBytes,
+		units:      units,
 	}
 	return r
 }
@@ -313,35 +245,40 @@ func putWaitRequest(r *waitRequest) {
 	waitRequestSyncPool.Put(r)
 }
 
-type readBytesResource int64
+// consumedUnitsResource is a quotapool.Resource used to subtract the request
+// units consumed by a request from the tokenBucket after the request has been
+// evaluated.
+type consumedUnitsResource RequestUnits
 
-var readBytesResourceSyncPool = sync.Pool{
-	New: func() interface{} { return new(readBytesResource) },
+var consumedUnitsResourceSyncPool = sync.Pool{
+	New: func() interface{} { return new(consumedUnitsResource) },
 }
 
-func newReadBytesResource(readBytes int64) *readBytesResource {
-	rb := readBytesResourceSyncPool.Get().(*readBytesResource)
-	*rb = readBytesResource(readBytes)
-	return rb
+func newConsumedUnitsResource(units RequestUnits) *consumedUnitsResource {
+	ru := consumedUnitsResourceSyncPool.Get().(*consumedUnitsResource)
+	*ru = consumedUnitsResource(units)
+	return ru
 }
 
-func putReadBytesResource(rb *readBytesResource) {
-	*rb = 0
-	readBytesResourceSyncPool.Put(rb)
+func putConsumedUnitsResource(ru *consumedUnitsResource) {
+	*ru = 0
+	consumedUnitsResourceSyncPool.Put(ru)
 }
 
+// Acquire is part of quotapool.Request.
 func (req *waitRequest) Acquire(
 	ctx context.Context, res quotapool.Resource,
 ) (fulfilled bool, tryAgainAfter time.Duration) {
-	r := res.(*tokenBuckets)
-	r.update()
-	if fulfilled, tryAgainAfter = r.check(req); !fulfilled {
+	tb := res.(*tokenBucket)
+	tb.update()
+	if fulfilled, tryAgainAfter = tb.check(req.units); !fulfilled {
 		return false, tryAgainAfter
 	}
-	r.subtract(req)
+	tb.tokens -= req.units
 	return true, 0
 }
 
+// ShouldWait is part of quotapool.Request.
 func (req *waitRequest) ShouldWait() bool {
 	return true
 }
@@ -50,7 +50,7 @@ func TestCloser(t *testing.T) {
 	// First Wait call will not block.
 	require.NoError(t, limiter.Wait(ctx, false, 1))
 	errCh := make(chan error, 1)
-	go func() { errCh <- limiter.Wait(ctx, false, 1<<30) }()
+	go func() { errCh <- limiter.Wait(ctx, true, 1<<30) }()
 	testutils.SucceedsSoon(t, func() error {
 		if timers := timeSource.Timers(); len(timers) != 1 {
 			return errors.Errorf("expected 1 timer, found %d", len(timers))
@@ -94,17 +94,18 @@ func (s launchState) String() string {
 }
 
 var testStateCommands = map[string]func(*testState, *testing.T, *datadriven.TestData) string{
-	"init":            (*testState).init,
-	"update_settings": (*testState).updateSettings,
-	"advance":         (*testState).advance,
-	"launch":          (*testState).launch,
-	"await":           (*testState).await,
-	"cancel":          (*testState).cancel,
-	"record_read":     (*testState).recordRead,
-	"timers":          (*testState).timers,
-	"metrics":         (*testState).metrics,
-	"get_tenants":     (*testState).getTenants,
-	"release_tenants": (*testState).releaseTenants,
+	"init":                 (*testState).init,
+	"update_settings":      (*testState).updateSettings,
+	"update_tenant_limits": (*testState).updateTenantLimits,
+	"advance":              (*testState).advance,
+	"launch":               (*testState).launch,
+	"await":                (*testState).await,
+	"cancel":               (*testState).cancel,
+	"record_response":      (*testState).recordResponse,
+	"timers":               (*testState).timers,
+	"metrics":              (*testState).metrics,
+	"get_tenants":          (*testState).getTenants,
+	"release_tenants":      (*testState).releaseTenants,
 }
 
 func (ts *testState) run(t *testing.T, d *datadriven.TestData) string {
@@ -122,14 +123,21 @@ const timeFormat = "15:04:05.000"
 
 var t0 = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
 
+// testConfig is the yaml serialization of the configuration stored in the
+// cluster settings: the default LimitConfig and the CostModel. Fields which
+// are omitted retain their current values.
+type testConfig struct {
+	Limits tenantrate.LimitConfig
+	Costs  tenantrate.CostModel
+}
+
 // init is called at the beginning of a test. It must be the first command.
-// The argument is a yaml serialization of LimitConfigs. It returns the time as
+// The argument is a yaml serialization of a testConfig. It returns the time as
 // of initialization (00:00:00.000). For example:
 //
 //  init
-//  requests: { rate: 1, burst: 2 }
-//  readbytes: { rate: 1024, burst: 2048 }
-//  writebytes: { rate: 1024, burst: 2048 }
+//  limits: { rate: 1, burst: 2 }
+//  costs: { readrequestcost: 1, writerequestcost: 1, writecostpermegabyte: 1024 }
 //  ----
 //  00:00:00.000
 //
@@ -142,9 +150,7 @@ func (ts *testState) init(t *testing.T, d *datadriven.TestData) string {
 	ts.tenants = make(map[roachpb.TenantID][]tenantrate.Limiter)
 	ts.clock = timeutil.NewManualTime(t0)
 	ts.settings = cluster.MakeTestingClusterSettings()
-	limits := tenantrate.LimitConfigsFromSettings(ts.settings)
-	parseLimits(t, d, &limits)
-	tenantrate.OverrideSettingsWithRateLimits(ts.settings, limits)
+	ts.parseAndOverrideSettings(t, d)
 	ts.rl = tenantrate.NewLimiterFactory(ts.settings, &tenantrate.TestingKnobs{
 		TimeSource: ts.clock,
 	})
@@ -153,13 +159,35 @@ func (ts *testState) init(t *testing.T, d *datadriven.TestData) string {
 	return ts.clock.Now().Format(timeFormat)
 }
 
-// updateSettings allows setting the rate and burst limits. It takes as input
-// yaml object representing the limits and updates accordingly. It returns
-// the current time. See init for more details as the semantics are the same.
+// updateSettings allows setting the default rate and burst limits and the cost
+// model. It takes as input yaml object representing the configuration and
+// updates accordingly. It returns the current time. See init for more details
+// as the semantics are the same.
 func (ts *testState) updateSettings(t *testing.T, d *datadriven.TestData) string {
-	limits := tenantrate.LimitConfigsFromSettings(ts.settings)
-	parseLimits(t, d, &limits)
-	tenantrate.OverrideSettingsWithRateLimits(ts.settings, limits)
+	ts.parseAndOverrideSettings(t, d)
+	return ts.formatTime()
+}
+
+// updateTenantLimits sets the resource limits of tenants, as would be done
+// upon a change to the system.tenants table. It takes as input a yaml map from
+// tenant ids to limits, and replaces all previously set tenant limits. It
+// returns the current time. For example:
+//
+//  update_tenant_limits
+//  2: { rate: 2, burst: 4 }
+//  ----
+//  00:00:00.000
+//
+func (ts *testState) updateTenantLimits(t *testing.T, d *datadriven.TestData) string {
+	var parsed map[uint64]tenantrate.LimitConfig
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &parsed); err != nil {
+		d.Fatalf(t, "failed to unmarshal tenant limits: %v", err)
+	}
+	limits := make(map[roachpb.TenantID]tenantrate.LimitConfig, len(parsed))
+	for id, l := range parsed {
+		limits[roachpb.MakeTenantID(id)] = l
+	}
+	ts.rl.UpdateTenantLimits(limits)
 	return ts.formatTime()
 }
 
@@ -295,32 +323,34 @@ func (ts *testState) cancel(t *testing.T, d *datadriven.TestData) string {
 	return ts.FormatRunning()
 }
 
-// recordRead accounts for bytes read from a request. It takes as input a
-// yaml list with fields tenant and readbytes. It returns the set of tasks
-// currently running like launch, await, and cancel.
+// recordResponse accounts for the bytes read by a request and the time spent
+// evaluating it. It takes as input a yaml list with fields tenant, readbytes
+// and cputime. It returns the set of tasks currently running like launch,
+// await, and cancel.
 //
 // For example:
 //
-//  record_read
-//  - { tenant: 2, readbytes: 32 }
+//  record_response
+//  - { tenant: 2, readbytes: 32, cputime: 10ms }
 //  ----
 //  [a@2]
 //
-func (ts *testState) recordRead(t *testing.T, d *datadriven.TestData) string {
-	var reads []struct {
+func (ts *testState) recordResponse(t *testing.T, d *datadriven.TestData) string {
+	var responses []struct {
 		Tenant    uint64
 		ReadBytes int64
+		CPUTime   time.Duration
 	}
-	if err := yaml.UnmarshalStrict([]byte(d.Input), &reads); err != nil {
-		d.Fatalf(t, "failed to unmarshal reads: %v", err)
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &responses); err != nil {
+		d.Fatalf(t, "failed to unmarshal responses: %v", err)
 	}
-	for _, r := range reads {
+	for _, r := range responses {
 		tid := roachpb.MakeTenantID(r.Tenant)
 		lims := ts.tenants[tid]
 		if len(lims) == 0 {
 			d.Fatalf(t, "no outstanding limiters for %v", tid)
 		}
-		lims[0].RecordRead(context.Background(), r.ReadBytes)
+		lims[0].RecordResponse(context.Background(), r.ReadBytes, r.CPUTime)
 	}
 	return ts.FormatRunning()
 }
@@ -344,6 +374,9 @@ func (ts *testState) recordRead(t *testing.T, d *datadriven.TestData) string {
 //  kv_tenant_rate_limit_read_requests_admitted 0
 //  kv_tenant_rate_limit_read_requests_admitted{tenant_id="2"} 0
 //  kv_tenant_rate_limit_read_requests_admitted{tenant_id="system"} 0
+//  kv_tenant_rate_limit_request_units_consumed 0.5
+//  kv_tenant_rate_limit_request_units_consumed{tenant_id="2"} 0.5
+//  kv_tenant_rate_limit_request_units_consumed{tenant_id="system"} 0
 //  kv_tenant_rate_limit_write_bytes_admitted 50
 //  kv_tenant_rate_limit_write_bytes_admitted{tenant_id="2"} 50
 //  kv_tenant_rate_limit_write_bytes_admitted{tenant_id="system"} 0
@@ -516,10 +549,15 @@ func parseTenantIDs(t *testing.T, d *datadriven.TestData) []uint64 {
 	return tenantIDs
 }
 
-func parseLimits(t *testing.T, d *datadriven.TestData, limits *tenantrate.LimitConfigs) {
-	if err := yaml.UnmarshalStrict([]byte(d.Input), &limits); err != nil {
-		d.Fatalf(t, "failed to unmarshal limits: %v", err)
+func (ts *testState) parseAndOverrideSettings(t *testing.T, d *datadriven.TestData) {
+	conf := testConfig{
+		Limits: tenantrate.LimitConfigFromSettings(ts.settings),
+		Costs:  tenantrate.CostModelFromSettings(ts.settings),
+	}
+	if err := yaml.UnmarshalStrict([]byte(d.Input), &conf); err != nil {
+		d.Fatalf(t, "failed to unmarshal settings: %v", err)
 	}
+	tenantrate.OverrideSettings(ts.settings, conf.Limits, conf.Costs)
 }
 
 func parseStrings(t *testing.T, d *datadriven.TestData) []string {
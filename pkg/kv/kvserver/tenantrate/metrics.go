@@ -24,6 +24,7 @@ type Metrics struct {
 	WriteRequestsAdmitted *aggmetric.AggCounter
 	ReadBytesAdmitted     *aggmetric.AggCounter
 	WriteBytesAdmitted    *aggmetric.AggCounter
+	RequestUnitsConsumed  *aggmetric.AggCounterFloat64
 }
 
 var _ metric.Struct = (*Metrics)(nil)
@@ -65,6 +66,12 @@ var (
 		Measurement: "Bytes",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaRequestUnitsConsumed = metric.Metadata{
+		Name:        "kv.tenant_rate_limit.request_units_consumed",
+		Help:        "Number of request units consumed by requests",
+		Measurement: "Request Units",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 // TenantIDLabel is the label used with metrics associated with a tenant.
@@ -80,6 +87,7 @@ func makeMetrics() Metrics {
 		WriteRequestsAdmitted: b.Counter(metaWriteRequestsAdmitted),
 		ReadBytesAdmitted:     b.Counter(metaReadBytesAdmitted),
 		WriteBytesAdmitted:    b.Counter(metaWriteBytesAdmitted),
+		RequestUnitsConsumed:  b.CounterFloat64(metaRequestUnitsConsumed),
 	}
 }
 
@@ -93,6 +101,7 @@ type tenantMetrics struct {
 	writeRequestsAdmitted *aggmetric.Counter
 	readBytesAdmitted     *aggmetric.Counter
 	writeBytesAdmitted    *aggmetric.Counter
+	requestUnitsConsumed  *aggmetric.CounterFloat64
 }
 
 func (m *Metrics) tenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
@@ -103,6 +112,7 @@ func (m *Metrics) tenantMetrics(tenantID roachpb.TenantID) tenantMetrics {
 		writeRequestsAdmitted: m.WriteRequestsAdmitted.AddChild(tid),
 		readBytesAdmitted:     m.ReadBytesAdmitted.AddChild(tid),
 		writeBytesAdmitted:    m.WriteBytesAdmitted.AddChild(tid),
+		requestUnitsConsumed:  m.RequestUnitsConsumed.AddChild(tid),
 	}
 }
 
@@ -112,4 +122,5 @@ func (tm *tenantMetrics) destroy() {
 	tm.writeRequestsAdmitted.Destroy()
 	tm.readBytesAdmitted.Destroy()
 	tm.writeBytesAdmitted.Destroy()
+	tm.requestUnitsConsumed.Destroy()
 }
@@ -18,104 +18,94 @@ import (
 // Limit defines a rate in units per second.
 type Limit float64
 
-// LimitConfig configures the rate limit and burst limit for a given resource.
+// LimitConfig configures the rate limit and burst limit of the token bucket of
+// a tenant, in request units.
 type LimitConfig struct {
 	Rate  Limit
-	Burst int64
+	Burst RequestUnits
 }
 
-// LimitConfigs configures the rate limits.
-// It is exported for convenience and testing.
-// The values are derived from cluster settings.
-type LimitConfigs struct {
-	ReadRequests  LimitConfig
-	WriteRequests LimitConfig
-	ReadBytes     LimitConfig
-	WriteBytes    LimitConfig
+// LimitConfigFromSettings constructs the LimitConfig of the tenants which do
+// not have resource limits of their own from the values stored in the
+// settings.
+func LimitConfigFromSettings(settings *cluster.Settings) LimitConfig {
+	return LimitConfig{
+		Rate:  Limit(rateLimit.Get(&settings.SV)),
+		Burst: RequestUnits(burstLimit.Get(&settings.SV)),
+	}
 }
 
-// LimitConfigsFromSettings constructs LimitConfigs from the values stored in
-// the settings.
-func LimitConfigsFromSettings(settings *cluster.Settings) LimitConfigs {
-	return LimitConfigs{
-		ReadRequests: LimitConfig{
-			Rate:  Limit(readRequestRateLimit.Get(&settings.SV)),
-			Burst: readRequestBurstLimit.Get(&settings.SV),
-		},
-		WriteRequests: LimitConfig{
-			Rate:  Limit(writeRequestRateLimit.Get(&settings.SV)),
-			Burst: writeRequestBurstLimit.Get(&settings.SV),
-		},
-		ReadBytes: LimitConfig{
-			Rate:  Limit(readRateLimit.Get(&settings.SV)),
-			Burst: readBurstLimit.Get(&settings.SV),
-		},
-		WriteBytes: LimitConfig{
-			Rate:  Limit(writeRateLimit.Get(&settings.SV)),
-			Burst: writeBurstLimit.Get(&settings.SV),
-		},
+// CostModelFromSettings constructs a CostModel from the values stored in the
+// settings.
+func CostModelFromSettings(settings *cluster.Settings) CostModel {
+	return CostModel{
+		ReadRequestCost:      RequestUnits(readRequestCost.Get(&settings.SV)),
+		ReadCostPerMegabyte:  RequestUnits(readCostPerMegabyte.Get(&settings.SV)),
+		WriteRequestCost:     RequestUnits(writeRequestCost.Get(&settings.SV)),
+		WriteCostPerMegabyte: RequestUnits(writeCostPerMegabyte.Get(&settings.SV)),
+		CPUCostPerSecond:     RequestUnits(cpuCostPerSecond.Get(&settings.SV)),
 	}
 }
 
 var (
-	readRequestRateLimit = settings.RegisterFloatSetting(
-		"kv.tenant_rate_limiter.read_requests.rate_limit",
-		"per-tenant read request rate limit in requests per second",
-		128,
+	rateLimit = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.rate_limit",
+		"per-tenant rate limit in request units per second, for tenants "+
+			"without resource limits of their own",
+		200,
 		settings.PositiveFloat,
 	)
 
-	readRequestBurstLimit = settings.RegisterIntSetting(
-		"kv.tenant_rate_limiter.read_requests.burst_limit",
-		"per-tenant read request burst limit in requests",
-		512,
-		settings.PositiveInt,
-	)
-
-	writeRequestRateLimit = settings.RegisterFloatSetting(
-		"kv.tenant_rate_limiter.write_requests.rate_limit",
-		"per-tenant write request rate limit in requests per second",
-		128,
+	burstLimit = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.burst_limit",
+		"per-tenant burst limit in request units, for tenants "+
+			"without resource limits of their own",
+		2000,
 		settings.PositiveFloat,
 	)
 
-	writeRequestBurstLimit = settings.RegisterIntSetting(
-		"kv.tenant_rate_limiter.write_requests.burst_limit",
-		"per-tenant write request burst limit in requests",
-		512,
-		settings.PositiveInt,
+	readRequestCost = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.read_request_cost",
+		"cost of a read request in request units",
+		0.7,
+		settings.NonNegativeFloat,
 	)
 
-	readRateLimit = settings.RegisterByteSizeSetting(
-		"kv.tenant_rate_limiter.read_bytes.rate_limit",
-		"per-tenant read rate limit in bytes per second",
-		1<<20 /* 1 MiB */)
+	readCostPerMegabyte = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.read_cost_per_megabyte",
+		"cost of a read in request units per megabyte read",
+		10,
+		settings.NonNegativeFloat,
+	)
 
-	readBurstLimit = settings.RegisterByteSizeSetting(
-		"kv.tenant_rate_limiter.read_bytes.burst_limit",
-		"per-tenant read burst limit in bytes",
-		16<<20 /* 16 MiB */)
+	writeRequestCost = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.write_request_cost",
+		"cost of a write request in request units",
+		1,
+		settings.NonNegativeFloat,
+	)
 
-	writeRateLimit = settings.RegisterByteSizeSetting(
-		"kv.tenant_rate_limiter.write_bytes.rate_limit",
-		"per-tenant write rate limit in bytes per second",
-		512<<10 /* 512 KiB */)
+	writeCostPerMegabyte = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.write_cost_per_megabyte",
+		"cost of a write in request units per megabyte written",
+		400,
+		settings.NonNegativeFloat,
+	)
 
-	writeBurstLimit = settings.RegisterByteSizeSetting(
-		"kv.tenant_rate_limiter.write_bytes.burst_limit",
-		"per-tenant write burst limit in bytes",
-		8<<20 /* 8 MiB */)
+	cpuCostPerSecond = settings.RegisterFloatSetting(
+		"kv.tenant_rate_limiter.cpu_cost_per_second",
+		"cost of the evaluation of requests in request units per second of "+
+			"evaluation",
+		1000,
+		settings.NonNegativeFloat,
+	)
 
 	// settingsSetOnChangeFuncs are the functions used to register the factory to
-	// be notified of changes to any of the settings which configure it.
+	// be notified of changes to any of the settings which configure the default
+	// limits. The cost model is instead read from the settings as requests are
+	// charged.
 	settingsSetOnChangeFuncs = [...]func(*settings.Values, func()){
-		readRequestRateLimit.SetOnChange,
-		readRequestBurstLimit.SetOnChange,
-		writeRequestRateLimit.SetOnChange,
-		writeRequestBurstLimit.SetOnChange,
-		readRateLimit.SetOnChange,
-		readBurstLimit.SetOnChange,
-		writeRateLimit.SetOnChange,
-		writeBurstLimit.SetOnChange,
+		rateLimit.SetOnChange,
+		burstLimit.SetOnChange,
 	}
 )
@@ -10,11 +10,15 @@
 
 package tenantrate
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // systemLimiter implements Limiter for the use of tracking metrics for the
 // system tenant. It does not actually perform any rate-limiting.
 type systemLimiter struct {
+	parent *LimiterFactory
 	tenantMetrics
 }
 
@@ -25,11 +29,15 @@ func (s systemLimiter) Wait(ctx context.Context, isWrite bool, writeBytes int64)
 		s.readRequestsAdmitted.Inc(1)
 	}
 	s.writeBytesAdmitted.Inc(writeBytes)
+	costModel := s.parent.costModel()
+	s.requestUnitsConsumed.Inc(float64(costModel.RequestCost(isWrite, writeBytes)))
 	return nil
 }
 
-func (s systemLimiter) RecordRead(ctx context.Context, readBytes int64) {
+func (s systemLimiter) RecordResponse(ctx context.Context, readBytes int64, cpuTime time.Duration) {
 	s.readBytesAdmitted.Inc(readBytes)
+	costModel := s.parent.costModel()
+	s.requestUnitsConsumed.Inc(float64(costModel.ResponseCost(readBytes, cpuTime)))
 }
 
 var _ Limiter = (*systemLimiter)(nil)
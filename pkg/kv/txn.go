@@ -17,11 +17,13 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -41,6 +43,10 @@ type Txn struct {
 	// It will be attached to all requests sent through this transaction.
 	gatewayNodeID roachpb.NodeID
 
+	// admissionHeader is attached to all the requests sent through this
+	// transaction which do not set their own, for use by admission control.
+	admissionHeader roachpb.AdmissionHeader
+
 	// The following fields are not safe for concurrent modification.
 	// They should be set before operating on the transaction.
 
@@ -112,11 +118,31 @@ func NewTxn(ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID) *Txn {
 
 // NewTxnWithSteppingEnabled is like NewTxn but suitable for use by SQL.
 func NewTxnWithSteppingEnabled(ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID) *Txn {
-	txn := NewTxn(ctx, db, gatewayNodeID)
+	txn := NewTxnWithAdmissionControl(ctx, db, gatewayNodeID,
+		roachpb.AdmissionHeader_FROM_SQL, admission.NormalPri)
 	_ = txn.ConfigureStepping(ctx, SteppingEnabled)
 	return txn
 }
 
+// NewTxnWithAdmissionControl is like NewTxn, but the requests sent through
+// the transaction are subject to admission control, with the given source
+// and priority.
+func NewTxnWithAdmissionControl(
+	ctx context.Context,
+	db *DB,
+	gatewayNodeID roachpb.NodeID,
+	source roachpb.AdmissionHeader_Source,
+	priority admission.WorkPriority,
+) *Txn {
+	txn := NewTxn(ctx, db, gatewayNodeID)
+	txn.admissionHeader = roachpb.AdmissionHeader{
+		Priority:   int32(priority),
+		CreateTime: timeutil.Now().UnixNano(),
+		Source:     source,
+	}
+	return txn
+}
+
 // NewTxnFromProto is like NewTxn but assumes the Transaction object is already initialized.
 // Do not use this directly; use NewTxn() instead.
 // This function exists for testing only.
@@ -154,6 +180,12 @@ func NewLeafTxn(
 	}
 	tis.Txn.AssertInitialized(ctx)
 	txn := &Txn{db: db, typ: LeafTxn, gatewayNodeID: gatewayNodeID}
+	// Leaf transactions are only used by DistSQL flows.
+	txn.admissionHeader = roachpb.AdmissionHeader{
+		Priority:   int32(admission.NormalPri),
+		CreateTime: timeutil.Now().UnixNano(),
+		Source:     roachpb.AdmissionHeader_FROM_SQL,
+	}
 	txn.mu.ID = tis.Txn.ID
 	txn.mu.userPriority = roachpb.NormalUserPriority
 	txn.mu.sender = db.factory.LeafTransactionalSender(tis)
@@ -911,6 +943,9 @@ func (txn *Txn) Send(
 	if txn.gatewayNodeID != 0 {
 		ba.Header.GatewayNodeID = txn.gatewayNodeID
 	}
+	if ba.AdmissionHeader == (roachpb.AdmissionHeader{}) {
+		ba.AdmissionHeader = txn.admissionHeader
+	}
 
 	txn.mu.Lock()
 	requestTxnID := txn.mu.ID
@@ -1222,6 +1222,12 @@ func (drr *DeleteRangeRequest) flags() int {
 	if drr.Inline {
 		return isRead | isWrite | isRange | isAlone
 	}
+	// Similarly, DeleteRange writing an MVCC range tombstone is not
+	// transactional. It consults the timestamp cache to avoid deleting keys
+	// below the reads that observed them.
+	if drr.UseRangeTombstone {
+		return isRead | isWrite | isRange | isAlone | consultsTSCache
+	}
 	// DeleteRange updates the timestamp cache as it doesn't leave intents or
 	// tombstones for keys which don't yet exist or keys that already have
 	// tombstones on them, but still wants to prevent anybody from writing under
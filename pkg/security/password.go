@@ -56,10 +56,24 @@ func appendEmptySha256(password string) []byte {
 	return append([]byte(password), sha256NewSum...)
 }
 
+// ErrPasswordMismatch is returned by CompareHashAndPassword when the password
+// does not match SCRAM credentials.
+var ErrPasswordMismatch = errors.New("password does not match SCRAM credentials")
+
 // CompareHashAndPassword tests that the provided bytes are equivalent to the
 // hash of the supplied password. If they are not equivalent, returns an
-// error.
+// error. Both bcrypt hashes and SCRAM-SHA-256 credentials are supported.
 func CompareHashAndPassword(hashedPassword []byte, password string) error {
+	if IsSCRAMHash(hashedPassword) {
+		creds, err := ParseSCRAMHash(hashedPassword)
+		if err != nil {
+			return err
+		}
+		if !creds.MatchesPassword(password) {
+			return ErrPasswordMismatch
+		}
+		return nil
+	}
 	return bcrypt.CompareHashAndPassword(hashedPassword, appendEmptySha256(password))
 }
 
@@ -68,6 +82,55 @@ func HashPassword(password string) ([]byte, error) {
 	return bcrypt.GenerateFromPassword(appendEmptySha256(password), BcryptCost)
 }
 
+// PasswordHashMethod identifies the algorithm used to hash passwords
+// stored in system.users.
+type PasswordHashMethod int64
+
+const (
+	// HashBCrypt is the CockroachDB-specific bcrypt-based hash.
+	HashBCrypt PasswordHashMethod = 1
+	// HashSCRAMSHA256 stores SCRAM-SHA-256 credentials, as Postgres does.
+	HashSCRAMSHA256 PasswordHashMethod = 2
+)
+
+// HashPasswordWithMethod takes a raw password and returns it hashed using
+// the given method.
+func HashPasswordWithMethod(method PasswordHashMethod, password string) ([]byte, error) {
+	switch method {
+	case HashBCrypt:
+		return HashPassword(password)
+	case HashSCRAMSHA256:
+		return HashPasswordSCRAM(password)
+	default:
+		return nil, errors.AssertionFailedf("unknown password hash method %d", method)
+	}
+}
+
+// PasswordHashMethodSetting is the cluster setting that configures the
+// method used to hash new passwords.
+var PasswordHashMethodSetting = settings.RegisterEnumSetting(
+	"server.user_login.password_encryption",
+	"which hash method to use to encode cleartext passwords passed via ALTER/CREATE USER/ROLE WITH PASSWORD",
+	"crdb-bcrypt",
+	map[int64]string{
+		int64(HashBCrypt):      "crdb-bcrypt",
+		int64(HashSCRAMSHA256): "scram-sha-256",
+	},
+).WithPublic()
+
+// UpgradeBcryptToSCRAM is the cluster setting that controls whether bcrypt
+// password hashes are converted to SCRAM-SHA-256 credentials when a user
+// logs in with a cleartext password, if
+// server.user_login.password_encryption is set to scram-sha-256.
+var UpgradeBcryptToSCRAM = settings.RegisterBoolSetting(
+	"server.user_login.upgrade_bcrypt_stored_passwords_to_scram.enabled",
+	"whether to automatically re-encode stored passwords using crdb-bcrypt to scram-sha-256 "+
+		"upon successful cleartext login, when server.user_login.password_encryption is scram-sha-256; "+
+		"while the conversion is in effect, the scram-sha-256 authentication method accepts a cleartext "+
+		"password from users without SCRAM credentials",
+	true,
+).WithPublic()
+
 // PromptForPassword prompts for a password.
 // This is meant to be used when using a password.
 func PromptForPassword() (string, error) {
@@ -0,0 +1,220 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"hash"
+	"strconv"
+
+	"github.com/cockroachdb/errors"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/secure/precis"
+)
+
+// This file implements the server side of the SCRAM-SHA-256 authentication
+// mechanism, as defined in RFC 5802 and RFC 7677. The credentials are stored
+// in system.users using the same format as the rolpassword column of
+// Postgres' pg_authid:
+//
+//   SCRAM-SHA-256$<iterations>:<salt>$<StoredKey>:<ServerKey>
+//
+// where the salt and keys are encoded in base64.
+
+// ScramIterCount is the number of PBKDF2 iterations used when computing new
+// SCRAM credentials. It is exposed for testing.
+//
+// This is the default used by Postgres, and the minimum recommended by
+// RFC 7677.
+var ScramIterCount = 4096
+
+const (
+	scramSHA256Prefix = "SCRAM-SHA-256$"
+	scramSaltLen      = 16
+	scramClientKey    = "Client Key"
+	scramServerKey    = "Server Key"
+)
+
+// SCRAMCredentials are the SCRAM-SHA-256 credentials of a user, from which
+// the server can verify a client proof without knowing the password.
+type SCRAMCredentials struct {
+	Iterations int
+	Salt       []byte
+	StoredKey  []byte
+	ServerKey  []byte
+}
+
+// HashPasswordSCRAM takes a raw password and returns its SCRAM-SHA-256
+// credentials, encoded in a form suitable for storage in system.users.
+func HashPasswordSCRAM(password string) ([]byte, error) {
+	salt := make([]byte, scramSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	creds := makeSCRAMCredentials(password, salt, ScramIterCount)
+	return creds.encode(), nil
+}
+
+func makeSCRAMCredentials(password string, salt []byte, iterations int) SCRAMCredentials {
+	saltedPassword := pbkdf2.Key(
+		[]byte(scramPreparePassword(password)), salt, iterations, sha256.Size, sha256.New,
+	)
+	clientKey := scramHMAC(saltedPassword, []byte(scramClientKey))
+	storedKey := sha256.Sum256(clientKey)
+	return SCRAMCredentials{
+		Iterations: iterations,
+		Salt:       salt,
+		StoredKey:  storedKey[:],
+		ServerKey:  scramHMAC(saltedPassword, []byte(scramServerKey)),
+	}
+}
+
+// scramPreparePassword normalizes a password before it is hashed. RFC 5802
+// mandates the use of SASLprep, which the OpaqueString profile of RFC 8265
+// supersedes. Like Postgres, the raw password is used if it cannot be
+// normalized.
+func scramPreparePassword(password string) string {
+	if prepared, err := precis.OpaqueString.String(password); err == nil {
+		return prepared
+	}
+	return password
+}
+
+func scramHMAC(key, msg []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+func (c *SCRAMCredentials) encode() []byte {
+	var buf bytes.Buffer
+	buf.WriteString(scramSHA256Prefix)
+	buf.WriteString(strconv.Itoa(c.Iterations))
+	buf.WriteByte(':')
+	buf.WriteString(base64.StdEncoding.EncodeToString(c.Salt))
+	buf.WriteByte('$')
+	buf.WriteString(base64.StdEncoding.EncodeToString(c.StoredKey))
+	buf.WriteByte(':')
+	buf.WriteString(base64.StdEncoding.EncodeToString(c.ServerKey))
+	return buf.Bytes()
+}
+
+// MockSCRAMCredentials returns the credentials presented to clients which
+// try to authenticate as a user without SCRAM credentials, including users
+// that don't exist. The salt is derived from a server secret and the user
+// name, so that it is stable across attempts and can't be told apart from
+// the salt of an actual user, and the iteration count is the one used for new
+// credentials. The keys are left empty: no client proof matches them.
+//
+// This mirrors what Postgres does in scram_mock_salt().
+func MockSCRAMCredentials(secret []byte, username SQLUsername) SCRAMCredentials {
+	return SCRAMCredentials{
+		Iterations: ScramIterCount,
+		Salt:       scramHMAC(secret, []byte(username.Normalized()))[:scramSaltLen],
+	}
+}
+
+// IsSCRAMHash returns true if the stored password hash contains SCRAM
+// credentials rather than a bcrypt hash.
+func IsSCRAMHash(hashedPassword []byte) bool {
+	return bytes.HasPrefix(hashedPassword, []byte(scramSHA256Prefix))
+}
+
+// ParseSCRAMHash decodes SCRAM credentials produced by HashPasswordSCRAM.
+func ParseSCRAMHash(hashedPassword []byte) (SCRAMCredentials, error) {
+	var creds SCRAMCredentials
+	if !IsSCRAMHash(hashedPassword) {
+		return creds, errors.New("not a SCRAM-SHA-256 hash")
+	}
+	parts := bytes.Split(hashedPassword[len(scramSHA256Prefix):], []byte("$"))
+	if len(parts) != 2 {
+		return creds, errors.New("malformed SCRAM-SHA-256 hash")
+	}
+	iterSalt := bytes.Split(parts[0], []byte(":"))
+	keys := bytes.Split(parts[1], []byte(":"))
+	if len(iterSalt) != 2 || len(keys) != 2 {
+		return creds, errors.New("malformed SCRAM-SHA-256 hash")
+	}
+	var err error
+	if creds.Iterations, err = strconv.Atoi(string(iterSalt[0])); err != nil || creds.Iterations <= 0 {
+		return creds, errors.New("malformed SCRAM-SHA-256 iteration count")
+	}
+	if creds.Salt, err = base64.StdEncoding.DecodeString(string(iterSalt[1])); err != nil {
+		return creds, errors.Wrap(err, "malformed SCRAM-SHA-256 salt")
+	}
+	if creds.StoredKey, err = base64.StdEncoding.DecodeString(string(keys[0])); err != nil ||
+		len(creds.StoredKey) != sha256.Size {
+		return creds, errors.New("malformed SCRAM-SHA-256 stored key")
+	}
+	if creds.ServerKey, err = base64.StdEncoding.DecodeString(string(keys[1])); err != nil ||
+		len(creds.ServerKey) != sha256.Size {
+		return creds, errors.New("malformed SCRAM-SHA-256 server key")
+	}
+	return creds, nil
+}
+
+// MatchesPassword returns true if the credentials were computed from the
+// given cleartext password. This is used when a client authenticates using
+// a cleartext password but the server only has SCRAM credentials.
+func (c *SCRAMCredentials) MatchesPassword(password string) bool {
+	computed := makeSCRAMCredentials(password, c.Salt, c.Iterations)
+	return subtle.ConstantTimeCompare(computed.StoredKey, c.StoredKey) == 1 &&
+		subtle.ConstantTimeCompare(computed.ServerKey, c.ServerKey) == 1
+}
+
+// VerifyClientProof checks the ClientProof sent by the client in its final
+// message against the stored credentials. authMessage is the concatenation
+// of the client-first-message-bare, the server-first-message and the
+// client-final-message-without-proof, separated by commas.
+func (c *SCRAMCredentials) VerifyClientProof(authMessage, clientProof []byte) bool {
+	if len(clientProof) != sha256.Size {
+		return false
+	}
+	clientSignature := scramHMAC(c.StoredKey, authMessage)
+	clientKey := make([]byte, sha256.Size)
+	for i := range clientKey {
+		clientKey[i] = clientProof[i] ^ clientSignature[i]
+	}
+	storedKey := sha256.Sum256(clientKey)
+	return subtle.ConstantTimeCompare(storedKey[:], c.StoredKey) == 1
+}
+
+// ServerSignature returns the signature which the server sends back to the
+// client in its final message, proving that it also knows the credentials.
+func (c *SCRAMCredentials) ServerSignature(authMessage []byte) []byte {
+	return scramHMAC(c.ServerKey, authMessage)
+}
+
+// TLSServerEndPoint returns the channel binding data of the
+// tls-server-end-point type defined in RFC 5929 for the given server
+// certificate. This is the only channel binding type supported by Postgres.
+func TLSServerEndPoint(cert *x509.Certificate) []byte {
+	var h hash.Hash
+	switch cert.SignatureAlgorithm {
+	case x509.SHA384WithRSA, x509.ECDSAWithSHA384, x509.SHA384WithRSAPSS:
+		h = sha512.New384()
+	case x509.SHA512WithRSA, x509.ECDSAWithSHA512, x509.SHA512WithRSAPSS:
+		h = sha512.New()
+	default:
+		// RFC 5929 mandates SHA-256 for certificates signed using MD5 or
+		// SHA-1, as well as for SHA-256 itself.
+		h = sha256.New()
+	}
+	_, _ = h.Write(cert.Raw)
+	return h.Sum(nil)
+}
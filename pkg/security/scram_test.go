@@ -0,0 +1,101 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package security_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSCRAMExchange verifies the server side of the example exchange given in
+// section 3 of RFC 7677.
+func TestSCRAMExchange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	const hashed = "SCRAM-SHA-256$4096:W22ZaJ0SNY7soEsUEjb6gQ==$" +
+		"WG5d8oPm3OtcPnkdi4Uo7BkeZkBFzpcXkuLmtbsT4qY=:wfPLwcE6nTWhTAmQ7tl2KeoiWGPlZqQxSrmfPwDl2dU="
+	const authMessage = "n=user,r=rOprNGfwEbeRWgbNEkqO," +
+		"r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096," +
+		"c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0"
+
+	require.True(t, security.IsSCRAMHash([]byte(hashed)))
+	creds, err := security.ParseSCRAMHash([]byte(hashed))
+	require.NoError(t, err)
+	require.Equal(t, 4096, creds.Iterations)
+
+	proof, err := base64.StdEncoding.DecodeString("dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=")
+	require.NoError(t, err)
+	require.True(t, creds.VerifyClientProof([]byte(authMessage), proof))
+	proof[0] ^= 1
+	require.False(t, creds.VerifyClientProof([]byte(authMessage), proof))
+	require.False(t, creds.VerifyClientProof([]byte(authMessage), proof[:10]))
+
+	require.Equal(t, "6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=",
+		base64.StdEncoding.EncodeToString(creds.ServerSignature([]byte(authMessage))))
+
+	require.NoError(t, security.CompareHashAndPassword([]byte(hashed), "pencil"))
+	require.Error(t, security.CompareHashAndPassword([]byte(hashed), "pencil2"))
+}
+
+func TestHashPasswordSCRAM(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	hashed, err := security.HashPasswordWithMethod(security.HashSCRAMSHA256, "hunter2")
+	require.NoError(t, err)
+	require.Regexp(t, `^SCRAM-SHA-256\$4096:[A-Za-z0-9+/=]{24}\$[A-Za-z0-9+/=]{44}:[A-Za-z0-9+/=]{44}$`,
+		string(hashed))
+	require.NoError(t, security.CompareHashAndPassword(hashed, "hunter2"))
+	require.Error(t, security.CompareHashAndPassword(hashed, "hunter3"))
+
+	// Two hashes of the same password use different salts.
+	hashed2, err := security.HashPasswordSCRAM("hunter2")
+	require.NoError(t, err)
+	require.NotEqual(t, hashed, hashed2)
+
+	bcryptHash, err := security.HashPasswordWithMethod(security.HashBCrypt, "hunter2")
+	require.NoError(t, err)
+	require.False(t, security.IsSCRAMHash(bcryptHash))
+	require.NoError(t, security.CompareHashAndPassword(bcryptHash, "hunter2"))
+
+	for _, bad := range []string{
+		"SCRAM-SHA-256$4096:c2FsdA==",
+		"SCRAM-SHA-256$abc:c2FsdA==$AAAA:AAAA",
+		"SCRAM-SHA-256$4096:c2FsdA==$AAAA:AAAA",
+		"SCRAM-SHA-256$4096:!!$" + string(hashed[len(hashed)-89:]),
+	} {
+		_, err := security.ParseSCRAMHash([]byte(bad))
+		require.Error(t, err, bad)
+	}
+}
+
+func TestMockSCRAMCredentials(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	secret := []byte("secret")
+	foo := security.MakeSQLUsernameFromPreNormalizedString("foo")
+	bar := security.MakeSQLUsernameFromPreNormalizedString("bar")
+
+	creds := security.MockSCRAMCredentials(secret, foo)
+	require.Equal(t, security.ScramIterCount, creds.Iterations)
+	require.Len(t, creds.Salt, 16)
+	// The salt is stable for a given user, but differs across users and
+	// secrets.
+	require.Equal(t, creds, security.MockSCRAMCredentials(secret, foo))
+	require.NotEqual(t, creds.Salt, security.MockSCRAMCredentials(secret, bar).Salt)
+	require.NotEqual(t, creds.Salt, security.MockSCRAMCredentials([]byte("other"), foo).Salt)
+
+	// No proof is accepted.
+	require.False(t, creds.VerifyClientProof([]byte("n=foo,r=abc"), make([]byte, 32)))
+}
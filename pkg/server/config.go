@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"path/filepath"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -471,6 +472,11 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	for _, spec := range cfg.Stores.Specs {
 		if !spec.InMemory {
 			physicalStores++
+			if spec.SeparateRaftLog {
+				// The dedicated Raft log engine gets its own share of the open
+				// file limit.
+				physicalStores++
+			}
 		}
 	}
 	openFileLimitPerStore, err := setOpenFileLimit(physicalStores)
@@ -548,8 +554,29 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 			if len(spec.RocksDBOptions) > 0 {
 				return nil, errors.Errorf("store %d: using Pebble storage engine but StoreSpec provides RocksDB options", i)
 			}
+			if spec.SeparateRaftLog {
+				// The Raft log engine shares the block cache and the encryption
+				// options of the store, but has its own WAL and tuning.
+				raftLogConfig := storage.PebbleConfig{
+					StorageConfig: storageConfig,
+					Opts:          storage.RaftLogPebbleOptions(),
+				}
+				raftLogConfig.Dir = filepath.Join(spec.Path, base.RaftLogEngineDir)
+				raftLogConfig.Opts.Cache = pebbleCache
+				raftLogConfig.Opts.MaxOpenFiles = int(openFileLimitPerStore)
+				raftLogEng, err := storage.NewPebble(ctx, raftLogConfig)
+				if err != nil {
+					return Engines{}, err
+				}
+				pebbleConfig.RaftLogEngine = raftLogEng
+				details = append(details, fmt.Sprintf("store %d: dedicated Raft log engine in %s",
+					i, raftLogConfig.Dir))
+			}
 			eng, err := storage.NewPebble(ctx, pebbleConfig)
 			if err != nil {
+				if pebbleConfig.RaftLogEngine != nil {
+					pebbleConfig.RaftLogEngine.Close()
+				}
 				return Engines{}, err
 			}
 			engines = append(engines, eng)
@@ -38,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/bootstrap"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/grpcutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -169,6 +170,12 @@ type Node struct {
 	additionalStoreInitCh chan struct{}
 
 	perReplicaServer kvserver.Server
+
+	// Admission control queues: kvAdmissionQ is used for all the KV work
+	// evaluated by the node, and storeGrantCoords provides a queue per store
+	// for the writes.
+	kvAdmissionQ     *admission.WorkQueue
+	storeGrantCoords *admission.StoreGrantCoordinators
 }
 
 var _ roachpb.InternalServer = &Node{}
@@ -284,6 +291,8 @@ func NewNode(
 	txnMetrics kvcoord.TxnMetrics,
 	execCfg *sql.ExecutorConfig,
 	clusterID *base.ClusterIDContainer,
+	kvAdmissionQ *admission.WorkQueue,
+	storeGrantCoords *admission.StoreGrantCoordinators,
 ) *Node {
 	var sqlExec *sql.InternalExecutor
 	if execCfg != nil {
@@ -298,6 +307,9 @@ func NewNode(
 		txnMetrics: txnMetrics,
 		sqlExec:    sqlExec,
 		clusterID:  clusterID,
+
+		kvAdmissionQ:     kvAdmissionQ,
+		storeGrantCoords: storeGrantCoords,
 	}
 	n.perReplicaServer = kvserver.MakeServer(&n.Descriptor, n.stores)
 	return n
@@ -865,6 +877,11 @@ func (n *Node) batchInternal(
 		}
 
 		tStart := timeutil.Now()
+		admittedWorkDone, err := n.admitBatch(ctx, args)
+		if err != nil {
+			return err
+		}
+		defer admittedWorkDone()
 		var pErr *roachpb.Error
 		br, pErr = n.stores.Send(ctx, *args)
 		if pErr != nil {
@@ -883,6 +900,62 @@ func (n *Node) batchInternal(
 	return br, nil
 }
 
+// admitBatch requests admission for the evaluation of a batch from the
+// admission control queues. The returned function must be called once the
+// batch has been evaluated.
+func (n *Node) admitBatch(ctx context.Context, ba *roachpb.BatchRequest) (func(), error) {
+	if n.kvAdmissionQ == nil {
+		return func() {}, nil
+	}
+	tenantID, ok := roachpb.TenantFromContext(ctx)
+	if !ok {
+		tenantID = roachpb.SystemTenantID
+	}
+	info := admission.WorkInfo{
+		TenantID:   tenantID,
+		Priority:   admission.WorkPriority(ba.AdmissionHeader.Priority),
+		CreateTime: ba.AdmissionHeader.CreateTime,
+		// Requests which do not originate from SQL, such as node liveness
+		// heartbeats, are often essential to the health of the cluster, so
+		// they are not made to wait.
+		BypassAdmission: ba.AdmissionHeader.Source == roachpb.AdmissionHeader_OTHER,
+	}
+	var storeQ *admission.WorkQueue
+	if ba.IsWrite() {
+		storeQ = n.storeGrantCoords.TryGetQueueForStore(ba.Replica.StoreID)
+	}
+	return admission.AdmitKVWork(ctx, n.kvAdmissionQ, storeQ, info)
+}
+
+// storeMetricsProvider provides the metrics of the stores of a node to
+// admission control.
+type storeMetricsProvider struct {
+	stores *kvserver.Stores
+}
+
+var _ admission.StoreMetricsProvider = storeMetricsProvider{}
+
+// GetStoreMetrics implements the admission.StoreMetricsProvider interface.
+// The stores whose metrics cannot be retrieved are omitted.
+func (p storeMetricsProvider) GetStoreMetrics() []admission.StoreMetrics {
+	var metrics []admission.StoreMetrics
+	_ = p.stores.VisitStores(func(s *kvserver.Store) error {
+		m, err := s.Engine().GetMetrics()
+		if err != nil {
+			return nil
+		}
+		metrics = append(metrics, admission.StoreMetrics{
+			StoreID:         s.StoreID(),
+			L0FileCount:     m.L0FileCount,
+			L0SublevelCount: m.L0SublevelCount,
+			L0Size:          m.L0Size,
+			L0BytesAdded:    m.FlushedBytes + m.L0BytesIngested,
+		})
+		return nil
+	})
+	return metrics
+}
+
 // Batch implements the roachpb.InternalServer interface.
 func (n *Node) Batch(
 	ctx context.Context, args *roachpb.BatchRequest,
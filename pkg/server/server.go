@@ -70,6 +70,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/ui"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/httputil"
@@ -159,6 +160,10 @@ type Server struct {
 
 	sqlServer *sqlServer
 
+	// The admission control coordinators, started in `(*Server).Start`.
+	grantCoordinator *admission.GrantCoordinator
+	storeGrantCoords *admission.StoreGrantCoordinators
+
 	// Created in NewServer but initialized (made usable) in `(*Server).Start`.
 	externalStorageBuilder *externalStorageBuilder
 
@@ -549,9 +554,21 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 	recorder := status.NewMetricsRecorder(clock, nodeLiveness, rpcContext, g, st)
 	registry.AddMetricStruct(rpcContext.RemoteClocks.Metrics())
 
+	admissionOpts := admission.DefaultOptions
+	admissionOpts.HistogramWindow = cfg.HistogramWindowInterval()
+	gcoord, metrics := admission.NewGrantCoordinator(st, admissionOpts)
+	for _, m := range metrics {
+		registry.AddMetricStruct(m)
+	}
+	storeGrantCoords, metrics := admission.NewStoreGrantCoordinators(st, cfg.HistogramWindowInterval())
+	for _, m := range metrics {
+		registry.AddMetricStruct(m)
+	}
+
 	node := NewNode(
 		storeCfg, recorder, registry, stopper,
-		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID)
+		txnMetrics, nil /* execCfg */, &rpcContext.ClusterID,
+		gcoord.GetWorkQueue(admission.KVWork), storeGrantCoords)
 	lateBoundNode = node
 	roachpb.RegisterInternalServer(grpcServer.Server, node)
 	kvserver.RegisterPerReplicaServer(grpcServer.Server, node.perReplicaServer)
@@ -620,6 +637,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 			externalStorage:        externalStorage,
 			externalStorageFromURI: externalStorageFromURI,
 			isMeta1Leaseholder:     node.stores.IsMeta1Leaseholder,
+			grantCoordinator:       gcoord,
 		},
 		SQLConfig:                &cfg.SQLConfig,
 		BaseConfig:               &cfg.BaseConfig,
@@ -680,6 +698,8 @@ func NewServer(cfg Config, stopper *stop.Stopper) (*Server, error) {
 		protectedtsReconciler:  protectedtsReconciler,
 		sqlServer:              sqlServer,
 		externalStorageBuilder: externalStorageBuilder,
+		grantCoordinator:       gcoord,
+		storeGrantCoords:       storeGrantCoords,
 	}
 	return lateBoundServer, err
 }
@@ -1522,6 +1542,19 @@ func (s *Server) PreStart(ctx context.Context) error {
 	}
 
 	log.Event(ctx, "started node")
+
+	// Now that the stores are started, start admission control.
+	if err := s.grantCoordinator.Start(ctx, s.stopper); err != nil {
+		return err
+	}
+	if err := s.storeGrantCoords.Start(
+		ctx, s.stopper, storeMetricsProvider{stores: s.node.stores},
+	); err != nil {
+		return err
+	}
+
+	// Apply the resource limits of tenants to the stores' rate limiters.
+	s.refreshTenantLimits(ctx)
 	if err := s.startPersistingHLCUpperBound(
 		ctx,
 		hlcUpperBound > 0,
@@ -62,6 +62,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sqlmigrations"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -129,6 +130,9 @@ type sqlServerOptionalKVArgs struct {
 	// Used by backup/restore.
 	externalStorage        cloud.ExternalStorageFactory
 	externalStorageFromURI cloud.ExternalStorageFromURIFactory
+
+	// Provides the admission control queues for the setup of DistSQL flows.
+	grantCoordinator *admission.GrantCoordinator
 }
 
 // sqlServerOptionalTenantArgs are the arguments supplied to newSQLServer which
@@ -405,6 +409,10 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*sqlServer, error) {
 		RangeCache:     cfg.distSender.RangeDescriptorCache(),
 		HydratedTables: hydratedTablesCache,
 	}
+	if cfg.grantCoordinator != nil {
+		distSQLCfg.SQLLeafStartAdmissionQ = cfg.grantCoordinator.GetWorkQueue(admission.SQLStatementLeafStartWork)
+		distSQLCfg.SQLRootStartAdmissionQ = cfg.grantCoordinator.GetWorkQueue(admission.SQLStatementRootStartWork)
+	}
 	cfg.TempStorageConfig.Mon.SetMetrics(distSQLMetrics.CurDiskBytesCount, distSQLMetrics.MaxDiskBytesHist)
 	if distSQLTestingKnobs := cfg.TestingKnobs.DistSQL; distSQLTestingKnobs != nil {
 		distSQLCfg.TestingKnobs = *distSQLTestingKnobs.(*execinfra.TestingKnobs)
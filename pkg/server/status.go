@@ -132,8 +132,17 @@ type baseStatusServer struct {
 	privilegeChecker *adminPrivilegeChecker
 	sessionRegistry  *sql.SessionRegistry
 	st               *cluster.Settings
+
+	// pgwireCancelSem limits the number of pgwire cancel requests that the
+	// server processes concurrently. Cancel requests are unauthenticated, so
+	// this bounds the rate at which a client can guess cancel keys.
+	pgwireCancelSem *quotapool.IntPool
 }
 
+// pgwireCancelConcurrency is the number of pgwire cancel requests that a
+// server processes concurrently.
+const pgwireCancelConcurrency = 256
+
 // getLocalSessions returns a list of local sessions on this node. Note that the
 // NodeID field is unset.
 func (b *baseStatusServer) getLocalSessions(
@@ -226,6 +235,39 @@ func findSessionByQueryID(queryID string) sessionFinder {
 	}
 }
 
+// pgwireCancelFailurePenalty is how long a failed pgwire cancel request keeps
+// its slot in the server's pgwireCancelSem.
+const pgwireCancelFailurePenalty = 1 * time.Second
+
+// cancelQueryByKeyLocal cancels the queries of the local session identified by
+// the pgwire cancel key in the request. No privilege check is performed: the
+// secret key is the proof that the request comes from the session's client.
+func (b *baseStatusServer) cancelQueryByKeyLocal(
+	ctx context.Context, req *serverpb.CancelQueryByKeyRequest,
+) (resp *serverpb.CancelQueryByKeyResponse, retErr error) {
+	alloc, err := b.pgwireCancelSem.TryAcquire(ctx, 1)
+	if err != nil {
+		return nil, status.Errorf(
+			codes.ResourceExhausted, "exceeded rate limit of pgwire cancellation requests")
+	}
+	defer func() {
+		// If the request did not cancel anything, hold on to the semaphore for
+		// a while longer. This keeps a client sending random keys from making
+		// more than a handful of guesses per second.
+		if retErr != nil || (resp != nil && !resp.Canceled) {
+			time.Sleep(pgwireCancelFailurePenalty)
+		}
+		alloc.Release()
+	}()
+
+	resp = &serverpb.CancelQueryByKeyResponse{}
+	resp.Canceled, err = b.sessionRegistry.CancelQueryByKey(req.CancelQueryKey)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
 func (b *baseStatusServer) checkCancelPrivilege(
 	ctx context.Context, username security.SQLUsername, findSession sessionFinder,
 ) error {
@@ -342,6 +384,7 @@ func newStatusServer(
 			privilegeChecker: adminServer.adminPrivilegeChecker,
 			sessionRegistry:  sessionRegistry,
 			st:               st,
+			pgwireCancelSem:  quotapool.NewIntPool("pgwire-cancel", pgwireCancelConcurrency),
 		},
 		cfg:              cfg,
 		admin:            adminServer,
@@ -1997,6 +2040,27 @@ func (s *statusServer) CancelQuery(
 	return output, nil
 }
 
+// CancelQueryByKey responds to a pgwire query cancellation request, and
+// cancels the queries of the session that owns the given cancel key. The
+// request is forwarded to the node that owns the session if needed.
+func (s *statusServer) CancelQueryByKey(
+	ctx context.Context, req *serverpb.CancelQueryByKeyRequest,
+) (*serverpb.CancelQueryByKeyResponse, error) {
+	// On the system tenant, SQL instance IDs are node IDs.
+	nodeID := roachpb.NodeID(req.SQLInstanceID)
+	if nodeID != s.gossip.NodeID.Get() {
+		// This request needs to be forwarded to another node.
+		ctx = propagateGatewayMetadata(ctx)
+		ctx = s.AnnotateCtx(ctx)
+		status, err := s.dialNode(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		}
+		return status.CancelQueryByKey(ctx, req)
+	}
+	return s.cancelQueryByKeyLocal(ctx, req)
+}
+
 // SpanStats requests the total statistics stored on a node for a given key
 // span, which may include multiple ranges.
 func (s *statusServer) SpanStats(
@@ -2104,6 +2168,13 @@ func (s *statusServer) Stores(
 		storeDetails.TotalBytes = envStats.TotalBytes
 		storeDetails.ActiveKeyFiles = envStats.ActiveKeyFiles
 		storeDetails.ActiveKeyBytes = envStats.ActiveKeyBytes
+		for _, ks := range envStats.KeyStats {
+			storeDetails.KeyStats = append(storeDetails.KeyStats, serverpb.StoreDetails_KeyStats{
+				KeyID: ks.KeyID,
+				Files: ks.Files,
+				Bytes: ks.Bytes,
+			})
+		}
 
 		resp.Stores = append(resp.Stores, storeDetails)
 
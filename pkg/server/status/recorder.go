@@ -547,9 +547,10 @@ func extractValue(mtr interface{}) (float64, error) {
 	// TODO(tschottdorf,ajwerner): consider moving this switch to a single
 	// interface implemented by the individual metric types.
 	type (
-		float64Valuer interface{ Value() float64 }
-		int64Valuer   interface{ Value() int64 }
-		int64Counter  interface{ Count() int64 }
+		float64Valuer  interface{ Value() float64 }
+		int64Valuer    interface{ Value() int64 }
+		int64Counter   interface{ Count() int64 }
+		float64Counter interface{ Count() float64 }
 	)
 	switch mtr := mtr.(type) {
 	case float64:
@@ -560,6 +561,8 @@ func extractValue(mtr interface{}) (float64, error) {
 		return float64(mtr.Value()), nil
 	case int64Counter:
 		return float64(mtr.Count()), nil
+	case float64Counter:
+		return mtr.Count(), nil
 	default:
 		return 0, errors.Errorf("cannot extract value for type %T", mtr)
 	}
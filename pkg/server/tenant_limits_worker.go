@@ -0,0 +1,126 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/tenantrate"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/errors"
+)
+
+// decodeTenantLimits extracts the resource limits of tenants from the rows of
+// the system.tenants table present in the system config KVs. Tenants without
+// resource limits of their own are omitted from the returned map.
+func decodeTenantLimits(
+	kvs []roachpb.KeyValue,
+) (map[roachpb.TenantID]tenantrate.LimitConfig, error) {
+	tbl := systemschema.TenantsTable
+
+	a := &rowenc.DatumAlloc{}
+	codec := keys.SystemSQLCodec
+	tenantsTablePrefix := codec.TablePrefix(keys.TenantsTableID)
+	colIdxMap := row.ColIDtoRowIndexFromCols(tbl.Columns)
+
+	limits := make(map[roachpb.TenantID]tenantrate.LimitConfig)
+	for _, kv := range kvs {
+		if !bytes.HasPrefix(kv.Key, tenantsTablePrefix) {
+			continue
+		}
+		tenantID, err := codec.DecodeTenantMetadataID(kv.Key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode key")
+		}
+
+		// All of the columns are stored in a single family, packed with
+		// diff-encoded column IDs followed by their values.
+		tuple, err := kv.Value.GetTuple()
+		if err != nil {
+			return nil, err
+		}
+		var infoBytes []byte
+		var colIDDiff uint32
+		var lastColID descpb.ColumnID
+		var res tree.Datum
+		for len(tuple) > 0 {
+			_, _, colIDDiff, _, err = encoding.DecodeValueTag(tuple)
+			if err != nil {
+				return nil, err
+			}
+			colID := lastColID + descpb.ColumnID(colIDDiff)
+			lastColID = colID
+			idx, ok := colIdxMap.Get(colID)
+			if !ok {
+				return nil, errors.Errorf("unknown column: %v", colID)
+			}
+			res, tuple, err = rowenc.DecodeTableValue(a, tbl.Columns[idx].Type, tuple)
+			if err != nil {
+				return nil, err
+			}
+			if colID == tbl.Columns[2].ID && res != tree.DNull { // info
+				infoBytes = []byte(tree.MustBeDBytes(res))
+			}
+		}
+
+		var info descpb.TenantInfo
+		if err := protoutil.Unmarshal(infoBytes, &info); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode info of tenant %v", tenantID)
+		}
+		if info.ResourceLimits == nil {
+			continue
+		}
+		limits[tenantID] = tenantrate.LimitConfig{
+			Rate:  tenantrate.Limit(info.ResourceLimits.RateLimit),
+			Burst: tenantrate.RequestUnits(info.ResourceLimits.BurstLimit),
+		}
+	}
+	return limits, nil
+}
+
+// refreshTenantLimits starts a listener which applies the resource limits of
+// tenants stored in the system.tenants table to the tenant rate limiters of
+// the node's stores.
+func (s *Server) refreshTenantLimits(ctx context.Context) {
+	s.stopper.RunWorker(ctx, func(ctx context.Context) {
+		gossipUpdateC := s.gossip.RegisterSystemConfigChannel()
+		for {
+			select {
+			case <-gossipUpdateC:
+				cfg := s.gossip.GetSystemConfig()
+				limits, err := decodeTenantLimits(cfg.Values)
+				if err != nil {
+					log.Warningf(ctx, "error processing tenant limits: %+v", err)
+					continue
+				}
+				if err := s.node.stores.VisitStores(func(store *kvserver.Store) error {
+					store.TenantRateLimiters().UpdateTenantLimits(limits)
+					return nil
+				}); err != nil {
+					log.Warningf(ctx, "error updating tenant limits: %+v", err)
+				}
+			case <-s.stopper.ShouldStop():
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/tenantrate"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// TestTenantLimitsRefresh verifies that the tenant rate limiters of the stores
+// pick up the resource limits set with update_tenant_resource_limits.
+func TestTenantLimitsRefresh(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, rawDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(rawDB)
+
+	tenantID := roachpb.MakeTenantID(10)
+	checkLimits := func(expected tenantrate.LimitConfig) {
+		testutils.SucceedsSoon(t, func() error {
+			return s.GetStores().(*kvserver.Stores).VisitStores(func(store *kvserver.Store) error {
+				if actual := store.TenantRateLimiters().TenantLimits(tenantID); actual != expected {
+					return errors.Errorf("s%d: expected limits %+v, found %+v", store.StoreID(), expected, actual)
+				}
+				return nil
+			})
+		})
+	}
+
+	db.Exec(t, `SELECT crdb_internal.create_tenant(10)`)
+	checkLimits(tenantrate.LimitConfigFromSettings(s.ClusterSettings()))
+
+	db.Exec(t, `SELECT crdb_internal.update_tenant_resource_limits(10, 123, 456)`)
+	checkLimits(tenantrate.LimitConfig{Rate: 123, Burst: 456})
+
+	db.Exec(t, `SELECT crdb_internal.update_tenant_resource_limits(10, 12, 34)`)
+	checkLimits(tenantrate.LimitConfig{Rate: 12, Burst: 34})
+}
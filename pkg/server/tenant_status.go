@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 )
 
 // tenantStatusServer is an implementation of a SQLStatusServer that is
@@ -43,6 +44,7 @@ func newTenantStatusServer(
 			privilegeChecker: privilegeChecker,
 			sessionRegistry:  sessionRegistry,
 			st:               st,
+			pgwireCancelSem:  quotapool.NewIntPool("pgwire-cancel", pgwireCancelConcurrency),
 		},
 	}
 }
@@ -81,6 +83,17 @@ func (t *tenantStatusServer) CancelQuery(
 	return output, nil
 }
 
+// CancelQueryByKey cancels the queries of the local session that owns the
+// given cancel key. Like the rest of the tenantStatusServer, it does not
+// forward the request to other SQL pods of the tenant, since there is no
+// registry of them: a cancel request which reaches a pod other than the one
+// serving the session does not cancel anything.
+func (t *tenantStatusServer) CancelQueryByKey(
+	ctx context.Context, request *serverpb.CancelQueryByKeyRequest,
+) (*serverpb.CancelQueryByKeyResponse, error) {
+	return t.cancelQueryByKeyLocal(ctx, request)
+}
+
 func (t *tenantStatusServer) CancelSession(
 	ctx context.Context, request *serverpb.CancelSessionRequest,
 ) (*serverpb.CancelSessionResponse, error) {
@@ -72,9 +72,17 @@ var retiredSettings = map[string]struct{}{
 	"sql.defaults.experimental_optimizer_foreign_key_cascades.enabled": {},
 	"sql.parallel_scans.enabled":                                       {},
 	// removed as of 21.1.
-	"sql.distsql.interleaved_joins.enabled": {},
-	"sql.testing.vectorize.batch_size":      {},
-	"sql.testing.mutations.max_batch_size":  {},
+	"sql.distsql.interleaved_joins.enabled":             {},
+	"sql.testing.vectorize.batch_size":                  {},
+	"sql.testing.mutations.max_batch_size":              {},
+	"kv.tenant_rate_limiter.read_requests.rate_limit":   {},
+	"kv.tenant_rate_limiter.read_requests.burst_limit":  {},
+	"kv.tenant_rate_limiter.write_requests.rate_limit":  {},
+	"kv.tenant_rate_limiter.write_requests.burst_limit": {},
+	"kv.tenant_rate_limiter.read_bytes.rate_limit":      {},
+	"kv.tenant_rate_limiter.read_bytes.burst_limit":     {},
+	"kv.tenant_rate_limiter.write_bytes.rate_limit":     {},
+	"kv.tenant_rate_limiter.write_bytes.burst_limit":    {},
 }
 
 // register adds a setting to the registry.
@@ -72,6 +72,10 @@ func (p *planner) AlterRoleNode(
 		return nil, err
 	}
 
+	if err := p.checkBypassRLSOptionConstraints(ctx, roleOptions); err != nil {
+		return nil, err
+	}
+
 	ua, err := p.getUserAuthInfo(ctx, nameE, opName)
 	if err != nil {
 		return nil, err
@@ -117,6 +121,17 @@ func (p *planner) checkPasswordOptionConstraints(
 	return nil
 }
 
+// checkBypassRLSOptionConstraints checks that only admin users can grant
+// BYPASSRLS, which exempts a role from all row-level security policies.
+func (p *planner) checkBypassRLSOptionConstraints(
+	ctx context.Context, roleOptions roleoption.List,
+) error {
+	if roleOptions.Contains(roleoption.BYPASSRLS) || roleOptions.Contains(roleoption.NOBYPASSRLS) {
+		return p.RequireAdminRole(ctx, "grant BYPASSRLS or NOBYPASSRLS")
+	}
+	return nil
+}
+
 func (n *alterRoleNode) startExec(params runParams) error {
 	var opName string
 	if n.isRole {
@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
@@ -181,6 +182,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 						"unique constraints without an index are not yet supported",
 					)
 				}
+				if d.Deferrability.Deferrable {
+					return unimplemented.NewWithIssue(31632, "deferrable unique constraints with an index")
+				}
 				if d.PrimaryKey {
 					// We only support "adding" a primary key when we are using the
 					// default rowid primary index or if a DROP PRIMARY KEY statement
@@ -411,6 +415,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 				if err != nil {
 					return err
 				}
+				fnDesc, err := params.p.getDependentFunction(params.ctx, ref.ID)
+				if err != nil {
+					return err
+				}
+				if fnDesc != nil {
+					jobDesc := fmt.Sprintf("removing function %q dependent on column %q which is being dropped",
+						fnDesc.Name, colToDrop.ColName())
+					if err := params.p.removeDependentFunction(params.ctx, n.tableDesc, fnDesc, jobDesc); err != nil {
+						return err
+					}
+					continue
+				}
 				viewDesc, err := params.p.getViewDescForCascade(
 					params.ctx, "column", string(t.Column), n.tableDesc.ParentID, ref.ID, t.DropBehavior,
 				)
@@ -768,6 +784,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableRowLevelSecurity:
+			if (t.Mode == tree.RowLevelSecurityEnable || t.Mode == tree.RowLevelSecurityForce) &&
+				!params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.RowLevelSecurity) {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to enable row-level security",
+					clusterversion.RowLevelSecurity)
+			}
+			if err := params.p.canModifyRowLevelSecurity(params.ctx, n.tableDesc); err != nil {
+				return err
+			}
+			descriptorChanged = setRowLevelSecurityMode(n.tableDesc, t.Mode) || descriptorChanged
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -900,6 +928,23 @@ func (n *alterTableNode) startExec(params runParams) error {
 		})
 }
 
+// setRowLevelSecurityMode enables, disables or forces the row-level security
+// policies of the table, and returns true if the descriptor changed.
+func setRowLevelSecurityMode(desc *tabledesc.Mutable, mode tree.RowLevelSecurityMode) bool {
+	prevEnabled, prevForced := desc.RowLevelSecurity, desc.ForceRowLevelSecurity
+	switch mode {
+	case tree.RowLevelSecurityEnable:
+		desc.RowLevelSecurity = true
+	case tree.RowLevelSecurityDisable:
+		desc.RowLevelSecurity = false
+	case tree.RowLevelSecurityForce:
+		desc.ForceRowLevelSecurity = true
+	case tree.RowLevelSecurityNoForce:
+		desc.ForceRowLevelSecurity = false
+	}
+	return desc.RowLevelSecurity != prevEnabled || desc.ForceRowLevelSecurity != prevForced
+}
+
 func (p *planner) setAuditMode(
 	ctx context.Context, desc *tabledesc.Mutable, auditMode tree.AuditMode,
 ) (bool, error) {
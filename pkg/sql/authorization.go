@@ -431,6 +431,33 @@ func (p *planner) HasRoleOption(ctx context.Context, roleOption roleoption.Optio
 		return true, nil
 	}
 
+	return p.hasGrantedRoleOption(ctx, user, roleOption)
+}
+
+// HasExplicitRoleOption is like HasRoleOption, except that admins only have
+// the role options which were granted to them. This is used for BYPASSRLS,
+// which admins do not have implicitly. The root and node users, which run the
+// internal queries, still have every role option.
+func (p *planner) HasExplicitRoleOption(
+	ctx context.Context, roleOption roleoption.Option,
+) (bool, error) {
+	if p.txn == nil || !p.txn.IsOpen() {
+		return false, errors.AssertionFailedf("cannot use HasExplicitRoleOption without a txn")
+	}
+
+	user := p.SessionData().User()
+	if user.IsRootUser() || user.IsNodeUser() {
+		return true, nil
+	}
+
+	return p.hasGrantedRoleOption(ctx, user, roleOption)
+}
+
+// hasGrantedRoleOption returns true if the role option was granted to the
+// user with CREATE ROLE or ALTER ROLE.
+func (p *planner) hasGrantedRoleOption(
+	ctx context.Context, user security.SQLUsername, roleOption roleoption.Option,
+) (bool, error) {
 	hasRolePrivilege, err := p.ExecCfg().InternalExecutor.QueryEx(
 		ctx, "has-role-option", p.Txn(),
 		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -114,6 +115,7 @@ const (
 	SchemaDescriptorKind
 	TableDescriptorKind
 	TypeDescriptorKind
+	FunctionDescriptorKind
 	AnyDescriptorKind // permit any kind
 )
 
@@ -188,6 +190,8 @@ func desiredKindError(desc catalog.Descriptor, kind DescriptorKind, id descpb.ID
 		kindMismatched = kind != TableDescriptorKind
 	case catalog.TypeDescriptor:
 		kindMismatched = kind != TypeDescriptorKind
+	case catalog.FunctionDescriptor:
+		kindMismatched = kind != FunctionDescriptorKind
 	}
 	if !kindMismatched {
 		return nil
@@ -212,6 +216,8 @@ func requiredError(kind DescriptorKind, id descpb.ID) error {
 		err = sqlerrors.NewUnsupportedSchemaUsageError(fmt.Sprintf("[%d]", id))
 	case TypeDescriptorKind:
 		err = sqlerrors.NewUndefinedTypeError(tree.NewUnqualifiedTypeName(tree.Name(fmt.Sprintf("[%d]", id))))
+	case FunctionDescriptorKind:
+		err = sqlerrors.NewUndefinedFunctionError(fmt.Sprintf("[%d]", id))
 	default:
 		err = errors.Errorf("failed to find descriptor [%d]", id)
 	}
@@ -290,6 +296,8 @@ func validateDescriptor(ctx context.Context, dg catalog.DescGetter, desc catalog
 		return desc.Validate(ctx, dg)
 	case catalog.SchemaDescriptor:
 		return nil
+	case catalog.FunctionDescriptor:
+		return desc.Validate(ctx, dg)
 	default:
 		return errors.AssertionFailedf("unknown descriptor type %T", desc)
 	}
@@ -307,8 +315,8 @@ func unwrapDescriptor(
 	validate bool,
 ) (catalog.Descriptor, error) {
 	descpb.MaybeSetDescriptorModificationTimeFromMVCCTimestamp(ctx, desc, ts)
-	table, database, typ, schema := descpb.TableFromDescriptor(desc, hlc.Timestamp{}),
-		desc.GetDatabase(), desc.GetType(), desc.GetSchema()
+	table, database, typ, schema, function := descpb.TableFromDescriptor(desc, hlc.Timestamp{}),
+		desc.GetDatabase(), desc.GetType(), desc.GetSchema(), desc.GetFunction()
 	var unwrapped catalog.Descriptor
 	switch {
 	case table != nil:
@@ -323,6 +331,8 @@ func unwrapDescriptor(
 		unwrapped = typedesc.NewImmutable(*typ)
 	case schema != nil:
 		unwrapped = schemadesc.NewImmutable(*schema)
+	case function != nil:
+		unwrapped = funcdesc.NewImmutable(*function)
 	default:
 		return nil, nil
 	}
@@ -341,9 +351,9 @@ func unwrapDescriptorMutable(
 	ctx context.Context, dg catalog.DescGetter, ts hlc.Timestamp, desc *descpb.Descriptor,
 ) (catalog.MutableDescriptor, error) {
 	descpb.MaybeSetDescriptorModificationTimeFromMVCCTimestamp(ctx, desc, ts)
-	table, database, typ, schema :=
+	table, database, typ, schema, function :=
 		descpb.TableFromDescriptor(desc, hlc.Timestamp{}),
-		desc.GetDatabase(), desc.GetType(), desc.GetSchema()
+		desc.GetDatabase(), desc.GetType(), desc.GetSchema(), desc.GetFunction()
 	switch {
 	case table != nil:
 		mutTable, err := tabledesc.NewFilledInExistingMutable(ctx, dg, false /* skipFKsWithMissingTable */, table)
@@ -364,6 +374,8 @@ func unwrapDescriptorMutable(
 		return typedesc.NewExistingMutable(*typ), nil
 	case schema != nil:
 		return schemadesc.NewMutableExisting(*schema), nil
+	case function != nil:
+		return funcdesc.NewMutableExisting(*function), nil
 	default:
 		return nil, nil
 	}
@@ -699,8 +711,8 @@ func GetSchemaDescriptorsFromIDs(
 // TODO(ajwerner): unify this with the other unwrapping logic.
 func UnwrapDescriptorRaw(ctx context.Context, desc *descpb.Descriptor) catalog.MutableDescriptor {
 	descpb.MaybeSetDescriptorModificationTimeFromMVCCTimestamp(ctx, desc, hlc.Timestamp{})
-	table, database, typ, schema := descpb.TableFromDescriptor(desc, hlc.Timestamp{}),
-		desc.GetDatabase(), desc.GetType(), desc.GetSchema()
+	table, database, typ, schema, function := descpb.TableFromDescriptor(desc, hlc.Timestamp{}),
+		desc.GetDatabase(), desc.GetType(), desc.GetSchema(), desc.GetFunction()
 	switch {
 	case table != nil:
 		return tabledesc.NewExistingMutable(*table)
@@ -710,6 +722,8 @@ func UnwrapDescriptorRaw(ctx context.Context, desc *descpb.Descriptor) catalog.M
 		return typedesc.NewExistingMutable(*typ)
 	case schema != nil:
 		return schemadesc.NewMutableExisting(*schema)
+	case function != nil:
+		return funcdesc.NewMutableExisting(*function)
 	default:
 		log.Fatalf(ctx, "failed to unwrap descriptor of type %T", desc.Union)
 		return nil // unreachable
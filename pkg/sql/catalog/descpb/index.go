@@ -13,6 +13,8 @@ package descpb
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
@@ -82,6 +84,8 @@ func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtx) {
 		if desc.Type != IndexDescriptor_INVERTED {
 			ctx.WriteByte(' ')
 			ctx.WriteString(desc.ColumnDirections[i].String())
+		} else if i == len(desc.ColumnNames)-1 && desc.InvertedColumnKind == IndexDescriptor_TRIGRAM {
+			ctx.WriteString(" gin_trgm_ops")
 		}
 	}
 }
@@ -90,11 +94,16 @@ func (desc *IndexDescriptor) ColNamesFormat(ctx *tree.FmtCtx) {
 func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	desc.ColumnNames = make([]string, 0, len(elems))
 	desc.ColumnDirections = make([]IndexDescriptor_Direction, 0, len(elems))
-	for _, c := range elems {
+	for i, c := range elems {
 		if c.Expr != nil {
 			return unimplemented.NewWithIssuef(9682, "only simple columns are supported as index elements")
 		}
 		desc.ColumnNames = append(desc.ColumnNames, string(c.Column))
+		if c.OpClass != "" {
+			if err := desc.fillOpClass(c, i == len(elems)-1); err != nil {
+				return err
+			}
+		}
 		switch c.Direction {
 		case tree.Ascending, tree.DefaultDirection:
 			desc.ColumnDirections = append(desc.ColumnDirections, IndexDescriptor_ASC)
@@ -107,6 +116,27 @@ func (desc *IndexDescriptor) FillColumns(elems tree.IndexElemList) error {
 	return nil
 }
 
+// fillOpClass sets the kind of the inverted column of the index according to
+// the operator class of the given index element. Only the trigram operator
+// classes are supported, on the inverted column of an inverted index.
+func (desc *IndexDescriptor) fillOpClass(elem tree.IndexElem, isLast bool) error {
+	if desc.Type != IndexDescriptor_INVERTED {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"operator class %q does not exist for access method \"btree\"", elem.OpClass)
+	}
+	if !isLast {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"operator class %q can only be used for the last column of an inverted index", elem.OpClass)
+	}
+	switch elem.OpClass {
+	case "gin_trgm_ops", "gist_trgm_ops":
+		desc.InvertedColumnKind = IndexDescriptor_TRIGRAM
+	default:
+		return pgerror.Newf(pgcode.UndefinedObject, "operator class %q does not exist", elem.OpClass)
+	}
+	return nil
+}
+
 type returnTrue struct{}
 
 func (returnTrue) Error() string { panic("unimplemented") }
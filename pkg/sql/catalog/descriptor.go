@@ -182,6 +182,10 @@ type TableDescriptor interface {
 	AllActiveAndInactiveChecks() []*descpb.TableDescriptor_CheckConstraint
 	ActiveChecks() []descpb.TableDescriptor_CheckConstraint
 	AllActiveAndInactiveUniqueWithoutIndexConstraints() []*descpb.UniqueWithoutIndexConstraint
+	GetTriggers() []descpb.TableDescriptor_Trigger
+	GetRowLevelSecurity() bool
+	GetForceRowLevelSecurity() bool
+	GetPolicies() []descpb.TableDescriptor_Policy
 	ForeachInboundFK(f func(fk *descpb.ForeignKeyConstraint) error) error
 	FindActiveColumnByName(s string) (*descpb.ColumnDescriptor, error)
 	WritableColumns() []descpb.ColumnDescriptor
@@ -201,6 +205,14 @@ type TypeDescriptor interface {
 	Validate(ctx context.Context, dg DescGetter) error
 }
 
+// FunctionDescriptor will eventually be called funcdesc.Descriptor.
+// It is implemented by funcdesc.Immutable and funcdesc.Mutable.
+type FunctionDescriptor interface {
+	Descriptor
+	FuncDesc() *descpb.FunctionDescriptor
+	Validate(ctx context.Context, dg DescGetter) error
+}
+
 // TypeDescriptorResolver is an interface used during hydration of type
 // metadata in types.T's. It is similar to tree.TypeReferenceResolver, except
 // that it has the power to return TypeDescriptor, rather than only a
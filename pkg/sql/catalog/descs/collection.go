@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/hydratedtables"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
@@ -609,6 +610,64 @@ func (tc *Collection) getTypeByName(
 	return true, typ, nil
 }
 
+// GetMutableFunctionByName returns a mutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is ignored.
+func (tc *Collection) GetMutableFunctionByName(
+	ctx context.Context, txn *kv.Txn, name tree.ObjectName, flags tree.ObjectLookupFlags,
+) (found bool, _ *funcdesc.Mutable, _ error) {
+	found, desc, err := tc.getFunctionByName(ctx, txn, name, flags, true /* mutable */)
+	if err != nil || !found {
+		return false, nil, err
+	}
+	return true, desc.(*funcdesc.Mutable), nil
+}
+
+// GetImmutableFunctionByName returns an immutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is ignored.
+func (tc *Collection) GetImmutableFunctionByName(
+	ctx context.Context, txn *kv.Txn, name tree.ObjectName, flags tree.ObjectLookupFlags,
+) (found bool, _ *funcdesc.Immutable, _ error) {
+	found, desc, err := tc.getFunctionByName(ctx, txn, name, flags, false /* mutable */)
+	if err != nil || !found {
+		return false, nil, err
+	}
+	return true, desc.(*funcdesc.Immutable), nil
+}
+
+// getFunctionByName returns a function descriptor with properties according
+// to the provided lookup flags.
+func (tc *Collection) getFunctionByName(
+	ctx context.Context,
+	txn *kv.Txn,
+	name tree.ObjectName,
+	flags tree.ObjectLookupFlags,
+	mutable bool,
+) (found bool, _ catalog.FunctionDescriptor, err error) {
+	found, desc, err := tc.getObjectByName(
+		ctx, txn, name.Catalog(), name.Schema(), name.Object(), flags, mutable)
+	if err != nil {
+		return false, nil, err
+	} else if !found {
+		if flags.Required {
+			return false, nil, sqlerrors.NewUndefinedFunctionError(tree.ErrString(name))
+		}
+		return false, nil, nil
+	}
+	fn, ok := desc.(catalog.FunctionDescriptor)
+	if !ok {
+		if flags.Required {
+			return false, nil, sqlerrors.NewUndefinedFunctionError(tree.ErrString(name))
+		}
+		return false, nil, nil
+	}
+	if dropped, err := filterDescriptorState(
+		fn, flags.CommonLookupFlags,
+	); err != nil || dropped {
+		return false, nil, err
+	}
+	return true, fn, nil
+}
+
 // TODO (lucy): Should this just take a database name? We're separately
 // resolving the database name in lots of places where we (indirectly) call
 // this.
@@ -0,0 +1,133 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSafeMessage(t *testing.T) {
+	for _, tc := range []struct {
+		desc catalog.FunctionDescriptor
+		exp  string
+	}{
+		{
+			desc: funcdesc.NewImmutable(descpb.FunctionDescriptor{
+				ID:             55,
+				Version:        1,
+				ParentID:       50,
+				ParentSchemaID: 29,
+				Name:           "secret_name",
+				Params:         []descpb.FunctionDescriptor_Param{{Name: "a", Type: types.Int}},
+				ReturnType:     types.Int,
+				Volatility:     descpb.FunctionDescriptor_IMMUTABLE,
+				FunctionBody:   "SELECT a + 1",
+			}),
+			exp: "funcdesc.Immutable: {ID: 55, Version: 1, ModificationTime: \"0,0\", ParentID: 50, ParentSchemaID: 29, State: PUBLIC, NumParams: 1, Volatility: IMMUTABLE}",
+		},
+		{
+			desc: funcdesc.NewCreatedMutable(descpb.FunctionDescriptor{
+				ID:                       56,
+				Version:                  1,
+				ParentID:                 50,
+				ParentSchemaID:           29,
+				Name:                     "secret_name",
+				ReturnType:               types.Int,
+				FunctionBody:             "SELECT count(*) FROM t JOIN u ON true",
+				DependsOn:                []descpb.ID{53, 54},
+				ReferencingDescriptorIDs: []descpb.ID{57},
+				State:                    descpb.DescriptorState_OFFLINE,
+				OfflineReason:            "foo",
+			}),
+			exp: "funcdesc.Mutable: {ID: 56, Version: 1, IsUncommitted: true, ModificationTime: \"0,0\", ParentID: 50, ParentSchemaID: 29, State: OFFLINE, OfflineReason: \"foo\", NumParams: 0, Volatility: VOLATILE, DependsOn: [53, 54], ReferencingDescriptorIDs: [57]}",
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			redacted := string(redact.Sprint(tc.desc).Redact())
+			require.Equal(t, tc.exp, redacted)
+			{
+				var m map[string]interface{}
+				require.NoError(t, yaml.UnmarshalStrict([]byte(redacted), &m))
+			}
+		})
+	}
+}
+
+func TestValidateLocal(t *testing.T) {
+	valid := func() descpb.FunctionDescriptor {
+		return descpb.FunctionDescriptor{
+			ID:             55,
+			Version:        1,
+			ParentID:       50,
+			ParentSchemaID: 29,
+			Name:           "f",
+			Params:         []descpb.FunctionDescriptor_Param{{Name: "a", Type: types.Int}},
+			ReturnType:     types.Int,
+			FunctionBody:   "SELECT a + 1",
+			Privileges:     descpb.NewDefaultPrivilegeDescriptor(security.RootUserName()),
+			// Mark the descriptor as dropped to skip cross-reference checks.
+			State: descpb.DescriptorState_DROP,
+		}
+	}
+	for _, tc := range []struct {
+		mutate func(desc *descpb.FunctionDescriptor)
+		err    string
+	}{
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) {},
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.Name = "" },
+			err:    "empty function name",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.ID = descpb.InvalidID },
+			err:    "invalid ID 0",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.ParentID = descpb.InvalidID },
+			err:    "invalid parentID 0",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.Params[0].Type = nil },
+			err:    "missing type for parameter 1",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.ReturnType = nil },
+			err:    "missing return type",
+		},
+		{
+			mutate: func(desc *descpb.FunctionDescriptor) { desc.FunctionBody = "" },
+			err:    "missing function body",
+		},
+	} {
+		t.Run(tc.err, func(t *testing.T) {
+			desc := valid()
+			tc.mutate(&desc)
+			err := funcdesc.NewImmutable(desc).Validate(context.Background(), nil /* dg */)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
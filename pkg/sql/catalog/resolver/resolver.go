@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -143,6 +144,24 @@ func ResolveMutableType(
 	return &tn, desc.(*typedesc.Mutable), nil
 }
 
+// ResolveFunction resolves a user-defined function descriptor through the
+// search path. It returns the resolved descriptor, as well as the fully
+// qualified resolved function name.
+func ResolveFunction(
+	ctx context.Context,
+	sc SchemaResolver,
+	un *tree.UnresolvedObjectName,
+	lookupFlags tree.ObjectLookupFlags,
+) (*tree.TableName, catalog.FunctionDescriptor, error) {
+	lookupFlags.DesiredObjectKind = tree.FunctionObject
+	desc, prefix, err := ResolveExistingObject(ctx, sc, un, lookupFlags)
+	if err != nil || desc == nil {
+		return nil, nil, err
+	}
+	fn := tree.MakeTableNameFromPrefix(prefix, tree.Name(un.Object()))
+	return &fn, desc.(catalog.FunctionDescriptor), nil
+}
+
 // ResolveExistingObject resolves an object with the given flags.
 func ResolveExistingObject(
 	ctx context.Context,
@@ -207,6 +226,14 @@ func ResolveExistingObject(
 		}
 
 		return descI.(*tabledesc.Immutable), prefix, nil
+	case tree.FunctionObject:
+		if _, isFunc := obj.(catalog.FunctionDescriptor); !isFunc {
+			return nil, prefix, sqlerrors.NewUndefinedFunctionError(tree.ErrString(&resolvedTn))
+		}
+		if lookupFlags.RequireMutable {
+			return obj.(*funcdesc.Mutable), prefix, nil
+		}
+		return obj.(*funcdesc.Immutable), prefix, nil
 	default:
 		return nil, prefix, errors.AssertionFailedf(
 			"unknown desired object kind %d", lookupFlags.DesiredObjectKind)
@@ -2026,6 +2026,14 @@ func (desc *wrapper) ValidateTable(ctx context.Context) error {
 		if err := desc.validatePartitioning(); err != nil {
 			return err
 		}
+
+		if err := desc.validateTriggers(); err != nil {
+			return err
+		}
+
+		if err := desc.validatePolicies(); err != nil {
+			return err
+		}
 	}
 
 	// Fill in any incorrect privileges that may have been missed due to mixed-versions.
@@ -2260,6 +2268,47 @@ func (desc *wrapper) validateCheckConstraints(
 	return nil
 }
 
+// validateTriggers validates that the triggers of the table have unique
+// names, are sorted by name, and fire on at least one event.
+func (desc *wrapper) validateTriggers() error {
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		if trigger.Name == "" {
+			return errors.Newf("empty trigger name")
+		}
+		if i > 0 && desc.Triggers[i-1].Name >= trigger.Name {
+			return errors.Newf(
+				"triggers not sorted by unique name: %q, %q", desc.Triggers[i-1].Name, trigger.Name)
+		}
+		if !trigger.OnInsert && !trigger.OnUpdate && !trigger.OnDelete {
+			return errors.Newf("trigger %q does not fire on any event", trigger.Name)
+		}
+		if trigger.ActionStatement == "" {
+			return errors.Newf("trigger %q has no action", trigger.Name)
+		}
+	}
+	return nil
+}
+
+// validatePolicies validates that the row-level security policies of the
+// table have unique names, are sorted by name, and apply to at least one role.
+func (desc *wrapper) validatePolicies() error {
+	for i := range desc.Policies {
+		policy := &desc.Policies[i]
+		if policy.Name == "" {
+			return errors.Newf("empty policy name")
+		}
+		if i > 0 && desc.Policies[i-1].Name >= policy.Name {
+			return errors.Newf(
+				"policies not sorted by unique name: %q, %q", desc.Policies[i-1].Name, policy.Name)
+		}
+		if len(policy.Roles) == 0 {
+			return errors.Newf("policy %q does not apply to any role", policy.Name)
+		}
+	}
+	return nil
+}
+
 // validateUniqueWithoutIndexConstraints validates that unique without index
 // constraints are well formed. Checks include validating the column IDs and
 // column names.
@@ -2710,13 +2759,18 @@ func checkColumnsValidForIndex(tableDesc *Mutable, indexColNames []string) error
 	return nil
 }
 
-func checkColumnsValidForInvertedIndex(tableDesc *Mutable, indexColNames []string) error {
+func checkColumnsValidForInvertedIndex(tableDesc *Mutable, idx *descpb.IndexDescriptor) error {
+	indexColNames := idx.ColumnNames
 	invalidColumns := make([]descpb.ColumnDescriptor, 0, len(indexColNames))
+	lastColIsString := false
 	for i, indexCol := range indexColNames {
 		for _, col := range tableDesc.AllNonDropColumns() {
 			if col.Name == indexCol {
 				lastCol := len(indexColNames) - 1
-				if i == lastCol && !colinfo.ColumnTypeIsInvertedIndexable(col.Type) ||
+				if i == lastCol {
+					lastColIsString = col.Type.Family() == types.StringFamily
+				}
+				if i == lastCol && !columnTypeIsInvertedIndexable(col.Type, idx.InvertedColumnKind) ||
 					i < lastCol && !colinfo.ColumnTypeIsIndexable(col.Type) {
 					invalidColumns = append(invalidColumns, col)
 				}
@@ -2724,11 +2778,27 @@ func checkColumnsValidForInvertedIndex(tableDesc *Mutable, indexColNames []strin
 		}
 	}
 	if len(invalidColumns) > 0 {
-		return notIndexableError(invalidColumns, true)
+		err := notIndexableError(invalidColumns, true)
+		if lastColIsString && idx.InvertedColumnKind == descpb.IndexDescriptor_DEFAULT {
+			err = errors.WithHint(err,
+				"a trigram inverted index can be created on a string column with the gin_trgm_ops operator class")
+		}
+		return err
 	}
 	return nil
 }
 
+// columnTypeIsInvertedIndexable returns whether the type t is valid for the
+// inverted column of an inverted index with the given kind of inverted column.
+func columnTypeIsInvertedIndexable(
+	t *types.T, kind descpb.IndexDescriptor_InvertedColumnKind,
+) bool {
+	if kind == descpb.IndexDescriptor_TRIGRAM {
+		return t.Family() == types.StringFamily
+	}
+	return colinfo.ColumnTypeIsInvertedIndexable(t)
+}
+
 // AddColumn adds a column to the table.
 func (desc *Mutable) AddColumn(col *descpb.ColumnDescriptor) {
 	desc.Columns = append(desc.Columns, *col)
@@ -2762,7 +2832,7 @@ func (desc *Mutable) AddIndex(idx descpb.IndexDescriptor, primary bool) error {
 		}
 
 	} else {
-		if err := checkColumnsValidForInvertedIndex(desc, idx.ColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, &idx); err != nil {
 			return err
 		}
 		desc.AddPublicNonPrimaryIndex(idx)
@@ -3835,7 +3905,7 @@ func (desc *Mutable) AddIndexMutation(
 			return err
 		}
 	case descpb.IndexDescriptor_INVERTED:
-		if err := checkColumnsValidForInvertedIndex(desc, idx.ColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, idx); err != nil {
 			return err
 		}
 	}
@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -108,6 +109,9 @@ func MakeColumnDefDescs(
 	}
 
 	var idx *descpb.IndexDescriptor
+	if d.Unique.IsUnique && !d.Unique.WithoutIndex && d.Unique.Deferrability.Deferrable {
+		return nil, nil, nil, unimplemented.NewWithIssue(31632, "deferrable unique constraints with an index")
+	}
 	if d.PrimaryKey.IsPrimaryKey || (d.Unique.IsUnique && !d.Unique.WithoutIndex) {
 		if !d.PrimaryKey.Sharded {
 			idx = &descpb.IndexDescriptor{
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -23,6 +24,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -324,6 +327,24 @@ func formatValues(colNames []string, values tree.Datums) string {
 // statically evaluate to true for the entire input).
 type checkSet = util.FastIntSet
 
+// rowLevelSecurityCheckOrd is the ordinal in a checkSet of the check of the
+// row-level security policies of the table.
+const rowLevelSecurityCheckOrd = math.MaxInt32
+
+// makeCheckSet converts the check ordinals of a mutation planned by the
+// optimizer into a checkSet. The optimizer adds the check of the row-level
+// security policies after the checks of the table (including the checks it
+// synthesizes), as ordinal table.CheckCount().
+func makeCheckSet(table cat.Table, ords exec.CheckOrdinalSet) checkSet {
+	if !ords.Contains(table.CheckCount()) {
+		return ords
+	}
+	res := ords.Copy()
+	res.Remove(table.CheckCount())
+	res.Add(rowLevelSecurityCheckOrd)
+	return res
+}
+
 // When executing mutations, we calculate a boolean column for each check
 // indicating if the check passed. This function verifies that each result is
 // true or null.
@@ -333,6 +354,9 @@ type checkSet = util.FastIntSet
 // the entire input); checkOrds contains the set of checks for which we have
 // values, as ordinals into ActiveChecks(). There must be exactly one value in
 // checkVals for each element in checkSet.
+//
+// The check of the row-level security policies of the table, if any, has the
+// ordinal rowLevelSecurityCheckOrd; its value must be true (and not null).
 func checkMutationInput(
 	ctx context.Context,
 	semaCtx *tree.SemaContext,
@@ -369,5 +393,15 @@ func checkMutationInput(
 		}
 		colIdx++
 	}
+
+	if checkOrds.Contains(rowLevelSecurityCheckOrd) {
+		// The ordinal is larger than the others, so the value is the last one.
+		if res, err := tree.GetBool(checkVals[checkOrds.Len()-1]); err != nil {
+			return err
+		} else if !res {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"new row violates row-level security policy for table %q", tabDesc.GetName())
+		}
+	}
 	return nil
 }
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -426,6 +427,7 @@ func (s *Server) SetupConn(
 		ctx, sd, args.SessionDefaults, stmtBuf, clientComm, memMetrics, &s.Metrics,
 		s.sqlStats.getStatsForApplication(sd.ApplicationName),
 	)
+	ex.queryCancelKey = args.QueryCancelKey
 	return ConnectionHandler{ex}, nil
 }
 
@@ -628,6 +630,7 @@ func (s *Server) newConnExecutor(
 		portals:   make(map[string]PreparedPortal),
 	}
 	ex.extraTxnState.prepStmtsNamespaceMemAcc = ex.sessionMon.MakeBoundAccount()
+	ex.extraTxnState.deferredConstraints.acc = ex.sessionMon.MakeBoundAccount()
 	ex.extraTxnState.descCollection = descs.MakeCollection(
 		s.cfg.LeaseManager, s.cfg.Settings, sd, s.cfg.HydratedTables)
 	ex.extraTxnState.txnRewindPos = -1
@@ -857,6 +860,8 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 			ctx, prepStmtNamespace{}, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.prepStmtsNamespaceMemAcc.Close(ctx)
+		ex.extraTxnState.sqlCursors.closeAll(ctx)
+		ex.extraTxnState.deferredConstraints.close(ctx)
 	}
 
 	if ex.sessionTracing.Enabled() {
@@ -1003,6 +1008,17 @@ type connExecutor struct {
 		// connExecutor's closure.
 		prepStmtsNamespaceMemAcc mon.BoundAccount
 
+		// sqlCursors contains the SQL cursors the session currently has access
+		// to. Cursors are scoped to the transaction in which they were declared,
+		// except for WITH HOLD cursors which survive the commit of their
+		// transaction.
+		sqlCursors cursorMap
+
+		// deferredConstraints contains the state of the deferrable constraints:
+		// the modes set with SET CONSTRAINTS and the keys of the deferred
+		// constraint checks, which are validated before committing.
+		deferredConstraints deferredConstraints
+
 		// onTxnFinish (if non-nil) will be called when txn is finished (either
 		// committed or aborted). It is set when txn is started but can remain
 		// unset when txn is executed within another higher-level txn.
@@ -1104,6 +1120,10 @@ type connExecutor struct {
 
 	sessionID ClusterWideID
 
+	// queryCancelKey is the pgwire cancel key of the session; see
+	// SessionArgs.QueryCancelKey.
+	queryCancelKey pgwirecancel.BackendKeyData
+
 	// activated determines whether activate() was called already.
 	// When this is set, close() must be called to release resources.
 	activated bool
@@ -1224,6 +1244,10 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		delete(ex.extraTxnState.schemaChangeJobsCache, k)
 	}
 
+	// Close the cursors that don't outlive the transaction. This stops their
+	// queries, which use the descriptors of the transaction.
+	ex.extraTxnState.sqlCursors.onTxnFinish(ctx, ev)
+
 	ex.extraTxnState.descCollection.ReleaseAll(ctx)
 
 	// Close all portals.
@@ -1232,6 +1256,8 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
 
+	ex.extraTxnState.deferredConstraints.reset(ctx)
+
 	switch ev {
 	case txnCommit, txnRollback:
 		ex.extraTxnState.savepoints.clear()
@@ -1358,9 +1384,9 @@ func (ex *connExecutor) run(
 	ex.onCancelSession = onCancel
 
 	ex.sessionID = ex.generateID()
-	ex.server.cfg.SessionRegistry.register(ex.sessionID, ex)
+	ex.server.cfg.SessionRegistry.register(ex.sessionID, ex.queryCancelKey, ex)
 	ex.planner.extendedEvalCtx.setSessionID(ex.sessionID)
-	defer ex.server.cfg.SessionRegistry.deregister(ex.sessionID)
+	defer ex.server.cfg.SessionRegistry.deregister(ex.sessionID, ex.queryCancelKey)
 
 	for {
 		ex.curStmtAST = nil
@@ -2190,6 +2216,13 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sessionDataMutator = ex.dataMutator
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.sqlCursors = &ex.extraTxnState.sqlCursors
+	p.deferredConstraints = nil
+	if ex.executorType == executorTypeExec {
+		// Internal executors can run in a transaction they don't commit, so the
+		// checks of their statements are never deferred.
+		p.deferredConstraints = &ex.extraTxnState.deferredConstraints
+	}
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -2324,7 +2357,7 @@ func (ex *connExecutor) txnStateTransitionsApplyWrapper(
 // initStatementResult initializes res according to a query.
 //
 // cols represents the columns of the result rows. Should be nil if
-// stmt.AST.StatementType() != tree.Rows.
+// stmt.AST.StatementType() is neither tree.Rows nor tree.CopyOut.
 //
 // If an error is returned, it is to be considered a query execution error.
 func (ex *connExecutor) initStatementResult(
@@ -2335,7 +2368,7 @@ func (ex *connExecutor) initStatementResult(
 			return err
 		}
 	}
-	if ast.StatementType() == tree.Rows {
+	if stmtType := ast.StatementType(); stmtType == tree.Rows || stmtType == tree.CopyOut {
 		// Note that this call is necessary even if cols is nil.
 		res.SetColumns(ctx, cols)
 	}
@@ -2359,6 +2392,18 @@ func (ex *connExecutor) cancelQuery(queryID ClusterWideID) bool {
 	return false
 }
 
+// cancelCurrentQueries is part of the registrySession interface.
+func (ex *connExecutor) cancelCurrentQueries() bool {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	canceled := false
+	for _, queryMeta := range ex.mu.ActiveQueries {
+		queryMeta.cancel()
+		canceled = true
+	}
+	return canceled
+}
+
 // cancelSession is part of the registrySession interface.
 func (ex *connExecutor) cancelSession() {
 	if ex.onCancelSession == nil {
@@ -431,6 +431,10 @@ func (ex *connExecutor) execStmtInOpenState(
 		}
 	}()
 
+	// declareCursor is set if the statement is a DECLARE statement, in which
+	// case the query of the cursor is planned instead of being executed.
+	var declareCursor *tree.DeclareCursor
+
 	switch s := ast.(type) {
 	case *tree.BeginTransaction:
 		// BEGIN is always an error when in the Open state. It's legitimate only in
@@ -532,6 +536,18 @@ func (ex *connExecutor) execStmtInOpenState(
 		if s.DiscardRows {
 			ih.SetDiscardRows()
 		}
+
+	case *tree.DeclareCursor:
+		// Continue below with the query of the cursor, which is planned
+		// instead of the statement. The cursor is created once the query has
+		// been planned; the query is run as the rows of the cursor are fetched.
+		if err := ex.checkDeclareCursor(s, os.ImplicitTxn.Get()); err != nil {
+			return makeErrEvent(err)
+		}
+		declareCursor = s
+		stmt.AST = s.Select
+		ast = s.Select
+		stmt.ExpectedTypes = nil
 	}
 
 	p.semaCtx.Annotations = tree.MakeAnnotations(stmt.NumAnnotations)
@@ -638,7 +654,11 @@ func (ex *connExecutor) execStmtInOpenState(
 		stmtThresholdSpan.SetVerbose(true)
 	}
 
-	if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+	if declareCursor != nil {
+		if err := ex.execDeclareCursor(ctx, p, declareCursor); err != nil {
+			res.SetError(err)
+		}
+	} else if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
 		return nil, nil, err
 	}
 
@@ -732,6 +752,21 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	// Close the cursors that don't outlive the transaction and buffer the
+	// rows of the ones that do, while the transaction can still be rolled back.
+	if err := ex.extraTxnState.sqlCursors.beforeCommit(ctx); err != nil {
+		return err
+	}
+
+	// Check the keys of the deferred constraint checks, while the transaction
+	// can still be rolled back.
+	ie := ex.planner.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+	if err := ex.extraTxnState.deferredConstraints.validate(
+		ctx, ie, ex.state.mu.txn, &ex.extraTxnState.descCollection, nil, /* names */
+	); err != nil {
+		return err
+	}
+
 	if err := ex.checkDescriptorTwoVersionInvariant(ctx); err != nil {
 		return err
 	}
@@ -827,7 +862,7 @@ func (ex *connExecutor) dispatchToExecutionEngine(
 	}
 
 	var cols colinfo.ResultColumns
-	if stmt.AST.StatementType() == tree.Rows {
+	if stmtType := stmt.AST.StatementType(); stmtType == tree.Rows || stmtType == tree.CopyOut {
 		cols = planner.curPlan.main.planColumns()
 	}
 	if err := ex.initStatementResult(ctx, res, stmt.AST, cols); err != nil {
@@ -1001,6 +1036,13 @@ func (ex *connExecutor) execWithDistSQLEngine(
 			return recv.stats, recv.commErr
 		}
 	}
+	if planner.curPlan.hasBeforeTriggers() {
+		if !ex.server.cfg.DistSQLPlanner.PlanAndRunBeforeTriggers(
+			ctx, planner, evalCtxFactory, &planner.curPlan.planComponents, recv,
+		) {
+			return recv.stats, recv.commErr
+		}
+	}
 	recv.discardRows = planner.instrumentation.ShouldDiscardRows()
 	// We pass in whether or not we wanted to distribute this plan, which tells
 	// the planner whether or not to plan remote table readers.
@@ -716,7 +716,7 @@ type RestrictedCommandResult interface {
 	AddRow(ctx context.Context, row tree.Datums) error
 
 	// IncrementRowsAffected increments a counter by n. This is used for all
-	// result types other than tree.Rows.
+	// result types other than tree.Rows and tree.CopyOut.
 	IncrementRowsAffected(n int)
 
 	// RowsAffected returns either the number of times AddRow was called, or the
@@ -0,0 +1,125 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/tests"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCopyOut uses the pgx driver, which supports the COPY TO sub-protocol.
+func TestCopyOut(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	params, _ := tests.CreateTestServerParams()
+	s, db, _ := serverutils.StartServer(t, params)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupGoDB := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "StartServer" /* prefix */, url.User(security.RootUser))
+	defer cleanupGoDB()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	sqlDB.Exec(t, `
+		CREATE TABLE t (id INT8 PRIMARY KEY, s STRING, b BOOL);
+		INSERT INTO t VALUES
+			(1, 'a', true),
+			(2, NULL, false),
+			(3, e'tab\tnew\nline\\', NULL),
+			(4, 'com,ma "quoted"', true),
+			(5, '', false);
+	`)
+
+	for _, tc := range []struct {
+		stmt     string
+		expected string
+	}{
+		{
+			stmt: `COPY t TO STDOUT`,
+			expected: "1\ta\tt\n" +
+				"2\t\\N\tf\n" +
+				"3\ttab\\tnew\\nline\\\\\t\\N\n" +
+				"4\tcom,ma \"quoted\"\tt\n" +
+				"5\t\tf\n",
+		},
+		{
+			stmt:     `COPY t (s, id) TO STDOUT WITH DELIMITER '|' NULL 'n/a'`,
+			expected: "a|1\nn/a|2\ntab\\tnew\\nline\\\\|3\ncom,ma \"quoted\"|4\n|5\n",
+		},
+		{
+			stmt: `COPY t TO STDOUT WITH CSV HEADER`,
+			expected: "id,s,b\n" +
+				"1,a,t\n" +
+				"2,,f\n" +
+				"3,\"tab\tnew\nline\\\",\n" +
+				"4,\"com,ma \"\"quoted\"\"\",t\n" +
+				"5,\"\",f\n",
+		},
+		{
+			stmt:     `COPY (SELECT id, s FROM t WHERE id < 3 ORDER BY id DESC) TO STDOUT CSV DELIMITER ';'`,
+			expected: "2;\n1;a\n",
+		},
+		{
+			stmt:     `COPY (INSERT INTO t VALUES (6, 'f', true) RETURNING id) TO STDOUT`,
+			expected: "6\n",
+		},
+		{
+			stmt: `COPY (SELECT 1::INT4, NULL::INT8) TO STDOUT BINARY`,
+			expected: "PGCOPY\n\377\r\n\000" + "\000\000\000\000" + "\000\000\000\000" +
+				"\000\002" + "\000\000\000\004" + "\000\000\000\001" + "\377\377\377\377" +
+				"\377\377",
+		},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			var buf bytes.Buffer
+			tag, err := conn.PgConn().CopyTo(ctx, &buf, tc.stmt)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf.String())
+			require.Regexp(t, `^COPY \d+$`, tag.String())
+		})
+	}
+
+	for _, tc := range []struct {
+		stmt        string
+		expectedErr string
+	}{
+		{`COPY t TO STDOUT WITH BINARY DELIMITER ','`, `cannot specify DELIMITER in BINARY mode`},
+		{`COPY t TO STDOUT WITH HEADER`, `COPY HEADER available only in CSV mode`},
+		{`COPY t TO STDOUT WITH DELIMITER '||'`, `COPY delimiter must be a single one-byte character`},
+		{`COPY t TO STDOUT WITH CSV DELIMITER '"'`, `COPY delimiter and quote must be different`},
+		{`COPY t TO STDOUT WITH NULL e'a\tb'`, `COPY delimiter must not appear in the NULL specification`},
+		{`COPY (INSERT INTO t VALUES (7)) TO STDOUT`, `COPY query must have a RETURNING clause`},
+		{`COPY t TO STDOUT WITH destination = 'foo'`, `DESTINATION option is not supported by COPY TO`},
+	} {
+		t.Run(tc.stmt, func(t *testing.T) {
+			var buf bytes.Buffer
+			_, err := conn.PgConn().CopyTo(ctx, &buf, tc.stmt)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
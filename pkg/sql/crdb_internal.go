@@ -2219,6 +2219,7 @@ CREATE TABLE crdb_internal.backward_dependencies (
 		viewDep := tree.NewDString("view")
 		sequenceDep := tree.NewDString("sequence")
 		interleaveDep := tree.NewDString("interleave")
+		functionDep := tree.NewDString("function")
 		return forEachTableDescAllWithTableLookup(ctx, p, dbContext, hideVirtual, func(
 			db *dbdesc.Immutable, _ string, table catalog.TableDescriptor, tableLookup tableLookupFn,
 		) error {
@@ -2289,6 +2290,20 @@ CREATE TABLE crdb_internal.backward_dependencies (
 					return err
 				}
 			}
+			for _, fnID := range table.TableDesc().DependsOnFunctions {
+				if err := addRow(
+					tableID, tableName,
+					tree.DNull,
+					tree.DNull,
+					tree.NewDInt(tree.DInt(fnID)),
+					functionDep,
+					tree.DNull,
+					tree.DNull,
+					tree.DNull,
+				); err != nil {
+					return err
+				}
+			}
 
 			// Record sequence dependencies.
 			return table.ForeachPublicColumn(func(col *descpb.ColumnDescriptor) error {
@@ -2361,8 +2376,9 @@ CREATE TABLE crdb_internal.forward_dependencies (
 		viewDep := tree.NewDString("view")
 		interleaveDep := tree.NewDString("interleave")
 		sequenceDep := tree.NewDString("sequence")
-		return forEachTableDescAll(ctx, p, dbContext, hideVirtual, /* virtual tables have no backward/forward dependencies*/
-			func(db *dbdesc.Immutable, _ string, table catalog.TableDescriptor) error {
+		functionDep := tree.NewDString("function")
+		return forEachTableDescAllWithTableLookup(ctx, p, dbContext, hideVirtual, /* virtual tables have no backward/forward dependencies*/
+			func(db *dbdesc.Immutable, _ string, table catalog.TableDescriptor, lookup tableLookupFn) error {
 				tableID := tree.NewDInt(tree.DInt(table.GetID()))
 				tableName := tree.NewDString(table.GetName())
 
@@ -2419,10 +2435,16 @@ CREATE TABLE crdb_internal.forward_dependencies (
 
 				if table.IsTable() || table.IsView() {
 					return table.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
+						if lookup.isFunction(dep.ID) {
+							return reportDependedOnBy(dep, functionDep)
+						}
 						return reportDependedOnBy(dep, viewDep)
 					})
 				} else if table.IsSequence() {
 					return table.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
+						if lookup.isFunction(dep.ID) {
+							return reportDependedOnBy(dep, functionDep)
+						}
 						return reportDependedOnBy(dep, sequenceDep)
 					})
 				}
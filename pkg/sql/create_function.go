@@ -0,0 +1,275 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+// createFunctionNode represents a CREATE FUNCTION statement.
+type createFunctionNode struct {
+	n *tree.CreateFunction
+	// funcName is the fully resolved name of the function.
+	funcName *tree.TableName
+	// body is the body of the function, with all data source names fully
+	// qualified.
+	body   string
+	dbDesc catalog.DatabaseDescriptor
+	// planDeps tracks which tables, views and sequences the function body
+	// depends on, and which of their columns and indexes it references.
+	planDeps planDependencies
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.UserDefinedFunctions) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create functions",
+			clusterversion.UserDefinedFunctions)
+	}
+
+	if n.n.Replace {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("or_replace_function"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+	}
+
+	fnParams := make([]descpb.FunctionDescriptor_Param, len(n.n.Params))
+	for i := range n.n.Params {
+		typ, err := getFunctionType(n.n.Params[i].Type)
+		if err != nil {
+			return err
+		}
+		fnParams[i] = descpb.FunctionDescriptor_Param{Name: string(n.n.Params[i].Name), Type: typ}
+	}
+	retType, err := getFunctionType(n.n.ReturnType)
+	if err != nil {
+		return err
+	}
+	nullInputBehavior := descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+	if n.n.Options.NullInputBehavior == tree.FunctionReturnsNullOnNullInput {
+		nullInputBehavior = descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+	}
+
+	// Load the relations the function depends on, so that we can install
+	// back-references to the function.
+	depIDs := make([]descpb.ID, 0, len(n.planDeps))
+	for id := range n.planDeps {
+		depIDs = append(depIDs, id)
+	}
+	sort.Slice(depIDs, func(i, j int) bool { return depIDs[i] < depIDs[j] })
+	backRefMutables := make([]*tabledesc.Mutable, len(depIDs))
+	for i, id := range depIDs {
+		backRefMutable, err := params.p.Descriptors().GetMutableTableVersionByID(params.ctx, id, params.p.txn)
+		if err != nil {
+			return err
+		}
+		if backRefMutable.Temporary {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"function %s cannot depend on temporary %s %q",
+				n.funcName, backRefMutable.TypeName(), backRefMutable.Name)
+		}
+		backRefMutables[i] = backRefMutable
+	}
+
+	dbID := n.dbDesc.GetID()
+	schemaID, err := params.p.getSchemaIDForCreate(
+		params.ctx, params.ExecCfg().Codec, dbID, n.funcName.Schema(),
+	)
+	if err != nil {
+		return err
+	}
+	if err := params.p.canCreateOnSchema(
+		params.ctx, schemaID, dbID, params.p.User(), skipCheckPublicSchema,
+	); err != nil {
+		return err
+	}
+	if schemaID != keys.PublicSchemaID {
+		sqltelemetry.IncrementUserDefinedSchemaCounter(sqltelemetry.UserDefinedSchemaUsedByObject)
+	}
+
+	// Check whether the name is already taken, and whether it is taken by a
+	// function that we are allowed to replace.
+	var fnDesc *funcdesc.Mutable
+	fnKey := catalogkv.MakeObjectNameKey(
+		params.ctx, params.ExecCfg().Settings, dbID, schemaID, n.funcName.Object(),
+	)
+	exists, collided, err := catalogkv.LookupObjectID(
+		params.ctx, params.p.txn, params.ExecCfg().Codec, dbID, schemaID, n.funcName.Object(),
+	)
+	if err != nil {
+		return err
+	}
+	if exists {
+		desc, err := params.p.Descriptors().GetMutableDescriptorByID(params.ctx, collided, params.p.txn)
+		if err != nil {
+			return sqlerrors.WrapErrorWhileConstructingObjectAlreadyExistsErr(err)
+		}
+		existing, isFunc := desc.(*funcdesc.Mutable)
+		if !n.n.Replace || !isFunc || existing.Dropped() {
+			return sqlerrors.MakeObjectAlreadyExistsError(desc.DescriptorProto(), n.funcName.String())
+		}
+		if err := params.p.canModifyFunction(params.ctx, existing); err != nil {
+			return err
+		}
+		fnDesc = existing
+	}
+
+	if fnDesc != nil {
+		// Replace the existing function. Calls to the function are resolved
+		// by the optimizer when they are planned, so a function can be
+		// replaced with one with a different signature.
+		// Views call the function with arguments of the types of its
+		// parameters and use its result, so its signature cannot change while
+		// views call it.
+		if len(fnDesc.ReferencingDescriptorIDs) > 0 && !functionSignatureMatches(fnDesc, fnParams, retType) {
+			return params.p.dependentViewError(
+				params.ctx, "signature of function", n.funcName.String(), dbID,
+				fnDesc.ReferencingDescriptorIDs[0], "change",
+			)
+		}
+		if err := params.p.removeFunctionBackReferences(params.ctx, fnDesc, depIDs); err != nil {
+			return err
+		}
+		fnDesc.Params = fnParams
+		fnDesc.ReturnType = retType
+		fnDesc.Volatility = funcdesc.VolatilityFromTree(n.n.Options.Volatility)
+		fnDesc.NullInputBehavior = nullInputBehavior
+		fnDesc.FunctionBody = n.body
+		fnDesc.DependsOn = depIDs
+		if err := params.p.writeFunctionDescChange(
+			params.ctx, fnDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+		); err != nil {
+			return err
+		}
+	} else {
+		id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.p.ExecCfg().DB, params.p.ExecCfg().Codec)
+		if err != nil {
+			return err
+		}
+		fnDesc = funcdesc.NewCreatedMutable(descpb.FunctionDescriptor{
+			Name:              n.funcName.Object(),
+			ID:                id,
+			ParentID:          dbID,
+			ParentSchemaID:    schemaID,
+			Version:           1,
+			Privileges:        descpb.NewDefaultPrivilegeDescriptor(params.p.User()),
+			Params:            fnParams,
+			ReturnType:        retType,
+			Volatility:        funcdesc.VolatilityFromTree(n.n.Options.Volatility),
+			NullInputBehavior: nullInputBehavior,
+			FunctionBody:      n.body,
+			DependsOn:         depIDs,
+		})
+		if err := params.p.createDescriptorWithID(
+			params.ctx,
+			fnKey.Key(params.ExecCfg().Codec),
+			id,
+			fnDesc,
+			params.EvalContext().Settings,
+			tree.AsStringWithFQNames(n.n, params.Ann()),
+		); err != nil {
+			return err
+		}
+	}
+
+	// Persist the back-references in all referenced relations.
+	for _, backRefMutable := range backRefMutables {
+		// In case that we are replacing a function that already depends on
+		// this relation, remove all existing references first.
+		backRefMutable.DependedOnBy = removeMatchingReferences(backRefMutable.DependedOnBy, fnDesc.ID)
+		for _, dep := range n.planDeps[backRefMutable.ID].deps {
+			// The ID of the function was not known when the dependencies were
+			// collected.
+			dep.ID = fnDesc.ID
+			backRefMutable.DependedOnBy = append(backRefMutable.DependedOnBy, dep)
+		}
+		if err := params.p.writeSchemaChange(
+			params.ctx,
+			backRefMutable,
+			descpb.InvalidMutationID,
+			fmt.Sprintf("updating function reference %q in table %s(%d)", n.funcName,
+				backRefMutable.Name, backRefMutable.ID,
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	dg := catalogkv.NewOneLevelUncachedDescGetter(params.p.txn, params.ExecCfg().Codec)
+	if err := fnDesc.Validate(params.ctx, dg); err != nil {
+		return err
+	}
+
+	// Log Create Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor update.
+	return params.p.logEvent(params.ctx,
+		fnDesc.ID,
+		&eventpb.CreateFunction{
+			FunctionName: n.funcName.FQString(),
+			Owner:        fnDesc.GetPrivileges().Owner().Normalized(),
+			IsReplace:    n.n.Replace,
+		})
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createFunctionNode) Close(ctx context.Context)  {}
+
+// functionSignatureMatches returns whether the given function has the given
+// parameter types and return type.
+func functionSignatureMatches(
+	fnDesc *funcdesc.Mutable, params []descpb.FunctionDescriptor_Param, retType *types.T,
+) bool {
+	if len(params) != len(fnDesc.Params) || !retType.Identical(fnDesc.ReturnType) {
+		return false
+	}
+	for i := range params {
+		if !params[i].Type.Identical(fnDesc.Params[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// getFunctionType returns the type of a parameter or of the result of a
+// user-defined function. The optimizer has already rejected types that are
+// not statically known.
+func getFunctionType(ref tree.ResolvableTypeReference) (*types.T, error) {
+	typ, ok := tree.GetStaticallyKnownType(ref)
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected function type %s", ref.SQLString())
+	}
+	return typ, nil
+}
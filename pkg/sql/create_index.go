@@ -250,6 +250,12 @@ func MakeIndexDescriptor(
 	if err := indexDesc.FillColumns(n.Columns); err != nil {
 		return nil, err
 	}
+	if indexDesc.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM &&
+		!params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.TrigramInvertedIndexes) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create trigram inverted indexes",
+			clusterversion.TrigramInvertedIndexes)
+	}
 
 	if err := paramparse.ApplyStorageParameters(
 		params.ctx,
@@ -0,0 +1,194 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createPolicyNode struct {
+	n         *tree.CreatePolicy
+	tableDesc *tabledesc.Mutable
+	policy    descpb.TableDescriptor_Policy
+}
+
+// Use to satisfy the linter.
+var _ planNode = &createPolicyNode{n: nil}
+
+// CreatePolicy creates a row-level security policy.
+// Privileges: ownership of the table.
+func (p *planner) CreatePolicy(ctx context.Context, n *tree.CreatePolicy) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE POLICY",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.RowLevelSecurity) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create policies",
+			clusterversion.RowLevelSecurity)
+	}
+
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.canModifyRowLevelSecurity(ctx, tableDesc); err != nil {
+		return nil, err
+	}
+
+	for i := range tableDesc.Policies {
+		if tableDesc.Policies[i].Name == string(n.Name) {
+			return nil, pgerror.Newf(pgcode.DuplicateObject,
+				"policy %q for table %q already exists", n.Name, tableDesc.Name)
+		}
+	}
+
+	policy := descpb.TableDescriptor_Policy{
+		Name:        string(n.Name),
+		Restrictive: n.Restrictive,
+	}
+	switch n.Command {
+	case tree.PolicyCommandAll:
+		policy.Command = descpb.TableDescriptor_Policy_ALL
+	case tree.PolicyCommandSelect:
+		policy.Command = descpb.TableDescriptor_Policy_SELECT
+	case tree.PolicyCommandInsert:
+		policy.Command = descpb.TableDescriptor_Policy_INSERT
+	case tree.PolicyCommandUpdate:
+		policy.Command = descpb.TableDescriptor_Policy_UPDATE
+	case tree.PolicyCommandDelete:
+		policy.Command = descpb.TableDescriptor_Policy_DELETE
+	}
+	if n.Using != nil && n.Command == tree.PolicyCommandInsert {
+		return nil, pgerror.New(pgcode.Syntax, "only WITH CHECK expression allowed for INSERT")
+	}
+	if n.WithCheck != nil &&
+		(n.Command == tree.PolicyCommandSelect || n.Command == tree.PolicyCommandDelete) {
+		return nil, pgerror.New(pgcode.Syntax, "WITH CHECK cannot be applied to SELECT or DELETE")
+	}
+
+	for _, role := range n.Roles {
+		if !role.IsPublicRole() {
+			exists, err := p.RoleExists(ctx, role)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, pgerror.Newf(pgcode.UndefinedObject, "role/user %q does not exist", role)
+			}
+		}
+		policy.Roles = append(policy.Roles, role.Normalized())
+	}
+	if len(policy.Roles) == 0 {
+		policy.Roles = []string{security.PublicRole}
+	}
+
+	// The expressions are stored with their column references dequalified,
+	// like check constraints, so that they can be built in the scope of any
+	// alias of the table.
+	validate := func(expr tree.Expr) (string, error) {
+		if expr == nil {
+			return "", nil
+		}
+		s, _, err := schemaexpr.DequalifyAndValidateExpr(
+			ctx, tableDesc, expr, types.Bool, "CREATE POLICY", &p.semaCtx, tree.VolatilityVolatile, &n.Table,
+		)
+		return s, err
+	}
+	if policy.UsingExpr, err = validate(n.Using); err != nil {
+		return nil, err
+	}
+	if policy.WithCheckExpr, err = validate(n.WithCheck); err != nil {
+		return nil, err
+	}
+
+	return &createPolicyNode{n: n, tableDesc: tableDesc, policy: policy}, nil
+}
+
+// canModifyRowLevelSecurity checks that the current user can change the
+// policies of the table or whether they are enforced, which requires
+// ownership of the table, as in Postgres. The CREATE privilege is not enough,
+// since it would let a user exempt itself from the policies.
+func (p *planner) canModifyRowLevelSecurity(ctx context.Context, desc *tabledesc.Mutable) error {
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	if hasAdmin {
+		return nil
+	}
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of table %s", tree.Name(desc.GetName()))
+	}
+	return nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE POLICY performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createPolicyNode) ReadingOwnWrites() {}
+
+func (n *createPolicyNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("policy"))
+
+	// Policies are kept sorted by name.
+	policies := n.tableDesc.Policies
+	idx := sort.Search(len(policies), func(i int) bool {
+		return policies[i].Name >= n.policy.Name
+	})
+	policies = append(policies, descpb.TableDescriptor_Policy{})
+	copy(policies[idx+1:], policies[idx:])
+	policies[idx] = n.policy
+	n.tableDesc.Policies = policies
+
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Log Create Policy event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return params.p.logEvent(params.ctx,
+		n.tableDesc.ID,
+		&eventpb.CreatePolicy{
+			TableName:  n.n.Table.FQString(),
+			PolicyName: n.policy.Name,
+		})
+}
+
+func (n *createPolicyNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createPolicyNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createPolicyNode) Close(ctx context.Context)           {}
@@ -87,6 +87,10 @@ func (p *planner) CreateRoleNode(
 		return nil, err
 	}
 
+	if err := p.checkBypassRLSOptionConstraints(ctx, roleOptions); err != nil {
+		return nil, err
+	}
+
 	ua, err := p.getUserAuthInfo(ctx, nameE, opName)
 	if err != nil {
 		return nil, err
@@ -322,7 +326,7 @@ func (p *planner) checkPasswordAndGetHash(
 		}
 	}
 
-	hashedPassword, err = security.HashPassword(password)
+	hashedPassword, err = security.HashPasswordWithMethod(GetConfiguredPasswordHashMethod(ctx, st), password)
 	if err != nil {
 		return hashedPassword, err
 	}
@@ -373,7 +373,10 @@ func createStatsDefaultColumns(
 
 	// Add column stats for each secondary index.
 	for i := range desc.GetPublicNonPrimaryIndexes() {
-		isInverted := desc.GetPublicNonPrimaryIndexes()[i].Type == descpb.IndexDescriptor_INVERTED
+		// Statistics on the trigrams of trigram inverted indexes are not
+		// collected.
+		isInverted := desc.GetPublicNonPrimaryIndexes()[i].Type == descpb.IndexDescriptor_INVERTED &&
+			desc.GetPublicNonPrimaryIndexes()[i].InvertedColumnKind != descpb.IndexDescriptor_TRIGRAM
 
 		for j := range desc.GetPublicNonPrimaryIndexes()[i].ColumnIDs {
 			// Generate stats for each indexed column.
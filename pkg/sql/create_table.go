@@ -609,6 +609,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	tbl *tabledesc.Mutable,
 	constraintName string,
 	colNames []string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -662,10 +663,12 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Validity:  validity,
+		Name:              constraintName,
+		TableID:           tbl.ID,
+		ColumnIDs:         columnIDs,
+		Validity:          validity,
+		Deferrable:        deferrability.Deferrable,
+		InitiallyDeferred: deferrability.InitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -681,6 +684,22 @@ func ResolveUniqueWithoutIndexConstraint(
 	return nil
 }
 
+// checkDeferrabilityVersion returns an error if the constraint is deferrable
+// but the cluster version does not allow storing its deferrability yet.
+func checkDeferrabilityVersion(
+	ctx context.Context, st *cluster.Settings, deferrability tree.ConstraintDeferrability,
+) error {
+	if !deferrability.Deferrable && !deferrability.InitiallyDeferred {
+		return nil
+	}
+	if st != nil && !st.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use deferrable constraints",
+			clusterversion.DeferrableConstraints)
+	}
+	return nil
+}
+
 // ResolveFK looks up the tables and columns mentioned in a `REFERENCES`
 // constraint and adds metadata representing that constraint to the descriptor.
 // It may, in doing so, add to or alter descriptors in the passed in `backrefs`
@@ -717,6 +736,10 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if err := checkDeferrabilityVersion(ctx, evalCtx.Settings, d.Deferrability); err != nil {
+		return err
+	}
+
 	var originColSet catalog.TableColSet
 	originCols := make([]*descpb.ColumnDescriptor, len(d.FromCols))
 	for i, col := range d.FromCols {
@@ -932,6 +955,8 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrable:          d.Deferrability.Deferrable,
+		InitiallyDeferred:   d.Deferrability.InitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -1532,6 +1557,12 @@ func NewTableDesc(
 			if err := idx.FillColumns(d.Columns); err != nil {
 				return nil, err
 			}
+			if idx.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM &&
+				!evalCtx.Settings.Version.IsActive(ctx, clusterversion.TrigramInvertedIndexes) {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to create trigram inverted indexes",
+					clusterversion.TrigramInvertedIndexes)
+			}
 			if d.Inverted {
 				columnDesc, _, err := desc.FindColumnByName(tree.Name(idx.InvertedColumnName()))
 				if err != nil {
@@ -1584,6 +1615,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrability.Deferrable {
+				return nil, unimplemented.NewWithIssue(31632, "deferrable unique constraints with an index")
+			}
 			idx := descpb.IndexDescriptor{
 				Name:             string(d.Name),
 				Unique:           true,
@@ -1773,10 +1807,13 @@ func NewTableDesc(
 						"unique constraints without an index are not yet supported",
 					)
 				}
+				if err := checkDeferrabilityVersion(ctx, evalCtx.Settings, d.Unique.Deferrability); err != nil {
+					return nil, err
+				}
 				// Add a unique constraint.
 				if err := ResolveUniqueWithoutIndexConstraint(
-					ctx, &desc, string(d.Unique.ConstraintName), []string{string(d.Name)}, NewTable,
-					tree.ValidationDefault,
+					ctx, &desc, string(d.Unique.ConstraintName), []string{string(d.Name)},
+					d.Unique.Deferrability, NewTable, tree.ValidationDefault,
 				); err != nil {
 					return nil, err
 				}
@@ -1816,13 +1853,16 @@ func NewTableDesc(
 						"unique constraints with a predicate but without an index are not supported",
 					)
 				}
+				if err := checkDeferrabilityVersion(ctx, evalCtx.Settings, d.Deferrability); err != nil {
+					return nil, err
+				}
 				// Add a unique constraint.
 				colNames := make([]string, len(d.Columns))
 				for i := range colNames {
 					colNames[i] = string(d.Columns[i].Column)
 				}
 				if err := ResolveUniqueWithoutIndexConstraint(
-					ctx, &desc, string(d.Name), colNames, NewTable, tree.ValidationDefault,
+					ctx, &desc, string(d.Name), colNames, d.Deferrability, NewTable, tree.ValidationDefault,
 				); err != nil {
 					return nil, err
 				}
@@ -2117,6 +2157,10 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 						Columns: make(tree.IndexElemList, 0, len(c.ColumnIDs)),
 					},
 					WithoutIndex: true,
+					Deferrability: tree.ConstraintDeferrability{
+						Deferrable:        c.Deferrable,
+						InitiallyDeferred: c.InitiallyDeferred,
+					},
 				}
 				colNames, err := td.NamesForColumnIDs(c.ColumnIDs)
 				if err != nil {
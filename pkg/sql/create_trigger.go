@@ -0,0 +1,118 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+// createTriggerNode represents a CREATE TRIGGER statement.
+type createTriggerNode struct {
+	n       *tree.CreateTrigger
+	tableID descpb.ID
+	// action is the action statement of the trigger, with all data source
+	// names fully qualified.
+	action string
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	if !params.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.TableTriggers) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create triggers",
+			clusterversion.TableTriggers)
+	}
+
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	tableDesc, err := params.p.Descriptors().GetMutableTableVersionByID(
+		params.ctx, n.tableID, params.p.txn,
+	)
+	if err != nil {
+		return err
+	}
+	if err := params.p.CheckPrivilege(params.ctx, tableDesc, privilege.CREATE); err != nil {
+		return err
+	}
+
+	name := string(n.n.Name)
+	idx := sort.Search(len(tableDesc.Triggers), func(i int) bool {
+		return tableDesc.Triggers[i].Name >= name
+	})
+	if idx < len(tableDesc.Triggers) && tableDesc.Triggers[idx].Name == name {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"trigger %q for relation %q already exists", name, tableDesc.Name)
+	}
+
+	trigger := descpb.TableDescriptor_Trigger{
+		Name:            name,
+		ActionTime:      descpb.TableDescriptor_Trigger_AFTER,
+		ForEachRow:      n.n.ForEachRow,
+		ActionStatement: n.action,
+		IsConstraint:    n.n.Constraint,
+	}
+	if n.n.ActionTime == tree.TriggerBefore {
+		trigger.ActionTime = descpb.TableDescriptor_Trigger_BEFORE
+	}
+	for _, e := range n.n.Events {
+		switch e {
+		case tree.TriggerEventInsert:
+			trigger.OnInsert = true
+		case tree.TriggerEventUpdate:
+			trigger.OnUpdate = true
+		case tree.TriggerEventDelete:
+			trigger.OnDelete = true
+		}
+	}
+	// Triggers are kept sorted by name, which is also the order in which they
+	// fire.
+	tableDesc.Triggers = append(tableDesc.Triggers, descpb.TableDescriptor_Trigger{})
+	copy(tableDesc.Triggers[idx+1:], tableDesc.Triggers[idx:])
+	tableDesc.Triggers[idx] = trigger
+
+	if err := params.p.writeSchemaChange(
+		params.ctx, tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	tn, err := params.p.getQualifiedTableName(params.ctx, tableDesc)
+	if err != nil {
+		return err
+	}
+	// Log Create Trigger event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return params.p.logEvent(params.ctx,
+		tableDesc.ID,
+		&eventpb.CreateTrigger{
+			TableName:   tn.FQString(),
+			TriggerName: name,
+		})
+}
+
+func (n *createTriggerNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createTriggerNode) Close(ctx context.Context)           {}
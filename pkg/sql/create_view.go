@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -52,6 +53,8 @@ type createViewNode struct {
 	// depends on. This is collected during the construction of
 	// the view query's logical plan.
 	planDeps planDependencies
+	// funcDeps are the user-defined functions called by the view query.
+	funcDeps []*funcdesc.Immutable
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -80,6 +83,18 @@ func (n *createViewNode) startExec(params runParams) error {
 				)
 			}
 		}
+		for _, fn := range n.funcDeps {
+			if fn.ParentID != n.dbDesc.ID {
+				return pgerror.Newf(pgcode.FeatureNotSupported,
+					"the view cannot refer to other databases; (see the '%s' cluster setting)",
+					allowCrossDatabaseViewsSetting,
+				)
+			}
+		}
+	}
+	fnIDs := make([]descpb.ID, 0, len(n.funcDeps))
+	for _, fn := range n.funcDeps {
+		fnIDs = append(fnIDs, fn.ID)
 	}
 
 	// First check the backrefs and see if any of them are temporary.
@@ -203,6 +218,7 @@ func (n *createViewNode) startExec(params runParams) error {
 		for backrefID := range n.planDeps {
 			desc.DependsOn = append(desc.DependsOn, backrefID)
 		}
+		desc.DependsOnFunctions = fnIDs
 
 		// TODO (lucy): I think this needs a NodeFormatter implementation. For now,
 		// do some basic string formatting (not accurate in the general case).
@@ -246,6 +262,11 @@ func (n *createViewNode) startExec(params runParams) error {
 		}
 	}
 
+	// Persist the back-references in all called functions.
+	if err := params.p.addViewFunctionBackReferences(params.ctx, newDesc, fnIDs); err != nil {
+		return err
+	}
+
 	// Install back references to types used by this view.
 	if err := params.p.addBackRefsFromAllTypesInTable(params.ctx, newDesc); err != nil {
 		return err
@@ -373,6 +394,17 @@ func (p *planner) replaceViewDesc(
 		toReplace.DependsOn = append(toReplace.DependsOn, backrefID)
 	}
 
+	// Likewise, remove the back-references from the functions that the view
+	// no longer calls.
+	fnIDs := make([]descpb.ID, len(n.funcDeps))
+	for i, fn := range n.funcDeps {
+		fnIDs[i] = fn.ID
+	}
+	if err := p.removeViewFunctionBackReferences(ctx, toReplace, fnIDs); err != nil {
+		return nil, err
+	}
+	toReplace.DependsOnFunctions = fnIDs
+
 	// Since we are replacing an existing view here, we need to write the new
 	// descriptor into place.
 	if err := p.writeSchemaChange(ctx, toReplace, descpb.InvalidMutationID,
@@ -0,0 +1,391 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// deferredConstraintsBatchSize is the maximum number of keys that are
+// validated by a single query.
+const deferredConstraintsBatchSize = 100
+
+// deferredConstraints holds the state of the deferrable constraints in a
+// transaction: the modes set with SET CONSTRAINTS, and the keys of the
+// constraint checks that were deferred until the end of the transaction.
+//
+// A check is deferred when its query (see errorIfRowsNode) finds violations
+// of a deferred constraint. The keys of the violating rows are recorded, and
+// the constraint is checked again for those keys when the transaction
+// commits, or when SET CONSTRAINTS makes the constraint immediate. A key that
+// no longer violates the constraint by then, for example because the missing
+// referenced row was inserted, is not an error.
+type deferredConstraints struct {
+	// allSet is true if SET CONSTRAINTS ALL was used in the transaction, in
+	// which case allDeferred is the mode it set.
+	allSet      bool
+	allDeferred bool
+
+	// deferred maps the names of the constraints set by SET CONSTRAINTS since
+	// the last SET CONSTRAINTS ALL to whether they are deferred.
+	deferred map[string]bool
+
+	// pending contains the keys of the deferred checks, per constraint.
+	pending map[deferredConstraintID]*deferredKeys
+
+	// acc accounts for the memory used by the pending keys. It is bound to the
+	// session monitor, so that a transaction deferring too many violations
+	// fails with a memory budget error instead of exhausting the node's memory.
+	acc mon.BoundAccount
+}
+
+// deferredConstraintID identifies a constraint with deferred checks.
+type deferredConstraintID struct {
+	// tableID is the table of the constraint; for a foreign key, it is the
+	// origin table.
+	tableID    descpb.ID
+	name       string
+	foreignKey bool
+}
+
+// deferredKeys are the keys of the deferred checks of a constraint, without
+// duplicates.
+type deferredKeys struct {
+	keys []tree.Datums
+	seen map[string]struct{}
+	// memUsage is the memory accounted for the keys in deferredConstraints.acc.
+	memUsage int64
+}
+
+// reset clears the state at the end of a transaction.
+func (dc *deferredConstraints) reset(ctx context.Context) {
+	dc.allSet, dc.allDeferred = false, false
+	dc.deferred = nil
+	dc.pending = nil
+	dc.acc.Clear(ctx)
+}
+
+// close releases the memory account when the session is closed.
+func (dc *deferredConstraints) close(ctx context.Context) {
+	dc.reset(ctx)
+	dc.acc.Close(ctx)
+}
+
+// isDeferred returns true if the violations of the given check should be
+// deferred until the end of the transaction.
+func (dc *deferredConstraints) isDeferred(c *exec.DeferrableCheck) bool {
+	if deferred, ok := dc.deferred[c.ConstraintName]; ok {
+		return deferred
+	}
+	if dc.allSet {
+		return dc.allDeferred
+	}
+	return c.InitiallyDeferred
+}
+
+// setMode records the mode set by SET CONSTRAINTS for the given constraints,
+// or for all constraints if names is empty.
+func (dc *deferredConstraints) setMode(names tree.NameList, deferred bool) {
+	if len(names) == 0 {
+		dc.allSet, dc.allDeferred = true, deferred
+		dc.deferred = nil
+		return
+	}
+	if dc.deferred == nil {
+		dc.deferred = make(map[string]bool)
+	}
+	for _, name := range names {
+		dc.deferred[string(name)] = deferred
+	}
+}
+
+// add records the key of a violation of the given check. An error is returned
+// if the memory budget of the session does not allow keeping the key.
+func (dc *deferredConstraints) add(
+	ctx context.Context, c *exec.DeferrableCheck, key tree.Datums,
+) error {
+	id := deferredConstraintID{
+		tableID:    descpb.ID(c.TableID),
+		name:       c.ConstraintName,
+		foreignKey: c.ForeignKey,
+	}
+	if dc.pending == nil {
+		dc.pending = make(map[deferredConstraintID]*deferredKeys)
+	}
+	keys, ok := dc.pending[id]
+	if !ok {
+		keys = &deferredKeys{seen: make(map[string]struct{})}
+		dc.pending[id] = keys
+	}
+	s := tree.AsString(&key)
+	if _, ok := keys.seen[s]; ok {
+		return nil
+	}
+	// The key is held both in keys and, as a string, in seen.
+	sz := tree.SizeOfDatums + tree.SizeOfDatum*int64(len(key)) + int64(len(s))
+	for _, d := range key {
+		sz += int64(d.Size())
+	}
+	if err := dc.acc.Grow(ctx, sz); err != nil {
+		return errors.Wrapf(err, "deferring the check of constraint %q", c.ConstraintName)
+	}
+	keys.memUsage += sz
+	keys.seen[s] = struct{}{}
+	keys.keys = append(keys.keys, key)
+	return nil
+}
+
+// validate checks the deferred keys of the given constraints, or of all
+// constraints if names is empty, and returns an error for the first key which
+// still violates its constraint. The validated keys are removed.
+//
+// The queries are run with the given internal executor, in the given
+// transaction, and see the descriptors modified in it through tc.
+func (dc *deferredConstraints) validate(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	tc *descs.Collection,
+	names tree.NameList,
+) error {
+	if len(dc.pending) == 0 {
+		return nil
+	}
+	ie.tcModifier = tc
+	defer func() {
+		ie.tcModifier = nil
+	}()
+
+	// Validate the constraints in a deterministic order.
+	ids := make([]deferredConstraintID, 0, len(dc.pending))
+	for id := range dc.pending {
+		include := len(names) == 0
+		for _, name := range names {
+			include = include || string(name) == id.name
+		}
+		if include {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].tableID != ids[j].tableID {
+			return ids[i].tableID < ids[j].tableID
+		}
+		return ids[i].name < ids[j].name
+	})
+	for _, id := range ids {
+		keys := dc.pending[id]
+		if err := validateDeferredKeys(ctx, ie, txn, tc, id, keys.keys); err != nil {
+			return err
+		}
+		delete(dc.pending, id)
+		dc.acc.Shrink(ctx, keys.memUsage)
+	}
+	return nil
+}
+
+// validateDeferredKeys checks the given keys of a deferred constraint.
+// Constraints which were dropped in the meantime are ignored.
+func validateDeferredKeys(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	tc *descs.Collection,
+	id deferredConstraintID,
+	keys []tree.Datums,
+) error {
+	flags := tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+		Required:       true,
+		IncludeOffline: true,
+		IncludeDropped: true,
+	}}
+	table, err := tc.GetTableVersionByID(ctx, txn, id.tableID, flags)
+	if err != nil {
+		return err
+	}
+	if table.Dropped() {
+		return nil
+	}
+
+	var originCols []string
+	var refTable *tabledesc.Immutable
+	var refCols []string
+	if id.foreignKey {
+		var fk *descpb.ForeignKeyConstraint
+		for i := range table.OutboundFKs {
+			if table.OutboundFKs[i].Name == id.name {
+				fk = &table.OutboundFKs[i]
+				break
+			}
+		}
+		if fk == nil {
+			return nil
+		}
+		if refTable, err = tc.GetTableVersionByID(ctx, txn, fk.ReferencedTableID, flags); err != nil {
+			return err
+		}
+		if originCols, err = table.NamesForColumnIDs(fk.OriginColumnIDs); err != nil {
+			return err
+		}
+		if refCols, err = refTable.NamesForColumnIDs(fk.ReferencedColumnIDs); err != nil {
+			return err
+		}
+	} else {
+		var uc *descpb.UniqueWithoutIndexConstraint
+		for i := range table.UniqueWithoutIndexConstraints {
+			if table.UniqueWithoutIndexConstraints[i].Name == id.name {
+				uc = &table.UniqueWithoutIndexConstraints[i]
+				break
+			}
+		}
+		if uc == nil {
+			return nil
+		}
+		if originCols, err = table.NamesForColumnIDs(uc.ColumnIDs); err != nil {
+			return err
+		}
+	}
+
+	var cols bytes.Buffer
+	for i, col := range originCols {
+		if i > 0 {
+			cols.WriteString(", ")
+		}
+		fmt.Fprintf(&cols, "s.%s", tree.NameString(col))
+	}
+
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > deferredConstraintsBatchSize {
+			batch = batch[:deferredConstraintsBatchSize]
+		}
+		keys = keys[len(batch):]
+
+		// Build a filter of the form:
+		//   (s.a = $1 AND s.b = $2) OR (s.a = $3 AND s.b = $4) OR ...
+		var filter bytes.Buffer
+		args := make([]interface{}, 0, len(batch)*len(originCols))
+		for i, key := range batch {
+			if i > 0 {
+				filter.WriteString(" OR ")
+			}
+			filter.WriteString("(")
+			for j, col := range originCols {
+				if j > 0 {
+					filter.WriteString(" AND ")
+				}
+				args = append(args, key[j])
+				fmt.Fprintf(&filter, "s.%s = $%d", tree.NameString(col), len(args))
+			}
+			filter.WriteString(")")
+		}
+
+		var query string
+		if id.foreignKey {
+			var match bytes.Buffer
+			for i := range originCols {
+				if i > 0 {
+					match.WriteString(" AND ")
+				}
+				fmt.Fprintf(&match, "t.%s = s.%s", tree.NameString(refCols[i]), tree.NameString(originCols[i]))
+			}
+			query = fmt.Sprintf(
+				`SELECT %s FROM [%d AS s] WHERE (%s) AND NOT EXISTS (SELECT 1 FROM [%d AS t] WHERE %s) LIMIT 1`,
+				cols.String(), table.ID, filter.String(), refTable.ID, match.String(),
+			)
+		} else {
+			query = fmt.Sprintf(
+				`SELECT %[1]s FROM [%[2]d AS s] WHERE (%[3]s) GROUP BY %[1]s HAVING count(*) > 1 LIMIT 1`,
+				cols.String(), table.ID, filter.String(),
+			)
+		}
+		row, err := ie.QueryRowEx(
+			ctx, "validate-deferred-constraint", txn,
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			query, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if row != nil {
+			return mkDeferredConstraintErr(table, refTable, id.name, originCols, row)
+		}
+	}
+	return nil
+}
+
+// mkDeferredConstraintErr returns the error for a key which violates a
+// deferred constraint at the end of the transaction. refTable is nil for a
+// unique constraint.
+func mkDeferredConstraintErr(
+	table, refTable *tabledesc.Immutable, name string, cols []string, key tree.Datums,
+) error {
+	var msg, details bytes.Buffer
+	details.WriteString("Key (")
+	for i, col := range cols {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(col)
+	}
+	details.WriteString(")=(")
+	for i, d := range key {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+	details.WriteString(")")
+
+	var code pgcode.Code
+	if refTable != nil {
+		// Generate an error of the form:
+		//   ERROR:  insert or update on table "child" violates foreign key constraint "foo"
+		//   DETAIL: Key (child_p)=(2) is not present in table "parent".
+		code = pgcode.ForeignKeyViolation
+		msg.WriteString("insert or update on table ")
+		lexbase.EncodeEscapedSQLIdent(&msg, table.Name)
+		msg.WriteString(" violates foreign key constraint ")
+		lexbase.EncodeEscapedSQLIdent(&msg, name)
+		details.WriteString(" is not present in table ")
+		lexbase.EncodeEscapedSQLIdent(&details, refTable.Name)
+		details.WriteString(".")
+	} else {
+		// Generate an error of the form:
+		//   ERROR:  duplicate key value violates unique constraint "foo"
+		//   DETAIL: Key (k)=(2) already exists.
+		code = pgcode.UniqueViolation
+		msg.WriteString("duplicate key value violates unique constraint ")
+		lexbase.EncodeEscapedSQLIdent(&msg, name)
+		details.WriteString(" already exists.")
+	}
+	return errors.WithDetail(
+		pgerror.WithConstraintName(pgerror.Newf(code, "%s", msg.String()), name),
+		details.String(),
+	)
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeferredConstraintsMemoryAccounting(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	m := mon.NewMonitorWithLimit(
+		"test", mon.MemoryResource, 10<<10 /* limit */, nil /* curCount */, nil, /* maxHist */
+		-1 /* increment */, 1000 /* noteworthy */, st,
+	)
+	m.Start(ctx, nil /* pool */, mon.MakeStandaloneBudget(10<<10))
+	defer m.Stop(ctx)
+
+	dc := deferredConstraints{acc: m.MakeBoundAccount()}
+	defer dc.close(ctx)
+	check := &exec.DeferrableCheck{TableID: 52, ConstraintName: "fk", ForeignKey: true}
+
+	// A key is accounted for once, however many times it is recorded.
+	require.NoError(t, dc.add(ctx, check, tree.Datums{tree.NewDInt(1)}))
+	used := dc.acc.Used()
+	require.NotZero(t, used)
+	require.NoError(t, dc.add(ctx, check, tree.Datums{tree.NewDInt(1)}))
+	require.Equal(t, used, dc.acc.Used())
+
+	// Recording keys fails once the budget is exhausted.
+	var err error
+	for i := 2; err == nil; i++ {
+		err = dc.add(ctx, check, tree.Datums{tree.NewDString(fmt.Sprintf("%0100d", i))})
+	}
+	require.Equal(t, pgcode.OutOfMemory, pgerror.GetPGCode(err))
+
+	// The memory is released at the end of the transaction.
+	dc.reset(ctx)
+	require.Zero(t, dc.acc.Used())
+}
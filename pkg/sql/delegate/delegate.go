@@ -82,6 +82,9 @@ func TryDelegate(
 	case *tree.ShowRangeForRow:
 		return d.delegateShowRangeForRow(t)
 
+	case *tree.ShowRangeLocalities:
+		return d.delegateShowRangeLocalities(t)
+
 	case *tree.ShowSurvivalGoal:
 		return d.delegateShowSurvivalGoal(t)
 
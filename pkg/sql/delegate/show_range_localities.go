@@ -0,0 +1,40 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+// delegateShowRangeLocalities implements SHOW RANGE <rangeid> LOCALITIES,
+// which breaks the request rate of a range at its leaseholder down by the
+// locality the requests were sent from. These are the request counts that
+// lease placement is based on.
+func (d *delegator) delegateShowRangeLocalities(
+	n *tree.ShowRangeLocalities,
+) (tree.Statement, error) {
+	sqltelemetry.IncrementShowCounter(sqltelemetry.RangeLocalities)
+
+	const query = `
+SELECT
+	l.key AS locality,
+	l.value::FLOAT8 AS queries_per_second,
+	l.value::FLOAT8 / NULLIF(sum(l.value::FLOAT8) OVER (), 0) AS fraction
+FROM crdb_internal.ranges_no_leases AS r,
+	jsonb_each_text(crdb_internal.range_locality_stats(r.start_key)) AS l
+WHERE r.range_id = %d
+ORDER BY queries_per_second DESC, locality
+`
+	return parse(fmt.Sprintf(query, n.RangeID))
+}
@@ -11,6 +11,10 @@
 package delegate
 
 import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lex"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 )
@@ -19,15 +23,15 @@ import (
 // Privileges: SELECT on system.users.
 func (d *delegator) delegateShowRoles() (tree.Statement, error) {
 	sqltelemetry.IncrementShowCounter(sqltelemetry.Roles)
-	return parse(`
+	return parse(fmt.Sprintf(`
 SELECT
 	u.username,
 	IFNULL(string_agg(o.option || COALESCE('=' || o.value, ''), ', ' ORDER BY o.option), '') AS options,
 	ARRAY (SELECT role FROM system.role_members AS rm WHERE rm.member = u.username ORDER BY 1) AS member_of
 FROM
-	system.users AS u LEFT JOIN system.role_options AS o ON u.username = o.username
+	system.users AS u LEFT JOIN system.role_options AS o ON u.username = o.username AND o.option != %s
 GROUP BY
 	u.username
 ORDER BY 1;
-`)
+`, lex.EscapeSQLString(roleoption.LDAPGroupRoles)))
 }
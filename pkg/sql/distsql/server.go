@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -495,15 +496,43 @@ func (ds *ServerImpl) SetupLocalSyncFlow(
 	output execinfra.RowReceiver,
 	localState LocalState,
 ) (context.Context, flowinfra.Flow, error) {
+	admittedWorkDone, err := admitFlowSetup(ctx, ds.SQLRootStartAdmissionQ)
+	if err != nil {
+		return nil, nil, err
+	}
 	ctx, f, err := ds.setupFlow(
 		ctx, tracing.SpanFromContext(ctx), parentMonitor, req, output, localState,
 	)
+	admittedWorkDone()
 	if err != nil {
 		return nil, nil, err
 	}
 	return ctx, f, err
 }
 
+// admitFlowSetup requests admission for the setup of a flow from the given
+// admission control queue, which may be nil. The returned function must be
+// called once the flow is set up.
+func admitFlowSetup(ctx context.Context, q *admission.WorkQueue) (func(), error) {
+	if q == nil {
+		return func() {}, nil
+	}
+	// The setup of flows is only subject to admission control on KV nodes,
+	// where all SQL work is done on behalf of the system tenant.
+	enabled, err := q.Admit(ctx, admission.WorkInfo{
+		TenantID:   roachpb.SystemTenantID,
+		Priority:   admission.NormalPri,
+		CreateTime: timeutil.Now().UnixNano(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return func() {}, nil
+	}
+	return func() { q.AdmittedWorkDone(roachpb.SystemTenantID) }, nil
+}
+
 // RunSyncFlow is part of the DistSQLServer interface.
 func (ds *ServerImpl) RunSyncFlow(stream execinfrapb.DistSQL_RunSyncFlowServer) error {
 	// Set up the outgoing mailbox for the stream.
@@ -547,6 +576,14 @@ func (ds *ServerImpl) SetupFlow(
 	log.VEventf(ctx, 1, "received SetupFlow request from n%v for flow %v", req.Flow.Gateway, req.Flow.FlowID)
 	parentSpan := tracing.SpanFromContext(ctx)
 
+	// Admission is requested with the RPC's context, so that the request
+	// stops waiting if the RPC is canceled.
+	admittedWorkDone, err := admitFlowSetup(ctx, ds.SQLLeafStartAdmissionQ)
+	if err != nil {
+		return &execinfrapb.SimpleResponse{Error: execinfrapb.NewError(ctx, err)}, nil
+	}
+	defer admittedWorkDone()
+
 	// Note: the passed context will be canceled when this RPC completes, so we
 	// can't associate it with the flow.
 	ctx = ds.AnnotateCtx(context.Background())
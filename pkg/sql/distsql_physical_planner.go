@@ -3548,7 +3548,8 @@ func (dsp *DistSQLPlanner) createPlanForWindow(
 }
 
 // createPlanForExport creates a physical plan for EXPORT.
-// We add a new stage of CSVWriter processors to the input plan.
+// We add a new stage of CSVWriter or ParquetWriter processors to the input
+// plan.
 func (dsp *DistSQLPlanner) createPlanForExport(
 	planCtx *PlanningCtx, n *exportNode,
 ) (*PhysicalPlan, error) {
@@ -3556,14 +3557,26 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 	if err != nil {
 		return nil, err
 	}
-	core := execinfrapb.ProcessorCoreUnion{CSVWriter: &execinfrapb.CSVWriterSpec{
-		Destination:      n.destination,
-		NamePattern:      n.fileNamePattern,
-		Options:          n.csvOpts,
-		ChunkRows:        int64(n.chunkRows),
-		CompressionCodec: n.fileCompression,
-		UserProto:        planCtx.planner.User().EncodeProto(),
-	}}
+	var core execinfrapb.ProcessorCoreUnion
+	if n.fileFormat == exportFormatParquet {
+		core.ParquetWriter = &execinfrapb.ParquetWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			ChunkRows:        int64(n.chunkRows),
+			CompressionCodec: n.fileCompression,
+			UserProto:        planCtx.planner.User().EncodeProto(),
+			ColumnNames:      n.colNames,
+		}
+	} else {
+		core.CSVWriter = &execinfrapb.CSVWriterSpec{
+			Destination:      n.destination,
+			NamePattern:      n.fileNamePattern,
+			Options:          n.csvOpts,
+			ChunkRows:        int64(n.chunkRows),
+			CompressionCodec: n.fileCompression,
+			UserProto:        planCtx.planner.User().EncodeProto(),
+		}
+	}
 
 	resTypes := make([]*types.T, len(colinfo.ExportColumns))
 	for i := range colinfo.ExportColumns {
@@ -3573,7 +3586,7 @@ func (dsp *DistSQLPlanner) createPlanForExport(
 		core, execinfrapb.PostProcessSpec{}, resTypes, execinfrapb.Ordering{},
 	)
 
-	// The CSVWriter produces the same columns as the EXPORT statement.
+	// The writers produce the same columns as the EXPORT statement.
 	plan.PlanToStreamColMap = identityMap(plan.PlanToStreamColMap, len(colinfo.ExportColumns))
 	return plan, nil
 }
@@ -680,7 +680,7 @@ func (r *DistSQLReceiver) Push(
 		return r.status
 	}
 
-	if r.stmtType != tree.Rows {
+	if r.stmtType != tree.Rows && r.stmtType != tree.CopyOut {
 		// We only need the row count. planNodeToRowSource is set up to handle
 		// ensuring that the last stage in the pipeline will return a single-column
 		// row with the row count in it, so just grab that and exit.
@@ -959,11 +959,41 @@ func (dsp *DistSQLPlanner) PlanAndRun(
 	return dsp.Run(planCtx, txn, physPlan, recv, evalCtx, nil /* finishedSetupFn */)
 }
 
-// PlanAndRunCascadesAndChecks runs any cascade and check queries.
+// PlanAndRunBeforeTriggers runs the BEFORE triggers of the main query. It must
+// be called after the subqueries and before the main query are run.
 //
-// Because cascades can themselves generate more cascades or check queries, this
-// method can append to plan.cascades and plan.checkPlans (and all these plans
-// must be closed later).
+// The main query reads its input from the buffer of the triggers, so the
+// buffer is filled first. Any cascades or check queries generated by the
+// triggers are added to plan.cascades and plan.checkPlans, and are run by
+// PlanAndRunCascadesAndChecks.
+//
+// Returns false if an error was encountered and sets that error in the provided
+// receiver.
+func (dsp *DistSQLPlanner) PlanAndRunBeforeTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	recv *DistSQLReceiver,
+) bool {
+	prevSteppingMode := planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+	defer func() { _ = planner.Txn().ConfigureStepping(ctx, prevSteppingMode) }()
+
+	if err := dsp.planAndRunBeforeTriggers(
+		ctx, planner, evalCtxFactory, plan, plan.cascades, recv,
+	); err != nil {
+		recv.SetError(err)
+		return false
+	}
+	return true
+}
+
+// PlanAndRunCascadesAndChecks runs any cascade, AFTER trigger and check
+// queries.
+//
+// Because cascades and triggers can themselves generate more cascades,
+// triggers or check queries, this method can append to plan.cascades and
+// plan.checkPlans (and all these plans must be closed later).
 //
 // Returns false if an error was encountered and sets that error in the provided
 // receiver.
@@ -981,8 +1011,25 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	prevSteppingMode := planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
 	defer func() { _ = planner.Txn().ConfigureStepping(ctx, prevSteppingMode) }()
 
+	// numFKCascades is the number of queued cascades (excluding triggers) in
+	// plan.cascades[:numCounted].
+	var numFKCascades, numCounted int
+
 	// We treat plan.cascades as a queue.
 	for i := 0; i < len(plan.cascades); i++ {
+		if plan.cascades[i].Before {
+			// BEFORE triggers are run before the query that queued them.
+			continue
+		}
+		if plan.cascades[i].Trigger {
+			c := plan.cascades[i]
+			if err := dsp.planAndRunTrigger(ctx, planner, evalCtxFactory, plan, &c, recv); err != nil {
+				recv.SetError(err)
+				return false
+			}
+			continue
+		}
+
 		// The original bufferNode is stored in c.Buffer; we can refer to it
 		// directly.
 		// TODO(radu): this requires keeping all previous plans "alive" until the
@@ -1035,20 +1082,25 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			return false
 		}
 
-		// Queue any new cascades.
-		if len(cp.cascades) > 0 {
-			plan.cascades = append(plan.cascades, cp.cascades...)
-		}
-
-		// Collect any new checks.
-		if len(cp.checkPlans) > 0 {
-			plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
+		// Queue any new cascades, and run the BEFORE triggers of the cascading
+		// query.
+		if err := dsp.queueCascades(
+			ctx, planner, evalCtxFactory, plan, cp, plan.cascades[i].triggerDepth, recv,
+		); err != nil {
+			recv.SetError(err)
+			return false
 		}
 
 		// In cyclical reference situations, the number of cascading operations can
 		// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
 		// safeguard in case we have a bug that results in an infinite cascade loop.
-		if limit := evalCtx.SessionData.OptimizerFKCascadesLimit; len(plan.cascades) > limit {
+		// Triggers are not counted; their nesting is limited separately.
+		for ; numCounted < len(plan.cascades); numCounted++ {
+			if !plan.cascades[numCounted].Trigger {
+				numFKCascades++
+			}
+		}
+		if limit := evalCtx.SessionData.OptimizerFKCascadesLimit; numFKCascades > limit {
 			telemetry.Inc(sqltelemetry.CascadesLimitReached)
 			err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
 			recv.SetError(err)
@@ -1099,6 +1151,165 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	return true
 }
 
+// queueCascades adds the cascades of a cascading query or trigger action to
+// plan.cascades, and its checks to plan.checkPlans. It then runs the BEFORE
+// triggers of the query, which must happen before the query runs.
+// triggerDepth is the number of triggers that are being run by the query.
+func (dsp *DistSQLPlanner) queueCascades(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	cp *planComponents,
+	triggerDepth int,
+	recv *DistSQLReceiver,
+) error {
+	if len(cp.checkPlans) > 0 {
+		plan.checkPlans = append(plan.checkPlans, cp.checkPlans...)
+	}
+	if len(cp.cascades) == 0 {
+		return nil
+	}
+	start := len(plan.cascades)
+	plan.cascades = append(plan.cascades, cp.cascades...)
+	queued := plan.cascades[start:]
+	for i := range queued {
+		queued[i].triggerDepth = triggerDepth
+	}
+	return dsp.planAndRunBeforeTriggers(ctx, planner, evalCtxFactory, plan, queued, recv)
+}
+
+// planAndRunBeforeTriggers runs the BEFORE triggers among the given cascades,
+// after filling the buffer they read from. The query that queued the cascades
+// reads its input from the same buffer.
+func (dsp *DistSQLPlanner) planAndRunBeforeTriggers(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	cascades []cascadeMetadata,
+	recv *DistSQLReceiver,
+) error {
+	for i := range cascades {
+		c := &cascades[i]
+		if !c.Before {
+			continue
+		}
+		buf := c.Buffer.(*bufferNode)
+		if buf.bufferedRows == nil {
+			log.VEventf(ctx, 1, "buffering input for trigger %s", c.FKName)
+			if err := dsp.planAndRunPostquery(
+				ctx, planMaybePhysical{planNode: buf}, planner, evalCtxFactory(), recv,
+			); err != nil {
+				return err
+			}
+			plan.triggerBuffers = append(plan.triggerBuffers, buf)
+		}
+		if err := dsp.planAndRunTrigger(ctx, planner, evalCtxFactory, plan, c, recv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planAndRunTrigger runs the action of a trigger: once for a statement-level
+// trigger, or once for each modified row for a row-level trigger.
+func (dsp *DistSQLPlanner) planAndRunTrigger(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	c *cascadeMetadata,
+	recv *DistSQLReceiver,
+) error {
+	buf := c.Buffer.(*bufferNode)
+	numRows := buf.bufferedRows.Len()
+	if c.ForEachRow && numRows == 0 {
+		// No rows were actually modified.
+		return nil
+	}
+
+	// Triggers can modify tables with more triggers, possibly recursively. To
+	// avoid infinite recursion, we enforce a limit on the nesting depth.
+	depth := c.triggerDepth + 1
+	if limit := planner.SessionData().TriggerDepthLimit; depth > limit {
+		telemetry.Inc(sqltelemetry.TriggerDepthLimitReached)
+		return pgerror.Newf(pgcode.TriggeredActionException, "trigger depth limit (%d) reached", limit)
+	}
+
+	if !c.ForEachRow {
+		log.VEventf(ctx, 1, "executing trigger %s", c.FKName)
+		return dsp.planAndRunTriggerAction(
+			ctx, planner, evalCtxFactory, plan, c, buf, numRows, depth, recv,
+		)
+	}
+
+	log.VEventf(ctx, 1, "executing trigger %s for %d rows", c.FKName, numRows)
+	cols := planColumns(buf)
+	for i := 0; i < numRows; i++ {
+		// The action reads the modified row from a buffer of its own.
+		rowBuf := &bufferNode{
+			plan: newZeroNode(cols),
+			bufferedRows: rowcontainer.NewRowContainer(
+				planner.EvalContext().Mon.MakeBoundAccount(), colinfo.ColTypeInfoFromResCols(cols),
+			),
+			label: buf.label,
+		}
+		plan.triggerBuffers = append(plan.triggerBuffers, rowBuf)
+		if _, err := rowBuf.bufferedRows.AddRow(ctx, buf.bufferedRows.At(i)); err != nil {
+			return err
+		}
+		if err := dsp.planAndRunTriggerAction(
+			ctx, planner, evalCtxFactory, plan, c, rowBuf, 1 /* numBufferedRows */, depth, recv,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// planAndRunTriggerAction plans and runs the action of a trigger for the rows
+// in the given buffer.
+func (dsp *DistSQLPlanner) planAndRunTriggerAction(
+	ctx context.Context,
+	planner *planner,
+	evalCtxFactory func() *extendedEvalContext,
+	plan *planComponents,
+	c *cascadeMetadata,
+	buf *bufferNode,
+	numBufferedRows int,
+	depth int,
+	recv *DistSQLReceiver,
+) error {
+	// We place a sequence point before every action, so that it can observe
+	// the writes by the previous steps.
+	_ = planner.Txn().ConfigureStepping(ctx, kv.SteppingEnabled)
+	if err := planner.Txn().Step(ctx); err != nil {
+		return err
+	}
+
+	evalCtx := evalCtxFactory()
+	execFactory := newExecFactory(planner)
+	actionPlan, err := c.PlanFn(
+		ctx, &planner.semaCtx, &evalCtx.EvalContext, execFactory,
+		buf, numBufferedRows, false, /* allowAutoCommit */
+	)
+	if err != nil {
+		return err
+	}
+	cp := actionPlan.(*planComponents)
+	plan.triggerPlans = append(plan.triggerPlans, cp.main)
+	if len(cp.subqueryPlans) > 0 {
+		return unimplemented.NewWithIssuef(28296,
+			"trigger %s: uncorrelated subqueries are not supported in trigger actions", c.FKName)
+	}
+
+	if err := dsp.queueCascades(ctx, planner, evalCtxFactory, plan, cp, depth, recv); err != nil {
+		return err
+	}
+	return dsp.planAndRunPostquery(ctx, cp.main, planner, evalCtx, recv)
+}
+
 // planAndRunPostquery runs a cascade or check query.
 func (dsp *DistSQLPlanner) planAndRunPostquery(
 	ctx context.Context,
@@ -1143,6 +1354,9 @@ func (dsp *DistSQLPlanner) planAndRunPostquery(
 	// TODO(yuzefovich): at the moment, errOnlyResultWriter is sufficient here,
 	// but it may not be the case when we support cascades through the optimizer.
 	postqueryRecv.resultWriter = &errOnlyResultWriter{}
+	// Trigger actions and the inputs buffered for BEFORE triggers can produce
+	// rows; these are not returned to the client.
+	postqueryRecv.discardRows = true
 	dsp.Run(postqueryPlanCtx, planner.txn, postqueryPhysPlan, postqueryRecv, evalCtx, nil /* finishedSetupFn */)()
 	if postqueryRecv.commErr != nil {
 		return postqueryRecv.commErr
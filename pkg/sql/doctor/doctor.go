@@ -160,6 +160,11 @@ func ExamineDescriptors(
 		case catalog.SchemaDescriptor:
 			// parent schema id is always 0.
 			parentSchemaExists = true
+		case catalog.FunctionDescriptor:
+			if err := d.Validate(ctx, descGetter); err != nil {
+				problemsFound = true
+				fmt.Fprint(stdout, reportMsg(desc, "%s", err))
+			}
 		}
 		if desc.GetParentID() != descpb.InvalidID && !parentExists {
 			problemsFound = true
@@ -324,6 +329,8 @@ func reportMsg(desc catalog.Descriptor, format string, args ...interface{}) stri
 		header = "  Schema"
 	case catalog.DatabaseDescriptor:
 		header = "Database"
+	case catalog.FunctionDescriptor:
+		header = "Function"
 	}
 	return fmt.Sprintf("%s %3d: ParentID %3d, ParentSchemaID %2d, Name '%s': ",
 		header, desc.GetID(), desc.GetParentID(), desc.GetParentSchemaID(), desc.GetName()) +
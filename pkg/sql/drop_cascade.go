@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -34,6 +35,7 @@ type dropCascadeState struct {
 	td                      []toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []*funcdesc.Mutable
 
 	droppedNames []string
 }
@@ -139,7 +141,7 @@ func (d *dropCascadeState) resolveCollectedObjects(
 				ctx,
 				tree.ObjectLookupFlags{
 					CommonLookupFlags: tree.CommonLookupFlags{
-						Required:       true,
+						Required:       false,
 						RequireMutable: true,
 						IncludeOffline: true,
 					},
@@ -152,8 +154,43 @@ func (d *dropCascadeState) resolveCollectedObjects(
 			if err != nil {
 				return err
 			}
-			// If we couldn't find the object at all, then continue.
 			if !found {
+				// If we couldn't resolve objName as a type either, try a function.
+				found, desc, err = p.LookupObject(
+					ctx,
+					tree.ObjectLookupFlags{
+						CommonLookupFlags: tree.CommonLookupFlags{
+							Required:       true,
+							RequireMutable: true,
+							IncludeOffline: true,
+						},
+						DesiredObjectKind: tree.FunctionObject,
+					},
+					objName.Catalog(),
+					objName.Schema(),
+					objName.Object(),
+				)
+				if err != nil {
+					return err
+				}
+				// If we couldn't find the object at all, then continue.
+				if !found {
+					continue
+				}
+				fnDesc, ok := desc.(*funcdesc.Mutable)
+				if !ok {
+					return errors.AssertionFailedf(
+						"descriptor for %q is not Mutable",
+						objName.Object(),
+					)
+				}
+				if err := p.canModifyFunction(ctx, fnDesc); err != nil {
+					return err
+				}
+				if err := p.canRemoveViewsCallingFunction(ctx, fnDesc, tree.DropCascade); err != nil {
+					return err
+				}
+				d.functionsToDelete = append(d.functionsToDelete, fnDesc)
 				continue
 			}
 			typDesc, ok := desc.(*typedesc.Mutable)
@@ -210,6 +247,14 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		}
 	}
 
+	// Now delete all of the functions. Functions that depended on a dropped
+	// relation were already dropped along with it.
+	for _, fn := range d.functionsToDelete {
+		if err := p.dropFunctionImpl(ctx, fn, "" /* jobDesc */); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
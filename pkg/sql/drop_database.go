@@ -292,6 +292,12 @@ func (p *planner) accumulateCascadingViews(
 	ctx context.Context, dependentObjects map[descpb.ID]*tabledesc.Mutable, desc *tabledesc.Mutable,
 ) error {
 	for _, ref := range desc.DependedOnBy {
+		// Functions that depend on the table are dropped along with it.
+		if fnDesc, err := p.getDependentFunction(ctx, ref.ID); err != nil {
+			return err
+		} else if fnDesc != nil {
+			continue
+		}
 		dependentDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, ref.ID, p.txn)
 		if err != nil {
 			return err
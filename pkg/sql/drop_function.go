@@ -0,0 +1,124 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+type dropFunctionNode struct {
+	n  *tree.DropFunction
+	fd []*funcdesc.Mutable
+}
+
+// Use to satisfy the linter.
+var _ planNode = &dropFunctionNode{n: nil}
+
+// DropFunction drops user-defined functions.
+// Privileges: ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropFunctionNode{n: n}
+	seen := make(map[descpb.ID]struct{}, len(n.Functions))
+	for i := range n.Functions {
+		fn := &n.Functions[i]
+		flags := tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+			Required:       !n.IfExists,
+			RequireMutable: true,
+		}}
+		_, desc, err := resolver.ResolveFunction(ctx, p, fn.FuncName, flags)
+		if err != nil {
+			return nil, err
+		}
+		if desc == nil {
+			// IfExists specified and the function did not exist.
+			continue
+		}
+		fnDesc := desc.(*funcdesc.Mutable)
+		if fn.ParamTypes != nil && !functionParamTypesMatch(fnDesc, fn.ParamTypes) {
+			if n.IfExists {
+				continue
+			}
+			return nil, sqlerrors.NewUndefinedFunctionError(tree.AsString(fn))
+		}
+		if _, ok := seen[fnDesc.ID]; ok {
+			continue
+		}
+		seen[fnDesc.ID] = struct{}{}
+		if err := p.canModifyFunction(ctx, fnDesc); err != nil {
+			return nil, err
+		}
+		node.fd = append(node.fd, fnDesc)
+	}
+	for _, fnDesc := range node.fd {
+		if err := p.canRemoveViewsCallingFunction(ctx, fnDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
+	}
+	if len(node.fd) == 0 {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return node, nil
+}
+
+// functionParamTypesMatch returns whether the given parameter types match the
+// parameters of the function.
+func functionParamTypesMatch(
+	fnDesc *funcdesc.Mutable, paramTypes []tree.ResolvableTypeReference,
+) bool {
+	if len(paramTypes) != len(fnDesc.Params) {
+		return false
+	}
+	for i := range paramTypes {
+		typ, ok := tree.GetStaticallyKnownType(paramTypes[i])
+		if !ok || !typ.Identical(fnDesc.Params[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropFunctionNode) ReadingOwnWrites() {}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+
+	for _, fnDesc := range n.fd {
+		if err := params.p.dropFunctionImpl(
+			params.ctx, fnDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dropFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropFunctionNode) Close(ctx context.Context)           {}
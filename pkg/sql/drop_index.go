@@ -428,6 +428,18 @@ func (p *planner) dropIndexByName(
 			if err != nil {
 				return err
 			}
+			fnDesc, err := p.getDependentFunction(ctx, tableRef.ID)
+			if err != nil {
+				return err
+			}
+			if fnDesc != nil {
+				fnJobDesc := fmt.Sprintf("removing function %q dependent on index %q which is being dropped",
+					fnDesc.Name, idx.Name)
+				if err := p.removeDependentFunction(ctx, tableDesc, fnDesc, fnJobDesc); err != nil {
+					return err
+				}
+				continue
+			}
 			viewDesc, err := p.getViewDescForCascade(
 				ctx, "index", idx.Name, tableDesc.ParentID, tableRef.ID, behavior,
 			)
@@ -326,11 +326,21 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
-	// Drop all views that depend on this table, assuming that we wouldn't have
-	// made it to this point if `cascade` wasn't enabled.
+	// Drop all views and functions that depend on this table, assuming that we
+	// wouldn't have made it to this point if `cascade` wasn't enabled.
 	// Copy out the set of dependencies as it may be overwritten in the loop.
 	dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), tableDesc.DependedOnBy...)
 	for _, ref := range dependedOnBy {
+		fnDesc, err := p.getDependentFunction(ctx, ref.ID)
+		if err != nil {
+			return droppedViews, err
+		}
+		if fnDesc != nil {
+			if err := p.dropFunctionImpl(ctx, fnDesc, "dropping dependent function"); err != nil {
+				return droppedViews, err
+			}
+			continue
+		}
 		viewDesc, err := p.getViewDescForCascade(
 			ctx, tableDesc.TypeName(), tableDesc.Name, tableDesc.ParentID, ref.ID, tree.DropCascade,
 		)
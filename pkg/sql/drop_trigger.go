@@ -0,0 +1,105 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *tabledesc.Mutable
+	// idx is the position of the dropped trigger in tableDesc.Triggers.
+	idx int
+}
+
+// Use to satisfy the linter.
+var _ planNode = &dropTriggerNode{n: nil}
+
+// DropTrigger drops a trigger.
+// Privileges: CREATE on table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		// IfExists specified and the table did not exist.
+		return newZeroNode(nil /* columns */), nil
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].Name == string(n.Name) {
+			// Triggers cannot be referenced by other objects, so there is
+			// nothing to check for the drop behavior.
+			return &dropTriggerNode{n: n, tableDesc: tableDesc, idx: i}, nil
+		}
+	}
+	if n.IfExists {
+		return newZeroNode(nil /* columns */), nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"trigger %q for table %q does not exist", n.Name, tableDesc.Name)
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropTriggerNode) ReadingOwnWrites() {}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("trigger"))
+
+	triggers := n.tableDesc.Triggers
+	n.tableDesc.Triggers = append(triggers[:n.idx], triggers[n.idx+1:]...)
+
+	if err := params.p.writeSchemaChange(
+		params.ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Log Drop Trigger event. This is an auditable log event and is
+	// recorded in the same transaction as the table descriptor update.
+	return params.p.logEvent(params.ctx,
+		n.tableDesc.ID,
+		&eventpb.DropTrigger{
+			TableName:   n.n.Table.FQString(),
+			TriggerName: string(n.n.Name),
+		})
+}
+
+func (n *dropTriggerNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropTriggerNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropTriggerNode) Close(ctx context.Context)           {}
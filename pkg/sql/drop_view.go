@@ -150,6 +150,13 @@ func (p *planner) canRemoveDependentViewGeneric(
 	ref descpb.TableDescriptor_Reference,
 	behavior tree.DropBehavior,
 ) error {
+	fnDesc, err := p.getDependentFunction(ctx, ref.ID)
+	if err != nil {
+		return err
+	}
+	if fnDesc != nil {
+		return p.canRemoveDependentFunction(ctx, typeName, objName, fnDesc, behavior)
+	}
 	viewDesc, err := p.getViewDescForCascade(ctx, typeName, objName, parentID, ref.ID, behavior)
 	if err != nil {
 		return err
@@ -215,9 +222,25 @@ func (p *planner) dropViewImpl(
 	}
 	viewDesc.DependsOn = nil
 
+	// Remove back-references from the functions this view calls.
+	if err := p.removeViewFunctionBackReferences(ctx, viewDesc, nil /* keep */); err != nil {
+		return cascadeDroppedViews, err
+	}
+	viewDesc.DependsOnFunctions = nil
+
 	if behavior == tree.DropCascade {
 		dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), viewDesc.DependedOnBy...)
 		for _, ref := range dependedOnBy {
+			fnDesc, err := p.getDependentFunction(ctx, ref.ID)
+			if err != nil {
+				return cascadeDroppedViews, err
+			}
+			if fnDesc != nil {
+				if err := p.dropFunctionImpl(ctx, fnDesc, "dropping dependent function"); err != nil {
+					return cascadeDroppedViews, err
+				}
+				continue
+			}
 			dependentDesc, err := p.getViewDescForCascade(
 				ctx, viewDesc.TypeName(), viewDesc.Name, viewDesc.ParentID, ref.ID, behavior,
 			)
@@ -26,6 +26,11 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// deferrable is set if the wrapped node is the check of a deferrable
+	// constraint, whose violations can be deferred until the end of the
+	// transaction.
+	deferrable *exec.DeferrableCheck
+
 	nexted bool
 }
 
@@ -40,13 +45,32 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	n.nexted = true
 
 	ok, err := n.plan.Next(params)
-	if err != nil {
+	if err != nil || !ok {
 		return false, err
 	}
-	if ok {
+	dc := params.p.deferredConstraints
+	if n.deferrable == nil || dc == nil || params.EvalContext().TxnImplicit ||
+		!dc.isDeferred(n.deferrable) {
 		return false, n.mkErr(n.plan.Values())
 	}
-	return false, nil
+
+	// The constraint is deferred: record the keys of all the violations, to
+	// check them again at the end of the transaction.
+	for ; ok; ok, err = n.plan.Next(params) {
+		row := n.plan.Values()
+		key := make(tree.Datums, len(n.deferrable.KeyCols))
+		for i, ord := range n.deferrable.KeyCols {
+			if key[i] = row[ord]; key[i] == tree.DNull {
+				// A key with NULLs can only violate a MATCH FULL foreign key, and
+				// cannot become valid later in the transaction.
+				return false, n.mkErr(row)
+			}
+		}
+		if err := dc.add(params.ctx, n.deferrable, key); err != nil {
+			return false, err
+		}
+	}
+	return false, err
 }
 
 func (n *errorIfRowsNode) Values() tree.Datums {
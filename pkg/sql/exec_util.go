@@ -57,6 +57,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -238,6 +239,13 @@ var optDrivenFKCascadesClusterLimit = settings.RegisterIntSetting(
 	settings.NonNegativeInt,
 )
 
+var triggerDepthClusterLimit = settings.RegisterIntSetting(
+	"sql.defaults.trigger_depth_limit",
+	"default value for trigger_depth_limit session setting; limits the nesting depth of triggers that run as part of a single query",
+	32,
+	settings.NonNegativeInt,
+)
+
 var preferLookupJoinsForFKs = settings.RegisterBoolSetting(
 	"sql.defaults.prefer_lookup_joins_for_fks.enabled",
 	"default value for prefer_lookup_joins_for_fks session setting; causes foreign key operations to use lookup joins when possible",
@@ -1187,6 +1195,11 @@ func (p *planner) isAsOf(ctx context.Context, stmt tree.Statement) (*hlc.Timesta
 		asOf = s.AsOf
 	case *tree.Export:
 		return p.isAsOf(ctx, s.Query)
+	case *tree.CopyTo:
+		if s.Statement == nil {
+			return nil, nil
+		}
+		return p.isAsOf(ctx, s.Statement)
 	case *tree.CreateStats:
 		if s.Options.AsOf.Expr == nil {
 			return nil, nil
@@ -1286,6 +1299,9 @@ type SessionArgs struct {
 	// client.
 	RemoteAddr            net.Addr
 	ConnResultsBufferSize int64
+	// QueryCancelKey is the pgwire cancel key sent to the client in the
+	// BackendKeyData message. It is zero for internal sessions.
+	QueryCancelKey pgwirecancel.BackendKeyData
 }
 
 // SessionRegistry stores a set of all sessions on this node.
@@ -1293,29 +1309,46 @@ type SessionArgs struct {
 type SessionRegistry struct {
 	syncutil.Mutex
 	sessions map[ClusterWideID]registrySession
+	// sessionsByCancelKey indexes the sessions that were given a pgwire
+	// cancel key. Internal sessions don't have one.
+	sessionsByCancelKey map[pgwirecancel.BackendKeyData]registrySession
 }
 
 // NewSessionRegistry creates a new SessionRegistry with an empty set
 // of sessions.
 func NewSessionRegistry() *SessionRegistry {
-	return &SessionRegistry{sessions: make(map[ClusterWideID]registrySession)}
+	return &SessionRegistry{
+		sessions:            make(map[ClusterWideID]registrySession),
+		sessionsByCancelKey: make(map[pgwirecancel.BackendKeyData]registrySession),
+	}
 }
 
-func (r *SessionRegistry) register(id ClusterWideID, s registrySession) {
+func (r *SessionRegistry) register(
+	id ClusterWideID, queryCancelKey pgwirecancel.BackendKeyData, s registrySession,
+) {
 	r.Lock()
 	r.sessions[id] = s
+	if queryCancelKey != 0 {
+		r.sessionsByCancelKey[queryCancelKey] = s
+	}
 	r.Unlock()
 }
 
-func (r *SessionRegistry) deregister(id ClusterWideID) {
+func (r *SessionRegistry) deregister(id ClusterWideID, queryCancelKey pgwirecancel.BackendKeyData) {
 	r.Lock()
 	delete(r.sessions, id)
+	if queryCancelKey != 0 {
+		delete(r.sessionsByCancelKey, queryCancelKey)
+	}
 	r.Unlock()
 }
 
 type registrySession interface {
 	user() security.SQLUsername
 	cancelQuery(queryID ClusterWideID) bool
+	// cancelCurrentQueries cancels all the queries currently running in the
+	// session, and returns whether there were any.
+	cancelCurrentQueries() bool
 	cancelSession()
 	// serialize serializes a Session into a serverpb.Session
 	// that can be served over RPC.
@@ -1342,6 +1375,20 @@ func (r *SessionRegistry) CancelQuery(queryIDStr string) (bool, error) {
 	return false, fmt.Errorf("query ID %s not found", queryID)
 }
 
+// CancelQueryByKey looks up the session with the given pgwire cancel key in
+// the session registry and cancels its current queries. The cancel key is
+// itself the proof of authorization, so no other permission check is needed.
+func (r *SessionRegistry) CancelQueryByKey(
+	queryCancelKey pgwirecancel.BackendKeyData,
+) (canceled bool, err error) {
+	r.Lock()
+	defer r.Unlock()
+	if session, ok := r.sessionsByCancelKey[queryCancelKey]; ok {
+		return session.cancelCurrentQueries(), nil
+	}
+	return false, fmt.Errorf("session for cancel key %d not found", queryCancelKey)
+}
+
 // CancelSession looks up the specified session in the session registry and
 // cancels it. The caller is responsible for all permission checks.
 func (r *SessionRegistry) CancelSession(
@@ -2107,6 +2154,18 @@ func (m *sessionDataMutator) SetOptimizerFKCascadesLimit(val int) {
 	m.data.OptimizerFKCascadesLimit = val
 }
 
+func (m *sessionDataMutator) SetTriggerDepthLimit(val int) {
+	m.data.TriggerDepthLimit = val
+}
+
+func (m *sessionDataMutator) SetRowSecurity(val bool) {
+	m.data.RowSecurityDisabled = !val
+}
+
+func (m *sessionDataMutator) SetTrigramSimilarityThreshold(val float64) {
+	m.data.TrigramSimilarityThreshold = val
+}
+
 func (m *sessionDataMutator) SetOptimizerUseHistograms(val bool) {
 	m.data.OptimizerUseHistograms = val
 }
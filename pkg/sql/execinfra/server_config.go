@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/storage/cloud"
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
@@ -148,6 +149,12 @@ type ServerConfig struct {
 	// user-defined types.
 	HydratedTables *hydratedtables.Cache
 
+	// SQLLeafStartAdmissionQ and SQLRootStartAdmissionQ are the admission
+	// control queues for the setup of remote and local flows, respectively.
+	// They are nil when the server does not run as part of a KV node.
+	SQLLeafStartAdmissionQ *admission.WorkQueue
+	SQLRootStartAdmissionQ *admission.WorkQueue
+
 	LatencyGetter *serverpb.LatencyGetter
 }
 
@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -40,9 +41,14 @@ type exportNode struct {
 	// fileNamePattern represents the file naming pattern for the
 	// export, typically to be appended to the destination URI
 	fileNamePattern string
+	// fileFormat is the format of the exported files, either CSV or PARQUET.
+	fileFormat      string
 	csvOpts         roachpb.CSVOptions
 	chunkRows       int
 	fileCompression execinfrapb.FileCompression
+	// colNames are the names of the exported columns, which are used as the
+	// field names of Parquet files.
+	colNames []string
 }
 
 func (e *exportNode) startExec(params runParams) error {
@@ -77,10 +83,21 @@ var exportOptionExpectValues = map[string]KVStringOptValidate{
 	exportOptionCompression: KVStringOptRequireValue,
 }
 
+const (
+	exportFormatCSV     = "CSV"
+	exportFormatParquet = "PARQUET"
+)
+
 const exportChunkRowsDefault = 100000
 const exportFilePatternPart = "%part%"
 const exportFilePatternDefault = exportFilePatternPart + ".csv"
+
+// ExportFilePatternParquet is the default name pattern of the files written by
+// a Parquet export.
+const ExportFilePatternParquet = exportFilePatternPart + ".parquet"
+
 const exportCompressionCodec = "gzip"
+const exportCompressionCodecSnappy = "snappy"
 
 // featureExportEnabled is used to enable and disable the EXPORT feature.
 var featureExportEnabled = settings.RegisterBoolSetting(
@@ -113,9 +130,17 @@ func (ef *execFactory) ConstructExport(
 		return nil, errors.Errorf("EXPORT cannot be used inside a transaction")
 	}
 
-	if fileFormat != "CSV" {
+	if fileFormat != exportFormatCSV && fileFormat != exportFormatParquet {
 		return nil, errors.Errorf("unsupported export format: %q", fileFormat)
 	}
+	// Nodes running older versions do not know the ParquetWriter processor or
+	// the snappy compression codec, so neither may be planned before the
+	// upgrade is finalized.
+	if fileFormat == exportFormatParquet &&
+		!ef.planner.ExecCfg().Settings.Version.IsActive(ef.planner.EvalContext().Context, clusterversion.ExportParquet) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to export to %s", clusterversion.ExportParquet, fileFormat)
+	}
 
 	destinationDatum, err := fileName.Eval(ef.planner.EvalContext())
 	if err != nil {
@@ -147,6 +172,15 @@ func (ef *execFactory) ConstructExport(
 		return nil, err
 	}
 
+	if fileFormat == exportFormatParquet {
+		for _, opt := range []string{exportOptionDelimiter, exportOptionNullAs} {
+			if _, ok := optVals[opt]; ok {
+				return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s option is not supported for %s exports", opt, fileFormat)
+			}
+		}
+	}
+
 	csvOpts := roachpb.CSVOptions{}
 
 	if override, ok := optVals[exportOptionDelimiter]; ok {
@@ -167,7 +201,8 @@ func (ef *execFactory) ConstructExport(
 			return nil, pgerror.WithCandidateCode(err, pgcode.InvalidParameterValue)
 		}
 		if chunkRows < 1 {
-			return nil, pgerror.New(pgcode.InvalidParameterValue, "invalid csv chunk size")
+			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid %s chunk size", strings.ToLower(fileFormat))
 		}
 	}
 
@@ -177,6 +212,10 @@ func (ef *execFactory) ConstructExport(
 	if name, ok := optVals[exportOptionCompression]; ok && len(name) != 0 {
 		if strings.EqualFold(name, exportCompressionCodec) {
 			codec = execinfrapb.FileCompression_Gzip
+		} else if strings.EqualFold(name, exportCompressionCodecSnappy) && fileFormat == exportFormatParquet {
+			// Snappy compresses the pages of a Parquet file, so it has no
+			// equivalent for CSV files.
+			codec = execinfrapb.FileCompression_Snappy
 		} else {
 			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
 				"unsupported compression codec %s", name)
@@ -184,14 +223,27 @@ func (ef *execFactory) ConstructExport(
 	}
 
 	exportID := ef.planner.stmt.QueryID.String()
-	namePattern := fmt.Sprintf("export%s-%s", exportID, exportFilePatternDefault)
+	filePattern := exportFilePatternDefault
+	if fileFormat == exportFormatParquet {
+		filePattern = ExportFilePatternParquet
+	}
+	namePattern := fmt.Sprintf("export%s-%s", exportID, filePattern)
+
+	source := input.(planNode)
+	cols := planColumns(source)
+	colNames := make([]string, len(cols))
+	for i := range cols {
+		colNames[i] = cols[i].Name
+	}
 
 	return &exportNode{
-		source:          input.(planNode),
+		source:          source,
 		destination:     string(*destination),
 		fileNamePattern: namePattern,
+		fileFormat:      fileFormat,
 		csvOpts:         csvOpts,
 		chunkRows:       chunkRows,
 		fileCompression: codec,
+		colNames:        colNames,
 	}, nil
 }
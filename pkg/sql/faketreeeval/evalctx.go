@@ -252,3 +252,10 @@ func (c *DummyTenantOperator) DestroyTenant(_ context.Context, _ uint64) error {
 func (c *DummyTenantOperator) GCTenant(_ context.Context, _ uint64) error {
 	return errors.WithStack(errEvalTenant)
 }
+
+// UpdateTenantResourceLimits is part of the tree.TenantOperator interface.
+func (c *DummyTenantOperator) UpdateTenantResourceLimits(
+	_ context.Context, _ uint64, _ float64, _ float64,
+) error {
+	return errors.WithStack(errEvalTenant)
+}
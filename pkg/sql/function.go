@@ -0,0 +1,350 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+func (p *planner) writeFunctionDesc(ctx context.Context, desc *funcdesc.Mutable) error {
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// writeFunctionDescChange writes the function descriptor and queues a schema
+// change job which waits for the old versions of the function to be released
+// by all nodes, and deletes the descriptor once it is dropped.
+func (p *planner) writeFunctionDescChange(
+	ctx context.Context, desc *funcdesc.Mutable, jobDesc string,
+) error {
+	job, jobExists := p.extendedEvalCtx.SchemaChangeJobCache[desc.ID]
+	if jobExists {
+		// Update it.
+		if err := job.WithTxn(p.txn).SetDescription(ctx,
+			func(ctx context.Context, desc string) (string, error) {
+				return desc + "; " + jobDesc, nil
+			},
+		); err != nil {
+			return err
+		}
+		log.Infof(ctx, "job %d: updated with for change on function %d", *job.ID(), desc.ID)
+	} else {
+		// Or, create a new job.
+		jobRecord := jobs.Record{
+			Description:   jobDesc,
+			Username:      p.User(),
+			DescriptorIDs: descpb.IDs{desc.ID},
+			Details: jobspb.SchemaChangeDetails{
+				DescID: desc.ID,
+				// The version distinction for database jobs doesn't matter for
+				// function jobs.
+				FormatVersion: jobspb.DatabaseJobFormatVersion,
+			},
+			Progress: jobspb.SchemaChangeProgress{},
+		}
+		newJob, err := p.extendedEvalCtx.QueueJob(jobRecord)
+		if err != nil {
+			return err
+		}
+		p.extendedEvalCtx.SchemaChangeJobCache[desc.ID] = newJob
+		log.Infof(ctx, "queued new schema change job %d for function %d", *newJob.ID(), desc.ID)
+	}
+
+	return p.writeFunctionDesc(ctx, desc)
+}
+
+func (p *planner) getQualifiedFunctionName(
+	ctx context.Context, desc catalog.FunctionDescriptor,
+) (*tree.TableName, error) {
+	dbDesc, err := catalogkv.MustGetDatabaseDescByID(ctx, p.txn, p.ExecCfg().Codec, desc.GetParentID())
+	if err != nil {
+		return nil, err
+	}
+	schemaName, err := resolver.ResolveSchemaNameByID(
+		ctx, p.txn, p.ExecCfg().Codec, desc.GetParentID(), desc.GetParentSchemaID(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	fnName := tree.MakeTableNameWithSchema(
+		tree.Name(dbDesc.GetName()),
+		tree.Name(schemaName),
+		tree.Name(desc.GetName()),
+	)
+	return &fnName, nil
+}
+
+// canModifyFunction checks that the current user can replace or drop the
+// given function, which requires ownership of the function.
+func (p *planner) canModifyFunction(ctx context.Context, desc *funcdesc.Mutable) error {
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of function %s", tree.Name(desc.GetName()))
+	}
+	return nil
+}
+
+// getDependentFunction returns the function with the given ID, or nil if the
+// descriptor with the given ID is not a function. The DependedOnBy
+// back-references of a relation refer to both the views and the functions
+// that depend on it.
+func (p *planner) getDependentFunction(
+	ctx context.Context, id descpb.ID,
+) (*funcdesc.Mutable, error) {
+	desc, err := p.Descriptors().GetMutableDescriptorByID(ctx, id, p.txn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error resolving dependent object ID %d", id)
+	}
+	fnDesc, _ := desc.(*funcdesc.Mutable)
+	return fnDesc, nil
+}
+
+// dependentFunctionError returns an error indicating that an operation
+// cannot be performed on an object because a function depends on it.
+func (p *planner) dependentFunctionError(
+	ctx context.Context, typeName, objName string, fnDesc catalog.FunctionDescriptor, op string,
+) error {
+	fnName, err := p.getQualifiedFunctionName(ctx, fnDesc)
+	if err != nil {
+		log.Warningf(ctx, "unable to retrieve name of function %d: %v", fnDesc.GetID(), err)
+		return sqlerrors.NewDependentObjectErrorf(
+			"cannot %s %s %q because a function depends on it", op, typeName, objName)
+	}
+	return errors.WithHintf(
+		sqlerrors.NewDependentObjectErrorf("cannot %s %s %q because function %q depends on it",
+			op, typeName, objName, fnName.FQString()),
+		"you can drop %s instead.", fnName.FQString())
+}
+
+// canRemoveDependentFunction checks that the given function, which depends on
+// the object being dropped, can be dropped as well.
+func (p *planner) canRemoveDependentFunction(
+	ctx context.Context,
+	typeName, objName string,
+	fnDesc *funcdesc.Mutable,
+	behavior tree.DropBehavior,
+) error {
+	if behavior != tree.DropCascade {
+		return p.dependentFunctionError(ctx, typeName, objName, fnDesc, "drop")
+	}
+	if err := p.canModifyFunction(ctx, fnDesc); err != nil {
+		return err
+	}
+	return p.canRemoveViewsCallingFunction(ctx, fnDesc, behavior)
+}
+
+// canRemoveViewsCallingFunction checks that the views which call the given
+// function can be dropped along with it.
+func (p *planner) canRemoveViewsCallingFunction(
+	ctx context.Context, fnDesc *funcdesc.Mutable, behavior tree.DropBehavior,
+) error {
+	for _, id := range fnDesc.ReferencingDescriptorIDs {
+		if err := p.canRemoveDependentViewGeneric(
+			ctx, "function", fnDesc.Name, fnDesc.ParentID,
+			descpb.TableDescriptor_Reference{ID: id}, behavior,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addViewFunctionBackReferences records that the given view calls the given
+// functions.
+func (p *planner) addViewFunctionBackReferences(
+	ctx context.Context, viewDesc *tabledesc.Mutable, fnIDs []descpb.ID,
+) error {
+	for _, id := range fnIDs {
+		fnDesc, err := p.getMutableFunctionByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		fnDesc.AddReferencingDescriptorID(viewDesc.ID)
+		if err := p.writeFunctionDescChange(ctx, fnDesc,
+			fmt.Sprintf("updating view reference %q in function %s(%d)",
+				viewDesc.Name, fnDesc.Name, fnDesc.ID),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeViewFunctionBackReferences removes the back-references to the given
+// view from the functions it calls, other than those in keep.
+func (p *planner) removeViewFunctionBackReferences(
+	ctx context.Context, viewDesc *tabledesc.Mutable, keep []descpb.ID,
+) error {
+	for _, id := range viewDesc.DependsOnFunctions {
+		kept := false
+		for _, keepID := range keep {
+			kept = kept || id == keepID
+		}
+		if kept {
+			continue
+		}
+		fnDesc, err := p.getMutableFunctionByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		// The function is also being deleted, so we don't have to remove the
+		// reference.
+		if fnDesc.Dropped() {
+			continue
+		}
+		fnDesc.RemoveReferencingDescriptorID(viewDesc.ID)
+		if err := p.writeFunctionDescChange(ctx, fnDesc,
+			fmt.Sprintf("removing references for view %s from function %s(%d)",
+				viewDesc.Name, fnDesc.Name, fnDesc.ID),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getMutableFunctionByID returns the function with the given ID.
+func (p *planner) getMutableFunctionByID(
+	ctx context.Context, id descpb.ID,
+) (*funcdesc.Mutable, error) {
+	desc, err := p.Descriptors().GetMutableDescriptorByID(ctx, id, p.txn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error resolving function ID %d", id)
+	}
+	fnDesc, ok := desc.(*funcdesc.Mutable)
+	if !ok {
+		return nil, errors.AssertionFailedf("descriptor %d is not a function", id)
+	}
+	return fnDesc, nil
+}
+
+// removeFunctionBackReferences removes the back-references to the given
+// function from the relations it depends on, other than those in keep.
+func (p *planner) removeFunctionBackReferences(
+	ctx context.Context, fnDesc *funcdesc.Mutable, keep []descpb.ID,
+) error {
+	for _, depID := range fnDesc.DependsOn {
+		kept := false
+		for _, id := range keep {
+			kept = kept || id == depID
+		}
+		if kept {
+			continue
+		}
+		dependencyDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, depID, p.txn)
+		if err != nil {
+			return errors.Wrapf(err, "error resolving dependency relation ID %d", depID)
+		}
+		// The dependency is also being deleted, so we don't have to remove the
+		// references.
+		if dependencyDesc.Dropped() {
+			continue
+		}
+		dependencyDesc.DependedOnBy = removeMatchingReferences(dependencyDesc.DependedOnBy, fnDesc.ID)
+		if err := p.writeSchemaChange(
+			ctx, dependencyDesc, descpb.InvalidMutationID,
+			fmt.Sprintf("removing references for function %s from table %s(%d)",
+				fnDesc.Name, dependencyDesc.Name, dependencyDesc.ID),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeDependentFunction removes the back-references to the given function
+// from the relation whose column or index is being dropped, and drops the
+// function.
+func (p *planner) removeDependentFunction(
+	ctx context.Context, tableDesc *tabledesc.Mutable, fnDesc *funcdesc.Mutable, jobDesc string,
+) error {
+	tableDesc.DependedOnBy = removeMatchingReferences(tableDesc.DependedOnBy, fnDesc.ID)
+	return p.dropFunctionImpl(ctx, fnDesc, jobDesc)
+}
+
+// dropFunctionImpl drops the given function and the views which call it,
+// removing its back-references from the relations it depends on, and logs an
+// event for it.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, fnDesc *funcdesc.Mutable, jobDesc string,
+) error {
+	if fnDesc.Dropped() {
+		return nil
+	}
+	fnName, err := p.getQualifiedFunctionName(ctx, fnDesc)
+	if err != nil {
+		return err
+	}
+	// The callers have checked that the views can be dropped. Dropping a view
+	// removes it from the referencing descriptors of the function.
+	for _, id := range append([]descpb.ID(nil), fnDesc.ReferencingDescriptorIDs...) {
+		viewDesc, err := p.getViewDescForCascade(
+			ctx, "function", fnDesc.Name, fnDesc.ParentID, id, tree.DropCascade,
+		)
+		if err != nil {
+			return err
+		}
+		if viewDesc.Dropped() {
+			continue
+		}
+		if _, err := p.dropViewImpl(
+			ctx, viewDesc, true /* queueJob */, "dropping dependent view", tree.DropCascade,
+		); err != nil {
+			return err
+		}
+	}
+	if err := p.removeFunctionBackReferences(ctx, fnDesc, nil /* keep */); err != nil {
+		return err
+	}
+	fnDesc.DependsOn = nil
+
+	// Release the name of the function and mark it as dropped. The schema
+	// change job deletes the descriptor.
+	fnDesc.DrainingNames = append(fnDesc.DrainingNames, descpb.NameInfo{
+		ParentID:       fnDesc.ParentID,
+		ParentSchemaID: fnDesc.ParentSchemaID,
+		Name:           fnDesc.Name,
+	})
+	fnDesc.SetDropped()
+	if err := p.writeFunctionDescChange(ctx, fnDesc, jobDesc); err != nil {
+		return err
+	}
+
+	// Log a Drop Function event. This is an auditable log event and is
+	// recorded in the same transaction as the function descriptor update.
+	return p.logEvent(ctx, fnDesc.ID, &eventpb.DropFunction{FunctionName: fnName.FQString()})
+}
@@ -1403,10 +1403,8 @@ func (t *logicTest) setup(cfg testClusterConfig, serverArgs TestServerArgs) {
 		// Increase tenant rate limits for faster tests.
 		conn := t.cluster.ServerConn(0)
 		for _, settingName := range []string{
-			"kv.tenant_rate_limiter.read_requests.rate_limit",
-			"kv.tenant_rate_limiter.read_requests.burst_limit",
-			"kv.tenant_rate_limiter.write_requests.rate_limit",
-			"kv.tenant_rate_limiter.write_requests.burst_limit",
+			"kv.tenant_rate_limiter.rate_limit",
+			"kv.tenant_rate_limiter.burst_limit",
 		} {
 			if _, err := conn.Exec(
 				fmt.Sprintf("SET CLUSTER SETTING %s = %d", settingName, 100000),
@@ -1414,18 +1412,6 @@ func (t *logicTest) setup(cfg testClusterConfig, serverArgs TestServerArgs) {
 				t.Fatal(err)
 			}
 		}
-		for _, settingName := range []string{
-			"kv.tenant_rate_limiter.read_bytes.rate_limit",
-			"kv.tenant_rate_limiter.read_bytes.burst_limit",
-			"kv.tenant_rate_limiter.write_bytes.rate_limit",
-			"kv.tenant_rate_limiter.write_bytes.burst_limit",
-		} {
-			if _, err := conn.Exec(
-				fmt.Sprintf("SET CLUSTER SETTING %s = '1GB'", settingName),
-			); err != nil {
-				t.Fatal(err)
-			}
-		}
 	}
 
 	// Set cluster settings.
@@ -75,6 +75,8 @@ func buildOpaque(
 		plan, err = p.AlterRole(ctx, n)
 	case *tree.AlterSequence:
 		plan, err = p.AlterSequence(ctx, n)
+	case *tree.CloseCursor:
+		plan, err = p.CloseCursor(ctx, n)
 	case *tree.CommentOnColumn:
 		plan, err = p.CommentOnColumn(ctx, n)
 	case *tree.CommentOnDatabase:
@@ -87,6 +89,8 @@ func buildOpaque(
 		plan, err = p.CreateDatabase(ctx, n)
 	case *tree.CreateIndex:
 		plan, err = p.CreateIndex(ctx, n)
+	case *tree.CreatePolicy:
+		plan, err = p.CreatePolicy(ctx, n)
 	case *tree.CreateSchema:
 		plan, err = p.CreateSchema(ctx, n)
 	case *tree.CreateType:
@@ -103,10 +107,14 @@ func buildOpaque(
 		plan, err = p.Discard(ctx, n)
 	case *tree.DropDatabase:
 		plan, err = p.DropDatabase(ctx, n)
+	case *tree.DropFunction:
+		plan, err = p.DropFunction(ctx, n)
 	case *tree.DropIndex:
 		plan, err = p.DropIndex(ctx, n)
 	case *tree.DropOwnedBy:
 		plan, err = p.DropOwnedBy(ctx)
+	case *tree.DropPolicy:
+		plan, err = p.DropPolicy(ctx, n)
 	case *tree.DropRole:
 		plan, err = p.DropRole(ctx, n)
 	case *tree.DropSchema:
@@ -115,14 +123,20 @@ func buildOpaque(
 		plan, err = p.DropSequence(ctx, n)
 	case *tree.DropTable:
 		plan, err = p.DropTable(ctx, n)
+	case *tree.DropTrigger:
+		plan, err = p.DropTrigger(ctx, n)
 	case *tree.DropType:
 		plan, err = p.DropType(ctx, n)
 	case *tree.DropView:
 		plan, err = p.DropView(ctx, n)
+	case *tree.FetchCursor:
+		plan, err = p.FetchCursor(ctx, &n.CursorStmt, false /* isMove */)
 	case *tree.Grant:
 		plan, err = p.Grant(ctx, n)
 	case *tree.GrantRole:
 		plan, err = p.GrantRole(ctx, n)
+	case *tree.MoveCursor:
+		plan, err = p.FetchCursor(ctx, &n.CursorStmt, true /* isMove */)
 	case *tree.ReassignOwnedBy:
 		plan, err = p.ReassignOwnedBy(ctx, n)
 	case *tree.RefreshMaterializedView:
@@ -157,6 +171,8 @@ func buildOpaque(
 		plan, err = p.SetSessionAuthorizationDefault()
 	case *tree.SetSessionCharacteristics:
 		plan, err = p.SetSessionCharacteristics(n)
+	case *tree.SetConstraints:
+		plan, err = p.SetConstraints(ctx, n)
 	case *tree.ShowClusterSetting:
 		plan, err = p.ShowClusterSetting(ctx, n)
 	case *tree.ShowHistogram:
@@ -209,6 +225,7 @@ func init() {
 		&tree.AlterType{},
 		&tree.AlterSequence{},
 		&tree.AlterRole{},
+		&tree.CloseCursor{},
 		&tree.CommentOnColumn{},
 		&tree.CommentOnDatabase{},
 		&tree.CommentOnIndex{},
@@ -216,6 +233,7 @@ func init() {
 		&tree.CreateDatabase{},
 		&tree.CreateExtension{},
 		&tree.CreateIndex{},
+		&tree.CreatePolicy{},
 		&tree.CreateSchema{},
 		&tree.CreateSequence{},
 		&tree.CreateType{},
@@ -223,16 +241,21 @@ func init() {
 		&tree.Deallocate{},
 		&tree.Discard{},
 		&tree.DropDatabase{},
+		&tree.DropFunction{},
 		&tree.DropIndex{},
 		&tree.DropOwnedBy{},
+		&tree.DropPolicy{},
 		&tree.DropRole{},
 		&tree.DropSchema{},
 		&tree.DropSequence{},
 		&tree.DropTable{},
+		&tree.DropTrigger{},
 		&tree.DropType{},
 		&tree.DropView{},
+		&tree.FetchCursor{},
 		&tree.Grant{},
 		&tree.GrantRole{},
+		&tree.MoveCursor{},
 		&tree.ReassignOwnedBy{},
 		&tree.RefreshMaterializedView{},
 		&tree.RenameColumn{},
@@ -250,6 +273,7 @@ func init() {
 		&tree.SetTransaction{},
 		&tree.SetSessionAuthorizationDefault{},
 		&tree.SetSessionCharacteristics{},
+		&tree.SetConstraints{},
 		&tree.ShowClusterSetting{},
 		&tree.ShowHistogram{},
 		&tree.ShowTableStats{},
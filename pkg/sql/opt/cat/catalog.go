@@ -125,6 +125,17 @@ type Catalog interface {
 		ctx context.Context, name *tree.UnresolvedObjectName,
 	) (*types.T, error)
 
+	// ResolveFunction locates a user-defined function with the given name,
+	// following the search path if the name is not qualified. If no such
+	// function exists, then ResolveFunction returns an error with code
+	// pgcode.UndefinedFunction.
+	//
+	// NOTE: The returned function must be immutable after construction, and so
+	// can be safely copied or used across goroutines.
+	ResolveFunction(
+		ctx context.Context, flags Flags, name *tree.UnresolvedObjectName,
+	) (Function, error)
+
 	// CheckPrivilege verifies that the current user has the given privilege on
 	// the given catalog object. If not, then CheckPrivilege returns an error.
 	CheckPrivilege(ctx context.Context, o Object, priv privilege.Kind) error
@@ -150,6 +161,19 @@ type Catalog interface {
 	// NOLOGIN instead of LOGIN.
 	HasRoleOption(ctx context.Context, roleOption roleoption.Option) (bool, error)
 
+	// HasExplicitRoleOption is like HasRoleOption, except that admins only have
+	// the role options which were granted to them.
+	HasExplicitRoleOption(ctx context.Context, roleOption roleoption.Option) (bool, error)
+
+	// HasOwnership returns true if the current user, or one of the roles it is
+	// a member of, owns the given catalog object.
+	HasOwnership(ctx context.Context, o Object) (bool, error)
+
+	// IsMemberOfRole returns true if the current user is the given role or a
+	// member of it, directly or indirectly. Every user is a member of the
+	// public role.
+	IsMemberOfRole(ctx context.Context, role string) (bool, error)
+
 	// FullyQualifiedName retrieves the fully qualified name of a data source.
 	// Note that:
 	//  - this call may involve a database operation so it shouldn't be used in
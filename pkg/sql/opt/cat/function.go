@@ -0,0 +1,52 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// Function is an interface to a user-defined function, exposing only the
+// information needed by the query optimizer. A user-defined function is
+// defined by a SQL query that computes its result from its parameters.
+type Function interface {
+	Object
+
+	// Name returns the fully normalized, fully qualified, and fully resolved
+	// name of the function (<db-name>.<schema-name>.<function-name>).
+	Name() *tree.TableName
+
+	// ParamCount returns the number of parameters of the function.
+	ParamCount() int
+
+	// ParamName returns the name of the ith parameter, where i < ParamCount.
+	// The name is empty if the parameter is unnamed.
+	ParamName(i int) tree.Name
+
+	// ParamType returns the type of the ith parameter, where i < ParamCount.
+	ParamType(i int) *types.T
+
+	// ReturnType returns the type of the result of the function.
+	ReturnType() *types.T
+
+	// Volatility returns the volatility declared for the function.
+	Volatility() tree.Volatility
+
+	// ReturnsNullOnNullInput returns true if the function returns NULL without
+	// evaluating its body when any of its arguments are NULL.
+	ReturnsNullOnNullInput() bool
+
+	// Body returns the SQL text of the SELECT query that computes the result
+	// of the function. The query refers to the parameters of the function by
+	// name.
+	Body() string
+}
@@ -131,6 +131,30 @@ type Table interface {
 	// Unique returns the ith unique constraint defined on this table, where
 	// i < UniqueCount.
 	Unique(i int) UniqueConstraint
+
+	// TriggerCount returns the number of triggers defined on this table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger defined on this table, where
+	// i < TriggerCount. Triggers are ordered by name, which is also the order
+	// in which they fire.
+	Trigger(i int) Trigger
+
+	// IsRowLevelSecurityEnabled returns true if the row-level security
+	// policies of the table are enforced.
+	IsRowLevelSecurityEnabled() bool
+
+	// IsRowLevelSecurityForced returns true if the row-level security policies
+	// of the table are also enforced for the owner of the table.
+	IsRowLevelSecurityForced() bool
+
+	// PolicyCount returns the number of row-level security policies defined on
+	// this table.
+	PolicyCount() int
+
+	// Policy returns the ith row-level security policy defined on this table,
+	// where i < PolicyCount.
+	Policy(i int) Policy
 }
 
 // CheckConstraint contains the SQL text and the validity status for a check
@@ -244,6 +268,10 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checks of the constraint can be deferred
+	// until the end of the transaction, and whether they are by default.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -277,4 +305,76 @@ type UniqueConstraint interface {
 	// cannot make any assumptions about the data. An unvalidated constraint still
 	// needs to be enforced on new mutations.
 	Validated() bool
+
+	// Deferrability returns whether the checks of the constraint can be deferred
+	// until the end of the transaction, and whether they are by default.
+	Deferrability() tree.ConstraintDeferrability
+}
+
+// Trigger represents a trigger defined on a table. A trigger executes a SQL
+// statement before or after an INSERT, UPDATE or DELETE on the table, either
+// once per statement or once per modified row. For example:
+//   CREATE TRIGGER tr AFTER INSERT ON t FOR EACH ROW AS 'INSERT INTO log ...'
+// Triggers are planned like cascades: the action runs after the mutation (or
+// before it, for BEFORE triggers) as a separate query in the same transaction.
+type Trigger interface {
+	// Name of the trigger.
+	Name() string
+
+	// Before is true if the trigger fires before the mutation, and false if it
+	// fires after.
+	Before() bool
+
+	// ForEachRow is true if the trigger fires once for each modified row, and
+	// false if it fires once per statement.
+	ForEachRow() bool
+
+	// OnInsert is true if the trigger fires on INSERT.
+	OnInsert() bool
+
+	// OnUpdate is true if the trigger fires on UPDATE.
+	OnUpdate() bool
+
+	// OnDelete is true if the trigger fires on DELETE.
+	OnDelete() bool
+
+	// Action returns the SQL statement executed when the trigger fires. Names
+	// in the statement are fully qualified.
+	Action() string
+}
+
+// Policy represents a row-level security policy defined on a table. When
+// row-level security is enabled on the table, the policies which apply to a
+// command and to the current user determine the rows that the command can
+// read and write. For example:
+//   CREATE POLICY p ON t FOR SELECT TO alice USING (owner = current_user)
+// A row is visible if it passes the USING expression of at least one of the
+// permissive policies, and of all the restrictive policies.
+type Policy interface {
+	// Name of the policy.
+	Name() string
+
+	// Restrictive is true if the policy is restrictive, and false if it is
+	// permissive.
+	Restrictive() bool
+
+	// Command returns the command to which the policy applies.
+	Command() tree.PolicyCommand
+
+	// RoleCount returns the number of roles to which the policy applies.
+	RoleCount() int
+
+	// Role returns the normalized name of the ith role to which the policy
+	// applies, where i < RoleCount. The policy applies to all users if one of
+	// the roles is "public".
+	Role(i int) string
+
+	// UsingExpr returns the SQL text of the expression which filters the
+	// existing rows read by the command, or the empty string if there is none.
+	UsingExpr() string
+
+	// WithCheckExpr returns the SQL text of the expression which the new rows
+	// written by the command must satisfy, or the empty string if there is
+	// none.
+	WithCheckExpr() string
 }
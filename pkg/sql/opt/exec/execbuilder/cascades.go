@@ -154,8 +154,11 @@ func makeCascadeBuilder(b *Builder, mutationWithID opt.WithID) (*cascadeBuilder,
 // setupCascade fills in an exec.Cascade struct for the given cascade.
 func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
 	return exec.Cascade{
-		FKName: cascade.FKName,
-		Buffer: cb.mutationBuffer,
+		FKName:     cascade.FKName,
+		Trigger:    cascade.Trigger,
+		Before:     cascade.Before,
+		ForEachRow: cascade.ForEachRow,
+		Buffer:     cb.mutationBuffer,
 		PlanFn: func(
 			ctx context.Context,
 			semaCtx *tree.SemaContext,
@@ -72,6 +72,16 @@ func (b *Builder) buildMutationInput(
 
 		b.addBuiltWithExpr(p.WithID, input.outputCols, bufferNode)
 		input.root = bufferNode
+
+		if p.FKCascades.HasBeforeTriggers() {
+			// BEFORE triggers must run after the input is buffered but before the
+			// mutation. The buffer is filled by the execution engine before the
+			// triggers run (see exec.Cascade), and the mutation reads from it.
+			input.root, err = b.factory.ConstructScanBuffer(bufferNode, label)
+			if err != nil {
+				return execPlan{}, err
+			}
+		}
 	}
 	return input, nil
 }
@@ -123,6 +133,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -156,10 +170,16 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	// We cannot use the fast path if the table has triggers, which are the only
+	// cascades an insert can have.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
-	//  - there are no self-referencing foreign keys;
+	//  - there are no self-referencing or deferrable foreign keys;
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	fkChecks := make([]exec.InsertFastPathFKCheck, len(ins.FKChecks))
 	for i := range ins.FKChecks {
@@ -169,6 +189,11 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability().Deferrable {
+			// Violations of a deferrable FK may have to be tolerated until the end
+			// of the transaction.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -568,6 +593,7 @@ func (b *Builder) tryBuildDeleteRange(del *memo.DeleteExpr) (_ execPlan, ok bool
 // of a row cascades into deleting all interleaved rows with the same prefix.
 // More specifically, the following conditions must apply:
 //  - none of the tables in the hierarchy have secondary indexes;
+//  - none of the tables in the hierarchy have triggers;
 //  - none of the tables in the hierarchy are referenced by any tables outside
 //    the hierarchy;
 //  - all foreign key references between tables in the hierarchy have columns
@@ -591,6 +617,11 @@ func (b *Builder) tryBuildDeleteRangeOnInterleaving(
 			return execPlan{}, false, nil
 		}
 
+		// Triggers must see the deleted rows, which DeleteRange does not fetch.
+		if currTab.TriggerCount() > 0 {
+			return execPlan{}, false, nil
+		}
+
 		currIdx := currTab.Index(cat.PrimaryIndex)
 		for i, n := 0, currIdx.InterleavedByCount(); i < n; i++ {
 			// We don't care about the index ID because we bail if any of the tables
@@ -801,7 +832,14 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		var deferrable *exec.DeferrableCheck
+		uc := md.TableMeta(c.Table).Table.Unique(c.CheckOrdinal)
+		if d := uc.Deferrability(); d.Deferrable {
+			deferrable = mkDeferrableCheck(
+				md.Table(c.Table).ID(), uc.Name(), false /* foreignKey */, d, c.KeyCols, query,
+			)
+		}
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -827,7 +865,19 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			}
 			return mkFKCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		var fk cat.ForeignKeyConstraint
+		if c.FKOutbound {
+			fk = md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal)
+		} else {
+			fk = md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+		}
+		var deferrable *exec.DeferrableCheck
+		if d := fk.Deferrability(); d.Deferrable {
+			deferrable = mkDeferrableCheck(
+				fk.OriginTableID(), fk.Name(), true /* foreignKey */, d, c.KeyCols, query,
+			)
+		}
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -836,6 +886,31 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// mkDeferrableCheck returns the exec.DeferrableCheck for a deferrable
+// constraint. The keyCols are the columns of the check query that correspond
+// to the constraint columns; for a foreign key, the values of the origin and
+// referenced columns are the same.
+func mkDeferrableCheck(
+	tableID cat.StableID,
+	name string,
+	foreignKey bool,
+	deferrability tree.ConstraintDeferrability,
+	keyCols opt.ColList,
+	query execPlan,
+) *exec.DeferrableCheck {
+	ords := make([]exec.NodeColumnOrdinal, len(keyCols))
+	for i, col := range keyCols {
+		ords[i] = query.getNodeColumnOrdinal(col)
+	}
+	return &exec.DeferrableCheck{
+		TableID:           tableID,
+		ConstraintName:    name,
+		ForeignKey:        foreignKey,
+		InitiallyDeferred: deferrability.InitiallyDeferred,
+		KeyCols:           ords,
+	}
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
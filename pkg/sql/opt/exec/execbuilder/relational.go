@@ -201,6 +201,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.GroupByExpr, *memo.ScalarGroupByExpr:
 		ep, err = b.buildGroupBy(e)
 
+	case *memo.GroupingSetsExpr:
+		ep, err = b.buildGroupingSets(t)
+
 	case *memo.DistinctOnExpr, *memo.EnsureDistinctOnExpr, *memo.UpsertDistinctOnExpr,
 		*memo.EnsureUpsertDistinctOnExpr:
 		ep, err = b.buildDistinct(t)
@@ -262,6 +265,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.CreateViewExpr:
 		ep, err = b.buildCreateView(t)
 
+	case *memo.CreateFunctionExpr:
+		ep, err = b.buildCreateFunction(t)
+
+	case *memo.CreateTriggerExpr:
+		ep, err = b.buildCreateTrigger(t)
+
 	case *memo.WithExpr:
 		ep, err = b.buildWith(t)
 
@@ -1089,6 +1098,37 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 	}
 
 	aggregations := *groupBy.Child(1).(*memo.AggregationsExpr)
+	aggInfos, err := b.buildAggInfos(input, aggregations)
+	if err != nil {
+		return execPlan{}, err
+	}
+	for i := range aggregations {
+		ep.outputCols.Set(int(aggregations[i].Col), len(groupingColIdx)+i)
+	}
+
+	if groupBy.Op() == opt.ScalarGroupByOp {
+		ep.root, err = b.factory.ConstructScalarGroupBy(input.root, aggInfos)
+	} else {
+		groupBy := groupBy.(*memo.GroupByExpr)
+		groupingColOrder := input.sqlOrdering(ordering.StreamingGroupingColOrdering(
+			&groupBy.GroupingPrivate, &groupBy.RequiredPhysical().Ordering,
+		))
+		reqOrdering := ep.reqOrdering(groupBy)
+		ep.root, err = b.factory.ConstructGroupBy(
+			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering,
+		)
+	}
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
+// buildAggInfos builds the exec.AggInfo for each of the given aggregations,
+// whose arguments are columns of the given input.
+func (b *Builder) buildAggInfos(
+	input execPlan, aggregations memo.AggregationsExpr,
+) ([]exec.AggInfo, error) {
 	aggInfos := make([]exec.AggInfo, len(aggregations))
 	for i := range aggregations {
 		item := &aggregations[i]
@@ -1098,7 +1138,7 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 		if aggFilter, ok := agg.(*memo.AggFilterExpr); ok {
 			filter, ok := aggFilter.Filter.(*memo.VariableExpr)
 			if !ok {
-				return execPlan{}, errors.AssertionFailedf("only VariableOp args supported")
+				return nil, errors.AssertionFailedf("only VariableOp args supported")
 			}
 			filterOrd = input.getNodeColumnOrdinal(filter.Col)
 			agg = aggFilter.Input
@@ -1120,12 +1160,12 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			child := agg.Child(j)
 			if variable, ok := child.(*memo.VariableExpr); ok {
 				if len(constArgs) != 0 {
-					return execPlan{}, errors.Errorf("constant args must come after variable args")
+					return nil, errors.Errorf("constant args must come after variable args")
 				}
 				argCols = append(argCols, input.getNodeColumnOrdinal(variable.Col))
 			} else {
 				if len(argCols) == 0 {
-					return execPlan{}, errors.Errorf("a constant arg requires at least one variable arg")
+					return nil, errors.Errorf("a constant arg requires at least one variable arg")
 				}
 				constArgs = append(constArgs, memo.ExtractConstDatum(child))
 			}
@@ -1139,24 +1179,123 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			ConstArgs:  constArgs,
 			Filter:     filterOrd,
 		}
-		ep.outputCols.Set(int(item.Col), len(groupingColIdx)+i)
 	}
+	return aggInfos, nil
+}
 
-	if groupBy.Op() == opt.ScalarGroupByOp {
-		ep.root, err = b.factory.ConstructScalarGroupBy(input.root, aggInfos)
-	} else {
-		groupBy := groupBy.(*memo.GroupByExpr)
-		groupingColOrder := input.sqlOrdering(ordering.StreamingGroupingColOrdering(
-			&groupBy.GroupingPrivate, &groupBy.RequiredPhysical().Ordering,
-		))
-		reqOrdering := ep.reqOrdering(groupBy)
-		ep.root, err = b.factory.ConstructGroupBy(
-			input.root, groupingColIdx, groupingColOrder, aggInfos, reqOrdering,
-		)
+// buildGroupingSets builds a GroupingSets expression. The input is buffered,
+// and is aggregated separately for each grouping set, using a GroupBy over a
+// scan of the buffer (or a ScalarGroupBy if the grouping set is empty). Each
+// aggregation is followed by a Render which adds NULLs for the grouping
+// columns that are not part of the grouping set, and the ordinal of the
+// grouping set. The results are combined using UNION ALL.
+func (b *Builder) buildGroupingSets(groupingSets *memo.GroupingSetsExpr) (execPlan, error) {
+	input, err := b.buildRelational(groupingSets.Input)
+	if err != nil {
+		return execPlan{}, err
 	}
+
+	const label = "grouping sets"
+	buffer, err := b.factory.ConstructBuffer(input.root, label)
 	if err != nil {
 		return execPlan{}, err
 	}
+
+	// Add the buffer as a subquery so it gets executed ahead of time, like the
+	// buffer of a With expression (see buildWith).
+	b.subqueries = append(b.subqueries, exec.Subquery{
+		Mode: exec.SubqueryAllRows,
+		Root: buffer,
+	})
+
+	// The output columns are the aggregates, the output grouping columns and
+	// the grouping set column, in this order.
+	aggregations := groupingSets.Aggregations
+	private := &groupingSets.GroupingSetsPrivate
+	md := b.mem.Metadata()
+	numCols := len(aggregations) + len(private.OutCols) + 1
+	var ep execPlan
+	cols := make(colinfo.ResultColumns, 0, numCols)
+	for i := range aggregations {
+		ep.outputCols.Set(int(aggregations[i].Col), len(cols))
+		cols = append(cols, colinfo.ResultColumn{
+			Name: md.ColumnMeta(aggregations[i].Col).Alias,
+			Typ:  aggregations[i].Agg.DataType(),
+		})
+	}
+	for _, col := range private.OutCols {
+		ep.outputCols.Set(int(col), len(cols))
+		meta := md.ColumnMeta(col)
+		cols = append(cols, colinfo.ResultColumn{Name: meta.Alias, Typ: meta.Type})
+	}
+	ep.outputCols.Set(int(private.GroupingSetCol), len(cols))
+	cols = append(cols, colinfo.ResultColumn{
+		Name: md.ColumnMeta(private.GroupingSetCol).Alias,
+		Typ:  types.Int,
+	})
+
+	for i, set := range private.Sets {
+		scan, err := b.factory.ConstructScanBuffer(buffer, label)
+		if err != nil {
+			return execPlan{}, err
+		}
+		scanPlan := execPlan{root: scan, outputCols: input.outputCols}
+
+		var groupingColIdx []exec.NodeColumnOrdinal
+		var groupingColMap opt.ColMap
+		for col, ok := set.Next(0); ok; col, ok = set.Next(col + 1) {
+			groupingColMap.Set(int(col), len(groupingColIdx))
+			groupingColIdx = append(groupingColIdx, scanPlan.getNodeColumnOrdinal(col))
+		}
+		aggInfos, err := b.buildAggInfos(scanPlan, aggregations)
+		if err != nil {
+			return execPlan{}, err
+		}
+		var node exec.Node
+		if set.Empty() {
+			node, err = b.factory.ConstructScalarGroupBy(scan, aggInfos)
+		} else {
+			node, err = b.factory.ConstructGroupBy(
+				scan, groupingColIdx, nil /* groupColOrdering */, aggInfos, nil, /* reqOrdering */
+			)
+		}
+		if err != nil {
+			return execPlan{}, err
+		}
+
+		// Render the output columns of the grouping set. The aggregation outputs
+		// the grouping columns of the set, followed by the aggregates.
+		exprs := make(tree.TypedExprs, 0, numCols)
+		ivh := tree.MakeIndexedVarHelper(nil /* container */, len(groupingColIdx)+len(aggInfos))
+		for j := range aggInfos {
+			exprs = append(exprs, ivh.IndexedVarWithType(len(groupingColIdx)+j, cols[j].Typ))
+		}
+		for j, col := range private.InputCols {
+			typ := cols[len(aggInfos)+j].Typ
+			if idx, ok := groupingColMap.Get(int(col)); ok {
+				exprs = append(exprs, ivh.IndexedVarWithType(idx, typ))
+			} else if typ.Family() == types.TupleFamily {
+				// See comment in buildCast.
+				exprs = append(exprs, tree.DNull)
+			} else {
+				exprs = append(exprs, tree.ReType(tree.DNull, typ))
+			}
+		}
+		exprs = append(exprs, tree.NewDInt(tree.DInt(i)))
+		node, err = b.factory.ConstructRender(node, cols, exprs, nil /* reqOrdering */)
+		if err != nil {
+			return execPlan{}, err
+		}
+
+		if i == 0 {
+			ep.root = node
+		} else {
+			ep.root, err = b.factory.ConstructSetOp(tree.UnionOp, true /* all */, ep.root, node)
+			if err != nil {
+				return execPlan{}, err
+			}
+		}
+	}
 	return ep, nil
 }
 
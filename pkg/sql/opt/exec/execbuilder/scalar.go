@@ -278,7 +278,7 @@ func (b *Builder) buildFunction(
 			return nil, err
 		}
 	}
-	funcRef := tree.WrapFunction(fn.Name)
+	funcRef := tree.WrapResolvedFunction(fn.Name, fn.Properties)
 	return tree.NewTypedFuncExpr(
 		funcRef,
 		0, /* aggQualifier */
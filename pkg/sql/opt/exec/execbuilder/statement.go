@@ -65,10 +65,23 @@ func (b *Builder) buildCreateView(cv *memo.CreateViewExpr) (execPlan, error) {
 		cv.ViewQuery,
 		cols,
 		cv.Deps,
+		cv.FuncDeps,
 	)
 	return execPlan{root: root}, err
 }
 
+func (b *Builder) buildCreateFunction(cf *memo.CreateFunctionExpr) (execPlan, error) {
+	schema := b.mem.Metadata().Schema(cf.Schema)
+	root, err := b.factory.ConstructCreateFunction(schema, cf.FuncName, cf.Syntax, cf.Body, cf.Deps)
+	return execPlan{root: root}, err
+}
+
+func (b *Builder) buildCreateTrigger(ct *memo.CreateTriggerExpr) (execPlan, error) {
+	table := b.mem.Metadata().Table(ct.Table)
+	root, err := b.factory.ConstructCreateTrigger(table, ct.Syntax, ct.Action)
+	return execPlan{root: root}, err
+}
+
 func (b *Builder) buildExplainOpt(explain *memo.ExplainExpr) (execPlan, error) {
 	fmtFlags := memo.ExprFmtHideAll
 	switch {
@@ -97,10 +97,41 @@ func Emit(plan *Plan, ob *OutputBuilder, spanFormatFn SpanFormatFn) error {
 		ob.LeaveNode()
 	}
 
+	// walkedBuffers contains the mutation inputs buffered for BEFORE triggers
+	// that were already emitted.
+	var walkedBuffers map[exec.Node]bool
 	for i := range plan.Cascades {
-		ob.EnterMetaNode("fk-cascade")
-		ob.Attr("fk", plan.Cascades[i].FKName)
-		if buffer := plan.Cascades[i].Buffer; buffer != nil {
+		c := &plan.Cascades[i]
+		if c.Before && !walkedBuffers[c.Buffer] {
+			// The input of the mutation is buffered before the BEFORE triggers
+			// run; it is not part of the main query.
+			if walkedBuffers == nil {
+				walkedBuffers = make(map[exec.Node]bool)
+			}
+			walkedBuffers[c.Buffer] = true
+			ob.EnterMetaNode("trigger-input")
+			if err := walk(c.Buffer.(*Node)); err != nil {
+				return err
+			}
+			ob.LeaveNode()
+		}
+		if c.Trigger {
+			ob.EnterMetaNode("trigger")
+			ob.Attr("trigger", c.FKName)
+			when := "after"
+			if c.Before {
+				when = "before"
+			}
+			if c.ForEachRow {
+				ob.Attrf("fires", "%s, for each row", when)
+			} else {
+				ob.Attrf("fires", "%s, for each statement", when)
+			}
+		} else {
+			ob.EnterMetaNode("fk-cascade")
+			ob.Attr("fk", c.FKName)
+		}
+		if buffer := c.Buffer; buffer != nil {
 			ob.Attr("input", buffer.(*Node).args.(*bufferArgs).Label)
 		}
 		ob.LeaveNode()
@@ -257,6 +288,8 @@ var nodeNames = [...]string{
 	createTableOp:          "create table",
 	createTableAsOp:        "create table as",
 	createViewOp:           "create view",
+	createFunctionOp:       "create function",
+	createTriggerOp:        "create trigger",
 	deleteOp:               "delete",
 	deleteRangeOp:          "delete range",
 	distinctOp:             "distinct",
@@ -680,6 +713,8 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		createTableOp,
 		createTableAsOp,
 		createViewOp,
+		createFunctionOp,
+		createTriggerOp,
 		sequenceSelectOp,
 		saveTableOp,
 		errorIfRowsOp,
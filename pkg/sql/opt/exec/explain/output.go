@@ -80,7 +80,7 @@ func (ob *OutputBuilder) EnterNode(
 
 // EnterMetaNode is like EnterNode, but the output will always have empty
 // strings for the columns and ordering. This is used for "meta nodes" like
-// "fk-cascade".
+// "fk-cascade" or "trigger".
 func (ob *OutputBuilder) EnterMetaNode(name string) {
 	ob.enterNode(name, "", "")
 }
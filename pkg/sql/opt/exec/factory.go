@@ -200,10 +200,27 @@ type ApplyJoinPlanRightSideFn func(ef Factory, leftRow tree.Datums) (Plan, error
 // Cascade describes a cascading query. The query uses a node created by
 // ConstructBuffer as an input; it should only be triggered if this buffer is
 // not empty.
+//
+// A Cascade can also describe a trigger defined on the mutated table. A
+// statement-level trigger is triggered even if the buffer is empty; a row-level
+// trigger is planned once for each buffered row, with a buffer containing just
+// that row.
 type Cascade struct {
-	// FKName is the name of the foreign key constraint.
+	// FKName is the name of the foreign key constraint, or the name of the
+	// trigger if Trigger is set.
 	FKName string
 
+	// Trigger is true if this is a trigger rather than an FK cascade.
+	Trigger bool
+
+	// Before is true if this is a trigger that must run before the mutation.
+	// The mutation reads its input from Buffer (using ScanBuffer), so Buffer
+	// must be filled and the trigger run before the main query.
+	Before bool
+
+	// ForEachRow is true if this is a row-level trigger.
+	ForEachRow bool
+
 	// Buffer is the Node returned by ConstructBuffer which stores the input to
 	// the mutation. It is nil if the cascade does not require a buffer.
 	Buffer Node
@@ -259,6 +276,30 @@ type InsertFastPathFKCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableCheck contains information about a foreign key or unique
+// constraint check whose violations can be deferred until the end of the
+// transaction (see ConstructErrorIfRows).
+type DeferrableCheck struct {
+	// TableID identifies the table the constraint belongs to; for foreign keys,
+	// this is the origin (referencing) table.
+	TableID cat.StableID
+
+	// ConstraintName is the name of the constraint.
+	ConstraintName string
+
+	// ForeignKey is true if the constraint is a foreign key, and false if it is
+	// a unique constraint.
+	ForeignKey bool
+
+	// InitiallyDeferred is true if the check is deferred unless SET CONSTRAINTS
+	// says otherwise.
+	InitiallyDeferred bool
+
+	// KeyCols contains the ordinals of the input columns which hold the key of
+	// the violating rows, in the order of the constraint columns.
+	KeyCols []NodeColumnOrdinal
+}
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
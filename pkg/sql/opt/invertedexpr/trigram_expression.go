@@ -0,0 +1,38 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedexpr
+
+import "github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+
+// TrigramsToSpanExpr converts a set of trigrams to a SpanExpression over a
+// trigram inverted index. If all is true, the SpanExpression represents the
+// rows containing all of the trigrams (the intersection of their keys).
+// Otherwise, it represents the rows containing any of the trigrams (the union
+// of their keys). The SpanExpression is never tight, since the trigrams of a
+// string only approximate the string. Returns nil if there are no trigrams.
+func TrigramsToSpanExpr(trigrams []string, all bool) *SpanExpression {
+	var invExpr InvertedExpression
+	for _, t := range trigrams {
+		val := EncInvertedVal(rowenc.EncodeTrigramInvertedIndexKey(nil /* inKey */, t))
+		spanExpr := ExprForInvertedSpan(MakeSingleInvertedValSpan(val), false /* tight */)
+		if invExpr == nil {
+			invExpr = spanExpr
+		} else if all {
+			invExpr = And(invExpr, spanExpr)
+		} else {
+			invExpr = Or(invExpr, spanExpr)
+		}
+	}
+	if spanExpr, ok := invExpr.(*SpanExpression); ok {
+		return spanExpr
+	}
+	return nil
+}
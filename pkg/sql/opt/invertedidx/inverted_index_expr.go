@@ -108,12 +108,20 @@ func TryFilterInvertedIndex(
 		}
 		typ = types.Geometry
 	} else {
-		filterPlanner = &jsonOrArrayFilterPlanner{
-			tabID: tabID,
-			index: index,
-		}
 		col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
 		typ = factory.Metadata().Table(tabID).Column(col).DatumType()
+		if typ.Family() == types.StringFamily {
+			// Only trigram inverted indexes can be created on string columns.
+			filterPlanner = &trigramFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		} else {
+			filterPlanner = &jsonOrArrayFilterPlanner{
+				tabID: tabID,
+				index: index,
+			}
+		}
 	}
 
 	var invertedExpr invertedexpr.InvertedExpression
@@ -188,6 +196,11 @@ func TryJoinInvertedIndex(
 			getSpanExpr: getSpanExprForGeometryIndex,
 		}
 	} else {
+		col := index.VirtualInvertedColumn().InvertedSourceColumnOrdinal()
+		if factory.Metadata().Table(tabID).Column(col).DatumType().Family() == types.StringFamily {
+			// Inverted joins are not supported for trigram inverted indexes.
+			return nil
+		}
 		joinPlanner = &jsonOrArrayJoinPlanner{
 			tabID:     tabID,
 			index:     index,
@@ -0,0 +1,105 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+)
+
+type trigramFilterPlanner struct {
+	tabID opt.TableID
+	index cat.Index
+}
+
+var _ invertedFilterPlanner = &trigramFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf is part of the invertedFilterPlanner
+// interface.
+func (t *trigramFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	evalCtx *tree.EvalContext, expr opt.ScalarExpr,
+) (
+	invertedExpr invertedexpr.InvertedExpression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	var trigrams []string
+	var all bool
+	switch e := expr.(type) {
+	case *memo.LikeExpr:
+		trigrams, all = t.getLikeTrigrams(e.Left, e.Right), true
+
+	case *memo.ILikeExpr:
+		// The index keys are lowercased, so the trigrams of the pattern are the
+		// same for LIKE and ILIKE.
+		trigrams, all = t.getLikeTrigrams(e.Left, e.Right), true
+
+	case *memo.ModExpr:
+		// A string which is similar to the constant must share at least one
+		// trigram with it, as long as the similarity threshold is positive.
+		if evalCtx.SessionData.TrigramSimilarityThreshold <= 0 {
+			return invertedexpr.NonInvertedColExpression{}, expr, nil
+		}
+		if s, ok := t.extractConstString(e.Left, e.Right); ok {
+			trigrams, all = trigram.MakeTrigrams(s, true /* pad */), false
+		}
+	}
+
+	spanExpr := invertedexpr.TrigramsToSpanExpr(trigrams, all)
+	if spanExpr == nil {
+		return invertedexpr.NonInvertedColExpression{}, expr, nil
+	}
+	// The trigrams only approximate the filter, so the original filter must
+	// always be applied. We do not currently support pre-filtering for trigram
+	// indexes, so the returned pre-filter state is nil.
+	return spanExpr, expr, nil
+}
+
+// getLikeTrigrams returns the trigrams which any string matching the constant
+// LIKE pattern in the right expression argument must contain, if the left
+// argument is a variable corresponding to the index column.
+func (t *trigramFilterPlanner) getLikeTrigrams(left, right opt.ScalarExpr) []string {
+	pattern, ok := t.extractConstString(left, right)
+	if !ok {
+		return nil
+	}
+	return trigram.MakeLikePatternTrigrams(pattern)
+}
+
+// extractConstString returns the constant string of the given right
+// expression argument if the left argument is a variable corresponding to the
+// index column. Otherwise, returns ok=false.
+func (t *trigramFilterPlanner) extractConstString(
+	left, right opt.ScalarExpr,
+) (s string, ok bool) {
+	variable, ok := left.(*memo.VariableExpr)
+	if !ok {
+		return "", false
+	}
+	if variable.Col != t.tabID.ColumnID(
+		t.index.VirtualInvertedColumn().InvertedSourceColumnOrdinal(),
+	) {
+		// The column does not match the index column.
+		return "", false
+	}
+	if !memo.CanExtractConstDatum(right) {
+		return "", false
+	}
+	d, ok := memo.ExtractConstDatum(right).(*tree.DString)
+	if !ok {
+		return "", false
+	}
+	return string(*d), true
+}
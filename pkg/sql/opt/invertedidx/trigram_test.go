@@ -0,0 +1,140 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedidx"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+func TestTryFilterTrigram(t *testing.T) {
+	semaCtx := tree.MakeSemaContext()
+	evalCtx := tree.NewTestingEvalContext(nil /* st */)
+
+	tc := testcat.New()
+	if _, err := tc.ExecuteDDL(
+		"CREATE TABLE t (s STRING, u STRING, INVERTED INDEX (s gin_trgm_ops))",
+	); err != nil {
+		t.Fatal(err)
+	}
+	var f norm.Factory
+	f.Init(evalCtx, tc)
+	md := f.Metadata()
+	tn := tree.NewUnqualifiedTableName("t")
+	tab := md.AddTable(tc.Table(tn), tn)
+	trigramOrd := 1
+
+	testCases := []struct {
+		filters   string
+		threshold float64
+		ok        bool
+		// The span expression is never tight, so remainingFilters always
+		// contains the original filters if ok=true.
+		remainingFilters string
+	}{
+		{
+			filters:          "s LIKE '%foo%'",
+			ok:               true,
+			remainingFilters: "s LIKE '%foo%'",
+		},
+		{
+			filters:          "s ILIKE '%FOO%'",
+			ok:               true,
+			remainingFilters: "s ILIKE '%FOO%'",
+		},
+		{
+			// An anchored pattern produces padded trigrams.
+			filters:          "s LIKE 'fo%'",
+			ok:               true,
+			remainingFilters: "s LIKE 'fo%'",
+		},
+		{
+			// There is no trigram in a pattern without a word of at least three
+			// characters, so the index cannot be constrained.
+			filters: "s LIKE '%fo%'",
+			ok:      false,
+		},
+		{
+			filters:          "s % 'foo'",
+			threshold:        0.3,
+			ok:               true,
+			remainingFilters: "s % 'foo'",
+		},
+		{
+			// A similarity threshold of zero matches strings without any common
+			// trigram, so the index cannot be constrained.
+			filters:   "s % 'foo'",
+			threshold: 0,
+			ok:        false,
+		},
+		{
+			// Equality is not supported.
+			filters: "s = 'foo'",
+			ok:      false,
+		},
+		{
+			// Wrong column.
+			filters: "u LIKE '%foo%'",
+			ok:      false,
+		},
+		{
+			filters:          "s LIKE '%foo%' AND s LIKE '%bar%'",
+			ok:               true,
+			remainingFilters: "s LIKE '%foo%' AND s LIKE '%bar%'",
+		},
+		{
+			filters:          "s LIKE '%foo%' AND u LIKE '%bar%'",
+			ok:               true,
+			remainingFilters: "s LIKE '%foo%' AND u LIKE '%bar%'",
+		},
+		{
+			filters:          "s LIKE '%foo%' OR s % 'bar'",
+			threshold:        0.3,
+			ok:               true,
+			remainingFilters: "s LIKE '%foo%' OR s % 'bar'",
+		},
+		{
+			// When operations affecting two different variables are OR-ed, we cannot
+			// constrain the index.
+			filters: "s LIKE '%foo%' OR u LIKE '%bar%'",
+			ok:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("test case: %v", tc)
+		evalCtx.SessionData.TrigramSimilarityThreshold = tc.threshold
+		filters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.filters)
+
+		spanExpr, _, remainingFilters, _, ok := invertedidx.TryFilterInvertedIndex(
+			evalCtx, &f, filters, nil /* optionalFilters */, tab, md.Table(tab).Index(trigramOrd),
+		)
+		if tc.ok != ok {
+			t.Fatalf("expected %v, got %v", tc.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+
+		if spanExpr.Tight {
+			t.Fatalf("expected tight=false, but got true")
+		}
+		expRemainingFilters := testutils.BuildFilters(t, &f, &semaCtx, evalCtx, tc.remainingFilters)
+		if remainingFilters.String() != expRemainingFilters.String() {
+			t.Errorf("expected remainingFilters=%v, got %v", expRemainingFilters, remainingFilters)
+		}
+	}
+}
@@ -628,6 +628,42 @@ func (f *WindowFrame) String() string {
 	return bld.String()
 }
 
+// GroupingSetList is the list of grouping sets of a GroupingSets operator.
+// Each grouping set is a subset of the input grouping columns.
+type GroupingSetList []opt.ColSet
+
+// HasEmptySet returns true if the list contains the empty grouping set.
+func (l GroupingSetList) HasEmptySet() bool {
+	for i := range l {
+		if l[i].Empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsInAll returns true if every grouping set in the list contains the
+// given column.
+func (l GroupingSetList) ContainsInAll(col opt.ColumnID) bool {
+	for i := range l {
+		if !l[i].Contains(col) {
+			return false
+		}
+	}
+	return true
+}
+
+func (l GroupingSetList) String() string {
+	var bld strings.Builder
+	for i := range l {
+		if i > 0 {
+			bld.WriteByte(' ')
+		}
+		bld.WriteString(l[i].String())
+	}
+	return bld.String()
+}
+
 // IsCanonical returns true if the ScanPrivate indicates an original unaltered
 // primary index Scan operator (i.e. unconstrained and not limited).
 func (s *ScanPrivate) IsCanonical() bool {
@@ -935,6 +971,30 @@ type FKCascade struct {
 	// It is empty if the mutation is a deletion. Empty if the cascade does not
 	// require input.
 	NewValues opt.ColList
+
+	// Trigger is true if this entry runs a trigger defined on the mutated table
+	// rather than an FK cascade. In that case, FKName is the name of the
+	// trigger, and OldValues and NewValues map 1-to-1 to the columns of the
+	// "old" and "new" transition relations (either can be empty).
+	Trigger bool
+
+	// Before is true if the trigger runs before the mutation rather than after.
+	// Only set if Trigger is true.
+	Before bool
+
+	// ForEachRow is true if the trigger runs once for each row of the mutation
+	// input rather than once for the entire input. Only set if Trigger is true.
+	ForEachRow bool
+}
+
+// HasBeforeTriggers returns true if any of the entries is a BEFORE trigger.
+func (c FKCascades) HasBeforeTriggers() bool {
+	for i := range c {
+		if c[i].Before {
+			return true
+		}
+	}
+	return false
 }
 
 // CascadeBuilder is an interface used to construct a cascading query for a
@@ -207,7 +207,8 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
-		*CancelSessionsExpr, *CreateViewExpr, *ExportExpr:
+		*CancelSessionsExpr, *CreateViewExpr, *CreateFunctionExpr, *CreateTriggerExpr,
+		*ExportExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -303,6 +304,25 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			tp.Childf("error: \"%s\"", private.ErrorOnDup)
 		}
 
+	case *GroupingSetsExpr:
+		if !f.HasFlags(ExprFmtHideColumns) {
+			if len(t.InputCols) > 0 {
+				child := tp.Child("grouping columns:")
+				for i := range t.InputCols {
+					f.Buffer.Reset()
+					f.formatCol("" /* label */, t.InputCols[i], opt.ColSet{} /* notNullCols */)
+					f.Buffer.WriteString(" => ")
+					f.formatCol("" /* label */, t.OutCols[i], opt.ColSet{} /* notNullCols */)
+					child.Child(f.Buffer.String())
+				}
+			}
+			tp.Childf("grouping sets: %s", t.Sets)
+			f.formatColList(e, tp, "grouping set column:", opt.ColList{t.GroupingSetCol})
+		}
+		if !f.HasFlags(ExprFmtHidePhysProps) && !t.Ordering.Any() {
+			tp.Childf("internal-ordering: %s", t.Ordering)
+		}
+
 	case *LimitExpr:
 		if !f.HasFlags(ExprFmtHidePhysProps) && !t.Ordering.Any() {
 			tp.Childf("internal-ordering: %s", t.Ordering)
@@ -615,6 +635,26 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			}
 			n.Child(f.Buffer.String())
 		}
+		for _, fn := range t.FuncDeps {
+			n.Childf("%s [function]", fn.Name())
+		}
+
+	case *CreateFunctionExpr:
+		tp.Child(t.Body)
+
+		n := tp.Child("dependencies")
+		for _, dep := range t.Deps {
+			f.Buffer.Reset()
+			name := dep.DataSource.Name()
+			f.Buffer.WriteString(name.String())
+			if dep.SpecificIndex {
+				fmt.Fprintf(f.Buffer, "@%s", dep.DataSource.(cat.Table).Index(dep.Index).Name())
+			}
+			n.Child(f.Buffer.String())
+		}
+
+	case *CreateTriggerExpr:
+		tp.Child(t.Action)
 
 	case *CreateStatisticsExpr:
 		tp.Child(t.Syntax.String())
@@ -1217,10 +1257,35 @@ func (f *ExprFmtCtx) formatMutationCommon(tp treeprinter.Node, p *MutationPrivat
 	if p.WithID != 0 {
 		tp.Childf("input binding: &%d", p.WithID)
 	}
-	if len(p.FKCascades) > 0 {
+	hasCascades, hasTriggers := false, false
+	for i := range p.FKCascades {
+		if p.FKCascades[i].Trigger {
+			hasTriggers = true
+		} else {
+			hasCascades = true
+		}
+	}
+	if hasCascades {
 		c := tp.Childf("cascades")
 		for i := range p.FKCascades {
-			c.Child(p.FKCascades[i].FKName)
+			if !p.FKCascades[i].Trigger {
+				c.Child(p.FKCascades[i].FKName)
+			}
+		}
+	}
+	if hasTriggers {
+		c := tp.Childf("triggers")
+		for i := range p.FKCascades {
+			if t := &p.FKCascades[i]; t.Trigger {
+				when, level := "after", "statement"
+				if t.Before {
+					when = "before"
+				}
+				if t.ForEachRow {
+					level = "row"
+				}
+				c.Childf("%s (%s, for each %s)", t.FKName, when, level)
+			}
 		}
 	}
 }
@@ -1340,6 +1405,12 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
 		}
 
+	case *GroupingSetsPrivate:
+		fmt.Fprintf(f.Buffer, " sets=%s", t.Sets)
+		if !t.Ordering.Any() {
+			fmt.Fprintf(f.Buffer, ",ordering=%s", t.Ordering)
+		}
+
 	case *IndexJoinPrivate:
 		tab := f.Memo.metadata.Table(t.Table)
 		fmt.Fprintf(f.Buffer, " %s", tab.Name())
@@ -1406,6 +1477,14 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 		schema := f.Memo.Metadata().Schema(t.Schema)
 		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.ViewName)
 
+	case *CreateFunctionPrivate:
+		schema := f.Memo.Metadata().Schema(t.Schema)
+		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.FuncName.Object())
+
+	case *CreateTriggerPrivate:
+		tab := f.Memo.Metadata().Table(t.Table)
+		fmt.Fprintf(f.Buffer, " %s ON %s", t.Syntax.Name, tab.Name())
+
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
@@ -555,6 +555,14 @@ func (h *hasher) HashViewDeps(val opt.ViewDeps) {
 	}
 }
 
+func (h *hasher) HashFuncDeps(val opt.FuncDeps) {
+	// Hash the length and address of the first element.
+	h.HashInt(len(val))
+	if len(val) > 0 {
+		h.HashPointer(unsafe.Pointer(&val[0]))
+	}
+}
+
 func (h *hasher) HashWindowFrame(val WindowFrame) {
 	h.HashInt(int(val.StartBoundType))
 	h.HashInt(int(val.EndBoundType))
@@ -562,6 +570,21 @@ func (h *hasher) HashWindowFrame(val WindowFrame) {
 	h.HashInt(int(val.FrameExclusion))
 }
 
+func (h *hasher) HashGroupingSetList(val GroupingSetList) {
+	hash := h.hash
+	for i := range val {
+		// Include the length of each grouping set, so that (1) (2) and (1,2) ()
+		// hash differently.
+		hash ^= internHash(val[i].Len())
+		hash *= prime64
+		for c, ok := val[i].Next(0); ok; c, ok = val[i].Next(c + 1) {
+			hash ^= internHash(c)
+			hash *= prime64
+		}
+	}
+	h.hash = hash
+}
+
 func (h *hasher) HashTupleOrdinal(val TupleOrdinal) {
 	h.HashUint64(uint64(val))
 }
@@ -945,6 +968,13 @@ func (h *hasher) IsViewDepsEqual(l, r opt.ViewDeps) bool {
 	return len(l) == 0 || &l[0] == &r[0]
 }
 
+func (h *hasher) IsFuncDepsEqual(l, r opt.FuncDeps) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	return len(l) == 0 || &l[0] == &r[0]
+}
+
 func (h *hasher) IsWindowFrameEqual(l, r WindowFrame) bool {
 	return l.StartBoundType == r.StartBoundType &&
 		l.EndBoundType == r.EndBoundType &&
@@ -952,6 +982,18 @@ func (h *hasher) IsWindowFrameEqual(l, r WindowFrame) bool {
 		l.FrameExclusion == r.FrameExclusion
 }
 
+func (h *hasher) IsGroupingSetListEqual(l, r GroupingSetList) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		if !l[i].Equals(r[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func (h *hasher) IsTupleOrdinalEqual(l, r TupleOrdinal) bool {
 	return l == r
 }
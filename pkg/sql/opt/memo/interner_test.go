@@ -438,6 +438,14 @@ func TestInterner(t *testing.T) {
 			},
 		}},
 
+		{hashFn: in.hasher.HashGroupingSetList, eqFn: in.hasher.IsGroupingSetListEqual, variations: []testVariation{
+			{val1: GroupingSetList{}, val2: GroupingSetList{}, equal: true},
+			{val1: GroupingSetList{opt.MakeColSet(1, 2), opt.ColSet{}}, val2: GroupingSetList{opt.MakeColSet(1, 2), opt.ColSet{}}, equal: true},
+			{val1: GroupingSetList{opt.MakeColSet(1, 2), opt.ColSet{}}, val2: GroupingSetList{opt.MakeColSet(1), opt.MakeColSet(2)}, equal: false},
+			{val1: GroupingSetList{opt.MakeColSet(1), opt.ColSet{}}, val2: GroupingSetList{opt.ColSet{}, opt.MakeColSet(1)}, equal: false},
+			{val1: GroupingSetList{opt.MakeColSet(1)}, val2: GroupingSetList{opt.MakeColSet(1), opt.MakeColSet(1)}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashTupleOrdinal, eqFn: in.hasher.IsTupleOrdinalEqual, variations: []testVariation{
 			{val1: TupleOrdinal(0), val2: TupleOrdinal(0), equal: true},
 			{val1: TupleOrdinal(0), val2: TupleOrdinal(1), equal: false},
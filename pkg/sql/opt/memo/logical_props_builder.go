@@ -640,6 +640,101 @@ func (b *logicalPropsBuilder) buildGroupingExprProps(groupExpr RelExpr, rel *pro
 	}
 }
 
+func (b *logicalPropsBuilder) buildGroupingSetsProps(
+	groupingSets *GroupingSetsExpr, rel *props.Relational,
+) {
+	BuildSharedProps(groupingSets, &rel.Shared)
+
+	inputProps := groupingSets.Input.Relational()
+	aggs := groupingSets.Aggregations
+	private := &groupingSets.GroupingSetsPrivate
+	hasEmptySet := private.Sets.HasEmptySet()
+
+	// Output Columns
+	// --------------
+	// Output columns are the union of the output grouping columns, the grouping
+	// set column and the columns from the aggregate projection list.
+	rel.OutputCols = private.OutCols.ToSet()
+	rel.OutputCols.Add(private.GroupingSetCol)
+	for i := range aggs {
+		rel.OutputCols.Add(aggs[i].Col)
+	}
+
+	// Not Null Columns
+	// ----------------
+	// An output grouping column is NULL in the rows of the grouping sets that
+	// don't contain it, so it is only not null if its input column is not null
+	// and is part of every grouping set.
+	rel.NotNullCols.Add(private.GroupingSetCol)
+	for i, col := range private.InputCols {
+		if inputProps.NotNullCols.Contains(col) && private.Sets.ContainsInAll(col) {
+			rel.NotNullCols.Add(private.OutCols[i])
+		}
+	}
+
+	for i := range aggs {
+		item := &aggs[i]
+		agg := ExtractAggFunc(item.Agg)
+
+		// Some aggregates never return NULL, regardless of input.
+		if opt.AggregateIsNeverNull(agg.Op()) {
+			rel.NotNullCols.Add(item.Col)
+			continue
+		}
+
+		// The empty grouping set is aggregated like a ScalarGroupBy, so it may
+		// have zero input rows and return NULL. This is also possible with
+		// AggFilter.
+		if hasEmptySet || item.Agg.Op() == opt.AggFilterOp {
+			continue
+		}
+
+		if opt.AggregateIsNeverNullOnNonNullInput(agg.Op()) {
+			inputCols := ExtractAggInputColumns(agg)
+			if inputCols.SubsetOf(inputProps.NotNullCols) {
+				rel.NotNullCols.Add(item.Col)
+			}
+		}
+	}
+
+	// Outer Columns
+	// -------------
+	// Outer columns were derived by BuildSharedProps; remove any that are bound
+	// by input columns.
+	rel.OuterCols.DifferenceWith(inputProps.OutputCols)
+
+	// Functional Dependencies
+	// -----------------------
+	// None of the input dependencies are retained, since the output grouping
+	// columns are different from the input columns. Within the rows of a
+	// grouping set there are no duplicates in the grouping columns, and the
+	// grouping columns which are not part of the set are NULL, so the output
+	// grouping columns together with the grouping set column form a strict key.
+	key := private.OutCols.ToSet()
+	key.Add(private.GroupingSetCol)
+	rel.FuncDeps.AddStrictKey(key, rel.OutputCols)
+
+	// Cardinality
+	// -----------
+	// The empty grouping set always returns exactly one row. The other grouping
+	// sets act like a GroupBy, never returning more rows than the input has, but
+	// at least one row if the input has at least one row.
+	rel.Cardinality = props.ZeroCardinality
+	for i := range private.Sets {
+		if private.Sets[i].Empty() {
+			rel.Cardinality = rel.Cardinality.Add(props.OneCardinality)
+		} else {
+			rel.Cardinality = rel.Cardinality.Add(inputProps.Cardinality.AsLowAs(1))
+		}
+	}
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildGroupingSets(groupingSets, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildUnionProps(union *UnionExpr, rel *props.Relational) {
 	b.buildSetProps(union, rel)
 }
@@ -1396,6 +1491,18 @@ func (b *logicalPropsBuilder) buildCreateViewProps(cv *CreateViewExpr, rel *prop
 	BuildSharedProps(cv, &rel.Shared)
 }
 
+func (b *logicalPropsBuilder) buildCreateFunctionProps(
+	cf *CreateFunctionExpr, rel *props.Relational,
+) {
+	BuildSharedProps(cf, &rel.Shared)
+}
+
+func (b *logicalPropsBuilder) buildCreateTriggerProps(
+	ct *CreateTriggerExpr, rel *props.Relational,
+) {
+	BuildSharedProps(ct, &rel.Shared)
+}
+
 func (b *logicalPropsBuilder) buildFiltersItemProps(item *FiltersItem, scalar *props.Scalar) {
 	BuildSharedProps(item.Condition, &scalar.Shared)
 
@@ -131,13 +131,14 @@ type Memo struct {
 
 	// The following are selected fields from SessionData which can affect
 	// planning. We need to cross-check these before reusing a cached memo.
-	reorderJoinsLimit       int
-	zigzagJoinEnabled       bool
-	useHistograms           bool
-	useMultiColStats        bool
-	safeUpdates             bool
-	preferLookupJoinsForFKs bool
-	saveTablesPrefix        string
+	reorderJoinsLimit          int
+	zigzagJoinEnabled          bool
+	useHistograms              bool
+	useMultiColStats           bool
+	safeUpdates                bool
+	preferLookupJoinsForFKs    bool
+	saveTablesPrefix           string
+	trigramSimilarityThreshold float64
 
 	// curID is the highest currently in-use scalar expression ID.
 	curID opt.ScalarID
@@ -171,6 +172,7 @@ func (m *Memo) Init(evalCtx *tree.EvalContext) {
 	m.safeUpdates = evalCtx.SessionData.SafeUpdates
 	m.preferLookupJoinsForFKs = evalCtx.SessionData.PreferLookupJoinsForFKs
 	m.saveTablesPrefix = evalCtx.SessionData.SaveTablesPrefix
+	m.trigramSimilarityThreshold = evalCtx.SessionData.TrigramSimilarityThreshold
 
 	m.curID = 0
 	m.curWithID = 0
@@ -277,7 +279,8 @@ func (m *Memo) IsStale(
 		m.useMultiColStats != evalCtx.SessionData.OptimizerUseMultiColStats ||
 		m.safeUpdates != evalCtx.SessionData.SafeUpdates ||
 		m.preferLookupJoinsForFKs != evalCtx.SessionData.PreferLookupJoinsForFKs ||
-		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix {
+		m.saveTablesPrefix != evalCtx.SessionData.SaveTablesPrefix ||
+		m.trigramSimilarityThreshold != evalCtx.SessionData.TrigramSimilarityThreshold {
 		return true, nil
 	}
 
@@ -377,6 +377,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 		opt.UpsertDistinctOnOp, opt.EnsureUpsertDistinctOnOp:
 		return sb.colStatGroupBy(colSet, e)
 
+	case opt.GroupingSetsOp:
+		return sb.colStatGroupingSets(colSet, e.(*GroupingSetsExpr))
+
 	case opt.LimitOp:
 		return sb.colStatLimit(colSet, e.(*LimitExpr))
 
@@ -1809,6 +1812,94 @@ func (sb *statisticsBuilder) colStatGroupBy(
 	return colStat
 }
 
+// +---------------+
+// | Grouping Sets |
+// +---------------+
+
+func (sb *statisticsBuilder) buildGroupingSets(
+	groupingSets *GroupingSetsExpr, relProps *props.Relational,
+) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+	s.Available = sb.availabilityFromInput(groupingSets)
+
+	// Each grouping set is estimated like a GroupBy on its grouping columns,
+	// or like a ScalarGroupBy if it is empty.
+	inputStats := sb.statsFromChild(groupingSets, 0 /* childIdx */)
+	s.RowCount = 0
+	for _, set := range groupingSets.Sets {
+		if set.Empty() {
+			s.RowCount++
+			continue
+		}
+		colStat := sb.colStatFromChild(set, groupingSets, 0 /* childIdx */)
+		s.RowCount += min(colStat.DistinctCount, inputStats.RowCount)
+	}
+
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatGroupingSets(
+	colSet opt.ColSet, groupingSets *GroupingSetsExpr,
+) *props.ColumnStatistic {
+	relProps := groupingSets.Relational()
+	s := &relProps.Stats
+	private := &groupingSets.GroupingSetsPrivate
+	numSets := float64(len(private.Sets))
+
+	// Map the requested output grouping columns to the input grouping columns.
+	var inputCols, groupingCols opt.ColSet
+	for i, col := range private.OutCols {
+		if colSet.Contains(col) {
+			inputCols.Add(private.InputCols[i])
+			groupingCols.Add(col)
+		}
+	}
+	groupingCols.Add(private.GroupingSetCol)
+
+	colStat, _ := s.ColStats.Add(colSet)
+	switch {
+	case !colSet.SubsetOf(groupingCols):
+		// Some of the requested columns are aggregates. Estimate the distinct
+		// count to be the same as the row count.
+		colStat.DistinctCount = s.RowCount
+		colStat.NullCount = 0
+
+	case inputCols.Empty():
+		// Only the grouping set column was requested.
+		colStat.DistinctCount = numSets
+		colStat.NullCount = 0
+
+	default:
+		// The output grouping columns have the distinct values of the input
+		// grouping columns, plus the NULLs of the grouping sets which don't
+		// contain them. Each grouping set has at most one group with NULL input
+		// values.
+		inputColStat := sb.colStatFromChild(inputCols, groupingSets, 0 /* childIdx */)
+		colStat.DistinctCount = inputColStat.DistinctCount + 1
+		colStat.NullCount = 0
+		for i := range private.Sets {
+			if inputCols.SubsetOf(private.Sets[i]) {
+				colStat.NullCount += min(1, inputColStat.NullCount)
+			} else {
+				colStat.NullCount += s.RowCount / numSets
+			}
+		}
+		if colSet.Contains(private.GroupingSetCol) {
+			colStat.DistinctCount *= numSets
+		}
+	}
+
+	if colSet.Intersects(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +--------+
 // | Set Op |
 // +--------+
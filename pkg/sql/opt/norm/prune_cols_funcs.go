@@ -25,6 +25,12 @@ func (c *CustomFuncs) NeededGroupingCols(private *memo.GroupingPrivate) opt.ColS
 	return private.GroupingCols.Union(private.Ordering.ColSet())
 }
 
+// NeededGroupingSetsCols returns the columns needed by a GroupingSets
+// operator's grouping columns or requested ordering.
+func (c *CustomFuncs) NeededGroupingSetsCols(private *memo.GroupingSetsPrivate) opt.ColSet {
+	return private.InputCols.ToSet().Union(private.Ordering.ColSet())
+}
+
 // NeededOrdinalityCols returns the columns needed by a Ordinality operator's
 // requested ordering.
 func (c *CustomFuncs) NeededOrdinalityCols(private *memo.OrdinalityPrivate) opt.ColSet {
@@ -68,6 +74,14 @@ func (c *CustomFuncs) NeededMutationCols(
 		cols.Add(private.CanaryCol)
 	}
 
+	// Triggers read the "old" and "new" rows from the buffered input.
+	for i := range private.FKCascades {
+		if private.FKCascades[i].Trigger {
+			addCols(opt.OptionalColList(private.FKCascades[i].OldValues))
+			addCols(opt.OptionalColList(private.FKCascades[i].NewValues))
+		}
+	}
+
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
 			withUses := memo.WithUses(uniqueChecks[i].Check)
@@ -141,6 +155,23 @@ func neededMutationFetchCols(
 		}
 	}
 
+	// Retain any FetchCols that triggers read as part of the "old" row (or the
+	// "new" row, for columns that an UPDATE does not modify).
+	var triggerCols opt.ColSet
+	for i := range private.FKCascades {
+		if c := &private.FKCascades[i]; c.Trigger {
+			triggerCols.UnionWith(c.OldValues.ToSet())
+			triggerCols.UnionWith(c.NewValues.ToSet())
+		}
+	}
+	if !triggerCols.Empty() {
+		for ord, col := range private.FetchCols {
+			if col != 0 && triggerCols.Contains(col) {
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -528,6 +559,14 @@ func DerivePruneCols(e memo.RelExpr) opt.ColSet {
 			relProps.Rule.PruneCols = relProps.OutputCols.Difference(groupingColSet)
 		}
 
+	case opt.GroupingSetsOp:
+		// Neither the grouping columns nor the grouping set column can be pruned,
+		// since they identify each output row. Aggregation columns can
+		// potentially be pruned.
+		private := e.Private().(*memo.GroupingSetsPrivate)
+		relProps.Rule.PruneCols = relProps.OutputCols.Difference(private.OutCols.ToSet())
+		relProps.Rule.PruneCols.Remove(private.GroupingSetCol)
+
 	case opt.LimitOp, opt.OffsetOp:
 		// Any pruneable input columns can potentially be pruned, as long as
 		// they're not used as an ordering column.
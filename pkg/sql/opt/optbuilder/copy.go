@@ -0,0 +1,106 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// buildCopyTo builds a COPY TO statement. The rows of the table or query are
+// produced like those of a regular query; it is up to the client connection
+// to encode them according to the COPY options.
+func (b *Builder) buildCopyTo(copyTo *tree.CopyTo, inScope *scope) (outScope *scope) {
+	if err := checkCopyToOptions(&copyTo.Options); err != nil {
+		panic(err)
+	}
+
+	stmt := copyTo.Statement
+	if stmt == nil {
+		exprs := tree.SelectExprs{tree.StarSelectExpr()}
+		if len(copyTo.Columns) > 0 {
+			exprs = make(tree.SelectExprs, len(copyTo.Columns))
+			for i, col := range copyTo.Columns {
+				exprs[i].Expr = tree.NewUnresolvedName(string(col))
+			}
+		}
+		stmt = &tree.Select{Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: tree.TableExprs{&copyTo.Table}},
+		}}
+	} else if stmt.StatementType() != tree.Rows {
+		switch stmt.(type) {
+		case *tree.Insert, *tree.Update, *tree.Delete:
+			panic(pgerror.New(pgcode.FeatureNotSupported, "COPY query must have a RETURNING clause"))
+		default:
+			panic(pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s is not supported as a COPY query", stmt.StatementTag()))
+		}
+	}
+	return b.buildStmt(stmt, nil /* desiredTypes */, inScope)
+}
+
+// checkCopyToOptions verifies that the options of a COPY TO statement are
+// compatible with each other, using the same rules as Postgres.
+func checkCopyToOptions(o *tree.CopyOptions) error {
+	if o.Destination != nil {
+		return pgerror.New(pgcode.Syntax, "DESTINATION option is not supported by COPY TO")
+	}
+	if o.CopyFormat == tree.CopyFormatBinary {
+		switch {
+		case o.Delimiter != nil:
+			return pgerror.New(pgcode.Syntax, "cannot specify DELIMITER in BINARY mode")
+		case o.Null != nil:
+			return pgerror.New(pgcode.Syntax, "cannot specify NULL in BINARY mode")
+		}
+	}
+	if o.Header && o.CopyFormat != tree.CopyFormatCSV {
+		return pgerror.New(pgcode.FeatureNotSupported, "COPY HEADER available only in CSV mode")
+	}
+	delimiter := "\t"
+	if o.CopyFormat == tree.CopyFormatCSV {
+		delimiter = ","
+	}
+	if o.Delimiter != nil {
+		delimiter = o.Delimiter.(*tree.StrVal).RawString()
+		if len(delimiter) != 1 {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"COPY delimiter must be a single one-byte character")
+		}
+		if delimiter == "\n" || delimiter == "\r" {
+			return pgerror.New(pgcode.InvalidParameterValue,
+				"COPY delimiter cannot be newline or carriage return")
+		}
+		if o.CopyFormat == tree.CopyFormatCSV && delimiter == `"` {
+			return pgerror.New(pgcode.InvalidParameterValue,
+				"COPY delimiter and quote must be different")
+		}
+		if o.CopyFormat != tree.CopyFormatCSV &&
+			strings.Contains(`\.abcdefghijklmnopqrstuvwxyz0123456789`, delimiter) {
+			return pgerror.Newf(pgcode.InvalidParameterValue, "COPY delimiter cannot be %q", delimiter)
+		}
+	}
+	if o.Null != nil {
+		null := o.Null.(*tree.StrVal).RawString()
+		if strings.ContainsAny(null, "\r\n") {
+			return pgerror.New(pgcode.InvalidParameterValue,
+				"COPY null representation cannot use newline or carriage return")
+		}
+		if strings.Contains(null, delimiter) {
+			return pgerror.New(pgcode.InvalidParameterValue,
+				"COPY delimiter must not appear in the NULL specification")
+		}
+	}
+	return nil
+}
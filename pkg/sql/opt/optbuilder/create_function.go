@@ -0,0 +1,189 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+func (b *Builder) buildCreateFunction(cf *tree.CreateFunction, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	fnName := cf.FuncName.ToTableName()
+	sch, resName := b.resolveSchemaForCreate(&fnName)
+	schID := b.factory.Metadata().AddSchema(sch)
+	fnName = tree.MakeTableNameFromPrefix(resName, tree.Name(cf.FuncName.Object()))
+
+	if lang := cf.Options.Language; lang != "" && !strings.EqualFold(lang, "sql") {
+		panic(unimplemented.NewWithIssuef(17511, "CREATE FUNCTION ... LANGUAGE %s", lang))
+	}
+	if cf.Options.Body == nil {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified"))
+	}
+	// Unqualified calls always resolve to builtins first, so a function with
+	// the same name as a builtin could only be called with a qualified name.
+	if _, ok := tree.FunDefs[fnName.Object()]; ok {
+		panic(pgerror.Newf(pgcode.DuplicateFunction,
+			"function %s conflicts with a built-in function", tree.ErrString(&fnName)))
+	}
+
+	paramTypes := make([]*types.T, len(cf.Params))
+	paramNames := make(map[tree.Name]struct{}, len(cf.Params))
+	for i := range cf.Params {
+		paramTypes[i] = resolveFunctionType(cf.Params[i].Type)
+		if name := cf.Params[i].Name; name != "" {
+			if _, ok := paramNames[name]; ok {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", name))
+			}
+			paramNames[name] = struct{}{}
+		}
+	}
+	retType := resolveFunctionType(cf.ReturnType)
+
+	stmt, err := parser.ParseOne(*cf.Options.Body)
+	if err != nil {
+		panic(pgerror.Wrap(err, pgcode.Syntax, "failed to parse function body"))
+	}
+	body, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(unimplemented.Newf("udf-body",
+			"function body must be a single SELECT statement, found %s", stmt.AST.StatementTag()))
+	}
+	if stmt.NumPlaceholders > len(cf.Params) {
+		panic(pgerror.Newf(pgcode.UndefinedParameter,
+			"there is no parameter $%d", stmt.NumPlaceholders))
+	}
+
+	// We build the body of the function to:
+	//  - check the body semantically,
+	//  - get the fully resolved names into the AST, and
+	//  - collect the dependencies of the function in b.viewDeps.
+	// The result is not otherwise used.
+	b.insideViewDef = true
+	b.insideFuncDef = true
+	b.trackViewDeps = true
+	b.qualifyDataSourceNamesInAST = true
+	defer func(placeholders tree.PlaceholderInfo) {
+		b.insideViewDef = false
+		b.insideFuncDef = false
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.qualifyDataSourceNamesInAST = false
+		b.semaCtx.Placeholders = placeholders
+	}(b.semaCtx.Placeholders)
+
+	// Parameters can be referenced by position.
+	if err := b.semaCtx.Placeholders.Init(len(paramTypes), nil /* typeHints */); err != nil {
+		panic(err)
+	}
+	copy(b.semaCtx.Placeholders.Types, paramTypes)
+
+	// Parameters can also be referenced by name, optionally qualified by the
+	// name of the function. Build the parameters as an outer scope of the body.
+	bodyScope := inScope
+	if len(cf.Params) > 0 {
+		params := make(tree.SelectExprs, len(cf.Params))
+		for i := range cf.Params {
+			params[i] = tree.SelectExpr{
+				Expr: &tree.CastExpr{Expr: tree.DNull, Type: paramTypes[i]},
+				As:   tree.UnrestrictedName(cf.Params[i].Name),
+			}
+			if params[i].As == "" {
+				params[i].As = tree.UnrestrictedName(tree.PlaceholderIdx(i).String())
+			}
+		}
+		paramScope := b.buildStmt(
+			&tree.Select{Select: &tree.SelectClause{Exprs: params}}, nil /* desiredTypes */, inScope,
+		)
+		b.renameSource(tree.AliasClause{Alias: fnName.ObjectName}, paramScope)
+		bodyScope = paramScope.push()
+	}
+
+	b.pushWithFrame()
+	defScope := b.buildStmtAtRoot(body, []*types.T{retType}, bodyScope)
+	b.popWithFrame(defScope)
+
+	p := defScope.makePhysicalProps().Presentation
+	if len(p) != 1 {
+		panic(errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function declared to return %s", retType.SQLString()),
+			"Function body must return exactly one column.",
+		))
+	}
+	if typ := b.factory.Metadata().ColumnMeta(p[0].ID).Type; !typ.Equivalent(retType) &&
+		typ.Family() != types.UnknownFamily {
+		panic(errors.WithDetailf(
+			pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function declared to return %s", retType.SQLString()),
+			"Actual return type is %s.", typ.SQLString(),
+		))
+	}
+
+	// The optimizer inlines and folds calls to IMMUTABLE and STABLE functions,
+	// so the body must not be more volatile than declared.
+	volatility := cf.Options.Volatility
+	if volatility == 0 {
+		volatility = tree.VolatilityVolatile
+	}
+	vs := defScope.expr.Relational().VolatilitySet
+	switch volatility {
+	case tree.VolatilityImmutable:
+		if len(b.viewDeps) > 0 || vs.HasStable() || vs.HasVolatile() {
+			panic(errors.WithHint(
+				pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"IMMUTABLE function %s must not read tables or call non-immutable functions",
+					tree.ErrString(&fnName)),
+				"Declare the function STABLE or VOLATILE.",
+			))
+		}
+	case tree.VolatilityStable:
+		if vs.HasVolatile() {
+			panic(errors.WithHint(
+				pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"STABLE function %s must not call volatile functions", tree.ErrString(&fnName)),
+				"Declare the function VOLATILE.",
+			))
+		}
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema:   schID,
+			FuncName: &fnName,
+			Syntax:   cf,
+			Body:     tree.AsStringWithFlags(body, tree.FmtParsable),
+			Deps:     b.viewDeps,
+		},
+	)
+	return outScope
+}
+
+// resolveFunctionType returns the type of a parameter or of the result of a
+// user-defined function.
+func resolveFunctionType(ref tree.ResolvableTypeReference) *types.T {
+	typ, ok := tree.GetStaticallyKnownType(ref)
+	if !ok {
+		panic(unimplemented.NewWithIssue(17511,
+			"user-defined types are not supported as parameter or return types of functions"))
+	}
+	return typ
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+func (b *Builder) buildCreateTrigger(ct *tree.CreateTrigger, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := ct.Table
+	tab, resName := b.resolveTable(&tn, privilege.CREATE)
+	if tab.IsVirtualTable() || tab.IsMaterializedView() {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a table", tree.ErrString(&resName)))
+	}
+	tabMeta := b.addTable(tab, &resName)
+
+	if ct.Constraint && (ct.ActionTime != tree.TriggerAfter || !ct.ForEachRow) {
+		panic(pgerror.New(pgcode.InvalidObjectDefinition,
+			"constraint triggers must be AFTER ROW triggers"))
+	}
+
+	stmt, err := parser.ParseOne(ct.Action)
+	if err != nil {
+		panic(pgerror.Wrap(err, pgcode.Syntax, "failed to parse trigger action"))
+	}
+	switch stmt.AST.(type) {
+	case *tree.Select, *tree.Insert, *tree.Update, *tree.Delete:
+	default:
+		panic(unimplemented.NewWithIssuef(28296,
+			"trigger action must be a SELECT, INSERT, UPDATE or DELETE statement, found %s",
+			stmt.AST.StatementTag()))
+	}
+	if stmt.NumPlaceholders > 0 {
+		panic(pgerror.New(pgcode.InvalidObjectDefinition,
+			"trigger action cannot contain placeholders"))
+	}
+
+	// The "new" relation is available if every event provides new rows, and
+	// the "old" relation if every event provides old rows.
+	hasNew, hasOld := true, true
+	for _, e := range ct.Events {
+		switch e {
+		case tree.TriggerEventInsert:
+			hasOld = false
+		case tree.TriggerEventDelete:
+			hasNew = false
+		}
+	}
+
+	// We build the action to:
+	//  - check it semantically, and
+	//  - get the fully resolved names of the data sources it reads into the
+	//    AST.
+	// The result is not otherwise used. The transition relations are built as
+	// CTEs over a scan of the table, which has the same columns.
+	b.qualifyDataSourceNamesInAST = true
+	defer func() {
+		b.qualifyDataSourceNamesInAST = false
+	}()
+
+	actionScope := inScope.push()
+	if hasNew || hasOld {
+		scanScope := b.buildScan(
+			tabMeta,
+			triggerColumnOrdinals(tab),
+			nil, /* indexFlags */
+			noRowLocking,
+			inScope,
+		)
+		cols := make(opt.ColList, len(scanScope.cols))
+		for i := range scanScope.cols {
+			cols[i] = scanScope.cols[i].id
+		}
+		binding := b.factory.Memo().NextWithID()
+		b.factory.Metadata().AddWithBinding(binding, scanScope.expr)
+		var oldCols, newCols opt.ColList
+		if hasOld {
+			oldCols = cols
+		}
+		if hasNew {
+			newCols = cols
+		}
+		b.addTriggerTransitionRelations(actionScope, tab, binding, oldCols, newCols)
+	}
+	b.pushWithFrame()
+	defScope := b.buildStmtAtRoot(stmt.AST, nil /* desiredTypes */, actionScope)
+	b.popWithFrame(defScope)
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateTrigger(
+		&memo.CreateTriggerPrivate{
+			Table:  tabMeta.MetaID,
+			Syntax: ct,
+			Action: tree.AsStringWithFlags(stmt.AST, tree.FmtParsable),
+		},
+	)
+	return outScope
+}
@@ -51,6 +51,7 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "delete", tab, alias)
+	mb.rlsReturning = resultsNeeded(del.Returning)
 
 	// Build the input expression that selects the rows that will be deleted:
 	//
@@ -76,6 +77,8 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 func (mb *mutationBuilder) buildDelete(returning tree.ReturningExprs) {
 	mb.buildFKChecksAndCascadesForDelete()
 
+	mb.buildTriggers(tree.TriggerEventDelete)
+
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols(mb.fetchScope)
 
@@ -34,6 +34,9 @@ package optbuilder
 //   pre-projection:  k+3 (as col1), v*2 (as col2)
 //   aggregation:     group by col1, calculate MIN(col2) (as col3)
 //   post-projection: 1 + col3
+//
+// If the GROUP BY has GROUPING SETS, ROLLUP or CUBE items, the aggregation is
+// built as a GroupingSets expression. See constructGroupingSets for details.
 
 import (
 	"context"
@@ -45,6 +48,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,8 +104,34 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets contains the grouping sets of a GROUP BY clause with
+	// GROUPING SETS, ROLLUP or CUBE items. Each grouping set contains the
+	// ordinals of its columns among the grouping columns (see groupingCols). It
+	// is nil for an ordinary GROUP BY, which has a single grouping set
+	// containing all the grouping columns.
+	groupingSets []util.FastIntSet
+
+	// groupingSetCol is an additional column produced by the aggregation when
+	// there are groupingSets. It contains the ordinal of the grouping set of
+	// each row, and is used to build calls to the GROUPING function.
+	groupingSetCol opt.ColumnID
 }
 
+const (
+	// maxGroupingSets is the maximum number of grouping sets in a GROUP BY
+	// clause. It is the same as the limit in Postgres.
+	maxGroupingSets = 4096
+
+	// maxCubeElements is the maximum number of elements of a CUBE, which denotes
+	// 2^maxCubeElements grouping sets. It is the same as the limit in Postgres.
+	maxCubeElements = 12
+
+	// maxGroupingFuncArgs is the maximum number of arguments to the GROUPING
+	// function, so that the result fits in a 32-bit integer like in Postgres.
+	maxGroupingFuncArgs = 31
+)
+
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
 // grouping column in an aggOutScope scope that projects that expression. It
 // is used to enforce scoping rules, since any non-aggregate, variable
@@ -134,6 +164,17 @@ func (g *groupby) groupingCols() []scopeColumn {
 	return g.aggInScope.cols[len(g.aggInScope.cols)-len(g.groupStrs):]
 }
 
+// groupingColOrdinal returns the ordinal of the grouping column with the given
+// ID among the given grouping columns, or -1 if there is none.
+func groupingColOrdinal(groupingCols []scopeColumn, id opt.ColumnID) int {
+	for i := range groupingCols {
+		if groupingCols[i].id == id {
+			return i
+		}
+	}
+	return -1
+}
+
 // getAggregateArgCols returns the columns in the aggInScope corresponding to
 // arguments to aggregate functions. If the aggregate has a filter, the column
 // corresponding to the filter's input will immediately follow the arguments.
@@ -336,6 +377,30 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
+
+	if g.groupingSets != nil {
+		b.buildGroupingSetCols(g)
+	}
+}
+
+// buildGroupingSetCols synthesizes the grouping columns produced by an
+// aggregation with grouping sets. Since a grouping column is NULL in the rows
+// of the grouping sets that do not contain it, it cannot have the same ID as
+// the corresponding input column, as it does for an ordinary GROUP BY. Instead,
+// the grouping columns in aggOutScope are given new IDs, and groupStrs is
+// updated to refer to them.
+func (b *Builder) buildGroupingSetCols(g *groupby) {
+	md := b.factory.Metadata()
+	inCols := g.groupingCols()
+	outCols := g.aggOutScope.cols[len(g.aggOutScope.cols)-len(inCols):]
+	for i := range outCols {
+		outCols[i].id = md.AddColumn(string(outCols[i].name), outCols[i].typ)
+		outCols[i].scalar = nil
+	}
+	for exprStr, col := range g.groupStrs {
+		g.groupStrs[exprStr] = &outCols[groupingColOrdinal(inCols, col.id)]
+	}
+	g.groupingSetCol = md.AddColumn("grouping_set", types.Int)
 }
 
 // buildAggregation builds the aggregation operators and constructs the
@@ -354,6 +419,9 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil {
+			return b.buildOrderedGroupingSets(groupingCols, having, fromScope)
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -430,12 +498,16 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+	if g.groupingSets != nil {
+		g.aggOutScope.expr = b.constructGroupingSets(g, groupingCols, g.aggInScope.ordering)
+	} else {
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -447,6 +519,121 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	return g.aggOutScope
 }
 
+// buildOrderedGroupingSets builds the aggregation for a GROUP BY clause with
+// grouping sets, when some of the aggregates are ordering sensitive and have
+// an ORDER BY clause. Unlike an ordinary GROUP BY (see
+// buildAggregationAsWindow), the aggregates cannot be computed as window
+// functions partitioned by the grouping columns, since the groups are
+// different for each grouping set. Instead, the ordering is used as the
+// intra-group ordering of the GroupingSets operator, which requires all the
+// ordered aggregates to have the same ordering.
+//
+// groupingCols contains the grouping columns in aggInScope; they are passed in
+// since columns may be appended to aggInScope after them.
+func (b *Builder) buildOrderedGroupingSets(
+	groupingCols []scopeColumn, having opt.ScalarExpr, fromScope *scope,
+) *scope {
+	g := fromScope.groupby
+
+	// As in buildAggregationAsWindow, the arguments and orderings of the
+	// aggregates are built over the FROM columns, which are all passed through
+	// the pre-projection.
+	g.aggInScope.appendColumnsFromScope(fromScope)
+
+	var ordering opt.Ordering
+	aggCols := g.aggregateResultCols()
+	for i, agg := range g.aggs {
+		args := b.buildWindowArgs(getTypedExprs(agg.Exprs), i, agg.def.Name, fromScope, g.aggInScope)
+		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		if agg.distinct {
+			aggCols[i].scalar = b.factory.ConstructAggDistinct(aggCols[i].scalar)
+		}
+		if agg.Filter != nil {
+			col := b.buildFilterCol(agg.Filter, i, agg.def.Name, fromScope, g.aggInScope)
+			aggCols[i].scalar = b.factory.ConstructAggFilter(
+				aggCols[i].scalar, b.factory.ConstructVariable(col.id),
+			)
+		}
+
+		if !agg.isCommutative() {
+			ord := b.buildWindowOrdering(agg.OrderBy, i, agg.def.Name, fromScope, g.aggInScope)
+			if ordering == nil {
+				ordering = ord
+			} else if !ordering.Equals(ord) {
+				panic(pgerror.New(pgcode.FeatureNotSupported,
+					"ordered aggregates with different orderings are not supported with "+
+						"GROUPING SETS, ROLLUP or CUBE"))
+			}
+		}
+	}
+
+	b.constructProjectForScope(fromScope, g.aggInScope)
+	g.aggOutScope.expr = b.constructGroupingSets(g, groupingCols, ordering)
+
+	// Wrap with having filter if it exists.
+	if having != nil {
+		input := g.aggOutScope.expr.(memo.RelExpr)
+		filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(having)}
+		g.aggOutScope.expr = b.factory.ConstructSelect(input, filters)
+	}
+
+	return g.aggOutScope
+}
+
+// constructGroupingSets constructs the GroupingSets expression for a GROUP BY
+// clause with grouping sets. The input of the aggregation is the
+// pre-projection in aggInScope, and groupingCols contains the grouping columns
+// in aggInScope. The given ordering is only used for intra-group ordering (for
+// order-sensitive aggregations like ARRAY_AGG).
+func (b *Builder) constructGroupingSets(
+	g *groupby, groupingCols []scopeColumn, ordering opt.Ordering,
+) memo.RelExpr {
+	// Deduplicate the aggregation columns, like constructGroupBy.
+	aggCols := g.aggregateResultCols()
+	aggs := make(memo.AggregationsExpr, 0, len(aggCols))
+	var aggColSet opt.ColSet
+	for i := range aggCols {
+		if id, scalar := aggCols[i].id, aggCols[i].scalar; !aggColSet.Contains(id) {
+			if scalar == nil {
+				panic(errors.AssertionFailedf("variable as aggregation"))
+			}
+			aggs = append(aggs, b.factory.ConstructAggregationsItem(scalar, id))
+			aggColSet.Add(id)
+		}
+	}
+
+	// The output grouping columns follow the aggregates in aggOutScope (see
+	// buildGroupingSetCols).
+	outCols := g.aggOutScope.cols[len(aggCols) : len(aggCols)+len(groupingCols)]
+	private := memo.GroupingSetsPrivate{
+		InputCols:      make(opt.ColList, len(groupingCols)),
+		OutCols:        make(opt.ColList, len(groupingCols)),
+		Sets:           make(memo.GroupingSetList, len(g.groupingSets)),
+		GroupingSetCol: g.groupingSetCol,
+	}
+	for i := range groupingCols {
+		private.InputCols[i] = groupingCols[i].id
+		private.OutCols[i] = outCols[i].id
+	}
+	for i, set := range g.groupingSets {
+		set.ForEach(func(j int) {
+			private.Sets[i].Add(groupingCols[j].id)
+		})
+	}
+
+	// The grouping columns which are part of every grouping set are constant
+	// within each group, so they are optional in the intra-group ordering.
+	var optCols opt.ColSet
+	for i := range groupingCols {
+		if private.Sets.ContainsInAll(groupingCols[i].id) {
+			optCols.Add(groupingCols[i].id)
+		}
+	}
+	private.Ordering.FromOrderingWithOptCols(ordering, optCols)
+
+	return b.factory.ConstructGroupingSets(g.aggInScope.expr.(memo.RelExpr), aggs, &private)
+}
+
 // analyzeHaving analyzes the having clause and returns it as a typed
 // expression. fromScope contains the name bindings that are visible for this
 // HAVING clause (e.g., passed in from an enclosing statement).
@@ -507,15 +694,169 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
+	// The grouping sets of the GROUP BY clause are the cross product of the
+	// grouping sets of its items. For example, GROUP BY a, ROLLUP (b) has the
+	// grouping sets (a, b) and (a).
+	sets := []util.FastIntSet{{}}
 	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		itemSets := b.buildGroupingItem(e, selects, projectionsScope, fromScope)
+		checkNumGroupingSets(len(sets) * len(itemSets))
+		product := make([]util.FastIntSet, 0, len(sets)*len(itemSets))
+		for _, set := range sets {
+			for _, itemSet := range itemSets {
+				product = append(product, set.Union(itemSet))
+			}
+		}
+		sets = product
 	}
 	g.buildingGroupingCols = false
+
+	// A single grouping set always contains all the grouping columns, so it is
+	// built as an ordinary GROUP BY.
+	if len(sets) > 1 {
+		g.groupingSets = sets
+	}
+}
+
+// buildGroupingItem builds the grouping columns for an item of a GROUP BY
+// clause, and returns the grouping sets that it denotes. An ordinary GROUP BY
+// expression denotes a single grouping set, while GROUPING SETS, ROLLUP and
+// CUBE denote several grouping sets. For example:
+//
+//   ROLLUP (a, b, c)               =>  (a, b, c), (a, b), (a), ()
+//   CUBE (a, b)                    =>  (a, b), (a), (b), ()
+//   GROUPING SETS (a, ROLLUP (b))  =>  (a), (b), ()
+//
+// See buildGrouping for a description of the remaining arguments.
+func (b *Builder) buildGroupingItem(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) []util.FastIntSet {
+	gs, ok := groupBy.(*tree.GroupingSet)
+	if !ok {
+		set := b.buildGrouping(groupBy, selects, projectionsScope, fromScope, fromScope.groupby.aggInScope)
+		return []util.FastIntSet{set}
+	}
+
+	if gs.Type == tree.GroupingSets {
+		var sets []util.FastIntSet
+		for _, e := range gs.Exprs {
+			sets = append(sets, b.buildGroupingItem(e, selects, projectionsScope, fromScope)...)
+			checkNumGroupingSets(len(sets))
+		}
+		return sets
+	}
+
+	// Each element of a ROLLUP or CUBE is a set of grouping columns.
+	elems := make([]util.FastIntSet, len(gs.Exprs))
+	for i, e := range gs.Exprs {
+		elems[i] = b.buildGrouping(e, selects, projectionsScope, fromScope, fromScope.groupby.aggInScope)
+	}
+
+	var sets []util.FastIntSet
+	switch gs.Type {
+	case tree.Rollup:
+		// ROLLUP denotes the prefixes of its elements, from longest to shortest.
+		sets = make([]util.FastIntSet, 0, len(elems)+1)
+		for i := len(elems); i >= 0; i-- {
+			var set util.FastIntSet
+			for j := 0; j < i; j++ {
+				set.UnionWith(elems[j])
+			}
+			sets = append(sets, set)
+		}
+
+	case tree.Cube:
+		// CUBE denotes all the subsets of its elements.
+		if len(elems) > maxCubeElements {
+			panic(pgerror.Newf(pgcode.TooManyColumns, "CUBE is limited to %d elements", maxCubeElements))
+		}
+		sets = make([]util.FastIntSet, 0, 1<<len(elems))
+		for mask := (1 << len(elems)) - 1; mask >= 0; mask-- {
+			var set util.FastIntSet
+			for j := range elems {
+				if mask&(1<<(len(elems)-1-j)) != 0 {
+					set.UnionWith(elems[j])
+				}
+			}
+			sets = append(sets, set)
+		}
+
+	default:
+		panic(errors.AssertionFailedf("unknown grouping set type %s", gs.Type))
+	}
+	return sets
+}
+
+// buildGroupingFunc builds a call to the GROUPING function. It returns a bit
+// mask with a bit set for each of its arguments that is not part of the
+// grouping set of the current row, where the last argument corresponds to the
+// least significant bit. The arguments must be grouping expressions.
+//
+// For an ordinary GROUP BY the result is always 0. With grouping sets, it is
+// built as a CASE expression which maps the ordinal of the grouping set of
+// each row (groupingSetCol) to the corresponding bit mask.
+func (b *Builder) buildGroupingFunc(
+	f *tree.FuncExpr, inScope, outScope *scope, outCol *scopeColumn, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	if !inScope.inGroupingContext() || inScope.inAgg || inScope.groupby.buildingGroupingCols {
+		panic(errGroupingFuncArgs)
+	}
+	if len(f.Exprs) > maxGroupingFuncArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingFuncArgs+1))
+	}
+
+	g := inScope.groupby
+	groupingCols := g.aggOutScope.cols[len(g.aggs):]
+	ords := make([]int, len(f.Exprs))
+	for i, e := range f.Exprs {
+		col, ok := g.groupStrs[symbolicExprStr(e.(tree.TypedExpr))]
+		if !ok {
+			panic(errGroupingFuncArgs)
+		}
+		ords[i] = groupingColOrdinal(groupingCols, col.id)
+	}
+
+	var out opt.ScalarExpr
+	if g.groupingSets == nil {
+		out = b.factory.ConstructConstVal(tree.DZero, types.Int)
+	} else {
+		whens := make(memo.ScalarListExpr, len(g.groupingSets))
+		for i, set := range g.groupingSets {
+			var mask int
+			for j, ord := range ords {
+				if !set.Contains(ord) {
+					mask |= 1 << (len(ords) - 1 - j)
+				}
+			}
+			whens[i] = b.factory.ConstructWhen(
+				b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+				b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(mask)), types.Int),
+			)
+		}
+		out = b.factory.ConstructCase(
+			b.factory.ConstructVariable(g.groupingSetCol), whens, b.factory.ConstructNull(types.Int),
+		)
+		if colRefs != nil {
+			colRefs.Add(g.groupingSetCol)
+		}
+	}
+	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+}
+
+// checkNumGroupingSets panics if the given number of grouping sets exceeds
+// maxGroupingSets.
+func checkNumGroupingSets(n int) {
+	if n > maxGroupingSets {
+		panic(pgerror.Newf(pgcode.StatementTooComplex,
+			"too many grouping sets present (maximum %d)", maxGroupingSets))
+	}
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the ordinals of the grouping
+// columns for the expression among all the grouping columns.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +870,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (ords util.FastIntSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -623,21 +964,25 @@ func (b *Builder) buildGrouping(
 	exprs = flattenTuples(exprs)
 
 	// Finally, build each of the GROUP BY columns.
+	g := fromScope.groupby
 	for _, e := range exprs {
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := g.groupStrs[exprStr]; ok {
+			ords.Add(groupingColOrdinal(g.groupingCols(), col.id))
 			continue
 		}
 
 		// Save a representation of the GROUP BY expression for validation of the
 		// SELECT and HAVING expressions. This enables queries such as:
 		//   SELECT x+y FROM t GROUP BY x+y
+		ords.Add(len(g.groupStrs))
 		col := aggInScope.addColumn(alias, e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
-		fromScope.groupby.groupStrs[exprStr] = col
+		g.groupStrs[exprStr] = col
 	}
+	return ords
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -891,6 +1236,13 @@ func isSQLFn(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.SQLClass
 }
 
+func isGroupingFunc(def *tree.FunctionDefinition) bool {
+	return def.Name == "grouping"
+}
+
+var errGroupingFuncArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level")
+
 func newGroupingError(name *tree.Name) error {
 	return pgerror.Newf(pgcode.Grouping,
 		"column \"%s\" must appear in the GROUP BY clause or be used in an aggregate function",
@@ -903,6 +1255,11 @@ func newGroupingError(name *tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The PK columns are NULL in the rows of the grouping sets that do not
+		// contain them, so they do not determine the other columns of the table.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
@@ -219,6 +219,12 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 	} else {
 		mb.init(b, "insert", tab, alias)
 	}
+	mb.rlsReturning = resultsNeeded(ins.Returning)
+	mb.initRowLevelSecurityCheck(tree.PolicyCommandInsert)
+	if ins.OnConflict != nil && mb.rlsCheckExpr != nil {
+		panic(unimplemented.Newf("rls-upsert",
+			"UPSERT and INSERT ... ON CONFLICT are not supported on tables with row-level security"))
+	}
 
 	// Compute target columns in two cases:
 	//
@@ -650,6 +656,8 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForInsert()
 
+	mb.buildTriggers(tree.TriggerEventInsert)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -1046,6 +1054,8 @@ func (mb *mutationBuilder) setUpsertCols(insertCols tree.NameList) {
 // buildUpsert constructs an Upsert operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
+	mb.checkNoTriggersForUpsert()
+
 	// Merge input insert and update columns using CASE expressions.
 	mb.projectUpsertColumns()
 
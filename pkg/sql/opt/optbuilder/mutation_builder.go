@@ -101,6 +101,15 @@ type mutationBuilder struct {
 	// an Upsert operator.
 	upsertColIDs opt.OptionalColList
 
+	// rlsCheckExpr is the expression which the new rows must pass according to
+	// the row-level security policies of the table, or nil if no policies are
+	// enforced. See initRowLevelSecurityCheck.
+	rlsCheckExpr tree.Expr
+
+	// rlsReturning is true if the mutation returns the rows it modifies, in
+	// which case they must also pass the SELECT row-level security policies.
+	rlsReturning bool
+
 	// checkColIDs lists the input column IDs storing the boolean results of
 	// evaluating check constraint expressions defined on the target table. Its
 	// length is always equal to the number of check constraints on the table
@@ -191,14 +200,19 @@ func (mb *mutationBuilder) init(b *Builder, opName string, tab cat.Table, alias
 
 	// Allocate segmented array of column IDs.
 	numPartialIndexes := partialIndexCount(tab)
-	colIDs := make(opt.OptionalColList, n*4+tab.CheckCount()+2*numPartialIndexes)
+	numChecks := tab.CheckCount()
+	if tab.IsRowLevelSecurityEnabled() {
+		// Reserve a check column for the row-level security policies.
+		numChecks++
+	}
+	colIDs := make(opt.OptionalColList, n*4+numChecks+2*numPartialIndexes)
 	mb.insertColIDs = colIDs[:n]
 	mb.fetchColIDs = colIDs[n : n*2]
 	mb.updateColIDs = colIDs[n*2 : n*3]
 	mb.upsertColIDs = colIDs[n*3 : n*4]
-	mb.checkColIDs = colIDs[n*4 : n*4+tab.CheckCount()]
-	mb.partialIndexPutColIDs = colIDs[n*4+tab.CheckCount() : n*4+tab.CheckCount()+numPartialIndexes]
-	mb.partialIndexDelColIDs = colIDs[n*4+tab.CheckCount()+numPartialIndexes:]
+	mb.checkColIDs = colIDs[n*4 : n*4+numChecks]
+	mb.partialIndexPutColIDs = colIDs[n*4+numChecks : n*4+numChecks+numPartialIndexes]
+	mb.partialIndexDelColIDs = colIDs[n*4+numChecks+numPartialIndexes:]
 
 	// Add the table and its columns (including mutation columns) to metadata.
 	mb.tabID = mb.md.AddTable(tab, &mb.alias)
@@ -273,6 +287,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 		noRowLocking,
 		inScope,
 	)
+	mb.addRowLevelSecurityFetchFilter(tree.PolicyCommandUpdate)
 	mb.outScope = mb.fetchScope
 
 	// Set list of columns that will be fetched by the input expression.
@@ -381,6 +396,7 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
+	mb.addRowLevelSecurityFetchFilter(tree.PolicyCommandDelete)
 	mb.outScope = mb.fetchScope
 
 	// WHERE
@@ -802,9 +818,10 @@ func findRoundingFunction(
 
 // addCheckConstraintCols synthesizes a boolean output column for each check
 // constraint defined on the target table. The mutation operator will report
-// a constraint violation error if the value of the column is false.
+// a constraint violation error if the value of the column is false. A last
+// column is added for the row-level security policies, if any are enforced.
 func (mb *mutationBuilder) addCheckConstraintCols() {
-	if mb.tab.CheckCount() != 0 {
+	if mb.tab.CheckCount() != 0 || mb.rlsCheckExpr != nil {
 		projectionsScope := mb.outScope.replace()
 		projectionsScope.appendColumnsFromScope(mb.outScope)
 		mutationCols := mb.mutationColumnIDs()
@@ -834,6 +851,10 @@ func (mb *mutationBuilder) addCheckConstraintCols() {
 			}
 		}
 
+		if mb.rlsCheckExpr != nil {
+			mb.addRowLevelSecurityCheckCol(projectionsScope)
+		}
+
 		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
 		mb.outScope = projectionsScope
 	}
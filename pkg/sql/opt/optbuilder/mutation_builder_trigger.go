@@ -0,0 +1,239 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// Triggers are planned like FK cascades: each trigger that fires for the
+// mutation is recorded as a memo.FKCascade (with the Trigger flag set) which
+// refers to the buffered mutation input. The action of the trigger is only
+// built when the trigger runs, by triggerBuilder.
+//
+// The action can read the modified rows through two transition relations:
+//
+//   - new contains the inserted rows, or the updated rows after the update;
+//   - old contains the deleted rows, or the updated rows before the update.
+//
+// Both have one column for each visible, non-virtual column of the table. A
+// statement-level trigger sees all the modified rows; a row-level trigger runs
+// once for each modified row and sees just that row.
+//
+// For example, for the following trigger:
+//
+//   CREATE TRIGGER tr AFTER INSERT ON parent FOR EACH STATEMENT
+//     AS 'INSERT INTO log SELECT p FROM new'
+//
+// an INSERT INTO parent is planned as:
+//
+//   insert parent
+//    ├── columns: <none>
+//    ├── insert-mapping:
+//    │    └── column1:3 => p:1
+//    ├── input binding: &1
+//    ├── triggers
+//    │    └── tr (after, for each statement)
+//    └── values
+//         ...
+//
+// and, after the insert is executed, the action is built as:
+//
+//   insert log
+//    └── with-scan &1 (new)
+//         └── mapping:
+//              └── column1:1 => p:2
+//
+
+// buildTriggers adds a memo.FKCascade entry for each trigger on the mutated
+// table that fires on the given event.
+func (mb *mutationBuilder) buildTriggers(event tree.TriggerEvent) {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		tr := mb.tab.Trigger(i)
+		if !triggerFiresOn(tr, event) {
+			continue
+		}
+		mb.ensureWithID()
+
+		ords := triggerColumnOrdinals(mb.tab)
+		var oldValues, newValues opt.ColList
+		if event != tree.TriggerEventInsert {
+			oldValues = make(opt.ColList, len(ords))
+			for j, ord := range ords {
+				oldValues[j] = mb.fetchColIDs[ord]
+			}
+		}
+		if event != tree.TriggerEventDelete {
+			newValues = make(opt.ColList, len(ords))
+			for j, ord := range ords {
+				if event == tree.TriggerEventInsert {
+					newValues[j] = mb.insertColIDs[ord]
+				} else if newValues[j] = mb.updateColIDs[ord]; newValues[j] == 0 {
+					newValues[j] = mb.fetchColIDs[ord]
+				}
+			}
+		}
+		for _, cols := range []opt.ColList{oldValues, newValues} {
+			for _, id := range cols {
+				if id == 0 {
+					panic(errors.AssertionFailedf("missing column for trigger %s", tr.Name()))
+				}
+			}
+		}
+
+		mb.cascades = append(mb.cascades, memo.FKCascade{
+			FKName:     tr.Name(),
+			Builder:    newTriggerBuilder(mb.tab, i),
+			WithID:     mb.withID,
+			OldValues:  oldValues,
+			NewValues:  newValues,
+			Trigger:    true,
+			Before:     tr.Before(),
+			ForEachRow: tr.ForEachRow(),
+		})
+	}
+}
+
+// checkNoTriggersForUpsert raises an error if the mutated table has triggers
+// that could fire for an UPSERT or INSERT ... ON CONFLICT DO UPDATE.
+func (mb *mutationBuilder) checkNoTriggersForUpsert() {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		tr := mb.tab.Trigger(i)
+		if tr.OnInsert() || tr.OnUpdate() {
+			panic(unimplemented.NewWithIssuef(28296,
+				"UPSERT and INSERT ... ON CONFLICT DO UPDATE are not supported on table %s "+
+					"with trigger %s", mb.tab.Name(), tr.Name()))
+		}
+	}
+}
+
+// triggerFiresOn returns true if the trigger fires on the given event.
+func triggerFiresOn(tr cat.Trigger, event tree.TriggerEvent) bool {
+	switch event {
+	case tree.TriggerEventInsert:
+		return tr.OnInsert()
+	case tree.TriggerEventUpdate:
+		return tr.OnUpdate()
+	default:
+		return tr.OnDelete()
+	}
+}
+
+// triggerColumnOrdinals returns the ordinals of the table columns that are
+// part of the transition relations: all visible, non-virtual public columns.
+func triggerColumnOrdinals(tab cat.Table) []int {
+	ords := tableOrdinals(tab, columnKinds{
+		includeMutations:       false,
+		includeSystem:          false,
+		includeVirtualInverted: false,
+		includeVirtualComputed: false,
+	})
+	res := ords[:0]
+	for _, ord := range ords {
+		if !tab.Column(ord).IsHidden() {
+			res = append(res, ord)
+		}
+	}
+	return res
+}
+
+// addTriggerTransitionRelations makes the "old" and "new" transition relations
+// visible in the given scope, as CTEs that read the given binding. The
+// relations are only added if the corresponding column list is not empty.
+func (b *Builder) addTriggerTransitionRelations(
+	inScope *scope, tab cat.Table, binding opt.WithID, oldValues, newValues opt.ColList,
+) {
+	ords := triggerColumnOrdinals(tab)
+	add := func(name tree.Name, values opt.ColList) {
+		if len(values) == 0 {
+			return
+		}
+		if len(values) != len(ords) {
+			panic(errors.AssertionFailedf(
+				"expected %d %s columns, got %d", len(ords), name, len(values),
+			))
+		}
+		cols := make(physical.Presentation, len(ords))
+		for i, ord := range ords {
+			cols[i] = opt.AliasedColumn{Alias: string(tab.Column(ord).ColName()), ID: values[i]}
+		}
+		if inScope.ctes == nil {
+			inScope.ctes = make(map[string]*cteSource)
+		}
+		inScope.ctes[name.String()] = &cteSource{
+			id:   binding,
+			name: tree.AliasClause{Alias: name},
+			cols: cols,
+		}
+	}
+	add("old", oldValues)
+	add("new", newValues)
+}
+
+// triggerBuilder is a memo.CascadeBuilder implementation for triggers. It
+// builds the action of the trigger, with the "old" and "new" transition
+// relations reading the buffered mutation input.
+type triggerBuilder struct {
+	table cat.Table
+	// triggerOrdinal is the ordinal of the trigger (can be passed to
+	// table.Trigger).
+	triggerOrdinal int
+}
+
+var _ memo.CascadeBuilder = &triggerBuilder{}
+
+func newTriggerBuilder(table cat.Table, triggerOrdinal int) *triggerBuilder {
+	return &triggerBuilder{
+		table:          table,
+		triggerOrdinal: triggerOrdinal,
+	}
+}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *triggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		tr := tb.table.Trigger(tb.triggerOrdinal)
+		stmt, err := parser.ParseOne(tr.Action())
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to parse action of trigger %s", tr.Name()))
+		}
+
+		// Construct a dummy operator as the binding.
+		md := b.factory.Metadata()
+		md.AddWithBinding(binding, b.factory.ConstructFakeRel(&memo.FakeRelPrivate{
+			Props: bindingProps,
+		}))
+
+		inScope := b.allocScope()
+		b.addTriggerTransitionRelations(inScope, tb.table, binding, oldValues, newValues)
+		outScope := b.buildStmtAtRoot(stmt.AST, nil /* desiredTypes */, inScope)
+		return outScope.expr
+	})
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// When row-level security is enabled on a table, the policies of the table
+// which apply to the current user restrict the rows that a statement can
+// read and write:
+//
+//   - SELECT only returns the rows which pass the USING expressions of the
+//     SELECT policies;
+//   - UPDATE and DELETE only modify the rows which pass the USING expressions
+//     of the UPDATE or DELETE policies;
+//   - INSERT and UPDATE fail if a new row does not pass the WITH CHECK
+//     expressions of the INSERT or UPDATE policies. The USING expression is
+//     used for a policy without a WITH CHECK expression;
+//   - if a mutation has a RETURNING clause, the rows it modifies and the new
+//     rows it writes must also pass the USING expressions of the SELECT
+//     policies, since they are returned to the user.
+//
+// A row passes the expressions if it passes at least one of the permissive
+// expressions and all of the restrictive expressions. If no permissive policy
+// applies, no row passes. USING expressions are added as a filter on top of
+// the scan of the table; WITH CHECK expressions are added as an extra check
+// column of the mutation, after the check constraint columns.
+//
+// The policies do not apply to the table owner (unless row-level security is
+// forced on the table), to users with the BYPASSRLS role option, or to the
+// queries issued by foreign key checks and cascades. Unlike other role
+// options, admins do not have BYPASSRLS unless it was granted to them.
+
+// rowLevelSecurityPolicies returns the policies of the table which apply to
+// the given command and to the current user. enforced is false if row-level
+// security does not apply to the current user, in which case all rows can be
+// read and written.
+func (b *Builder) rowLevelSecurityPolicies(
+	tab cat.Table, cmd tree.PolicyCommand,
+) (policies []cat.Policy, enforced bool) {
+	if !tab.IsRowLevelSecurityEnabled() {
+		return nil, false
+	}
+
+	// The policies that apply depend on the current user and its roles, as
+	// well as on the row_security setting, none of which is tracked by the
+	// metadata dependencies.
+	b.DisableMemoReuse = true
+
+	bypass, err := b.catalog.HasExplicitRoleOption(b.ctx, roleoption.BYPASSRLS)
+	if err != nil {
+		panic(err)
+	}
+	if !bypass && !tab.IsRowLevelSecurityForced() {
+		if bypass, err = b.catalog.HasOwnership(b.ctx, tab); err != nil {
+			panic(err)
+		}
+	}
+	if bypass {
+		return nil, false
+	}
+
+	if b.evalCtx.SessionData.RowSecurityDisabled {
+		panic(pgerror.Newf(pgcode.InsufficientPrivilege,
+			"query would be affected by row-level security policy for table %q", tab.Name()))
+	}
+
+	for i, n := 0, tab.PolicyCount(); i < n; i++ {
+		p := tab.Policy(i)
+		if p.Command() != tree.PolicyCommandAll && p.Command() != cmd {
+			continue
+		}
+		if b.policyAppliesToCurrentUser(p) {
+			policies = append(policies, p)
+		}
+	}
+	return policies, true
+}
+
+// policyAppliesToCurrentUser returns true if the current user is a member of
+// one of the roles of the policy.
+func (b *Builder) policyAppliesToCurrentUser(p cat.Policy) bool {
+	for i, n := 0, p.RoleCount(); i < n; i++ {
+		isMember, err := b.catalog.IsMemberOfRole(b.ctx, p.Role(i))
+		if err != nil {
+			panic(err)
+		}
+		if isMember {
+			return true
+		}
+	}
+	return false
+}
+
+// combinePolicyExprs combines the given policy expressions into a single
+// boolean expression: the disjunction of the permissive expressions, and the
+// conjunction of the restrictive ones. Policies with an empty expression are
+// ignored. If there is no permissive expression, the result is false.
+func combinePolicyExprs(policies []cat.Policy, policyExpr func(cat.Policy) string) tree.Expr {
+	var permissive, restrictive tree.Expr
+	for _, p := range policies {
+		text := policyExpr(p)
+		if text == "" {
+			continue
+		}
+		expr, err := parser.ParseExpr(text)
+		if err != nil {
+			panic(err)
+		}
+		expr = &tree.ParenExpr{Expr: expr}
+		switch {
+		case p.Restrictive() && restrictive == nil:
+			restrictive = expr
+		case p.Restrictive():
+			restrictive = &tree.AndExpr{Left: restrictive, Right: expr}
+		case permissive == nil:
+			permissive = expr
+		default:
+			permissive = &tree.OrExpr{Left: permissive, Right: expr}
+		}
+	}
+	if permissive == nil {
+		return tree.DBoolFalse
+	}
+	if restrictive == nil {
+		return permissive
+	}
+	return &tree.AndExpr{Left: &tree.ParenExpr{Expr: permissive}, Right: restrictive}
+}
+
+// policyUsingExpr returns the expression which the existing rows read by a
+// command must pass.
+func policyUsingExpr(p cat.Policy) string {
+	return p.UsingExpr()
+}
+
+// policyCheckExpr returns the expression which the new rows written by a
+// command must pass.
+func policyCheckExpr(p cat.Policy) string {
+	if e := p.WithCheckExpr(); e != "" {
+		return e
+	}
+	return p.UsingExpr()
+}
+
+// addRowLevelSecurityFilter filters the rows of the table scanned by inScope
+// with the USING expressions of the policies which apply to the given command.
+func (b *Builder) addRowLevelSecurityFilter(tab cat.Table, cmd tree.PolicyCommand, inScope *scope) {
+	policies, enforced := b.rowLevelSecurityPolicies(tab, cmd)
+	if !enforced {
+		return
+	}
+
+	// Resolve the expression in a scope without an outer scope, so that its
+	// names can only refer to the columns of the table.
+	policyScope := b.allocScope()
+	policyScope.appendColumnsFromScope(inScope)
+	filter := b.resolveAndBuildScalar(
+		combinePolicyExprs(policies, policyUsingExpr),
+		types.Bool,
+		exprKindPolicy,
+		tree.RejectSpecial|tree.RejectSubqueries,
+		policyScope,
+	)
+	inScope.expr = b.factory.ConstructSelect(
+		inScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{b.factory.ConstructFiltersItem(filter)},
+	)
+}
+
+// addRowLevelSecurityFetchFilter filters the rows of the table fetched by an
+// UPDATE or DELETE with the USING expressions of the policies which apply to
+// the given command, and of the SELECT policies if the rows are returned.
+func (mb *mutationBuilder) addRowLevelSecurityFetchFilter(cmd tree.PolicyCommand) {
+	mb.b.addRowLevelSecurityFilter(mb.tab, cmd, mb.fetchScope)
+	if mb.rlsReturning {
+		mb.b.addRowLevelSecurityFilter(mb.tab, tree.PolicyCommandSelect, mb.fetchScope)
+	}
+}
+
+// initRowLevelSecurityCheck sets the expression which the new rows written by
+// the mutation must pass, if any policies apply to the given command. If the
+// new rows are returned, they must also pass the USING expressions of the
+// SELECT policies. It must be called before addCheckConstraintCols.
+func (mb *mutationBuilder) initRowLevelSecurityCheck(cmd tree.PolicyCommand) {
+	policies, enforced := mb.b.rowLevelSecurityPolicies(mb.tab, cmd)
+	if !enforced {
+		return
+	}
+	mb.rlsCheckExpr = combinePolicyExprs(policies, policyCheckExpr)
+	if mb.rlsReturning {
+		selectPolicies, _ := mb.b.rowLevelSecurityPolicies(mb.tab, tree.PolicyCommandSelect)
+		mb.rlsCheckExpr = &tree.AndExpr{
+			Left:  &tree.ParenExpr{Expr: mb.rlsCheckExpr},
+			Right: &tree.ParenExpr{Expr: combinePolicyExprs(selectPolicies, policyUsingExpr)},
+		}
+	}
+}
+
+// addRowLevelSecurityCheckCol synthesizes the boolean check column for the
+// row-level security policies, as the last of the check columns. Unlike for
+// check constraints, the mutation operator reports a violation if the value of
+// the column is NULL.
+func (mb *mutationBuilder) addRowLevelSecurityCheckCol(projectionsScope *scope) {
+	defer mb.b.semaCtx.Properties.Restore(mb.b.semaCtx.Properties)
+	mb.b.semaCtx.Properties.Require(exprKindPolicy.String(), tree.RejectSpecial|tree.RejectSubqueries)
+
+	texpr := mb.outScope.resolveAndRequireType(mb.rlsCheckExpr, types.Bool)
+	scopeCol := projectionsScope.addColumn("rls_check", texpr)
+	mb.b.buildScalar(texpr, mb.outScope, projectionsScope, scopeCol, nil /* colRefs */)
+	mb.checkColIDs[mb.tab.CheckCount()] = scopeCol.id
+}
@@ -85,6 +85,14 @@ func (b *Builder) buildScalar(
 				panic(newGroupingError(&t.name))
 			}
 
+			if g.groupingSets != nil {
+				// The grouping columns of an aggregation with grouping sets are NULL
+				// in the rows of the grouping sets which don't contain them, so an
+				// outer column cannot be added as a grouping column. Since it is
+				// constant, it can be referenced directly instead.
+				return b.finishBuildScalarRef(t, inScope, outScope, outCol, colRefs)
+			}
+
 			// We add a new grouping column; these show up both in aggInScope and
 			// aggOutScope.
 			//
@@ -497,6 +505,10 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if isGroupingFunc(def) {
+		return b.buildGroupingFunc(f, inScope, outScope, outCol, colRefs)
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -137,6 +137,7 @@ const (
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
+	exprKindPolicy
 	exprKindReturning
 	exprKindSelect
 	exprKindValues
@@ -157,6 +158,7 @@ var exprKindName = [...]string{
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
+	exprKindPolicy:            "POLICY",
 	exprKindReturning:         "RETURNING",
 	exprKindSelect:            "SELECT",
 	exprKindValues:            "VALUES",
@@ -932,7 +934,8 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 	case *tree.FuncExpr:
 		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
 		if err != nil {
-			panic(err)
+			expr = s.replaceUDF(t, err)
+			break
 		}
 
 		if isGenerator(def) && s.replaceSRFs {
@@ -111,7 +111,7 @@ func (b *Builder) buildDataSource(
 		switch t := ds.(type) {
 		case cat.Table:
 			tabMeta := b.addTable(t, &resName)
-			return b.buildScan(
+			outScope = b.buildScan(
 				tabMeta,
 				tableOrdinals(t, columnKinds{
 					includeMutations:       false,
@@ -121,6 +121,8 @@ func (b *Builder) buildDataSource(
 				}),
 				indexFlags, locking, inScope,
 			)
+			b.addRowLevelSecurityFilter(t, tree.PolicyCommandSelect, outScope)
+			return outScope
 
 		case cat.Sequence:
 			return b.buildSequenceSelect(t, &resName, inScope)
@@ -407,7 +409,9 @@ func (b *Builder) buildScanFromTableRef(
 
 	tn := tree.MakeUnqualifiedTableName(tab.Name())
 	tabMeta := b.addTable(tab, &tn)
-	return b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	outScope = b.buildScan(tabMeta, ordinals, indexFlags, locking, inScope)
+	b.addRowLevelSecurityFilter(tab, tree.PolicyCommandSelect, outScope)
+	return outScope
 }
 
 // addTable adds a table to the metadata and returns the TableMeta. The table
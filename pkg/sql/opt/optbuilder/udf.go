@@ -0,0 +1,380 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// replaceUDF is called when the name of the given function call could not be
+// resolved to a builtin function. If the name refers to a user-defined
+// function in the catalog, replaceUDF returns an expression that replaces the
+// call. Otherwise, it panics with resolveErr.
+//
+// Calls to IMMUTABLE and STABLE functions are inlined into the query, so that
+// the optimizer can fold, normalize and decorrelate the body of the function
+// along with the rest of the query. Calls that cannot be inlined are replaced
+// with a function that evaluates the body of the function using the internal
+// executor.
+func (s *scope) replaceUDF(f *tree.FuncExpr, resolveErr error) tree.Expr {
+	b := s.builder
+	un, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok || pgerror.GetPGCode(resolveErr) != pgcode.UndefinedFunction {
+		panic(resolveErr)
+	}
+	name, err := un.ToUnresolvedObjectName(tree.NoAnnotation)
+	if err != nil {
+		panic(resolveErr)
+	}
+	fn, err := b.catalog.ResolveFunction(
+		b.ctx, cat.Flags{AvoidDescriptorCaches: b.insideViewDef}, name,
+	)
+	if err != nil {
+		switch pgerror.GetPGCode(err) {
+		case pgcode.UndefinedFunction, pgcode.UndefinedObject, pgcode.UndefinedSchema,
+			pgcode.InvalidSchemaName, pgcode.InvalidCatalogName:
+			// Report the name as an unknown function.
+			panic(resolveErr)
+		}
+		panic(err)
+	}
+
+	if b.insideFuncDef {
+		panic(unimplemented.Newf("udf-reference",
+			"user-defined function %s cannot be referenced by another function",
+			tree.ErrString(un)))
+	}
+	if f.WindowDef != nil {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"OVER specified, but %s is not a window function nor an aggregate function",
+			tree.ErrString(un)))
+	}
+	if f.Type != 0 || f.Filter != nil || len(f.OrderBy) > 0 {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not an aggregate function", tree.ErrString(un)))
+	}
+	if len(f.Exprs) != fn.ParamCount() {
+		panic(pgerror.Newf(pgcode.UndefinedFunction,
+			"wrong number of arguments for function %s: expected %d, found %d",
+			tree.ErrString(un), fn.ParamCount(), len(f.Exprs)))
+	}
+
+	// The function can be altered or dropped at any time, and the body of the
+	// function is not tracked by the memo's dependencies.
+	b.DisableMemoReuse = true
+
+	stmt, err := parser.ParseOne(fn.Body())
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.Syntax,
+			"failed to parse body of function %s", tree.ErrString(fn.Name())))
+	}
+	body, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(errors.AssertionFailedf("expected SELECT statement"))
+	}
+
+	if b.trackViewDeps {
+		// The view depends on the function itself, not on the relations read by
+		// its body, so the call is not inlined. The function is called by its
+		// fully qualified name in the stored view query, like data sources.
+		found := false
+		for _, dep := range b.viewFuncDeps {
+			found = found || dep.ID() == fn.ID()
+		}
+		if !found {
+			b.viewFuncDeps = append(b.viewFuncDeps, fn)
+		}
+		if b.qualifyDataSourceNamesInAST {
+			fnName := fn.Name()
+			f.Func.FunctionReference = tree.NewUnresolvedName(
+				string(fnName.CatalogName), string(fnName.SchemaName), string(fnName.ObjectName),
+			)
+		}
+		return s.makeUDFCall(fn, body, f)
+	}
+
+	// Bodies that refer to parameters by position cannot be inlined, since the
+	// placeholders would conflict with the placeholders of the query.
+	if fn.Volatility() != tree.VolatilityVolatile && !fn.ReturnsNullOnNullInput() &&
+		stmt.NumPlaceholders == 0 {
+		if expr := s.inlineUDF(fn, body, f.Exprs); expr != nil {
+			return expr
+		}
+	}
+	return s.makeUDFCall(fn, body, f)
+}
+
+// inlineUDF returns an expression that computes the result of the given
+// function from its arguments, or nil if the call cannot be inlined.
+//
+// If the body of the function is a single expression without a FROM clause,
+// the parameters are substituted by the arguments in the expression:
+//
+//   CREATE FUNCTION f(a INT) RETURNS INT IMMUTABLE AS 'SELECT a + 1'
+//   f(x) => CAST((x:::INT + 1) AS INT)
+//
+// Otherwise, the body is inlined as a correlated subquery:
+//
+//   CREATE FUNCTION f(a INT) RETURNS INT STABLE AS 'SELECT v FROM kv WHERE k = a'
+//   f(x) => (SELECT CAST((SELECT v FROM kv WHERE k = a) AS INT) FROM (SELECT x:::INT AS a) AS f)
+//
+func (s *scope) inlineUDF(fn cat.Function, body *tree.Select, args tree.Exprs) tree.Expr {
+	args = annotateUDFArgs(fn, args)
+
+	if expr, ok := s.udfBodyExpr(body); ok {
+		// Each argument must be evaluated exactly once, unless it is trivial.
+		uses := make([]int, len(args))
+		_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+			if i, ok := udfParamOrdinal(fn, expr); ok {
+				uses[i]++
+				return false, expr, nil
+			}
+			return true, expr, nil
+		})
+		canSubstitute := true
+		for i := range uses {
+			if uses[i] != 1 && !isTrivialUDFArg(args[i]) {
+				canSubstitute = false
+				break
+			}
+		}
+		if canSubstitute {
+			expr, err := tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+				if i, ok := udfParamOrdinal(fn, expr); ok {
+					return false, &tree.ParenExpr{Expr: args[i]}, nil
+				}
+				return true, expr, nil
+			})
+			if err != nil {
+				panic(err)
+			}
+			return &tree.CastExpr{Expr: &tree.ParenExpr{Expr: expr}, Type: fn.ReturnType()}
+		}
+	}
+
+	// Arguments that are not scalar functions of a single row cannot be moved
+	// into a subquery.
+	for i := range args {
+		if !s.isRowScalarExpr(args[i]) {
+			return nil
+		}
+	}
+	// Wrap the subquery in parentheses so that it is replaced when the result
+	// is walked.
+	return &tree.ParenExpr{Expr: &tree.Subquery{
+		Select: &tree.ParenSelect{Select: wrapUDFBody(fn, body, args)},
+	}}
+}
+
+// makeUDFCall returns a copy of the given function call that evaluates the
+// body of the given function with the internal executor.
+func (s *scope) makeUDFCall(fn cat.Function, body *tree.Select, f *tree.FuncExpr) tree.Expr {
+	params := make(tree.Exprs, fn.ParamCount())
+	paramTypes := make(tree.ArgTypes, fn.ParamCount())
+	for i := range params {
+		params[i] = &tree.Placeholder{Idx: tree.PlaceholderIdx(i)}
+		paramTypes[i].Name = string(udfParamName(fn, i))
+		paramTypes[i].Typ = fn.ParamType(i)
+	}
+	params = annotateUDFArgs(fn, params)
+	query := tree.AsStringWithFlags(wrapUDFBody(fn, body, params), tree.FmtParsable)
+	name := tree.AsStringWithFlags(fn.Name(), tree.FmtParsable)
+
+	def := tree.NewUserDefinedFunctionDefinition(
+		name,
+		&tree.FunctionProperties{
+			NullableArgs:     !fn.ReturnsNullOnNullInput(),
+			DistsqlBlocklist: true,
+		},
+		&tree.Overload{
+			Types:      paramTypes,
+			ReturnType: tree.FixedReturnType(fn.ReturnType()),
+			Volatility: fn.Volatility(),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if evalCtx.InternalExecutor == nil {
+					return nil, errors.AssertionFailedf(
+						"cannot evaluate function %s without an internal executor", name)
+				}
+				qargs := make([]interface{}, len(args))
+				for i := range args {
+					qargs[i] = args[i]
+				}
+				row, err := evalCtx.InternalExecutor.QueryRow(
+					evalCtx.Ctx(), "udf", evalCtx.Txn, query, qargs...,
+				)
+				if err != nil {
+					return nil, err
+				}
+				if row == nil {
+					return tree.DNull, nil
+				}
+				return row[0], nil
+			},
+		},
+	)
+
+	// The arguments are replaced by their typed versions during type checking,
+	// so they must not be shared with the original call.
+	newF := *f
+	newF.Func = tree.ResolvableFunctionReference{FunctionReference: def}
+	newF.Exprs = append(tree.Exprs(nil), f.Exprs...)
+	return &newF
+}
+
+// udfBodyExpr returns the expression computed by the body of a function, if
+// the body is a single expression without a FROM clause that can be
+// substituted into the calling query.
+func (s *scope) udfBodyExpr(body *tree.Select) (tree.Expr, bool) {
+	if body.With != nil || body.OrderBy != nil || body.Limit != nil || body.Locking != nil {
+		return nil, false
+	}
+	sel, ok := body.Select.(*tree.SelectClause)
+	if !ok || sel.Distinct || sel.DistinctOn != nil || len(sel.From.Tables) != 0 ||
+		sel.From.AsOf.Expr != nil || sel.Where != nil || sel.GroupBy != nil ||
+		sel.Having != nil || sel.Window != nil || len(sel.Exprs) != 1 {
+		return nil, false
+	}
+	expr := sel.Exprs[0].Expr
+	if !s.isRowScalarExpr(expr) {
+		return nil, false
+	}
+	isSimple := true
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch t := expr.(type) {
+		case *tree.Subquery, *tree.ArrayFlatten:
+			isSimple = false
+		case *tree.UnresolvedName:
+			if t.Star {
+				isSimple = false
+			}
+		}
+		return isSimple, expr, nil
+	})
+	return expr, isSimple
+}
+
+// isRowScalarExpr returns false if the given expression contains aggregate,
+// window or set-generating functions, or function calls that cannot be
+// resolved to builtins.
+func (s *scope) isRowScalarExpr(expr tree.Expr) bool {
+	res := true
+	_, _ = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+		if f, ok := expr.(*tree.FuncExpr); ok {
+			def, err := f.Func.Resolve(s.builder.semaCtx.SearchPath)
+			if err != nil || def.Class != tree.NormalClass || f.WindowDef != nil {
+				res = false
+			}
+		}
+		return res, expr, nil
+	})
+	return res
+}
+
+// wrapUDFBody returns a query that computes the result of the given function
+// from the given arguments:
+//
+//   SELECT CAST((<body>) AS <return type>) FROM (SELECT <args>) AS <function>
+//
+func wrapUDFBody(fn cat.Function, body *tree.Select, args tree.Exprs) *tree.Select {
+	sel := &tree.SelectClause{
+		Exprs: tree.SelectExprs{{
+			Expr: &tree.CastExpr{
+				Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: body}},
+				Type: fn.ReturnType(),
+			},
+		}},
+	}
+	if len(args) > 0 {
+		params := make(tree.SelectExprs, len(args))
+		for i := range args {
+			params[i] = tree.SelectExpr{Expr: args[i], As: tree.UnrestrictedName(udfParamName(fn, i))}
+		}
+		sel.From.Tables = tree.TableExprs{&tree.AliasedTableExpr{
+			Expr: &tree.Subquery{Select: &tree.ParenSelect{
+				Select: &tree.Select{Select: &tree.SelectClause{Exprs: params}},
+			}},
+			As: tree.AliasClause{Alias: fn.Name().ObjectName},
+		}}
+	}
+	return &tree.Select{Select: sel}
+}
+
+// annotateUDFArgs returns the given arguments annotated with the types of the
+// corresponding parameters of the given function.
+func annotateUDFArgs(fn cat.Function, args tree.Exprs) tree.Exprs {
+	res := make(tree.Exprs, len(args))
+	for i := range args {
+		res[i] = &tree.AnnotateTypeExpr{
+			Expr:       args[i],
+			Type:       fn.ParamType(i),
+			SyntaxMode: tree.AnnotateShort,
+		}
+	}
+	return res
+}
+
+// udfParamName returns the name of the ith parameter of the given function.
+// Unnamed parameters are named after their position, e.g. "$1".
+func udfParamName(fn cat.Function, i int) tree.Name {
+	if name := fn.ParamName(i); name != "" {
+		return name
+	}
+	return tree.Name(tree.PlaceholderIdx(i).String())
+}
+
+// udfParamOrdinal returns the ordinal of the parameter of the given function
+// that is referenced by the given expression, if any. Parameters can be
+// referenced either by their name or by their name qualified by the name of
+// the function.
+func udfParamOrdinal(fn cat.Function, expr tree.Expr) (int, bool) {
+	un, ok := expr.(*tree.UnresolvedName)
+	if !ok || un.Star {
+		return 0, false
+	}
+	switch un.NumParts {
+	case 1:
+	case 2:
+		if un.Parts[1] != string(fn.Name().ObjectName) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	for i, n := 0, fn.ParamCount(); i < n; i++ {
+		if un.Parts[0] == string(udfParamName(fn, i)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// isTrivialUDFArg returns true if the given argument of a function call is
+// cheap to evaluate and has no side effects, so that it can be evaluated any
+// number of times.
+func isTrivialUDFArg(expr tree.Expr) bool {
+	switch t := expr.(type) {
+	case *tree.AnnotateTypeExpr:
+		return isTrivialUDFArg(t.Expr)
+	case *tree.ParenExpr:
+		return isTrivialUDFArg(t.Expr)
+	case *tree.UnresolvedName:
+		return !t.Star
+	case *tree.ColumnItem, *tree.Placeholder, *tree.NumVal, *tree.StrVal, tree.Datum:
+		return true
+	}
+	return false
+}
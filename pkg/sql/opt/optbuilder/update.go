@@ -86,6 +86,8 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 
 	var mb mutationBuilder
 	mb.init(b, "update", tab, alias)
+	mb.rlsReturning = resultsNeeded(upd.Returning)
+	mb.initRowLevelSecurityCheck(tree.PolicyCommandUpdate)
 
 	// Build the input expression that selects the rows that will be updated:
 	//
@@ -343,6 +345,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpdate()
 
+	mb.buildTriggers(tree.TriggerEventUpdate)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -205,6 +205,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"ScanFlags":         {fullName: "memo.ScanFlags", passByVal: true},
 		"JoinFlags":         {fullName: "memo.JoinFlags", passByVal: true},
 		"WindowFrame":       {fullName: "memo.WindowFrame", passByVal: true},
+		"GroupingSetList":   {fullName: "memo.GroupingSetList", passByVal: true},
 		"FKCascades":        {fullName: "memo.FKCascades", passByVal: true},
 		"ExplainOptions":    {fullName: "tree.ExplainOptions", passByVal: true},
 		"StatementType":     {fullName: "tree.StatementType", passByVal: true},
@@ -219,6 +220,8 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"Subquery":          {fullName: "tree.Subquery", isPointer: true, usePointerIntern: true},
 		"CreateTable":       {fullName: "tree.CreateTable", isPointer: true, usePointerIntern: true},
 		"CreateStats":       {fullName: "tree.CreateStats", isPointer: true, usePointerIntern: true},
+		"CreateFunction":    {fullName: "tree.CreateFunction", isPointer: true, usePointerIntern: true},
+		"CreateTrigger":     {fullName: "tree.CreateTrigger", isPointer: true, usePointerIntern: true},
 		"TableName":         {fullName: "tree.TableName", isPointer: true, usePointerIntern: true},
 		"Constraint":        {fullName: "constraint.Constraint", isPointer: true, usePointerIntern: true},
 		"FuncProps":         {fullName: "tree.FunctionProperties", isPointer: true, usePointerIntern: true},
@@ -236,6 +239,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"IndexOrdinal":      {fullName: "cat.IndexOrdinal", passByVal: true},
 		"IndexOrdinals":     {fullName: "cat.IndexOrdinals", passByVal: true},
 		"ViewDeps":          {fullName: "opt.ViewDeps", passByVal: true},
+		"FuncDeps":          {fullName: "opt.FuncDeps", passByVal: true},
 		"LockingItem":       {fullName: "tree.LockingItem", isPointer: true},
 		"MaterializeClause": {fullName: "tree.MaterializeClause", passByVal: true},
 		"SpanExpression":    {fullName: "invertedexpr.SpanExpression", isPointer: true, usePointerIntern: true},
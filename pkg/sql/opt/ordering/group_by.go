@@ -26,6 +26,16 @@ func scalarGroupByBuildChildReqOrdering(
 	return parent.(*memo.ScalarGroupByExpr).Ordering
 }
 
+func groupingSetsBuildChildReqOrdering(
+	parent memo.RelExpr, required *physical.OrderingChoice, childIdx int,
+) physical.OrderingChoice {
+	if childIdx != 0 {
+		return physical.OrderingChoice{}
+	}
+	// GroupingSets requires the intra-group ordering in its private.
+	return parent.(*memo.GroupingSetsExpr).Ordering
+}
+
 func groupByCanProvideOrdering(expr memo.RelExpr, required *physical.OrderingChoice) bool {
 	// GroupBy may require a certain ordering of its input, but can also pass
 	// through a stronger ordering on the grouping columns.
@@ -152,6 +152,13 @@ func init() {
 		buildChildReqOrdering: scalarGroupByBuildChildReqOrdering,
 		buildProvidedOrdering: noProvidedOrdering,
 	}
+	funcMap[opt.GroupingSetsOp] = funcs{
+		// GroupingSets returns the groups of each grouping set in turn, so it
+		// cannot provide an ordering on its output columns.
+		canProvideOrdering:    canNeverProvideOrdering,
+		buildChildReqOrdering: groupingSetsBuildChildReqOrdering,
+		buildProvidedOrdering: noProvidedOrdering,
+	}
 	funcMap[opt.GroupByOp] = funcs{
 		canProvideOrdering:    groupByCanProvideOrdering,
 		buildChildReqOrdering: groupByBuildChildReqOrdering,
@@ -238,6 +238,7 @@ func New(catalog cat.Catalog, sql string) *OptTester {
 	ot.evalCtx.SessionData.OptimizerUseMultiColStats = true
 	ot.evalCtx.SessionData.ReorderJoinsLimit = opt.DefaultJoinOrderLimit
 	ot.evalCtx.SessionData.InsertFastPath = true
+	ot.evalCtx.SessionData.TrigramSimilarityThreshold = 0.3
 
 	return ot
 }
@@ -520,7 +521,11 @@ func (ot *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 					if err != nil {
 						d.Fatalf(tb, "error building cascade: %+v", err)
 					}
-					n := tp.Child("cascade")
+					label := "cascade"
+					if c.Trigger {
+						label = "trigger"
+					}
+					n := tp.Child(label)
 					n.Child(strings.TrimRight(ot.FormatExpr(cascade), "\n"))
 					buildCascades(cascade, n, level+1)
 				}
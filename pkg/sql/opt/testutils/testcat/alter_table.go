@@ -27,6 +27,7 @@ import (
 // Supported commands:
 //  - INJECT STATISTICS: imports table statistics from a JSON object.
 //  - ADD CONSTRAINT FOREIGN KEY: add a foreign key reference.
+//  - ENABLE/DISABLE/FORCE/NO FORCE ROW LEVEL SECURITY.
 //
 func (tc *Catalog) AlterTable(stmt *tree.AlterTable) {
 	tn := stmt.Table.ToTableName()
@@ -48,6 +49,18 @@ func (tc *Catalog) AlterTable(stmt *tree.AlterTable) {
 				panic(errors.AssertionFailedf("unsupported constraint type %v", d))
 			}
 
+		case *tree.AlterTableRowLevelSecurity:
+			switch t.Mode {
+			case tree.RowLevelSecurityEnable:
+				tab.RLSEnabled = true
+			case tree.RowLevelSecurityDisable:
+				tab.RLSEnabled = false
+			case tree.RowLevelSecurityForce:
+				tab.RLSForced = true
+			case tree.RowLevelSecurityNoForce:
+				tab.RLSForced = false
+			}
+
 		default:
 			panic(errors.AssertionFailedf("unsupported ALTER TABLE command %T", t))
 		}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// CreateFunction creates a test function from a parsed DDL statement and adds
+// it to the catalog. The function body is stored as-is, so it must refer to
+// parameters by name.
+func (tc *Catalog) CreateFunction(stmt *tree.CreateFunction) *Function {
+	name := stmt.FuncName.ToTableName()
+	tc.qualifyTableName(&name)
+
+	if stmt.Options.Body == nil {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified"))
+	}
+
+	fn := &Function{
+		FuncID:         tc.nextStableID(),
+		FuncName:       name,
+		Params:         make([]FunctionParam, len(stmt.Params)),
+		BodyText:       *stmt.Options.Body,
+		FuncVolatility: stmt.Options.Volatility,
+		Strict:         stmt.Options.NullInputBehavior == tree.FunctionReturnsNullOnNullInput,
+	}
+	if fn.FuncVolatility == 0 {
+		fn.FuncVolatility = tree.VolatilityVolatile
+	}
+	for i := range stmt.Params {
+		fn.Params[i] = FunctionParam{
+			Name: stmt.Params[i].Name,
+			Type: tree.MustBeStaticallyKnownType(stmt.Params[i].Type),
+		}
+	}
+	fn.RetType = tree.MustBeStaticallyKnownType(stmt.ReturnType)
+
+	tc.AddFunction(fn)
+	return fn
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// CreatePolicy is a partial implementation of the CREATE POLICY statement.
+// The expressions are stored as-is, so names in them are resolved in the
+// scope of the table when the policy is applied.
+func (tc *Catalog) CreatePolicy(stmt *tree.CreatePolicy) {
+	tn := stmt.Table
+	// Update the table name to include catalog and schema if not provided.
+	tc.qualifyTableName(&tn)
+	tab := tc.Table(&tn)
+
+	for i := range tab.Policies {
+		if tab.Policies[i].PolicyName == string(stmt.Name) {
+			panic(errors.Newf(`policy "%s" for table "%s" already exists`, stmt.Name, tab.TabName.Table()))
+		}
+	}
+
+	p := Policy{
+		PolicyName:    string(stmt.Name),
+		IsRestrictive: stmt.Restrictive,
+		Cmd:           stmt.Command,
+	}
+	for _, r := range stmt.Roles {
+		p.Roles = append(p.Roles, r.Normalized())
+	}
+	if len(p.Roles) == 0 {
+		p.Roles = []string{security.PublicRole}
+	}
+	if stmt.Using != nil {
+		p.Using = tree.Serialize(stmt.Using)
+	}
+	if stmt.WithCheck != nil {
+		p.WithCheck = tree.Serialize(stmt.WithCheck)
+	}
+	tab.Policies = append(tab.Policies, p)
+	sort.Slice(tab.Policies, func(i, j int) bool {
+		return tab.Policies[i].PolicyName < tab.Policies[j].PolicyName
+	})
+}
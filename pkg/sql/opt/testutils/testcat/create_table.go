@@ -191,7 +191,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.WithoutIndex)
+				tab.addUniqueConstraint(def.Name, def.Columns, def.WithoutIndex, def.Deferrability)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -209,6 +209,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						def.Unique.ConstraintName,
 						tree.IndexElemList{{Column: def.Name}},
 						def.Unique.WithoutIndex,
+						def.Unique.Deferrability,
 					)
 				} else {
 					tab.addIndex(
@@ -453,13 +454,17 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	cols := make([]int, len(columns))
 	for i, c := range columns {
@@ -481,6 +486,7 @@ func (tt *Table) addUniqueConstraint(
 		columnOrdinals: cols,
 		withoutIndex:   withoutIndex,
 		validated:      true,
+		deferrability:  deferrability,
 	}
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
@@ -549,7 +555,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, false /* withoutIndex */, tree.ConstraintDeferrability{},
+		)
 	}
 
 	idx := &Index{
@@ -788,6 +796,15 @@ func (ti *Index) addColumn(
 		// TODO(radu,mjibson): update this when the corresponding type in the real
 		// catalog is fixed (see sql.newOptTable).
 		typ := tt.Columns[ordinal].DatumType()
+		if elem.OpClass != "" {
+			if elem.OpClass != "gin_trgm_ops" && elem.OpClass != "gist_trgm_ops" {
+				panic(fmt.Errorf("operator class %s does not exist", elem.OpClass))
+			}
+			if typ.Family() != types.StringFamily {
+				panic(fmt.Errorf("operator class %s cannot be used with type %s", elem.OpClass, typ))
+			}
+			ti.trigram = true
+		}
 		col.InitVirtualInverted(
 			len(tt.Columns),
 			elem.Column+"_inverted_key",
@@ -849,7 +866,7 @@ func (ti *Index) addColumnByOrdinal(
 	if colType == keyCol || colType == strictKeyCol {
 		typ := col.DatumType()
 		if col.Kind() == cat.VirtualInverted {
-			if !colinfo.ColumnTypeIsInvertedIndexable(typ) {
+			if !colinfo.ColumnTypeIsInvertedIndexable(typ) && !ti.trigram {
 				panic(fmt.Errorf(
 					"column %s of type %s is not allowed as the last column of an inverted index",
 					col.ColName(), typ,
@@ -0,0 +1,55 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// CreateTrigger is a partial implementation of the CREATE TRIGGER statement.
+// The action is stored as-is, so names in it are resolved when the trigger
+// fires.
+func (tc *Catalog) CreateTrigger(stmt *tree.CreateTrigger) {
+	tn := stmt.Table
+	// Update the table name to include catalog and schema if not provided.
+	tc.qualifyTableName(&tn)
+	tab := tc.Table(&tn)
+
+	for i := range tab.Triggers {
+		if tab.Triggers[i].TriggerName == string(stmt.Name) {
+			panic(errors.Newf(`trigger "%s" for relation "%s" already exists`, stmt.Name, tab.TabName.Table()))
+		}
+	}
+
+	tr := Trigger{
+		TriggerName:  string(stmt.Name),
+		IsBefore:     stmt.ActionTime == tree.TriggerBefore,
+		IsForEachRow: stmt.ForEachRow,
+		ActionText:   stmt.Action,
+	}
+	for _, e := range stmt.Events {
+		switch e {
+		case tree.TriggerEventInsert:
+			tr.Insert = true
+		case tree.TriggerEventUpdate:
+			tr.Update = true
+		case tree.TriggerEventDelete:
+			tr.Delete = true
+		}
+	}
+	tab.Triggers = append(tab.Triggers, tr)
+	sort.Slice(tab.Triggers, func(i, j int) bool {
+		return tab.Triggers[i].TriggerName < tab.Triggers[j].TriggerName
+	})
+}
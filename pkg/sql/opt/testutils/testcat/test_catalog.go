@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -45,6 +46,7 @@ const (
 type Catalog struct {
 	tree.TypeReferenceResolver
 	testSchema Schema
+	functions  map[string]*Function
 	counter    int
 }
 
@@ -68,6 +70,7 @@ func New() *Catalog {
 			},
 			dataSources: make(map[string]dataSource),
 		},
+		functions: make(map[string]*Function),
 	}
 }
 
@@ -173,6 +176,19 @@ func (tc *Catalog) ResolveDataSourceByID(
 		"relation [%d] does not exist", id)
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (tc *Catalog) ResolveFunction(
+	_ context.Context, _ cat.Flags, name *tree.UnresolvedObjectName,
+) (cat.Function, error) {
+	toResolve := name.ToTableName()
+	tc.qualifyTableName(&toResolve)
+	if fn, ok := tc.functions[toResolve.FQString()]; ok {
+		return fn, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedFunction,
+		"function %q does not exist", tree.ErrString(name))
+}
+
 // ResolveTypeByOID is part of the cat.Catalog interface.
 func (tc *Catalog) ResolveTypeByOID(context.Context, oid.Oid) (*types.T, error) {
 	return nil, errors.Newf("test catalog cannot handle user defined types")
@@ -202,6 +218,10 @@ func (tc *Catalog) CheckAnyPrivilege(ctx context.Context, o cat.Object) error {
 		if t.Revoked {
 			return pgerror.Newf(pgcode.InsufficientPrivilege, "user does not have privilege to access %v", t.SeqName)
 		}
+	case *Function:
+		if t.Revoked {
+			return pgerror.Newf(pgcode.InsufficientPrivilege, "user does not have privilege to access %v", t.FuncName)
+		}
 	default:
 		panic("invalid Object")
 	}
@@ -223,6 +243,27 @@ func (tc *Catalog) HasRoleOption(ctx context.Context, roleOption roleoption.Opti
 	return true, nil
 }
 
+// HasExplicitRoleOption is part of the cat.Catalog interface. The test user is
+// an admin without any granted role option, so that row-level security
+// policies apply to its queries.
+func (tc *Catalog) HasExplicitRoleOption(
+	ctx context.Context, roleOption roleoption.Option,
+) (bool, error) {
+	return false, nil
+}
+
+// HasOwnership is part of the cat.Catalog interface. The test user does not
+// own any object.
+func (tc *Catalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	return false, nil
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface. The test user is only a
+// member of the public role.
+func (tc *Catalog) IsMemberOfRole(ctx context.Context, role string) (bool, error) {
+	return role == security.PublicRole, nil
+}
+
 // FullyQualifiedName is part of the cat.Catalog interface.
 func (tc *Catalog) FullyQualifiedName(
 	ctx context.Context, ds cat.DataSource,
@@ -327,6 +368,16 @@ func (tc *Catalog) AddSequence(seq *Sequence) {
 	tc.testSchema.dataSources[fq] = seq
 }
 
+// AddFunction adds the given test function to the catalog.
+func (tc *Catalog) AddFunction(fn *Function) {
+	fq := fn.FuncName.FQString()
+	if _, ok := tc.functions[fq]; ok {
+		panic(pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q already exists", tree.ErrString(&fn.FuncName)))
+	}
+	tc.functions[fq] = fn
+}
+
 // ExecuteMultipleDDL parses the given semicolon-separated DDL SQL statements
 // and applies each of them to the test catalog.
 func (tc *Catalog) ExecuteMultipleDDL(sql string) error {
@@ -395,6 +446,18 @@ func (tc *Catalog) ExecuteDDLWithIndexVersion(
 		tc.CreateSequence(stmt)
 		return "", nil
 
+	case *tree.CreateFunction:
+		tc.CreateFunction(stmt)
+		return "", nil
+
+	case *tree.CreateTrigger:
+		tc.CreateTrigger(stmt)
+		return "", nil
+
+	case *tree.CreatePolicy:
+		tc.CreatePolicy(stmt)
+		return "", nil
+
 	case *tree.SetZoneConfig:
 		tc.SetZoneConfig(stmt)
 		return "", nil
@@ -597,6 +660,17 @@ type Table struct {
 	inboundFKs  []ForeignKeyConstraint
 
 	uniqueConstraints []UniqueConstraint
+
+	// Triggers are sorted by name.
+	Triggers []Trigger
+
+	// RLSEnabled and RLSForced are set by ALTER TABLE ... ENABLE/FORCE ROW
+	// LEVEL SECURITY.
+	RLSEnabled bool
+	RLSForced  bool
+
+	// Policies are sorted by name.
+	Policies []Policy
 }
 
 var _ cat.Table = &Table{}
@@ -736,6 +810,36 @@ func (tt *Table) Unique(i int) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return len(tt.Triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	return &tt.Triggers[i]
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityEnabled() bool {
+	return tt.RLSEnabled
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (tt *Table) IsRowLevelSecurityForced() bool {
+	return tt.RLSForced
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (tt *Table) PolicyCount() int {
+	return len(tt.Policies)
+}
+
+// Policy is part of the cat.Table interface.
+func (tt *Table) Policy(i int) cat.Policy {
+	return &tt.Policies[i]
+}
+
 // FindOrdinal returns the ordinal of the column with the given name.
 func (tt *Table) FindOrdinal(name string) int {
 	for i, col := range tt.Columns {
@@ -796,6 +900,9 @@ type Index struct {
 	// inverted index. Otherwise geoConfig is nil.
 	geoConfig *geoindex.Config
 
+	// trigram is true if this is a trigram inverted index on a string column.
+	trigram bool
+
 	// version is the index descriptor version of the index.
 	version descpb.IndexDescriptorVersion
 }
@@ -1071,10 +1178,11 @@ type ForeignKeyConstraint struct {
 	originColumnOrdinals     []int
 	referencedColumnOrdinals []int
 
-	validated    bool
-	matchMethod  tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validated     bool
+	matchMethod   tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1142,6 +1250,11 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // UniqueConstraint implements cat.UniqueConstraint. See that interface
 // for more information on the fields.
 type UniqueConstraint struct {
@@ -1150,6 +1263,7 @@ type UniqueConstraint struct {
 	columnOrdinals []int
 	withoutIndex   bool
 	validated      bool
+	deferrability  tree.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &UniqueConstraint{}
@@ -1190,6 +1304,108 @@ func (u *UniqueConstraint) Validated() bool {
 	return u.validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
+// Trigger implements cat.Trigger. See that interface for more information on
+// the fields.
+type Trigger struct {
+	TriggerName  string
+	IsBefore     bool
+	IsForEachRow bool
+	Insert       bool
+	Update       bool
+	Delete       bool
+	ActionText   string
+}
+
+var _ cat.Trigger = &Trigger{}
+
+// Name is part of the cat.Trigger interface.
+func (t *Trigger) Name() string {
+	return t.TriggerName
+}
+
+// Before is part of the cat.Trigger interface.
+func (t *Trigger) Before() bool {
+	return t.IsBefore
+}
+
+// ForEachRow is part of the cat.Trigger interface.
+func (t *Trigger) ForEachRow() bool {
+	return t.IsForEachRow
+}
+
+// OnInsert is part of the cat.Trigger interface.
+func (t *Trigger) OnInsert() bool {
+	return t.Insert
+}
+
+// OnUpdate is part of the cat.Trigger interface.
+func (t *Trigger) OnUpdate() bool {
+	return t.Update
+}
+
+// OnDelete is part of the cat.Trigger interface.
+func (t *Trigger) OnDelete() bool {
+	return t.Delete
+}
+
+// Action is part of the cat.Trigger interface.
+func (t *Trigger) Action() string {
+	return t.ActionText
+}
+
+// Policy implements cat.Policy. See that interface for more information on
+// the fields.
+type Policy struct {
+	PolicyName    string
+	IsRestrictive bool
+	Cmd           tree.PolicyCommand
+	Roles         []string
+	Using         string
+	WithCheck     string
+}
+
+var _ cat.Policy = &Policy{}
+
+// Name is part of the cat.Policy interface.
+func (p *Policy) Name() string {
+	return p.PolicyName
+}
+
+// Restrictive is part of the cat.Policy interface.
+func (p *Policy) Restrictive() bool {
+	return p.IsRestrictive
+}
+
+// Command is part of the cat.Policy interface.
+func (p *Policy) Command() tree.PolicyCommand {
+	return p.Cmd
+}
+
+// RoleCount is part of the cat.Policy interface.
+func (p *Policy) RoleCount() int {
+	return len(p.Roles)
+}
+
+// Role is part of the cat.Policy interface.
+func (p *Policy) Role(i int) string {
+	return p.Roles[i]
+}
+
+// UsingExpr is part of the cat.Policy interface.
+func (p *Policy) UsingExpr() string {
+	return p.Using
+}
+
+// WithCheckExpr is part of the cat.Policy interface.
+func (p *Policy) WithCheckExpr() string {
+	return p.WithCheck
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
@@ -1241,6 +1457,89 @@ func (ts *Sequence) String() string {
 	return tp.String()
 }
 
+// Function implements the cat.Function interface for testing purposes.
+type Function struct {
+	FuncID         cat.StableID
+	FuncVersion    int
+	FuncName       tree.TableName
+	Params         []FunctionParam
+	RetType        *types.T
+	BodyText       string
+	FuncVolatility tree.Volatility
+	Strict         bool
+
+	// If Revoked is true, then the user has had privileges on the function
+	// revoked.
+	Revoked bool
+}
+
+// FunctionParam is a parameter of a test function.
+type FunctionParam struct {
+	Name tree.Name
+	Type *types.T
+}
+
+var _ cat.Function = &Function{}
+
+// ID is part of the cat.Object interface.
+func (tf *Function) ID() cat.StableID {
+	return tf.FuncID
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (tf *Function) PostgresDescriptorID() cat.StableID {
+	return tf.FuncID
+}
+
+// Equals is part of the cat.Object interface.
+func (tf *Function) Equals(other cat.Object) bool {
+	otherFunction, ok := other.(*Function)
+	if !ok {
+		return false
+	}
+	return tf.FuncID == otherFunction.FuncID && tf.FuncVersion == otherFunction.FuncVersion
+}
+
+// Name is part of the cat.Function interface.
+func (tf *Function) Name() *tree.TableName {
+	return &tf.FuncName
+}
+
+// ParamCount is part of the cat.Function interface.
+func (tf *Function) ParamCount() int {
+	return len(tf.Params)
+}
+
+// ParamName is part of the cat.Function interface.
+func (tf *Function) ParamName(i int) tree.Name {
+	return tf.Params[i].Name
+}
+
+// ParamType is part of the cat.Function interface.
+func (tf *Function) ParamType(i int) *types.T {
+	return tf.Params[i].Type
+}
+
+// ReturnType is part of the cat.Function interface.
+func (tf *Function) ReturnType() *types.T {
+	return tf.RetType
+}
+
+// Volatility is part of the cat.Function interface.
+func (tf *Function) Volatility() tree.Volatility {
+	return tf.FuncVolatility
+}
+
+// ReturnsNullOnNullInput is part of the cat.Function interface.
+func (tf *Function) ReturnsNullOnNullInput() bool {
+	return tf.Strict
+}
+
+// Body is part of the cat.Function interface.
+func (tf *Function) Body() string {
+	return tf.BodyText
+}
+
 // Family implements the cat.Family interface for testing purposes.
 type Family struct {
 	FamName string
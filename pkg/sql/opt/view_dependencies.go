@@ -20,6 +20,9 @@ import (
 // ViewDeps contains information about the dependencies of a view.
 type ViewDeps []ViewDep
 
+// FuncDeps contains the user-defined functions that are called by a view.
+type FuncDeps []cat.Function
+
 // ViewDep contains information about a view dependency.
 type ViewDep struct {
 	DataSource cat.DataSource
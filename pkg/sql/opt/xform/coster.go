@@ -469,6 +469,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 		opt.UpsertDistinctOnOp, opt.EnsureUpsertDistinctOnOp:
 		cost = c.computeGroupingCost(candidate, required)
 
+	case opt.GroupingSetsOp:
+		cost = c.computeGroupingSetsCost(candidate.(*memo.GroupingSetsExpr))
+
 	case opt.LimitOp:
 		cost = c.computeLimitCost(candidate.(*memo.LimitExpr))
 
@@ -1008,6 +1011,31 @@ func (c *coster) computeGroupingCost(grouping memo.RelExpr, required *physical.R
 	return cost
 }
 
+func (c *coster) computeGroupingSetsCost(groupingSets *memo.GroupingSetsExpr) memo.Cost {
+	// Start with the same fixed overhead as the other grouping operators, once
+	// for each grouping set.
+	numSets := memo.Cost(len(groupingSets.Sets))
+	cost := numSets * cpuCostFactor
+
+	// Add the CPU cost of emitting the rows.
+	cost += memo.Cost(groupingSets.Relational().Stats.RowCount) * cpuCostFactor
+
+	// The input is buffered once, and then read and aggregated once for each
+	// grouping set, using a hash table unless the grouping set is empty.
+	inputRowCount := memo.Cost(groupingSets.Input.Relational().Stats.RowCount)
+	cost += inputRowCount * cpuCostFactor
+	aggsCount := memo.Cost(len(groupingSets.Aggregations))
+	for _, set := range groupingSets.Sets {
+		groupingColCount := memo.Cost(set.Len())
+		cost += inputRowCount * (aggsCount + groupingColCount) * cpuCostFactor
+		if groupingColCount > 0 {
+			cost += inputRowCount * cpuCostFactor
+		}
+	}
+
+	return cost
+}
+
 func (c *coster) computeLimitCost(limit *memo.LimitExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
 	cost := memo.Cost(limit.Relational().Stats.RowCount) * cpuCostFactor
@@ -21,11 +21,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -291,6 +293,29 @@ func getDescForDataSource(o cat.DataSource) (*tabledesc.Immutable, error) {
 	}
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunction(
+	ctx context.Context, flags cat.Flags, name *tree.UnresolvedObjectName,
+) (cat.Function, error) {
+	if flags.AvoidDescriptorCaches {
+		defer func(prev bool) {
+			oc.planner.avoidCachedDescriptors = prev
+		}(oc.planner.avoidCachedDescriptors)
+		oc.planner.avoidCachedDescriptors = true
+	}
+
+	fn, desc, err := resolver.ResolveFunction(ctx, oc.planner, name, tree.ObjectLookupFlagsWithRequired())
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure that the current user can access the target schema.
+	if err := oc.planner.canResolveDescUnderSchema(ctx, desc.GetParentSchemaID(), desc); err != nil {
+		return nil, err
+	}
+	return newOptFunction(desc.(*funcdesc.Immutable), fn), nil
+}
+
 // CheckPrivilege is part of the cat.Catalog interface.
 func (oc *optCatalog) CheckPrivilege(ctx context.Context, o cat.Object, priv privilege.Kind) error {
 	desc, err := getDescFromCatalogObjectForPermissions(o)
@@ -326,6 +351,36 @@ func (oc *optCatalog) HasRoleOption(
 	return oc.planner.HasRoleOption(ctx, roleOption)
 }
 
+// HasExplicitRoleOption is part of the cat.Catalog interface.
+func (oc *optCatalog) HasExplicitRoleOption(
+	ctx context.Context, roleOption roleoption.Option,
+) (bool, error) {
+	return oc.planner.HasExplicitRoleOption(ctx, roleOption)
+}
+
+// HasOwnership is part of the cat.Catalog interface.
+func (oc *optCatalog) HasOwnership(ctx context.Context, o cat.Object) (bool, error) {
+	desc, err := getDescFromCatalogObjectForPermissions(o)
+	if err != nil {
+		return false, err
+	}
+	return oc.planner.HasOwnership(ctx, desc)
+}
+
+// IsMemberOfRole is part of the cat.Catalog interface.
+func (oc *optCatalog) IsMemberOfRole(ctx context.Context, role string) (bool, error) {
+	user := oc.planner.User()
+	if role == security.PublicRole || role == user.Normalized() {
+		return true, nil
+	}
+	memberOf, err := oc.planner.MemberOfWithAdminOption(ctx, user)
+	if err != nil {
+		return false, err
+	}
+	_, ok := memberOf[security.MakeSQLUsernameFromPreNormalizedString(role)]
+	return ok, nil
+}
+
 // FullyQualifiedName is part of the cat.Catalog interface.
 func (oc *optCatalog) FullyQualifiedName(
 	ctx context.Context, ds cat.DataSource,
@@ -516,6 +571,81 @@ func (ov *optView) ColumnName(i int) tree.Name {
 	return tree.Name(ov.desc.Columns[i].Name)
 }
 
+// optFunction is a wrapper around funcdesc.Immutable that implements the
+// cat.Object and cat.Function interfaces.
+type optFunction struct {
+	desc *funcdesc.Immutable
+	name tree.TableName
+}
+
+var _ cat.Function = &optFunction{}
+
+func newOptFunction(desc *funcdesc.Immutable, name *tree.TableName) *optFunction {
+	fn := &optFunction{desc: desc, name: *name}
+	fn.name.ExplicitCatalog = true
+	fn.name.ExplicitSchema = true
+	return fn
+}
+
+// ID is part of the cat.Object interface.
+func (of *optFunction) ID() cat.StableID {
+	return cat.StableID(of.desc.ID)
+}
+
+// PostgresDescriptorID is part of the cat.Object interface.
+func (of *optFunction) PostgresDescriptorID() cat.StableID {
+	return cat.StableID(of.desc.ID)
+}
+
+// Equals is part of the cat.Object interface.
+func (of *optFunction) Equals(other cat.Object) bool {
+	otherFunc, ok := other.(*optFunction)
+	if !ok {
+		return false
+	}
+	return of.desc.ID == otherFunc.desc.ID && of.desc.Version == otherFunc.desc.Version
+}
+
+// Name is part of the cat.Function interface.
+func (of *optFunction) Name() *tree.TableName {
+	return &of.name
+}
+
+// ParamCount is part of the cat.Function interface.
+func (of *optFunction) ParamCount() int {
+	return len(of.desc.Params)
+}
+
+// ParamName is part of the cat.Function interface.
+func (of *optFunction) ParamName(i int) tree.Name {
+	return tree.Name(of.desc.Params[i].Name)
+}
+
+// ParamType is part of the cat.Function interface.
+func (of *optFunction) ParamType(i int) *types.T {
+	return of.desc.Params[i].Type
+}
+
+// ReturnType is part of the cat.Function interface.
+func (of *optFunction) ReturnType() *types.T {
+	return of.desc.ReturnType
+}
+
+// Volatility is part of the cat.Function interface.
+func (of *optFunction) Volatility() tree.Volatility {
+	return funcdesc.VolatilityToTree(of.desc.FunctionDescriptor.Volatility)
+}
+
+// ReturnsNullOnNullInput is part of the cat.Function interface.
+func (of *optFunction) ReturnsNullOnNullInput() bool {
+	return of.desc.ReturnsNullOnNullInput()
+}
+
+// Body is part of the cat.Function interface.
+func (of *optFunction) Body() string {
+	return of.desc.FunctionBody
+}
+
 // optSequence is a wrapper around sqlbase.Immutable that
 // implements the cat.Object and cat.DataSource interfaces.
 type optSequence struct {
@@ -775,6 +905,10 @@ func newOptTable(
 			columns:      u.ColumnIDs,
 			withoutIndex: true,
 			validity:     u.Validity,
+			deferrability: tree.ConstraintDeferrability{
+				Deferrable:        u.Deferrable,
+				InitiallyDeferred: u.InitiallyDeferred,
+			},
 		})
 	}
 
@@ -790,6 +924,10 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability: tree.ConstraintDeferrability{
+				Deferrable:        fk.Deferrable,
+				InitiallyDeferred: fk.InitiallyDeferred,
+			},
 		})
 	}
 	for i := range ot.desc.InboundFKs {
@@ -804,6 +942,10 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability: tree.ConstraintDeferrability{
+				Deferrable:        fk.Deferrable,
+				InitiallyDeferred: fk.InitiallyDeferred,
+			},
 		})
 	}
 
@@ -1076,6 +1218,36 @@ func (ot *optTable) Unique(i int) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.desc.GetTriggers())
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return optTrigger{desc: &ot.desc.GetTriggers()[i]}
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityEnabled() bool {
+	return ot.desc.GetRowLevelSecurity()
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optTable) IsRowLevelSecurityForced() bool {
+	return ot.desc.GetForceRowLevelSecurity()
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optTable) PolicyCount() int {
+	return len(ot.desc.GetPolicies())
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optTable) Policy(i int) cat.Policy {
+	return optPolicy{desc: &ot.desc.GetPolicies()[i]}
+}
+
 // lookupColumnOrdinal returns the ordinal of the column with the given ID. A
 // cache makes the lookup O(1).
 func (ot *optTable) lookupColumnOrdinal(colID descpb.ColumnID) (int, error) {
@@ -1474,8 +1646,9 @@ type optUniqueConstraint struct {
 	table   cat.StableID
 	columns []descpb.ColumnID
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &optUniqueConstraint{}
@@ -1518,6 +1691,108 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
+// optTrigger implements cat.Trigger and wraps a trigger stored in a table
+// descriptor.
+type optTrigger struct {
+	desc *descpb.TableDescriptor_Trigger
+}
+
+var _ cat.Trigger = optTrigger{}
+
+// Name is part of the cat.Trigger interface.
+func (t optTrigger) Name() string {
+	return t.desc.Name
+}
+
+// Before is part of the cat.Trigger interface.
+func (t optTrigger) Before() bool {
+	return t.desc.ActionTime == descpb.TableDescriptor_Trigger_BEFORE
+}
+
+// ForEachRow is part of the cat.Trigger interface.
+func (t optTrigger) ForEachRow() bool {
+	return t.desc.ForEachRow
+}
+
+// OnInsert is part of the cat.Trigger interface.
+func (t optTrigger) OnInsert() bool {
+	return t.desc.OnInsert
+}
+
+// OnUpdate is part of the cat.Trigger interface.
+func (t optTrigger) OnUpdate() bool {
+	return t.desc.OnUpdate
+}
+
+// OnDelete is part of the cat.Trigger interface.
+func (t optTrigger) OnDelete() bool {
+	return t.desc.OnDelete
+}
+
+// Action is part of the cat.Trigger interface.
+func (t optTrigger) Action() string {
+	return t.desc.ActionStatement
+}
+
+// optPolicy implements cat.Policy and wraps a row-level security policy
+// stored in a table descriptor.
+type optPolicy struct {
+	desc *descpb.TableDescriptor_Policy
+}
+
+var _ cat.Policy = optPolicy{}
+
+// Name is part of the cat.Policy interface.
+func (p optPolicy) Name() string {
+	return p.desc.Name
+}
+
+// Restrictive is part of the cat.Policy interface.
+func (p optPolicy) Restrictive() bool {
+	return p.desc.Restrictive
+}
+
+// Command is part of the cat.Policy interface.
+func (p optPolicy) Command() tree.PolicyCommand {
+	switch p.desc.Command {
+	case descpb.TableDescriptor_Policy_SELECT:
+		return tree.PolicyCommandSelect
+	case descpb.TableDescriptor_Policy_INSERT:
+		return tree.PolicyCommandInsert
+	case descpb.TableDescriptor_Policy_UPDATE:
+		return tree.PolicyCommandUpdate
+	case descpb.TableDescriptor_Policy_DELETE:
+		return tree.PolicyCommandDelete
+	default:
+		return tree.PolicyCommandAll
+	}
+}
+
+// RoleCount is part of the cat.Policy interface.
+func (p optPolicy) RoleCount() int {
+	return len(p.desc.Roles)
+}
+
+// Role is part of the cat.Policy interface.
+func (p optPolicy) Role(i int) string {
+	return p.desc.Roles[i]
+}
+
+// UsingExpr is part of the cat.Policy interface.
+func (p optPolicy) UsingExpr() string {
+	return p.desc.UsingExpr
+}
+
+// WithCheckExpr is part of the cat.Policy interface.
+func (p optPolicy) WithCheckExpr() string {
+	return p.desc.WithCheckExpr
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1531,10 +1806,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction descpb.ForeignKeyReference_Action
-	updateAction descpb.ForeignKeyReference_Action
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  descpb.ForeignKeyReference_Action
+	updateAction  descpb.ForeignKeyReference_Action
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1606,6 +1882,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc *tabledesc.Immutable
@@ -1900,6 +2181,36 @@ func (ot *optVirtualTable) Unique(i int) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
+// IsRowLevelSecurityEnabled is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityEnabled() bool {
+	return false
+}
+
+// IsRowLevelSecurityForced is part of the cat.Table interface.
+func (ot *optVirtualTable) IsRowLevelSecurityForced() bool {
+	return false
+}
+
+// PolicyCount is part of the cat.Table interface.
+func (ot *optVirtualTable) PolicyCount() int {
+	return 0
+}
+
+// Policy is part of the cat.Table interface.
+func (ot *optVirtualTable) Policy(i int) cat.Policy {
+	panic(errors.AssertionFailedf("no policies"))
+}
+
 // optVirtualIndex is a dummy implementation of cat.Index for the indexes
 // reported by a virtual table. The index assumes that table column 0 is a dummy
 // PK column.
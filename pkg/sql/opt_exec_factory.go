@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -1174,7 +1175,7 @@ func (ef *execFactory) ConstructInsert(
 		source: input.(planNode),
 		run: insertRun{
 			ti:         tableInserter{ri: ri},
-			checkOrds:  checkOrdSet,
+			checkOrds:  makeCheckSet(table, checkOrdSet),
 			insertCols: ri.InsertCols,
 		},
 	}
@@ -1241,7 +1242,7 @@ func (ef *execFactory) ConstructInsertFastPath(
 		run: insertFastPathRun{
 			insertRun: insertRun{
 				ti:         tableInserter{ri: ri},
-				checkOrds:  checkOrdSet,
+				checkOrds:  makeCheckSet(table, checkOrdSet),
 				insertCols: ri.InsertCols,
 			},
 		},
@@ -1343,7 +1344,7 @@ func (ef *execFactory) ConstructUpdate(
 		source: input.(planNode),
 		run: updateRun{
 			tu:        tableUpdater{ru: ru},
-			checkOrds: checks,
+			checkOrds: makeCheckSet(table, checks),
 			iVarContainerForComputedCols: schemaexpr.RowIndexedVarContainer{
 				CurSourceRow: make(tree.Datums, len(ru.FetchCols)),
 				Cols:         ru.FetchCols,
@@ -1449,7 +1450,7 @@ func (ef *execFactory) ConstructUpsert(
 	*ups = upsertNode{
 		source: input.(planNode),
 		run: upsertRun{
-			checkOrds:  checks,
+			checkOrds:  makeCheckSet(table, checks),
 			insertCols: ri.InsertCols,
 			tw: optTableUpserter{
 				ri:            ri,
@@ -1635,6 +1636,7 @@ func (ef *execFactory) ConstructCreateView(
 	viewQuery string,
 	columns colinfo.ResultColumns,
 	deps opt.ViewDeps,
+	funcDeps opt.FuncDeps,
 ) (exec.Node, error) {
 
 	if err := checkSchemaChangeEnabled(
@@ -1645,6 +1647,84 @@ func (ef *execFactory) ConstructCreateView(
 		return nil, err
 	}
 
+	planDeps, err := makePlanDependencies(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	fns := make([]*funcdesc.Immutable, len(funcDeps))
+	for i := range funcDeps {
+		fns[i] = funcDeps[i].(*optFunction).desc
+	}
+
+	return &createViewNode{
+		viewName:     viewName,
+		ifNotExists:  ifNotExists,
+		replace:      replace,
+		materialized: materialized,
+		persistence:  persistence,
+		viewQuery:    viewQuery,
+		dbDesc:       schema.(*optSchema).database,
+		columns:      columns,
+		planDeps:     planDeps,
+		funcDeps:     fns,
+	}, nil
+}
+
+// ConstructCreateFunction is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateFunction(
+	schema cat.Schema,
+	funcName *tree.TableName,
+	cf *tree.CreateFunction,
+	body string,
+	deps opt.ViewDeps,
+) (exec.Node, error) {
+
+	if err := checkSchemaChangeEnabled(
+		ef.planner.EvalContext().Context,
+		ef.planner.ExecCfg(),
+		"CREATE FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	planDeps, err := makePlanDependencies(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	return &createFunctionNode{
+		n:        cf,
+		funcName: funcName,
+		body:     body,
+		dbDesc:   schema.(*optSchema).database,
+		planDeps: planDeps,
+	}, nil
+}
+
+// ConstructCreateTrigger is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateTrigger(
+	table cat.Table, ct *tree.CreateTrigger, action string,
+) (exec.Node, error) {
+	if err := checkSchemaChangeEnabled(
+		ef.planner.EvalContext().Context,
+		ef.planner.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{
+		n:       ct,
+		tableID: table.(*optTable).desc.GetID(),
+		action:  action,
+	}, nil
+}
+
+// makePlanDependencies converts the dependencies collected by the optimizer
+// for a view or a function into the back-references to be stored in the
+// descriptors of the relations they depend on.
+func makePlanDependencies(deps opt.ViewDeps) (planDependencies, error) {
 	planDeps := make(planDependencies, len(deps))
 	for _, d := range deps {
 		desc, err := getDescForDataSource(d.DataSource)
@@ -1667,18 +1747,7 @@ func (ef *execFactory) ConstructCreateView(
 		entry.deps = append(entry.deps, ref)
 		planDeps[desc.ID] = entry
 	}
-
-	return &createViewNode{
-		viewName:     viewName,
-		ifNotExists:  ifNotExists,
-		replace:      replace,
-		materialized: materialized,
-		persistence:  persistence,
-		viewQuery:    viewQuery,
-		dbDesc:       schema.(*optSchema).database,
-		columns:      columns,
-		planDeps:     planDeps,
-	}, nil
+	return planDeps, nil
 }
 
 // ConstructSequenceSelect is part of the exec.Factory interface.
@@ -1695,11 +1764,12 @@ func (ef *execFactory) ConstructSaveTable(
 
 // ConstructErrorIfRows is part of the exec.Factory interface.
 func (ef *execFactory) ConstructErrorIfRows(
-	input exec.Node, mkErr exec.MkErrFn,
+	input exec.Node, mkErr exec.MkErrFn, deferrable *exec.DeferrableCheck,
 ) (exec.Node, error) {
 	return &errorIfRowsNode{
-		plan:  input.(planNode),
-		mkErr: mkErr,
+		plan:       input.(planNode),
+		mkErr:      mkErr,
+		deferrable: deferrable,
 	}, nil
 }
 
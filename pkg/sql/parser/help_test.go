@@ -143,6 +143,16 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TABLE blah AS SELECT 1 ??`, `SELECT`},
 
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION f ??`, `CREATE FUNCTION`},
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE CONSTRAINT TRIGGER tr ??`, `CREATE TRIGGER`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+
+		{`CREATE POLICY ??`, `CREATE POLICY`},
+		{`DROP POLICY ??`, `DROP POLICY`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
@@ -214,6 +224,13 @@ func TestContextualHelp(t *testing.T) {
 		{`DEALLOCATE ALL ??`, `DEALLOCATE`},
 		{`DEALLOCATE PREPARE ??`, `DEALLOCATE`},
 
+		{`DECLARE ??`, `DECLARE`},
+		{`DECLARE foo ??`, `DECLARE`},
+		{`FETCH ??`, `FETCH`},
+		{`FETCH NEXT ??`, `FETCH`},
+		{`MOVE ??`, `MOVE`},
+		{`CLOSE ??`, `CLOSE`},
+
 		{`INSERT INTO ??`, `INSERT`},
 		{`INSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`INSERT INTO blah VALUES (1) RETURNING ??`, `INSERT`},
@@ -388,6 +405,8 @@ func TestContextualHelp(t *testing.T) {
 		{`SET SESSION blah TO 42 ??`, `SET SESSION`},
 
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
 		{`SET TIME ZONE 'UTC' ??`, `SET SESSION`},
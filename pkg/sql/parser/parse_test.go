@@ -124,6 +124,8 @@ func TestParse(t *testing.T) {
 		{`CREATE INVERTED INDEX a ON b (c) WHERE d > 3`},
 		{`CREATE INVERTED INDEX a ON b (c) INTERLEAVE IN PARENT d (e)`},
 		{`CREATE INVERTED INDEX IF NOT EXISTS a ON b (c) WHERE d > 3`},
+		{`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
+		{`CREATE INVERTED INDEX a ON b (c, d gist_trgm_ops)`},
 		{`CREATE INDEX a ON b (c) WITH (fillfactor = 100, y_bounds = 50)`},
 
 		{`CREATE INDEX ON a ((a + b))`},
@@ -233,6 +235,13 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON UPDATE SET NULL)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT)`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT s FOREIGN KEY (b, c) REFERENCES other (x, y) MATCH FULL ON DELETE SET DEFAULT ON UPDATE SET NULL)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8 REFERENCES other (x) DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8 REFERENCES other (x) ON UPDATE CASCADE DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8 UNIQUE WITHOUT INDEX DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, CONSTRAINT d UNIQUE WITHOUT INDEX (b) DEFERRABLE INITIALLY DEFERRED)`},
+		{`ALTER TABLE a ADD CONSTRAINT s FOREIGN KEY (b) REFERENCES other DEFERRABLE NOT VALID`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, INDEX d (b, c))`},
 		{`CREATE TABLE a (b INT8, c STRING, CONSTRAINT d UNIQUE (b, c))`},
@@ -242,6 +251,7 @@ func TestParse(t *testing.T) {
 		{`CREATE TABLE a (b INT8, UNIQUE (b) STORING (c))`},
 		{`CREATE TABLE a (b INT8, INDEX (b))`},
 		{`CREATE TABLE a (b INT8, INVERTED INDEX (b))`},
+		{`CREATE TABLE a (b STRING, INVERTED INDEX (b gin_trgm_ops))`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON UPDATE RESTRICT)`},
 		{`CREATE TABLE a (b INT8, c INT8 REFERENCES foo ON DELETE RESTRICT)`},
@@ -417,6 +427,33 @@ func TestParse(t *testing.T) {
 		{`CREATE TYPE a.b AS ENUM ('a', 'b', 'c')`},
 		{`CREATE TYPE a.b.c AS ENUM ('a', 'b', 'c')`},
 
+		{`CREATE FUNCTION f() RETURNS INT8 AS 'SELECT 1'`},
+		{`CREATE OR REPLACE FUNCTION f() RETURNS INT8 AS 'SELECT 1'`},
+		{`CREATE FUNCTION sc.f(INT8, b STRING) RETURNS STRING LANGUAGE sql AS 'SELECT b'`},
+		{`CREATE FUNCTION db.sc.f(a INT8[]) RETURNS INT8 IMMUTABLE AS 'SELECT a[1]'`},
+		{`CREATE FUNCTION f(a INT8) RETURNS INT8 LANGUAGE sql STABLE RETURNS NULL ON NULL INPUT AS 'SELECT a'`},
+		{`CREATE FUNCTION f(a INT8) RETURNS INT8 VOLATILE CALLED ON NULL INPUT AS 'SELECT a'`},
+		{`CREATE FUNCTION f() RETURNS INT8 LANGUAGE sql`},
+		{`CREATE FUNCTION f(a t) RETURNS t AS 'SELECT a'`},
+
+		{`DROP FUNCTION f`},
+		{`DROP FUNCTION f()`},
+		{`DROP FUNCTION sc.f(INT8, STRING), db.sc.g`},
+		{`DROP FUNCTION IF EXISTS f(INT8) CASCADE`},
+		{`DROP FUNCTION IF EXISTS f, g RESTRICT`},
+
+		{`CREATE TRIGGER tr BEFORE INSERT ON t FOR EACH ROW AS 'INSERT INTO audit SELECT * FROM new'`},
+		{`CREATE TRIGGER tr AFTER INSERT OR UPDATE OR DELETE ON db.sc.t FOR EACH STATEMENT AS 'SELECT 1'`},
+		{`CREATE CONSTRAINT TRIGGER tr AFTER DELETE ON t FOR EACH ROW AS 'DELETE FROM u WHERE a IN (SELECT a FROM old)'`},
+		{`DROP TRIGGER tr ON t`},
+		{`DROP TRIGGER IF EXISTS tr ON sc.t CASCADE`},
+
+		{`CREATE POLICY p ON t`},
+		{`CREATE POLICY p ON db.sc.t AS RESTRICTIVE FOR UPDATE TO foo, bar USING (a = current_user()) WITH CHECK (b > 0)`},
+		{`CREATE POLICY p ON t FOR SELECT TO public USING (tenant = current_setting('app.tenant'))`},
+		{`DROP POLICY p ON t`},
+		{`DROP POLICY IF EXISTS p ON sc.t CASCADE`},
+
 		{`DROP SCHEMA a`},
 		{`DROP SCHEMA a, b`},
 		{`DROP SCHEMA IF EXISTS a, b, c`},
@@ -649,6 +686,7 @@ func TestParse(t *testing.T) {
 		{`SHOW RANGE FROM INDEX d.t@i FOR ROW (1, 2)`},
 		{`SHOW RANGE FROM INDEX t@i FOR ROW (1, 2)`},
 		{`SHOW RANGE FROM INDEX i FOR ROW (1, 2)`},
+		{`SHOW RANGE 1 LOCALITIES`},
 		{`SHOW RANGES FROM TABLE d.t`},
 		{`EXPLAIN SHOW RANGES FROM TABLE d.t`},
 		{`SHOW RANGES FROM TABLE t`},
@@ -757,6 +795,25 @@ func TestParse(t *testing.T) {
 		{`DEALLOCATE a`},
 		{`DEALLOCATE ALL`},
 
+		{`DECLARE a CURSOR FOR SELECT 1`},
+		{`DECLARE a BINARY CURSOR FOR SELECT 1`},
+		{`DECLARE a INSENSITIVE SCROLL CURSOR WITH HOLD FOR SELECT 1`},
+		{`DECLARE a ASENSITIVE NO SCROLL CURSOR FOR SELECT * FROM t`},
+		{`FETCH 1 a`},
+		{`FETCH 3 a`},
+		{`FETCH -3 a`},
+		{`FETCH ALL a`},
+		{`FETCH BACKWARD ALL a`},
+		{`FETCH FIRST a`},
+		{`FETCH LAST a`},
+		{`FETCH ABSOLUTE 3 a`},
+		{`FETCH RELATIVE -3 a`},
+		{`MOVE 1 a`},
+		{`MOVE ALL a`},
+		{`MOVE ABSOLUTE -1 a`},
+		{`CLOSE a`},
+		{`CLOSE ALL`},
+
 		// Tables are the default, but can also be specified with
 		// GRANT x ON TABLE y. However, the stringer does not output TABLE.
 		{`GRANT SELECT ON TABLE foo TO root`},
@@ -1125,6 +1182,12 @@ func TestParse(t *testing.T) {
 		{`SELECT 1 FROM t GROUP BY a`},
 		{`SELECT 1 FROM t GROUP BY a, b`},
 		{`SELECT 1 FROM t GROUP BY ()`},
+		{`SELECT 1 FROM t GROUP BY ROLLUP (a, b)`},
+		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b, (c, d))`},
+		{`SELECT 1 FROM t GROUP BY CUBE (a, b)`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (a, (b, c), ())`},
+		{`SELECT 1 FROM t GROUP BY GROUPING SETS (a, ROLLUP (b, c), CUBE (d), GROUPING SETS (e, f))`},
+		{`SELECT grouping(a, b), count(*) FROM t GROUP BY ROLLUP (a, b)`},
 		{`SELECT sum(x ORDER BY y) FROM t`},
 		{`SELECT sum(x ORDER BY y, z) FROM t`},
 
@@ -1273,6 +1336,11 @@ func TestParse(t *testing.T) {
 		{`SET a = 3.0`},
 		{`SET a = $1`},
 		{`SET a = off`},
+		{`SET CONSTRAINTS ALL DEFERRED`},
+		{`SET CONSTRAINTS ALL IMMEDIATE`},
+		{`SET CONSTRAINTS a DEFERRED`},
+		{`SET CONSTRAINTS a, b IMMEDIATE`},
+
 		{`SET TRANSACTION READ ONLY`},
 		{`SET TRANSACTION READ WRITE`},
 		{`SET TRANSACTION ISOLATION LEVEL SERIALIZABLE`},
@@ -1456,6 +1524,9 @@ func TestParse(t *testing.T) {
 		{`ALTER TABLE IF EXISTS a SET SCHEMA s`},
 
 		{`ALTER TABLE a OWNER TO foo`},
+		{`ALTER TABLE a ENABLE ROW LEVEL SECURITY`},
+		{`ALTER TABLE a DISABLE ROW LEVEL SECURITY`},
+		{`ALTER TABLE a FORCE ROW LEVEL SECURITY, NO FORCE ROW LEVEL SECURITY`},
 		{`ALTER TABLE IF EXISTS a OWNER TO foo`},
 
 		{`ALTER VIEW v SET SCHEMA s`},
@@ -1476,6 +1547,12 @@ func TestParse(t *testing.T) {
 		{`COPY crdb_internal.file_upload FROM STDIN WITH destination = 'filename'`},
 		{`COPY t (a, b, c) FROM STDIN WITH BINARY`},
 		{`COPY crdb_internal.file_upload FROM STDIN WITH BINARY destination = 'filename'`},
+		{`COPY t TO STDOUT`},
+		{`COPY t (a, b, c) TO STDOUT`},
+		{`COPY t TO STDOUT WITH BINARY`},
+		{`COPY t TO STDOUT WITH CSV HEADER DELIMITER '|' NULL 'n/a'`},
+		{`COPY (SELECT a, b FROM t WHERE c > 1) TO STDOUT WITH CSV`},
+		{`COPY (INSERT INTO t VALUES (1) RETURNING a) TO STDOUT`},
 
 		{`ALTER TABLE a SPLIT AT VALUES (1)`},
 		{`EXPLAIN ALTER TABLE a SPLIT AT VALUES (1)`},
@@ -1780,6 +1857,22 @@ func TestParse2(t *testing.T) {
 		sql      string
 		expected string
 	}{
+		{`CREATE FUNCTION f(a INT) RETURNS INT AS 'SELECT a' STRICT LANGUAGE sql IMMUTABLE`,
+			`CREATE FUNCTION f(a INT8) RETURNS INT8 LANGUAGE sql IMMUTABLE RETURNS NULL ON NULL INPUT AS 'SELECT a'`},
+		{`DROP FUNCTION f(int, text)`,
+			`DROP FUNCTION f(INT8, STRING)`},
+		{`CREATE TRIGGER tr AFTER UPDATE ON t AS 'SELECT 1'`,
+			`CREATE TRIGGER tr AFTER UPDATE ON t FOR EACH STATEMENT AS 'SELECT 1'`},
+		{`CREATE TRIGGER tr BEFORE DELETE ON t FOR ROW AS 'SELECT 1'`,
+			`CREATE TRIGGER tr BEFORE DELETE ON t FOR EACH ROW AS 'SELECT 1'`},
+		{`CREATE POLICY p ON t AS PERMISSIVE FOR ALL USING (true)`,
+			`CREATE POLICY p ON t USING (true)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY DEFERRED)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY DEFERRED)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other DEFERRABLE)`},
+		{`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other INITIALLY IMMEDIATE)`,
+			`CREATE TABLE a (b INT8, FOREIGN KEY (b) REFERENCES other)`},
 		{`CREATE DATABASE a WITH ENCODING = 'foo'`,
 			`CREATE DATABASE a ENCODING = 'foo'`},
 		{`CREATE DATABASE a TEMPLATE = template0`,
@@ -1829,6 +1922,10 @@ func TestParse2(t *testing.T) {
 			`CREATE INVERTED INDEX a ON b (c)`},
 		{`CREATE UNIQUE INDEX a ON b USING GIN (c)`,
 			`CREATE UNIQUE INVERTED INDEX a ON b (c)`},
+		{`CREATE INDEX a ON b USING GIN (c gin_trgm_ops)`,
+			`CREATE INVERTED INDEX a ON b (c gin_trgm_ops)`},
+		{`CREATE INDEX a ON b USING GIST (c gist_trgm_ops)`,
+			`CREATE INVERTED INDEX a ON b (c gist_trgm_ops)`},
 
 		{`CREATE INDEX ON a (a, (lower(b)))`,
 			`CREATE INDEX ON a (a, lower(b))`},
@@ -2289,6 +2386,23 @@ $function$`,
 		{`DEALLOCATE PREPARE ALL`,
 			`DEALLOCATE ALL`},
 
+		{`DECLARE a ASENSITIVE CURSOR WITHOUT HOLD FOR SELECT 1`, `DECLARE a ASENSITIVE CURSOR FOR SELECT 1`},
+		{`FETCH a`, `FETCH 1 a`},
+		{`FETCH FROM a`, `FETCH 1 a`},
+		{`FETCH NEXT a`, `FETCH 1 a`},
+		{`FETCH NEXT IN a`, `FETCH 1 a`},
+		{`FETCH PRIOR FROM a`, `FETCH -1 a`},
+		{`FETCH FORWARD a`, `FETCH 1 a`},
+		{`FETCH BACKWARD a`, `FETCH -1 a`},
+		{`FETCH FORWARD 3 FROM a`, `FETCH 3 a`},
+		{`FETCH BACKWARD 3 IN a`, `FETCH -3 a`},
+		{`FETCH BACKWARD -3 a`, `FETCH 3 a`},
+		{`FETCH FORWARD ALL a`, `FETCH ALL a`},
+		{`FETCH ALL FROM a`, `FETCH ALL a`},
+		{`FETCH FIRST IN a`, `FETCH FIRST a`},
+		{`MOVE NEXT a`, `MOVE 1 a`},
+		{`MOVE BACKWARD ALL IN a`, `MOVE BACKWARD ALL a`},
+
 		{`CANCEL JOB a`, `CANCEL JOBS VALUES (a)`},
 		{`EXPLAIN CANCEL JOB a`, `EXPLAIN CANCEL JOBS VALUES (a)`},
 		{`CANCEL JOBS FOR SCHEDULE a`, `CANCEL JOBS FOR SCHEDULES VALUES (a)`},
@@ -2391,6 +2505,10 @@ $function$`,
 			`COPY t (a, b, c) FROM STDIN WITH BINARY`},
 		{`COPY t (a, b, c) FROM STDIN destination = 'filename' BINARY`,
 			`COPY t (a, b, c) FROM STDIN WITH BINARY destination = 'filename'`},
+		{`COPY t TO STDOUT DELIMITER AS ',' NULL AS '' CSV`,
+			`COPY t TO STDOUT WITH CSV DELIMITER ',' NULL ''`},
+		{`COPY (VALUES (1)) TO STDOUT HEADER CSV`,
+			`COPY (VALUES (1)) TO STDOUT WITH CSV HEADER`},
 
 		// Identifier handling for zone configs.
 
@@ -2570,6 +2688,10 @@ SKIP_MISSING_FOREIGN_KEYS, SKIP_MISSING_SEQUENCES, SKIP_MISSING_SEQUENCE_OWNERS,
 			`ALTER ROLE 'foo' WITH CREATELOGIN`},
 		{`ALTER ROLE foo NOCREATELOGIN`,
 			`ALTER ROLE 'foo' WITH NOCREATELOGIN`},
+		{`ALTER ROLE foo BYPASSRLS`,
+			`ALTER ROLE 'foo' WITH BYPASSRLS`},
+		{`CREATE ROLE foo NOBYPASSRLS`,
+			`CREATE ROLE 'foo' WITH NOBYPASSRLS`},
 		{`DROP ROLE foo, bar`,
 			`DROP ROLE 'foo', 'bar'`},
 		{`DROP ROLE IF EXISTS foo, bar`,
@@ -3144,16 +3266,14 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE ACCESS METHOD a`, 0, `create access method`, ``},
 
 		{`COPY x FROM STDIN WHERE a = b`, 54580, ``, ``},
+		{`COPY x FROM STDIN WITH CSV`, 0, `copy from csv, delimiter, null or header`, ``},
 
 		{`CREATE AGGREGATE a`, 0, `create aggregate`, ``},
 		{`CREATE CAST a`, 0, `create cast`, ``},
-		{`CREATE CONSTRAINT TRIGGER a`, 28296, `create constraint`, ``},
 		{`CREATE CONVERSION a`, 0, `create conversion`, ``},
 		{`CREATE DEFAULT CONVERSION a`, 0, `create def conv`, ``},
 		{`CREATE FOREIGN DATA WRAPPER a`, 0, `create fdw`, ``},
 		{`CREATE FOREIGN TABLE a`, 0, `create foreign table`, ``},
-		{`CREATE FUNCTION a`, 17511, `create`, ``},
-		{`CREATE OR REPLACE FUNCTION a`, 17511, `create`, ``},
 		{`CREATE LANGUAGE a`, 17511, `create language a`, ``},
 		{`CREATE OPERATOR a`, 0, `create operator`, ``},
 		{`CREATE PUBLICATION a`, 0, `create publication`, ``},
@@ -3162,7 +3282,8 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE SUBSCRIPTION a`, 0, `create subscription`, ``},
 		{`CREATE TABLESPACE a`, 54113, `create tablespace`, ``},
 		{`CREATE TEXT SEARCH a`, 7821, `create text`, ``},
-		{`CREATE TRIGGER a`, 28296, `create`, ``},
+		{`CREATE TRIGGER a BEFORE INSERT ON t EXECUTE FUNCTION f()`, 28296, `execute function`, ``},
+		{`CREATE TRIGGER a BEFORE TRUNCATE ON t AS 'SELECT 1'`, 28296, `truncate`, ``},
 
 		{`DROP ACCESS METHOD a`, 0, `drop access method`, ``},
 		{`DROP AGGREGATE a`, 0, `drop aggregate`, ``},
@@ -3173,7 +3294,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP EXTENSION a`, 0, `drop extension a`, ``},
 		{`DROP FOREIGN TABLE a`, 0, `drop foreign table`, ``},
 		{`DROP FOREIGN DATA WRAPPER a`, 0, `drop fdw`, ``},
-		{`DROP FUNCTION a`, 17511, `drop `, ``},
 		{`DROP LANGUAGE a`, 17511, `drop language a`, ``},
 		{`DROP OPERATOR a`, 0, `drop operator`, ``},
 		{`DROP PUBLICATION a`, 0, `drop publication`, ``},
@@ -3181,14 +3301,12 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`DROP SERVER a`, 0, `drop server`, ``},
 		{`DROP SUBSCRIPTION a`, 0, `drop subscription`, ``},
 		{`DROP TEXT SEARCH a`, 7821, `drop text`, ``},
-		{`DROP TRIGGER a`, 28296, `drop`, ``},
 
 		{`DISCARD PLANS`, 0, `discard plans`, ``},
 		{`DISCARD SEQUENCES`, 0, `discard sequences`, ``},
 		{`DISCARD TEMP`, 0, `discard temp`, ``},
 		{`DISCARD TEMPORARY`, 0, `discard temp`, ``},
 
-		{`SET CONSTRAINTS foo`, 0, `set constraints`, ``},
 		{`SET LOCAL foo = bar`, 32562, ``, ``},
 		{`SET foo FROM CURRENT`, 0, `set from current`, ``},
 
@@ -3203,13 +3321,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE TABLE a(b INT8 REFERENCES c(x) MATCH PARTIAL`, 20305, `match partial`, ``},
 		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) MATCH PARTIAL)`, 20305, `match partial`, ``},
 
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY DEFERRED)`, 31632, `initially deferred`, ``},
-		{`CREATE TABLE a(b INT8, FOREIGN KEY (b) REFERENCES c(x) DEFERRABLE INITIALLY IMMEDIATE)`, 31632, `initially immediate`, ``},
-		{`CREATE TABLE a(b INT8, UNIQUE (b) DEFERRABLE)`, 31632, `deferrable`, ``},
-		{`CREATE TABLE a(b INT8, CHECK (b > 0) DEFERRABLE)`, 31632, `deferrable`, ``},
 
 		{`CREATE TABLE a (LIKE b INCLUDING COMMENTS)`, 47071, `like table`, ``},
 		{`CREATE TABLE a (LIKE b INCLUDING IDENTITY)`, 47071, `like table`, ``},
@@ -3255,8 +3366,6 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`CREATE INDEX a ON b USING SPGIST (c)`, 0, `index using spgist`, ``},
 		{`CREATE INDEX a ON b USING BRIN (c)`, 0, `index using brin`, ``},
 
-		{`CREATE INDEX a ON b(c gin_trgm_ops)`, 41285, `index using gin_trgm_ops`, ``},
-		{`CREATE INDEX a ON b(c gist_trgm_ops)`, 41285, `index using gist_trgm_ops`, ``},
 		{`CREATE INDEX a ON b(c bobby)`, 47420, ``, ``},
 		{`CREATE INDEX a ON b(a NULLS LAST)`, 6224, ``, ``},
 		{`CREATE INDEX a ON b(a ASC NULLS LAST)`, 6224, ``, ``},
@@ -3270,15 +3379,10 @@ func TestUnimplementedSyntax(t *testing.T) {
 		{`SELECT a(b) 'c'`, 0, `a(...) SCONST`, ``},
 		{`SELECT (a,b) OVERLAPS (c,d)`, 0, `overlaps`, ``},
 		{`SELECT UNIQUE (SELECT b)`, 0, `UNIQUE predicate`, ``},
-		{`SELECT GROUPING (a,b,c)`, 0, `d_expr grouping`, ``},
 		{`SELECT a(VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT a(b, c, VARIADIC b)`, 0, `variadic`, ``},
 		{`SELECT TREAT (a AS INT8)`, 0, `treat`, ``},
 
-		{`SELECT 1 FROM t GROUP BY ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY a, ROLLUP (b)`, 46280, `rollup`, ``},
-		{`SELECT 1 FROM t GROUP BY CUBE (b)`, 46280, `cube`, ``},
-		{`SELECT 1 FROM t GROUP BY GROUPING SETS (b)`, 46280, `grouping sets`, ``},
 
 		{`SELECT a FROM t ORDER BY a NULLS LAST`, 6224, ``, ``},
 		{`SELECT a FROM t ORDER BY a ASC NULLS LAST`, 6224, ``, ``},
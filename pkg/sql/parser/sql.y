@@ -428,6 +428,15 @@ func (u *sqlSymUnion) readWriteMode() tree.ReadWriteMode {
 func (u *sqlSymUnion) deferrableMode() tree.DeferrableMode {
     return u.val.(tree.DeferrableMode)
 }
+func (u *sqlSymUnion) cursorSensitivity() tree.CursorSensitivity {
+    return u.val.(tree.CursorSensitivity)
+}
+func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
+    return u.val.(tree.CursorScrollOption)
+}
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
 func (u *sqlSymUnion) idxElem() tree.IndexElem {
     return u.val.(tree.IndexElem)
 }
@@ -515,9 +524,42 @@ func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
 func (u *sqlSymUnion) referenceActions() tree.ReferenceActions {
     return u.val.(tree.ReferenceActions)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) createStatsOptions() *tree.CreateStatsOptions {
     return u.val.(*tree.CreateStatsOptions)
 }
+func (u *sqlSymUnion) functionOptions() *tree.FunctionOptions {
+    return u.val.(*tree.FunctionOptions)
+}
+func (u *sqlSymUnion) funcParam() tree.FuncParam {
+    return u.val.(tree.FuncParam)
+}
+func (u *sqlSymUnion) funcParams() tree.FuncParams {
+    return u.val.(tree.FuncParams)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() []tree.FuncObj {
+    return u.val.([]tree.FuncObj)
+}
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() tree.TriggerEvent {
+    return u.val.(tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() []tree.TriggerEvent {
+    return u.val.([]tree.TriggerEvent)
+}
+func (u *sqlSymUnion) policyCommand() tree.PolicyCommand {
+    return u.val.(tree.PolicyCommand)
+}
+func (u *sqlSymUnion) rowLevelSecurityMode() tree.RowLevelSecurityMode {
+    return u.val.(tree.RowLevelSecurityMode)
+}
 func (u *sqlSymUnion) scrubOptions() tree.ScrubOptions {
     return u.val.(tree.ScrubOptions)
 }
@@ -594,29 +636,29 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACCESS ACTION ADD ADMIN AFFINITY AFTER AGGREGATE
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADMIN AFFINITY AFTER AGGREGATE
 %token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
-%token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC
+%token <str> ASENSITIVE ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC
 
-%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
-%token <str> BUCKET_COUNT
+%token <str> BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BUCKET_COUNT BYPASSRLS
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
-%token <str> CACHE CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
+%token <str> CACHE CALLED CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CLOSE
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
 %token <str> CONFLICT CONNECTION CONSTRAINT CONSTRAINTS CONTAINS CONTROLCHANGEFEED CONTROLJOB
 %token <str> CONVERSION CONVERT COPY COVERING CREATE CREATEDB CREATELOGIN CREATEROLE
-%token <str> CROSS CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
+%token <str> CROSS CSV CUBE CURRENT CURRENT_CATALOG CURRENT_DATE CURRENT_SCHEMA
 %token <str> CURRENT_ROLE CURRENT_TIME CURRENT_TIMESTAMP
-%token <str> CURRENT_USER CYCLE
+%token <str> CURRENT_USER CURSOR CYCLE
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEC DECIMAL DEFAULT DEFAULTS
-%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DESC DESTINATION DETACHED
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
+%token <str> DISABLE DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENABLE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT
@@ -624,19 +666,19 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE_INDEX FOREIGN FROM FULL FUNCTION
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FOREIGN FORWARD FROM FULL FUNCTION
 
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INTERLEAVE INITIALLY
-%token <str> INNER INSERT INT INTEGER
+%token <str> INNER INPUT INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -646,23 +688,23 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
 %token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LOCAL LOCALITIES LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH
+%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
 
-%token <str> NAN NAME NAMES NATURAL NEVER NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED NOCONTROLJOB
+%token <str> NAN NAME NAMES NATURAL NEVER NEXT NO NOBYPASSRLS NOCANCELQUERY NOCONTROLCHANGEFEED NOCONTROLJOB
 %token <str> NOCREATEDB NOCREATELOGIN NOCREATEROLE NOLOGIN NOMODIFYCLUSTERSETTING NO_INDEX_JOIN
 %token <str> NONE NORMAL NOT NOTHING NOTNULL NOVIEWACTIVITY NOWAIT NULL NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
 %token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
 
-%token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACING
-%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIORITY PRIVILEGES
+%token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PERMISSIVE PHYSICAL PLACING
+%token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLICY POLYGON POLYGONM POLYGONZ POLYGONZM
+%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
 %token <str> PROCEDURAL PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY
@@ -670,15 +712,15 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> RANGE RANGES READ REAL REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGPROC REGPROCEDURE REGNAMESPACE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE
-%token <str> RELEASE RESET RESTORE RESTRICT RESUME RETURNING RETRY REVISION_HISTORY REVOKE RIGHT
+%token <str> RELATIVE RELEASE RESET RESTORE RESTRICT RESTRICTIVE RESUME RETURNING RETURNS RETRY REVISION_HISTORY REVOKE RIGHT
 %token <str> ROLE ROLES ROLLBACK ROLLUP ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SECURITY SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> STABLE START STATEMENT STATISTICS STATUS STDIN STDOUT STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -690,7 +732,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIRTUAL
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIRTUAL VOLATILE
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -799,6 +841,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt
@@ -818,8 +861,25 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <*tree.CreateStatsOptions> opt_create_stats_options
 %type <*tree.CreateStatsOptions> create_stats_option_list
 %type <*tree.CreateStatsOptions> create_stats_option
+%type <*tree.FunctionOptions> opt_create_func_opt_list create_func_opt_list create_func_opt_item
+%type <tree.FuncParams> opt_func_param_list func_param_list
+%type <tree.FuncParam> func_param
+%type <tree.FuncObj> func_obj
+%type <[]tree.FuncObj> func_obj_list
+%type <tree.TriggerActionTime> trigger_action_time
+%type <tree.TriggerEvent> trigger_event
+%type <[]tree.TriggerEvent> trigger_event_list
+%type <bool> opt_trigger_for_each
+%type <bool> opt_policy_restrictive
+%type <tree.PolicyCommand> opt_policy_command
+%type <[]security.SQLUsername> opt_policy_roles
+%type <tree.Expr> opt_policy_using opt_policy_with_check
+%type <tree.RowLevelSecurityMode> row_level_security_mode
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_func_stmt
+%type <tree.Statement> create_trigger_stmt
+%type <tree.Statement> create_policy_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
@@ -831,6 +891,9 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_func_stmt
+%type <tree.Statement> drop_trigger_stmt
+%type <tree.Statement> drop_policy_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 
@@ -867,6 +930,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -921,6 +985,8 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
 %type <tree.Statement> reindex_stmt
 
 %type <[]string> opt_incremental
@@ -1057,6 +1123,8 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.Expr> overlay_placing
 
 %type <bool> opt_unique opt_concurrently opt_cluster opt_without_index
+%type <bool> constraints_set_mode
+%type <tree.NameList> constraints_set_list
 %type <bool> opt_index_access_method
 
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
@@ -1134,6 +1202,11 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <int32> iconst32
 %type <int64> signed_iconst64
 %type <int64> iconst64
+%type <int64> next_prior forward_backward opt_forward_backward
+%type <bool> opt_hold opt_binary
+%type <tree.CursorSensitivity> opt_sensitivity
+%type <tree.CursorScrollOption> opt_scroll
+%type <tree.CursorStmt> cursor_movement_specifier
 %type <tree.Expr> var_value
 %type <tree.Exprs> var_list
 %type <tree.NameList> var_name
@@ -1164,6 +1237,7 @@ func (u *sqlSymUnion) objectNamePrefixList() tree.ObjectNamePrefixList {
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -1287,6 +1361,7 @@ stmt:
 | preparable_stmt           // help texts in sub-rule
 | analyze_stmt              // EXTEND WITH HELP: ANALYZE
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt              // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt           // EXTEND WITH HELP: DEALLOCATE
@@ -1301,8 +1376,10 @@ stmt:
 | refresh_stmt              // EXTEND WITH HELP: REFRESH
 | nonpreparable_set_stmt    // help texts in sub-rule
 | transaction_stmt          // help texts in sub-rule
-| close_cursor_stmt
-| declare_cursor_stmt
+| close_cursor_stmt         // EXTEND WITH HELP: CLOSE
+| declare_cursor_stmt       // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt         // EXTEND WITH HELP: FETCH
+| move_cursor_stmt          // EXTEND WITH HELP: MOVE
 | reindex_stmt
 | /* EMPTY */
   {
@@ -1357,6 +1434,7 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... {ENABLE | DISABLE | FORCE | NO FORCE} ROW LEVEL SECURITY
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE [WITHOUT INDEX] | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2008,11 +2086,22 @@ alter_table_cmd:
       Owner: $3.user(),
     }
   }
+  // ALTER TABLE <name> {ENABLE | DISABLE | FORCE | NO FORCE} ROW LEVEL SECURITY
+| row_level_security_mode ROW LEVEL SECURITY
+  {
+    $$.val = &tree.AlterTableRowLevelSecurity{Mode: $1.rowLevelSecurityMode()}
+  }
 
 audit_mode:
   READ WRITE { $$.val = tree.AuditModeReadWrite }
 | OFF        { $$.val = tree.AuditModeDisable }
 
+row_level_security_mode:
+  ENABLE   { $$.val = tree.RowLevelSecurityEnable }
+| DISABLE  { $$.val = tree.RowLevelSecurityDisable }
+| FORCE    { $$.val = tree.RowLevelSecurityForce }
+| NO FORCE { $$.val = tree.RowLevelSecurityNoForce }
+
 alter_index_cmds:
   alter_index_cmd
   {
@@ -2775,9 +2864,13 @@ import_stmt:
 //
 // Formats:
 //    CSV
+//    PARQUET             [DECIMAL without a precision is written as STRING]
 //
 // Options:
 //    delimiter = '...'   [CSV-specific]
+//    nullas = '...'      [CSV-specific]
+//    chunk_rows = '...'
+//    compression = 'gzip' | 'snappy' ['snappy' is PARQUET-specific]
 //
 // %SeeAlso: SELECT
 export_stmt:
@@ -2875,13 +2968,36 @@ copy_from_stmt:
     if $7.expr() != nil {
       return unimplementedWithIssue(sqllex, 54580)
     }
+    opts := $6.copyOptions()
+    if opts.CopyFormat == tree.CopyFormatCSV || opts.Delimiter != nil || opts.Null != nil || opts.Header {
+      return unimplemented(sqllex, "copy from csv, delimiter, null or header")
+    }
     $$.val = &tree.CopyFrom{
        Table: name,
        Columns: $3.nameList(),
        Stdin: true,
+       Options: *opts,
+    }
+  }
+
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Table: $2.unresolvedObjectName().ToTableName(),
+       Columns: $3.nameList(),
        Options: *$6.copyOptions(),
     }
   }
+| COPY '(' preparable_stmt ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Statement: $3.stmt(),
+       Options: *$7.copyOptions(),
+    }
+  }
 
 opt_with_copy_options:
   opt_with copy_options_list
@@ -2914,6 +3030,30 @@ copy_options:
   {
     $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatBinary}
   }
+| CSV
+  {
+    $$.val = &tree.CopyOptions{CopyFormat: tree.CopyFormatCSV}
+  }
+| HEADER
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
+| DELIMITER SCONST
+  {
+    $$.val = &tree.CopyOptions{Delimiter: tree.NewStrVal($2)}
+  }
+| DELIMITER AS SCONST
+  {
+    $$.val = &tree.CopyOptions{Delimiter: tree.NewStrVal($3)}
+  }
+| NULL SCONST
+  {
+    $$.val = &tree.CopyOptions{Null: tree.NewStrVal($2)}
+  }
+| NULL AS SCONST
+  {
+    $$.val = &tree.CopyOptions{Null: tree.NewStrVal($3)}
+  }
 
 // %Help: CANCEL
 // %Category: Group
@@ -3067,7 +3207,8 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE, CREATE TYPE, CREATE EXTENSION
+// CREATE ROLE, CREATE TYPE, CREATE EXTENSION, CREATE FUNCTION,
+// CREATE TRIGGER, CREATE POLICY
 create_stmt:
   create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
@@ -3094,13 +3235,10 @@ create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
 | CREATE AGGREGATE error { return unimplemented(sqllex, "create aggregate") }
 | CREATE CAST error { return unimplemented(sqllex, "create cast") }
-| CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplemented(sqllex, "create operator") }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
@@ -3109,7 +3247,6 @@ create_unsupported:
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create") }
 
 opt_or_replace:
   OR REPLACE {}
@@ -3134,7 +3271,6 @@ drop_unsupported:
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
@@ -3142,7 +3278,6 @@ drop_unsupported:
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_changefeed_stmt
@@ -3155,6 +3290,9 @@ create_ddl_stmt:
 | CREATE opt_persistence_temp_table TABLE error   // SHOW HELP: CREATE TABLE
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
+| create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
+| create_policy_stmt   // EXTEND WITH HELP: CREATE POLICY
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
 // %Help: CREATE STATISTICS - create a new table statistic
@@ -3346,7 +3484,8 @@ discard_stmt:
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE, DROP TYPE
+// DROP USER, DROP ROLE, DROP TYPE, DROP FUNCTION, DROP TRIGGER,
+// DROP POLICY
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
@@ -3362,6 +3501,9 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
+| drop_policy_stmt   // EXTEND WITH HELP: DROP POLICY
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -3496,6 +3638,103 @@ drop_type_stmt:
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
 
+// %Help: DROP FUNCTION - remove a user-defined function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [( [<param_type> [, ...]] )] [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_func_stmt:
+  DROP FUNCTION func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS func_obj_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.funcObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <table_name> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      IfExists: false,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+// %Help: DROP POLICY - remove a row-level security policy
+// %Category: DDL
+// %Text: DROP POLICY [IF EXISTS] <name> ON <table_name> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE POLICY
+drop_policy_stmt:
+  DROP POLICY name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropPolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      IfExists: false,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP POLICY IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropPolicy{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP POLICY error // SHOW HELP: DROP POLICY
+
+func_obj_list:
+  func_obj
+  {
+    $$.val = []tree.FuncObj{$1.funcObj()}
+  }
+| func_obj_list ',' func_obj
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+func_obj:
+  db_object_name
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName()}
+  }
+| db_object_name '(' ')'
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName(), ParamTypes: []tree.ResolvableTypeReference{}}
+  }
+| db_object_name '(' type_list ')'
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName(), ParamTypes: $3.typeReferences()}
+  }
+
 target_types:
   type_name_list
   {
@@ -3977,8 +4216,8 @@ use_stmt:
 // SET remainder, e.g. SET TRANSACTION
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
 | SET LOCAL error { return unimplementedWithIssue(sqllex, 32562) }
 
 // SET SESSION / SET CLUSTER SETTING
@@ -4163,6 +4402,40 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set when the constraints of the current transaction are checked
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Only foreign key and unique constraints declared DEFERRABLE can be
+// deferred. The checks of deferred constraints run when the transaction
+// commits.
+//
+// %SeeAlso: SET TRANSACTION, CREATE TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS constraints_set_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_list:
+  ALL
+  {
+    $$.val = tree.NameList(nil)
+  }
+| name_list
+
+constraints_set_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -4390,14 +4663,228 @@ show_stmt:
 | SHOW error                // SHOW HELP: SHOW
 | show_last_query_stats_stmt
 
-// Cursors are not yet supported by CockroachDB. CLOSE ALL is safe to no-op
-// since there will be no open cursors.
+// %Help: CLOSE - close SQL cursor
+// %Category: Misc
+// %Text: CLOSE [ ALL | <name> ]
+// %SeeAlso: DECLARE, FETCH, MOVE
 close_cursor_stmt:
-	CLOSE ALL { }
-| CLOSE cursor_name { return unimplementedWithIssue(sqllex, 41412) }
+  CLOSE ALL
+  {
+    $$.val = &tree.CloseCursor{
+      All: true,
+    }
+  }
+| CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{
+      Name: tree.Name($2),
+    }
+  }
+| CLOSE error // SHOW HELP: CLOSE
 
+// %Help: DECLARE - declare a SQL cursor
+// %Category: Misc
+// %Text: DECLARE <name> [ BINARY ] [ INSENSITIVE | ASENSITIVE ] [ [ NO ] SCROLL ]
+//        CURSOR [ { WITH | WITHOUT } HOLD ] FOR <selectclause>
+// %SeeAlso: FETCH, MOVE, CLOSE
 declare_cursor_stmt:
-	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
+  DECLARE cursor_name opt_binary opt_sensitivity opt_scroll CURSOR opt_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{
+      Name: tree.Name($2),
+      Select: $9.slct(),
+      Binary: $3.bool(),
+      Sensitivity: $4.cursorSensitivity(),
+      Scroll: $5.cursorScrollOption(),
+      Hold: $7.bool(),
+    }
+  }
+| DECLARE error // SHOW HELP: DECLARE
+
+opt_binary:
+  BINARY
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+opt_sensitivity:
+  INSENSITIVE
+  {
+    $$.val = tree.Insensitive
+  }
+| ASENSITIVE
+  {
+    $$.val = tree.Asensitive
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedSensitivity
+  }
+
+opt_scroll:
+  SCROLL
+  {
+    $$.val = tree.Scroll
+  }
+| NO SCROLL
+  {
+    $$.val = tree.NoScroll
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedScroll
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - fetch rows from a SQL cursor
+// %Category: Misc
+// %Text: FETCH [ <direction> [ FROM | IN ] ] <name>
+//
+// Direction:
+//   NEXT, PRIOR, FIRST, LAST, ABSOLUTE <count>, RELATIVE <count>, <count>, ALL,
+//   FORWARD [ <count> | ALL ], BACKWARD [ <count> | ALL ]
+// %SeeAlso: MOVE, CLOSE, DECLARE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - move a SQL cursor without fetching rows
+// %Category: Misc
+// %Text: MOVE [ <direction> [ FROM | IN ] ] <name>
+//
+// Direction:
+//   NEXT, PRIOR, FIRST, LAST, ABSOLUTE <count>, RELATIVE <count>, <count>, ALL,
+//   FORWARD [ <count> | ALL ], BACKWARD [ <count> | ALL ]
+// %SeeAlso: FETCH, CLOSE, DECLARE
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($1),
+      Count: 1,
+    }
+  }
+| from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($2),
+      Count: 1,
+    }
+  }
+| next_prior opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| forward_backward opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| opt_forward_backward signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      Count: $2.int64() * $1.int64(),
+    }
+  }
+| opt_forward_backward ALL opt_from_or_in cursor_name
+  {
+    fetchType := tree.FetchAll
+    count := $1.int64()
+    if count < 0 {
+      fetchType = tree.FetchBackwardAll
+    }
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: fetchType,
+    }
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchAbsolute,
+      Count: $2.int64(),
+    }
+  }
+| RELATIVE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchRelative,
+      Count: $2.int64(),
+    }
+  }
+| FIRST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchFirst,
+    }
+  }
+| LAST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchLast,
+    }
+  }
+
+next_prior:
+  NEXT  { $$.val = int64(1) }
+| PRIOR { $$.val = int64(-1) }
+
+opt_forward_backward:
+  forward_backward { $$.val = $1.int64() }
+| /* EMPTY */ { $$.val = int64(1) }
+
+forward_backward:
+  FORWARD  { $$.val = int64(1) }
+| BACKWARD { $$.val = int64(-1) }
+
+opt_from_or_in:
+  from_or_in { }
+| /* EMPTY */ { }
+
+from_or_in:
+  FROM { }
+| IN { }
 
 reindex_stmt:
   REINDEX TABLE error
@@ -5122,11 +5609,12 @@ show_zone_stmt:
     $$.val = &tree.ShowZoneConfig{}
   }
 
-// %Help: SHOW RANGE - show range information for a row
+// %Help: SHOW RANGE - show range information for a row or the request localities of a range
 // %Category: Misc
 // %Text:
 // SHOW RANGE FROM TABLE <tablename> FOR ROW (value1, value2, ...)
 // SHOW RANGE FROM INDEX [ <tablename> @ ] <indexname> FOR ROW (value1, value2, ...)
+// SHOW RANGE <rangeid> LOCALITIES
 show_range_for_row_stmt:
   SHOW RANGE FROM TABLE table_name FOR ROW '(' expr_list ')'
   {
@@ -5143,6 +5631,10 @@ show_range_for_row_stmt:
       TableOrIndex: $5.tableIndexName(),
     }
   }
+| SHOW RANGE iconst64 LOCALITIES
+  {
+    $$.val = &tree.ShowRangeLocalities{RangeID: $3.int64()}
+  }
 | SHOW RANGE error // SHOW HELP: SHOW RANGE
 
 // %Help: SHOW RANGES - list ranges
@@ -5628,17 +6120,21 @@ alter_schema_stmt:
 //
 // Table constraints:
 //    PRIMARY KEY ( <colnames...> ) [USING HASH WITH BUCKET_COUNT = <shard_buckets>]
-//    FOREIGN KEY ( <colnames...> ) REFERENCES <tablename> [( <colnames...> )] [ON DELETE {NO ACTION | RESTRICT}] [ON UPDATE {NO ACTION | RESTRICT}]
-//    UNIQUE [WITHOUT INDEX] ( <colnames... ) [{STORING | INCLUDE | COVERING} ( <colnames...> )] [<interleave>]
+//    FOREIGN KEY ( <colnames...> ) REFERENCES <tablename> [( <colnames...> )] [ON DELETE {NO ACTION | RESTRICT}] [ON UPDATE {NO ACTION | RESTRICT}] [<deferrability>]
+//    UNIQUE [WITHOUT INDEX] ( <colnames... ) [{STORING | INCLUDE | COVERING} ( <colnames...> )] [<interleave>] [<deferrability>]
 //    CHECK ( <expr> )
 //
 // Column qualifiers:
-//   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE [WITHOUT INDEX] | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
+//   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE [WITHOUT INDEX] [<deferrability>] | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
 //   FAMILY <familyname>, CREATE [IF NOT EXISTS] FAMILY [<familyname>]
-//   REFERENCES <tablename> [( <colnames...> )] [ON DELETE {NO ACTION | RESTRICT}] [ON UPDATE {NO ACTION | RESTRICT}]
+//   REFERENCES <tablename> [( <colnames...> )] [ON DELETE {NO ACTION | RESTRICT}] [ON UPDATE {NO ACTION | RESTRICT}] [<deferrability>]
 //   COLLATE <collationname>
 //   AS ( <expr> ) STORED
 //
+// Deferrability clause (only for foreign keys and unique constraints without index):
+//    DEFERRABLE [INITIALLY {DEFERRED | IMMEDIATE}]
+//    INITIALLY {DEFERRED | IMMEDIATE}
+//
 // Interleave clause:
 //    INTERLEAVE IN PARENT <tablename> ( <colnames...> ) [CASCADE | RESTRICT]
 //
@@ -6086,10 +6582,11 @@ col_qualification_elem:
   {
     $$.val = tree.NullConstraint{}
   }
-| UNIQUE opt_without_index
+| UNIQUE opt_without_index opt_deferrable
   {
     $$.val = tree.UniqueConstraint{
       WithoutIndex: $2.bool(),
+      Deferrability: $3.constraintDeferrability(),
     }
   }
 | PRIMARY KEY
@@ -6111,7 +6608,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
  {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -6119,6 +6616,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrability: $6.constraintDeferrability(),
     }
  }
 | generated_as '(' a_expr ')' STORED
@@ -6217,6 +6715,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability().Deferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -6233,6 +6735,7 @@ constraint_elem:
         PartitionBy: $8.partitionBy(),
         Predicate: $10.expr(),
       },
+      Deferrability: $9.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_interleave
@@ -6256,6 +6759,7 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
     }
   }
 | EXCLUDE USING error
@@ -6365,13 +6869,34 @@ create_as_col_qualification_elem:
     $$.val = tree.PrimaryKeyConstraint{}
   }
 
+// NOT DEFERRABLE is not supported, because it conflicts with the NOT VALID
+// suffix of ALTER TABLE ... ADD CONSTRAINT. Constraints are not deferrable by
+// default anyway.
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.ConstraintDeferrability{}
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.ConstraintDeferrability{Deferrable: true}
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintDeferrability{Deferrable: true, InitiallyDeferred: true}
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintDeferrability{Deferrable: true}
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintDeferrability{Deferrable: true, InitiallyDeferred: true}
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintDeferrability{}
+  }
 
 storing:
   COVERING
@@ -6796,6 +7321,14 @@ role_option:
   {
     $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
   }
+| BYPASSRLS
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOBYPASSRLS
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
 | password_clause
 | valid_until_clause
 
@@ -6869,6 +7402,317 @@ create_type_stmt:
   // Domain types.
 | CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
 
+// %Help: CREATE FUNCTION - create a user-defined function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [[<param_name>] <param_type> [, ...]] )
+//   RETURNS <type>
+//   [ LANGUAGE SQL
+//   | IMMUTABLE | STABLE | VOLATILE
+//   | CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT
+//   | AS '<definition>' ] ...
+// %SeeAlso: DROP FUNCTION
+create_func_stmt:
+  CREATE FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS typename opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      FuncName: $3.unresolvedObjectName(),
+      Params: $5.funcParams(),
+      ReturnType: $8.typeReference(),
+      Options: *$9.functionOptions(),
+    }
+  }
+| CREATE OR REPLACE FUNCTION db_object_name '(' opt_func_param_list ')' RETURNS typename opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      FuncName: $5.unresolvedObjectName(),
+      Replace: true,
+      Params: $7.funcParams(),
+      ReturnType: $10.typeReference(),
+      Options: *$11.functionOptions(),
+    }
+  }
+| CREATE FUNCTION error // SHOW HELP: CREATE FUNCTION
+| CREATE OR REPLACE FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+// %Help: CREATE TRIGGER - create a trigger
+// %Category: DDL
+// %Text:
+// CREATE [CONSTRAINT] TRIGGER <name> { BEFORE | AFTER } <event> [OR ...]
+//   ON <table_name> [FOR [EACH] { ROW | STATEMENT }] AS '<statement>'
+//
+// <event> is one of INSERT, UPDATE or DELETE. The statement can refer to
+// the modified rows through the "new" and "old" relations.
+// %SeeAlso: DROP TRIGGER
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name opt_trigger_for_each AS SCONST
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName().ToTableName(),
+      ForEachRow: $8.bool(),
+      Action: $10,
+    }
+  }
+| CREATE CONSTRAINT TRIGGER name trigger_action_time trigger_event_list ON table_name opt_trigger_for_each AS SCONST
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($4),
+      Constraint: true,
+      ActionTime: $5.triggerActionTime(),
+      Events: $6.triggerEvents(),
+      Table: $8.unresolvedObjectName().ToTableName(),
+      ForEachRow: $9.bool(),
+      Action: $11,
+    }
+  }
+| CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name opt_trigger_for_each EXECUTE error
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "execute function")
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+| CREATE CONSTRAINT TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerAfter
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = []tree.TriggerEvent{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = tree.TriggerEventInsert
+  }
+| UPDATE
+  {
+    $$.val = tree.TriggerEventUpdate
+  }
+| DELETE
+  {
+    $$.val = tree.TriggerEventDelete
+  }
+| TRUNCATE
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "truncate")
+  }
+
+opt_trigger_for_each:
+  FOR EACH ROW
+  {
+    $$.val = true
+  }
+| FOR ROW
+  {
+    $$.val = true
+  }
+| FOR EACH STATEMENT
+  {
+    $$.val = false
+  }
+| FOR STATEMENT
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: CREATE POLICY - create a row-level security policy
+// %Category: DDL
+// %Text:
+// CREATE POLICY <name> ON <table_name>
+//   [AS { PERMISSIVE | RESTRICTIVE }]
+//   [FOR { ALL | SELECT | INSERT | UPDATE | DELETE }]
+//   [TO <role> [, ...]]
+//   [USING ( <expr> )]
+//   [WITH CHECK ( <expr> )]
+//
+// The policies of a table are only enforced after
+// ALTER TABLE ... ENABLE ROW LEVEL SECURITY.
+// %SeeAlso: DROP POLICY, ALTER TABLE
+create_policy_stmt:
+  CREATE POLICY name ON table_name opt_policy_restrictive opt_policy_command opt_policy_roles opt_policy_using opt_policy_with_check
+  {
+    $$.val = &tree.CreatePolicy{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName().ToTableName(),
+      Restrictive: $6.bool(),
+      Command: $7.policyCommand(),
+      Roles: $8.users(),
+      Using: $9.expr(),
+      WithCheck: $10.expr(),
+    }
+  }
+| CREATE POLICY error // SHOW HELP: CREATE POLICY
+
+opt_policy_restrictive:
+  AS PERMISSIVE
+  {
+    $$.val = false
+  }
+| AS RESTRICTIVE
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+opt_policy_command:
+  FOR ALL
+  {
+    $$.val = tree.PolicyCommandAll
+  }
+| FOR SELECT
+  {
+    $$.val = tree.PolicyCommandSelect
+  }
+| FOR INSERT
+  {
+    $$.val = tree.PolicyCommandInsert
+  }
+| FOR UPDATE
+  {
+    $$.val = tree.PolicyCommandUpdate
+  }
+| FOR DELETE
+  {
+    $$.val = tree.PolicyCommandDelete
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.PolicyCommandAll
+  }
+
+opt_policy_roles:
+  TO role_spec_list
+  {
+    $$.val = $2.users()
+  }
+| /* EMPTY */
+  {
+    $$.val = []security.SQLUsername(nil)
+  }
+
+opt_policy_using:
+  USING '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+opt_policy_with_check:
+  WITH CHECK '(' a_expr ')'
+  {
+    $$.val = $4.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+opt_func_param_list:
+  func_param_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncParams(nil)
+  }
+
+func_param_list:
+  func_param
+  {
+    $$.val = tree.FuncParams{$1.funcParam()}
+  }
+| func_param_list ',' func_param
+  {
+    $$.val = append($1.funcParams(), $3.funcParam())
+  }
+
+func_param:
+  type_function_name typename
+  {
+    $$.val = tree.FuncParam{Name: tree.Name($1), Type: $2.typeReference()}
+  }
+| typename
+  {
+    $$.val = tree.FuncParam{Type: $1.typeReference()}
+  }
+
+opt_create_func_opt_list:
+  create_func_opt_list
+| /* EMPTY */
+  {
+    $$.val = &tree.FunctionOptions{}
+  }
+
+create_func_opt_list:
+  create_func_opt_item
+| create_func_opt_list create_func_opt_item
+  {
+    a := $1.functionOptions()
+    if err := a.CombineWith($2.functionOptions()); err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = a
+  }
+
+create_func_opt_item:
+  LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = &tree.FunctionOptions{Language: $2}
+  }
+| IMMUTABLE
+  {
+    $$.val = &tree.FunctionOptions{Volatility: tree.VolatilityImmutable}
+  }
+| STABLE
+  {
+    $$.val = &tree.FunctionOptions{Volatility: tree.VolatilityStable}
+  }
+| VOLATILE
+  {
+    $$.val = &tree.FunctionOptions{Volatility: tree.VolatilityVolatile}
+  }
+| CALLED ON NULL INPUT
+  {
+    $$.val = &tree.FunctionOptions{NullInputBehavior: tree.FunctionCalledOnNullInput}
+  }
+| RETURNS NULL ON NULL INPUT
+  {
+    $$.val = &tree.FunctionOptions{NullInputBehavior: tree.FunctionReturnsNullOnNullInput}
+  }
+| STRICT
+  {
+    $$.val = &tree.FunctionOptions{NullInputBehavior: tree.FunctionReturnsNullOnNullInput}
+  }
+| AS SCONST
+  {
+    body := $2
+    $$.val = &tree.FunctionOptions{Body: &body}
+  }
+
 opt_enum_val_list:
   enum_val_list
   {
@@ -7059,10 +7903,8 @@ index_elem_options:
     opClass := $1
     dir := $2.dir()
     nullsOrder := $3.nullsOrder()
-    if opClass != "" {
-      if opClass == "gin_trgm_ops" || opClass == "gist_trgm_ops" {
-        return unimplementedWithIssueDetail(sqllex, 41285, "index using " + opClass)
-      }
+    // Only the trigram operator classes are supported.
+    if opClass != "" && opClass != "gin_trgm_ops" && opClass != "gist_trgm_ops" {
       return unimplementedWithIssue(sqllex, 47420)
     }
     // We currently only support the opposite of Postgres defaults.
@@ -7074,7 +7916,7 @@ index_elem_options:
         return unimplementedWithIssue(sqllex, 6224)
       }
     }
-    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder}
+    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder, OpClass: tree.Name(opClass)}
   }
 
 opt_class:
@@ -8679,9 +9521,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.Rollup, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.Cube, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -10523,7 +11374,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("grouping"), Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -11991,6 +12845,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ACCESS
 | ADD
@@ -12000,18 +12855,22 @@ unreserved_keyword:
 | AGGREGATE
 | ALTER
 | ALWAYS
+| ASENSITIVE
 | AT
 | ATTRIBUTE
 | AUTOMATIC
 | BACKUP
 | BACKUPS
+| BACKWARD
 | BEFORE
 | BEGIN
 | BINARY
 | BUCKET_COUNT
 | BUNDLE
 | BY
+| BYPASSRLS
 | CACHE
+| CALLED
 | CANCEL
 | CANCELQUERY
 | CASCADE
@@ -12040,8 +12899,10 @@ unreserved_keyword:
 | CREATEDB
 | CREATELOGIN
 | CREATEROLE
+| CSV
 | CUBE
 | CURRENT
+| CURSOR
 | CYCLE
 | DATA
 | DATABASE
@@ -12050,14 +12911,18 @@ unreserved_keyword:
 | DEALLOCATE
 | DECLARE
 | DELETE
+| DELIMITER
 | DEFAULTS
 | DEFERRED
 | DESTINATION
 | DETACHED
+| DISABLE
 | DISCARD
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
+| ENABLE
 | ENCODING
 | ENCRYPTION_PASSPHRASE
 | ENUM
@@ -12081,7 +12946,9 @@ unreserved_keyword:
 | FILTER
 | FIRST
 | FOLLOWING
+| FORCE
 | FORCE_INDEX
+| FORWARD
 | FUNCTION
 | GENERATED
 | GEOMETRYM
@@ -12096,11 +12963,14 @@ unreserved_keyword:
 | GRANTS
 | GROUPS
 | HASH
+| HEADER
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDING
@@ -12109,6 +12979,8 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INPUT
+| INSENSITIVE
 | INSERT
 | INTERLEAVE
 | INTO_DB
@@ -12134,6 +13006,7 @@ unreserved_keyword:
 | LOCAL
 | LOCKED
 | LOGIN
+| LOCALITIES
 | LOCALITY
 | LOOKUP
 | LOW
@@ -12158,11 +13031,13 @@ unreserved_keyword:
 | MULTIPOLYGONZ
 | MULTIPOLYGONZM
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NEVER
 | NEXT
 | NO
+| NOBYPASSRLS
 | NORMAL
 | NO_INDEX_JOIN
 | NOCREATEDB
@@ -12196,18 +13071,21 @@ unreserved_keyword:
 | PASSWORD
 | PAUSE
 | PAUSED
+| PERMISSIVE
 | PHYSICAL
 | PLAN
 | PLANS
 | POINTM
 | POINTZ
 | POINTZM
+| POLICY
 | POLYGONM
 | POLYGONZ
 | POLYGONZM
 | PRECEDING
 | PREPARE
 | PRESERVE
+| PRIOR
 | PRIORITY
 | PRIVILEGES
 | PUBLIC
@@ -12226,6 +13104,7 @@ unreserved_keyword:
 | REGIONAL
 | REGIONS
 | REINDEX
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -12233,8 +13112,10 @@ unreserved_keyword:
 | RESET
 | RESTORE
 | RESTRICT
+| RESTRICTIVE
 | RESUME
 | RETRY
+| RETURNS
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -12246,6 +13127,7 @@ unreserved_keyword:
 | RUNNING
 | SCHEDULE
 | SCHEDULES
+| SECURITY
 | SETTING
 | SETTINGS
 | STATUS
@@ -12253,6 +13135,7 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
@@ -12275,9 +13158,12 @@ unreserved_keyword:
 | SNAPSHOT
 | SPLIT
 | SQL
+| STABLE
 | START
+| STATEMENT
 | STATISTICS
 | STDIN
+| STDOUT
 | STORAGE
 | STORE
 | STORED
@@ -12322,6 +13208,7 @@ unreserved_keyword:
 | VARYING
 | VIEW
 | VIEWACTIVITY
+| VOLATILE
 | WITHIN
 | WITHOUT
 | WRITE
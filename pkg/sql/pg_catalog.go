@@ -216,6 +216,7 @@ var pgCatalog = virtualSchema{
 		catconstants.PgCatalogCollationTableID:           pgCatalogCollationTable,
 		catconstants.PgCatalogConstraintTableID:          pgCatalogConstraintTable,
 		catconstants.PgCatalogConversionTableID:          pgCatalogConversionTable,
+		catconstants.PgCatalogCursorsTableID:             pgCatalogCursorsTable,
 		catconstants.PgCatalogDatabaseTableID:            pgCatalogDatabaseTable,
 		catconstants.PgCatalogDefaultACLTableID:          pgCatalogDefaultACLTable,
 		catconstants.PgCatalogDependTableID:              pgCatalogDependTable,
@@ -762,6 +763,8 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		condeferrable := tree.DBoolFalse
+		condeferred := tree.DBoolFalse
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -793,6 +796,8 @@ func populateTableConstraints(
 				conindid = h.IndexOid(con.ReferencedTable.ID, idx.ID)
 			}
 			confrelid = tableOid(con.ReferencedTable.ID)
+			condeferrable = tree.MakeDBool(tree.DBool(con.FK.Deferrable))
+			condeferred = tree.MakeDBool(tree.DBool(con.FK.InitiallyDeferred))
 			if r, ok := fkActionMap[con.FK.OnUpdate]; ok {
 				confupdtype = r
 			}
@@ -850,6 +855,13 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				formatDeferrability(
+					&f.Buffer,
+					con.UniqueWithoutIndexConstraint.Deferrable,
+					con.UniqueWithoutIndexConstraint.InitiallyDeferred,
+				)
+				condeferrable = tree.MakeDBool(tree.DBool(con.UniqueWithoutIndexConstraint.Deferrable))
+				condeferred = tree.MakeDBool(tree.DBool(con.UniqueWithoutIndexConstraint.InitiallyDeferred))
 			} else {
 				return errors.AssertionFailedf(
 					"Index or UniqueWithoutIndexConstraint must be non-nil for a unique constraint",
@@ -881,8 +893,8 @@ func populateTableConstraints(
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1177,6 +1189,10 @@ https://www.postgresql.org/docs/9.5/catalog-pg-depend.html`,
 			//
 			// Issue #57417: https://github.com/cockroachdb/cockroach/issues/57417
 			reportViewDependency := func(dep *descpb.TableDescriptor_Reference) error {
+				// User-defined functions are not exposed in pg_proc.
+				if tableLookup.isFunction(dep.ID) {
+					return nil
+				}
 				for _, colID := range dep.ColumnIDs {
 					if err := addRow(
 						pgClassTableOid,                //classid
@@ -1563,6 +1579,9 @@ func indexDefFromDescriptor(
 		if index.ColumnDirections[i] == descpb.IndexDescriptor_DESC {
 			elem.Direction = tree.Descending
 		}
+		if i == len(index.ColumnNames)-1 && index.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+			elem.OpClass = "gin_trgm_ops"
+		}
 		indexDef.Columns[i] = elem
 	}
 	for i, name := range index.StoreColumnNames {
@@ -1827,6 +1846,34 @@ https://www.postgresql.org/docs/9.6/view-pg-prepared-xacts.html`,
 	},
 }
 
+// pgCatalogCursorsTable implements the pg_cursors table.
+// The statement field uses the parsed version of the DECLARE statement.
+var pgCatalogCursorsTable = virtualSchemaTable{
+	comment: `open cursors
+https://www.postgresql.org/docs/current/view-pg-cursors.html`,
+	schema: vtable.PGCatalogCursors,
+	populate: func(ctx context.Context, p *planner, dbContext *dbdesc.Immutable, addRow func(...tree.Datum) error) error {
+		for _, name := range p.sqlCursors.names() {
+			c := p.sqlCursors.cursors[name]
+			ts, err := tree.MakeDTimestampTZ(c.created, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.NewDString(string(name)),
+				tree.NewDString(c.statement),
+				tree.MakeDBool(tree.DBool(c.hold)),
+				tree.MakeDBool(tree.DBool(c.binary)),
+				tree.MakeDBool(tree.DBool(c.scrollable())),
+				ts,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
 // pgCatalogPreparedStatementsTable implements the pg_prepared_statements table.
 // The statement field differs in that it uses the parsed version
 // of the PREPARE statement.
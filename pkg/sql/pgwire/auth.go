@@ -30,6 +30,16 @@ const (
 	// authCleartextPassword is the pgwire auth response code to request
 	// a plaintext password during the connection handshake.
 	authCleartextPassword int32 = 3
+	// authSASL is the pgwire auth response code to start a SASL
+	// authentication exchange, listing the mechanisms supported by the
+	// server.
+	authSASL int32 = 10
+	// authSASLContinue is the pgwire auth response code carrying a SASL
+	// challenge to the client.
+	authSASLContinue int32 = 11
+	// authSASLFinal is the pgwire auth response code carrying the outcome of
+	// a SASL exchange, before the final authOK.
+	authSASLFinal int32 = 12
 )
 
 type authOptions struct {
@@ -93,10 +103,14 @@ func (c *conn) handleAuthentication(
 
 	if !exists {
 		ac.Logf(ctx, "user does not exist: %q", c.sessionArgs.User)
-		return nil, sendError(errors.Errorf(security.ErrPasswordUserAuthFailed, c.sessionArgs.User))
-	}
-
-	if !canLogin {
+		// The scram-sha-256 method performs a mock exchange for missing users,
+		// so that clients can't tell them apart from existing users, see
+		// authScram. The other methods fail right away.
+		if _, hbaEntry, _, err := c.findAuthenticationMethod(authOpt); err != nil ||
+			hbaEntry.Method.Value != scramMethod {
+			return nil, sendError(errors.Errorf(security.ErrPasswordUserAuthFailed, c.sessionArgs.User))
+		}
+	} else if !canLogin {
 		ac.Logf(ctx, "%q does not have login privilege", c.sessionArgs.User)
 		return nil, sendError(errors.Errorf(
 			"%s does not have login privilege", c.sessionArgs.User))
@@ -256,6 +270,9 @@ type AuthConn interface {
 	// Logf logs a message on the authentication log, if auth logs
 	// are enabled.
 	Logf(ctx context.Context, format string, args ...interface{})
+	// SessionUser returns the user name sent by the client in the startup
+	// message, which is the user being authenticated.
+	SessionUser() security.SQLUsername
 }
 
 // authPipe is the implementation for the authenticator and AuthConn interfaces.
@@ -334,6 +351,11 @@ func (p *authPipe) Logf(ctx context.Context, format string, args ...interface{})
 	}
 }
 
+// SessionUser is part of the AuthConn interface.
+func (p *authPipe) SessionUser() security.SQLUsername {
+	return p.c.sessionArgs.User
+}
+
 // authResult is part of the authenticator interface.
 func (p *authPipe) authResult() (unqualifiedIntSizer, error) {
 	p.noMorePwdData()
@@ -53,6 +53,20 @@ func loadDefaultMethods() {
 	// a cleartext password.
 	RegisterAuthMethod("cert-password", authCertPassword, hba.ConnAny, nil)
 
+	// The "scram-sha-256" method performs a SCRAM-SHA-256 exchange, which
+	// proves that the client knows the password without sending it over the
+	// wire. Over SSL connections, the client may additionally bind the
+	// exchange to the server certificate.
+	//
+	// Users whose password is not yet stored as SCRAM credentials are
+	// authenticated using a clear text password instead, so that the stored
+	// hash can be converted, but only if that conversion is enabled (see
+	// sql.StoredPasswordHashUpgradeEnabled). Otherwise they cannot log in
+	// with this method. Neither can users without a password, nor users that
+	// don't exist, but all of them go through a mock exchange which only
+	// fails at the proof verification.
+	RegisterAuthMethod(scramMethod, authScram, hba.ConnAny, nil)
+
 	// The "reject" method rejects any connection attempt that matches
 	// the current rule.
 	RegisterAuthMethod("reject", authReject, hba.ConnAny, nil)
@@ -87,7 +101,7 @@ func authPassword(
 	_ tls.ConnectionState,
 	pwRetrieveFn PasswordRetrievalFn,
 	pwValidUntilFn PasswordValidUntilFn,
-	_ *sql.ExecutorConfig,
+	execCfg *sql.ExecutorConfig,
 	_ *hba.Entry,
 ) (security.UserAuthHook, error) {
 	if err := c.SendAuthRequest(authCleartextPassword, nil /* data */); err != nil {
@@ -109,20 +123,40 @@ func authPassword(
 		c.Logf(ctx, "user has no password defined")
 	}
 
+	if err := checkPasswordNotExpired(ctx, c, pwValidUntilFn); err != nil {
+		return nil, err
+	}
+
+	hook := security.UserAuthPasswordHook(
+		false /*insecure*/, password, hashedPassword,
+	)
+	return func(requestedUser security.SQLUsername, clientConnection bool) (func(), error) {
+		connClose, err := hook(requestedUser, clientConnection)
+		if err == nil {
+			// Now that we know the cleartext password, we can convert the
+			// stored hash to the configured method, if needed.
+			sql.MaybeUpgradeStoredPasswordHash(ctx, execCfg, requestedUser, password, hashedPassword)
+		}
+		return connClose, err
+	}, nil
+}
+
+// checkPasswordNotExpired returns an error if the password of the user has
+// expired.
+func checkPasswordNotExpired(
+	ctx context.Context, c AuthConn, pwValidUntilFn PasswordValidUntilFn,
+) error {
 	validUntil, err := pwValidUntilFn(ctx)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if validUntil != nil {
 		if validUntil.Sub(timeutil.Now()) < 0 {
 			c.Logf(ctx, "password is expired")
-			return nil, errors.New("password is expired")
+			return errors.New("password is expired")
 		}
 	}
-
-	return security.UserAuthPasswordHook(
-		false /*insecure*/, password, hashedPassword,
-	), nil
+	return nil
 }
 
 func passwordString(pwdData []byte) (string, error) {
@@ -0,0 +1,336 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/hba"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/errors"
+)
+
+// This file implements the server side of the SASL exchange used by the
+// scram-sha-256 authentication method. The messages are described in
+// https://www.postgresql.org/docs/current/sasl-authentication.html.
+
+const (
+	// scramMethod is the name of the HBA method.
+	scramMethod        = "scram-sha-256"
+	scramMechanism     = "SCRAM-SHA-256"
+	scramPlusMechanism = "SCRAM-SHA-256-PLUS"
+	// scramChannelBinding is the only channel binding type supported by
+	// Postgres and its clients.
+	scramChannelBinding = "tls-server-end-point"
+	// scramNonceLen is the number of random bytes in the server nonce.
+	scramNonceLen = 18
+)
+
+func authScram(
+	ctx context.Context,
+	c AuthConn,
+	tlsState tls.ConnectionState,
+	pwRetrieveFn PasswordRetrievalFn,
+	pwValidUntilFn PasswordValidUntilFn,
+	execCfg *sql.ExecutorConfig,
+	entry *hba.Entry,
+) (security.UserAuthHook, error) {
+	hashedPassword, err := pwRetrieveFn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Users that can't log in with this method go through a mock exchange
+	// using made up credentials, which fails at the proof verification like
+	// for a wrong password. Failing right away would let clients find out
+	// which users exist and how their password is stored. Missing users have
+	// no password either, see handleAuthentication.
+	var creds security.SCRAMCredentials
+	mock := true
+	switch {
+	case len(hashedPassword) == 0:
+		c.Logf(ctx, "user has no password defined, performing a mock SCRAM exchange")
+	case !security.IsSCRAMHash(hashedPassword):
+		// The SCRAM exchange requires SCRAM credentials. The only reason to
+		// accept a cleartext password instead is to convert the stored hash to
+		// SCRAM credentials, so that the next login uses the SCRAM exchange.
+		if sql.StoredPasswordHashUpgradeEnabled(ctx, execCfg.Settings) {
+			c.Logf(ctx, "user has no SCRAM credentials, "+
+				"falling back to cleartext password authentication to convert the stored password hash")
+			return authPassword(ctx, c, tlsState, pwRetrieveFn, pwValidUntilFn, execCfg, entry)
+		}
+		c.Logf(ctx, "user has no SCRAM credentials, and the conversion of "+
+			"stored password hashes to SCRAM-SHA-256 is disabled, performing a mock SCRAM exchange")
+	default:
+		if creds, err = security.ParseSCRAMHash(hashedPassword); err != nil {
+			return nil, err
+		}
+		mock = false
+	}
+	if mock {
+		creds = security.MockSCRAMCredentials(
+			[]byte(sql.ClusterSecret.Get(&execCfg.Settings.SV)), c.SessionUser(),
+		)
+	}
+
+	// Channel binding is only offered over TLS connections.
+	var serverCert *x509.Certificate
+	if tlsState.HandshakeComplete {
+		serverCert, err = scramServerCertificate(execCfg)
+		if err != nil {
+			c.Logf(ctx, "unable to load the server certificate for channel binding: %v", err)
+			serverCert = nil
+		}
+	}
+
+	var mechanisms bytes.Buffer
+	if serverCert != nil {
+		mechanisms.WriteString(scramPlusMechanism)
+		mechanisms.WriteByte(0)
+	}
+	mechanisms.WriteString(scramMechanism)
+	mechanisms.WriteByte(0)
+	mechanisms.WriteByte(0)
+	if err := c.SendAuthRequest(authSASL, mechanisms.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// Read the SASLInitialResponse message.
+	initialResponse, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	mechanism, clientFirst, err := parseSASLInitialResponse(initialResponse)
+	if err != nil {
+		return nil, err
+	}
+	if mechanism != scramMechanism && (mechanism != scramPlusMechanism || serverCert == nil) {
+		return nil, pgwirebase.NewProtocolViolationErrorf(
+			"client selected an invalid SASL authentication mechanism")
+	}
+	gs2Header, clientFirstBare, clientNonce, err := parseScramClientFirst(
+		clientFirst, mechanism, serverCert != nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the server-first-message.
+	nonceBytes := make([]byte, scramNonceLen)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, err
+	}
+	nonce := clientNonce + base64.StdEncoding.EncodeToString(nonceBytes)
+	serverFirst := fmt.Sprintf("r=%s,s=%s,i=%d",
+		nonce, base64.StdEncoding.EncodeToString(creds.Salt), creds.Iterations)
+	if err := c.SendAuthRequest(authSASLContinue, []byte(serverFirst)); err != nil {
+		return nil, err
+	}
+
+	// Read the client-final-message, in a SASLResponse message.
+	clientFinal, err := c.GetPwdData()
+	if err != nil {
+		return nil, err
+	}
+	channelBinding, clientFinalWithoutProof, proof, err := parseScramClientFinal(
+		string(clientFinal), nonce,
+	)
+	if err != nil {
+		return nil, err
+	}
+	// The client repeats the gs2 header, followed by the channel binding
+	// data if channel binding was selected.
+	cbind := []byte(gs2Header)
+	if mechanism == scramPlusMechanism {
+		cbind = append(cbind, security.TLSServerEndPoint(serverCert)...)
+	}
+	if channelBinding != base64.StdEncoding.EncodeToString(cbind) {
+		return nil, pgwirebase.NewProtocolViolationErrorf("SCRAM channel binding check failed")
+	}
+
+	authMessage := []byte(clientFirstBare + "," + serverFirst + "," + clientFinalWithoutProof)
+	if mock {
+		return scramAuthFailedHook, nil
+	}
+	if !creds.VerifyClientProof(authMessage, proof) {
+		c.Logf(ctx, "invalid SCRAM client proof")
+		return scramAuthFailedHook, nil
+	}
+	// Only disclose that the password has expired to clients that know it.
+	if err := checkPasswordNotExpired(ctx, c, pwValidUntilFn); err != nil {
+		return nil, err
+	}
+
+	// Send the server-final-message, which proves to the client that the
+	// server also knows the credentials.
+	serverFinal := "v=" + base64.StdEncoding.EncodeToString(creds.ServerSignature(authMessage))
+	if err := c.SendAuthRequest(authSASLFinal, []byte(serverFinal)); err != nil {
+		return nil, err
+	}
+	return func(requestedUser security.SQLUsername, _ bool) (func(), error) {
+		if requestedUser.Undefined() {
+			return nil, errors.New("user is missing")
+		}
+		return nil, nil
+	}, nil
+}
+
+// scramAuthFailedHook is the authentication hook of the connections that fail
+// to authenticate with scram-sha-256.
+func scramAuthFailedHook(requestedUser security.SQLUsername, _ bool) (func(), error) {
+	return nil, errors.Errorf(security.ErrPasswordUserAuthFailed, requestedUser)
+}
+
+// parseSASLInitialResponse decodes a SASLInitialResponse message into the
+// name of the mechanism selected by the client and the initial response of
+// the mechanism.
+func parseSASLInitialResponse(data []byte) (mechanism string, response string, _ error) {
+	i := bytes.IndexByte(data, 0)
+	if i < 0 || len(data) < i+5 {
+		return "", "", pgwirebase.NewProtocolViolationErrorf("malformed SASLInitialResponse message")
+	}
+	mechanism = string(data[:i])
+	data = data[i+1:]
+	length := int32(binary.BigEndian.Uint32(data))
+	data = data[4:]
+	if length < 0 || int(length) != len(data) {
+		return "", "", pgwirebase.NewProtocolViolationErrorf("malformed SASLInitialResponse message")
+	}
+	return mechanism, string(data), nil
+}
+
+// parseScramClientFirst decodes the client-first-message of a SCRAM exchange.
+// It returns the gs2 header, which the client repeats in its final message,
+// the client-first-message-bare, which is part of the signed auth message,
+// and the client nonce.
+//
+// The user name sent by the client is ignored: like Postgres, we use the
+// user name of the startup message.
+func parseScramClientFirst(
+	msg string, mechanism string, channelBindingOffered bool,
+) (gs2Header, clientFirstBare, clientNonce string, _ error) {
+	malformed := func(format string, args ...interface{}) error {
+		return pgwirebase.NewProtocolViolationErrorf("malformed SCRAM message: "+format, args...)
+	}
+
+	// The gs2 header contains the channel binding flag and an optional
+	// authorization identity.
+	parts := strings.SplitN(msg, ",", 3)
+	if len(parts) != 3 {
+		return "", "", "", malformed("missing attributes in client-first-message")
+	}
+	switch cbFlag := parts[0]; {
+	case cbFlag == "n":
+		// The client does not support channel binding.
+		if mechanism == scramPlusMechanism {
+			return "", "", "", pgwirebase.NewProtocolViolationErrorf(
+				"SCRAM-SHA-256-PLUS selected, but the client does not request channel binding")
+		}
+	case cbFlag == "y":
+		// The client supports channel binding but thinks the server does not.
+		// This is a sign of a downgrade attack if we did offer it.
+		if channelBindingOffered {
+			return "", "", "", pgwirebase.NewProtocolViolationErrorf(
+				"SCRAM channel binding negotiation error: the client supports channel binding " +
+					"but thinks the server does not")
+		}
+	case strings.HasPrefix(cbFlag, "p="):
+		if mechanism != scramPlusMechanism {
+			return "", "", "", pgwirebase.NewProtocolViolationErrorf(
+				"client requires SCRAM channel binding, but it was not negotiated")
+		}
+		if cbFlag[2:] != scramChannelBinding {
+			return "", "", "", pgwirebase.NewProtocolViolationErrorf(
+				"unsupported SCRAM channel-binding type %q", cbFlag[2:])
+		}
+	default:
+		return "", "", "", malformed("unexpected channel-binding flag %q", cbFlag)
+	}
+	if parts[1] != "" {
+		return "", "", "", pgerror.New(pgcode.FeatureNotSupported,
+			"client uses authorization identity, but it is not supported")
+	}
+	gs2Header = parts[0] + "," + parts[1] + ","
+	clientFirstBare = parts[2]
+
+	attrs := strings.Split(clientFirstBare, ",")
+	if len(attrs) < 2 || !strings.HasPrefix(attrs[0], "n=") {
+		return "", "", "", malformed("expected user name in client-first-message")
+	}
+	if len(attrs[1]) <= len("r=") || !strings.HasPrefix(attrs[1], "r=") {
+		return "", "", "", malformed("expected nonce in client-first-message")
+	}
+	return gs2Header, clientFirstBare, attrs[1][2:], nil
+}
+
+// parseScramClientFinal decodes the client-final-message of a SCRAM exchange
+// into the encoded channel binding data, the client-final-message-without-proof,
+// which is part of the signed auth message, and the decoded client proof. The
+// nonce must match the one sent in the server-first-message.
+func parseScramClientFinal(
+	msg string, nonce string,
+) (channelBinding, clientFinalWithoutProof string, proof []byte, _ error) {
+	attrs := strings.Split(msg, ",")
+	if len(attrs) < 3 || !strings.HasPrefix(attrs[0], "c=") || !strings.HasPrefix(attrs[1], "r=") {
+		return "", "", nil, pgwirebase.NewProtocolViolationErrorf(
+			"malformed SCRAM message: invalid client-final-message")
+	}
+	if attrs[1][2:] != nonce {
+		return "", "", nil, pgwirebase.NewProtocolViolationErrorf(
+			"malformed SCRAM message: unexpected SCRAM nonce")
+	}
+	last := attrs[len(attrs)-1]
+	if !strings.HasPrefix(last, "p=") {
+		return "", "", nil, pgwirebase.NewProtocolViolationErrorf(
+			"malformed SCRAM message: missing proof in client-final-message")
+	}
+	proof, err := base64.StdEncoding.DecodeString(last[2:])
+	if err != nil {
+		return "", "", nil, pgwirebase.NewProtocolViolationErrorf(
+			"malformed SCRAM message: invalid proof in client-final-message")
+	}
+	return attrs[0][2:], msg[:len(msg)-len(last)-1], proof, nil
+}
+
+// scramServerCertificate returns the certificate which the server presents
+// to SQL clients, whose hash is used as the channel binding data.
+func scramServerCertificate(execCfg *sql.ExecutorConfig) (*x509.Certificate, error) {
+	tlsConfig, err := execCfg.RPCContext.GetServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	var cert *tls.Certificate
+	if tlsConfig.GetCertificate != nil {
+		if cert, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{}); err != nil {
+			return nil, err
+		}
+	} else if len(tlsConfig.Certificates) > 0 {
+		cert = &tlsConfig.Certificates[0]
+	}
+	if cert == nil || len(cert.Certificate) == 0 {
+		return nil, errors.New("no server certificate")
+	}
+	if cert.Leaf != nil {
+		return cert.Leaf, nil
+	}
+	return x509.ParseCertificate(cert.Certificate[0])
+}
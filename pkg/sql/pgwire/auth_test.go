@@ -265,7 +265,20 @@ func hbaRunTest(t *testing.T, insecure bool) {
 					return string(body), nil
 
 				case "sql":
-					_, err := conn.ExecContext(context.Background(), td.Input)
+					if !td.HasArg("retry") {
+						_, err := conn.ExecContext(context.Background(), td.Input)
+						return "ok", err
+					}
+					// Retry the statement until it produces the expected result, for
+					// effects of the previous commands that are applied asynchronously.
+					var err error
+					_ = testutils.SucceedsSoonError(func() error {
+						_, err = conn.ExecContext(context.Background(), td.Input)
+						if res := fmtErr(err); res != strings.TrimSpace(td.Expected) {
+							return errors.Newf("unexpected result: %s", res)
+						}
+						return nil
+					})
 					return "ok", err
 
 				case "authlog":
@@ -85,6 +85,10 @@ type commandResult struct {
 	// (except types must always be set).
 	types []*types.T
 
+	// copyOut describes the encoding of the rows of COPY TO statements, which
+	// are sent as CopyData messages instead of DataRow messages.
+	copyOut copyOutFormat
+
 	// bufferingDisabled is conditionally set during planning of certain
 	// statements.
 	bufferingDisabled bool
@@ -140,6 +144,9 @@ func (r *commandResult) Close(ctx context.Context, t sql.TransactionStatusIndica
 	// Send a completion message, specific to the type of result.
 	switch r.typ {
 	case commandComplete:
+		if r.stmtType == tree.CopyOut {
+			r.conn.bufferCopyDone(&r.copyOut)
+		}
 		tag := cookTag(
 			r.cmdCompleteTag, r.conn.writerState.tagBuf[:0], r.stmtType, r.rowsAffected,
 		)
@@ -203,7 +210,11 @@ func (r *commandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	}
 	r.rowsAffected++
 
-	r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
+	if r.stmtType == tree.CopyOut {
+		r.conn.bufferCopyData(ctx, row, &r.copyOut, r.conv, r.location, r.types)
+	} else {
+		r.conn.bufferRow(ctx, row, r.formatCodes, r.conv, r.location, r.types)
+	}
 	var err error
 	if r.bufferingDisabled {
 		err = r.conn.Flush(r.pos)
@@ -236,7 +247,11 @@ func (r *commandResult) BufferNotice(notice pgnotice.Notice) {
 func (r *commandResult) SetColumns(ctx context.Context, cols colinfo.ResultColumns) {
 	r.assertNotReleased()
 	r.conn.writerState.fi.registerCmd(r.pos)
-	if r.descOpt == sql.NeedRowDesc {
+	if r.stmtType == tree.CopyOut {
+		// COPY TO statements describe their results with a CopyOutResponse
+		// message, regardless of whether a row description was requested.
+		r.conn.bufferCopyOutResponse(cols, &r.copyOut)
+	} else if r.descOpt == sql.NeedRowDesc {
 		_ /* err */ = r.conn.writeRowDescription(ctx, cols, r.formatCodes, &r.conn.writerState.buf)
 	}
 	r.types = make([]*types.T, len(cols))
@@ -292,6 +307,7 @@ func (r *commandResult) ResetStmtType(stmt tree.Statement) {
 	r.assertNotReleased()
 	r.stmtType = stmt.StatementType()
 	r.cmdCompleteTag = stmt.StatementTag()
+	r.copyOut = makeCopyOutFormat(stmt)
 }
 
 // release frees the commandResult and allows its memory to be reused.
@@ -344,8 +360,11 @@ func (c *conn) newCommandResult(
 		stmtType:       stmt.StatementType(),
 		descOpt:        descOpt,
 		formatCodes:    formatCodes,
+		copyOut:        makeCopyOutFormat(stmt),
 	}
-	if limit == 0 {
+	if limit == 0 || r.stmtType == tree.CopyOut {
+		// COPY TO statements always send all their rows; portal suspension
+		// doesn't apply to the COPY sub-protocol.
 		return r
 	}
 	telemetry.Inc(sqltelemetry.PortalWithLimitRequestCounter)
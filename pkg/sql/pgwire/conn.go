@@ -87,6 +87,9 @@ type conn struct {
 
 	readBuf    pgwirebase.ReadBuffer
 	msgBuilder writeBuffer
+	// copyOutBuf is scratch space used to format the values of COPY TO
+	// statements before they're escaped into msgBuilder.
+	copyOutBuf writeBuffer
 
 	sv *settings.Values
 
@@ -171,6 +174,7 @@ func newConn(
 	c.writerState.fi.lastFlushed = -1
 	c.writerState.fi.cmdStarts = make(map[sql.CmdPos]int)
 	c.msgBuilder.init(metrics.BytesOutCount)
+	c.copyOutBuf.init(metrics.BytesOutCount)
 
 	return c
 }
@@ -683,6 +687,15 @@ func (c *conn) sendInitialConnData(
 		return sql.ConnectionHandler{}, err
 	}
 
+	// Send the client the key it can use to cancel queries running in this
+	// session via a separate CancelRequest connection.
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgBackendKeyData)
+	c.msgBuilder.putInt32(c.sessionArgs.QueryCancelKey.ProcessID())
+	c.msgBuilder.putInt32(c.sessionArgs.QueryCancelKey.SecretKey())
+	if err := c.msgBuilder.finishMsg(c.conn); err != nil {
+		return sql.ConnectionHandler{}, err
+	}
+
 	// An initial readyForQuery message is part of the handshake.
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgReady)
 	c.msgBuilder.writeByte(byte(sql.IdleTxnBlock))
@@ -1112,7 +1125,7 @@ func cookTag(tagStr string, buf []byte, stmtType tree.StatementType, rowsAffecte
 		tag = append(tag, ' ')
 		tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
 
-	case tree.Rows:
+	case tree.Rows, tree.CopyOut:
 		tag = append(tag, ' ')
 		tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
 
@@ -0,0 +1,211 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// copyBinarySignature is the header of the binary COPY format: the signature,
+// followed by the flags field and the length of the header extension area,
+// both of which are always zero.
+var copyBinarySignature = []byte("PGCOPY\n\377\r\n\000" + "\000\000\000\000" + "\000\000\000\000")
+
+// copyOutFormat describes how the rows of a COPY TO statement are encoded in
+// CopyData messages. The options have been validated during planning.
+type copyOutFormat struct {
+	format    tree.CopyFormat
+	delimiter byte
+	null      string
+	header    bool
+}
+
+// makeCopyOutFormat returns the copyOutFormat of a COPY TO statement, filling
+// in the Postgres defaults for the options which weren't specified. It returns
+// the zero value for all other statements.
+func makeCopyOutFormat(stmt tree.Statement) copyOutFormat {
+	copyTo, ok := stmt.(*tree.CopyTo)
+	if !ok {
+		return copyOutFormat{}
+	}
+	opts := &copyTo.Options
+	f := copyOutFormat{
+		format:    opts.CopyFormat,
+		delimiter: '\t',
+		null:      `\N`,
+		header:    opts.Header,
+	}
+	if f.format == tree.CopyFormatCSV {
+		f.delimiter = ','
+		f.null = ""
+	}
+	if opts.Delimiter != nil {
+		f.delimiter = opts.Delimiter.(*tree.StrVal).RawString()[0]
+	}
+	if opts.Null != nil {
+		f.null = opts.Null.(*tree.StrVal).RawString()
+	}
+	return f
+}
+
+// bufferCopyOutResponse writes a CopyOutResponse message announcing the
+// columns of a COPY TO statement, followed by the binary header or the CSV
+// header line, if any.
+func (c *conn) bufferCopyOutResponse(cols colinfo.ResultColumns, f *copyOutFormat) {
+	fmtCode := pgwirebase.FormatText
+	if f.format == tree.CopyFormatBinary {
+		fmtCode = pgwirebase.FormatBinary
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	c.msgBuilder.writeByte(byte(fmtCode))
+	c.msgBuilder.putInt16(int16(len(cols)))
+	for range cols {
+		c.msgBuilder.putInt16(int16(fmtCode))
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+
+	switch {
+	case f.format == tree.CopyFormatBinary:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.write(copyBinarySignature)
+	case f.header:
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		for i := range cols {
+			if i > 0 {
+				c.msgBuilder.writeByte(f.delimiter)
+			}
+			writeCopyCSVField(&c.msgBuilder, []byte(cols[i].Name), f)
+		}
+		c.msgBuilder.writeByte('\n')
+	default:
+		return
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyData serializes a row of a COPY TO statement into a CopyData
+// message and adds it to the buffer.
+func (c *conn) bufferCopyData(
+	ctx context.Context,
+	row tree.Datums,
+	f *copyOutFormat,
+	conv sessiondatapb.DataConversionConfig,
+	sessionLoc *time.Location,
+	types []*types.T,
+) {
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+	if f.format == tree.CopyFormatBinary {
+		// Each tuple of the binary format is laid out like a DataRow message.
+		c.msgBuilder.putInt16(int16(len(row)))
+		for i, col := range row {
+			c.msgBuilder.writeBinaryDatum(ctx, col, sessionLoc, types[i])
+		}
+	} else {
+		for i, col := range row {
+			if i > 0 {
+				c.msgBuilder.writeByte(f.delimiter)
+			}
+			if col == tree.DNull {
+				c.msgBuilder.writeString(f.null)
+				continue
+			}
+			// Format the datum as text into the scratch buffer, and strip the
+			// length prefix.
+			c.copyOutBuf.reset()
+			c.copyOutBuf.writeTextDatum(ctx, col, conv, sessionLoc, types[i])
+			val := c.copyOutBuf.wrapped.Bytes()[4:]
+			if f.format == tree.CopyFormatCSV {
+				writeCopyCSVField(&c.msgBuilder, val, f)
+			} else {
+				writeCopyTextField(&c.msgBuilder, val, f)
+			}
+		}
+		c.msgBuilder.writeByte('\n')
+	}
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// bufferCopyDone writes the binary trailer, if needed, and the CopyDone
+// message which ends the data of a COPY TO statement.
+func (c *conn) bufferCopyDone(f *copyOutFormat) {
+	if f.format == tree.CopyFormatBinary {
+		c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyData)
+		c.msgBuilder.putInt16(-1)
+		if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+	}
+	c.msgBuilder.initMsg(pgwirebase.ServerMsgCopyDone)
+	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// writeCopyTextField writes a value in the text COPY format, escaping the
+// characters which would otherwise be interpreted as delimiters.
+func writeCopyTextField(b *writeBuffer, val []byte, f *copyOutFormat) {
+	for _, ch := range val {
+		switch ch {
+		case '\\':
+			b.writeString(`\\`)
+		case '\n':
+			b.writeString(`\n`)
+		case '\r':
+			b.writeString(`\r`)
+		case '\t':
+			b.writeString(`\t`)
+		case f.delimiter:
+			b.writeByte('\\')
+			b.writeByte(ch)
+		default:
+			b.writeByte(ch)
+		}
+	}
+}
+
+// writeCopyCSVField writes a value in the CSV COPY format. The value is quoted
+// if it could otherwise be confused with a delimiter, a line break or the NULL
+// string; embedded quotes are doubled.
+func writeCopyCSVField(b *writeBuffer, val []byte, f *copyOutFormat) {
+	needsQuotes := string(val) == f.null || string(val) == `\.`
+	for _, ch := range val {
+		if ch == f.delimiter || ch == '"' || ch == '\n' || ch == '\r' {
+			needsQuotes = true
+			break
+		}
+	}
+	if !needsQuotes {
+		b.write(val)
+		return
+	}
+	b.writeByte('"')
+	for _, ch := range val {
+		if ch == '"' {
+			b.writeByte('"')
+		}
+		b.writeByte(ch)
+	}
+	b.writeByte('"')
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/server"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
@@ -44,6 +45,7 @@ import (
 	"github.com/jackc/pgproto3/v2"
 	"github.com/jackc/pgx"
 	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
 )
 
 func wrongArgCountString(want, got int) string {
@@ -1918,12 +1920,116 @@ func TestCancelRequest(t *testing.T) {
 		if _, err := fe.Receive(); !errors.Is(err, io.ErrUnexpectedEOF) {
 			t.Fatalf("unexpected: %v", err)
 		}
-		if count := telemetry.GetRawFeatureCounts()["pgwire.unimplemented.cancel_request"]; count != 1 {
+		if count := telemetry.GetRawFeatureCounts()["pgwire.cancel_request"]; count != 1 {
 			t.Fatalf("expected 1 cancel request, got %d", count)
 		}
 	})
 }
 
+// TestCancelQuery checks that a client can cancel a running query by
+// sending a CancelRequest with the BackendKeyData for its session.
+// lib/pq does this when the context of the query is canceled.
+func TestCancelQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupFn := sqlutils.PGUrl(
+		t, s.ServingSQLAddr(), "TestCancelQuery", url.User(security.RootUser))
+	defer cleanupFn()
+	db, err := gosql.Open("postgres", pgURL.String())
+	require.NoError(t, err)
+	defer db.Close()
+
+	cancelCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = db.QueryContext(cancelCtx, "SELECT pg_sleep(30)")
+	require.Error(t, err)
+	require.Regexp(t, "query execution canceled", err)
+
+	testutils.SucceedsSoon(t, func() error {
+		if count := s.MustGetSQLNetworkCounter("sql.pgwire_cancel.successful"); count != 1 {
+			return errors.Newf("expected 1 successful cancel request, got %d", count)
+		}
+		return nil
+	})
+}
+
+// TestCancelQueryOtherNode checks that a CancelRequest sent to a node
+// other than the one serving the session is routed to the right node.
+func TestCancelQueryOtherNode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartNewTestCluster(t, 2, base.TestClusterArgs{
+		ServerArgs: base.TestServerArgs{Insecure: true},
+	})
+	defer tc.Stopper().Stop(ctx)
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", tc.Server(0).ServingSQLAddr())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Open a session on the first node and remember its BackendKeyData.
+	fe := pgproto3.NewFrontend(pgproto3.NewChunkReader(conn), conn)
+	// version30 is the protocol version for a normal SQL connection.
+	const version30 = 196608
+	require.NoError(t, fe.Send(&pgproto3.StartupMessage{
+		ProtocolVersion: version30,
+		Parameters:      map[string]string{"user": security.RootUser},
+	}))
+	var keyData *pgproto3.BackendKeyData
+	for {
+		msg, err := fe.Receive()
+		require.NoError(t, err)
+		if m, ok := msg.(*pgproto3.BackendKeyData); ok {
+			keyData = &pgproto3.BackendKeyData{ProcessID: m.ProcessID, SecretKey: m.SecretKey}
+		}
+		if _, ok := msg.(*pgproto3.ReadyForQuery); ok {
+			break
+		}
+	}
+	require.NotNil(t, keyData)
+	require.NoError(t, fe.Send(&pgproto3.Query{String: "SELECT pg_sleep(30)"}))
+
+	// Wait until the query is running so that there is something to cancel.
+	sqlDB := sqlutils.MakeSQLRunner(tc.ServerConn(1))
+	testutils.SucceedsSoon(t, func() error {
+		var count int
+		sqlDB.QueryRow(t,
+			`SELECT count(*) FROM [SHOW CLUSTER QUERIES] WHERE query LIKE 'SELECT pg_sleep%'`,
+		).Scan(&count)
+		if count != 1 {
+			return errors.Newf("expected 1 running query, found %d", count)
+		}
+		return nil
+	})
+
+	// Send the cancel request to the second node.
+	cancelConn, err := d.DialContext(ctx, "tcp", tc.Server(1).ServingSQLAddr())
+	require.NoError(t, err)
+	defer cancelConn.Close()
+	cancelFe := pgproto3.NewFrontend(pgproto3.NewChunkReader(cancelConn), cancelConn)
+	require.NoError(t, cancelFe.Send(&pgproto3.CancelRequest{
+		ProcessID: keyData.ProcessID,
+		SecretKey: keyData.SecretKey,
+	}))
+
+	for {
+		msg, err := fe.Receive()
+		require.NoError(t, err)
+		if m, ok := msg.(*pgproto3.ErrorResponse); ok {
+			require.Equal(t, pgcode.QueryCanceled.String(), m.Code)
+			break
+		}
+	}
+}
+
 func TestFailPrepareFailsTxn(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
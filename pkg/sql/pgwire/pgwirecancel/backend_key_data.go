@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package pgwirecancel contains helpers for encoding and decoding the
+// BackendKeyData that is used by the PostgreSQL wire protocol to cancel
+// the currently running queries of a session.
+package pgwirecancel
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+)
+
+// BackendKeyData is a 64-bit identifier used by the pgwire protocol to cancel
+// queries. It is sent to the client in the BackendKeyData message when a
+// session starts, and the client sends it back in a CancelRequest on a new
+// connection. The PostgreSQL protocol defines it as a 32-bit process ID
+// followed by a 32-bit secret key; clients treat both halves as opaque.
+//
+// The key is made up of the SQLInstanceID of the node that owns the session,
+// which lets any gateway node route the cancel request to the right place,
+// and random bits that act as the secret. If the leading bit is set, then the
+// SQLInstanceID is encoded in the following 11 bits, leaving 52 random bits.
+// Otherwise, the SQLInstanceID takes up the rest of the upper 32 bits and the
+// lower 32 bits are random.
+type BackendKeyData uint64
+
+const (
+	// leadingBitMask is set when the short (11-bit) instance ID encoding is
+	// used.
+	leadingBitMask = 1 << 63
+
+	// shortInstanceIDBits is the number of bits available to the instance ID
+	// in the short encoding.
+	shortInstanceIDBits  = 11
+	shortInstanceIDShift = 63 - shortInstanceIDBits
+	shortInstanceIDMask  = (1<<shortInstanceIDBits - 1) << shortInstanceIDShift
+	shortRandomMask      = 1<<shortInstanceIDShift - 1
+
+	// longInstanceIDShift is the position of the instance ID in the long
+	// encoding.
+	longInstanceIDShift = 32
+	longInstanceIDMask  = (1<<31 - 1) << longInstanceIDShift
+	longRandomMask      = 1<<longInstanceIDShift - 1
+)
+
+// MakeBackendKeyData returns a new BackendKeyData that encodes the given
+// SQLInstanceID alongside random bits drawn from a cryptographically secure
+// source.
+func MakeBackendKeyData(sqlInstanceID base.SQLInstanceID) BackendKeyData {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		// crypto/rand only fails if the OS entropy source is unavailable, in
+		// which case there is nothing sensible to do.
+		panic(fmt.Sprintf("unable to generate random cancel key: %v", err))
+	}
+	return makeBackendKeyData(binary.BigEndian.Uint64(buf[:]), sqlInstanceID)
+}
+
+func makeBackendKeyData(random uint64, sqlInstanceID base.SQLInstanceID) BackendKeyData {
+	if sqlInstanceID < 1<<shortInstanceIDBits {
+		// Most clusters have few enough instances that the ID fits in 11 bits,
+		// which leaves more room for the random secret.
+		return BackendKeyData(leadingBitMask |
+			(uint64(sqlInstanceID) << shortInstanceIDShift) |
+			(random & shortRandomMask))
+	}
+	return BackendKeyData(
+		(uint64(sqlInstanceID) << longInstanceIDShift & longInstanceIDMask) |
+			(random & longRandomMask))
+}
+
+// GetSQLInstanceID returns the SQLInstanceID encoded in the key.
+func (b BackendKeyData) GetSQLInstanceID() base.SQLInstanceID {
+	bits := uint64(b)
+	if bits&leadingBitMask != 0 {
+		return base.SQLInstanceID((bits & shortInstanceIDMask) >> shortInstanceIDShift)
+	}
+	return base.SQLInstanceID((bits & longInstanceIDMask) >> longInstanceIDShift)
+}
+
+// ProcessID returns the upper 32 bits of the key, which is sent to the client
+// as the "process ID" half of the BackendKeyData message.
+func (b BackendKeyData) ProcessID() int32 {
+	return int32(uint64(b) >> 32)
+}
+
+// SecretKey returns the lower 32 bits of the key, which is sent to the client
+// as the "secret key" half of the BackendKeyData message.
+func (b BackendKeyData) SecretKey() int32 {
+	return int32(uint32(b))
+}
+
+// FromProcessIDAndSecretKey reassembles a BackendKeyData from the two halves
+// sent by a client in a CancelRequest.
+func FromProcessIDAndSecretKey(processID, secretKey int32) BackendKeyData {
+	return BackendKeyData(uint64(uint32(processID))<<32 | uint64(uint32(secretKey)))
+}
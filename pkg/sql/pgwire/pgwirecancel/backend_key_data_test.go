@@ -0,0 +1,43 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwirecancel
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackendKeyData(t *testing.T) {
+	for _, id := range []base.SQLInstanceID{
+		0, 1, 2, 100, 1<<shortInstanceIDBits - 1, 1 << shortInstanceIDBits, 123456, math.MaxInt32,
+	} {
+		for _, random := range []uint64{0, 1, 0xdeadbeef, math.MaxUint64} {
+			k := makeBackendKeyData(random, id)
+			require.Equal(t, id, k.GetSQLInstanceID(), "key %x", uint64(k))
+			require.Equal(t, k, FromProcessIDAndSecretKey(k.ProcessID(), k.SecretKey()))
+		}
+		k := MakeBackendKeyData(id)
+		require.Equal(t, id, k.GetSQLInstanceID())
+	}
+}
+
+func TestBackendKeyDataIsRandom(t *testing.T) {
+	seen := make(map[BackendKeyData]struct{})
+	for i := 0; i < 100; i++ {
+		k := MakeBackendKeyData(1)
+		_, ok := seen[k]
+		require.False(t, ok, "duplicate key %x", uint64(k))
+		seen[k] = struct{}{}
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
@@ -30,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -43,6 +45,8 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/redact"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // ATTENTION: After changing this value in a unit test, you probably want to
@@ -114,6 +118,24 @@ var (
 		Measurement: "SQL Bytes",
 		Unit:        metric.Unit_BYTES,
 	}
+	MetaPGWireCancelTotal = metric.Metadata{
+		Name:        "sql.pgwire_cancel.total",
+		Help:        "Counter of the number of pgwire query cancel requests",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaPGWireCancelIgnored = metric.Metadata{
+		Name:        "sql.pgwire_cancel.ignored",
+		Help:        "Counter of the number of pgwire query cancel requests that were ignored due to rate limiting",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	MetaPGWireCancelSuccessful = metric.Metadata{
+		Name:        "sql.pgwire_cancel.successful",
+		Help:        "Counter of the number of pgwire query cancel requests that were successful",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
 )
 
 const (
@@ -195,6 +217,10 @@ type ServerMetrics struct {
 	NewConns       *metric.Counter
 	ConnMemMetrics sql.BaseMemoryMetrics
 	SQLMemMetrics  sql.MemoryMetrics
+
+	PGWireCancelTotalCount      *metric.Counter
+	PGWireCancelIgnoredCount    *metric.Counter
+	PGWireCancelSuccessfulCount *metric.Counter
 }
 
 func makeServerMetrics(
@@ -207,6 +233,10 @@ func makeServerMetrics(
 		NewConns:       metric.NewCounter(MetaNewConns),
 		ConnMemMetrics: sql.MakeBaseMemMetrics("conns", histogramWindow),
 		SQLMemMetrics:  sqlMemMetrics,
+
+		PGWireCancelTotalCount:      metric.NewCounter(MetaPGWireCancelTotal),
+		PGWireCancelIgnoredCount:    metric.NewCounter(MetaPGWireCancelIgnored),
+		PGWireCancelSuccessfulCount: metric.NewCounter(MetaPGWireCancelSuccessful),
 	}
 }
 
@@ -518,7 +548,8 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn, socketType Socket
 	if version == versionCancel {
 		// The cancel message is rather peculiar: it is sent without
 		// authentication, always over an unencrypted channel.
-		return handleCancel(conn)
+		s.handleCancel(ctx, conn, &buf)
+		return nil
 	}
 
 	// If the server is shutting down, terminate the connection early.
@@ -554,7 +585,8 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn, socketType Socket
 		// Yet, we've found clients in the wild that send the cancel
 		// after the TLS handshake, for example at
 		// https://github.com/cockroachlabs/support/issues/600.
-		return handleCancel(conn)
+		s.handleCancel(ctx, conn, &buf)
+		return nil
 
 	default:
 		// We don't know this protocol.
@@ -577,6 +609,7 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn, socketType Socket
 	if sArgs, err = parseClientProvidedSessionParameters(ctx, &s.execCfg.Settings.SV, &buf); err != nil {
 		return s.sendErr(ctx, conn, err)
 	}
+	sArgs.QueryCancelKey = pgwirecancel.MakeBackendKeyData(s.execCfg.NodeID.SQLInstanceID())
 
 	// If a test is hooking in some authentication option, load it.
 	var testingAuthHook func(context.Context) error
@@ -599,12 +632,42 @@ func (s *Server) ServeConn(ctx context.Context, conn net.Conn, socketType Socket
 	return nil
 }
 
-func handleCancel(conn net.Conn) error {
-	// Since we don't support this, close the door in the client's
-	// face. Make a note of that use in telemetry.
+// handleCancel handles a pgwire query cancel request. The payload is
+// the BackendKeyData that was sent to the client when the session to
+// cancel was established. The request is routed to the node that
+// serves that session, which may not be the current node.
+//
+// The client does not expect a response, so the connection is closed
+// right away and any error is only logged.
+func (s *Server) handleCancel(ctx context.Context, conn net.Conn, buf *pgwirebase.ReadBuffer) {
 	telemetry.Inc(sqltelemetry.CancelRequestCounter)
+	s.metrics.PGWireCancelTotalCount.Inc(1)
 	_ = conn.Close()
-	return nil
+
+	resp, err := func() (*serverpb.CancelQueryByKeyResponse, error) {
+		backendKeyDataBits, err := buf.GetUint64()
+		if err != nil {
+			return nil, err
+		}
+		cancelKey := pgwirecancel.BackendKeyData(backendKeyDataBits)
+		// The request is forwarded to the appropriate node.
+		req := &serverpb.CancelQueryByKeyRequest{
+			SQLInstanceID:  cancelKey.GetSQLInstanceID(),
+			CancelQueryKey: cancelKey,
+		}
+		return s.execCfg.SQLStatusServer.CancelQueryByKey(ctx, req)
+	}()
+	if resp != nil && len(resp.Error) > 0 {
+		err = errors.CombineErrors(err, errors.Newf("error from CancelQueryByKeyResponse: %s", resp.Error))
+	}
+
+	if resp != nil && resp.Canceled {
+		s.metrics.PGWireCancelSuccessfulCount.Inc(1)
+	} else if respStatus := status.Convert(err); respStatus.Code() == codes.ResourceExhausted {
+		s.metrics.PGWireCancelIgnoredCount.Inc(1)
+	} else if err != nil {
+		log.Sessions.Warningf(ctx, "unexpected error while handling pgwire cancellation request: %v", err)
+	}
 }
 
 // parseClientProvidedSessionParameters reads the incoming k/v pairs
@@ -147,10 +147,12 @@ var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &changePrivilegesNode{}
 var _ planNode = &createDatabaseNode{}
+var _ planNode = &createFunctionNode{}
 var _ planNode = &createIndexNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
+var _ planNode = &createTriggerNode{}
 var _ planNode = &createTypeNode{}
 var _ planNode = &CreateRoleNode{}
 var _ planNode = &createViewNode{}
@@ -159,15 +161,18 @@ var _ planNode = &deleteNode{}
 var _ planNode = &deleteRangeNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
+var _ planNode = &dropFunctionNode{}
 var _ planNode = &dropIndexNode{}
 var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
+var _ planNode = &dropTriggerNode{}
 var _ planNode = &dropTypeNode{}
 var _ planNode = &DropRoleNode{}
 var _ planNode = &dropViewNode{}
 var _ planNode = &errorIfRowsNode{}
 var _ planNode = &explainVecNode{}
+var _ planNode = &fetchNode{}
 var _ planNode = &filterNode{}
 var _ planNode = &GrantRoleNode{}
 var _ planNode = &groupNode{}
@@ -219,6 +224,7 @@ var _ planNodeFastPath = &serializeNode{}
 var _ planNodeFastPath = &setZoneConfigNode{}
 var _ planNodeFastPath = &controlJobsNode{}
 var _ planNodeFastPath = &controlSchedulesNode{}
+var _ planNodeFastPath = &fetchNode{}
 
 var _ planNodeReadingOwnWrites = &alterIndexNode{}
 var _ planNodeReadingOwnWrites = &alterSchemaNode{}
@@ -228,11 +234,15 @@ var _ planNodeReadingOwnWrites = &alterTypeNode{}
 var _ planNodeReadingOwnWrites = &createIndexNode{}
 var _ planNodeReadingOwnWrites = &createSequenceNode{}
 var _ planNodeReadingOwnWrites = &createDatabaseNode{}
+var _ planNodeReadingOwnWrites = &createFunctionNode{}
 var _ planNodeReadingOwnWrites = &createTableNode{}
+var _ planNodeReadingOwnWrites = &createTriggerNode{}
 var _ planNodeReadingOwnWrites = &createTypeNode{}
 var _ planNodeReadingOwnWrites = &createViewNode{}
 var _ planNodeReadingOwnWrites = &changePrivilegesNode{}
+var _ planNodeReadingOwnWrites = &dropFunctionNode{}
 var _ planNodeReadingOwnWrites = &dropSchemaNode{}
+var _ planNodeReadingOwnWrites = &dropTriggerNode{}
 var _ planNodeReadingOwnWrites = &dropTypeNode{}
 var _ planNodeReadingOwnWrites = &refreshMaterializedViewNode{}
 var _ planNodeReadingOwnWrites = &reparentDatabaseNode{}
@@ -407,13 +417,26 @@ type planComponents struct {
 	// checkPlans contains all the plans for queries that are to be executed after
 	// the main query (for example, foreign key checks).
 	checkPlans []checkPlan
+
+	// triggerPlans contains the plans for the actions of the triggers that
+	// were run. A row-level trigger has one plan for each modified row.
+	triggerPlans []planMaybePhysical
+
+	// triggerBuffers contains the buffers that were filled for BEFORE
+	// triggers, and the single-row buffers that were created for row-level
+	// triggers. These are not part of any other plan.
+	triggerBuffers []*bufferNode
 }
 
 type cascadeMetadata struct {
 	exec.Cascade
 	// plan for the cascade. This plan is not populated upfront; it is created
 	// only when it needs to run, after the main query (and previous cascades).
+	// It is not populated for triggers (see planComponents.triggerPlans).
 	plan planMaybePhysical
+	// triggerDepth is the number of triggers that are being run by the
+	// statement that queued the cascade (0 for the main query).
+	triggerDepth int
 }
 
 // checkPlan is a query tree that is executed after the main one. It can only
@@ -422,6 +445,17 @@ type checkPlan struct {
 	plan planMaybePhysical
 }
 
+// hasBeforeTriggers returns true if the main query has BEFORE triggers, which
+// must run before the main query.
+func (p *planComponents) hasBeforeTriggers() bool {
+	for i := range p.cascades {
+		if p.cascades[i].Before {
+			return true
+		}
+	}
+	return false
+}
+
 // close calls Close on all plan trees.
 func (p *planComponents) close(ctx context.Context) {
 	p.main.Close(ctx)
@@ -434,6 +468,12 @@ func (p *planComponents) close(ctx context.Context) {
 	for i := range p.checkPlans {
 		p.checkPlans[i].plan.Close(ctx)
 	}
+	for i := range p.triggerPlans {
+		p.triggerPlans[i].Close(ctx)
+	}
+	for _, buf := range p.triggerBuffers {
+		buf.Close(ctx)
+	}
 }
 
 // init resets planTop to point to a given statement; used at the start of the
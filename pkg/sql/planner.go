@@ -159,6 +159,14 @@ type planner struct {
 
 	preparedStatements preparedStatementsAccessor
 
+	// sqlCursors contains the SQL cursors of the session.
+	sqlCursors *cursorMap
+
+	// deferredConstraints contains the state of the deferrable constraints in
+	// the current transaction. It is nil if the checks of deferrable
+	// constraints cannot be deferred, for example for internal executors.
+	deferredConstraints *deferredConstraints
+
 	// avoidCachedDescriptors, when true, instructs all code that
 	// accesses table/view descriptors to force reading the descriptors
 	// within the transaction. This is necessary to read descriptors
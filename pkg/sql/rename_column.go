@@ -166,6 +166,21 @@ func (p *planner) renameColumn(
 		}
 	}
 
+	// Rename the column in row-level security policies.
+	for i := range tableDesc.Policies {
+		p := &tableDesc.Policies[i]
+		for _, expr := range []*string{&p.UsingExpr, &p.WithCheckExpr} {
+			if *expr == "" {
+				continue
+			}
+			newExpr, err := schemaexpr.RenameColumn(*expr, *oldName, *newName)
+			if err != nil {
+				return false, err
+			}
+			*expr = newExpr
+		}
+	}
+
 	// Rename the column in computed columns.
 	for i := range tableDesc.Columns {
 		if otherCol := &tableDesc.Columns[i]; otherCol.IsComputed() {
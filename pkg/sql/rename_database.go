@@ -154,10 +154,46 @@ func (n *renameDatabaseNode) startExec(params runParams) error {
 				return err
 			}
 			if !found {
+				// Views refer to the functions they call by their fully qualified
+				// names as well.
+				found, fnDesc, err := p.Descriptors().GetImmutableFunctionByName(
+					ctx, p.txn, &tbNames[i], tree.ObjectLookupFlags{CommonLookupFlags: lookupFlags},
+				)
+				if err != nil {
+					return err
+				}
+				if found && len(fnDesc.ReferencingDescriptorIDs) > 0 {
+					fnName := tree.MakeTableNameWithSchema(
+						tree.Name(dbDesc.GetName()),
+						tree.Name(schema),
+						tree.Name(fnDesc.GetName()),
+					)
+					return p.dependentViewError(
+						ctx, "database of function", fnName.String(), dbDesc.GetID(),
+						fnDesc.ReferencingDescriptorIDs[0], "rename",
+					)
+				}
 				continue
 			}
 
 			if err := tbDesc.ForeachDependedOnBy(func(dependedOn *descpb.TableDescriptor_Reference) error {
+				// The bodies of functions always refer to relations by their fully
+				// qualified names, so a function that depends on a relation in this
+				// database prevents renaming it.
+				fnDesc, err := p.getDependentFunction(ctx, dependedOn.ID)
+				if err != nil {
+					return err
+				}
+				if fnDesc != nil {
+					tbTableName := tree.MakeTableNameWithSchema(
+						tree.Name(dbDesc.GetName()),
+						tree.Name(schema),
+						tree.Name(tbDesc.GetName()),
+					)
+					return p.dependentFunctionError(
+						ctx, "database of relation", tbTableName.String(), fnDesc, "rename",
+					)
+				}
 				dependentDesc, err := catalogkv.MustGetTableDescByID(ctx, p.txn, p.ExecCfg().Codec, dependedOn.ID)
 				if err != nil {
 					return err
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -189,6 +190,18 @@ func (n *reparentDatabaseNode) startExec(params runParams) error {
 					return errors.Wrapf(err, errStr, n.db.Name, tbl.Name)
 				}
 				for _, ref := range tbl.GetDependedOnBy() {
+					fnDesc, err := p.getDependentFunction(ctx, ref.ID)
+					if err != nil {
+						return errors.Wrapf(err, errStr, n.db.Name, tblName.String())
+					}
+					if fnDesc != nil {
+						fqName, err := p.getQualifiedFunctionName(ctx, fnDesc)
+						if err != nil {
+							return errors.Wrapf(err, errStr, n.db.Name, fnDesc.Name)
+						}
+						names = append(names, fqName.String())
+						continue
+					}
 					dep, err := p.Descriptors().GetMutableTableVersionByID(ctx, ref.ID, p.txn)
 					if err != nil {
 						return errors.Wrapf(err, errStr, n.db.Name, tblName.String())
@@ -225,7 +238,7 @@ func (n *reparentDatabaseNode) startExec(params runParams) error {
 				ctx,
 				tree.ObjectLookupFlags{
 					CommonLookupFlags: tree.CommonLookupFlags{
-						Required:       true,
+						Required:       false,
 						RequireMutable: true,
 						IncludeOffline: true,
 					},
@@ -238,8 +251,75 @@ func (n *reparentDatabaseNode) startExec(params runParams) error {
 			if err != nil {
 				return err
 			}
-			// If we couldn't find the object at all, then continue.
 			if !found {
+				// If we couldn't resolve objName as a type either, try a function.
+				// Functions which refer to relations in this database were rejected
+				// above along with the relations, so the remaining functions do not
+				// refer to the name of the database. Views refer to the functions
+				// they call by their fully qualified names, though.
+				found, desc, err := p.LookupObject(
+					ctx,
+					tree.ObjectLookupFlags{
+						CommonLookupFlags: tree.CommonLookupFlags{
+							Required:       true,
+							RequireMutable: true,
+							IncludeOffline: true,
+						},
+						DesiredObjectKind: tree.FunctionObject,
+					},
+					objName.Catalog(),
+					objName.Schema(),
+					objName.Object(),
+				)
+				if err != nil {
+					return err
+				}
+				// If we couldn't find the object at all, then continue.
+				if !found {
+					continue
+				}
+				// Remap the ID's on the function.
+				fn, ok := desc.(*funcdesc.Mutable)
+				if !ok {
+					return errors.AssertionFailedf("%q was not a Mutable", objName.Object())
+				}
+				if len(fn.ReferencingDescriptorIDs) > 0 {
+					var names []string
+					const errStr = "cannot convert database %q into schema because %q has dependent objects"
+					fnName, err := p.getQualifiedFunctionName(ctx, fn)
+					if err != nil {
+						return errors.Wrapf(err, errStr, n.db.Name, fn.Name)
+					}
+					for _, id := range fn.ReferencingDescriptorIDs {
+						dep, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+						if err != nil {
+							return errors.Wrapf(err, errStr, n.db.Name, fnName.String())
+						}
+						fqName, err := p.getQualifiedTableName(ctx, dep)
+						if err != nil {
+							return errors.Wrapf(err, errStr, n.db.Name, dep.Name)
+						}
+						names = append(names, fqName.String())
+					}
+					return sqlerrors.NewDependentObjectErrorf(
+						"could not convert database %q into schema because %q has dependent objects %v",
+						n.db.Name,
+						fnName.String(),
+						names,
+					)
+				}
+				fn.DrainingNames = append(fn.DrainingNames, descpb.NameInfo{
+					ParentID:       fn.ParentID,
+					ParentSchemaID: fn.ParentSchemaID,
+					Name:           fn.Name,
+				})
+				fn.ParentID = n.newParent.ID
+				fn.ParentSchemaID = schema.ID
+				objKey := catalogkv.MakeObjectNameKey(ctx, p.ExecCfg().Settings, fn.ParentID, fn.ParentSchemaID, fn.Name).Key(codec)
+				b.CPut(objKey, fn.ID, nil /* expected */)
+				if err := p.writeFunctionDescChange(ctx, fn, tree.AsStringWithFQNames(n.n, params.Ann())); err != nil {
+					return err
+				}
 				continue
 			}
 			// Remap the ID's on the type.
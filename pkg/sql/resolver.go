@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -647,6 +648,7 @@ type internalLookupCtx struct {
 	tbIDs       []descpb.ID
 	typDescs    map[descpb.ID]*typedesc.Immutable
 	typIDs      []descpb.ID
+	fnDescs     map[descpb.ID]*funcdesc.Immutable
 
 	// fallback is utilized in GetDesc
 	fallback catalog.DescGetter
@@ -665,6 +667,9 @@ func (l *internalLookupCtx) GetDesc(ctx context.Context, id descpb.ID) (catalog.
 	if desc, ok := l.tbDescs[id]; ok {
 		return desc, nil
 	}
+	if desc, ok := l.fnDescs[id]; ok {
+		return desc, nil
+	}
 	if l.fallback != nil {
 		return l.fallback.GetDesc(ctx, id)
 	}
@@ -708,6 +713,8 @@ func newInternalLookupCtxFromDescriptors(
 			descriptors[i] = typedesc.NewImmutable(*t.Type)
 		case *descpb.Descriptor_Schema:
 			descriptors[i] = schemadesc.NewImmutable(*t.Schema)
+		case *descpb.Descriptor_Function:
+			descriptors[i] = funcdesc.NewImmutable(*t.Function)
 		}
 	}
 	lCtx := newInternalLookupCtx(ctx, descriptors, prefix, nil /* fallback */)
@@ -733,6 +740,7 @@ func newInternalLookupCtx(
 	}
 	tbDescs := make(map[descpb.ID]*tabledesc.Immutable)
 	typDescs := make(map[descpb.ID]*typedesc.Immutable)
+	fnDescs := make(map[descpb.ID]*funcdesc.Immutable)
 	var tbIDs, typIDs, dbIDs, schemaIDs []descpb.ID
 	// Record descriptors for name lookups.
 	for i := range descs {
@@ -756,6 +764,8 @@ func newInternalLookupCtx(
 				// Only make the type visible for iteration if the prefix was included.
 				typIDs = append(typIDs, desc.GetID())
 			}
+		case *funcdesc.Immutable:
+			fnDescs[desc.GetID()] = desc
 		case *schemadesc.Immutable:
 			schemaDescs[desc.GetID()] = desc
 			if prefix == nil || prefix.GetID() == desc.ParentID {
@@ -777,6 +787,7 @@ func newInternalLookupCtx(
 		tbIDs:       tbIDs,
 		dbIDs:       dbIDs,
 		typIDs:      typIDs,
+		fnDescs:     fnDescs,
 		fallback:    fallback,
 	}
 }
@@ -791,6 +802,14 @@ func (l *internalLookupCtx) getDatabaseByID(id descpb.ID) (*dbdesc.Immutable, er
 	return db, nil
 }
 
+// isFunction returns whether the descriptor with the given ID is a
+// user-defined function. The DependedOnBy back-references of a relation refer
+// to both views and functions.
+func (l *internalLookupCtx) isFunction(id descpb.ID) bool {
+	_, ok := l.fnDescs[id]
+	return ok
+}
+
 func (l *internalLookupCtx) getTableByID(id descpb.ID) (catalog.TableDescriptor, error) {
 	tb, ok := l.tbDescs[id]
 	if !ok {
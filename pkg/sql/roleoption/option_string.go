@@ -28,11 +28,13 @@ func _() {
 	_ = x[NOCANCELQUERY-18]
 	_ = x[MODIFYCLUSTERSETTING-19]
 	_ = x[NOMODIFYCLUSTERSETTING-20]
+	_ = x[BYPASSRLS-21]
+	_ = x[NOBYPASSRLS-22]
 }
 
-const _Option_name = "CREATEROLENOCREATEROLEPASSWORDLOGINNOLOGINVALIDUNTILCONTROLJOBNOCONTROLJOBCONTROLCHANGEFEEDNOCONTROLCHANGEFEEDCREATEDBNOCREATEDBCREATELOGINNOCREATELOGINVIEWACTIVITYNOVIEWACTIVITYCANCELQUERYNOCANCELQUERYMODIFYCLUSTERSETTINGNOMODIFYCLUSTERSETTING"
+const _Option_name = "CREATEROLENOCREATEROLEPASSWORDLOGINNOLOGINVALIDUNTILCONTROLJOBNOCONTROLJOBCONTROLCHANGEFEEDNOCONTROLCHANGEFEEDCREATEDBNOCREATEDBCREATELOGINNOCREATELOGINVIEWACTIVITYNOVIEWACTIVITYCANCELQUERYNOCANCELQUERYMODIFYCLUSTERSETTINGNOMODIFYCLUSTERSETTINGBYPASSRLSNOBYPASSRLS"
 
-var _Option_index = [...]uint8{0, 10, 22, 30, 35, 42, 52, 62, 74, 91, 110, 118, 128, 139, 152, 164, 178, 189, 202, 222, 244}
+var _Option_index = [...]uint16{0, 10, 22, 30, 35, 42, 52, 62, 74, 91, 110, 118, 128, 139, 152, 164, 178, 189, 202, 222, 244, 253, 264}
 
 func (i Option) String() string {
 	i -= 1
@@ -55,6 +55,8 @@ const (
 	NOCANCELQUERY
 	MODIFYCLUSTERSETTING
 	NOMODIFYCLUSTERSETTING
+	BYPASSRLS
+	NOBYPASSRLS
 )
 
 // toSQLStmts is a map of Kind -> SQL statement string for applying the
@@ -79,6 +81,8 @@ var toSQLStmts = map[Option]string{
 	NOCANCELQUERY:          `DELETE FROM system.role_options WHERE username = $1 AND option = 'CANCELQUERY'`,
 	MODIFYCLUSTERSETTING:   `UPSERT INTO system.role_options (username, option) VALUES ($1, 'MODIFYCLUSTERSETTING')`,
 	NOMODIFYCLUSTERSETTING: `DELETE FROM system.role_options WHERE username = $1 AND option = 'MODIFYCLUSTERSETTING'`,
+	BYPASSRLS:              `UPSERT INTO system.role_options (username, option) VALUES ($1, 'BYPASSRLS')`,
+	NOBYPASSRLS:            `DELETE FROM system.role_options WHERE username = $1 AND option = 'BYPASSRLS'`,
 }
 
 // Mask returns the bitmask for a given role option.
@@ -108,8 +112,17 @@ var ByName = map[string]Option{
 	"NOCANCELQUERY":          NOCANCELQUERY,
 	"MODIFYCLUSTERSETTING":   MODIFYCLUSTERSETTING,
 	"NOMODIFYCLUSTERSETTING": NOMODIFYCLUSTERSETTING,
+	"BYPASSRLS":              BYPASSRLS,
+	"NOBYPASSRLS":            NOBYPASSRLS,
 }
 
+// LDAPGroupRoles is not a role option, but the name of the row of
+// system.role_options under which the LDAP authentication method records the
+// roles it granted to a user, as a comma-separated list. Neither the grammar
+// nor ToOption accept it, so it cannot be set with CREATE or ALTER ROLE, and
+// SHOW ROLES omits it.
+const LDAPGroupRoles = "LDAPGROUPROLES"
+
 // ToOption takes a string and returns the corresponding Option.
 func ToOption(str string) (Option, error) {
 	ret := ByName[strings.ToUpper(str)]
@@ -210,7 +223,9 @@ func (rol List) CheckRoleOptionConflicts() error {
 		(roleOptionBits&CANCELQUERY.Mask() != 0 &&
 			roleOptionBits&NOCANCELQUERY.Mask() != 0) ||
 		(roleOptionBits&MODIFYCLUSTERSETTING.Mask() != 0 &&
-			roleOptionBits&NOMODIFYCLUSTERSETTING.Mask() != 0) {
+			roleOptionBits&NOMODIFYCLUSTERSETTING.Mask() != 0) ||
+		(roleOptionBits&BYPASSRLS.Mask() != 0 &&
+			roleOptionBits&NOBYPASSRLS.Mask() != 0) {
 		return pgerror.Newf(pgcode.Syntax, "conflicting role options")
 	}
 	return nil
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/unique"
 	"github.com/cockroachdb/errors"
 )
@@ -781,12 +782,46 @@ func EncodeInvertedIndexKeys(
 	} else {
 		val = tree.DNull
 	}
+	if index.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+		return EncodeTrigramInvertedIndexTableKeys(val, keyPrefix)
+	}
 	if !geoindex.IsEmptyConfig(&index.GeoConfig) {
 		return EncodeGeoInvertedIndexTableKeys(val, keyPrefix, index)
 	}
 	return EncodeInvertedIndexTableKeys(val, keyPrefix, index.Version)
 }
 
+// EncodeTrigramInvertedIndexTableKeys produces one inverted index key per
+// trigram of the input datum, which should be a string. Each output key is
+// prefixed by inKey. No key is produced for NULL or for a string without any
+// trigram, such as the empty string.
+func EncodeTrigramInvertedIndexTableKeys(val tree.Datum, inKey []byte) (key [][]byte, err error) {
+	if val == tree.DNull {
+		return nil, nil
+	}
+	s, ok := tree.AsDString(val)
+	if !ok {
+		return nil, errors.AssertionFailedf(
+			"trying to apply trigram inverted index to unsupported type %s", val.ResolvedType())
+	}
+	trigrams := trigram.MakeTrigrams(string(s), true /* pad */)
+	outKeys := make([][]byte, len(trigrams))
+	for i := range trigrams {
+		outKeys[i] = EncodeTrigramInvertedIndexKey(inKey, trigrams[i])
+	}
+	return outKeys, nil
+}
+
+// EncodeTrigramInvertedIndexKey returns the inverted index key of the given
+// trigram, prefixed by inKey.
+func EncodeTrigramInvertedIndexKey(inKey []byte, t string) []byte {
+	// Make sure that the prefix is copied, since it is shared by all the keys
+	// of a value.
+	outKey := make([]byte, len(inKey), len(inKey)+len(t)+2)
+	copy(outKey, inKey)
+	return encoding.EncodeStringAscending(outKey, t)
+}
+
 // EncodeInvertedIndexTableKeys produces one inverted index key per element in
 // the input datum, which should be a container (either JSON or Array). For
 // JSON, "element" means unique path through the document. Each output key is
@@ -67,6 +67,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/unaccent"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -3007,9 +3008,56 @@ may increase either contention or retry errors, or both.`,
 	"dmetaphone_alt":         makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 56820, Category: categoryFuzzyStringMatching}),
 
 	// Trigram functions.
-	"similarity":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"show_trgm":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
-	"word_similarity":        makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
+	// See https://www.postgresql.org/docs/current/pgtrgm.html.
+	"similarity": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				l, r := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+				return tree.NewDFloat(tree.DFloat(trigram.Similarity(l, r))), nil
+			},
+			Info: "Returns a number that indicates how similar the two arguments are, from 0 " +
+				"(completely dissimilar) to 1 (identical): the number of trigrams they share " +
+				"divided by the number of distinct trigrams of the two arguments.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"show_trgm": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				trigrams := trigram.MakeTrigrams(string(tree.MustBeDString(args[0])), true /* pad */)
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigrams {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info:       "Returns the sorted array of the trigrams of the input.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+	"word_similarity": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+			ReturnType: tree.FixedReturnType(types.Float),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				l, r := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+				return tree.NewDFloat(tree.DFloat(trigram.WordSimilarity(l, r))), nil
+			},
+			Info: "Returns a number that indicates the greatest similarity between the set of " +
+				"trigrams of the first argument and any continuous extent of the ordered set of " +
+				"trigrams of the second argument.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
 	"strict_word_similarity": makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 	"show_limit":             makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
 	"set_limit":              makeBuiltin(tree.FunctionProperties{UnsupportedWithIssue: 41285, Category: categoryTrigram}),
@@ -4003,6 +4051,44 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	// Return the request rate of a range by request locality.
+	"crdb_internal.range_locality_stats": makeBuiltin(
+		tree.FunctionProperties{
+			Category: categorySystemInfo,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"key", types.Bytes},
+			},
+			ReturnType: tree.FixedReturnType(types.Jsonb),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				key := []byte(tree.MustBeDBytes(args[0]))
+				b := &kv.Batch{}
+				b.AddRawRequest(&roachpb.RangeStatsRequest{
+					RequestHeader: roachpb.RequestHeader{
+						Key: key,
+					},
+				})
+				if err := ctx.Txn.Run(ctx.Context, b); err != nil {
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue, "message: %s", err)
+				}
+				resp := b.RawResponse().Responses[0].GetInner().(*roachpb.RangeStatsResponse)
+				builder := json.NewObjectBuilder(len(resp.LocalityQueriesPerSecond))
+				for _, l := range resp.LocalityQueriesPerSecond {
+					qps, err := json.FromFloat64(l.QueriesPerSecond)
+					if err != nil {
+						return nil, err
+					}
+					builder.Add(l.Locality, qps)
+				}
+				return tree.NewDJSON(builder.Build()), nil
+			},
+			Info: "This function is used to retrieve the request rate of a range at its " +
+				"leaseholder, by the locality the requests were sent from, as a JSON object.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	// Returns a namespace_id based on parentID and a given name.
 	// Allows a non-admin to query the system.namespace table, but performs
 	// the relevant permission checks to ensure secure access.
@@ -4238,6 +4324,20 @@ may increase either contention or retry errors, or both.`,
 			},
 			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
 			Volatility: tree.VolatilityStable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"val", types.String},
+				{"version", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// Strings can only be indexed by trigram inverted indexes, whose
+				// entries do not depend on the version.
+				return trigramNumInvertedIndexEntries(ctx, args[0])
+			},
+			Info:       "This function is used only by CockroachDB's developers for testing purposes.",
+			Volatility: tree.VolatilityStable,
 		}),
 
 	// Returns true iff the current user has admin role.
@@ -4630,6 +4730,43 @@ may increase either contention or retry errors, or both.`,
 		},
 	),
 
+	"crdb_internal.update_tenant_resource_limits": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categoryMultiTenancy,
+			Undocumented: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"tenant_id", types.Int},
+				{"rate_limit", types.Float},
+				{"burst_limit", types.Float},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				sTenID := int64(tree.MustBeDInt(args[0]))
+				if sTenID <= 0 {
+					return nil, pgerror.New(pgcode.InvalidParameterValue, "tenant ID must be positive")
+				}
+				rateLimit := float64(tree.MustBeDFloat(args[1]))
+				burstLimit := float64(tree.MustBeDFloat(args[2]))
+				if !(rateLimit > 0) || !(burstLimit > 0) {
+					return nil, pgerror.New(pgcode.InvalidParameterValue,
+						"rate and burst limits must be positive")
+				}
+				if err := ctx.Tenant.UpdateTenantResourceLimits(
+					ctx.Context, uint64(sTenID), rateLimit, burstLimit,
+				); err != nil {
+					return nil, err
+				}
+				return args[0], nil
+			},
+			Info: "Sets the rate limit, in request units per second, and the burst limit, " +
+				"in request units, of the KV requests of the tenant with the provided ID. " +
+				"Must be run by the System tenant.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	"crdb_internal.compact_engine_span": makeBuiltin(
 		tree.FunctionProperties{
 			Category:         categorySystemRepair,
@@ -4713,6 +4850,27 @@ may increase either contention or retry errors, or both.`,
 			Volatility: tree.VolatilityImmutable,
 		},
 	),
+
+	// grouping is replaced by the optimizer when it builds the aggregation
+	// of a query, so this overload is never evaluated.
+	"grouping": makeBuiltin(
+		tree.FunctionProperties{
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.VariadicType{
+				VarType: types.Any,
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return nil, errors.AssertionFailedf("grouping() should have been replaced during planning")
+			},
+			Info: "Returns a bit mask indicating which of its arguments are not part of the " +
+				"grouping set of the current row. The last argument corresponds to the least " +
+				"significant bit. The arguments must be grouping expressions of the query.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
 }
 
 var lengthImpls = func(incBitOverload bool) builtinDefinition {
@@ -6814,6 +6972,14 @@ func jsonNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datu
 	return tree.NewDInt(tree.DInt(n)), nil
 }
 
+func trigramNumInvertedIndexEntries(_ *tree.EvalContext, val tree.Datum) (tree.Datum, error) {
+	if val == tree.DNull {
+		return tree.DZero, nil
+	}
+	trigrams := trigram.MakeTrigrams(string(tree.MustBeDString(val)), true /* pad */)
+	return tree.NewDInt(tree.DInt(len(trigrams))), nil
+}
+
 func arrayNumInvertedIndexEntries(
 	ctx *tree.EvalContext, val, version tree.Datum,
 ) (tree.Datum, error) {
@@ -77,6 +77,7 @@ func (*AlterTableValidateConstraint) alterTableCmd() {}
 func (*AlterTablePartitionBy) alterTableCmd()        {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
 func (*AlterTableOwner) alterTableCmd()              {}
+func (*AlterTableRowLevelSecurity) alterTableCmd()   {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -94,6 +95,7 @@ var _ AlterTableCmd = &AlterTableValidateConstraint{}
 var _ AlterTableCmd = &AlterTablePartitionBy{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
 var _ AlterTableCmd = &AlterTableOwner{}
+var _ AlterTableCmd = &AlterTableRowLevelSecurity{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -535,6 +537,52 @@ func (node *AlterTableSetAudit) Format(ctx *FmtCtx) {
 	ctx.WriteString(node.Mode.String())
 }
 
+// RowLevelSecurityMode represents a change to the row-level security of a
+// table.
+type RowLevelSecurityMode int
+
+const (
+	// RowLevelSecurityEnable enforces the policies of the table.
+	RowLevelSecurityEnable RowLevelSecurityMode = iota
+	// RowLevelSecurityDisable stops enforcing the policies of the table.
+	RowLevelSecurityDisable
+	// RowLevelSecurityForce enforces the policies of the table for its owner
+	// too.
+	RowLevelSecurityForce
+	// RowLevelSecurityNoForce exempts the owner of the table from its
+	// policies.
+	RowLevelSecurityNoForce
+)
+
+var rowLevelSecurityModeName = [...]string{
+	RowLevelSecurityEnable:  "ENABLE",
+	RowLevelSecurityDisable: "DISABLE",
+	RowLevelSecurityForce:   "FORCE",
+	RowLevelSecurityNoForce: "NO FORCE",
+}
+
+func (m RowLevelSecurityMode) String() string {
+	return rowLevelSecurityModeName[m]
+}
+
+// AlterTableRowLevelSecurity represents an ALTER TABLE {ENABLE | DISABLE |
+// FORCE | NO FORCE} ROW LEVEL SECURITY command.
+type AlterTableRowLevelSecurity struct {
+	Mode RowLevelSecurityMode
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableRowLevelSecurity) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "row_level_security")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableRowLevelSecurity) Format(ctx *FmtCtx) {
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.Mode.String())
+	ctx.WriteString(" ROW LEVEL SECURITY")
+}
+
 // AlterTableInjectStats represents an ALTER TABLE INJECT STATISTICS statement.
 type AlterTableInjectStats struct {
 	Stats Expr
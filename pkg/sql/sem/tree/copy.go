@@ -20,10 +20,22 @@ type CopyFrom struct {
 	Options CopyOptions
 }
 
+// CopyTo represents a COPY TO statement. Exactly one of Table and Statement
+// is set.
+type CopyTo struct {
+	Table     TableName
+	Columns   NameList
+	Statement Statement
+	Options   CopyOptions
+}
+
 // CopyOptions describes options for COPY execution.
 type CopyOptions struct {
 	Destination Expr
 	CopyFormat  CopyFormat
+	Delimiter   Expr
+	Null        Expr
+	Header      bool
 }
 
 var _ NodeFormatter = &CopyOptions{}
@@ -47,6 +59,28 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 	}
 }
 
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(node.Statement)
+		ctx.WriteString(")")
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
 // Format implements the NodeFormatter interface
 func (o *CopyOptions) Format(ctx *FmtCtx) {
 	var addSep bool
@@ -61,8 +95,25 @@ func (o *CopyOptions) Format(ctx *FmtCtx) {
 		case CopyFormatBinary:
 			ctx.WriteString("BINARY")
 			addSep = true
+		case CopyFormatCSV:
+			ctx.WriteString("CSV")
+			addSep = true
 		}
 	}
+	if o.Header {
+		maybeAddSep()
+		ctx.WriteString("HEADER")
+	}
+	if o.Delimiter != nil {
+		maybeAddSep()
+		ctx.WriteString("DELIMITER ")
+		ctx.FormatNode(o.Delimiter)
+	}
+	if o.Null != nil {
+		maybeAddSep()
+		ctx.WriteString("NULL ")
+		ctx.FormatNode(o.Null)
+	}
 	if o.Destination != nil {
 		maybeAddSep()
 		// Lowercase because that's what has historically been produced
@@ -93,6 +144,24 @@ func (o *CopyOptions) CombineWith(other *CopyOptions) error {
 		}
 		o.CopyFormat = other.CopyFormat
 	}
+	if other.Delimiter != nil {
+		if o.Delimiter != nil {
+			return errors.New("delimiter option specified multiple times")
+		}
+		o.Delimiter = other.Delimiter
+	}
+	if other.Null != nil {
+		if o.Null != nil {
+			return errors.New("null option specified multiple times")
+		}
+		o.Null = other.Null
+	}
+	if other.Header {
+		if o.Header {
+			return errors.New("header option specified multiple times")
+		}
+		o.Header = true
+	}
 	return nil
 }
 
@@ -103,4 +172,5 @@ type CopyFormat int
 const (
 	CopyFormatText CopyFormat = iota
 	CopyFormatBinary
+	CopyFormatCSV
 )
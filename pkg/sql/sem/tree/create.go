@@ -99,6 +99,9 @@ type IndexElem struct {
 	Expr       Expr
 	Direction  Direction
 	NullsOrder NullsOrder
+	// OpClass is set if an operator class was specified for the element. Only
+	// the trigram operator classes of inverted indexes are supported.
+	OpClass Name
 }
 
 // Format implements the NodeFormatter interface.
@@ -117,6 +120,10 @@ func (node *IndexElem) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 	}
+	if node.OpClass != "" {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.OpClass)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -139,6 +146,9 @@ func (node *IndexElem) doc(p *PrettyCfg) pretty.Doc {
 			d = p.bracket("(", d, ")")
 		}
 	}
+	if node.OpClass != "" {
+		d = pretty.ConcatSpace(d, p.Doc(&node.OpClass))
+	}
 	if node.Direction != DefaultDirection {
 		d = pretty.ConcatSpace(d, pretty.Keyword(node.Direction.String()))
 	}
@@ -335,6 +345,338 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	FuncName   *UnresolvedObjectName
+	Replace    bool
+	Params     FuncParams
+	ReturnType ResolvableTypeReference
+	Options    FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Params)
+	ctx.WriteString(") RETURNS ")
+	ctx.FormatTypeReference(node.ReturnType)
+	if !node.Options.Empty() {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(&node.Options)
+	}
+}
+
+// FuncParam represents a single parameter in a CREATE FUNCTION statement.
+type FuncParam struct {
+	// Name is empty if the parameter is unnamed.
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParam) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncParams represents the parameter list of a CREATE FUNCTION statement.
+type FuncParams []FuncParam
+
+// Format implements the NodeFormatter interface.
+func (node *FuncParams) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionNullInputBehavior represents the behavior of a user-defined
+// function when it is called with a NULL argument.
+type FunctionNullInputBehavior int
+
+const (
+	// FunctionNullInputUnspecified indicates that the behavior was not
+	// specified; it defaults to FunctionCalledOnNullInput.
+	FunctionNullInputUnspecified FunctionNullInputBehavior = iota
+	// FunctionCalledOnNullInput indicates that the function is evaluated even
+	// if some of its arguments are NULL.
+	FunctionCalledOnNullInput
+	// FunctionReturnsNullOnNullInput indicates that the function returns NULL
+	// without being evaluated if any of its arguments is NULL.
+	FunctionReturnsNullOnNullInput
+)
+
+// FunctionOptions represents the options of a CREATE FUNCTION statement.
+type FunctionOptions struct {
+	// Volatility is zero if it was not specified.
+	Volatility        Volatility
+	NullInputBehavior FunctionNullInputBehavior
+	// Language is empty if it was not specified.
+	Language string
+	// Body is nil if no AS clause was specified.
+	Body *string
+}
+
+// Empty returns true if no options were provided.
+func (o *FunctionOptions) Empty() bool {
+	return o.Volatility == 0 && o.NullInputBehavior == FunctionNullInputUnspecified &&
+		o.Language == "" && o.Body == nil
+}
+
+// Format implements the NodeFormatter interface.
+func (o *FunctionOptions) Format(ctx *FmtCtx) {
+	sep := ""
+	if o.Language != "" {
+		ctx.WriteString("LANGUAGE ")
+		ctx.WriteString(o.Language)
+		sep = " "
+	}
+	if o.Volatility != 0 {
+		ctx.WriteString(sep)
+		switch o.Volatility {
+		case VolatilityImmutable:
+			ctx.WriteString("IMMUTABLE")
+		case VolatilityStable:
+			ctx.WriteString("STABLE")
+		default:
+			ctx.WriteString("VOLATILE")
+		}
+		sep = " "
+	}
+	switch o.NullInputBehavior {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString(sep)
+		ctx.WriteString("CALLED ON NULL INPUT")
+		sep = " "
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString(sep)
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+		sep = " "
+	}
+	if o.Body != nil {
+		ctx.WriteString(sep)
+		ctx.WriteString("AS ")
+		if ctx.flags.HasFlags(FmtAnonymize) {
+			ctx.WriteByte('_')
+		} else {
+			lex.EncodeSQLString(&ctx.Buffer, *o.Body)
+		}
+	}
+}
+
+// CombineWith combines two options, erroring out if the two options contain
+// incompatible settings.
+func (o *FunctionOptions) CombineWith(other *FunctionOptions) error {
+	if other.Volatility != 0 {
+		if o.Volatility != 0 {
+			return errors.New("conflicting or redundant options: volatility specified multiple times")
+		}
+		o.Volatility = other.Volatility
+	}
+	if other.NullInputBehavior != FunctionNullInputUnspecified {
+		if o.NullInputBehavior != FunctionNullInputUnspecified {
+			return errors.New("conflicting or redundant options: null input behavior specified multiple times")
+		}
+		o.NullInputBehavior = other.NullInputBehavior
+	}
+	if other.Language != "" {
+		if o.Language != "" {
+			return errors.New("conflicting or redundant options: LANGUAGE specified multiple times")
+		}
+		o.Language = other.Language
+	}
+	if other.Body != nil {
+		if o.Body != nil {
+			return errors.New("conflicting or redundant options: AS specified multiple times")
+		}
+		o.Body = other.Body
+	}
+	return nil
+}
+
+// TriggerActionTime represents when a trigger fires relative to the
+// statement or row that activates it.
+type TriggerActionTime int
+
+const (
+	// TriggerBefore indicates that the trigger fires before the mutation.
+	TriggerBefore TriggerActionTime = iota
+	// TriggerAfter indicates that the trigger fires after the mutation.
+	TriggerAfter
+)
+
+// String implements the fmt.Stringer interface.
+func (t TriggerActionTime) String() string {
+	if t == TriggerBefore {
+		return "BEFORE"
+	}
+	return "AFTER"
+}
+
+// TriggerEvent represents a kind of mutation that activates a trigger.
+type TriggerEvent int
+
+const (
+	// TriggerEventInsert activates a trigger on INSERT.
+	TriggerEventInsert TriggerEvent = iota
+	// TriggerEventUpdate activates a trigger on UPDATE.
+	TriggerEventUpdate
+	// TriggerEventDelete activates a trigger on DELETE.
+	TriggerEventDelete
+)
+
+// String implements the fmt.Stringer interface.
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerEventInsert:
+		return "INSERT"
+	case TriggerEventUpdate:
+		return "UPDATE"
+	default:
+		return "DELETE"
+	}
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement.
+type CreateTrigger struct {
+	Name       Name
+	Constraint bool
+	ActionTime TriggerActionTime
+	Events     []TriggerEvent
+	Table      TableName
+	ForEachRow bool
+	// Action is the SQL statement executed when the trigger fires.
+	Action string
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Constraint {
+		ctx.WriteString("CONSTRAINT ")
+	}
+	ctx.WriteString("TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	for i, e := range node.Events {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.WriteString(e.String())
+	}
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.ForEachRow {
+		ctx.WriteString(" FOR EACH ROW")
+	} else {
+		ctx.WriteString(" FOR EACH STATEMENT")
+	}
+	ctx.WriteString(" AS ")
+	if ctx.flags.HasFlags(FmtAnonymize) {
+		ctx.WriteByte('_')
+	} else {
+		lex.EncodeSQLString(&ctx.Buffer, node.Action)
+	}
+}
+
+// PolicyCommand represents the command to which a row-level security policy
+// applies.
+type PolicyCommand int
+
+const (
+	// PolicyCommandAll applies a policy to all commands.
+	PolicyCommandAll PolicyCommand = iota
+	// PolicyCommandSelect applies a policy to SELECT.
+	PolicyCommandSelect
+	// PolicyCommandInsert applies a policy to INSERT.
+	PolicyCommandInsert
+	// PolicyCommandUpdate applies a policy to UPDATE.
+	PolicyCommandUpdate
+	// PolicyCommandDelete applies a policy to DELETE.
+	PolicyCommandDelete
+)
+
+var policyCommandName = [...]string{
+	PolicyCommandAll:    "ALL",
+	PolicyCommandSelect: "SELECT",
+	PolicyCommandInsert: "INSERT",
+	PolicyCommandUpdate: "UPDATE",
+	PolicyCommandDelete: "DELETE",
+}
+
+// String implements the fmt.Stringer interface.
+func (c PolicyCommand) String() string {
+	return policyCommandName[c]
+}
+
+// CreatePolicy represents a CREATE POLICY statement.
+type CreatePolicy struct {
+	Name        Name
+	Table       TableName
+	Restrictive bool
+	Command     PolicyCommand
+	// Roles are the roles to which the policy applies. The policy applies to
+	// all roles if Roles is empty.
+	Roles []security.SQLUsername
+	// Using is the expression which filters the existing rows, or nil.
+	Using Expr
+	// WithCheck is the expression which the new rows must satisfy, or nil.
+	WithCheck Expr
+}
+
+var _ Statement = &CreatePolicy{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE POLICY ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.Restrictive {
+		ctx.WriteString(" AS RESTRICTIVE")
+	}
+	if node.Command != PolicyCommandAll {
+		ctx.WriteString(" FOR ")
+		ctx.WriteString(node.Command.String())
+	}
+	if len(node.Roles) > 0 {
+		ctx.WriteString(" TO ")
+		for i := range node.Roles {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatUsername(node.Roles[i])
+		}
+	}
+	if node.Using != nil {
+		ctx.WriteString(" USING (")
+		ctx.FormatNode(node.Using)
+		ctx.WriteByte(')')
+	}
+	if node.WithCheck != nil {
+		ctx.WriteString(" WITH CHECK (")
+		ctx.FormatNode(node.WithCheck)
+		ctx.WriteByte(')')
+	}
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -394,6 +736,7 @@ type ColumnTableDef struct {
 		IsUnique       bool
 		WithoutIndex   bool
 		ConstraintName Name
+		Deferrability  ConstraintDeferrability
 	}
 	DefaultExpr struct {
 		Expr           Expr
@@ -406,6 +749,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -520,6 +864,7 @@ func NewColumnTableDef(
 			d.Unique.IsUnique = true
 			d.Unique.WithoutIndex = t.WithoutIndex
 			d.Unique.ConstraintName = c.Name
+			d.Unique.Deferrability = t.Deferrability
 		case *ColumnCheckConstraint:
 			d.CheckExprs = append(d.CheckExprs, ColumnTableDefCheckExpr{
 				Expr:           t.Expr,
@@ -535,6 +880,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			d.Computed.Computed = true
 			d.Computed.Expr = t.Expr
@@ -616,6 +962,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			if node.Unique.WithoutIndex {
 				ctx.WriteString(" WITHOUT INDEX")
 			}
+			ctx.FormatNode(&node.Unique.Deferrability)
 		}
 	}
 	if node.HasDefaultExpr() {
@@ -652,6 +999,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(&node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -746,7 +1094,8 @@ type ShardedPrimaryKeyConstraint struct {
 
 // UniqueConstraint represents UNIQUE on a column.
 type UniqueConstraint struct {
-	WithoutIndex bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnCheckConstraint represents either a check on a column.
@@ -756,10 +1105,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -847,8 +1197,9 @@ func (*CheckConstraintTableDef) constraintTableDef()      {}
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -888,6 +1239,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionBy != nil {
 		ctx.FormatNode(node.PartitionBy)
 	}
+	ctx.FormatNode(&node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -938,6 +1290,24 @@ func (node *ReferenceActions) Format(ctx *FmtCtx) {
 	}
 }
 
+// ConstraintDeferrability specifies whether the checks of a constraint can be
+// deferred until the end of the transaction with SET CONSTRAINTS, and whether
+// they are deferred by default.
+type ConstraintDeferrability struct {
+	Deferrable        bool
+	InitiallyDeferred bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if node.Deferrable {
+		ctx.WriteString(" DEFERRABLE")
+		if node.InitiallyDeferred {
+			ctx.WriteString(" INITIALLY DEFERRED")
+		}
+	}
+}
+
 // CompositeKeyMatchMethod is the algorithm use when matching composite keys.
 // See https://github.com/cockroachdb/cockroach/issues/20305 or
 // https://www.postgresql.org/docs/11/sql-createtable.html for details on the
@@ -963,12 +1333,13 @@ func (c CompositeKeyMatchMethod) String() string {
 
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name     Name
-	Table    TableName
-	FromCols NameList
-	ToCols   NameList
-	Actions  ReferenceActions
-	Match    CompositeKeyMatchMethod
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -996,6 +1367,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1339,12 +1711,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -196,6 +196,105 @@ func (node *DropType) Format(ctx *FmtCtx) {
 	}
 }
 
+// FuncObj identifies a function in a DROP FUNCTION command.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	// ParamTypes is nil if no parameter list was specified.
+	ParamTypes []ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.ParamTypes != nil {
+		ctx.WriteByte('(')
+		for i, typ := range node.ParamTypes {
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatTypeReference(typ)
+		}
+		ctx.WriteByte(')')
+	}
+}
+
+// DropFunction represents a DROP FUNCTION command.
+type DropFunction struct {
+	Functions    []FuncObj
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	for i := range node.Functions {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&node.Functions[i])
+	}
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// DropPolicy represents a DROP POLICY command.
+type DropPolicy struct {
+	Name         Name
+	Table        TableName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropPolicy{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPolicy) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP POLICY ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// DropTrigger represents a DROP TRIGGER command.
+type DropTrigger struct {
+	Name         Name
+	Table        TableName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
 // DropSchema represents a DROP SCHEMA command.
 type DropSchema struct {
 	Names        ObjectNamePrefixList
@@ -46,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -1637,6 +1638,21 @@ var BinOps = map[BinaryOperator]binOpOverload{
 			},
 			Volatility: VolatilityImmutable,
 		},
+		// The trigram similarity operator of the pg_trgm extension returns true
+		// if the similarity of the operands is at least the value of the
+		// pg_trgm.similarity_threshold session variable.
+		&BinOp{
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				l, r := string(MustBeDString(left)), string(MustBeDString(right))
+				return MakeDBool(DBool(
+					trigram.Similarity(l, r) >= ctx.SessionData.TrigramSimilarityThreshold,
+				)), nil
+			},
+			Volatility: VolatilityStable,
+		},
 	},
 
 	Concat: {
@@ -3191,6 +3207,13 @@ type TenantOperator interface {
 	// success it also removes the tenant record.
 	// It returns an error if the tenant does not exist.
 	GCTenant(ctx context.Context, tenantID uint64) error
+
+	// UpdateTenantResourceLimits sets the rate and burst limits, in request
+	// units, of the token bucket which rate limits the KV requests of a tenant.
+	// It returns an error if the tenant does not exist.
+	UpdateTenantResourceLimits(
+		ctx context.Context, tenantID uint64, rateLimit float64, burstLimit float64,
+	) error
 }
 
 // EvalContextTestingKnobs contains test knobs.
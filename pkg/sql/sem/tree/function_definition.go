@@ -94,6 +94,11 @@ type FunctionProperties struct {
 	// should take RegClass as the arg type for the sequence name instead of
 	// string, we will add a dependency on all RegClass types used in a view.
 	HasSequenceArguments bool
+
+	// UserDefined is true if the function was defined with CREATE FUNCTION
+	// rather than being a builtin. User-defined functions cannot be resolved
+	// through FunDefs.
+	UserDefined bool
 }
 
 // ShouldDocument returns whether the built-in function should be included in
@@ -155,6 +160,20 @@ func NewFunctionDefinition(
 	}
 }
 
+// NewUserDefinedFunctionDefinition allocates a function definition for a
+// user-defined function with a single overload. Unlike builtins, calls to
+// user-defined functions are not counted by telemetry.
+func NewUserDefinedFunctionDefinition(
+	name string, props *FunctionProperties, def *Overload,
+) *FunctionDefinition {
+	props.UserDefined = true
+	return &FunctionDefinition{
+		Name:               name,
+		Definition:         []overloadImpl{def},
+		FunctionProperties: *props,
+	}
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
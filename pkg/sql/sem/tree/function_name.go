@@ -72,6 +72,19 @@ func WrapFunction(n string) ResolvableFunctionReference {
 	return ResolvableFunctionReference{fd}
 }
 
+// WrapResolvedFunction creates a new ResolvableFunctionReference for a
+// function that has already been resolved and has the given properties.
+// Unlike WrapFunction, it also supports user-defined functions.
+func WrapResolvedFunction(n string, props *FunctionProperties) ResolvableFunctionReference {
+	if props.UserDefined {
+		return ResolvableFunctionReference{&FunctionDefinition{
+			Name:               n,
+			FunctionProperties: *props,
+		}}
+	}
+	return WrapFunction(n)
+}
+
 // FunctionReference is the common interface to UnresolvedName and QualifiedFunctionName.
 type FunctionReference interface {
 	fmt.Stringer
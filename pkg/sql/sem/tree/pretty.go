@@ -1686,6 +1686,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [DEFERRABLE ...]
 	//    [WHERE ...]
 	//
 	// or (no constraint name):
@@ -1694,6 +1695,7 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    [STORING ( ... )]
 	//    [INTERLEAVE ...]
 	//    [PARTITION BY ...]
+	//    [DEFERRABLE ...]
 	//    [WHERE ...]
 	//
 	clauses := make([]pretty.Doc, 0, 5)
@@ -1726,6 +1728,9 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionBy != nil {
 		clauses = append(clauses, p.Doc(node.PartitionBy))
 	}
+	if d := p.Doc(&node.Deferrability); d != pretty.Nil {
+		clauses = append(clauses, d)
+	}
 	if node.Predicate != nil {
 		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
 	}
@@ -1743,6 +1748,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl (...)
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1750,8 +1756,9 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl [(...)]
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
-	clauses := make([]pretty.Doc, 0, 4)
+	clauses := make([]pretty.Doc, 0, 5)
 	title := pretty.ConcatSpace(
 		pretty.Keyword("FOREIGN KEY"),
 		p.bracket("(", p.Doc(&node.FromCols), ")"))
@@ -1776,6 +1783,10 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	if d := p.Doc(&node.Deferrability); d != pretty.Nil {
+		clauses = append(clauses, d)
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
@@ -1878,6 +1889,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if node.Unique.WithoutIndex {
 			pkConstraint = pretty.ConcatSpace(pkConstraint, pretty.Keyword("WITHOUT INDEX"))
 		}
+		if d := p.Doc(&node.Unique.Deferrability); d != pretty.Nil {
+			pkConstraint = pretty.ConcatSpace(pkConstraint, d)
+		}
 	}
 	if pkConstraint != pretty.Nil {
 		clauses = append(clauses, p.maybePrependConstraintName(&node.Unique.ConstraintName, pkConstraint))
@@ -1899,7 +1913,7 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if node.References.Col != "" {
 			fkHead = pretty.ConcatSpace(fkHead, p.bracket("(", p.Doc(&node.References.Col), ")"))
 		}
-		fkDetails := make([]pretty.Doc, 0, 2)
+		fkDetails := make([]pretty.Doc, 0, 3)
 		// We omit MATCH SIMPLE because it is the default.
 		if node.References.Match != MatchSimple {
 			fkDetails = append(fkDetails, pretty.Keyword(node.References.Match.String()))
@@ -1907,6 +1921,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if d := p.Doc(&node.References.Deferrability); d != pretty.Nil {
+			fkDetails = append(fkDetails, d)
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -1978,6 +1995,17 @@ func (node *ReferenceActions) doc(p *PrettyCfg) pretty.Doc {
 	return pretty.Fold(pretty.ConcatSpace, docs...)
 }
 
+func (node *ConstraintDeferrability) doc(p *PrettyCfg) pretty.Doc {
+	if !node.Deferrable {
+		return pretty.Nil
+	}
+	d := pretty.Keyword("DEFERRABLE")
+	if node.InitiallyDeferred {
+		d = pretty.ConcatSpace(d, pretty.Keyword("INITIALLY DEFERRED"))
+	}
+	return d
+}
+
 func (node *Backup) doc(p *PrettyCfg) pretty.Doc {
 	items := make([]pretty.TableRow, 0, 6)
 
@@ -570,6 +570,46 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType represents the type of a GroupingSet.
+type GroupingSetType int
+
+// The values of GroupingSetType.
+const (
+	// GroupingSets represents GROUPING SETS (...).
+	GroupingSets GroupingSetType = iota
+	// Rollup represents ROLLUP (...).
+	Rollup
+	// Cube represents CUBE (...).
+	Cube
+)
+
+var groupingSetTypeName = [...]string{
+	GroupingSets: "GROUPING SETS",
+	Rollup:       "ROLLUP",
+	Cube:         "CUBE",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a GROUPING SETS, ROLLUP or CUBE item of a GROUP BY
+// clause. The elements of a ROLLUP or CUBE are expressions, each of which may
+// be a tuple denoting several grouping columns. The elements of a GROUPING
+// SETS are themselves GROUP BY items.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
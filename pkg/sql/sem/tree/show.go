@@ -581,6 +581,16 @@ func (node *ShowRangeForRow) Format(ctx *FmtCtx) {
 	ctx.WriteString(")")
 }
 
+// ShowRangeLocalities represents a SHOW RANGE <rangeid> LOCALITIES statement.
+type ShowRangeLocalities struct {
+	RangeID int64
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowRangeLocalities) Format(ctx *FmtCtx) {
+	ctx.Printf("SHOW RANGE %d LOCALITIES", node.RangeID)
+}
+
 // ShowFingerprints represents a SHOW EXPERIMENTAL_FINGERPRINTS statement.
 type ShowFingerprints struct {
 	Table *UnresolvedObjectName
@@ -756,6 +756,7 @@ var (
 	errInvalidMaxUsage     = pgerror.New(pgcode.Syntax, "MAXVALUE can only appear within a range partition expression")
 	errInvalidMinUsage     = pgerror.New(pgcode.Syntax, "MINVALUE can only appear within a range partition expression")
 	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
+	errInvalidGroupingSet  = pgerror.New(pgcode.Syntax, "GROUPING SETS, ROLLUP and CUBE can only appear in a GROUP BY clause")
 )
 
 // NewAggInAggError creates an error for the case when an aggregate function is
@@ -1370,6 +1371,13 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSet
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
@@ -128,6 +128,13 @@ type LocalOnlySessionData struct {
 	// OptimizerFKCascadesLimit is the maximum number of cascading operations that
 	// are run for a single query.
 	OptimizerFKCascadesLimit int
+	// TriggerDepthLimit is the maximum nesting depth of triggers that are run
+	// for a single query.
+	TriggerDepthLimit int
+	// RowSecurityDisabled is true if the row_security session variable is off,
+	// in which case queries which would be filtered by row-level security
+	// policies return an error instead.
+	RowSecurityDisabled bool
 	// ResultsBufferSize specifies the size at which the pgwire results buffer
 	// will self-flush.
 	ResultsBufferSize int64
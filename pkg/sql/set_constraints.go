@@ -0,0 +1,89 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// SetConstraints implements the SET CONSTRAINTS statement, which sets whether
+// the checks of deferrable constraints are deferred until the end of the
+// current transaction.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html for
+// details.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	if p.deferredConstraints == nil || p.EvalContext().TxnImplicit {
+		// As in Postgres, SET CONSTRAINTS has no effect outside of a
+		// transaction block.
+		p.BufferClientNotice(ctx, pgnotice.Newf(
+			"SET CONSTRAINTS can only be used in transaction blocks",
+		))
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	ie := p.extendedEvalCtx.InternalExecutor.(*InternalExecutor)
+	if err := p.checkDeferrableConstraintNames(ctx, ie, n.Names); err != nil {
+		return nil, err
+	}
+	p.deferredConstraints.setMode(n.Names, n.Deferred)
+	if !n.Deferred {
+		// The constraints which become immediate are checked right away for the
+		// keys whose checks were deferred so far.
+		if err := p.deferredConstraints.validate(
+			ctx, ie, p.txn, p.Descriptors(), n.Names,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// checkDeferrableConstraintNames returns an error if one of the given names
+// does not refer to a deferrable constraint in the current database.
+func (p *planner) checkDeferrableConstraintNames(
+	ctx context.Context, ie *InternalExecutor, names tree.NameList,
+) error {
+	if len(names) == 0 {
+		return nil
+	}
+	ie.tcModifier = p.Descriptors()
+	defer func() {
+		ie.tcModifier = nil
+	}()
+	for _, name := range names {
+		row, err := ie.QueryRowEx(
+			ctx, "check-deferrable-constraint", p.txn,
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			`SELECT count(*), count(*) FILTER (WHERE condeferrable)
+			   FROM pg_catalog.pg_constraint WHERE conname = $1`,
+			string(name),
+		)
+		if err != nil {
+			return err
+		}
+		if tree.MustBeDInt(row[0]) == 0 {
+			return pgerror.Newf(pgcode.UndefinedObject,
+				"constraint %q does not exist", string(name))
+		}
+		if tree.MustBeDInt(row[1]) == 0 {
+			return pgerror.Newf(pgcode.WrongObjectType,
+				"constraint %q is not deferrable", string(name))
+		}
+	}
+	return nil
+}
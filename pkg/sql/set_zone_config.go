@@ -83,6 +83,11 @@ var supportedZoneConfigOptions = map[tree.Name]struct {
 		loadYAML(&c.LeasePreferences, string(tree.MustBeDString(d)))
 		c.InheritedLeasePreferences = false
 	}},
+	"lease_placement": {types.String, func(c *zonepb.ZoneConfig, d tree.Datum) {
+		var placement zonepb.LeasePlacement
+		loadYAML(&placement, string(tree.MustBeDString(d)))
+		c.LeasePlacement = &placement
+	}},
 }
 
 // zoneOptionKeys contains the keys from suportedZoneConfigOptions in
@@ -215,12 +215,24 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	formatDeferrability(buf, fk.Deferrable, fk.InitiallyDeferred)
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
 	return nil
 }
 
+// formatDeferrability writes the deferrability clause of a foreign key or
+// unique constraint, if any.
+func formatDeferrability(buf *bytes.Buffer, deferrable, initiallyDeferred bool) {
+	if deferrable {
+		buf.WriteString(" DEFERRABLE")
+		if initiallyDeferred {
+			buf.WriteString(" INITIALLY DEFERRED")
+		}
+	}
+}
+
 // ShowCreateSequence returns a valid SQL representation of the
 // CREATE SEQUENCE statement used to create the given sequence.
 func ShowCreateSequence(
@@ -456,6 +468,7 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		formatDeferrability(&f.Buffer, c.Deferrable, c.InitiallyDeferred)
 		if c.Validity != descpb.ConstraintValidity_Validated {
 			f.WriteString(" NOT VALID")
 		}
@@ -215,6 +215,11 @@ func zoneConfigToSQL(zs *tree.ZoneSpecifier, zone *zonepb.ZoneConfig) (string, e
 	if !zone.InheritedLeasePreferences {
 		writeComma(f, useComma)
 		f.Printf("\tlease_preferences = %s", lex.EscapeSQLString(prefs))
+		useComma = true
+	}
+	if zone.LeasePlacement != nil {
+		writeComma(f, useComma)
+		f.Printf("\tlease_placement = %s", lex.EscapeSQLString(strings.ToLower(zone.LeasePlacement.String())))
 	}
 	return f.String(), nil
 }
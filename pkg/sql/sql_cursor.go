@@ -0,0 +1,789 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// sqlCursor is a SQL cursor created by a DECLARE statement.
+//
+// The query of the cursor is planned when the cursor is declared, but it is
+// only run as its rows are fetched: like the execution of a suspended portal,
+// the execution of the query is paused in between FETCH and MOVE statements.
+// See cursorSource for details.
+//
+// Cursors are forward-only unless they are declared with SCROLL, and keep
+// nothing but the last row returned by the query. Only SCROLL cursors, which
+// can move backwards, and WITH HOLD cursors, whose rows are all buffered when
+// their transaction commits so that they can outlive it, keep the rows returned
+// by the query. They do so in a container which spills to temporary storage
+// once the rows exceed the working memory limit, so that paging through large
+// results does not exhaust the session's memory budget.
+type sqlCursor struct {
+	// statement is the text of the DECLARE statement that created the cursor.
+	statement string
+	created   time.Time
+	hold      bool
+	binary    bool
+	scroll    tree.CursorScrollOption
+
+	cols colinfo.ResultColumns
+
+	// src runs the query of the cursor. It is nil once the transaction in which
+	// the cursor was declared has finished.
+	src *cursorSource
+	// mon is the memory monitor of the query.
+	mon *mon.BytesMonitor
+	// rows contains the rows returned by the query so far. It is only set for
+	// scrollable and WITH HOLD cursors, in which case rowsMon and diskMon are
+	// the memory and disk monitors of the container.
+	rows             *rowcontainer.DiskBackedIndexedRowContainer
+	rowsMon, diskMon *mon.BytesMonitor
+	// scratch is used to encode the rows added to rows.
+	scratch rowenc.EncDatumRow
+	// numRows is the number of rows returned by the query so far, and last is
+	// the last of them.
+	numRows int
+	last    tree.Datums
+
+	// pos is the current position of the cursor. Positions 1 through numRows
+	// refer to rows of the result; position 0 is before the first row and
+	// position numRows+1 is after the last row, once the query has returned all
+	// its rows.
+	pos int
+
+	// committed is set once the transaction in which a WITH HOLD cursor was
+	// declared commits. Only WITH HOLD cursors survive the end of their
+	// transaction.
+	committed bool
+}
+
+// close stops the query of the cursor, if it is still running, and releases
+// the resources of the cursor.
+func (c *sqlCursor) close(ctx context.Context) {
+	if c.src != nil {
+		c.src.stop()
+		c.src = nil
+	}
+	if c.rows != nil {
+		c.rows.Close(ctx)
+		c.rowsMon.Stop(ctx)
+		c.diskMon.Stop(ctx)
+	}
+	c.mon.Stop(ctx)
+}
+
+// finishQuery stops the query of the cursor, if it is still running, and
+// updates the transaction in which the cursor was declared with the reads the
+// query performed.
+func (c *sqlCursor) finishQuery(ctx context.Context) error {
+	if c.src == nil {
+		return nil
+	}
+	err := c.src.finish(ctx)
+	c.src = nil
+	return err
+}
+
+// scrollable returns whether the cursor can be moved backwards. Only cursors
+// declared with SCROLL can be: like Postgres does for plans that cannot be
+// run backwards, cursors declared without a scroll option are forward-only.
+func (c *sqlCursor) scrollable() bool {
+	return c.scroll == tree.Scroll
+}
+
+// pull runs the query of the cursor until it returns its next row. It returns
+// false if the query has already returned all its rows.
+func (c *sqlCursor) pull(ctx context.Context) (bool, error) {
+	if c.src == nil {
+		return false, nil
+	}
+	row, ok, err := c.src.next(ctx)
+	if err != nil || !ok {
+		return false, err
+	}
+	if c.rows != nil {
+		for i, d := range row {
+			c.scratch[i] = rowenc.DatumToEncDatum(c.cols[i].Typ, d)
+		}
+		if err := c.rows.AddRow(ctx, c.scratch); err != nil {
+			return false, err
+		}
+	}
+	c.numRows++
+	c.last = row
+	return true, nil
+}
+
+// pullAll runs the query of the cursor until it has returned all its rows.
+func (c *sqlCursor) pullAll(ctx context.Context) error {
+	for {
+		if ok, err := c.pull(ctx); err != nil || !ok {
+			return err
+		}
+	}
+}
+
+// rowAt returns the row at the given position of the result, running the
+// query further if it has not returned that row yet. It returns false if the
+// result has fewer rows.
+func (c *sqlCursor) rowAt(ctx context.Context, pos int64) (tree.Datums, bool, error) {
+	for int64(c.numRows) < pos {
+		if ok, err := c.pull(ctx); err != nil || !ok {
+			return nil, false, err
+		}
+	}
+	if pos == int64(c.numRows) {
+		return c.last, true, nil
+	}
+	if c.rows == nil {
+		return nil, false, errCursorForwardOnly
+	}
+	row, err := c.rows.GetRow(ctx, int(pos)-1)
+	if err != nil {
+		return nil, false, err
+	}
+	datums, err := row.GetDatums(0, len(c.cols))
+	if err != nil {
+		return nil, false, err
+	}
+	return datums, true, nil
+}
+
+// step moves the cursor by one row in the given direction (1 or -1),
+// returning the row it moves onto, if any.
+func (c *sqlCursor) step(ctx context.Context, dir int) (tree.Datums, bool, error) {
+	if dir < 0 {
+		if c.pos <= 1 {
+			c.pos = 0
+			return nil, false, nil
+		}
+		c.pos--
+		return c.rowAt(ctx, int64(c.pos))
+	}
+	row, ok, err := c.rowAt(ctx, int64(c.pos)+1)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		c.pos = c.numRows + 1
+		return nil, false, nil
+	}
+	c.pos++
+	return row, true, nil
+}
+
+// moveTo moves the cursor to the given position, returning the row at that
+// position if there is one. Positions past the end of the result place the
+// cursor after the last row.
+func (c *sqlCursor) moveTo(ctx context.Context, target int64) (tree.Datums, bool, error) {
+	if target < int64(c.pos) && !c.scrollable() {
+		return nil, false, errCursorForwardOnly
+	}
+	if target < 1 {
+		c.pos = 0
+		return nil, false, nil
+	}
+	row, ok, err := c.rowAt(ctx, target)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		c.pos = c.numRows + 1
+		return nil, false, nil
+	}
+	c.pos = int(target)
+	return row, true, nil
+}
+
+var errCursorForwardOnly = errors.WithHint(
+	pgerror.New(pgcode.ObjectNotInPrerequisiteState, "cursor can only scan forward"),
+	"Declare it with SCROLL option to enable backward scan.",
+)
+
+// cursorMap holds the SQL cursors of a session.
+type cursorMap struct {
+	cursors map[tree.Name]*sqlCursor
+}
+
+func (m *cursorMap) get(name tree.Name) (*sqlCursor, error) {
+	c, ok := m.cursors[name]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	return c, nil
+}
+
+func (m *cursorMap) add(name tree.Name, c *sqlCursor) error {
+	if _, ok := m.cursors[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+	}
+	if m.cursors == nil {
+		m.cursors = make(map[tree.Name]*sqlCursor)
+	}
+	m.cursors[name] = c
+	return nil
+}
+
+// remove closes the cursor with the given name, for a CLOSE statement.
+func (m *cursorMap) remove(ctx context.Context, name tree.Name) error {
+	c, err := m.get(name)
+	if err != nil {
+		return err
+	}
+	err = c.finishQuery(ctx)
+	c.close(ctx)
+	delete(m.cursors, name)
+	return err
+}
+
+// removeAll closes all the cursors, for a CLOSE ALL statement.
+func (m *cursorMap) removeAll(ctx context.Context) error {
+	var retErr error
+	for _, name := range m.names() {
+		if err := m.remove(ctx, name); err != nil && retErr == nil {
+			retErr = err
+		}
+	}
+	return retErr
+}
+
+// names returns the names of all open cursors, in sorted order.
+func (m *cursorMap) names() []tree.Name {
+	names := make([]tree.Name, 0, len(m.cursors))
+	for name := range m.cursors {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// closeAll closes all the cursors in the map, when the session ends.
+func (m *cursorMap) closeAll(ctx context.Context) {
+	for name, c := range m.cursors {
+		c.close(ctx)
+		delete(m.cursors, name)
+	}
+}
+
+// beforeCommit is called before the transaction in which the cursors were
+// declared commits. The rows of WITH HOLD cursors are buffered so that they
+// can outlive the transaction, the cursors that don't are closed, and the
+// transaction is updated with the reads performed by the queries of the
+// cursors.
+func (m *cursorMap) beforeCommit(ctx context.Context) error {
+	for _, name := range m.names() {
+		c := m.cursors[name]
+		if !c.hold {
+			if err := m.remove(ctx, name); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.pullAll(ctx); err != nil {
+			return err
+		}
+		if err := c.finishQuery(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onTxnFinish closes the cursors that are scoped to the transaction that just
+// finished or restarted. If the transaction committed, WITH HOLD cursors stay
+// open; otherwise, only WITH HOLD cursors from previous transactions do.
+func (m *cursorMap) onTxnFinish(ctx context.Context, ev txnEvent) {
+	for name, c := range m.cursors {
+		if c.committed || (ev == txnCommit && c.hold) {
+			c.committed = true
+			continue
+		}
+		c.close(ctx)
+		delete(m.cursors, name)
+	}
+}
+
+// cursorSource runs the query of a cursor and returns its rows one at a time.
+//
+// The query runs in its own goroutine, in a leaf transaction derived from the
+// transaction in which the cursor was declared. The goroutine only runs while
+// a row is being requested from it: when the query produces a row, its
+// execution is blocked until the next row is requested, so that the query
+// never runs concurrently with the other statements of the session. Since the
+// leaf transaction reads at the sequence number of the DECLARE statement, the
+// query does not see the writes of the statements that follow it.
+type cursorSource struct {
+	// root is the transaction in which the cursor was declared, and leaf is the
+	// transaction in which the query runs.
+	root, leaf *kv.Txn
+	// readTS is the read timestamp of the root transaction when the cursor was
+	// declared. The reads of the query are only valid as long as the root
+	// transaction does not move its read timestamp.
+	readTS hlc.Timestamp
+
+	cancel context.CancelFunc
+	// req is used to request the next row from the goroutine, which responds
+	// on resp.
+	req  chan struct{}
+	resp chan cursorRow
+	// done is closed once the goroutine has exited.
+	done chan struct{}
+
+	// finished is set once the query has returned all its rows or failed, in
+	// which case err is its error.
+	finished bool
+	err      error
+}
+
+// cursorRow is a response to a request for the next row of a cursorSource.
+type cursorRow struct {
+	row tree.Datums
+	// done is set when the query has returned all its rows or failed, in which
+	// case err is its error.
+	done bool
+	err  error
+}
+
+// startCursorSource starts running the query planned by p, which must have
+// been planned in the root transaction p.txn, in a new goroutine.
+func startCursorSource(ctx context.Context, p *planner) (*cursorSource, error) {
+	root := p.txn
+	tis, err := root.GetLeafTxnInputStateOrRejectClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dsp := p.ExecCfg().DistSQLPlanner
+	s := &cursorSource{
+		root:   root,
+		leaf:   kv.NewLeafTxn(ctx, p.ExecCfg().DB, dsp.gatewayNodeID, &tis),
+		readTS: root.ReadTimestamp(),
+		req:    make(chan struct{}),
+		resp:   make(chan cursorRow),
+		done:   make(chan struct{}),
+	}
+	p.txn = s.leaf
+	p.extendedEvalCtx.Txn = s.leaf
+
+	// The goroutine outlives the DECLARE statement, so it runs in the context
+	// of the session's transaction.
+	ctx, s.cancel = context.WithCancel(p.EvalContext().Context)
+	go s.run(ctx, p)
+	return s, nil
+}
+
+// run runs the query of the cursor once its first row is requested.
+func (s *cursorSource) run(ctx context.Context, p *planner) {
+	defer close(s.done)
+	select {
+	case <-s.req:
+	case <-ctx.Done():
+		p.curPlan.close(ctx)
+		return
+	}
+	err := s.runPlan(ctx, p)
+	select {
+	case s.resp <- cursorRow{done: true, err: err}:
+	case <-ctx.Done():
+	}
+}
+
+// runPlan runs the plan of the cursor's query, sending its rows through
+// s.resp.
+func (s *cursorSource) runPlan(ctx context.Context, p *planner) error {
+	defer p.curPlan.close(ctx)
+	execCfg := p.ExecCfg()
+	w := &cursorResultWriter{src: s}
+	recv := MakeDistSQLReceiver(
+		ctx, w, tree.Rows,
+		execCfg.RangeDescriptorCache,
+		s.root,
+		execCfg.Clock,
+		&SessionTracing{},
+	)
+	defer recv.Release()
+
+	dsp := execCfg.DistSQLPlanner
+	plan := &p.curPlan.planComponents
+	if len(plan.subqueryPlans) != 0 && !dsp.PlanAndRunSubqueries(
+		ctx, p, p.ExtendedEvalContextCopy, plan.subqueryPlans, recv,
+	) {
+		if w.err != nil {
+			return w.err
+		}
+		return recv.commErr
+	}
+
+	// The plan is always run locally, since the leaf transaction cannot be
+	// used to create the leaf transactions of remote flows.
+	evalCtx := p.ExtendedEvalContext()
+	planCtx := dsp.NewPlanningCtx(ctx, evalCtx, p, p.txn, false /* distribute */)
+	planCtx.stmtType = recv.stmtType
+	dsp.PlanAndRun(ctx, evalCtx, planCtx, p.txn, plan.main, recv)()
+	if w.err != nil {
+		return w.err
+	}
+	return recv.commErr
+}
+
+// next runs the query until it returns its next row. It returns false once the
+// query has returned all its rows.
+func (s *cursorSource) next(ctx context.Context) (tree.Datums, bool, error) {
+	if s.finished {
+		return nil, false, s.err
+	}
+	if err := s.checkReadTimestamp(); err != nil {
+		return nil, false, err
+	}
+	select {
+	case s.req <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+	select {
+	case r := <-s.resp:
+		if r.done {
+			s.finished, s.err = true, r.err
+			return nil, false, r.err
+		}
+		return r.row, true, nil
+	case <-ctx.Done():
+		// The query is in the middle of producing a row and can't be resumed.
+		s.stop()
+		s.finished, s.err = true, ctx.Err()
+		return nil, false, s.err
+	}
+}
+
+// checkReadTimestamp returns an error if the root transaction has moved its
+// read timestamp since the cursor was declared, in which case the rows
+// returned by the query are no longer consistent with the transaction.
+func (s *cursorSource) checkReadTimestamp() error {
+	if readTS := s.root.ReadTimestamp(); readTS != s.readTS {
+		return pgerror.Newf(pgcode.SerializationFailure,
+			"restart transaction: the read timestamp of the transaction moved from %s to %s "+
+				"while the cursor was open", s.readTS, readTS)
+	}
+	return nil
+}
+
+// stop stops the query and waits for its goroutine to exit.
+func (s *cursorSource) stop() {
+	s.cancel()
+	<-s.done
+}
+
+// finish stops the query and updates the root transaction with the reads that
+// the query performed in its leaf transaction, so that they are validated if
+// the root transaction needs to refresh its reads.
+func (s *cursorSource) finish(ctx context.Context) error {
+	s.stop()
+	if err := s.checkReadTimestamp(); err != nil {
+		return err
+	}
+	tfs, err := s.leaf.GetLeafTxnFinalState(ctx)
+	if err != nil {
+		return err
+	}
+	return s.root.UpdateRootWithLeafFinalState(ctx, &tfs)
+}
+
+// cursorResultWriter is the rowResultWriter of the query of a cursor. It sends
+// each row to the session, and then blocks until the next row is requested.
+type cursorResultWriter struct {
+	src *cursorSource
+	err error
+}
+
+var _ rowResultWriter = &cursorResultWriter{}
+
+// AddRow is part of the rowResultWriter interface.
+func (w *cursorResultWriter) AddRow(ctx context.Context, row tree.Datums) error {
+	r := cursorRow{row: make(tree.Datums, len(row))}
+	copy(r.row, row)
+	select {
+	case w.src.resp <- r:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-w.src.req:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IncrementRowsAffected is part of the rowResultWriter interface.
+func (w *cursorResultWriter) IncrementRowsAffected(int) {}
+
+// SetError is part of the rowResultWriter interface.
+func (w *cursorResultWriter) SetError(err error) {
+	w.err = err
+}
+
+// Err is part of the rowResultWriter interface.
+func (w *cursorResultWriter) Err() error {
+	return w.err
+}
+
+// checkDeclareCursor validates a DECLARE statement.
+func (ex *connExecutor) checkDeclareCursor(s *tree.DeclareCursor, implicitTxn bool) error {
+	if s.Binary {
+		return unimplemented.NewWithIssue(41412, "binary cursors are not supported")
+	}
+	if len(s.Select.Locking) > 0 {
+		// The query of the cursor runs in a leaf transaction, which cannot
+		// acquire locks on behalf of the transaction.
+		return unimplemented.New("cursor-locking",
+			"DECLARE CURSOR with FOR UPDATE or FOR SHARE is not supported")
+	}
+	if implicitTxn && !s.Hold {
+		return pgerror.New(pgcode.NoActiveSQLTransaction,
+			"DECLARE CURSOR can only be used in transaction blocks")
+	}
+	if _, ok := ex.extraTxnState.sqlCursors.cursors[s.Name]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", s.Name)
+	}
+	return nil
+}
+
+// execDeclareCursor creates the cursor of a DECLARE statement. The query of
+// the cursor, which p has been set up to plan, is planned with a planner of
+// its own, since its plan outlives the statement. The query is only run as
+// the rows of the cursor are fetched.
+func (ex *connExecutor) execDeclareCursor(
+	ctx context.Context, p *planner, s *tree.DeclareCursor,
+) error {
+	cursorMon := execinfra.NewMonitor(ctx, ex.sessionMon, "sql-cursor")
+	cp := &planner{execCfg: ex.server.cfg, alloc: &rowenc.DatumAlloc{}}
+	ex.initPlanner(ctx, cp)
+	ex.resetPlanner(ctx, cp, p.txn, p.extendedEvalCtx.StmtTimestamp)
+	cp.stmt = p.stmt
+	cp.semaCtx.Placeholders = p.semaCtx.Placeholders
+	cp.semaCtx.Annotations = p.semaCtx.Annotations
+	cp.semaCtx.AsOfTimestamp = p.semaCtx.AsOfTimestamp
+	cp.extendedEvalCtx.Placeholders = &cp.semaCtx.Placeholders
+	cp.extendedEvalCtx.Annotations = &cp.semaCtx.Annotations
+	cp.extendedEvalCtx.Mon = cursorMon
+	cp.avoidCachedDescriptors = p.avoidCachedDescriptors
+
+	err := ex.makeExecPlan(ctx, cp)
+	if err == nil && cp.curPlan.mem != nil {
+		if rel, ok := cp.curPlan.mem.RootExpr().(memo.RelExpr); ok && rel.Relational().CanMutate {
+			err = pgerror.New(pgcode.FeatureNotSupported,
+				"DECLARE CURSOR must not contain data-modifying statements")
+		}
+	}
+	var src *cursorSource
+	if err == nil {
+		src, err = startCursorSource(ctx, cp)
+	}
+	if err != nil {
+		cp.curPlan.close(ctx)
+		cursorMon.Stop(ctx)
+		return err
+	}
+
+	c := &sqlCursor{
+		statement: p.stmt.SQL,
+		created:   timeutil.Now(),
+		hold:      s.Hold,
+		binary:    s.Binary,
+		scroll:    s.Scroll,
+		cols:      cp.curPlan.main.planColumns(),
+		src:       src,
+		mon:       cursorMon,
+	}
+	if c.hold || c.scrollable() {
+		distSQLCfg := &ex.server.cfg.DistSQLSrv.ServerConfig
+		c.rowsMon = execinfra.NewLimitedMonitor(ctx, cursorMon, distSQLCfg, "sql-cursor-limited")
+		c.diskMon = execinfra.NewMonitor(ctx, distSQLCfg.DiskMonitor, "sql-cursor-disk")
+		typs := make([]*types.T, len(c.cols))
+		for i := range c.cols {
+			typs[i] = c.cols[i].Typ
+		}
+		c.rows = rowcontainer.NewDiskBackedIndexedRowContainer(
+			nil /* ordering */, typs, &cp.extendedEvalCtx.EvalContext,
+			distSQLCfg.TempStorage, c.rowsMon, c.diskMon,
+		)
+		c.scratch = make(rowenc.EncDatumRow, len(c.cols))
+	}
+	if err := ex.extraTxnState.sqlCursors.add(s.Name, c); err != nil {
+		c.close(ctx)
+		return err
+	}
+	return nil
+}
+
+// FetchCursor implements the FETCH and MOVE statements.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(
+	_ context.Context, s *tree.CursorStmt, isMove bool,
+) (planNode, error) {
+	cursor, err := p.sqlCursors.get(s.Name)
+	if err != nil {
+		return nil, err
+	}
+	n := &fetchNode{n: s, cursor: cursor, isMove: isMove}
+	if !isMove {
+		n.columns = cursor.cols
+	}
+	return n, nil
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, s *tree.CloseCursor) (planNode, error) {
+	var err error
+	if s.All {
+		err = p.sqlCursors.removeAll(ctx)
+	} else {
+		err = p.sqlCursors.remove(ctx, s.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// fetchNode returns rows from a cursor for a FETCH statement, or only moves
+// the cursor for a MOVE statement.
+type fetchNode struct {
+	n       *tree.CursorStmt
+	cursor  *sqlCursor
+	isMove  bool
+	columns colinfo.ResultColumns
+
+	// remaining is the number of rows left to return. If positioned is set, the
+	// cursor has already been moved onto the only row to return; otherwise, the
+	// cursor moves by one row in the direction of dir (1 or -1) for each row.
+	remaining  int64
+	positioned bool
+	dir        int
+	row        tree.Datums
+
+	// moved is the number of rows a MOVE statement moved over.
+	moved int
+}
+
+func (n *fetchNode) startExec(params runParams) error {
+	c, s := n.cursor, n.n
+	var target int64
+	switch s.FetchType {
+	case tree.FetchNormal:
+		if s.Count == 0 {
+			target = int64(c.pos)
+			break
+		}
+		n.remaining, n.dir = s.Count, 1
+		if s.Count < 0 {
+			n.remaining, n.dir = -s.Count, -1
+		}
+	case tree.FetchAll:
+		n.remaining, n.dir = math.MaxInt64, 1
+	case tree.FetchBackwardAll:
+		n.remaining, n.dir = math.MaxInt64, -1
+	case tree.FetchRelative:
+		target = int64(c.pos) + s.Count
+	case tree.FetchAbsolute:
+		target = s.Count
+		if target < 0 {
+			if err := c.pullAll(params.ctx); err != nil {
+				return err
+			}
+			target += int64(c.numRows) + 1
+		}
+	case tree.FetchFirst:
+		target = 1
+	case tree.FetchLast:
+		if err := c.pullAll(params.ctx); err != nil {
+			return err
+		}
+		target = int64(c.numRows)
+	default:
+		return errors.AssertionFailedf("unknown fetch type %d", s.FetchType)
+	}
+
+	if n.dir == 0 {
+		row, ok, err := c.moveTo(params.ctx, target)
+		if err != nil {
+			return err
+		}
+		if ok {
+			n.remaining, n.positioned, n.row = 1, true, row
+		}
+	} else if n.dir < 0 && !c.scrollable() {
+		return errCursorForwardOnly
+	}
+
+	if n.isMove {
+		for {
+			ok, err := n.Next(params)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			n.moved++
+		}
+	}
+	return nil
+}
+
+// FastPathResults implements the planNodeFastPath interface.
+func (n *fetchNode) FastPathResults() (int, bool) {
+	return n.moved, n.isMove
+}
+
+func (n *fetchNode) Next(params runParams) (bool, error) {
+	if n.remaining == 0 {
+		return false, nil
+	}
+	n.remaining--
+	if n.positioned {
+		return true, nil
+	}
+	row, ok, err := n.cursor.step(params.ctx, n.dir)
+	if err != nil || !ok {
+		n.remaining = 0
+		return false, err
+	}
+	n.row = row
+	return true, nil
+}
+
+func (n *fetchNode) Values() tree.Datums {
+	return n.row
+}
+
+func (n *fetchNode) Close(context.Context) {}
@@ -33,3 +33,7 @@ func VecModeCounter(mode string) telemetry.Counter {
 // CascadesLimitReached is to be incremented whenever the limit of foreign key
 // cascade for a single query is exceeded.
 var CascadesLimitReached = telemetry.GetCounterOnce("sql.exec.cascade-limit-reached")
+
+// TriggerDepthLimitReached is to be incremented whenever the limit of nested
+// triggers for a single query is exceeded.
+var TriggerDepthLimitReached = telemetry.GetCounterOnce("sql.exec.trigger-depth-limit-reached")
@@ -52,6 +52,8 @@ const (
 	Roles
 	// Schedules represents the SHOW SCHEDULE command.
 	Schedules
+	// RangeLocalities represents the SHOW RANGE LOCALITIES command.
+	RangeLocalities
 )
 
 var showTelemetryNameMap = map[ShowTelemetryType]string{
@@ -70,6 +72,7 @@ var showTelemetryNameMap = map[ShowTelemetryType]string{
 	Jobs:                    "jobs",
 	Roles:                   "roles",
 	Schedules:               "schedules",
+	RangeLocalities:         "rangelocalities",
 }
 
 func (s ShowTelemetryType) String() string {
@@ -307,3 +307,37 @@ func (p *planner) GCTenant(ctx context.Context, tenID uint64) error {
 
 	return GCTenant(ctx, p.ExecCfg(), info)
 }
+
+// UpdateTenantResourceLimits implements the tree.TenantOperator interface.
+func (p *planner) UpdateTenantResourceLimits(
+	ctx context.Context, tenID uint64, rateLimit float64, burstLimit float64,
+) error {
+	const op = "update"
+	if err := rejectIfCantCoordinateMultiTenancy(p.execCfg.Codec, op); err != nil {
+		return err
+	}
+	if err := rejectIfSystemTenant(tenID, op); err != nil {
+		return err
+	}
+
+	// Retrieve the tenant's info.
+	info, err := getTenantRecord(ctx, p.execCfg, p.txn, tenID)
+	if err != nil {
+		return errors.Wrap(err, "updating tenant resource limits")
+	}
+
+	// The updated info is gossiped to the KV servers with the SystemConfig,
+	// which then reconfigure the rate limiters of the tenant. The update is
+	// only gossiped if the transaction sets the SystemConfig trigger. Set it
+	// here instead of relying on the UPDATE statement below to do so.
+	if err := p.maybeSetSystemConfig(keys.TenantsTableID); err != nil {
+		return err
+	}
+	info.ResourceLimits = &descpb.TenantInfo_ResourceLimits{
+		RateLimit:  rateLimit,
+		BurstLimit: burstLimit,
+	}
+	return errors.Wrap(
+		updateTenantRecord(ctx, p.execCfg, p.txn, info), "updating tenant resource limits",
+	)
+}
@@ -14,8 +14,10 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -23,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
 )
 
 // GetUserHashedPassword determines if the given user exists and
@@ -181,6 +184,68 @@ func retrieveUserAndPassword(
 	return exists, canLogin, hashedPassword, validUntil, err
 }
 
+// GetConfiguredPasswordHashMethod returns the method to use to hash new
+// passwords. SCRAM-SHA-256 credentials are only used once all the nodes
+// are able to understand them.
+func GetConfiguredPasswordHashMethod(
+	ctx context.Context, st *cluster.Settings,
+) security.PasswordHashMethod {
+	if !st.Version.IsActive(ctx, clusterversion.SCRAMAuthentication) {
+		return security.HashBCrypt
+	}
+	return security.PasswordHashMethod(security.PasswordHashMethodSetting.Get(&st.SV))
+}
+
+// StoredPasswordHashUpgradeEnabled returns whether stored bcrypt password
+// hashes are converted to SCRAM credentials upon successful cleartext login.
+func StoredPasswordHashUpgradeEnabled(ctx context.Context, st *cluster.Settings) bool {
+	return security.UpgradeBcryptToSCRAM.Get(&st.SV) &&
+		GetConfiguredPasswordHashMethod(ctx, st) == security.HashSCRAMSHA256
+}
+
+// MaybeUpgradeStoredPasswordHash re-encodes the stored password of a user
+// using the configured hash method, after the user has successfully
+// authenticated with the given cleartext password. The update is
+// conditional on the stored hash not having changed in the meantime.
+//
+// Computing SCRAM credentials is expensive, so the conversion runs in an
+// async task rather than as part of the authentication of the connection.
+// Errors are not reported to the client: the user has authenticated
+// successfully already and the conversion will be attempted again on the
+// next login.
+func MaybeUpgradeStoredPasswordHash(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	username security.SQLUsername,
+	password string,
+	currentHash []byte,
+) {
+	if !StoredPasswordHashUpgradeEnabled(ctx, execCfg.Settings) || security.IsSCRAMHash(currentHash) {
+		return
+	}
+	// The task outlives the authentication of the connection, and so must not
+	// be canceled with it.
+	ctx = logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	if err := execCfg.DistSQLSrv.Stopper.RunAsyncTask(ctx, "upgrade-hashed-pwd", func(ctx context.Context) {
+		newHash, err := security.HashPasswordSCRAM(password)
+		if err != nil {
+			log.Warningf(ctx, "unable to compute SCRAM credentials for %q: %v", username, err)
+			return
+		}
+		const upgradeHashedPassword = `UPDATE system.users SET "hashedPassword" = $3 ` +
+			`WHERE username = $1 AND "hashedPassword" = $2`
+		if _, err := execCfg.InternalExecutor.ExecEx(
+			ctx, "upgrade-hashed-pwd", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			upgradeHashedPassword, username, currentHash, newHash,
+		); err != nil {
+			log.Warningf(ctx, "unable to upgrade the stored password of %q to SCRAM-SHA-256: %v", username, err)
+		}
+	}); err != nil {
+		log.Warningf(ctx, "unable to upgrade the stored password of %q to SCRAM-SHA-256: %v", username, err)
+	}
+}
+
 var userLoginTimeout = settings.RegisterDurationSetting(
 	"server.user_login.timeout",
 	"timeout after which client authentication times out if some system range is unavailable (0 = no timeout)",
@@ -605,6 +605,29 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`trigger_depth_limit`: {
+		GetStringVal: makeIntGetStringValFn(`trigger_depth_limit`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+			if b < 0 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"cannot set trigger_depth_limit to a negative value: %d", b)
+			}
+			m.SetTriggerDepthLimit(int(b))
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return strconv.FormatInt(int64(evalCtx.SessionData.TriggerDepthLimit), 10)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return strconv.FormatInt(triggerDepthClusterLimit.Get(sv), 10)
+		},
+	},
+
 	// CockroachDB extension.
 	`optimizer_use_histograms`: {
 		GetStringVal: makePostgresBoolGetStringValFn(`optimizer_use_histograms`),
@@ -923,15 +946,47 @@ var varGen = map[string]sessionVar{
 	// results received by clients, we accept both values.
 	`synchronize_seqscans`: makeCompatBoolVar(`synchronize_seqscans`, true, true /* anyAllowed */),
 
+	// See https://www.postgresql.org/docs/current/pgtrgm.html#PGTRGM-GUC
+	`pg_trgm.similarity_threshold`: {
+		GetStringVal: makeFloatGetStringValFn(`pg_trgm.similarity_threshold`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return wrapSetVarError("pg_trgm.similarity_threshold", s, "%v", err)
+			}
+			if f < 0 || f > 1 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%g is outside the valid range for parameter "pg_trgm.similarity_threshold" (0 .. 1)`, f)
+			}
+			m.SetTrigramSimilarityThreshold(f)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return strconv.FormatFloat(evalCtx.SessionData.TrigramSimilarityThreshold, 'g', -1, 64)
+		},
+		GlobalDefault: func(sv *settings.Values) string { return "0.3" },
+	},
+
 	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-ROW-SECURITY
-	// The default in pg is "on" but row security is not supported in CockroachDB.
-	// We blindly accept both values because as long as there are now row security policies defined,
-	// either value produces the same query results in PostgreSQL. That is, as long as CockroachDB
-	// does not support row security, accepting either "on" and "off" but ignoring the result
-	// is postgres-compatible.
-	// If/when CockroachDB is extended to support row security, the default and allowed values
-	// should be modified accordingly.
-	`row_security`: makeCompatBoolVar(`row_security`, false, true /* anyAllowed */),
+	// When row_security is off, queries which would be filtered by row-level
+	// security policies return an error instead.
+	`row_security`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`row_security`),
+		Set: func(_ context.Context, m *sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar("row_security", s)
+			if err != nil {
+				return err
+			}
+			m.SetRowSecurity(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) string {
+			return formatBoolAsPostgresSetting(!evalCtx.SessionData.RowSecurityDisabled)
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(true)
+		},
+	},
 
 	`statement_timeout`: {
 		GetStringVal: makeTimeoutVarGetter(`statement_timeout`),
@@ -1354,6 +1409,16 @@ func makeIntGetStringValFn(name string) getStringValFn {
 	}
 }
 
+func makeFloatGetStringValFn(name string) getStringValFn {
+	return func(ctx context.Context, evalCtx *extendedEvalContext, values []tree.TypedExpr) (string, error) {
+		f, err := getFloatVal(&evalCtx.EvalContext, name, values)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	}
+}
+
 // IsSessionVariableConfigurable returns true iff there is a session
 // variable with the given name and it is settable by a client
 // (e.g. in pgwire).
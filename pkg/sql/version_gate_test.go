@@ -0,0 +1,99 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/server"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+// TestSchemaVersionGates verifies that the statements writing descriptor
+// fields which nodes running the previous release would ignore or fail to
+// decode are rejected until the cluster version introducing them is finalized.
+func TestSchemaVersionGates(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		version clusterversion.Key
+		// setup is run before the version is finalized.
+		setup []string
+		// stmts fail before the version is finalized and succeed afterwards.
+		stmts []string
+	}{
+		{
+			version: clusterversion.UserDefinedFunctions,
+			stmts:   []string{`CREATE FUNCTION one() RETURNS INT IMMUTABLE AS 'SELECT 1'`},
+		},
+		{
+			version: clusterversion.TableTriggers,
+			setup:   []string{`CREATE TABLE t (k INT PRIMARY KEY)`, `CREATE TABLE audit (k INT)`},
+			stmts: []string{
+				`CREATE TRIGGER audit_insert AFTER INSERT ON t FOR EACH STATEMENT
+  AS 'INSERT INTO audit SELECT k FROM new'`,
+			},
+		},
+		{
+			version: clusterversion.DeferrableConstraints,
+			setup:   []string{`CREATE TABLE t (k INT PRIMARY KEY)`, `CREATE TABLE u (k INT PRIMARY KEY, t INT)`},
+			stmts: []string{
+				`ALTER TABLE u ADD CONSTRAINT u_t_fkey FOREIGN KEY (t) REFERENCES t (k) DEFERRABLE`,
+				`CREATE TABLE v (k INT PRIMARY KEY, t INT REFERENCES t (k) DEFERRABLE INITIALLY DEFERRED)`,
+			},
+		},
+		{
+			version: clusterversion.RowLevelSecurity,
+			setup:   []string{`CREATE TABLE t (k INT PRIMARY KEY)`, `ALTER TABLE t DISABLE ROW LEVEL SECURITY`},
+			stmts:   []string{`CREATE POLICY p ON t USING (k > 0)`, `ALTER TABLE t ENABLE ROW LEVEL SECURITY`},
+		},
+		{
+			version: clusterversion.TrigramInvertedIndexes,
+			setup:   []string{`CREATE TABLE t (k INT PRIMARY KEY, s STRING)`},
+			stmts: []string{
+				`CREATE INVERTED INDEX ON t (s gin_trgm_ops)`,
+				`CREATE INDEX ON t USING GIST (s gist_trgm_ops)`,
+				`CREATE TABLE u (k INT PRIMARY KEY, s STRING, INVERTED INDEX (s gin_trgm_ops))`,
+			},
+		},
+	} {
+		t.Run(tc.version.String(), func(t *testing.T) {
+			srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+				Knobs: base.TestingKnobs{
+					Server: &server.TestingKnobs{
+						BinaryVersionOverride:          clusterversion.ByKey(tc.version - 1),
+						DisableAutomaticVersionUpgrade: 1,
+					},
+				},
+			})
+			defer srv.Stopper().Stop(context.Background())
+			sqlDB := sqlutils.MakeSQLRunner(db)
+
+			for _, stmt := range tc.setup {
+				sqlDB.Exec(t, stmt)
+			}
+			for _, stmt := range tc.stmts {
+				sqlDB.ExpectErr(t, `version .* must be finalized`, stmt)
+			}
+			sqlDB.Exec(t, `SET CLUSTER SETTING version = $1`, clusterversion.ByKey(tc.version).String())
+			for _, stmt := range tc.stmts {
+				sqlDB.Exec(t, stmt)
+			}
+		})
+	}
+}
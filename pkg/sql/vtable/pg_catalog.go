@@ -559,6 +559,18 @@ CREATE TABLE pg_catalog.pg_prepared_statements (
 	from_sql boolean
 )`
 
+// PGCatalogCursors describes the schema of the pg_catalog.pg_cursors table.
+// https://www.postgresql.org/docs/current/view-pg-cursors.html,
+const PGCatalogCursors = `
+CREATE TABLE pg_catalog.pg_cursors (
+	name TEXT,
+	statement TEXT,
+	is_holdable BOOL,
+	is_binary BOOL,
+	is_scrollable BOOL,
+	creation_time TIMESTAMPTZ
+)`
+
 // PGCatalogProc describes the schema of the pg_catalog.pg_proc table.
 // https://www.postgresql.org/docs/9.5/catalog-pg-proc.html,
 const PGCatalogProc = `
@@ -146,6 +146,28 @@ func BenchmarkMVCCGet_Pebble(b *testing.B) {
 	}
 }
 
+func BenchmarkMVCCGetRangeTombstones_Pebble(b *testing.B) {
+	ctx := context.Background()
+	for _, rangeTombstone := range []string{"none", "disjoint", "overlapping"} {
+		b.Run(fmt.Sprintf("rangeTombstone=%s", rangeTombstone), func(b *testing.B) {
+			runMVCCReadRangeTombstones(ctx, b, setupMVCCInMemPebble, 0 /* numRows */, rangeTombstone)
+		})
+	}
+}
+
+func BenchmarkMVCCScanRangeTombstones_Pebble(b *testing.B) {
+	ctx := context.Background()
+	for _, numRows := range []int{10, 1000} {
+		b.Run(fmt.Sprintf("rows=%d", numRows), func(b *testing.B) {
+			for _, rangeTombstone := range []string{"none", "disjoint", "overlapping"} {
+				b.Run(fmt.Sprintf("rangeTombstone=%s", rangeTombstone), func(b *testing.B) {
+					runMVCCReadRangeTombstones(ctx, b, setupMVCCInMemPebble, numRows, rangeTombstone)
+				})
+			}
+		})
+	}
+}
+
 func BenchmarkMVCCComputeStats_Pebble(b *testing.B) {
 	skip.UnderShort(b)
 	ctx := context.Background()
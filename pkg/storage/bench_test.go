@@ -714,6 +714,76 @@ func runMVCCBatchTimeSeries(ctx context.Context, b *testing.B, emk engineMaker,
 	b.StopTimer()
 }
 
+// runMVCCReadRangeTombstones creates numKeys keys, and an MVCC range
+// tombstone above them as specified by rangeTombstone: "none", "disjoint"
+// (over a span without keys) or "overlapping" (over all of the keys). It then
+// performs b.N MVCCScans of numRows keys below the range tombstone, or
+// MVCCGets if numRows is zero.
+func runMVCCReadRangeTombstones(
+	ctx context.Context, b *testing.B, emk engineMaker, numRows int, rangeTombstone string,
+) {
+	const numKeys = 10000
+	eng := emk(b, fmt.Sprintf("read_range_tombstones_%s", rangeTombstone))
+	defer eng.Close()
+
+	batch := eng.NewBatch()
+	keyBuf := append(make([]byte, 0, 64), []byte("key-")...)
+	value := roachpb.MakeValueFromBytes(make([]byte, 8))
+	for i := 0; i < numKeys; i++ {
+		key := roachpb.Key(encoding.EncodeUvarintAscending(keyBuf[:4], uint64(i)))
+		if err := MVCCPut(ctx, batch, nil, key, hlc.Timestamp{WallTime: 1}, value, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+	switch rangeTombstone {
+	case "none":
+	case "disjoint":
+		if err := MVCCDeleteRangeUsingTombstone(ctx, batch, nil,
+			roachpb.Key("tomb-a"), roachpb.Key("tomb-z"), hlc.Timestamp{WallTime: 2}); err != nil {
+			b.Fatal(err)
+		}
+	case "overlapping":
+		if err := MVCCDeleteRangeUsingTombstone(ctx, batch, nil,
+			roachpb.Key("key-"), roachpb.Key("key-").PrefixEnd(), hlc.Timestamp{WallTime: 2}); err != nil {
+			b.Fatal(err)
+		}
+	default:
+		b.Fatalf("unknown range tombstone %q", rangeTombstone)
+	}
+	if err := batch.Commit(false /* sync */); err != nil {
+		b.Fatal(err)
+	}
+	batch.Close()
+	if err := eng.Flush(); err != nil {
+		b.Fatal(err)
+	}
+
+	ts := hlc.Timestamp{WallTime: 1}
+	endKeyBuf := append(make([]byte, 0, 64), []byte("key-")...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		keyIdx := rand.Int31n(int32(numKeys - numRows))
+		key := roachpb.Key(encoding.EncodeUvarintAscending(keyBuf[:4], uint64(keyIdx)))
+		if numRows == 0 {
+			if v, _, err := MVCCGet(ctx, eng, key, ts, MVCCGetOptions{}); err != nil {
+				b.Fatalf("failed get: %+v", err)
+			} else if v == nil {
+				b.Fatalf("failed get (key not found): %d", keyIdx)
+			}
+			continue
+		}
+		endKey := roachpb.Key(encoding.EncodeUvarintAscending(endKeyBuf[:4], uint64(keyIdx+int32(numRows))))
+		res, err := MVCCScan(ctx, eng, key, endKey, ts, MVCCScanOptions{})
+		if err != nil {
+			b.Fatalf("failed scan: %+v", err)
+		}
+		if len(res.KVs) != numRows {
+			b.Fatalf("failed to scan: %d != %d", len(res.KVs), numRows)
+		}
+	}
+	b.StopTimer()
+}
+
 // runMVCCGetMergedValue reads merged values for numKeys separate keys and mergesPerKey
 // operands per key.
 func runMVCCGetMergedValue(
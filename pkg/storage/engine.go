@@ -155,6 +155,11 @@ type MVCCIterator interface {
 	// SupportsPrev returns true if MVCCIterator implementation supports reverse
 	// iteration with Prev() or SeekLT().
 	SupportsPrev() bool
+	// RangeTombstonesAt returns the timestamps of the MVCC range tombstones
+	// covering the given key, newest first. Only iterators of the
+	// MVCCKeyAndIntentsIterKind, which expose the range tombstones as point
+	// tombstones, return them.
+	RangeTombstonesAt(key roachpb.Key) ([]hlc.Timestamp, error)
 }
 
 // EngineIterator is an iterator over key-value pairs where the key is
@@ -566,6 +571,12 @@ type Engine interface {
 	// GetEnvStats retrieves stats about the engine's environment
 	// For RocksDB, this includes details of at-rest encryption.
 	GetEnvStats() (*EnvStats, error)
+	// ReencryptFiles rewrites sstables that are encrypted with a data key
+	// other than the active one, so that they are encrypted with the active
+	// data key, until at least maxBytes bytes have been rewritten or there are
+	// no such sstables left. It returns the number of bytes rewritten. It is a
+	// no-op when encryption-at-rest is not enabled.
+	ReencryptFiles(ctx context.Context, maxBytes int64) (int64, error)
 	// GetAuxiliaryDir returns a path under which files can be stored
 	// persistently, and from which data can be ingested by the engine.
 	//
@@ -730,6 +741,8 @@ type Metrics struct {
 	PendingCompactionBytesEstimate int64
 	L0FileCount                    int64
 	L0SublevelCount                int64
+	L0Size                         int64
+	L0BytesIngested                int64
 	ReadAmplification              int64
 	NumSSTables                    int64
 }
@@ -749,6 +762,20 @@ type EnvStats struct {
 	EncryptionType int32
 	// EncryptionStatus is a serialized enginepbccl/stats.proto::EncryptionStatus protobuf.
 	EncryptionStatus []byte
+	// KeyStats breaks the files down by the data key they are encrypted with,
+	// ordered by key ID.
+	KeyStats []EncryptionKeyStats
+}
+
+// EncryptionKeyStats are the stats of the files encrypted with a data key.
+type EncryptionKeyStats struct {
+	// KeyID is the ID of the data key, or "plain" for files that are not
+	// encrypted.
+	KeyID string
+	// Files is the number of files using the key.
+	Files uint64
+	// Bytes is the size of the sstables using the key.
+	Bytes uint64
 }
 
 // EncryptionRegistries contains the encryption-related registries:
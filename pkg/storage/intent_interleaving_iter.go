@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
 )
@@ -755,3 +756,7 @@ func (i *intentInterleavingIter) Stats() IteratorStats {
 func (i *intentInterleavingIter) SupportsPrev() bool {
 	return true
 }
+
+func (i *intentInterleavingIter) RangeTombstonesAt(roachpb.Key) ([]hlc.Timestamp, error) {
+	return nil, nil
+}
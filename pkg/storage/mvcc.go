@@ -1370,6 +1370,18 @@ func mvccPutInternal(
 
 	// Verify we're not mixing inline and non-inline values.
 	putIsInline := timestamp.IsEmpty()
+	if !ok && !putIsInline && iter != nil {
+		// The MVCC range tombstones covering a key only show up once the key
+		// has versions, so materialize them before writing the first one. The
+		// write is then checked against them like against any other tombstone.
+		// Blind puts are not checked, as they are only used for keys without
+		// prior versions.
+		ok, origMetaKeySize, origMetaValSize, err = materializeRangeTombstones(
+			writer, iter, ms, metaKey, &buf.meta)
+		if err != nil {
+			return err
+		}
+	}
 	if ok && putIsInline != buf.meta.IsInline() {
 		return errors.Errorf("%q: put is inline=%t, but existing value is inline=%t",
 			metaKey, putIsInline, buf.meta.IsInline())
@@ -2050,6 +2062,58 @@ func MVCCClearTimeRange(
 	key, endKey roachpb.Key,
 	startTime, endTime hlc.Timestamp,
 	maxBatchSize int64,
+) (*roachpb.Span, error) {
+	// The range tombstones in the time span are cleared up front. The deletion
+	// tombstones synthesized for them come and go with the versions of the
+	// keys they cover, which the incremental stats updates below don't
+	// account for, so the stats of the span are recomputed instead.
+	rangeTombstones, err := MVCCScanRangeTombstones(rw, key, endKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(rangeTombstones) > 0 {
+		computeStats := func() (enginepb.MVCCStats, error) {
+			iter := rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{
+				LowerBound: key,
+				UpperBound: endKey,
+			})
+			defer iter.Close()
+			return ComputeStatsForRange(iter, key, endKey, endTime.WallTime)
+		}
+		var before enginepb.MVCCStats
+		if ms != nil {
+			if before, err = computeStats(); err != nil {
+				return nil, err
+			}
+		}
+		if err := clearRangeTombstones(rw, ms, key, endKey, func(ts hlc.Timestamp) bool {
+			return startTime.Less(ts) && ts.LessEq(endTime)
+		}); err != nil {
+			return nil, err
+		}
+		resume, err := mvccClearTimeRange(rw, nil /* ms */, key, endKey, startTime, endTime, maxBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if ms != nil {
+			after, err := computeStats()
+			if err != nil {
+				return nil, err
+			}
+			after.Subtract(before)
+			ms.Add(after)
+		}
+		return resume, nil
+	}
+	return mvccClearTimeRange(rw, ms, key, endKey, startTime, endTime, maxBatchSize)
+}
+
+func mvccClearTimeRange(
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	key, endKey roachpb.Key,
+	startTime, endTime hlc.Timestamp,
+	maxBatchSize int64,
 ) (*roachpb.Span, error) {
 	var batchSize int64
 	var resume *roachpb.Span
@@ -3154,6 +3218,27 @@ func MVCCGarbageCollect(
 		}
 		inlinedValue := meta.IsInline()
 		implicitMeta := iter.UnsafeKey().IsValue()
+		if !inlinedValue {
+			rangeTombstones, err := iter.RangeTombstonesAt(gcKey.Key)
+			if err != nil {
+				return err
+			}
+			if len(rangeTombstones) > 0 {
+				if meta.Timestamp.ToTimestamp().LessEq(gcKey.Timestamp) {
+					if !meta.Deleted {
+						return errors.Errorf("request to GC non-deleted, latest value of %q", gcKey.Key)
+					}
+					if meta.Txn != nil {
+						return errors.Errorf("request to GC intent at %q", gcKey.Key)
+					}
+				}
+				if err := garbageCollectCoveredKey(rw, ms, gcKey.Key, gcKey.Timestamp, timestamp); err != nil {
+					return err
+				}
+				count++
+				continue
+			}
+		}
 		// First, check whether all values of the key are being deleted.
 		//
 		// Note that we naively can't terminate GC'ing keys loop early if we
@@ -68,7 +68,9 @@ import (
 // NOTE: This is not used by CockroachDB and has been preserved to serve as an
 // oracle to prove the correctness of the new export logic.
 type MVCCIncrementalIterator struct {
-	iter MVCCIterator
+	reader Reader
+	endKey roachpb.Key
+	iter   MVCCIterator
 
 	// A time-bound iterator cannot be used by itself due to a bug in the time-
 	// bound iterator (#28358). This was historically augmented with an iterator
@@ -135,6 +137,8 @@ func NewMVCCIncrementalIterator(
 	}
 
 	return &MVCCIncrementalIterator{
+		reader:        reader,
+		endKey:        opts.EndKey,
 		iter:          iter,
 		startTime:     opts.StartTime,
 		endTime:       opts.EndTime,
@@ -146,6 +150,19 @@ func NewMVCCIncrementalIterator(
 // provided key. startKey should be a metadata key to ensure that the iterator
 // has a chance to observe any intents on the key if they are there.
 func (i *MVCCIncrementalIterator) SeekGE(startKey MVCCKey) {
+	if i.timeBoundIter != nil {
+		// The TBI does not see the deletions of the range tombstones in the time
+		// range, which the main iterator exposes as point tombstones, so it can't
+		// be used to skip keys if there are any.
+		if ok, err := i.hasRangeTombstonesInTimeRange(startKey.Key); err != nil {
+			i.err = err
+			i.valid = false
+			return
+		} else if ok {
+			i.timeBoundIter.Close()
+			i.timeBoundIter = nil
+		}
+	}
 	if i.timeBoundIter != nil {
 		// Check which is the first key seen by the TBI.
 		i.timeBoundIter.SeekGE(startKey)
@@ -172,6 +189,28 @@ func (i *MVCCIncrementalIterator) SeekGE(startKey MVCCKey) {
 	i.advance()
 }
 
+// hasRangeTombstonesInTimeRange returns whether there are range tombstones in
+// the time range of the iterator overlapping the keys from the given one on.
+func (i *MVCCIncrementalIterator) hasRangeTombstonesInTimeRange(key roachpb.Key) (bool, error) {
+	endKey := i.endKey
+	if len(endKey) == 0 {
+		endKey = roachpb.KeyMax
+	}
+	if key.Compare(endKey) >= 0 {
+		return false, nil
+	}
+	rangeKeys, err := MVCCScanRangeTombstones(i.reader, key, endKey)
+	if err != nil {
+		return false, err
+	}
+	for _, rangeKey := range rangeKeys {
+		if i.startTime.Less(rangeKey.Timestamp) && rangeKey.Timestamp.LessEq(i.endTime) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // Close frees up resources held by the iterator.
 func (i *MVCCIncrementalIterator) Close() {
 	i.iter.Close()
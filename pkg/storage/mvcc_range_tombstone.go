@@ -0,0 +1,750 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+// An MVCC range tombstone deletes all the keys of a span at a timestamp with
+// a single write, while the deleted versions remain visible to reads below
+// that timestamp, exactly as if a point deletion tombstone had been written
+// for each of the keys.
+//
+// Range tombstones are stored as fragments in the replicated range-local
+// keyspace under keys.LocalMVCCRangeTombstonePrefix. The fragment starting at
+// key k holds a version k@T for each range tombstone at timestamp T covering
+// k, the value of which is the end key of that range tombstone. The range
+// tombstones covering a key are thus the versions, with an end key past the
+// key, of the fragment with the greatest start key at or below it. Fragments
+// do not straddle the bounds of a range: the split trigger truncates them at
+// the split key.
+//
+// Iterators over the MVCC keyspace (MVCCKeyAndIntentsIterKind) expose range
+// tombstones as point deletion tombstones, synthesized for each of the keys
+// which have versions of their own (see mvccRangeTombstoneIter). The MVCC
+// stats follow the same view, i.e. the synthesized tombstones are accounted
+// for like point tombstones, and the fragments count as system data.
+
+// MVCCRangeKey is a versioned key span.
+type MVCCRangeKey struct {
+	StartKey  roachpb.Key
+	EndKey    roachpb.Key
+	Timestamp hlc.Timestamp
+}
+
+// String implements the fmt.Stringer interface.
+func (k MVCCRangeKey) String() string {
+	return fmt.Sprintf("%s/%s", roachpb.Span{Key: k.StartKey, EndKey: k.EndKey}, k.Timestamp)
+}
+
+// rangeTombstoneVersion is a version of a range tombstone fragment, i.e. one
+// of the range tombstones covering the start key of the fragment.
+type rangeTombstoneVersion struct {
+	timestamp hlc.Timestamp
+	endKey    roachpb.Key
+}
+
+// rangeTombstoneFragment is a fragment of the range tombstones. Its versions
+// are ordered from the newest to the oldest.
+type rangeTombstoneFragment struct {
+	startKey roachpb.Key
+	versions []rangeTombstoneVersion
+}
+
+// covering returns the versions of the fragment which cover the given key.
+// The key must not sort before the start key of the fragment.
+func (f *rangeTombstoneFragment) covering(key roachpb.Key) []rangeTombstoneVersion {
+	var res []rangeTombstoneVersion
+	for _, v := range f.versions {
+		if key.Compare(v.endKey) < 0 {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// rangeTombstoneFragmentSysStats returns the contribution to SysBytes and
+// SysCount of a fragment with the given versions, as computed by
+// ComputeStatsForRange.
+func rangeTombstoneFragmentSysStats(
+	startKey roachpb.Key, versions []rangeTombstoneVersion,
+) (sysBytes, sysCount int64) {
+	if len(versions) == 0 {
+		return 0, 0
+	}
+	sysBytes = int64(len(keys.MVCCRangeTombstoneKey(startKey))) + 1
+	for _, v := range versions {
+		sysBytes += int64(len(v.endKey)) + MVCCVersionTimestampSize
+	}
+	return sysBytes, 1
+}
+
+// rangeTombstoneFragmentIter iterates over the fragments of the range
+// tombstones. The iteration must not change direction after a seek.
+type rangeTombstoneFragmentIter struct {
+	iter EngineIterator
+	// iterValid is the validity of iter, which is positioned past the current
+	// fragment in the direction of the iteration.
+	iterValid bool
+	valid     bool
+	err       error
+	fragment  rangeTombstoneFragment
+}
+
+func newRangeTombstoneFragmentIter(reader Reader) *rangeTombstoneFragmentIter {
+	return &rangeTombstoneFragmentIter{
+		iter: reader.NewEngineIterator(IterOptions{
+			LowerBound: keys.LocalMVCCRangeTombstonePrefix,
+			UpperBound: keys.LocalMVCCRangeTombstoneMax,
+		}),
+	}
+}
+
+func (it *rangeTombstoneFragmentIter) close() {
+	it.iter.Close()
+}
+
+// seekGE positions the iterator at the first fragment starting at or after
+// the given key.
+func (it *rangeTombstoneFragmentIter) seekGE(key roachpb.Key) {
+	valid, err := it.iter.SeekEngineKeyGE(EngineKey{Key: keys.MVCCRangeTombstoneKey(key)})
+	it.read(valid, err, true /* forward */)
+}
+
+// seekLE positions the iterator at the last fragment starting at or before
+// the given key, which is the one holding the range tombstones covering it.
+func (it *rangeTombstoneFragmentIter) seekLE(key roachpb.Key) {
+	valid, err := it.iter.SeekEngineKeyLT(EngineKey{Key: keys.MVCCRangeTombstoneKey(key.Next())})
+	it.read(valid, err, false /* forward */)
+}
+
+// seekLT positions the iterator at the last fragment starting before the
+// given key.
+func (it *rangeTombstoneFragmentIter) seekLT(key roachpb.Key) {
+	valid, err := it.iter.SeekEngineKeyLT(EngineKey{Key: keys.MVCCRangeTombstoneKey(key)})
+	it.read(valid, err, false /* forward */)
+}
+
+// next moves the iterator to the next fragment, after a seekGE.
+func (it *rangeTombstoneFragmentIter) next() {
+	it.read(it.iterValid, nil, true /* forward */)
+}
+
+// prev moves the iterator to the previous fragment, after a seekLE or seekLT.
+func (it *rangeTombstoneFragmentIter) prev() {
+	it.read(it.iterValid, nil, false /* forward */)
+}
+
+// overlaps returns whether any range tombstone overlaps the span [lower,
+// upper), where nil bounds are unbounded. It leaves the iterator positioned
+// at the last fragment starting before upper.
+func (it *rangeTombstoneFragmentIter) overlaps(lower, upper roachpb.Key) (bool, error) {
+	if upper == nil {
+		upper = roachpb.KeyMax
+	}
+	it.seekLT(upper)
+	if !it.valid {
+		return false, it.err
+	}
+	// The range tombstones overlapping the span either start in it, or
+	// cover its start key and are thus held by the fragment starting last
+	// before it.
+	if lower == nil || it.fragment.startKey.Compare(lower) >= 0 {
+		return true, nil
+	}
+	return len(it.fragment.covering(lower)) > 0, nil
+}
+
+func (it *rangeTombstoneFragmentIter) read(valid bool, err error, forward bool) {
+	it.valid = false
+	it.fragment = rangeTombstoneFragment{}
+	var tombKey roachpb.Key
+	for valid && err == nil {
+		var engineKey EngineKey
+		if engineKey, err = it.iter.UnsafeEngineKey(); err != nil {
+			break
+		}
+		if tombKey == nil {
+			tombKey = append(roachpb.Key(nil), engineKey.Key...)
+		} else if !engineKey.Key.Equal(tombKey) {
+			break
+		}
+		var key MVCCKey
+		if key, err = engineKey.ToMVCCKey(); err != nil {
+			break
+		}
+		it.fragment.versions = append(it.fragment.versions, rangeTombstoneVersion{
+			timestamp: key.Timestamp,
+			endKey:    append(roachpb.Key(nil), it.iter.UnsafeValue()...),
+		})
+		if forward {
+			valid, err = it.iter.NextEngineKey()
+		} else {
+			valid, err = it.iter.PrevEngineKey()
+		}
+	}
+	it.iterValid, it.err = valid, err
+	if err != nil || tombKey == nil {
+		return
+	}
+	if it.fragment.startKey, it.err = keys.DecodeMVCCRangeTombstoneKey(tombKey); it.err != nil {
+		return
+	}
+	if !forward {
+		versions := it.fragment.versions
+		for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+			versions[i], versions[j] = versions[j], versions[i]
+		}
+	}
+	it.valid = true
+}
+
+// coveringRangeTombstones returns the versions of the range tombstones
+// covering the given key, from the newest to the oldest.
+func coveringRangeTombstones(reader Reader, key roachpb.Key) ([]rangeTombstoneVersion, error) {
+	it := newRangeTombstoneFragmentIter(reader)
+	defer it.close()
+	it.seekLE(key)
+	if !it.valid {
+		return nil, it.err
+	}
+	return it.fragment.covering(key), nil
+}
+
+// scanRangeTombstoneFragments returns the fragments starting in the span
+// [startKey, endKey).
+func scanRangeTombstoneFragments(
+	reader Reader, startKey, endKey roachpb.Key,
+) ([]rangeTombstoneFragment, error) {
+	it := newRangeTombstoneFragmentIter(reader)
+	defer it.close()
+	var fragments []rangeTombstoneFragment
+	for it.seekGE(startKey); it.valid && it.fragment.startKey.Compare(endKey) < 0; it.next() {
+		fragments = append(fragments, it.fragment)
+	}
+	return fragments, it.err
+}
+
+// updateRangeTombstoneFragment replaces the versions of the fragment starting
+// at the given key.
+func updateRangeTombstoneFragment(
+	w Writer, ms *enginepb.MVCCStats, startKey roachpb.Key, orig, updated []rangeTombstoneVersion,
+) error {
+	tombKey := keys.MVCCRangeTombstoneKey(startKey)
+	for _, o := range orig {
+		found := false
+		for _, u := range updated {
+			if o.timestamp == u.timestamp {
+				found = true
+				break
+			}
+		}
+		if !found {
+			if err := w.ClearMVCC(MVCCKey{Key: tombKey, Timestamp: o.timestamp}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, u := range updated {
+		unchanged := false
+		for _, o := range orig {
+			if o.timestamp == u.timestamp && o.endKey.Equal(u.endKey) {
+				unchanged = true
+				break
+			}
+		}
+		if !unchanged {
+			if err := w.PutMVCC(MVCCKey{Key: tombKey, Timestamp: u.timestamp}, u.endKey); err != nil {
+				return err
+			}
+		}
+	}
+	if ms != nil {
+		origBytes, origCount := rangeTombstoneFragmentSysStats(startKey, orig)
+		updatedBytes, updatedCount := rangeTombstoneFragmentSysStats(startKey, updated)
+		ms.SysBytes += updatedBytes - origBytes
+		ms.SysCount += updatedCount - origCount
+	}
+	return nil
+}
+
+// splitRangeTombstones makes sure that no fragment straddles the given key,
+// by starting a new fragment at it with the range tombstones covering it.
+func splitRangeTombstones(rw ReadWriter, ms *enginepb.MVCCStats, key roachpb.Key) error {
+	it := newRangeTombstoneFragmentIter(rw)
+	it.seekLE(key)
+	valid, err, fragment := it.valid, it.err, it.fragment
+	it.close()
+	if err != nil || !valid || fragment.startKey.Equal(key) {
+		return err
+	}
+	return updateRangeTombstoneFragment(rw, ms, key, nil /* orig */, fragment.covering(key))
+}
+
+// putRangeTombstone writes the range tombstone deleting the span [startKey,
+// endKey) at the given timestamp.
+func putRangeTombstone(
+	rw ReadWriter, ms *enginepb.MVCCStats, startKey, endKey roachpb.Key, timestamp hlc.Timestamp,
+) error {
+	if err := splitRangeTombstones(rw, ms, startKey); err != nil {
+		return err
+	}
+	fragments, err := scanRangeTombstoneFragments(rw, startKey, endKey)
+	if err != nil {
+		return err
+	}
+	if len(fragments) == 0 || !fragments[0].startKey.Equal(startKey) {
+		fragments = append([]rangeTombstoneFragment{{startKey: startKey}}, fragments...)
+	}
+	for _, f := range fragments {
+		updated := append([]rangeTombstoneVersion(nil), f.versions...)
+		updated = append(updated, rangeTombstoneVersion{timestamp: timestamp, endKey: endKey})
+		sort.Slice(updated, func(i, j int) bool {
+			return updated[j].timestamp.Less(updated[i].timestamp)
+		})
+		if err := updateRangeTombstoneFragment(rw, ms, f.startKey, f.versions, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearRangeTombstones removes the range tombstones matching the given
+// predicate from the span [startKey, endKey). The range tombstones which
+// extend beyond the span are truncated to exclude it.
+func clearRangeTombstones(
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	startKey, endKey roachpb.Key,
+	pred func(hlc.Timestamp) bool,
+) error {
+	if err := splitRangeTombstones(rw, ms, endKey); err != nil {
+		return err
+	}
+
+	// Truncate the range tombstones which start before the span. These are
+	// found in the run of fragments preceding it which cover its start key.
+	var truncated []rangeTombstoneFragment
+	it := newRangeTombstoneFragmentIter(rw)
+	for it.seekLT(startKey); it.valid; it.prev() {
+		if len(it.fragment.covering(startKey)) == 0 {
+			break
+		}
+		truncated = append(truncated, it.fragment)
+	}
+	err := it.err
+	it.close()
+	if err != nil {
+		return err
+	}
+	for _, f := range truncated {
+		updated := make([]rangeTombstoneVersion, len(f.versions))
+		for i, v := range f.versions {
+			updated[i] = v
+			if pred(v.timestamp) && startKey.Compare(v.endKey) < 0 {
+				updated[i].endKey = startKey
+			}
+		}
+		if err := updateRangeTombstoneFragment(rw, ms, f.startKey, f.versions, updated); err != nil {
+			return err
+		}
+	}
+
+	fragments, err := scanRangeTombstoneFragments(rw, startKey, endKey)
+	if err != nil {
+		return err
+	}
+	for _, f := range fragments {
+		var updated []rangeTombstoneVersion
+		for _, v := range f.versions {
+			if !pred(v.timestamp) {
+				updated = append(updated, v)
+			}
+		}
+		if err := updateRangeTombstoneFragment(rw, ms, f.startKey, f.versions, updated); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MVCCScanRangeTombstones returns the range tombstones overlapping the span
+// [startKey, endKey), truncated to the span. They are ordered by start key,
+// and then from the newest to the oldest.
+func MVCCScanRangeTombstones(
+	reader Reader, startKey, endKey roachpb.Key,
+) ([]MVCCRangeKey, error) {
+	// The range tombstones are reassembled from their fragments, each range
+	// tombstone (or what remains of it after truncations) being identified by
+	// its timestamp and end key.
+	type rangeTombstoneID struct {
+		timestamp hlc.Timestamp
+		endKey    string
+	}
+	seen := make(map[rangeTombstoneID]struct{})
+	var res []MVCCRangeKey
+	add := func(fragmentStart roachpb.Key, versions []rangeTombstoneVersion) {
+		if fragmentStart.Compare(startKey) < 0 {
+			fragmentStart = startKey
+		}
+		for _, v := range versions {
+			id := rangeTombstoneID{timestamp: v.timestamp, endKey: string(v.endKey)}
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			seen[id] = struct{}{}
+			rangeKey := MVCCRangeKey{StartKey: fragmentStart, EndKey: v.endKey, Timestamp: v.timestamp}
+			if endKey.Compare(rangeKey.EndKey) < 0 {
+				rangeKey.EndKey = endKey
+			}
+			res = append(res, rangeKey)
+		}
+	}
+
+	covering, err := coveringRangeTombstones(reader, startKey)
+	if err != nil {
+		return nil, err
+	}
+	add(startKey, covering)
+	fragments, err := scanRangeTombstoneFragments(reader, startKey.Next(), endKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range fragments {
+		add(f.startKey, f.versions)
+	}
+	return res, nil
+}
+
+// MVCCDeleteRangeUsingTombstone deletes the keys in the span [startKey,
+// endKey) at the given timestamp by writing an MVCC range tombstone. It is
+// not transactional: an error is returned if the span contains intents, or
+// keys or range tombstones at or above the timestamp. A logical op is logged
+// for each of the live keys deleted, for the benefit of rangefeeds.
+//
+// The span is scanned once, to check the keys and to compute the stats
+// update, which is that of writing a point deletion tombstone for each of the
+// keys with versions. Only the newest version of each key is visited.
+func MVCCDeleteRangeUsingTombstone(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	startKey, endKey roachpb.Key,
+	timestamp hlc.Timestamp,
+) error {
+	if timestamp.IsEmpty() {
+		return errors.Errorf("cannot write range tombstone over %s without a timestamp",
+			roachpb.Span{Key: startKey, EndKey: endKey})
+	}
+	if len(startKey) == 0 || startKey.Compare(endKey) >= 0 {
+		return errors.Errorf("invalid range tombstone span %s", roachpb.Span{Key: startKey, EndKey: endKey})
+	}
+
+	// The versions of a fragment are ordered from the newest to the oldest, so
+	// only the first one of each fragment needs to be checked.
+	var tooOldTimestamp hlc.Timestamp
+	fragmentIter := newRangeTombstoneFragmentIter(rw)
+	fragmentIter.seekLE(startKey)
+	if fragmentIter.valid {
+		if covering := fragmentIter.fragment.covering(startKey); len(covering) > 0 &&
+			timestamp.LessEq(covering[0].timestamp) {
+			tooOldTimestamp.Forward(covering[0].timestamp)
+		}
+	}
+	if fragmentIter.err == nil {
+		for fragmentIter.seekGE(startKey.Next()); fragmentIter.valid &&
+			fragmentIter.fragment.startKey.Compare(endKey) < 0; fragmentIter.next() {
+			if versions := fragmentIter.fragment.versions; timestamp.LessEq(versions[0].timestamp) {
+				tooOldTimestamp.Forward(versions[0].timestamp)
+			}
+		}
+	}
+	err := fragmentIter.err
+	fragmentIter.close()
+	if err != nil {
+		return err
+	}
+
+	// The range tombstone shows up as a point deletion tombstone at the given
+	// timestamp on each of the keys with versions, including the synthesized
+	// tombstones of older range tombstones. The logical ops are logged before
+	// all the keys have been checked, which is fine since the batch is
+	// discarded on error.
+	var delta enginepb.MVCCStats
+	var meta enginepb.MVCCMetadata
+	iter := rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{
+		LowerBound: startKey,
+		UpperBound: endKey,
+	})
+	defer iter.Close()
+	for iter.SeekGE(MakeMVCCMetadataKey(startKey)); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if !key.IsValue() {
+			if err := iter.ValueProto(&meta); err != nil {
+				return errors.Wrap(err, "unable to decode MVCCMetadata")
+			}
+			if meta.IsInline() {
+				return errors.Errorf("cannot delete inline value %q with a range tombstone", key.Key)
+			}
+			return &roachpb.WriteIntentError{Intents: []roachpb.Intent{
+				roachpb.MakeIntent(meta.Txn, append(roachpb.Key(nil), key.Key...)),
+			}}
+		}
+		if timestamp.LessEq(key.Timestamp) {
+			tooOldTimestamp.Forward(key.Timestamp)
+			continue
+		}
+		if !tooOldTimestamp.IsEmpty() {
+			// The stats update and logical ops are moot.
+			continue
+		}
+		valSize := int64(len(iter.UnsafeValue()))
+		if ms != nil {
+			metaKeySize := int64(MakeMVCCMetadataKey(key.Key).EncodedSize())
+			orig := enginepb.MVCCMetadata{
+				Timestamp: key.Timestamp.ToLegacyTimestamp(),
+				KeyBytes:  MVCCVersionTimestampSize,
+				ValBytes:  valSize,
+				Deleted:   valSize == 0,
+			}
+			tombstone := enginepb.MVCCMetadata{
+				Timestamp: timestamp.ToLegacyTimestamp(),
+				KeyBytes:  MVCCVersionTimestampSize,
+				Deleted:   true,
+			}
+			delta.Add(updateStatsOnPut(key.Key, 0 /* prevValSize */, metaKeySize, 0, /* origMetaValSize */
+				metaKeySize, 0 /* metaValSize */, &orig, &tombstone))
+		}
+		if valSize > 0 {
+			rw.LogLogicalOp(MVCCWriteValueOpType, MVCCLogicalOpDetails{
+				Key:       append(roachpb.Key(nil), key.Key...),
+				Timestamp: timestamp,
+				Safe:      true,
+			})
+		}
+	}
+	if !tooOldTimestamp.IsEmpty() {
+		return roachpb.NewWriteTooOldError(timestamp, tooOldTimestamp.Next())
+	}
+
+	if err := putRangeTombstone(rw, ms, startKey, endKey, timestamp); err != nil {
+		return err
+	}
+	if ms != nil {
+		ms.Add(delta)
+	}
+	return nil
+}
+
+// materializeRangeTombstones writes a point deletion tombstone for each of the
+// range tombstones covering a key without versions of its own, which the
+// iterator therefore does not expose. It is called before writing a version
+// of such a key, which would otherwise expose range tombstones newer than
+// the write and leave the write deleted. On success, meta and the returned
+// sizes are those of the newest tombstone, as mvccGetMetadata would return
+// them.
+func materializeRangeTombstones(
+	writer Writer,
+	iter MVCCIterator,
+	ms *enginepb.MVCCStats,
+	metaKey MVCCKey,
+	meta *enginepb.MVCCMetadata,
+) (ok bool, metaKeySize, metaValSize int64, err error) {
+	timestamps, err := iter.RangeTombstonesAt(metaKey.Key)
+	if err != nil || len(timestamps) == 0 {
+		return false, 0, 0, err
+	}
+	var orig *enginepb.MVCCMetadata
+	var origMetaKeySize int64
+	metaKeySize = int64(metaKey.EncodedSize())
+	for j := len(timestamps) - 1; j >= 0; j-- {
+		ts := timestamps[j]
+		if err := writer.PutMVCC(MVCCKey{Key: metaKey.Key, Timestamp: ts}, nil); err != nil {
+			return false, 0, 0, err
+		}
+		tombstone := &enginepb.MVCCMetadata{
+			Timestamp: ts.ToLegacyTimestamp(),
+			KeyBytes:  MVCCVersionTimestampSize,
+			Deleted:   true,
+		}
+		if ms != nil {
+			ms.Add(updateStatsOnPut(metaKey.Key, 0 /* prevValSize */, origMetaKeySize, 0, /* origMetaValSize */
+				metaKeySize, 0 /* metaValSize */, orig, tombstone))
+		}
+		orig, origMetaKeySize = tombstone, metaKeySize
+	}
+	*meta = *orig
+	return true, metaKeySize, 0, nil
+}
+
+// MVCCSplitRangeTombstones splits the fragments of the range tombstones at the
+// given key, so that none of them straddles it. It is used when splitting a
+// range, which must not hold fragments extending beyond its bounds.
+func MVCCSplitRangeTombstones(rw ReadWriter, ms *enginepb.MVCCStats, key roachpb.Key) error {
+	return clearRangeTombstones(rw, ms, key, key, func(hlc.Timestamp) bool { return true })
+}
+
+// MVCCClearRangeTombstones removes all the range tombstones from the span
+// [startKey, endKey), without regard for the keys they delete. Like
+// ClearMVCCRange, this is not an MVCC operation, and the caller is
+// responsible for the stats of the keys in the span.
+func MVCCClearRangeTombstones(
+	rw ReadWriter, ms *enginepb.MVCCStats, startKey, endKey roachpb.Key,
+) error {
+	return clearRangeTombstones(rw, ms, startKey, endKey, func(hlc.Timestamp) bool { return true })
+}
+
+// MVCCGarbageCollectRangeKeys garbage collects the given range tombstones,
+// along with all the versions they shadow, i.e. the versions at or below
+// their timestamp of the keys in their span, and the older range tombstones
+// overlapping them. The timestamp parameter is used to compute the stats
+// update.
+//
+// Computing the stats update requires scanning the spans.
+func MVCCGarbageCollectRangeKeys(
+	ctx context.Context,
+	rw ReadWriter,
+	ms *enginepb.MVCCStats,
+	rangeKeys []MVCCRangeKey,
+	timestamp hlc.Timestamp,
+) error {
+	for _, rangeKey := range rangeKeys {
+		computeStats := func() (enginepb.MVCCStats, error) {
+			iter := rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{
+				LowerBound: rangeKey.StartKey,
+				UpperBound: rangeKey.EndKey,
+			})
+			defer iter.Close()
+			return ComputeStatsForRange(iter, rangeKey.StartKey, rangeKey.EndKey, timestamp.WallTime)
+		}
+		var before enginepb.MVCCStats
+		if ms != nil {
+			var err error
+			if before, err = computeStats(); err != nil {
+				return err
+			}
+		}
+
+		if err := clearShadowedVersions(rw, rangeKey, false /* prefix */); err != nil {
+			return err
+		}
+		if err := clearRangeTombstones(rw, ms, rangeKey.StartKey, rangeKey.EndKey,
+			func(ts hlc.Timestamp) bool { return ts.LessEq(rangeKey.Timestamp) },
+		); err != nil {
+			return err
+		}
+
+		if ms != nil {
+			after, err := computeStats()
+			if err != nil {
+				return err
+			}
+			after.Subtract(before)
+			ms.Add(after)
+		}
+	}
+	return nil
+}
+
+// clearShadowedVersions clears the versions at or below the timestamp of the
+// given range tombstone of the keys in its span. A prefix iterator is used if
+// the span holds a single key and prefix is set.
+func clearShadowedVersions(rw ReadWriter, rangeKey MVCCRangeKey, prefix bool) error {
+	// The versions are collected before clearing them, since the iterator
+	// must not observe its own writes.
+	var shadowed []MVCCKey
+	iter := rw.NewMVCCIterator(MVCCKeyIterKind, IterOptions{
+		Prefix:     prefix,
+		LowerBound: rangeKey.StartKey,
+		UpperBound: rangeKey.EndKey,
+	})
+	for iter.SeekGE(MakeMVCCMetadataKey(rangeKey.StartKey)); ; {
+		ok, err := iter.Valid()
+		if err != nil {
+			iter.Close()
+			return err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if !key.IsValue() {
+			iter.Next()
+			continue
+		}
+		if rangeKey.Timestamp.Less(key.Timestamp) {
+			iter.SeekGE(MVCCKey{Key: key.Key, Timestamp: rangeKey.Timestamp})
+			continue
+		}
+		shadowed = append(shadowed, iter.Key())
+		iter.Next()
+	}
+	iter.Close()
+	for _, key := range shadowed {
+		if err := rw.ClearMVCC(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// garbageCollectCoveredKey garbage collects the versions at or below the
+// given timestamp of a key covered by range tombstones, on behalf of
+// MVCCGarbageCollect. The deletion tombstones synthesized for the range
+// tombstones are only removed along with the range tombstones themselves, and
+// cease to exist once the key has no versions left, so the stats of the key
+// are recomputed rather than updated version by version. Prefix iterators are
+// used, since MVCCGarbageCollect holds a regular one.
+func garbageCollectCoveredKey(
+	rw ReadWriter, ms *enginepb.MVCCStats, key roachpb.Key, gcTimestamp, timestamp hlc.Timestamp,
+) error {
+	computeStats := func() (enginepb.MVCCStats, error) {
+		iter := rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{Prefix: true})
+		defer iter.Close()
+		return ComputeStatsForRange(iter, key, key.Next(), timestamp.WallTime)
+	}
+	var before enginepb.MVCCStats
+	if ms != nil {
+		var err error
+		if before, err = computeStats(); err != nil {
+			return err
+		}
+	}
+	if err := clearShadowedVersions(rw, MVCCRangeKey{
+		StartKey:  key,
+		EndKey:    key.Next(),
+		Timestamp: gcTimestamp,
+	}, true /* prefix */); err != nil {
+		return err
+	}
+	if ms != nil {
+		after, err := computeStats()
+		if err != nil {
+			return err
+		}
+		after.Subtract(before)
+		ms.Add(after)
+	}
+	return nil
+}
@@ -0,0 +1,481 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/pebble"
+)
+
+// mvccRangeTombstoneIter wraps an MVCCIterator, exposing the MVCC range
+// tombstones as point deletion tombstones. For each global key which has
+// versions of its own, a deletion tombstone is synthesized at the timestamp
+// of each range tombstone covering the key, unless the key already has a
+// version at that timestamp. Keys without versions are not affected, which
+// bounds the cost of range tombstones to the data they delete.
+//
+// The entries of the keys which are not covered by range tombstones are
+// passed through from the wrapped iterator. The entries of a covered key are
+// instead buffered, merged with the synthesized tombstones, in which case
+// the wrapped iterator is positioned past the key in the forward direction.
+// The fragment of the range tombstones holding the current key is cached.
+type mvccRangeTombstoneIter struct {
+	iter MVCCIterator
+
+	// handle is used to create the iterator over the fragments when it is
+	// first needed. cachedFragmentIter is reused for it if it is not nil and
+	// not in use.
+	handle             pebble.Reader
+	cachedFragmentIter *pebbleIterator
+	fragmentIter       *rangeTombstoneFragmentIter
+
+	// The cached fragment holds the range tombstones of the keys in
+	// [fragmentStart, fragmentEnd), where nil bounds are unbounded.
+	fragmentCached bool
+	fragmentStart  roachpb.Key
+	fragmentEnd    roachpb.Key
+	fragment       rangeTombstoneFragment
+
+	// buffered is set when the iterator is positioned at the entries[pos]
+	// entry of a covered key.
+	buffered bool
+	entries  []rangeTombstoneIterEntry
+	pos      int
+	err      error
+}
+
+// rangeTombstoneIterEntry is a buffered entry of mvccRangeTombstoneIter.
+type rangeTombstoneIterEntry struct {
+	key    MVCCKey
+	rawKey []byte
+	value  []byte
+}
+
+var _ MVCCIterator = &mvccRangeTombstoneIter{}
+
+func newMVCCRangeTombstoneIter(
+	iter MVCCIterator, handle pebble.Reader, cachedFragmentIter *pebbleIterator,
+) *mvccRangeTombstoneIter {
+	return &mvccRangeTombstoneIter{
+		iter:               iter,
+		handle:             handle,
+		cachedFragmentIter: cachedFragmentIter,
+	}
+}
+
+// maybeWrapRangeTombstoneIter wraps the given iterator such that it exposes
+// the MVCC range tombstones, if it is of the MVCCKeyAndIntentsIterKind and
+// range tombstones overlap the bounds it was created with. Iterators are thus
+// only wrapped when there is something to expose, at the cost of a seek over
+// the fragments. The iterator over the fragments is created from the given
+// handle, reusing cachedFragmentIter if it is not nil.
+func maybeWrapRangeTombstoneIter(
+	iterKind MVCCIterKind,
+	iter MVCCIterator,
+	opts IterOptions,
+	handle pebble.Reader,
+	cachedFragmentIter *pebbleIterator,
+) MVCCIterator {
+	if iterKind != MVCCKeyAndIntentsIterKind {
+		return iter
+	}
+	fragmentIter := newCachedRangeTombstoneFragmentIter(handle, cachedFragmentIter)
+	// Errors are returned by the wrapper, which seeks the fragments again.
+	if overlaps, err := fragmentIter.overlaps(opts.LowerBound, opts.UpperBound); err == nil && !overlaps {
+		fragmentIter.close()
+		return iter
+	}
+	wrapped := newMVCCRangeTombstoneIter(iter, handle, cachedFragmentIter)
+	wrapped.fragmentIter = fragmentIter
+	return wrapped
+}
+
+// newCachedRangeTombstoneFragmentIter returns an iterator over the fragments
+// of the range tombstones created from the given handle, reusing
+// cachedFragmentIter if it is not nil and not in use.
+func newCachedRangeTombstoneFragmentIter(
+	handle pebble.Reader, cachedFragmentIter *pebbleIterator,
+) *rangeTombstoneFragmentIter {
+	opts := IterOptions{
+		LowerBound: keys.LocalMVCCRangeTombstonePrefix,
+		UpperBound: keys.LocalMVCCRangeTombstoneMax,
+	}
+	if c := cachedFragmentIter; c != nil && !c.inuse {
+		if c.iter != nil {
+			c.setOptions(opts)
+		} else {
+			c.init(handle, opts)
+			c.reusable = true
+		}
+		c.inuse = true
+		return &rangeTombstoneFragmentIter{iter: c}
+	}
+	return &rangeTombstoneFragmentIter{iter: newPebbleIterator(handle, opts)}
+}
+
+// Close implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Close() {
+	i.iter.Close()
+	if i.fragmentIter != nil {
+		i.fragmentIter.close()
+	}
+}
+
+// loadFragment caches the fragment holding the range tombstones of the given
+// key.
+func (i *mvccRangeTombstoneIter) loadFragment(key roachpb.Key) error {
+	if i.fragmentIter == nil {
+		i.fragmentIter = newCachedRangeTombstoneFragmentIter(i.handle, i.cachedFragmentIter)
+	}
+	i.fragmentCached = false
+	i.fragmentIter.seekLE(key)
+	if i.fragmentIter.err != nil {
+		return i.fragmentIter.err
+	}
+	i.fragmentStart, i.fragment = nil, rangeTombstoneFragment{}
+	if i.fragmentIter.valid {
+		i.fragmentStart, i.fragment = i.fragmentIter.fragment.startKey, i.fragmentIter.fragment
+	}
+	i.fragmentIter.seekGE(key.Next())
+	if i.fragmentIter.err != nil {
+		return i.fragmentIter.err
+	}
+	i.fragmentEnd = nil
+	if i.fragmentIter.valid {
+		i.fragmentEnd = i.fragmentIter.fragment.startKey
+	}
+	i.fragmentCached = true
+	return nil
+}
+
+// rangeTombstonesAt returns the versions of the range tombstones covering
+// the given key.
+func (i *mvccRangeTombstoneIter) rangeTombstonesAt(
+	key roachpb.Key,
+) ([]rangeTombstoneVersion, error) {
+	if isSysLocal(key) {
+		return nil, nil
+	}
+	if !i.fragmentCached ||
+		(i.fragmentStart != nil && key.Compare(i.fragmentStart) < 0) ||
+		(i.fragmentEnd != nil && key.Compare(i.fragmentEnd) >= 0) {
+		if err := i.loadFragment(key); err != nil {
+			return nil, err
+		}
+	}
+	if len(i.fragment.versions) == 0 {
+		return nil, nil
+	}
+	return i.fragment.covering(key), nil
+}
+
+// load buffers the entries of the given key, merged with the synthesized
+// deletion tombstones, leaving the wrapped iterator positioned past them.
+// Returns false if the key has no entries.
+func (i *mvccRangeTombstoneIter) load(key roachpb.Key, versions []rangeTombstoneVersion) bool {
+	i.buffered = false
+	i.entries = i.entries[:0]
+	hasVersions := false
+	for i.iter.SeekGE(MakeMVCCMetadataKey(key)); ; i.iter.Next() {
+		ok, err := i.iter.Valid()
+		if err != nil {
+			i.err = err
+			return false
+		}
+		if !ok {
+			break
+		}
+		unsafeKey := i.iter.UnsafeKey()
+		if !unsafeKey.Key.Equal(key) {
+			break
+		}
+		hasVersions = hasVersions || unsafeKey.IsValue()
+		i.entries = append(i.entries, rangeTombstoneIterEntry{
+			key: MVCCKey{
+				Key:       append(roachpb.Key(nil), unsafeKey.Key...),
+				Timestamp: unsafeKey.Timestamp,
+			},
+			rawKey: append([]byte(nil), i.iter.UnsafeRawKey()...),
+			value:  append([]byte(nil), i.iter.UnsafeValue()...),
+		})
+	}
+	if len(i.entries) == 0 {
+		return false
+	}
+	if hasVersions {
+		n := len(i.entries)
+		for _, v := range versions {
+			exists := false
+			for _, e := range i.entries[:n] {
+				if e.key.Timestamp == v.timestamp {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				synthesized := MVCCKey{Key: i.entries[0].key.Key, Timestamp: v.timestamp}
+				i.entries = append(i.entries, rangeTombstoneIterEntry{
+					key:    synthesized,
+					rawKey: EncodeKey(synthesized),
+				})
+			}
+		}
+		if len(i.entries) > n {
+			sort.SliceStable(i.entries, func(a, b int) bool {
+				return i.entries[a].key.Less(i.entries[b].key)
+			})
+		}
+	}
+	i.buffered = true
+	return true
+}
+
+// settle is called after the wrapped iterator moved to an entry in the
+// given direction, and buffers the entries of its key if it is covered.
+func (i *mvccRangeTombstoneIter) settle(forward bool) {
+	i.buffered = false
+	if ok, err := i.iter.Valid(); !ok || err != nil {
+		return
+	}
+	unsafeKey := i.iter.UnsafeKey().Key
+	versions, err := i.rangeTombstonesAt(unsafeKey)
+	if err != nil {
+		i.err = err
+		return
+	}
+	if len(versions) == 0 {
+		return
+	}
+	if !i.load(append(roachpb.Key(nil), unsafeKey...), versions) {
+		return
+	}
+	if forward {
+		i.pos = 0
+	} else {
+		i.pos = len(i.entries) - 1
+	}
+}
+
+// SeekGE implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) SeekGE(key MVCCKey) {
+	i.buffered, i.err = false, nil
+	versions, err := i.rangeTombstonesAt(key.Key)
+	if err != nil {
+		i.err = err
+		return
+	}
+	if len(versions) == 0 {
+		i.iter.SeekGE(key)
+		i.settle(true /* forward */)
+		return
+	}
+	// The wrapped iterator may skip the key if it only has versions above the
+	// seek key, so the key is loaded in any case.
+	if i.load(key.Key, versions) {
+		for i.pos = 0; i.pos < len(i.entries) && i.entries[i.pos].key.Less(key); i.pos++ {
+		}
+		if i.pos < len(i.entries) {
+			return
+		}
+	}
+	if i.err == nil {
+		i.settle(true /* forward */)
+	}
+}
+
+// SeekLT implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) SeekLT(key MVCCKey) {
+	i.buffered, i.err = false, nil
+	versions, err := i.rangeTombstonesAt(key.Key)
+	if err != nil {
+		i.err = err
+		return
+	}
+	if len(versions) == 0 {
+		i.iter.SeekLT(key)
+		i.settle(false /* forward */)
+		return
+	}
+	if i.load(key.Key, versions) {
+		for i.pos = len(i.entries) - 1; i.pos >= 0 && !i.entries[i.pos].key.Less(key); i.pos-- {
+		}
+		if i.pos >= 0 {
+			return
+		}
+	}
+	if i.err == nil {
+		i.buffered = false
+		i.iter.SeekLT(MakeMVCCMetadataKey(key.Key))
+		i.settle(false /* forward */)
+	}
+}
+
+// Valid implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Valid() (bool, error) {
+	if i.err != nil {
+		return false, i.err
+	}
+	if i.buffered {
+		return true, nil
+	}
+	return i.iter.Valid()
+}
+
+// Next implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Next() {
+	if i.buffered {
+		if i.pos++; i.pos < len(i.entries) {
+			return
+		}
+	} else {
+		i.iter.Next()
+	}
+	i.settle(true /* forward */)
+}
+
+// NextKey implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) NextKey() {
+	if !i.buffered {
+		i.iter.NextKey()
+	}
+	i.settle(true /* forward */)
+}
+
+// Prev implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Prev() {
+	if i.buffered {
+		if i.pos--; i.pos >= 0 {
+			return
+		}
+		i.iter.SeekLT(MakeMVCCMetadataKey(i.entries[0].key.Key))
+	} else {
+		i.iter.Prev()
+	}
+	i.settle(false /* forward */)
+}
+
+// UnsafeKey implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) UnsafeKey() MVCCKey {
+	if i.buffered {
+		return i.entries[i.pos].key
+	}
+	return i.iter.UnsafeKey()
+}
+
+// Key implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Key() MVCCKey {
+	if i.buffered {
+		// The buffered entries are not reused.
+		return i.entries[i.pos].key
+	}
+	return i.iter.Key()
+}
+
+// UnsafeRawKey implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) UnsafeRawKey() []byte {
+	if i.buffered {
+		return i.entries[i.pos].rawKey
+	}
+	return i.iter.UnsafeRawKey()
+}
+
+// UnsafeRawMVCCKey implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) UnsafeRawMVCCKey() []byte {
+	if i.buffered {
+		return i.entries[i.pos].rawKey
+	}
+	return i.iter.UnsafeRawMVCCKey()
+}
+
+// UnsafeValue implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) UnsafeValue() []byte {
+	if i.buffered {
+		return i.entries[i.pos].value
+	}
+	return i.iter.UnsafeValue()
+}
+
+// Value implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Value() []byte {
+	if i.buffered {
+		return i.entries[i.pos].value
+	}
+	return i.iter.Value()
+}
+
+// ValueProto implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) ValueProto(msg protoutil.Message) error {
+	return protoutil.Unmarshal(i.UnsafeValue(), msg)
+}
+
+// IsCurIntentSeparated implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) IsCurIntentSeparated() bool {
+	if i.buffered {
+		return false
+	}
+	return i.iter.IsCurIntentSeparated()
+}
+
+// ComputeStats implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) ComputeStats(
+	start, end roachpb.Key, nowNanos int64,
+) (enginepb.MVCCStats, error) {
+	return ComputeStatsForRange(i, start, end, nowNanos)
+}
+
+// FindSplitKey implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) FindSplitKey(
+	start, end, minSplitKey roachpb.Key, targetSize int64,
+) (MVCCKey, error) {
+	return findSplitKeyUsingIterator(i, start, end, minSplitKey, targetSize)
+}
+
+// CheckForKeyCollisions implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) CheckForKeyCollisions(
+	sstData []byte, start, end roachpb.Key,
+) (enginepb.MVCCStats, error) {
+	return checkForKeyCollisionsGo(i, sstData, start, end)
+}
+
+// SetUpperBound implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) SetUpperBound(key roachpb.Key) {
+	i.buffered = false
+	i.iter.SetUpperBound(key)
+}
+
+// Stats implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) Stats() IteratorStats {
+	return i.iter.Stats()
+}
+
+// SupportsPrev implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) SupportsPrev() bool {
+	return i.iter.SupportsPrev()
+}
+
+// RangeTombstonesAt implements the MVCCIterator interface.
+func (i *mvccRangeTombstoneIter) RangeTombstonesAt(key roachpb.Key) ([]hlc.Timestamp, error) {
+	versions, err := i.rangeTombstonesAt(key)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+	res := make([]hlc.Timestamp, len(versions))
+	for j, v := range versions {
+		res[j] = v.timestamp
+	}
+	return res, nil
+}
@@ -0,0 +1,335 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// assertRangeTombstoneStats checks that the given stats match the stats
+// recomputed over the whole engine, including the range tombstone fragments.
+func assertRangeTombstoneStats(
+	t *testing.T, rw ReadWriter, ms *enginepb.MVCCStats, nowNanos int64,
+) {
+	t.Helper()
+	iter := rw.NewMVCCIterator(MVCCKeyAndIntentsIterKind, IterOptions{UpperBound: roachpb.KeyMax})
+	defer iter.Close()
+	expMS, err := ComputeStatsForRange(iter, roachpb.KeyMin, roachpb.KeyMax, nowNanos)
+	require.NoError(t, err)
+	msCpy := *ms
+	msCpy.AgeTo(nowNanos)
+	require.Equal(t, expMS, msCpy)
+}
+
+// scanKeys returns the keys visible at the given timestamp.
+func scanKeys(t *testing.T, r Reader, ts hlc.Timestamp, reverse bool) []string {
+	t.Helper()
+	res, err := MVCCScan(context.Background(), r, localMax, keyMax, ts, MVCCScanOptions{Reverse: reverse})
+	require.NoError(t, err)
+	var keys []string
+	for _, kv := range res.KVs {
+		keys = append(keys, string(kv.Key))
+	}
+	return keys
+}
+
+func TestMVCCDeleteRangeUsingTombstone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	ms := &enginepb.MVCCStats{}
+	for _, key := range []string{"a", "b", "d"} {
+		require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key(key), ts(1), value1, nil))
+	}
+	require.NoError(t, MVCCDelete(ctx, engine, ms, roachpb.Key("b"), ts(1).Next(), nil))
+	require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key("c"), ts(3), value1, nil))
+
+	// Invalid spans and timestamps are rejected.
+	require.Error(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("b"), roachpb.Key("a"), ts(4)))
+	require.Error(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("a"), roachpb.Key("b"), hlc.Timestamp{}))
+
+	// Keys at or above the timestamp cause a WriteTooOldError.
+	err := MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("a"), roachpb.Key("e"), ts(3))
+	var wtoErr *roachpb.WriteTooOldError
+	require.True(t, errors.As(err, &wtoErr), "%v", err)
+	require.Equal(t, ts(3).Next(), wtoErr.ActualTimestamp)
+
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("a"), roachpb.Key("c"), ts(2)))
+	assertRangeTombstoneStats(t, engine, ms, 2)
+	require.Equal(t, []string{"a", "b", "d"}, scanKeys(t, engine, ts(1), false))
+	require.Equal(t, []string{"d", "b", "a"}, scanKeys(t, engine, ts(1), true))
+	require.Equal(t, []string{"d"}, scanKeys(t, engine, ts(2), false))
+	require.Equal(t, []string{"c", "d"}, scanKeys(t, engine, ts(3), false))
+	require.Equal(t, []string{"d", "c"}, scanKeys(t, engine, ts(3), true))
+
+	// The deletions are visible as point tombstones.
+	val, _, err := MVCCGet(ctx, engine, roachpb.Key("a"), ts(2), MVCCGetOptions{Tombstones: true})
+	require.NoError(t, err)
+	require.NotNil(t, val)
+	require.False(t, val.IsPresent())
+	require.Equal(t, ts(2), val.Timestamp)
+
+	// Overlapping range tombstones fragment each other.
+	err = MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("b"), roachpb.Key("e"), ts(2))
+	require.True(t, errors.As(err, &wtoErr), "%v", err)
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("b"), roachpb.Key("e"), ts(4)))
+	assertRangeTombstoneStats(t, engine, ms, 4)
+	rangeKeys, err := MVCCScanRangeTombstones(engine, localMax, keyMax)
+	require.NoError(t, err)
+	require.Equal(t, []MVCCRangeKey{
+		{StartKey: roachpb.Key("a"), EndKey: roachpb.Key("c"), Timestamp: ts(2)},
+		{StartKey: roachpb.Key("b"), EndKey: roachpb.Key("e"), Timestamp: ts(4)},
+	}, rangeKeys)
+	rangeKeys, err = MVCCScanRangeTombstones(engine, roachpb.Key("bb"), roachpb.Key("d"))
+	require.NoError(t, err)
+	require.Equal(t, []MVCCRangeKey{
+		{StartKey: roachpb.Key("bb"), EndKey: roachpb.Key("d"), Timestamp: ts(4)},
+		{StartKey: roachpb.Key("bb"), EndKey: roachpb.Key("c"), Timestamp: ts(2)},
+	}, rangeKeys)
+	require.Equal(t, []string{"c", "d"}, scanKeys(t, engine, ts(3), false))
+	require.Empty(t, scanKeys(t, engine, ts(4), false))
+
+	// Writes below a range tombstone are pushed above it, including the writes
+	// of keys which didn't exist.
+	for _, key := range []string{"c", "cc"} {
+		err = MVCCPut(ctx, engine, ms, roachpb.Key(key), ts(3), value2, nil)
+		require.True(t, errors.As(err, &wtoErr), "%v", err)
+		require.Equal(t, ts(4).Next(), wtoErr.ActualTimestamp)
+	}
+	assertRangeTombstoneStats(t, engine, ms, 5)
+	require.Equal(t, []string{"c", "cc"}, scanKeys(t, engine, ts(5), false))
+	require.Empty(t, scanKeys(t, engine, ts(4), false))
+
+	// Writes above it succeed.
+	require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key("dd"), ts(5), value2, nil))
+	assertRangeTombstoneStats(t, engine, ms, 5)
+	require.Equal(t, []string{"c", "cc", "dd"}, scanKeys(t, engine, ts(5), false))
+	require.Equal(t, []string{"dd", "cc", "c"}, scanKeys(t, engine, ts(5), true))
+
+	// Intents cause a WriteIntentError.
+	txn := makeTxn(*txn1, ts(6))
+	require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key("f"), txn.ReadTimestamp, value1, txn))
+	err = MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("e"), roachpb.Key("g"), ts(7))
+	var wiErr *roachpb.WriteIntentError
+	require.True(t, errors.As(err, &wiErr), "%v", err)
+}
+
+func TestMVCCSplitRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ts1, ts2 := hlc.Timestamp{WallTime: 1}, hlc.Timestamp{WallTime: 2}
+	ms := &enginepb.MVCCStats{}
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key(key), ts1, value1, nil))
+	}
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("a"), roachpb.Key("d"), ts2))
+
+	require.NoError(t, MVCCSplitRangeTombstones(engine, ms, roachpb.Key("b")))
+	assertRangeTombstoneStats(t, engine, ms, 2)
+	rangeKeys, err := MVCCScanRangeTombstones(engine, localMax, keyMax)
+	require.NoError(t, err)
+	require.Equal(t, []MVCCRangeKey{
+		{StartKey: roachpb.Key("a"), EndKey: roachpb.Key("b"), Timestamp: ts2},
+		{StartKey: roachpb.Key("b"), EndKey: roachpb.Key("d"), Timestamp: ts2},
+	}, rangeKeys)
+	require.Equal(t, []string{"a", "b", "c"}, scanKeys(t, engine, ts1, false))
+	require.Empty(t, scanKeys(t, engine, ts2, false))
+
+	// Clearing the range tombstones of one side leaves the other intact.
+	require.NoError(t, MVCCClearRangeTombstones(engine, ms, roachpb.Key("b"), roachpb.Key("d")))
+	require.Equal(t, []string{"b", "c"}, scanKeys(t, engine, ts2, false))
+}
+
+func TestMVCCClearTimeRangeWithRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	ms := &enginepb.MVCCStats{}
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key(key), ts(1), value1, nil))
+	}
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("a"), roachpb.Key("d"), ts(2)))
+	require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key("b"), ts(3), value2, nil))
+	require.Equal(t, []string{"b"}, scanKeys(t, engine, ts(3), false))
+
+	// Reverting the right half to ts1 undoes the deletion there.
+	resume, err := MVCCClearTimeRange(ctx, engine, ms, roachpb.Key("b"), roachpb.Key("d"), ts(1), ts(3), 10)
+	require.NoError(t, err)
+	require.Nil(t, resume)
+	assertRangeTombstoneStats(t, engine, ms, 3)
+	require.Equal(t, []string{"b", "c"}, scanKeys(t, engine, ts(3), false))
+	val, _, err := MVCCGet(ctx, engine, roachpb.Key("b"), ts(3), MVCCGetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, ts(1), val.Timestamp)
+	rangeKeys, err := MVCCScanRangeTombstones(engine, localMax, keyMax)
+	require.NoError(t, err)
+	require.Equal(t, []MVCCRangeKey{
+		{StartKey: roachpb.Key("a"), EndKey: roachpb.Key("b"), Timestamp: ts(2)},
+	}, rangeKeys)
+}
+
+func TestMVCCGarbageCollectRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	ms := &enginepb.MVCCStats{}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key(key), ts(1), value1, nil))
+	}
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("a"), roachpb.Key("c"), ts(2)))
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, ms, roachpb.Key("c"), roachpb.Key("e"), ts(3)))
+	require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key("a"), ts(4), value2, nil))
+	require.NoError(t, MVCCPut(ctx, engine, ms, roachpb.Key("c"), ts(4), value2, nil))
+	assertRangeTombstoneStats(t, engine, ms, 4)
+
+	// Garbage collecting a point version below a range tombstone leaves the
+	// range tombstone in place.
+	require.NoError(t, MVCCGarbageCollect(ctx, engine, ms, []roachpb.GCRequest_GCKey{
+		{Key: roachpb.Key("c"), Timestamp: ts(1)},
+	}, ts(5)))
+	assertRangeTombstoneStats(t, engine, ms, 5)
+	require.Equal(t, []string{"a", "c"}, scanKeys(t, engine, ts(4), false))
+	require.Equal(t, []string{"a", "b", "d"}, scanKeys(t, engine, ts(1), false))
+
+	// Garbage collecting a range tombstone removes the versions it shadows.
+	require.NoError(t, MVCCGarbageCollectRangeKeys(ctx, engine, ms, []MVCCRangeKey{
+		{StartKey: roachpb.Key("a"), EndKey: roachpb.Key("c"), Timestamp: ts(2)},
+	}, ts(5)))
+	assertRangeTombstoneStats(t, engine, ms, 5)
+	require.Equal(t, []string{"d"}, scanKeys(t, engine, ts(1), false))
+	require.Equal(t, []string{"a", "c"}, scanKeys(t, engine, ts(4), false))
+	rangeKeys, err := MVCCScanRangeTombstones(engine, localMax, keyMax)
+	require.NoError(t, err)
+	require.Equal(t, []MVCCRangeKey{
+		{StartKey: roachpb.Key("c"), EndKey: roachpb.Key("e"), Timestamp: ts(3)},
+	}, rangeKeys)
+}
+
+func TestMVCCIncrementalIteratorRangeTombstones(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ts := func(wallTime int64) hlc.Timestamp { return hlc.Timestamp{WallTime: wallTime} }
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, MVCCPut(ctx, engine, nil, roachpb.Key(key), ts(1), value1, nil))
+	}
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, nil, roachpb.Key("a"), roachpb.Key("c"), ts(2)))
+
+	for _, tbi := range []bool{false, true} {
+		iter := NewMVCCIncrementalIterator(engine, MVCCIncrementalIterOptions{
+			EnableTimeBoundIteratorOptimization: tbi,
+			EndKey:                              keyMax,
+			StartTime:                           ts(1),
+			EndTime:                             ts(2),
+		})
+		var deleted []string
+		for iter.SeekGE(MakeMVCCMetadataKey(localMax)); ; iter.Next() {
+			ok, err := iter.Valid()
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			require.Equal(t, ts(2), iter.UnsafeKey().Timestamp)
+			require.Empty(t, iter.UnsafeValue())
+			deleted = append(deleted, string(iter.UnsafeKey().Key))
+		}
+		iter.Close()
+		require.Equal(t, []string{"a", "b"}, deleted)
+	}
+}
+
+// TestMVCCRangeTombstoneIterWrapping verifies that iterators are only wrapped
+// to expose range tombstones if range tombstones overlap their bounds.
+func TestMVCCRangeTombstoneIterWrapping(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	engine := createTestPebbleEngine()
+	defer engine.Close()
+
+	ts1, ts2 := hlc.Timestamp{WallTime: 1}, hlc.Timestamp{WallTime: 2}
+	for _, key := range []string{"a", "c", "e"} {
+		require.NoError(t, MVCCPut(ctx, engine, nil, roachpb.Key(key), ts1, value1, nil))
+	}
+
+	testCases := []struct {
+		lower, upper string
+		expWrapped   bool
+	}{
+		{"", "", true},
+		{"a", "b", false},
+		{"a", "c", true},
+		{"c", "d", true},
+		{"cc", "e", true},
+		{"d", "e", true},
+		{"f", "g", false},
+		{"", "a", false},
+		{"a", "\xff", true},
+	}
+	// Prefix iterators have no bounds, and are wrapped if there are any
+	// range tombstones.
+	isWrapped := func(r Reader, lower, upper string) bool {
+		opts := IterOptions{Prefix: true}
+		if lower != "" || upper != "" {
+			opts = IterOptions{LowerBound: roachpb.Key(lower), UpperBound: roachpb.Key(upper)}
+		}
+		iter := r.NewMVCCIterator(MVCCKeyAndIntentsIterKind, opts)
+		defer iter.Close()
+		_, wrapped := iter.(*mvccRangeTombstoneIter)
+		return wrapped
+	}
+	for _, tc := range testCases {
+		require.False(t, isWrapped(engine, tc.lower, tc.upper), "[%s,%s)", tc.lower, tc.upper)
+	}
+
+	require.NoError(t, MVCCDeleteRangeUsingTombstone(ctx, engine, nil, roachpb.Key("b"), roachpb.Key("e"), ts2))
+	for _, tc := range testCases {
+		require.Equal(t, tc.expWrapped, isWrapped(engine, tc.lower, tc.upper), "[%s,%s)", tc.lower, tc.upper)
+		// Readers which cache the iterator over the fragments behave the same.
+		ro := engine.NewReadOnly()
+		require.Equal(t, tc.expWrapped, isWrapped(ro, tc.lower, tc.upper), "[%s,%s)", tc.lower, tc.upper)
+		ro.Close()
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
 	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/bloom"
@@ -388,6 +389,26 @@ func DefaultPebbleOptions() *pebble.Options {
 	return opts
 }
 
+// RaftLogPebbleOptions returns the options for a dedicated Raft log engine.
+// The Raft log is written sequentially, read almost exclusively by scanning
+// its tail, and deleted shortly after being written, so most of it should
+// never make it past the memtables and L0.
+func RaftLogPebbleOptions() *pebble.Options {
+	opts := DefaultPebbleOptions()
+	// A larger memtable lets more log entries be truncated before they are
+	// flushed.
+	opts.MemTableSize = 128 << 20 // 128 MB
+	// Truncated entries leave behind point tombstones which are best dropped
+	// by compacting L0 eagerly into the (small) lower levels.
+	opts.L0CompactionThreshold = 1
+	// The log is only ever read through iterators, which don't use bloom
+	// filters.
+	for i := range opts.Levels {
+		opts.Levels[i].FilterPolicy = nil
+	}
+	return opts
+}
+
 type pebbleLogger struct {
 	ctx   context.Context
 	depth int
@@ -408,6 +429,10 @@ type PebbleConfig struct {
 	base.StorageConfig
 	// Pebble specific options.
 	Opts *pebble.Options
+	// RaftLogEngine, if set, is a dedicated engine holding the Raft log of the
+	// store backed by this engine. It is closed along with this engine. See
+	// RaftLogEngine.
+	RaftLogEngine *Pebble
 }
 
 // EncryptionStatsHandler provides encryption related stats.
@@ -422,6 +447,9 @@ type EncryptionStatsHandler interface {
 	GetActiveStoreKeyType() int32
 	// Returns the KeyID embedded in the serialized EncryptionSettings.
 	GetKeyIDFromSettings(settings []byte) (string, error)
+	// Rewrites the file in the DB directory, which must not be written to
+	// anymore, so that it is encrypted with the active data key.
+	ReencryptFile(filename string) error
 }
 
 // Pebble is a wrapper around a Pebble database instance.
@@ -448,10 +476,23 @@ type Pebble struct {
 
 	useWrappedIntentWriter bool
 	wrappedIntentWriter    intentDemuxWriter
+
+	// raftLogEngine is the dedicated Raft log engine of the store, if any.
+	raftLogEngine *Pebble
 }
 
 var _ Engine = &Pebble{}
 
+// RaftLogEngine returns the engine holding the Raft log (and the sideloaded
+// payloads of its entries) of the store backed by eng. Unless the store was
+// opened with a dedicated Raft log engine, this is eng itself.
+func RaftLogEngine(eng Engine) Engine {
+	if p, ok := eng.(*Pebble); ok && p.raftLogEngine != nil {
+		return p.raftLogEngine
+	}
+	return eng
+}
+
 // NewEncryptedEnvFunc creates an encrypted environment and returns the vfs.FS to use for reading
 // and writing data. This should be initialized by calling engineccl.Init() before calling
 // NewPebble(). The optionBytes is a binary serialized baseccl.EncryptionOptions, so that non-CCL
@@ -544,6 +585,8 @@ func NewPebble(ctx context.Context, cfg PebbleConfig) (*Pebble, error) {
 		fileRegistry: fileRegistry,
 		fs:           cfg.Opts.FS,
 		logger:       cfg.Opts.Logger,
+
+		raftLogEngine: cfg.RaftLogEngine,
 	}
 	p.connectEventMetrics(ctx, &cfg.Opts.EventListener)
 	p.eventListener = &cfg.Opts.EventListener
@@ -632,6 +675,9 @@ func (p *Pebble) Close() {
 	}
 	p.closed = true
 	_ = p.db.Close()
+	if p.raftLogEngine != nil {
+		p.raftLogEngine.Close()
+	}
 }
 
 // Closed implements the Engine interface.
@@ -711,7 +757,7 @@ func (p *Pebble) NewMVCCIterator(iterKind MVCCIterKind, opts IterOptions) MVCCIt
 	if iter == nil {
 		panic("couldn't create a new iterator")
 	}
-	return iter
+	return maybeWrapRangeTombstoneIter(iterKind, iter, opts, p.db, nil /* cachedFragmentIter */)
 }
 
 // NewEngineIterator implements the Engine interface.
@@ -934,6 +980,8 @@ func (p *Pebble) GetMetrics() (*Metrics, error) {
 		PendingCompactionBytesEstimate: int64(m.Compact.EstimatedDebt),
 		L0FileCount:                    m.Levels[0].NumFiles,
 		L0SublevelCount:                int64(m.Levels[0].Sublevels),
+		L0Size:                         m.Levels[0].Size,
+		L0BytesIngested:                int64(m.Levels[0].BytesIngested),
 		ReadAmplification:              int64(m.ReadAmp()),
 		NumSSTables:                    numSSTables,
 	}, nil
@@ -998,6 +1046,7 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 		}
 	}
 
+	keyStats := make(map[string]*EncryptionKeyStats)
 	for filePath, entry := range fr.Files {
 		keyID, err := p.statsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
 		if err != nil {
@@ -1006,10 +1055,15 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 		if len(keyID) == 0 {
 			keyID = "plain"
 		}
-		if keyID != activeKeyID {
-			continue
+		ks, ok := keyStats[keyID]
+		if !ok {
+			ks = &EncryptionKeyStats{KeyID: keyID}
+			keyStats[keyID] = ks
+		}
+		ks.Files++
+		if keyID == activeKeyID {
+			stats.ActiveKeyFiles++
 		}
-		stats.ActiveKeyFiles++
 
 		filename := p.fs.PathBase(filePath)
 		numStr := strings.TrimSuffix(filename, ".sst")
@@ -1020,11 +1074,71 @@ func (p *Pebble) GetEnvStats() (*EnvStats, error) {
 		if err != nil {
 			return nil, errors.Wrapf(err, "parsing filename %q", errors.Safe(filename))
 		}
-		stats.ActiveKeyBytes += sstSizes[pebble.FileNum(u)]
+		ks.Bytes += sstSizes[pebble.FileNum(u)]
+		if keyID == activeKeyID {
+			stats.ActiveKeyBytes += sstSizes[pebble.FileNum(u)]
+		}
+	}
+	for _, ks := range keyStats {
+		stats.KeyStats = append(stats.KeyStats, *ks)
 	}
+	sort.Slice(stats.KeyStats, func(i, j int) bool {
+		return stats.KeyStats[i].KeyID < stats.KeyStats[j].KeyID
+	})
 	return stats, nil
 }
 
+// ReencryptFiles implements the Engine interface.
+func (p *Pebble) ReencryptFiles(ctx context.Context, maxBytes int64) (int64, error) {
+	if p.statsHandler == nil {
+		return 0, nil
+	}
+	activeKeyID, err := p.statsHandler.GetActiveDataKeyID()
+	if err != nil {
+		return 0, err
+	}
+	sstInfos, err := p.db.SSTables()
+	if err != nil {
+		return 0, err
+	}
+	var rewritten int64
+	for _, ssts := range sstInfos {
+		for _, sst := range ssts {
+			if rewritten >= maxBytes {
+				return rewritten, nil
+			}
+			if err := ctx.Err(); err != nil {
+				return rewritten, err
+			}
+			filename := fmt.Sprintf("%s.sst", sst.FileNum)
+			// Files missing from the registry were written before
+			// encryption-at-rest was enabled, and are not encrypted.
+			var keyID string
+			if entry := p.fileRegistry.GetFileEntry(p.fs.PathJoin(p.path, filename)); entry != nil {
+				keyID, err = p.statsHandler.GetKeyIDFromSettings(entry.EncryptionSettings)
+				if err != nil {
+					return rewritten, err
+				}
+			}
+			if len(keyID) == 0 {
+				keyID = "plain"
+			}
+			if keyID == activeKeyID {
+				continue
+			}
+			if err := p.statsHandler.ReencryptFile(p.fs.PathJoin(p.path, filename)); err != nil {
+				if oserror.IsNotExist(err) {
+					// The sstable was compacted away in the meantime.
+					continue
+				}
+				return rewritten, errors.Wrapf(err, "re-encrypting %s", filename)
+			}
+			rewritten += int64(sst.Size)
+		}
+	}
+	return rewritten, nil
+}
+
 // GetAuxiliaryDir implements the Engine interface.
 func (p *Pebble) GetAuxiliaryDir() string {
 	return p.auxDir
@@ -1205,7 +1319,10 @@ type pebbleReadOnly struct {
 	normalIter       pebbleIterator
 	prefixEngineIter pebbleIterator
 	normalEngineIter pebbleIterator
-	closed           bool
+	// Reuse the iterator over the fragments of MVCC range tombstones, used by
+	// the MVCCKey iterators.
+	rangeTombstoneIter pebbleIterator
+	closed             bool
 }
 
 var _ ReadWriter = &pebbleReadOnly{}
@@ -1219,6 +1336,7 @@ func (p *pebbleReadOnly) Close() {
 	p.normalIter.destroy()
 	p.prefixEngineIter.destroy()
 	p.normalEngineIter.destroy()
+	p.rangeTombstoneIter.destroy()
 }
 
 func (p *pebbleReadOnly) Closed() bool {
@@ -1285,7 +1403,8 @@ func (p *pebbleReadOnly) NewMVCCIterator(iterKind MVCCIterKind, opts IterOptions
 
 	if !opts.MinTimestampHint.IsEmpty() {
 		// MVCCIterators that specify timestamp bounds cannot be cached.
-		return newPebbleIterator(p.parent.db, opts)
+		return maybeWrapRangeTombstoneIter(
+			iterKind, newPebbleIterator(p.parent.db, opts), opts, p.parent.db, &p.rangeTombstoneIter)
 	}
 
 	iter := &p.normalIter
@@ -1304,7 +1423,7 @@ func (p *pebbleReadOnly) NewMVCCIterator(iterKind MVCCIterKind, opts IterOptions
 	}
 
 	iter.inuse = true
-	return iter
+	return maybeWrapRangeTombstoneIter(iterKind, iter, opts, p.parent.db, &p.rangeTombstoneIter)
 }
 
 // NewEngineIterator implements the Engine interface.
@@ -1491,7 +1610,8 @@ func (p *pebbleSnapshot) NewMVCCIterator(iterKind MVCCIterKind, opts IterOptions
 			return r.NewMVCCIterator(iterKind, opts)
 		}
 	}
-	return newPebbleIterator(p.snapshot, opts)
+	return maybeWrapRangeTombstoneIter(
+		iterKind, newPebbleIterator(p.snapshot, opts), opts, p.snapshot, nil /* cachedFragmentIter */)
 }
 
 // NewEngineIterator implements the Reader interface.
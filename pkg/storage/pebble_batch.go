@@ -37,10 +37,13 @@ type pebbleBatch struct {
 	normalIter       pebbleIterator
 	prefixEngineIter pebbleIterator
 	normalEngineIter pebbleIterator
-	closed           bool
-	isDistinct       bool
-	distinctOpen     bool
-	parentBatch      *pebbleBatch
+	// Reuse the iterator over the fragments of MVCC range tombstones, used by
+	// the MVCCKey iterators.
+	rangeTombstoneIter pebbleIterator
+	closed             bool
+	isDistinct         bool
+	distinctOpen       bool
+	parentBatch        *pebbleBatch
 
 	useWrappedIntentWriter bool
 	wrappedIntentWriter    intentDemuxWriter
@@ -83,6 +86,11 @@ func newPebbleBatch(db *pebble.DB, batch *pebble.Batch) *pebbleBatch {
 			upperBoundBuf: pb.normalEngineIter.upperBoundBuf,
 			reusable:      true,
 		},
+		rangeTombstoneIter: pebbleIterator{
+			lowerBoundBuf: pb.rangeTombstoneIter.lowerBoundBuf,
+			upperBoundBuf: pb.rangeTombstoneIter.upperBoundBuf,
+			reusable:      true,
+		},
 	}
 	pb.wrappedIntentWriter, pb.useWrappedIntentWriter = tryWrapIntentWriter(pb)
 	return pb
@@ -100,6 +108,7 @@ func (p *pebbleBatch) Close() {
 	p.normalIter.destroy()
 	p.prefixEngineIter.destroy()
 	p.normalEngineIter.destroy()
+	p.rangeTombstoneIter.destroy()
 
 	if !p.isDistinct {
 		_ = p.batch.Close()
@@ -203,9 +212,15 @@ func (p *pebbleBatch) NewMVCCIterator(iterKind MVCCIterKind, opts IterOptions) M
 		}
 	}
 
+	var handle pebble.Reader = p.db
+	if p.batch.Indexed() {
+		handle = p.batch
+	}
+
 	if !opts.MinTimestampHint.IsEmpty() {
 		// MVCCIterators that specify timestamp bounds cannot be cached.
-		return newPebbleIterator(p.batch, opts)
+		return maybeWrapRangeTombstoneIter(
+			iterKind, newPebbleIterator(p.batch, opts), opts, handle, &p.rangeTombstoneIter)
 	}
 
 	iter := &p.normalIter
@@ -218,14 +233,12 @@ func (p *pebbleBatch) NewMVCCIterator(iterKind MVCCIterKind, opts IterOptions) M
 
 	if iter.iter != nil {
 		iter.setOptions(opts)
-	} else if p.batch.Indexed() {
-		iter.init(p.batch, opts)
 	} else {
-		iter.init(p.db, opts)
+		iter.init(handle, opts)
 	}
 
 	iter.inuse = true
-	return iter
+	return maybeWrapRangeTombstoneIter(iterKind, iter, opts, handle, &p.rangeTombstoneIter)
 }
 
 // NewEngineIterator implements the Batch interface.
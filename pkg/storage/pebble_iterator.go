@@ -18,6 +18,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/pebble"
@@ -621,6 +622,11 @@ func (p *pebbleIterator) SupportsPrev() bool {
 	return true
 }
 
+// RangeTombstonesAt implements the MVCCIterator interface.
+func (p *pebbleIterator) RangeTombstonesAt(roachpb.Key) ([]hlc.Timestamp, error) {
+	return nil, nil
+}
+
 // CheckForKeyCollisions indicates if the provided SST data collides with this
 // iterator in the specified range.
 func (p *pebbleIterator) CheckForKeyCollisions(
@@ -22,6 +22,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -33,6 +34,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/datadriven"
 	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -298,6 +300,38 @@ func TestPebbleDiskSlowEmit(t *testing.T) {
 	require.Equal(t, uint64(1), p.diskStallCount)
 }
 
+func TestPebbleRaftLogEngine(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	settings := cluster.MakeTestingClusterSettings()
+	raftEng := newPebbleInMem(ctx, roachpb.Attributes{}, 1<<20, settings)
+
+	opts := DefaultPebbleOptions()
+	opts.FS = vfs.NewMem()
+	eng, err := NewPebble(ctx, PebbleConfig{
+		StorageConfig: base.StorageConfig{Settings: settings},
+		Opts:          opts,
+		RaftLogEngine: raftEng,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, Engine(raftEng), RaftLogEngine(eng))
+	require.Equal(t, Engine(raftEng), RaftLogEngine(raftEng))
+
+	// The engines are independent.
+	require.NoError(t, eng.PutUnversioned(roachpb.Key("a"), []byte("state")))
+	require.NoError(t, raftEng.PutUnversioned(roachpb.Key("a"), []byte("raft")))
+	v, err := eng.MVCCGet(MVCCKey{Key: roachpb.Key("a")})
+	require.NoError(t, err)
+	require.Equal(t, []byte("state"), v)
+
+	// Closing the engine closes the Raft log engine.
+	eng.Close()
+	require.True(t, raftEng.Closed())
+}
+
 func BenchmarkMVCCKeyCompare(b *testing.B) {
 	rng := rand.New(rand.NewSource(timeutil.Now().Unix()))
 	keys := make([][]byte, 1000)
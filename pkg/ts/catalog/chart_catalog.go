@@ -30,6 +30,69 @@ package catalog
 //		(chartDefaultsPerMetricType).
 
 var charts = []sectionDescription{
+	{
+		Organization: [][]string{{Process, "Admission Control"}},
+		Charts: []chartDescription{
+			{
+				Title: "Requests",
+				Metrics: []string{
+					"admission.requested.kv",
+					"admission.requested.sql-leaf-start",
+					"admission.requested.sql-root-start",
+					"admission.requested.kv-stores",
+				},
+			},
+			{
+				Title: "Admitted",
+				Metrics: []string{
+					"admission.admitted.kv",
+					"admission.admitted.sql-leaf-start",
+					"admission.admitted.sql-root-start",
+					"admission.admitted.kv-stores",
+				},
+			},
+			{
+				Title: "Errored",
+				Metrics: []string{
+					"admission.errored.kv",
+					"admission.errored.sql-leaf-start",
+					"admission.errored.sql-root-start",
+					"admission.errored.kv-stores",
+				},
+			},
+			{
+				Title: "Wait Durations",
+				Metrics: []string{
+					"admission.wait_durations.kv",
+					"admission.wait_durations.sql-leaf-start",
+					"admission.wait_durations.sql-root-start",
+					"admission.wait_durations.kv-stores",
+				},
+			},
+			{
+				Title: "Wait Queue Length",
+				Metrics: []string{
+					"admission.wait_queue_length.kv",
+					"admission.wait_queue_length.sql-leaf-start",
+					"admission.wait_queue_length.sql-root-start",
+					"admission.wait_queue_length.kv-stores",
+				},
+			},
+			{
+				Title: "Granter Slots",
+				Metrics: []string{
+					"admission.granter.total_slots.kv",
+					"admission.granter.used_slots.kv",
+					"admission.granter.used_slots.sql-leaf-start",
+					"admission.granter.used_slots.sql-root-start",
+				},
+			},
+			{
+				Title:   "IO Tokens Exhausted Duration",
+				Metrics: []string{"admission.granter.io_tokens_exhausted_duration.kv"},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Process, "Build Info"}},
 		Charts: []chartDescription{
@@ -498,6 +561,13 @@ var charts = []sectionDescription{
 					"range.snapshots.applied-non-voter",
 				},
 			},
+			{
+				Title: "Delegated Snapshots",
+				Metrics: []string{
+					"range.snapshots.delegate.successes",
+					"range.snapshots.delegate.failures",
+				},
+			},
 		},
 	},
 	{
@@ -770,6 +840,12 @@ var charts = []sectionDescription{
 				Percentiles: false,
 				Metrics:     []string{"kv.tenant_rate_limit.write_bytes_admitted"},
 			},
+			{
+				Title:       "Request Units Consumed by Tenants",
+				Downsampler: DescribeAggregator_MAX,
+				Percentiles: false,
+				Metrics:     []string{"kv.tenant_rate_limit.request_units_consumed"},
+			},
 		},
 	},
 	{
@@ -1857,6 +1933,14 @@ var charts = []sectionDescription{
 					"sql.bytesout",
 				},
 			},
+			{
+				Title: "Query Cancel Requests",
+				Metrics: []string{
+					"sql.pgwire_cancel.total",
+					"sql.pgwire_cancel.ignored",
+					"sql.pgwire_cancel.successful",
+				},
+			},
 			{
 				Title: "Exec Latency",
 				Metrics: []string{
@@ -0,0 +1,42 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package admission contains the node-level admission control subsystem,
+// which protects a node from overload by queueing work before it starts
+// consuming resources, instead of admitting all work immediately.
+//
+// Work is categorized by WorkKind. Each WorkKind has a WorkQueue, in which
+// work waits for admission, and a granter, which decides when the queued
+// work may start, based on the resources available:
+//
+//  - KVWork, i.e. the BatchRequests evaluated by the node, and the setup of
+//    SQL flows (SQLStatementLeafStartWork and SQLStatementRootStartWork) use
+//    CPU slots. The number of slots for KVWork is adjusted continuously by
+//    the kvSlotAdjuster, based on the number of runnable goroutines: it is
+//    decreased when the CPU is overloaded, and increased when the slots are
+//    the bottleneck while the CPU is not overloaded. The SQL work kinds
+//    have a fixed number of slots, but are not granted any while the CPU is
+//    overloaded, so that KV work takes precedence.
+//
+//  - The write work of each store uses tokens, which are replenished
+//    periodically by an ioLoadListener based on the metrics of the store's
+//    LSM: when L0 has too many files or sublevels, the tokens are limited
+//    so that the rate at which bytes are added to L0 does not exceed the
+//    rate at which they are compacted out of it.
+//
+// The GrantCoordinator ties together the granters and WorkQueues that use
+// CPU slots, and the StoreGrantCoordinators do the same for the
+// store-level work.
+//
+// A WorkQueue orders the waiting work first by tenant, then by priority,
+// and then by creation time. Tenants are given a fair share of the
+// resources: the next grant goes to the tenant with the least work
+// currently admitted (for slots) or recently admitted (for tokens).
+package admission
@@ -0,0 +1,421 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/goschedstats"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// KVAdmissionControlEnabled controls whether the work performed by the KV
+// layer is subject to admission control.
+var KVAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.kv.enabled",
+	"when true, work performed by the KV layer is subject to admission control",
+	false)
+
+// SQLAdmissionControlEnabled controls whether the setup of SQL flows is
+// subject to admission control.
+var SQLAdmissionControlEnabled = settings.RegisterBoolSetting(
+	"admission.sql.enabled",
+	"when true, the setup of SQL statement flows is subject to admission control",
+	false)
+
+// KVSlotAdjusterOverloadThreshold sets the number of runnable goroutines
+// per CPU above which the CPU is considered to be overloaded.
+var KVSlotAdjusterOverloadThreshold = settings.RegisterIntSetting(
+	"admission.kv_slot_adjuster.overload_threshold",
+	"when the number of runnable goroutines per CPU is greater than this threshold, the "+
+		"slot adjuster considers the CPU to be overloaded",
+	32, settings.PositiveInt)
+
+// WorkKind represents the kinds of work that are subject to admission
+// control.
+type WorkKind int8
+
+const (
+	// KVWork represents the BatchRequests evaluated by the KV layer. They may
+	// originate from the SQL layer of this node or of another node, or from
+	// the KV layer itself.
+	KVWork WorkKind = iota
+	// SQLStatementLeafStartWork represents the setup of the flows of a SQL
+	// statement on the nodes other than the gateway.
+	SQLStatementLeafStartWork
+	// SQLStatementRootStartWork represents the setup of the flow of a SQL
+	// statement on the gateway node.
+	SQLStatementRootStartWork
+	numWorkKinds
+)
+
+func (wk WorkKind) String() string {
+	switch wk {
+	case KVWork:
+		return "kv"
+	case SQLStatementLeafStartWork:
+		return "sql-leaf-start"
+	case SQLStatementRootStartWork:
+		return "sql-root-start"
+	default:
+		panic(errors.AssertionFailedf("unknown WorkKind %d", int8(wk)))
+	}
+}
+
+// granter is paired with a requester: the requester queues the work of a
+// WorkKind, and the granter decides when the queued work may be admitted.
+type granter interface {
+	// tryGet is used by the requester to get a slot or token for work that
+	// does not need to wait behind queued work. It returns true if the work
+	// may proceed.
+	tryGet() bool
+	// returnGrant returns a slot once the admitted work is done, or returns
+	// a slot or token which was granted to work that was then canceled.
+	returnGrant()
+	// tookWithoutPermission informs the granter that a slot or token was
+	// taken by work which bypasses admission control.
+	tookWithoutPermission()
+}
+
+// requester is implemented by the object which queues the work of a
+// WorkKind, i.e. WorkQueue.
+type requester interface {
+	// hasWaitingRequests returns whether there is work waiting.
+	hasWaitingRequests() bool
+	// granted grants admission to the next waiting work. It returns false if
+	// no work is waiting, in which case the caller still holds the grant.
+	granted() bool
+}
+
+// grantWaitingWork grants admission to the work waiting in the requester,
+// as long as tryGet succeeds. undo gives back a grant obtained with tryGet
+// when no work turns out to be waiting.
+func grantWaitingWork(r requester, tryGet func() bool, undo func()) {
+	for tryGet() {
+		if r.granted() {
+			continue
+		}
+		undo()
+		// Work may have been queued after the call to granted(), and may
+		// have found no grant available while we were holding one. Check
+		// again, so that such work does not wait forever.
+		if !r.hasWaitingRequests() {
+			return
+		}
+	}
+}
+
+// cpuOverloadIndicator reports whether the CPU is overloaded.
+type cpuOverloadIndicator interface {
+	isOverloaded() bool
+}
+
+// slotGranter implements granter for the work kinds that use CPU slots. The
+// work holds its slot until it is done.
+type slotGranter struct {
+	workKind  WorkKind
+	requester requester
+	// cpuOverload, if set, prevents slots from being granted while the CPU
+	// is overloaded.
+	cpuOverload     cpuOverloadIndicator
+	usedSlotsMetric *metric.Gauge
+
+	mu struct {
+		syncutil.Mutex
+		// usedSlots can exceed totalSlots, due to work that bypasses
+		// admission control, or when totalSlots is decreased.
+		usedSlots  int
+		totalSlots int
+	}
+}
+
+var _ granter = &slotGranter{}
+
+// tryGet implements the granter interface.
+func (sg *slotGranter) tryGet() bool {
+	if sg.cpuOverload != nil && sg.cpuOverload.isOverloaded() {
+		return false
+	}
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if sg.mu.usedSlots >= sg.mu.totalSlots {
+		return false
+	}
+	sg.mu.usedSlots++
+	sg.usedSlotsMetric.Update(int64(sg.mu.usedSlots))
+	return true
+}
+
+// returnGrant implements the granter interface.
+func (sg *slotGranter) returnGrant() {
+	sg.returnSlot()
+	sg.tryGrant()
+}
+
+// tookWithoutPermission implements the granter interface.
+func (sg *slotGranter) tookWithoutPermission() {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	sg.mu.usedSlots++
+	sg.usedSlotsMetric.Update(int64(sg.mu.usedSlots))
+}
+
+func (sg *slotGranter) returnSlot() {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if sg.mu.usedSlots <= 0 {
+		panic(errors.AssertionFailedf("no %s slot in use", sg.workKind))
+	}
+	sg.mu.usedSlots--
+	sg.usedSlotsMetric.Update(int64(sg.mu.usedSlots))
+}
+
+// tryGrant grants the available slots to the waiting work.
+func (sg *slotGranter) tryGrant() {
+	grantWaitingWork(sg.requester, sg.tryGet, sg.returnSlot)
+}
+
+// kvSlotAdjuster adjusts the number of slots for KVWork, based on the CPU
+// load. It also implements cpuOverloadIndicator, for the SQL work kinds.
+type kvSlotAdjuster struct {
+	settings         *cluster.Settings
+	granter          *slotGranter
+	minCPUSlots      int
+	maxCPUSlots      int
+	totalSlotsMetric *metric.Gauge
+	// overloaded is set, atomically, when the last sample of the CPU load
+	// indicated that the CPU was overloaded.
+	overloaded int32
+}
+
+var _ cpuOverloadIndicator = &kvSlotAdjuster{}
+
+// CPULoad adjusts the number of slots given the number of runnable
+// goroutines and the number of procs: the slots are decreased while the CPU
+// is overloaded, and increased while the CPU is underloaded and all the
+// slots are in use.
+func (kvsa *kvSlotAdjuster) CPULoad(runnable int, procs int) {
+	threshold := int(KVSlotAdjusterOverloadThreshold.Get(&kvsa.settings.SV))
+	overloaded := runnable >= threshold*procs
+
+	kvsa.granter.mu.Lock()
+	usedSlots := kvsa.granter.mu.usedSlots
+	totalSlots := kvsa.granter.mu.totalSlots
+	if overloaded {
+		// Only decrease the slots if they are being used; if more slots than
+		// the total are in use, the overload may be due to work which took
+		// slots without permission, and decreasing further would not help.
+		if usedSlots > 0 && usedSlots <= totalSlots && totalSlots > kvsa.minCPUSlots {
+			totalSlots--
+		}
+	} else if runnable <= threshold*procs/2 {
+		// Underload. If the slots are the bottleneck, increase them.
+		if usedSlots >= totalSlots && totalSlots < kvsa.maxCPUSlots {
+			totalSlots++
+		}
+	}
+	kvsa.granter.mu.totalSlots = totalSlots
+	kvsa.granter.mu.Unlock()
+
+	var overloadedInt int32
+	if overloaded {
+		overloadedInt = 1
+	}
+	atomic.StoreInt32(&kvsa.overloaded, overloadedInt)
+	kvsa.totalSlotsMetric.Update(int64(totalSlots))
+}
+
+// isOverloaded implements the cpuOverloadIndicator interface.
+func (kvsa *kvSlotAdjuster) isOverloaded() bool {
+	return atomic.LoadInt32(&kvsa.overloaded) == 1
+}
+
+// Options are the options of a GrantCoordinator.
+type Options struct {
+	// MinCPUSlots and MaxCPUSlots bound the number of slots for KVWork.
+	MinCPUSlots int
+	MaxCPUSlots int
+	// SQLStatementLeafStartWorkSlots and SQLStatementRootStartWorkSlots are
+	// the number of slots for the respective work kinds.
+	SQLStatementLeafStartWorkSlots int
+	SQLStatementRootStartWorkSlots int
+	// HistogramWindow is the window of the histogram metrics.
+	HistogramWindow time.Duration
+}
+
+// DefaultOptions are the default options of a GrantCoordinator.
+var DefaultOptions = Options{
+	MinCPUSlots:                    1,
+	MaxCPUSlots:                    100000,
+	SQLStatementLeafStartWorkSlots: 100,
+	SQLStatementRootStartWorkSlots: 100,
+}
+
+// GrantCoordinator ties together the granters and WorkQueues of a node
+// for the work kinds that use CPU slots. The number of slots for KVWork is
+// adjusted based on the CPU load, which is sampled periodically (see
+// Start).
+type GrantCoordinator struct {
+	settings        *cluster.Settings
+	granters        [numWorkKinds]*slotGranter
+	queues          [numWorkKinds]*WorkQueue
+	cpuLoadListener *kvSlotAdjuster
+}
+
+// NewGrantCoordinator creates a GrantCoordinator, along with the WorkQueues
+// of the work kinds that use CPU slots. The returned metric structs need
+// to be registered by the caller.
+func NewGrantCoordinator(st *cluster.Settings, opts Options) (*GrantCoordinator, []metric.Struct) {
+	metrics := makeGranterMetrics()
+	coord := &GrantCoordinator{settings: st}
+
+	kvg := &slotGranter{
+		workKind:        KVWork,
+		usedSlotsMetric: metrics.KVUsedSlots,
+	}
+	kvg.mu.totalSlots = opts.MinCPUSlots
+	metrics.KVTotalSlots.Update(int64(opts.MinCPUSlots))
+	coord.cpuLoadListener = &kvSlotAdjuster{
+		settings:         st,
+		granter:          kvg,
+		minCPUSlots:      opts.MinCPUSlots,
+		maxCPUSlots:      opts.MaxCPUSlots,
+		totalSlotsMetric: metrics.KVTotalSlots,
+	}
+	coord.granters[KVWork] = kvg
+
+	// The SQL work kinds are not granted slots while the CPU is overloaded,
+	// so that KV work, which is not subject to this restriction, takes
+	// precedence.
+	sqlLeaf := &slotGranter{
+		workKind:        SQLStatementLeafStartWork,
+		cpuOverload:     coord.cpuLoadListener,
+		usedSlotsMetric: metrics.SQLLeafStartUsedSlots,
+	}
+	sqlLeaf.mu.totalSlots = opts.SQLStatementLeafStartWorkSlots
+	coord.granters[SQLStatementLeafStartWork] = sqlLeaf
+
+	sqlRoot := &slotGranter{
+		workKind:        SQLStatementRootStartWork,
+		cpuOverload:     coord.cpuLoadListener,
+		usedSlotsMetric: metrics.SQLRootStartUsedSlots,
+	}
+	sqlRoot.mu.totalSlots = opts.SQLStatementRootStartWorkSlots
+	coord.granters[SQLStatementRootStartWork] = sqlRoot
+
+	metricStructs := []metric.Struct{metrics}
+	for i, g := range coord.granters {
+		workKind := WorkKind(i)
+		enabled := SQLAdmissionControlEnabled
+		if workKind == KVWork {
+			enabled = KVAdmissionControlEnabled
+		}
+		wqMetrics := makeWorkQueueMetrics(workKind.String(), opts.HistogramWindow)
+		q := makeWorkQueue(workKind, g, st, enabled, false /* usesTokens */, wqMetrics)
+		g.requester = q
+		coord.queues[workKind] = q
+		metricStructs = append(metricStructs, wqMetrics)
+	}
+	return coord, metricStructs
+}
+
+// GetWorkQueue returns the WorkQueue of the given work kind.
+func (coord *GrantCoordinator) GetWorkQueue(workKind WorkKind) *WorkQueue {
+	return coord.queues[workKind]
+}
+
+// CPULoad informs the GrantCoordinator of the current CPU load, i.e. the
+// number of runnable goroutines and the number of procs. The slots are
+// adjusted accordingly, and granted to the waiting work if possible.
+func (coord *GrantCoordinator) CPULoad(runnable int, procs int) {
+	coord.cpuLoadListener.CPULoad(runnable, procs)
+	// Slots may have been added, or the CPU may no longer be overloaded.
+	for _, g := range coord.granters {
+		g.tryGrant()
+	}
+}
+
+// cpuLoadSamplePeriod is the period at which the CPU load is sampled. The
+// number of runnable goroutines fluctuates quickly, so it is sampled often.
+const cpuLoadSamplePeriod = time.Millisecond
+
+// Start starts the periodic sampling of the CPU load.
+func (coord *GrantCoordinator) Start(ctx context.Context, stopper *stop.Stopper) error {
+	return stopper.RunAsyncTask(ctx, "admission-cpu-load", func(ctx context.Context) {
+		ticker := time.NewTicker(cpuLoadSamplePeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				coord.CPULoad(goschedstats.NumRunnableGoroutines())
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
+
+var (
+	kvTotalSlotsMeta = metric.Metadata{
+		Name:        "admission.granter.total_slots.kv",
+		Help:        "Total slots for kv work",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	kvUsedSlotsMeta = metric.Metadata{
+		Name:        "admission.granter.used_slots.kv",
+		Help:        "Used slots for kv work",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	sqlLeafStartUsedSlotsMeta = metric.Metadata{
+		Name:        "admission.granter.used_slots.sql-leaf-start",
+		Help:        "Used slots for the setup of SQL leaf flows",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+	sqlRootStartUsedSlotsMeta = metric.Metadata{
+		Name:        "admission.granter.used_slots.sql-root-start",
+		Help:        "Used slots for the setup of SQL root flows",
+		Measurement: "Slots",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// GranterMetrics are the metrics of the slot granters of a
+// GrantCoordinator.
+type GranterMetrics struct {
+	KVTotalSlots          *metric.Gauge
+	KVUsedSlots           *metric.Gauge
+	SQLLeafStartUsedSlots *metric.Gauge
+	SQLRootStartUsedSlots *metric.Gauge
+}
+
+var _ metric.Struct = GranterMetrics{}
+
+// MetricStruct implements the metric.Struct interface.
+func (GranterMetrics) MetricStruct() {}
+
+func makeGranterMetrics() GranterMetrics {
+	return GranterMetrics{
+		KVTotalSlots:          metric.NewGauge(kvTotalSlotsMeta),
+		KVUsedSlots:           metric.NewGauge(kvUsedSlotsMeta),
+		SQLLeafStartUsedSlots: metric.NewGauge(sqlLeafStartUsedSlotsMeta),
+		SQLRootStartUsedSlots: metric.NewGauge(sqlRootStartUsedSlotsMeta),
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantCoordinator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	KVAdmissionControlEnabled.Override(&st.SV, true)
+	SQLAdmissionControlEnabled.Override(&st.SV, true)
+	KVSlotAdjusterOverloadThreshold.Override(&st.SV, 10)
+	opts := DefaultOptions
+	opts.MinCPUSlots = 1
+	opts.MaxCPUSlots = 2
+	opts.SQLStatementLeafStartWorkSlots = 1
+	opts.HistogramWindow = time.Second
+	coord, metricStructs := NewGrantCoordinator(st, opts)
+	require.Equal(t, 1+int(numWorkKinds), len(metricStructs))
+	kvQueue := coord.GetWorkQueue(KVWork)
+	sqlQueue := coord.GetWorkQueue(SQLStatementLeafStartWork)
+	kvGranter := coord.granters[KVWork]
+	usedAndTotal := func() (int, int) {
+		kvGranter.mu.Lock()
+		defer kvGranter.mu.Unlock()
+		return kvGranter.mu.usedSlots, kvGranter.mu.totalSlots
+	}
+
+	// The single KV slot is taken by the first work.
+	tenantID := roachpb.SystemTenantID
+	enabled, err := kvQueue.Admit(ctx, WorkInfo{TenantID: tenantID})
+	require.NoError(t, err)
+	require.True(t, enabled)
+	ch := admitAsync(kvQueue, ctx, WorkInfo{TenantID: tenantID})
+	waitForQueueLength(t, kvQueue, 1)
+
+	// The CPU is underloaded while the slots are the bottleneck, so a slot
+	// is added, which is granted to the waiting work.
+	coord.CPULoad(1 /* runnable */, 1 /* procs */)
+	require.NoError(t, <-ch)
+	used, total := usedAndTotal()
+	require.Equal(t, 2, used)
+	require.Equal(t, 2, total)
+
+	// The maximum number of slots has been reached.
+	coord.CPULoad(1 /* runnable */, 1 /* procs */)
+	_, total = usedAndTotal()
+	require.Equal(t, 2, total)
+
+	// Overload decreases the slots, and prevents SQL work from being
+	// granted a slot.
+	coord.CPULoad(10 /* runnable */, 1 /* procs */)
+	_, total = usedAndTotal()
+	require.Equal(t, 1, total)
+	sqlCh := admitAsync(sqlQueue, ctx, WorkInfo{TenantID: tenantID})
+	waitForQueueLength(t, sqlQueue, 1)
+
+	// The minimum number of slots has been reached.
+	coord.CPULoad(10 /* runnable */, 1 /* procs */)
+	_, total = usedAndTotal()
+	require.Equal(t, 1, total)
+
+	// The overload is over: the SQL work is granted its slot. The KV slots
+	// are not increased, since they are not the bottleneck once the KV work
+	// is done.
+	kvQueue.AdmittedWorkDone(tenantID)
+	kvQueue.AdmittedWorkDone(tenantID)
+	coord.CPULoad(5 /* runnable */, 1 /* procs */)
+	require.NoError(t, <-sqlCh)
+	used, total = usedAndTotal()
+	require.Equal(t, 0, used)
+	require.Equal(t, 1, total)
+	sqlQueue.AdmittedWorkDone(tenantID)
+}
+
+func TestIOLoadListener(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	KVAdmissionControlEnabled.Override(&st.SV, true)
+	L0FileCountOverloadThreshold.Override(&st.SV, 10)
+	sgc, _ := NewStoreGrantCoordinators(st, time.Second /* histogramWindow */)
+	storeID := roachpb.StoreID(1)
+	require.Nil(t, sgc.TryGetQueueForStore(storeID))
+
+	m := StoreMetrics{StoreID: storeID, L0FileCount: 5}
+	sgc.tick(ctx, []StoreMetrics{m}, tokenAllocationTicks)
+	q := sgc.TryGetQueueForStore(storeID)
+	require.NotNil(t, q)
+	c := sgc.getOrCreate(storeID)
+	availableTokens := func() int64 {
+		c.granter.mu.Lock()
+		defer c.granter.mu.Unlock()
+		return c.granter.mu.availableIOTokens
+	}
+	require.Equal(t, int64(unlimitedTokens), availableTokens())
+
+	// 10 writes, of 100 bytes each, are admitted.
+	for i := 0; i < 10; i++ {
+		_, err := q.Admit(ctx, WorkInfo{TenantID: roachpb.SystemTenantID})
+		require.NoError(t, err)
+	}
+	// L0 is healthy: the tokens remain unlimited.
+	m.L0BytesAdded = 1000
+	m.L0Size = 500
+	sgc.tick(ctx, []StoreMetrics{m}, tokenAllocationTicks)
+	require.Equal(t, int64(unlimitedTokens), availableTokens())
+
+	// Another 10 writes, of 100 bytes each, are admitted, and L0 is
+	// overloaded. 1000 bytes were removed from L0 in the last interval,
+	// and 500 in the previous one, so the tokens are limited to
+	// (0.5*1000+0.25*500)/2/100 = 3 writes, which are allocated across the
+	// ticks of the interval.
+	for i := 0; i < 10; i++ {
+		_, err := q.Admit(ctx, WorkInfo{TenantID: roachpb.SystemTenantID})
+		require.NoError(t, err)
+	}
+	m.L0BytesAdded = 2000
+	m.L0FileCount = 20
+	sgc.tick(ctx, []StoreMetrics{m}, tokenAllocationTicks)
+	require.Equal(t, int64(3), c.ioLoadListener.totalTokens)
+	require.Equal(t, int64(1), availableTokens())
+	_, err := q.Admit(ctx, WorkInfo{TenantID: roachpb.SystemTenantID})
+	require.NoError(t, err)
+	require.Equal(t, int64(0), availableTokens())
+
+	// The next write waits, until the next tick allocates a token.
+	ch := admitAsync(q, ctx, WorkInfo{TenantID: roachpb.SystemTenantID})
+	waitForQueueLength(t, q, 1)
+	sgc.tick(ctx, nil /* metrics */, tokenAllocationTicks-1)
+	require.NoError(t, <-ch)
+	sgc.tick(ctx, nil /* metrics */, tokenAllocationTicks-2)
+	require.Equal(t, int64(1), availableTokens())
+	require.Equal(t, int64(3), c.ioLoadListener.tokensAllocated)
+	// All the tokens of the interval have been allocated, and the unused
+	// tokens do not carry over to the next tick.
+	sgc.tick(ctx, nil /* metrics */, tokenAllocationTicks-3)
+	require.Equal(t, int64(0), availableTokens())
+}
@@ -0,0 +1,406 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// L0FileCountOverloadThreshold sets the number of files in L0 above which
+// a store is considered to be overloaded.
+var L0FileCountOverloadThreshold = settings.RegisterIntSetting(
+	"admission.l0_file_count_overload_threshold",
+	"when the L0 file count exceeds this threshold, the store is considered overloaded",
+	1000, settings.PositiveInt)
+
+// L0SubLevelCountOverloadThreshold sets the number of sublevels in L0 above
+// which a store is considered to be overloaded.
+var L0SubLevelCountOverloadThreshold = settings.RegisterIntSetting(
+	"admission.l0_sub_level_count_overload_threshold",
+	"when the L0 sub-level count exceeds this threshold, the store is considered overloaded",
+	20, settings.PositiveInt)
+
+const (
+	// unlimitedTokens is the number of tokens of a store which is not
+	// overloaded.
+	unlimitedTokens = math.MaxInt64
+	// tokenAllocationTicks is the number of ticks over which the tokens
+	// computed by the ioLoadListener are allocated. The tokens are computed
+	// once per adjustment interval, i.e. every tokenAllocationTicks ticks.
+	tokenAllocationTicks = 15
+	// tokenAllocationTickDuration is the duration of a tick.
+	tokenAllocationTickDuration = time.Second
+)
+
+// StoreMetrics are the metrics of the storage engine of a store which are
+// used to compute the write tokens of the store.
+type StoreMetrics struct {
+	StoreID roachpb.StoreID
+	// L0FileCount and L0SublevelCount describe the current shape of L0.
+	L0FileCount     int64
+	L0SublevelCount int64
+	// L0Size is the total size of the files in L0, in bytes.
+	L0Size int64
+	// L0BytesAdded is the cumulative number of bytes added to L0, by
+	// flushes and ingestions.
+	L0BytesAdded int64
+}
+
+// StoreMetricsProvider provides the StoreMetrics of all the stores of a
+// node.
+type StoreMetricsProvider interface {
+	GetStoreMetrics() []StoreMetrics
+}
+
+// kvStoreTokenGranter implements granter for the write work of a store.
+// Each admitted write consumes a token. The tokens are replenished on every
+// tick by the ioLoadListener.
+type kvStoreTokenGranter struct {
+	requester                 requester
+	ioTokensExhaustedDuration *metric.Counter
+
+	mu struct {
+		syncutil.Mutex
+		// availableIOTokens can become negative, due to work that bypasses
+		// admission control.
+		availableIOTokens int64
+		// tokensTaken is the cumulative number of tokens taken.
+		tokensTaken int64
+		// exhaustedStart is the time at which the tokens were exhausted, if
+		// they currently are.
+		exhaustedStart time.Time
+	}
+}
+
+var _ granter = &kvStoreTokenGranter{}
+
+// tryGet implements the granter interface.
+func (sg *kvStoreTokenGranter) tryGet() bool {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if sg.mu.availableIOTokens <= 0 {
+		return false
+	}
+	sg.takeTokenLocked()
+	return true
+}
+
+// returnGrant implements the granter interface.
+func (sg *kvStoreTokenGranter) returnGrant() {
+	sg.returnToken()
+	sg.tryGrant()
+}
+
+// tookWithoutPermission implements the granter interface.
+func (sg *kvStoreTokenGranter) tookWithoutPermission() {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	sg.takeTokenLocked()
+}
+
+func (sg *kvStoreTokenGranter) takeTokenLocked() {
+	if sg.mu.availableIOTokens != unlimitedTokens {
+		sg.mu.availableIOTokens--
+		if sg.mu.availableIOTokens <= 0 && sg.mu.exhaustedStart.IsZero() {
+			sg.mu.exhaustedStart = timeutil.Now()
+		}
+	}
+	sg.mu.tokensTaken++
+}
+
+func (sg *kvStoreTokenGranter) returnToken() {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if sg.mu.availableIOTokens != unlimitedTokens {
+		sg.mu.availableIOTokens++
+		sg.maybeEndExhaustionLocked()
+	}
+	sg.mu.tokensTaken--
+}
+
+func (sg *kvStoreTokenGranter) maybeEndExhaustionLocked() {
+	if sg.mu.availableIOTokens > 0 && !sg.mu.exhaustedStart.IsZero() {
+		sg.ioTokensExhaustedDuration.Inc(timeutil.Since(sg.mu.exhaustedStart).Nanoseconds())
+		sg.mu.exhaustedStart = time.Time{}
+	}
+}
+
+// setAvailableIOTokens sets the number of tokens available until the next
+// tick. The tokens which were taken without permission beyond the previous
+// allocation are deducted from the new allocation.
+func (sg *kvStoreTokenGranter) setAvailableIOTokens(tokens int64) {
+	sg.mu.Lock()
+	if tokens == unlimitedTokens || sg.mu.availableIOTokens >= 0 {
+		sg.mu.availableIOTokens = tokens
+	} else {
+		sg.mu.availableIOTokens += tokens
+	}
+	sg.maybeEndExhaustionLocked()
+	sg.mu.Unlock()
+	sg.tryGrant()
+}
+
+func (sg *kvStoreTokenGranter) getTokensTaken() int64 {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	return sg.mu.tokensTaken
+}
+
+// tryGrant grants the available tokens to the waiting work.
+func (sg *kvStoreTokenGranter) tryGrant() {
+	grantWaitingWork(sg.requester, sg.tryGet, sg.returnToken)
+}
+
+// ioLoadListener computes the write tokens of a store, based on the
+// metrics of its LSM. While L0 is healthy, the tokens are unlimited. When
+// L0 has too many files or sublevels, the tokens are limited so that the
+// bytes added to L0 are about half of the bytes compacted out of L0, which
+// allows L0 to shrink back to a healthy state.
+type ioLoadListener struct {
+	settings  *cluster.Settings
+	kvGranter *kvStoreTokenGranter
+	kvQueue   *WorkQueue
+
+	// Cumulative stats as of the last call to pebbleMetricsTick.
+	initialized     bool
+	tokensTaken     int64
+	l0BytesAdded    int64
+	l0Size          int64
+	smoothedRemoved float64
+	smoothedPerWork float64
+
+	// totalTokens are the tokens for the current adjustment interval, of
+	// which tokensAllocated have been allocated so far.
+	totalTokens     int64
+	tokensAllocated int64
+}
+
+// smoothingAlpha is the weight of the latest interval in the exponentially
+// smoothed statistics.
+const smoothingAlpha = 0.5
+
+// pebbleMetricsTick is called at the start of each adjustment interval, to
+// compute the tokens for the interval.
+func (io *ioLoadListener) pebbleMetricsTick(ctx context.Context, m StoreMetrics) {
+	tokensTaken := io.kvGranter.getTokensTaken()
+	if !io.initialized {
+		io.initialized = true
+		io.tokensTaken = tokensTaken
+		io.l0BytesAdded = m.L0BytesAdded
+		io.l0Size = m.L0Size
+		io.totalTokens = unlimitedTokens
+		io.tokensAllocated = 0
+		return
+	}
+	bytesAdded := m.L0BytesAdded - io.l0BytesAdded
+	// The bytes removed from L0 by compactions are the bytes that L0 would
+	// contain without compactions, minus the bytes it actually contains.
+	bytesRemoved := io.l0Size + bytesAdded - m.L0Size
+	if bytesRemoved < 0 {
+		bytesRemoved = 0
+	}
+	admitted := tokensTaken - io.tokensTaken
+	io.smoothedRemoved = smoothingAlpha*float64(bytesRemoved) + (1-smoothingAlpha)*io.smoothedRemoved
+	if admitted > 0 && bytesAdded > 0 {
+		perWork := float64(bytesAdded) / float64(admitted)
+		if io.smoothedPerWork == 0 {
+			io.smoothedPerWork = perWork
+		} else {
+			io.smoothedPerWork = smoothingAlpha*perWork + (1-smoothingAlpha)*io.smoothedPerWork
+		}
+	}
+	io.tokensTaken = tokensTaken
+	io.l0BytesAdded = m.L0BytesAdded
+	io.l0Size = m.L0Size
+	io.tokensAllocated = 0
+
+	if m.L0FileCount <= L0FileCountOverloadThreshold.Get(&io.settings.SV) &&
+		m.L0SublevelCount <= L0SubLevelCountOverloadThreshold.Get(&io.settings.SV) {
+		io.totalTokens = unlimitedTokens
+		return
+	}
+	perWork := io.smoothedPerWork
+	if perWork < 1 {
+		perWork = 1
+	}
+	io.totalTokens = int64(io.smoothedRemoved / 2 / perWork)
+	log.Infof(ctx, "IO overload on store s%d (files %d, sub-levels %d): admitting %d writes "+
+		"in the next %s (%.0f bytes removed from L0, %.0f bytes per write)",
+		m.StoreID, m.L0FileCount, m.L0SublevelCount, io.totalTokens,
+		tokenAllocationTicks*tokenAllocationTickDuration, io.smoothedRemoved, io.smoothedPerWork)
+}
+
+// allocateTokensTick allocates a share of the tokens of the adjustment
+// interval to the granter. ticksRemaining is the number of ticks remaining
+// in the interval, including the current one.
+func (io *ioLoadListener) allocateTokensTick(ticksRemaining int64) {
+	var toAllocate int64
+	if io.totalTokens == unlimitedTokens {
+		toAllocate = unlimitedTokens
+	} else {
+		// Round up, so that the tokens are not starved when there are fewer
+		// tokens than ticks.
+		toAllocate = (io.totalTokens - io.tokensAllocated + ticksRemaining - 1) / ticksRemaining
+		if toAllocate < 0 {
+			toAllocate = 0
+		}
+		io.tokensAllocated += toAllocate
+	}
+	io.kvGranter.setAvailableIOTokens(toAllocate)
+	io.kvQueue.resetTokenUsage()
+}
+
+// storeGrantCoordinator holds the admission control state of a store.
+type storeGrantCoordinator struct {
+	granter        *kvStoreTokenGranter
+	queue          *WorkQueue
+	ioLoadListener *ioLoadListener
+}
+
+// StoreGrantCoordinators holds the admission control state of the stores of
+// a node: for each store, a WorkQueue for the write work, with a granter
+// whose tokens are computed by an ioLoadListener.
+type StoreGrantCoordinators struct {
+	settings *cluster.Settings
+	// The WorkQueues and granters of all the stores share their metrics.
+	workQueueMetrics          WorkQueueMetrics
+	ioTokensExhaustedDuration *metric.Counter
+
+	mu struct {
+		syncutil.Mutex
+		stores map[roachpb.StoreID]*storeGrantCoordinator
+	}
+}
+
+var ioTokensExhaustedDurationMeta = metric.Metadata{
+	Name:        "admission.granter.io_tokens_exhausted_duration.kv",
+	Help:        "Total duration when IO tokens were exhausted, in nanoseconds",
+	Measurement: "Duration",
+	Unit:        metric.Unit_NANOSECONDS,
+}
+
+// storeWorkQueueName is the name of the store WorkQueues, in the metrics.
+const storeWorkQueueName = "kv-stores"
+
+// NewStoreGrantCoordinators creates the StoreGrantCoordinators of a node.
+// The returned metric structs need to be registered by the caller.
+func NewStoreGrantCoordinators(
+	st *cluster.Settings, histogramWindow time.Duration,
+) (*StoreGrantCoordinators, []metric.Struct) {
+	sgc := &StoreGrantCoordinators{
+		settings:                  st,
+		workQueueMetrics:          makeWorkQueueMetrics(storeWorkQueueName, histogramWindow),
+		ioTokensExhaustedDuration: metric.NewCounter(ioTokensExhaustedDurationMeta),
+	}
+	sgc.mu.stores = make(map[roachpb.StoreID]*storeGrantCoordinator)
+	return sgc, []metric.Struct{sgc.workQueueMetrics, StoreGranterMetrics{sgc.ioTokensExhaustedDuration}}
+}
+
+// StoreGranterMetrics are the metrics of the store granters.
+type StoreGranterMetrics struct {
+	IOTokensExhaustedDuration *metric.Counter
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (StoreGranterMetrics) MetricStruct() {}
+
+// TryGetQueueForStore returns the WorkQueue for the write work of the given
+// store, or nil if the store is not known (yet).
+func (sgc *StoreGrantCoordinators) TryGetQueueForStore(storeID roachpb.StoreID) *WorkQueue {
+	sgc.mu.Lock()
+	defer sgc.mu.Unlock()
+	if c, ok := sgc.mu.stores[storeID]; ok {
+		return c.queue
+	}
+	return nil
+}
+
+func (sgc *StoreGrantCoordinators) getOrCreate(storeID roachpb.StoreID) *storeGrantCoordinator {
+	sgc.mu.Lock()
+	defer sgc.mu.Unlock()
+	if c, ok := sgc.mu.stores[storeID]; ok {
+		return c
+	}
+	g := &kvStoreTokenGranter{ioTokensExhaustedDuration: sgc.ioTokensExhaustedDuration}
+	g.mu.availableIOTokens = unlimitedTokens
+	q := makeWorkQueue(KVWork, g, sgc.settings, KVAdmissionControlEnabled,
+		true /* usesTokens */, sgc.workQueueMetrics)
+	g.requester = q
+	c := &storeGrantCoordinator{
+		granter: g,
+		queue:   q,
+		ioLoadListener: &ioLoadListener{
+			settings:  sgc.settings,
+			kvGranter: g,
+			kvQueue:   q,
+		},
+	}
+	sgc.mu.stores[storeID] = c
+	return c
+}
+
+// tick is called on every tick. The StoreMetrics are only provided at the
+// start of each adjustment interval.
+func (sgc *StoreGrantCoordinators) tick(
+	ctx context.Context, metrics []StoreMetrics, ticksRemaining int64,
+) {
+	for _, m := range metrics {
+		sgc.getOrCreate(m.StoreID).ioLoadListener.pebbleMetricsTick(ctx, m)
+	}
+	sgc.mu.Lock()
+	stores := make([]*storeGrantCoordinator, 0, len(sgc.mu.stores))
+	for _, c := range sgc.mu.stores {
+		stores = append(stores, c)
+	}
+	sgc.mu.Unlock()
+	for _, c := range stores {
+		c.ioLoadListener.allocateTokensTick(ticksRemaining)
+	}
+}
+
+// Start starts the periodic computation of the write tokens of the stores,
+// based on the metrics obtained from the provider.
+func (sgc *StoreGrantCoordinators) Start(
+	ctx context.Context, stopper *stop.Stopper, provider StoreMetricsProvider,
+) error {
+	// Initialize the stores before returning, so that their queues are
+	// available right away.
+	sgc.tick(ctx, provider.GetStoreMetrics(), tokenAllocationTicks)
+	return stopper.RunAsyncTask(ctx, "admission-store-tokens", func(ctx context.Context) {
+		ticker := time.NewTicker(tokenAllocationTickDuration)
+		defer ticker.Stop()
+		ticks := int64(1)
+		for {
+			select {
+			case <-ticker.C:
+				var metrics []StoreMetrics
+				if ticks%tokenAllocationTicks == 0 {
+					metrics = provider.GetStoreMetrics()
+				}
+				sgc.tick(ctx, metrics, tokenAllocationTicks-ticks%tokenAllocationTicks)
+				ticks++
+			case <-stopper.ShouldQuiesce():
+				return
+			}
+		}
+	})
+}
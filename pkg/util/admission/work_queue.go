@@ -0,0 +1,477 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// WorkPriority represents the priority of work. In a WorkQueue, it is only
+// used to order the work of a tenant: high priority work of a tenant can
+// starve its lower priority work, but not the work of other tenants.
+type WorkPriority int8
+
+const (
+	// LowPri is low priority work.
+	LowPri WorkPriority = math.MinInt8
+	// NormalPri is normal priority work.
+	NormalPri WorkPriority = 0
+	// HighPri is high priority work.
+	HighPri WorkPriority = math.MaxInt8
+)
+
+// WorkInfo provides the information that is used to order work in a
+// WorkQueue.
+type WorkInfo struct {
+	// TenantID is the ID of the tenant which issued the work. In
+	// single-tenant clusters, this is always the system tenant.
+	TenantID roachpb.TenantID
+	// Priority orders the work of a tenant.
+	Priority WorkPriority
+	// CreateTime is the creation time of this work, or of a parent work (e.g.
+	// the start time of the transaction the work belongs to), in nanoseconds
+	// since the epoch. It is used to order the work of a (TenantID, Priority)
+	// pair: earlier work is admitted first.
+	CreateTime int64
+	// BypassAdmission is set for work that must not wait, because it is
+	// essential to the health of the cluster (e.g. node liveness heartbeats).
+	// Such work is admitted immediately, but is accounted for by the
+	// granter, so that the resources it consumes are taken into account.
+	BypassAdmission bool
+}
+
+// WorkQueue maintains a queue of work waiting to be admitted. Work that
+// cannot be admitted immediately waits in the queue until the granter
+// grants it admission. WorkQueue implements the requester interface.
+//
+// The waiting work is ordered by tenant, then priority, then CreateTime.
+// When a grant is available, it goes to the waiting tenant with the least
+// resources in use, which gives a fair share of the resources to each
+// tenant.
+type WorkQueue struct {
+	workKind   WorkKind
+	granter    granter
+	usesTokens bool
+	settings   *cluster.Settings
+	// enabled is the setting which controls whether admission control is
+	// enabled for this queue.
+	enabled *settings.BoolSetting
+	metrics WorkQueueMetrics
+
+	mu struct {
+		syncutil.Mutex
+		// tenants contains the tenants with waiting work, or with admitted
+		// work that is still accounted for.
+		tenants map[uint64]*tenantInfo
+		// tenantHeap contains the tenants with waiting work.
+		tenantHeap tenantHeap
+	}
+}
+
+var _ requester = &WorkQueue{}
+
+func makeWorkQueue(
+	workKind WorkKind,
+	granter granter,
+	st *cluster.Settings,
+	enabled *settings.BoolSetting,
+	usesTokens bool,
+	metrics WorkQueueMetrics,
+) *WorkQueue {
+	q := &WorkQueue{
+		workKind:   workKind,
+		granter:    granter,
+		usesTokens: usesTokens,
+		settings:   st,
+		enabled:    enabled,
+		metrics:    metrics,
+	}
+	q.mu.tenants = make(map[uint64]*tenantInfo)
+	return q
+}
+
+// Admit is called to request admission for some work. If err != nil, the
+// work was not admitted, because ctx was canceled while waiting. Otherwise,
+// enabled reports whether admission control is enabled: if it is, the work
+// was admitted and, for WorkQueues that use slots, the caller must call
+// AdmittedWorkDone once the work is done. If it is not, the caller does not
+// need to do anything further.
+func (q *WorkQueue) Admit(ctx context.Context, info WorkInfo) (enabled bool, err error) {
+	if !q.enabled.Get(&q.settings.SV) {
+		return false, nil
+	}
+	q.metrics.Requested.Inc(1)
+	tenantID := info.TenantID.ToUint64()
+
+	q.mu.Lock()
+	tenant := q.getOrCreateTenantLocked(tenantID)
+	if info.BypassAdmission {
+		q.incTenantUsedLocked(tenant)
+		q.mu.Unlock()
+		q.granter.tookWithoutPermission()
+		q.metrics.Admitted.Inc(1)
+		return true, nil
+	}
+	// Fast path: if no work is waiting, try to get admission directly from
+	// the granter. Otherwise, the work waits behind the work already queued,
+	// which is granted admission as soon as the resources become available.
+	if len(q.mu.tenantHeap) == 0 && q.granter.tryGet() {
+		q.incTenantUsedLocked(tenant)
+		q.mu.Unlock()
+		q.metrics.Admitted.Inc(1)
+		return true, nil
+	}
+	work := &waitingWork{
+		priority:   info.Priority,
+		createTime: info.CreateTime,
+		ch:         make(chan struct{}),
+	}
+	heap.Push(&tenant.waitingWorkHeap, work)
+	if len(tenant.waitingWorkHeap) == 1 {
+		heap.Push(&q.mu.tenantHeap, tenant)
+	}
+	q.mu.Unlock()
+	q.metrics.WaitQueueLength.Inc(1)
+
+	startTime := timeutil.Now()
+	select {
+	case <-work.ch:
+		waitDur := timeutil.Since(startTime)
+		q.metrics.WaitDurations.RecordValue(waitDur.Nanoseconds())
+		q.metrics.Admitted.Inc(1)
+		log.Eventf(ctx, "admitted after waiting %s in %s queue", waitDur, q.workKind)
+		return true, nil
+	case <-ctx.Done():
+		waitDur := timeutil.Since(startTime)
+		q.mu.Lock()
+		if work.granted {
+			// The work was granted admission concurrently with the
+			// cancellation. Return the grant, since it will not be used.
+			q.decTenantUsedLocked(tenant)
+			q.mu.Unlock()
+			q.granter.returnGrant()
+		} else {
+			heap.Remove(&tenant.waitingWorkHeap, work.heapIndex)
+			if len(tenant.waitingWorkHeap) == 0 {
+				heap.Remove(&q.mu.tenantHeap, tenant.heapIndex)
+			}
+			q.maybeRemoveTenantLocked(tenant)
+			q.mu.Unlock()
+			q.metrics.WaitQueueLength.Dec(1)
+		}
+		q.metrics.WaitDurations.RecordValue(waitDur.Nanoseconds())
+		q.metrics.Errored.Inc(1)
+		return true, errors.Wrapf(ctx.Err(),
+			"canceled after waiting %s in %s admission queue", waitDur, q.workKind)
+	}
+}
+
+// AdmittedWorkDone is used to inform the WorkQueue that some admitted work
+// is done, which releases the slot it was using. It must only be called
+// for WorkQueues that use slots, i.e. not for the store write queues.
+func (q *WorkQueue) AdmittedWorkDone(tenantID roachpb.TenantID) {
+	if q.usesTokens {
+		panic(errors.AssertionFailedf("tokens are not returned"))
+	}
+	q.mu.Lock()
+	tenant, ok := q.mu.tenants[tenantID.ToUint64()]
+	if !ok {
+		q.mu.Unlock()
+		panic(errors.AssertionFailedf("tenant %d not found in %s queue", tenantID, q.workKind))
+	}
+	q.decTenantUsedLocked(tenant)
+	q.mu.Unlock()
+	q.granter.returnGrant()
+}
+
+// AdmitKVWork requests admission for a KV BatchRequest from kvQueue and, for
+// writes, from the write WorkQueue of the store the batch is destined to
+// (storeQueue is nil for reads). The store write tokens are acquired first,
+// so that writes waiting for an overloaded store don't hold CPU slots which
+// other work could use. If err == nil, the returned function must be called
+// once the work is done.
+func AdmitKVWork(
+	ctx context.Context, kvQueue, storeQueue *WorkQueue, info WorkInfo,
+) (admittedWorkDone func(), err error) {
+	if storeQueue != nil {
+		// Tokens are not returned, so there is nothing to undo if the work is
+		// not admitted by kvQueue.
+		if _, err := storeQueue.Admit(ctx, info); err != nil {
+			return nil, err
+		}
+	}
+	enabled, err := kvQueue.Admit(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return func() {}, nil
+	}
+	return func() { kvQueue.AdmittedWorkDone(info.TenantID) }, nil
+}
+
+// hasWaitingRequests implements the requester interface.
+func (q *WorkQueue) hasWaitingRequests() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.mu.tenantHeap) > 0
+}
+
+// granted implements the requester interface.
+func (q *WorkQueue) granted() bool {
+	q.mu.Lock()
+	if len(q.mu.tenantHeap) == 0 {
+		q.mu.Unlock()
+		return false
+	}
+	tenant := q.mu.tenantHeap[0]
+	work := heap.Pop(&tenant.waitingWorkHeap).(*waitingWork)
+	work.granted = true
+	if len(tenant.waitingWorkHeap) == 0 {
+		heap.Remove(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+	q.incTenantUsedLocked(tenant)
+	q.mu.Unlock()
+	q.metrics.WaitQueueLength.Dec(1)
+	close(work.ch)
+	return true
+}
+
+// resetTokenUsage is called periodically for WorkQueues that use tokens, to
+// forget about the tokens used in the past, so that fair sharing between
+// tenants is based on their recent usage.
+func (q *WorkQueue) resetTokenUsage() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, tenant := range q.mu.tenants {
+		tenant.used = 0
+		q.maybeRemoveTenantLocked(tenant)
+	}
+	heap.Init(&q.mu.tenantHeap)
+}
+
+func (q *WorkQueue) getOrCreateTenantLocked(tenantID uint64) *tenantInfo {
+	tenant, ok := q.mu.tenants[tenantID]
+	if !ok {
+		tenant = &tenantInfo{id: tenantID, heapIndex: -1}
+		q.mu.tenants[tenantID] = tenant
+	}
+	return tenant
+}
+
+func (q *WorkQueue) incTenantUsedLocked(tenant *tenantInfo) {
+	tenant.used++
+	if tenant.heapIndex >= 0 {
+		heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+}
+
+func (q *WorkQueue) decTenantUsedLocked(tenant *tenantInfo) {
+	if tenant.used == 0 {
+		if !q.usesTokens {
+			panic(errors.AssertionFailedf(
+				"tenant %d has no slots in use in %s queue", tenant.id, q.workKind))
+		}
+		// The token usage was reset in the meantime.
+		return
+	}
+	tenant.used--
+	if tenant.heapIndex >= 0 {
+		heap.Fix(&q.mu.tenantHeap, tenant.heapIndex)
+	}
+	q.maybeRemoveTenantLocked(tenant)
+}
+
+// maybeRemoveTenantLocked forgets about a tenant that has neither waiting
+// work nor resources in use.
+func (q *WorkQueue) maybeRemoveTenantLocked(tenant *tenantInfo) {
+	if tenant.used == 0 && len(tenant.waitingWorkHeap) == 0 {
+		delete(q.mu.tenants, tenant.id)
+	}
+}
+
+// tenantInfo is the information about a tenant in a WorkQueue.
+type tenantInfo struct {
+	id uint64
+	// used is the number of slots in use by the tenant, for WorkQueues that
+	// use slots, or the number of tokens used by the tenant since the last
+	// call to resetTokenUsage, for WorkQueues that use tokens.
+	used            uint64
+	waitingWorkHeap waitingWorkHeap
+	// heapIndex is the index of the tenant in the tenantHeap, or -1 if the
+	// tenant has no waiting work.
+	heapIndex int
+}
+
+// tenantHeap is a heap of the tenants with waiting work, ordered by the
+// resources they use.
+type tenantHeap []*tenantInfo
+
+var _ heap.Interface = (*tenantHeap)(nil)
+
+func (th *tenantHeap) Len() int {
+	return len(*th)
+}
+
+func (th *tenantHeap) Less(i, j int) bool {
+	return (*th)[i].used < (*th)[j].used
+}
+
+func (th *tenantHeap) Swap(i, j int) {
+	(*th)[i], (*th)[j] = (*th)[j], (*th)[i]
+	(*th)[i].heapIndex = i
+	(*th)[j].heapIndex = j
+}
+
+func (th *tenantHeap) Push(x interface{}) {
+	item := x.(*tenantInfo)
+	item.heapIndex = len(*th)
+	*th = append(*th, item)
+}
+
+func (th *tenantHeap) Pop() interface{} {
+	old := *th
+	n := len(old)
+	item := old[n-1]
+	item.heapIndex = -1
+	old[n-1] = nil
+	*th = old[0 : n-1]
+	return item
+}
+
+// waitingWork is a piece of work waiting for admission.
+type waitingWork struct {
+	priority   WorkPriority
+	createTime int64
+	// ch is closed when the work is granted admission.
+	ch chan struct{}
+	// granted is set, under WorkQueue.mu, when the work is granted admission.
+	granted   bool
+	heapIndex int
+}
+
+// waitingWorkHeap is a heap of the waiting work of a tenant, ordered by
+// priority, then by CreateTime.
+type waitingWorkHeap []*waitingWork
+
+var _ heap.Interface = (*waitingWorkHeap)(nil)
+
+func (wwh *waitingWorkHeap) Len() int {
+	return len(*wwh)
+}
+
+func (wwh *waitingWorkHeap) Less(i, j int) bool {
+	if (*wwh)[i].priority == (*wwh)[j].priority {
+		return (*wwh)[i].createTime < (*wwh)[j].createTime
+	}
+	return (*wwh)[i].priority > (*wwh)[j].priority
+}
+
+func (wwh *waitingWorkHeap) Swap(i, j int) {
+	(*wwh)[i], (*wwh)[j] = (*wwh)[j], (*wwh)[i]
+	(*wwh)[i].heapIndex = i
+	(*wwh)[j].heapIndex = j
+}
+
+func (wwh *waitingWorkHeap) Push(x interface{}) {
+	item := x.(*waitingWork)
+	item.heapIndex = len(*wwh)
+	*wwh = append(*wwh, item)
+}
+
+func (wwh *waitingWorkHeap) Pop() interface{} {
+	old := *wwh
+	n := len(old)
+	item := old[n-1]
+	item.heapIndex = -1
+	old[n-1] = nil
+	*wwh = old[0 : n-1]
+	return item
+}
+
+var (
+	requestedMeta = metric.Metadata{
+		Name:        "admission.requested.",
+		Help:        "Number of requests",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	admittedMeta = metric.Metadata{
+		Name:        "admission.admitted.",
+		Help:        "Number of requests admitted",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	erroredMeta = metric.Metadata{
+		Name:        "admission.errored.",
+		Help:        "Number of requests not admitted due to error",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+	waitDurationsMeta = metric.Metadata{
+		Name:        "admission.wait_durations.",
+		Help:        "Wait time durations for requests that waited",
+		Measurement: "Wait time Duration",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	waitQueueLengthMeta = metric.Metadata{
+		Name:        "admission.wait_queue_length.",
+		Help:        "Length of wait queue",
+		Measurement: "Requests",
+		Unit:        metric.Unit_COUNT,
+	}
+)
+
+// addName returns a copy of the metadata, with the given name appended to
+// the metric name and help.
+func addName(name string, meta metric.Metadata) metric.Metadata {
+	rv := meta
+	rv.Name = rv.Name + name
+	rv.Help = rv.Help + " for " + name
+	return rv
+}
+
+// WorkQueueMetrics are the metrics of a WorkQueue. The WorkQueues of the
+// stores of a node share their metrics.
+type WorkQueueMetrics struct {
+	Requested       *metric.Counter
+	Admitted        *metric.Counter
+	Errored         *metric.Counter
+	WaitDurations   *metric.Histogram
+	WaitQueueLength *metric.Gauge
+}
+
+var _ metric.Struct = WorkQueueMetrics{}
+
+// MetricStruct implements the metric.Struct interface.
+func (WorkQueueMetrics) MetricStruct() {}
+
+func makeWorkQueueMetrics(name string, histogramWindow time.Duration) WorkQueueMetrics {
+	return WorkQueueMetrics{
+		Requested:       metric.NewCounter(addName(name, requestedMeta)),
+		Admitted:        metric.NewCounter(addName(name, admittedMeta)),
+		Errored:         metric.NewCounter(addName(name, erroredMeta)),
+		WaitDurations:   metric.NewLatency(addName(name, waitDurationsMeta), histogramWindow),
+		WaitQueueLength: metric.NewGauge(addName(name, waitQueueLengthMeta)),
+	}
+}
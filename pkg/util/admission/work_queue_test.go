@@ -0,0 +1,270 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package admission
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// testGranter is a granter which grants as many times as it is allowed to
+// by the test.
+type testGranter struct {
+	mu struct {
+		syncutil.Mutex
+		available             int
+		returned              int
+		tookWithoutPermission int
+	}
+}
+
+var _ granter = &testGranter{}
+
+func (tg *testGranter) tryGet() bool {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	if tg.mu.available > 0 {
+		tg.mu.available--
+		return true
+	}
+	return false
+}
+
+func (tg *testGranter) returnGrant() {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.mu.returned++
+}
+
+func (tg *testGranter) tookWithoutPermission() {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.mu.tookWithoutPermission++
+}
+
+func makeTestWorkQueue(t *testing.T, usesTokens bool) (*WorkQueue, *testGranter) {
+	st := cluster.MakeTestingClusterSettings()
+	KVAdmissionControlEnabled.Override(&st.SV, true)
+	g := &testGranter{}
+	q := makeWorkQueue(KVWork, g, st, KVAdmissionControlEnabled, usesTokens,
+		makeWorkQueueMetrics("test", time.Second))
+	return q, g
+}
+
+// admitAsync requests admission in a separate goroutine, and returns a
+// channel on which the result is delivered.
+func admitAsync(q *WorkQueue, ctx context.Context, info WorkInfo) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		enabled, err := q.Admit(ctx, info)
+		if err == nil && !enabled {
+			panic("admission control unexpectedly disabled")
+		}
+		ch <- err
+	}()
+	return ch
+}
+
+func waitForQueueLength(t *testing.T, q *WorkQueue, n int64) {
+	testutils.SucceedsSoon(t, func() error {
+		if l := q.metrics.WaitQueueLength.Value(); l != n {
+			return errors.Errorf("expected queue length %d, found %d", n, l)
+		}
+		return nil
+	})
+}
+
+func TestWorkQueueDisabled(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q, _ := makeTestWorkQueue(t, false /* usesTokens */)
+	KVAdmissionControlEnabled.Override(&q.settings.SV, false)
+	enabled, err := q.Admit(context.Background(), WorkInfo{TenantID: roachpb.SystemTenantID})
+	require.NoError(t, err)
+	require.False(t, enabled)
+	require.Equal(t, int64(0), q.metrics.Requested.Count())
+}
+
+func TestWorkQueueOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	q, g := makeTestWorkQueue(t, false /* usesTokens */)
+	t1 := roachpb.MakeTenantID(2)
+	t2 := roachpb.MakeTenantID(3)
+
+	// Fast path.
+	g.mu.available = 1
+	enabled, err := q.Admit(ctx, WorkInfo{TenantID: t1})
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	// No more grants: the work waits.
+	low := admitAsync(q, ctx, WorkInfo{TenantID: t1, Priority: LowPri, CreateTime: 1})
+	waitForQueueLength(t, q, 1)
+	high := admitAsync(q, ctx, WorkInfo{TenantID: t1, Priority: HighPri, CreateTime: 3})
+	waitForQueueLength(t, q, 2)
+	other := admitAsync(q, ctx, WorkInfo{TenantID: t2, Priority: LowPri, CreateTime: 2})
+	waitForQueueLength(t, q, 3)
+	require.True(t, q.hasWaitingRequests())
+
+	// t2 uses no slot, while t1 uses one, so t2 goes first.
+	require.True(t, q.granted())
+	require.NoError(t, <-other)
+	// Within t1, the high priority work goes first.
+	require.True(t, q.granted())
+	require.NoError(t, <-high)
+	require.True(t, q.granted())
+	require.NoError(t, <-low)
+	require.False(t, q.hasWaitingRequests())
+	require.False(t, q.granted())
+
+	for _, tenantID := range []roachpb.TenantID{t1, t1, t1, t2} {
+		q.AdmittedWorkDone(tenantID)
+	}
+	g.mu.Lock()
+	require.Equal(t, 4, g.mu.returned)
+	g.mu.Unlock()
+	q.mu.Lock()
+	require.Equal(t, 0, len(q.mu.tenants))
+	q.mu.Unlock()
+	require.Equal(t, int64(4), q.metrics.Admitted.Count())
+	require.Equal(t, int64(0), q.metrics.WaitQueueLength.Value())
+}
+
+func TestWorkQueueCreateTimeOrdering(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	q, _ := makeTestWorkQueue(t, false /* usesTokens */)
+	var chans []<-chan error
+	for _, createTime := range []int64{3, 1, 2} {
+		chans = append(chans, admitAsync(q, ctx, WorkInfo{
+			TenantID: roachpb.SystemTenantID, CreateTime: createTime,
+		}))
+		waitForQueueLength(t, q, int64(len(chans)))
+	}
+	// The work is admitted in CreateTime order.
+	for _, i := range []int{1, 2, 0} {
+		require.True(t, q.granted())
+		require.NoError(t, <-chans[i])
+	}
+}
+
+func TestWorkQueueBypassAdmission(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q, g := makeTestWorkQueue(t, false /* usesTokens */)
+	enabled, err := q.Admit(context.Background(), WorkInfo{
+		TenantID: roachpb.SystemTenantID, BypassAdmission: true,
+	})
+	require.NoError(t, err)
+	require.True(t, enabled)
+	g.mu.Lock()
+	require.Equal(t, 1, g.mu.tookWithoutPermission)
+	g.mu.Unlock()
+	q.AdmittedWorkDone(roachpb.SystemTenantID)
+}
+
+func TestWorkQueueCancel(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q, _ := makeTestWorkQueue(t, false /* usesTokens */)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := admitAsync(q, ctx, WorkInfo{TenantID: roachpb.SystemTenantID})
+	waitForQueueLength(t, q, 1)
+	cancel()
+	require.Error(t, <-ch)
+	require.False(t, q.hasWaitingRequests())
+	require.Equal(t, int64(1), q.metrics.Errored.Count())
+	require.Equal(t, int64(0), q.metrics.WaitQueueLength.Value())
+	q.mu.Lock()
+	require.Equal(t, 0, len(q.mu.tenants))
+	q.mu.Unlock()
+}
+
+func TestWorkQueueTokens(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	q, g := makeTestWorkQueue(t, true /* usesTokens */)
+	t1 := roachpb.MakeTenantID(2)
+	t2 := roachpb.MakeTenantID(3)
+	g.mu.available = 2
+	for i := 0; i < 2; i++ {
+		_, err := q.Admit(ctx, WorkInfo{TenantID: t1})
+		require.NoError(t, err)
+	}
+	require.Panics(t, func() { q.AdmittedWorkDone(t1) })
+
+	// t1 used tokens recently, so t2 goes first.
+	ch1 := admitAsync(q, ctx, WorkInfo{TenantID: t1})
+	waitForQueueLength(t, q, 1)
+	ch2 := admitAsync(q, ctx, WorkInfo{TenantID: t2})
+	waitForQueueLength(t, q, 2)
+	require.True(t, q.granted())
+	require.NoError(t, <-ch2)
+
+	// Once the usage is reset, the tenants without waiting work are
+	// forgotten.
+	q.resetTokenUsage()
+	q.mu.Lock()
+	require.Equal(t, 1, len(q.mu.tenants))
+	q.mu.Unlock()
+	require.True(t, q.granted())
+	require.NoError(t, <-ch1)
+}
+
+// TestAdmitKVWork verifies that a write waiting for store write tokens does
+// not hold a KV slot, which reads can use in the meantime.
+func TestAdmitKVWork(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	kvQ, kvG := makeTestWorkQueue(t, false /* usesTokens */)
+	storeQ, _ := makeTestWorkQueue(t, true /* usesTokens */)
+	info := WorkInfo{TenantID: roachpb.SystemTenantID}
+	kvG.mu.available = 1
+
+	writeC := make(chan func(), 1)
+	go func() {
+		done, err := AdmitKVWork(ctx, kvQ, storeQ, info)
+		if err != nil {
+			panic(err)
+		}
+		writeC <- done
+	}()
+	waitForQueueLength(t, storeQ, 1)
+
+	// The read gets the only slot.
+	readDone, err := AdmitKVWork(ctx, kvQ, nil /* storeQueue */, info)
+	require.NoError(t, err)
+
+	// Once it gets tokens, the write waits for the slot held by the read.
+	require.True(t, storeQ.granted())
+	waitForQueueLength(t, kvQ, 1)
+	readDone()
+	require.True(t, kvQ.granted())
+	(<-writeC)()
+
+	kvG.mu.Lock()
+	require.Equal(t, 2, kvG.mu.returned)
+	kvG.mu.Unlock()
+}
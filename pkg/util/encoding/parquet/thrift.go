@@ -0,0 +1,130 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import "encoding/binary"
+
+// Type identifiers of the Thrift compact protocol.
+const (
+	thriftStop      = 0
+	thriftTrue      = 1
+	thriftFalse     = 2
+	thriftByte      = 3
+	thriftI32       = 5
+	thriftI64       = 6
+	thriftBinary    = 8
+	thriftList      = 9
+	thriftStruct    = 12
+	thriftMaxDelta  = 15
+	thriftShortList = 15
+)
+
+// thriftWriter encodes Thrift structs using the compact protocol, which is how
+// all the metadata of a Parquet file is serialized. Only the types used by the
+// Parquet metadata are supported.
+type thriftWriter struct {
+	buf []byte
+	// lastField is the ID of the last field written in the innermost open
+	// struct, and parents holds the IDs for the enclosing structs.
+	lastField int16
+	parents   []int16
+	scratch   [binary.MaxVarintLen64]byte
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf = append(w.buf, w.scratch[:n]...)
+}
+
+func (w *thriftWriter) zigzag(v int64) {
+	w.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - w.lastField; delta > 0 && delta <= thriftMaxDelta {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.zigzag(int64(id))
+	}
+	w.lastField = id
+}
+
+// structBegin starts a struct. The caller must already have written the
+// header of the field or list which contains the struct, if any.
+func (w *thriftWriter) structBegin() {
+	w.parents = append(w.parents, w.lastField)
+	w.lastField = 0
+}
+
+func (w *thriftWriter) structEnd() {
+	w.buf = append(w.buf, thriftStop)
+	w.lastField = w.parents[len(w.parents)-1]
+	w.parents = w.parents[:len(w.parents)-1]
+}
+
+func (w *thriftWriter) structField(id int16) {
+	w.fieldHeader(id, thriftStruct)
+	w.structBegin()
+}
+
+// emptyStructField writes a field holding a struct without fields, which is
+// how the members of unions such as LogicalType are usually represented.
+func (w *thriftWriter) emptyStructField(id int16) {
+	w.structField(id)
+	w.structEnd()
+}
+
+func (w *thriftWriter) boolField(id int16, v bool) {
+	if v {
+		w.fieldHeader(id, thriftTrue)
+	} else {
+		w.fieldHeader(id, thriftFalse)
+	}
+}
+
+func (w *thriftWriter) byteField(id int16, v int8) {
+	w.fieldHeader(id, thriftByte)
+	w.buf = append(w.buf, byte(v))
+}
+
+func (w *thriftWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *thriftWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *thriftWriter) stringField(id int16, v string) {
+	w.fieldHeader(id, thriftBinary)
+	w.string(v)
+}
+
+func (w *thriftWriter) string(v string) {
+	w.uvarint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// listField writes the header of a list of n elements of the given type. The
+// elements must be written by the caller: i32s with zigzag, strings with
+// string and structs with structBegin and structEnd.
+func (w *thriftWriter) listField(id int16, elemType byte, n int) {
+	w.fieldHeader(id, thriftList)
+	if n < thriftShortList {
+		w.buf = append(w.buf, byte(n)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.uvarint(uint64(n))
+	}
+}
@@ -0,0 +1,699 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package parquet implements a writer for the Apache Parquet file format. See
+// https://github.com/apache/parquet-format for the specification.
+//
+// Only the subset of the format needed to export the results of SQL queries is
+// supported: a flat schema of nullable columns, each of which holds either a
+// single value or a list of values per row. Every file consists of a single
+// row group with one PLAIN encoded data page per column.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+)
+
+// PhysicalType is the type used to store the values of a column.
+type PhysicalType int32
+
+// The physical types of the Parquet format. The deprecated INT96 type is not
+// supported.
+const (
+	TypeBoolean           PhysicalType = 0
+	TypeInt32             PhysicalType = 1
+	TypeInt64             PhysicalType = 2
+	TypeFloat             PhysicalType = 4
+	TypeDouble            PhysicalType = 5
+	TypeByteArray         PhysicalType = 6
+	TypeFixedLenByteArray PhysicalType = 7
+)
+
+// LogicalType annotates a column with how its stored values are to be
+// interpreted.
+type LogicalType int
+
+const (
+	// LogicalNone means the values are to be interpreted as their physical type.
+	LogicalNone LogicalType = iota
+	// LogicalString annotates UTF-8 encoded ByteArray values.
+	LogicalString
+	// LogicalEnum annotates ByteArray values holding the labels of an enum.
+	LogicalEnum
+	// LogicalJSON annotates ByteArray values holding JSON documents.
+	LogicalJSON
+	// LogicalUUID annotates FixedLenByteArray values of length 16.
+	LogicalUUID
+	// LogicalDate annotates Int32 values holding days since the Unix epoch.
+	LogicalDate
+	// LogicalDecimal annotates ByteArray values holding the unscaled value of a
+	// decimal as a big-endian two's complement integer. The Precision and Scale
+	// of the column must be set.
+	LogicalDecimal
+	// LogicalTimeMicros annotates Int64 values holding microseconds since
+	// midnight, in an unspecified time zone.
+	LogicalTimeMicros
+	// LogicalTimestampMicros annotates Int64 values holding microseconds since
+	// the Unix epoch, in an unspecified time zone.
+	LogicalTimestampMicros
+	// LogicalTimestampMicrosUTC annotates Int64 values holding microseconds
+	// since the Unix epoch in UTC.
+	LogicalTimestampMicrosUTC
+	// LogicalInt16 annotates Int32 values which fit in 16 bits.
+	LogicalInt16
+	// LogicalInt32 annotates signed Int32 values.
+	LogicalInt32
+	// LogicalInt64 annotates signed Int64 values.
+	LogicalInt64
+)
+
+// Codec is a compression codec for the data pages of a file.
+type Codec int32
+
+// The supported compression codecs.
+const (
+	CodecUncompressed Codec = 0
+	CodecSnappy       Codec = 1
+	CodecGzip         Codec = 2
+)
+
+// Column describes a column of a file.
+type Column struct {
+	Name    string
+	Type    PhysicalType
+	Logical LogicalType
+	// TypeLength is the length of the values of a TypeFixedLenByteArray column.
+	TypeLength int32
+	// Precision and Scale describe the values of a LogicalDecimal column.
+	Precision, Scale int32
+	// List indicates that the value of the column in each row is a list of
+	// values of the column's type rather than a single value.
+	List bool
+}
+
+// Values of the Parquet enums used in the metadata.
+const (
+	repetitionOptional = 1
+	repetitionRepeated = 2
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+
+	convertedUTF8            = 0
+	convertedList            = 3
+	convertedEnum            = 4
+	convertedDecimal         = 5
+	convertedDate            = 6
+	convertedTimestampMicros = 10
+	convertedInt16           = 16
+	convertedInt32           = 17
+	convertedInt64           = 18
+	convertedJSON            = 19
+)
+
+const magic = "PAR1"
+
+const createdBy = "CockroachDB"
+
+// Writer writes rows to a Parquet file. The rows are buffered in memory until
+// the Writer is closed.
+type Writer struct {
+	w       io.Writer
+	codec   Codec
+	columns []columnBuffer
+	numRows int64
+}
+
+// NewWriter returns a Writer which writes a file with the given columns to w.
+func NewWriter(w io.Writer, columns []Column, codec Codec) (*Writer, error) {
+	switch codec {
+	case CodecUncompressed, CodecSnappy, CodecGzip:
+	default:
+		return nil, errors.Errorf("unsupported compression codec %d", codec)
+	}
+	pw := &Writer{w: w, codec: codec, columns: make([]columnBuffer, len(columns))}
+	for i := range columns {
+		col := &columns[i]
+		if col.Name == "" {
+			return nil, errors.Errorf("column %d has no name", i)
+		}
+		switch col.Type {
+		case TypeBoolean, TypeInt32, TypeInt64, TypeFloat, TypeDouble, TypeByteArray:
+		case TypeFixedLenByteArray:
+			if col.TypeLength <= 0 {
+				return nil, errors.Errorf("column %q has invalid length %d", col.Name, col.TypeLength)
+			}
+		default:
+			return nil, errors.Errorf("column %q has unsupported type %d", col.Name, col.Type)
+		}
+		if col.Logical == LogicalDecimal && (col.Precision <= 0 || col.Scale < 0 || col.Scale > col.Precision) {
+			return nil, errors.Errorf("column %q has invalid precision %d and scale %d",
+				col.Name, col.Precision, col.Scale)
+		}
+		pw.columns[i].col = *col
+	}
+	return pw, nil
+}
+
+// NumRows returns the number of rows added to the Writer.
+func (w *Writer) NumRows() int64 {
+	return w.numRows
+}
+
+// AddRow adds a row to the file. The row must have a value for every column,
+// which is nil for NULL and otherwise has the Go type corresponding to the
+// column's physical type: bool, int32, int64, float32, float64 or []byte for
+// both byte array types. The values of List columns are []interface{} whose
+// elements follow the same rules.
+func (w *Writer) AddRow(row []interface{}) error {
+	if len(row) != len(w.columns) {
+		return errors.Errorf("expected %d values, found %d", len(w.columns), len(row))
+	}
+	for i := range w.columns {
+		if err := w.columns[i].checkValue(row[i]); err != nil {
+			return err
+		}
+	}
+	for i := range w.columns {
+		w.columns[i].add(row[i])
+	}
+	w.numRows++
+	return nil
+}
+
+// Close writes the file. The Writer cannot be used afterwards. It does not
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	cw := &countingWriter{w: w.w}
+	if _, err := io.WriteString(cw, magic); err != nil {
+		return err
+	}
+
+	chunks := make([]columnChunk, len(w.columns))
+	if w.numRows > 0 {
+		for i := range w.columns {
+			var err error
+			if chunks[i], err = w.columns[i].writeChunk(cw, w.codec); err != nil {
+				return err
+			}
+			w.columns[i] = columnBuffer{col: w.columns[i].col}
+		}
+	}
+
+	footer := w.encodeFileMetaData(chunks)
+	if _, err := cw.Write(footer); err != nil {
+		return err
+	}
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(len(footer)))
+	if _, err := cw.Write(footerLen[:]); err != nil {
+		return err
+	}
+	_, err := io.WriteString(cw, magic)
+	return err
+}
+
+// encodeFileMetaData encodes the FileMetaData struct which makes up the footer
+// of the file.
+func (w *Writer) encodeFileMetaData(chunks []columnChunk) []byte {
+	t := &thriftWriter{}
+	t.structBegin()
+	t.i32Field(1, 1 /* version */)
+
+	numElements := 1
+	for i := range w.columns {
+		if w.columns[i].col.List {
+			numElements += 3
+		} else {
+			numElements++
+		}
+	}
+	t.listField(2, thriftStruct, numElements)
+	t.structBegin()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(w.columns)))
+	t.structEnd()
+	for i := range w.columns {
+		encodeSchemaElements(t, &w.columns[i].col)
+	}
+
+	t.i64Field(3, w.numRows)
+	if w.numRows == 0 {
+		t.listField(4, thriftStruct, 0)
+	} else {
+		t.listField(4, thriftStruct, 1)
+		t.structBegin()
+		t.listField(1, thriftStruct, len(chunks))
+		var totalSize int64
+		for i := range chunks {
+			encodeColumnChunk(t, &w.columns[i].col, &chunks[i])
+			totalSize += chunks[i].uncompressedSize
+		}
+		t.i64Field(2, totalSize)
+		t.i64Field(3, w.numRows)
+		t.structEnd()
+	}
+	t.stringField(6, createdBy)
+	t.structEnd()
+	return t.buf
+}
+
+// encodeSchemaElements encodes the SchemaElements describing a column. List
+// columns use the three-level structure required by the specification:
+//
+//	optional group <name> (LIST) {
+//	  repeated group list {
+//	    optional <type> element;
+//	  }
+//	}
+func encodeSchemaElements(t *thriftWriter, col *Column) {
+	name := col.Name
+	if col.List {
+		t.structBegin()
+		t.i32Field(3, repetitionOptional)
+		t.stringField(4, col.Name)
+		t.i32Field(5, 1 /* num_children */)
+		t.i32Field(6, convertedList)
+		t.structField(10)
+		t.emptyStructField(3 /* LIST */)
+		t.structEnd()
+		t.structEnd()
+
+		t.structBegin()
+		t.i32Field(3, repetitionRepeated)
+		t.stringField(4, "list")
+		t.i32Field(5, 1 /* num_children */)
+		t.structEnd()
+
+		name = "element"
+	}
+
+	t.structBegin()
+	t.i32Field(1, int32(col.Type))
+	if col.Type == TypeFixedLenByteArray {
+		t.i32Field(2, col.TypeLength)
+	}
+	t.i32Field(3, repetitionOptional)
+	t.stringField(4, name)
+	if converted, ok := col.convertedType(); ok {
+		t.i32Field(6, converted)
+	}
+	if col.Logical == LogicalDecimal {
+		t.i32Field(7, col.Scale)
+		t.i32Field(8, col.Precision)
+	}
+	if col.Logical != LogicalNone {
+		t.structField(10)
+		encodeLogicalType(t, col)
+		t.structEnd()
+	}
+	t.structEnd()
+}
+
+// convertedType returns the deprecated ConvertedType equivalent to the logical
+// type of the column, which older readers rely on. The TIME_MICROS and
+// TIMESTAMP_MICROS converted types imply values adjusted to UTC, so there is
+// none for LogicalTimeMicros or LogicalTimestampMicros.
+func (col *Column) convertedType() (int32, bool) {
+	switch col.Logical {
+	case LogicalString:
+		return convertedUTF8, true
+	case LogicalEnum:
+		return convertedEnum, true
+	case LogicalJSON:
+		return convertedJSON, true
+	case LogicalDate:
+		return convertedDate, true
+	case LogicalDecimal:
+		return convertedDecimal, true
+	case LogicalTimestampMicrosUTC:
+		return convertedTimestampMicros, true
+	case LogicalInt16:
+		return convertedInt16, true
+	case LogicalInt32:
+		return convertedInt32, true
+	case LogicalInt64:
+		return convertedInt64, true
+	}
+	return 0, false
+}
+
+// encodeLogicalType encodes the member of the LogicalType union corresponding
+// to the logical type of the column.
+func encodeLogicalType(t *thriftWriter, col *Column) {
+	switch col.Logical {
+	case LogicalString:
+		t.emptyStructField(1)
+	case LogicalEnum:
+		t.emptyStructField(4)
+	case LogicalDecimal:
+		t.structField(5)
+		t.i32Field(1, col.Scale)
+		t.i32Field(2, col.Precision)
+		t.structEnd()
+	case LogicalDate:
+		t.emptyStructField(6)
+	case LogicalTimeMicros:
+		t.structField(7)
+		t.boolField(1, false /* isAdjustedToUTC */)
+		t.structField(2)
+		t.emptyStructField(2 /* MICROS */)
+		t.structEnd()
+		t.structEnd()
+	case LogicalTimestampMicros, LogicalTimestampMicrosUTC:
+		t.structField(8)
+		t.boolField(1, col.Logical == LogicalTimestampMicrosUTC /* isAdjustedToUTC */)
+		t.structField(2)
+		t.emptyStructField(2 /* MICROS */)
+		t.structEnd()
+		t.structEnd()
+	case LogicalInt16:
+		encodeIntType(t, 16)
+	case LogicalInt32:
+		encodeIntType(t, 32)
+	case LogicalInt64:
+		encodeIntType(t, 64)
+	case LogicalJSON:
+		t.emptyStructField(12)
+	case LogicalUUID:
+		t.emptyStructField(14)
+	}
+}
+
+func encodeIntType(t *thriftWriter, bitWidth int8) {
+	t.structField(10)
+	t.byteField(1, bitWidth)
+	t.boolField(2, true /* isSigned */)
+	t.structEnd()
+}
+
+// encodeColumnChunk encodes the ColumnChunk struct describing the data of a
+// column.
+func encodeColumnChunk(t *thriftWriter, col *Column, chunk *columnChunk) {
+	t.structBegin()
+	t.i64Field(2, chunk.offset)
+	t.structField(3)
+	t.i32Field(1, int32(col.Type))
+	t.listField(2, thriftI32, 2)
+	t.zigzag(encodingPlain)
+	t.zigzag(encodingRLE)
+	if col.List {
+		t.listField(3, thriftBinary, 3)
+		t.string(col.Name)
+		t.string("list")
+		t.string("element")
+	} else {
+		t.listField(3, thriftBinary, 1)
+		t.string(col.Name)
+	}
+	t.i32Field(4, int32(chunk.codec))
+	t.i64Field(5, chunk.numValues)
+	t.i64Field(6, chunk.uncompressedSize)
+	t.i64Field(7, chunk.compressedSize)
+	t.i64Field(9, chunk.offset)
+	t.structEnd()
+	t.structEnd()
+}
+
+// columnChunk describes the data of a column once it has been written.
+type columnChunk struct {
+	codec            Codec
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+// columnBuffer accumulates the values of a column until they are written.
+type columnBuffer struct {
+	col Column
+	// repLevels and defLevels hold the repetition and definition level of every
+	// value, including NULLs and the elements of lists.
+	repLevels []uint8
+	defLevels []uint8
+	// values holds the PLAIN encoding of all the non-NULL values, except for
+	// booleans which are accumulated in bools instead.
+	values []byte
+	bools  []bool
+}
+
+// maxDefLevel returns the definition level of a non-NULL value. A list column
+// has three levels: the list, the repeated group and the element.
+func (c *columnBuffer) maxDefLevel() uint8 {
+	if c.col.List {
+		return 3
+	}
+	return 1
+}
+
+func (c *columnBuffer) maxRepLevel() uint8 {
+	if c.col.List {
+		return 1
+	}
+	return 0
+}
+
+func (c *columnBuffer) checkValue(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+	if !c.col.List {
+		return c.checkScalar(v)
+	}
+	elems, ok := v.([]interface{})
+	if !ok {
+		return errors.Errorf("column %q: expected a list, found %T", c.col.Name, v)
+	}
+	for _, e := range elems {
+		if e == nil {
+			continue
+		}
+		if err := c.checkScalar(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *columnBuffer) checkScalar(v interface{}) error {
+	var ok bool
+	switch c.col.Type {
+	case TypeBoolean:
+		_, ok = v.(bool)
+	case TypeInt32:
+		_, ok = v.(int32)
+	case TypeInt64:
+		_, ok = v.(int64)
+	case TypeFloat:
+		_, ok = v.(float32)
+	case TypeDouble:
+		_, ok = v.(float64)
+	case TypeByteArray:
+		var b []byte
+		if b, ok = v.([]byte); ok && len(b) > math.MaxInt32 {
+			return errors.Errorf("column %q: value of %d bytes is too large", c.col.Name, len(b))
+		}
+	case TypeFixedLenByteArray:
+		var b []byte
+		if b, ok = v.([]byte); ok && len(b) != int(c.col.TypeLength) {
+			return errors.Errorf("column %q: expected a value of %d bytes, found %d bytes",
+				c.col.Name, c.col.TypeLength, len(b))
+		}
+	}
+	if !ok {
+		return errors.Errorf("column %q: unexpected value of type %T", c.col.Name, v)
+	}
+	return nil
+}
+
+// add adds a value which has been validated by checkValue.
+func (c *columnBuffer) add(v interface{}) {
+	if v == nil {
+		c.repLevels = append(c.repLevels, 0)
+		c.defLevels = append(c.defLevels, 0)
+		return
+	}
+	if !c.col.List {
+		c.repLevels = append(c.repLevels, 0)
+		c.defLevels = append(c.defLevels, 1)
+		c.addScalar(v)
+		return
+	}
+	elems := v.([]interface{})
+	if len(elems) == 0 {
+		c.repLevels = append(c.repLevels, 0)
+		c.defLevels = append(c.defLevels, 1)
+		return
+	}
+	for i, e := range elems {
+		if i == 0 {
+			c.repLevels = append(c.repLevels, 0)
+		} else {
+			c.repLevels = append(c.repLevels, 1)
+		}
+		if e == nil {
+			c.defLevels = append(c.defLevels, 2)
+			continue
+		}
+		c.defLevels = append(c.defLevels, 3)
+		c.addScalar(e)
+	}
+}
+
+func (c *columnBuffer) addScalar(v interface{}) {
+	var scratch [8]byte
+	switch c.col.Type {
+	case TypeBoolean:
+		c.bools = append(c.bools, v.(bool))
+	case TypeInt32:
+		binary.LittleEndian.PutUint32(scratch[:], uint32(v.(int32)))
+		c.values = append(c.values, scratch[:4]...)
+	case TypeInt64:
+		binary.LittleEndian.PutUint64(scratch[:], uint64(v.(int64)))
+		c.values = append(c.values, scratch[:8]...)
+	case TypeFloat:
+		binary.LittleEndian.PutUint32(scratch[:], math.Float32bits(v.(float32)))
+		c.values = append(c.values, scratch[:4]...)
+	case TypeDouble:
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v.(float64)))
+		c.values = append(c.values, scratch[:8]...)
+	case TypeByteArray:
+		b := v.([]byte)
+		binary.LittleEndian.PutUint32(scratch[:], uint32(len(b)))
+		c.values = append(c.values, scratch[:4]...)
+		c.values = append(c.values, b...)
+	case TypeFixedLenByteArray:
+		c.values = append(c.values, v.([]byte)...)
+	}
+}
+
+// writeChunk writes the column as a single data page.
+func (c *columnBuffer) writeChunk(w *countingWriter, codec Codec) (columnChunk, error) {
+	var page []byte
+	if c.maxRepLevel() > 0 {
+		page = appendLevels(page, c.repLevels)
+	}
+	page = appendLevels(page, c.defLevels)
+	if c.col.Type == TypeBoolean {
+		page = appendBitPacked(page, c.bools)
+	} else {
+		page = append(page, c.values...)
+	}
+
+	compressed, err := compress(codec, page)
+	if err != nil {
+		return columnChunk{}, err
+	}
+
+	t := &thriftWriter{}
+	t.structBegin()
+	t.i32Field(1, pageTypeData)
+	t.i32Field(2, int32(len(page)))
+	t.i32Field(3, int32(len(compressed)))
+	t.structField(5)
+	t.i32Field(1, int32(len(c.defLevels)))
+	t.i32Field(2, encodingPlain)
+	t.i32Field(3, encodingRLE)
+	t.i32Field(4, encodingRLE)
+	t.structEnd()
+	t.structEnd()
+
+	chunk := columnChunk{
+		codec:            codec,
+		offset:           w.n,
+		numValues:        int64(len(c.defLevels)),
+		uncompressedSize: int64(len(t.buf) + len(page)),
+		compressedSize:   int64(len(t.buf) + len(compressed)),
+	}
+	if _, err := w.Write(t.buf); err != nil {
+		return columnChunk{}, err
+	}
+	if _, err := w.Write(compressed); err != nil {
+		return columnChunk{}, err
+	}
+	return chunk, nil
+}
+
+// appendLevels appends the given levels using the RLE/bit-packing hybrid
+// encoding, prefixed by their length. Only RLE runs are used, which is
+// compact for the long runs of identical levels typical of most columns.
+func appendLevels(buf []byte, levels []uint8) []byte {
+	lenOffset := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	var scratch [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i + 1
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		n := binary.PutUvarint(scratch[:], uint64(j-i)<<1)
+		buf = append(buf, scratch[:n]...)
+		// All levels are at most 3, so they fit in a single byte.
+		buf = append(buf, levels[i])
+		i = j
+	}
+	binary.LittleEndian.PutUint32(buf[lenOffset:], uint32(len(buf)-lenOffset-4))
+	return buf
+}
+
+// appendBitPacked appends the PLAIN encoding of booleans, which packs them
+// into bits starting with the least significant bit of each byte.
+func appendBitPacked(buf []byte, bools []bool) []byte {
+	for i := 0; i < len(bools); i += 8 {
+		var b byte
+		for j := 0; j < 8 && i+j < len(bools); j++ {
+			if bools[i+j] {
+				b |= 1 << uint(j)
+			}
+		}
+		buf = append(buf, b)
+	}
+	return buf
+}
+
+func compress(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecSnappy:
+		return snappy.Encode(nil, data), nil
+	case CodecGzip:
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		if _, err := gw.Write(data); err != nil {
+			return nil, err
+		}
+		if err := gw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return data, nil
+	}
+}
+
+// countingWriter keeps track of the offset of the data written to a file.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,425 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift structs encoded with the compact protocol into
+// maps from field ID to value. It is the inverse of thriftWriter.
+type thriftReader struct {
+	t   *testing.T
+	buf []byte
+}
+
+type thriftStructValue map[int16]interface{}
+
+func (r *thriftReader) byte() byte {
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.buf)
+	require.True(r.t, n > 0)
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *thriftReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftTrue:
+		return true
+	case thriftFalse:
+		return false
+	case thriftByte:
+		return int64(int8(r.byte()))
+	case thriftI32, thriftI64:
+		return r.zigzag()
+	case thriftBinary:
+		n := r.uvarint()
+		v := string(r.buf[:n])
+		r.buf = r.buf[n:]
+		return v
+	case thriftList:
+		h := r.byte()
+		n, elemType := int(h>>4), h&0x0f
+		if n == thriftShortList {
+			n = int(r.uvarint())
+		}
+		elems := make([]interface{}, n)
+		for i := range elems {
+			if elemType == thriftTrue {
+				elems[i] = r.byte() == thriftTrue
+			} else {
+				elems[i] = r.value(elemType)
+			}
+		}
+		return elems
+	case thriftStruct:
+		return r.readStruct()
+	default:
+		r.t.Fatalf("unexpected thrift type %d", typ)
+		return nil
+	}
+}
+
+func (r *thriftReader) readStruct() thriftStructValue {
+	s := thriftStructValue{}
+	var lastField int16
+	for {
+		h := r.byte()
+		if h == thriftStop {
+			return s
+		}
+		id := lastField + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		s[id] = r.value(h & 0x0f)
+		lastField = id
+	}
+}
+
+// readLevels decodes levels encoded with the RLE/bit-packing hybrid encoding
+// and prefixed by their length, returning the remaining data.
+func readLevels(t *testing.T, data []byte, n int) ([]int, []byte) {
+	length := binary.LittleEndian.Uint32(data)
+	data, rest := data[4:4+length], data[4+length:]
+	var levels []int
+	for len(levels) < n {
+		h, k := binary.Uvarint(data)
+		data = data[k:]
+		if h&1 == 0 {
+			for i := uint64(0); i < h>>1; i++ {
+				levels = append(levels, int(data[0]))
+			}
+			data = data[1:]
+		} else {
+			// Bit-packed runs of 8 values with a bit width of at most 2.
+			for i := uint64(0); i < h>>1; i++ {
+				v := binary.LittleEndian.Uint16(data)
+				for j := 0; j < 8; j++ {
+					levels = append(levels, int(v>>(2*j))&3)
+				}
+				data = data[2:]
+			}
+		}
+	}
+	require.Empty(t, data)
+	return levels[:n], rest
+}
+
+// readFile reads a file written by a Writer, returning its schema elements and
+// its rows in the format accepted by AddRow.
+func readFile(t *testing.T, file []byte) ([]thriftStructValue, [][]interface{}) {
+	require.Equal(t, magic, string(file[:4]))
+	require.Equal(t, magic, string(file[len(file)-4:]))
+	footerLen := binary.LittleEndian.Uint32(file[len(file)-8:])
+	footer := file[len(file)-8-int(footerLen) : len(file)-8]
+	r := &thriftReader{t: t, buf: footer}
+	meta := r.readStruct()
+	require.Empty(t, r.buf)
+
+	var schema, leaves []thriftStructValue
+	for _, e := range meta[2].([]interface{}) {
+		schema = append(schema, e.(thriftStructValue))
+		if _, isGroup := e.(thriftStructValue)[5]; !isGroup {
+			leaves = append(leaves, e.(thriftStructValue))
+		}
+	}
+	numRows := int(meta[3].(int64))
+	rows := make([][]interface{}, numRows)
+	for i := range rows {
+		rows[i] = make([]interface{}, schema[0][5].(int64))
+	}
+	rowGroups := meta[4].([]interface{})
+	if numRows == 0 {
+		require.Empty(t, rowGroups)
+		return schema, rows
+	}
+	require.Len(t, rowGroups, 1)
+	rowGroup := rowGroups[0].(thriftStructValue)
+	require.Equal(t, int64(numRows), rowGroup[3])
+
+	for c, chunk := range rowGroup[1].([]interface{}) {
+		md := chunk.(thriftStructValue)[3].(thriftStructValue)
+		isList := len(md[3].([]interface{})) == 3
+		offset := md[9].(int64)
+		pr := &thriftReader{t: t, buf: file[offset:]}
+		header := pr.readStruct()
+		page := pr.buf[:header[3].(int64)]
+		require.Equal(t, md[7], int64(len(file[offset:])-len(pr.buf))+header[3].(int64))
+
+		switch md[4].(int64) {
+		case int64(CodecSnappy):
+			var err error
+			page, err = snappy.Decode(nil, page)
+			require.NoError(t, err)
+		case int64(CodecGzip):
+			gr, err := gzip.NewReader(bytes.NewReader(page))
+			require.NoError(t, err)
+			page, err = ioutil.ReadAll(gr)
+			require.NoError(t, err)
+		}
+		require.Equal(t, header[2], int64(len(page)))
+
+		numValues := int(header[5].(thriftStructValue)[1].(int64))
+		require.Equal(t, md[5], int64(numValues))
+		repLevels := make([]int, numValues)
+		if isList {
+			repLevels, page = readLevels(t, page, numValues)
+		}
+		defLevels, page := readLevels(t, page, numValues)
+		maxDef := 1
+		if isList {
+			maxDef = 3
+		}
+
+		typ := PhysicalType(md[1].(int64))
+		var bit int
+		readValue := func() interface{} {
+			switch typ {
+			case TypeBoolean:
+				v := page[0]&(1<<uint(bit%8)) != 0
+				if bit++; bit%8 == 0 {
+					page = page[1:]
+				}
+				return v
+			case TypeInt32:
+				v := int32(binary.LittleEndian.Uint32(page))
+				page = page[4:]
+				return v
+			case TypeInt64:
+				v := int64(binary.LittleEndian.Uint64(page))
+				page = page[8:]
+				return v
+			case TypeFloat:
+				v := math.Float32frombits(binary.LittleEndian.Uint32(page))
+				page = page[4:]
+				return v
+			case TypeDouble:
+				v := math.Float64frombits(binary.LittleEndian.Uint64(page))
+				page = page[8:]
+				return v
+			case TypeByteArray:
+				n := binary.LittleEndian.Uint32(page)
+				v := page[4 : 4+n]
+				page = page[4+n:]
+				return v
+			default:
+				n := leaves[c][2].(int64)
+				v := page[:n]
+				page = page[n:]
+				return v
+			}
+		}
+
+		row := -1
+		for i := 0; i < numValues; i++ {
+			if repLevels[i] == 0 {
+				row++
+			}
+			switch def := defLevels[i]; {
+			case def == 0:
+				rows[row][c] = nil
+			case !isList:
+				rows[row][c] = readValue()
+			case def == 1:
+				rows[row][c] = []interface{}{}
+			default:
+				if repLevels[i] == 0 {
+					rows[row][c] = []interface{}{}
+				}
+				var v interface{}
+				if def == maxDef {
+					v = readValue()
+				}
+				rows[row][c] = append(rows[row][c].([]interface{}), v)
+			}
+		}
+		require.Equal(t, numRows-1, row)
+		if typ == TypeBoolean && bit%8 != 0 {
+			page = page[1:]
+		}
+		require.Empty(t, page)
+	}
+	return schema, rows
+}
+
+func TestWriter(t *testing.T) {
+	columns := []Column{
+		{Name: "b", Type: TypeBoolean},
+		{Name: "i2", Type: TypeInt32, Logical: LogicalInt16},
+		{Name: "i8", Type: TypeInt64, Logical: LogicalInt64},
+		{Name: "f4", Type: TypeFloat},
+		{Name: "f8", Type: TypeDouble},
+		{Name: "s", Type: TypeByteArray, Logical: LogicalString},
+		{Name: "d", Type: TypeByteArray, Logical: LogicalDecimal, Precision: 10, Scale: 2},
+		{Name: "u", Type: TypeFixedLenByteArray, TypeLength: 16, Logical: LogicalUUID},
+		{Name: "ts", Type: TypeInt64, Logical: LogicalTimestampMicrosUTC},
+		{Name: "a", Type: TypeInt64, Logical: LogicalInt64, List: true},
+		{Name: "ab", Type: TypeBoolean, List: true},
+	}
+	uuid := []byte("0123456789abcdef")
+	rows := [][]interface{}{
+		{
+			true, int32(1), int64(1), float32(1.5), 1.25, []byte("foo"), []byte{0x04, 0xd2},
+			uuid, int64(1614556800000000), []interface{}{int64(1), int64(2)}, []interface{}{true, nil, false},
+		},
+		{nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil},
+		{
+			false, int32(-32768), int64(math.MinInt64), float32(math.Inf(1)), -0.5, []byte(""), []byte{0xff},
+			uuid, int64(-1), []interface{}{}, []interface{}{},
+		},
+		{
+			true, int32(32767), int64(math.MaxInt64), float32(0), math.MaxFloat64, []byte("bar"), []byte{0},
+			uuid, int64(0), []interface{}{nil, int64(3), nil}, []interface{}{nil},
+		},
+	}
+	for i := 0; i < 10; i++ {
+		rows = append(rows, []interface{}{
+			i%2 == 0, int32(i), int64(i), nil, float64(i), []byte("x"), nil,
+			nil, int64(i), []interface{}{int64(i)}, nil,
+		})
+	}
+
+	for _, codec := range []Codec{CodecUncompressed, CodecSnappy, CodecGzip} {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, columns, codec)
+		require.NoError(t, err)
+		for _, row := range rows {
+			require.NoError(t, w.AddRow(row))
+		}
+		require.Equal(t, int64(len(rows)), w.NumRows())
+		require.NoError(t, w.Close())
+
+		schema, readRows := readFile(t, buf.Bytes())
+		require.Equal(t, rows, readRows)
+
+		// The root, one element per scalar column and three per list column.
+		require.Len(t, schema, 1+len(columns)+2*2)
+		require.Equal(t, "schema", schema[0][4])
+		require.Equal(t, int64(len(columns)), schema[0][5])
+
+		decimal := schema[7]
+		require.Equal(t, "d", decimal[4])
+		require.Equal(t, int64(convertedDecimal), decimal[6])
+		require.Equal(t, int64(2), decimal[7])
+		require.Equal(t, int64(10), decimal[8])
+		require.Equal(t, thriftStructValue{5: thriftStructValue{1: int64(2), 2: int64(10)}}, decimal[10])
+
+		ts := schema[9]
+		require.Equal(t, thriftStructValue{
+			8: thriftStructValue{1: true, 2: thriftStructValue{2: thriftStructValue{}}},
+		}, ts[10])
+
+		list, repeated, element := schema[10], schema[11], schema[12]
+		require.Equal(t, "a", list[4])
+		require.Equal(t, int64(convertedList), list[6])
+		require.Equal(t, int64(repetitionRepeated), repeated[3])
+		require.Equal(t, "element", element[4])
+		require.Equal(t, int64(TypeInt64), element[1])
+	}
+}
+
+// TestWriterTimeTypes verifies that only time types adjusted to UTC carry a
+// ConvertedType, which would otherwise contradict their LogicalType.
+func TestWriterTimeTypes(t *testing.T) {
+	columns := []Column{
+		{Name: "t", Type: TypeInt64, Logical: LogicalTimeMicros},
+		{Name: "ts", Type: TypeInt64, Logical: LogicalTimestampMicros},
+		{Name: "tsutc", Type: TypeInt64, Logical: LogicalTimestampMicrosUTC},
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, columns, CodecUncompressed)
+	require.NoError(t, err)
+	require.NoError(t, w.AddRow([]interface{}{int64(1), int64(2), int64(3)}))
+	require.NoError(t, w.Close())
+	schema, _ := readFile(t, buf.Bytes())
+	require.Len(t, schema, 1+len(columns))
+
+	micros := thriftStructValue{2: thriftStructValue{}}
+	for i, tc := range []struct {
+		converted interface{}
+		logical   thriftStructValue
+	}{
+		{nil, thriftStructValue{7: thriftStructValue{1: false, 2: micros}}},
+		{nil, thriftStructValue{8: thriftStructValue{1: false, 2: micros}}},
+		{int64(convertedTimestampMicros), thriftStructValue{8: thriftStructValue{1: true, 2: micros}}},
+	} {
+		col := schema[i+1]
+		require.Equal(t, columns[i].Name, col[4])
+		require.Equal(t, tc.converted, col[6], columns[i].Name)
+		require.Equal(t, tc.logical, col[10], columns[i].Name)
+	}
+}
+
+func TestWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{{Name: "a", Type: TypeInt32}}, CodecSnappy)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	schema, rows := readFile(t, buf.Bytes())
+	require.Len(t, schema, 2)
+	require.Empty(t, rows)
+}
+
+func TestWriterErrors(t *testing.T) {
+	_, err := NewWriter(ioutil.Discard, []Column{{Name: "a", Type: TypeFixedLenByteArray}}, CodecUncompressed)
+	require.EqualError(t, err, `column "a" has invalid length 0`)
+
+	_, err = NewWriter(ioutil.Discard, []Column{
+		{Name: "a", Type: TypeByteArray, Logical: LogicalDecimal, Precision: 2, Scale: 3},
+	}, CodecUncompressed)
+	require.EqualError(t, err, `column "a" has invalid precision 2 and scale 3`)
+
+	_, err = NewWriter(ioutil.Discard, []Column{{Name: "a", Type: TypeInt32}}, Codec(5))
+	require.EqualError(t, err, `unsupported compression codec 5`)
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, []Column{
+		{Name: "a", Type: TypeInt32},
+		{Name: "b", Type: TypeInt64, List: true},
+		{Name: "c", Type: TypeFixedLenByteArray, TypeLength: 2},
+	}, CodecUncompressed)
+	require.NoError(t, err)
+	require.NoError(t, w.AddRow([]interface{}{int32(1), []interface{}{int64(1)}, []byte("ab")}))
+	require.EqualError(t, w.AddRow([]interface{}{int32(1)}), `expected 3 values, found 1`)
+	require.EqualError(t, w.AddRow([]interface{}{int64(1), nil, nil}),
+		`column "a": unexpected value of type int64`)
+	require.EqualError(t, w.AddRow([]interface{}{nil, int64(1), nil}),
+		`column "b": expected a list, found int64`)
+	require.EqualError(t, w.AddRow([]interface{}{nil, []interface{}{int32(1)}, nil}),
+		`column "b": unexpected value of type int32`)
+	require.EqualError(t, w.AddRow([]interface{}{int32(2), nil, []byte("abc")}),
+		`column "c": expected a value of 2 bytes, found 3 bytes`)
+
+	// Rows which failed to be added leave no trace in the file.
+	require.NoError(t, w.Close())
+	_, rows := readFile(t, buf.Bytes())
+	require.Equal(t, [][]interface{}{{int32(1), []interface{}{int64(1)}, []byte("ab")}}, rows)
+}
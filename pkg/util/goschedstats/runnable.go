@@ -0,0 +1,26 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package goschedstats exposes statistics about the Go scheduler which are
+// not available through the runtime package, such as the number of
+// goroutines waiting to run.
+package goschedstats
+
+// NumRunnableGoroutines returns the number of goroutines which are ready to
+// run but are waiting for a processor (i.e. are in the scheduler's run
+// queues), and the number of processors (GOMAXPROCS).
+//
+// The number of runnable goroutines is a better signal of CPU overload than
+// the CPU utilization: it keeps growing once the CPU is saturated, and it
+// reacts within milliseconds. The value is computed without synchronizing
+// with the scheduler, so it is approximate.
+func NumRunnableGoroutines() (numRunnable int, numProcs int) {
+	return numRunnableGoroutines()
+}
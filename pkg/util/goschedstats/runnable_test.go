@@ -0,0 +1,50 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package goschedstats
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestNumRunnableGoroutines(t *testing.T) {
+	// Keep the processors busy, so that some goroutines are likely to be
+	// waiting in the run queues.
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4*runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					runtime.Gosched()
+				}
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(stop)
+
+	for i := 0; i < 100; i++ {
+		numRunnable, numProcs := NumRunnableGoroutines()
+		if numProcs != runtime.GOMAXPROCS(0) {
+			t.Fatalf("expected %d procs, got %d", runtime.GOMAXPROCS(0), numProcs)
+		}
+		if numRunnable < 0 {
+			t.Fatalf("unexpected negative number of runnable goroutines: %d", numRunnable)
+		}
+	}
+}
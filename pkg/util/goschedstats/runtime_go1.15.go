@@ -0,0 +1,125 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build gc,go1.15,!go1.17
+
+package goschedstats
+
+import (
+	"sync/atomic"
+	_ "unsafe" // required by go:linkname
+)
+
+// The structs below mirror the prefix of the runtime's internal structs
+// that we need to access, and are thus tied to specific Go releases, which
+// is why this file is protected by a build tag. See runtime/runtime2.go.
+
+type puintptr uintptr
+type muintptr uintptr
+type guintptr uintptr
+
+type sysmontick struct {
+	schedtick   uint32
+	schedwhen   int64
+	syscalltick uint32
+	syscallwhen int64
+}
+
+type pageCache struct {
+	base  uintptr
+	cache uint64
+	scav  uint64
+}
+
+// p mirrors runtime.p, up to the local run queue.
+type p struct {
+	id           int32
+	status       uint32
+	link         puintptr
+	schedtick    uint32
+	syscalltick  uint32
+	sysmontick   sysmontick
+	m            muintptr
+	mcache       uintptr
+	pcache       pageCache
+	raceprocctx  uintptr
+	deferpool    [5][]uintptr
+	deferpoolbuf [5][32]uintptr
+	goidcache    uint64
+	goidcacheend uint64
+	runqhead     uint32
+	runqtail     uint32
+	runq         [256]guintptr
+	runnext      guintptr
+}
+
+type mutex struct {
+	key uintptr
+}
+
+type gQueue struct {
+	head guintptr
+	tail guintptr
+}
+
+// schedt mirrors runtime.schedt, up to the global run queue.
+type schedt struct {
+	goidgen      uint64
+	lastpoll     uint64
+	pollUntil    uint64
+	lock         mutex
+	midle        muintptr
+	nmidle       int32
+	nmidlelocked int32
+	mnext        int64
+	maxmcount    int32
+	nmsys        int32
+	nmfreed      int64
+	ngsys        uint32
+	pidle        puintptr
+	npidle       uint32
+	nmspinning   uint32
+	runq         gQueue
+	runqsize     int32
+}
+
+//go:linkname allp runtime.allp
+var allp []*p
+
+//go:linkname sched runtime.sched
+var sched schedt
+
+func numRunnableGoroutines() (numRunnable int, numProcs int) {
+	// NB: we don't acquire sched.lock, so allp could change underneath us
+	// if GOMAXPROCS is changed concurrently, which is rare. The resulting
+	// count is only used as a signal, so this is acceptable.
+	numRunnable = int(atomic.LoadInt32(&sched.runqsize))
+	numProcs = len(allp)
+	for _, p := range allp {
+		// Retry loop for concurrent updates of the local run queue. This
+		// mirrors runqlen() in the runtime.
+		for {
+			h := atomic.LoadUint32(&p.runqhead)
+			t := atomic.LoadUint32(&p.runqtail)
+			next := atomic.LoadUintptr((*uintptr)(&p.runnext))
+			runnable := int32(t - h)
+			if atomic.LoadUint32(&p.runqhead) != h || runnable < 0 {
+				// A concurrent update interfered; try again.
+				continue
+			}
+			if next != 0 {
+				runnable++
+			}
+			numRunnable += int(runnable)
+			break
+		}
+	}
+	return numRunnable, numProcs
+}
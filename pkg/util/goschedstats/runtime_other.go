@@ -0,0 +1,23 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// +build !gc !go1.15 go1.17
+
+package goschedstats
+
+import "runtime"
+
+// numRunnableGoroutines is not supported for this Go release: the runtime
+// internals are only mirrored for specific releases (see
+// runtime_go1.15.go). No goroutine is ever reported as runnable, which
+// means that the CPU is never considered to be overloaded.
+func numRunnableGoroutines() (numRunnable int, numProcs int) {
+	return 0, runtime.GOMAXPROCS(0)
+}
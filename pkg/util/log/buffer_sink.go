@@ -0,0 +1,217 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+// bufferSink wraps a network sink so that log entries are sent
+// asynchronously: output() only queues the formatted entries in
+// memory, and a background goroutine (flushDaemon) writes them to the
+// child sink. This ensures that logging does not block when the
+// network collector is slow or unavailable.
+//
+// While the child sink is unavailable, entries accumulate in the
+// buffer, up to maxSize bytes. Beyond that, the oldest entries are
+// dropped.
+type bufferSink struct {
+	child   logSink
+	maxSize int
+
+	// retryInterval is the delay before a new attempt to write to the
+	// child sink after an error.
+	retryInterval time.Duration
+
+	// flushC is signaled when new entries are queued.
+	flushC chan struct{}
+
+	// writeMu serializes the writes to the child sink.
+	writeMu syncutil.Mutex
+
+	mu struct {
+		syncutil.Mutex
+		// entries is the queue of formatted entries not yet written to
+		// the child sink.
+		entries [][]byte
+		// size is the total size of the queued entries, in bytes.
+		size int
+		// dropped is the number of entries dropped since the last
+		// report, because the buffer was full.
+		dropped int
+		// popped counts the entries removed from the front of the
+		// queue, either because they were written or dropped.
+		popped uint64
+	}
+}
+
+// errEntryRejected marks the errors of child sinks which indicate that
+// the network collector will never accept an entry. Such entries are
+// dropped instead of being retried, which would block the entries
+// queued after them.
+var errEntryRejected = errors.New("log entry rejected")
+
+// bufferRetryInterval is the default retryInterval of buffer sinks.
+// Overridden in tests.
+var bufferRetryInterval = time.Second
+
+func newBufferSink(child logSink, maxSize int) *bufferSink {
+	return &bufferSink{
+		child:         child,
+		maxSize:       maxSize,
+		retryInterval: bufferRetryInterval,
+		flushC:        make(chan struct{}, 1),
+	}
+}
+
+// active implements the logSink interface.
+func (l *bufferSink) active() bool { return l.child.active() }
+
+// attachHints implements the logSink interface.
+func (l *bufferSink) attachHints(stacks []byte) []byte {
+	return l.child.attachHints(stacks)
+}
+
+// exitCode implements the logSink interface.
+func (l *bufferSink) exitCode() exit.Code {
+	return l.child.exitCode()
+}
+
+// output implements the logSink interface.
+//
+// Errors are not reported to the caller, since the entries are written
+// asynchronously. Entries for which extraSync is requested (e.g. fatal
+// errors) are written synchronously, together with all the entries
+// queued before them, since the process may be about to terminate.
+func (l *bufferSink) output(extraSync bool, b []byte) error {
+	l.enqueue(b)
+	if extraSync {
+		_ = l.flush()
+		return nil
+	}
+	select {
+	case l.flushC <- struct{}{}:
+	default:
+		// A flush is already pending.
+	}
+	return nil
+}
+
+// emergencyOutput implements the logSink interface.
+func (l *bufferSink) emergencyOutput(b []byte) {
+	l.enqueue(b)
+	_ = l.flush()
+}
+
+// enqueue appends a copy of the entry to the queue, dropping the
+// oldest entries if the buffer becomes full.
+func (l *bufferSink) enqueue(b []byte) {
+	// The caller reuses the buffer after we return.
+	entry := append([]byte(nil), b...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.entries = append(l.mu.entries, entry)
+	l.mu.size += len(entry)
+	for l.mu.size > l.maxSize && len(l.mu.entries) > 0 {
+		l.popLocked()
+		l.mu.dropped++
+	}
+}
+
+func (l *bufferSink) popLocked() {
+	l.mu.size -= len(l.mu.entries[0])
+	l.mu.entries[0] = nil
+	l.mu.entries = l.mu.entries[1:]
+	l.mu.popped++
+}
+
+// flush writes the queued entries to the child sink, until the queue
+// is empty or an error is encountered. In case of error, the entries
+// that could not be written remain queued, except for an entry that
+// the collector rejected, which is reported and dropped.
+func (l *bufferSink) flush() error {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+
+	for {
+		l.mu.Lock()
+		if l.mu.dropped > 0 {
+			fmt.Fprintf(OrigStderr, "%s: %d log entries dropped: buffer full\n", l.child, l.mu.dropped)
+			l.mu.dropped = 0
+		}
+		if len(l.mu.entries) == 0 {
+			l.mu.Unlock()
+			return nil
+		}
+		entry := l.mu.entries[0]
+		seq := l.mu.popped
+		l.mu.Unlock()
+
+		if err := l.child.output(false /* extraSync */, entry); err != nil {
+			if !errors.Is(err, errEntryRejected) {
+				return err
+			}
+			fmt.Fprintf(OrigStderr, "%v; log entry dropped\n", err)
+		}
+
+		l.mu.Lock()
+		// The entry may have been dropped by enqueue() in the meantime.
+		if l.mu.popped == seq {
+			l.popLocked()
+		}
+		l.mu.Unlock()
+	}
+}
+
+// close releases the resources held by the child sink, if any.
+func (l *bufferSink) close() {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	if c, ok := l.child.(closableSink); ok {
+		c.close()
+	}
+}
+
+// flushDaemon writes the queued entries to the child sink in the
+// background, until the context is canceled.
+func (l *bufferSink) flushDaemon(ctx context.Context) {
+	// lastErr is used to report a failure once, instead of upon every
+	// retry.
+	var lastErr error
+	var retryC <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.flushC:
+		case <-retryC:
+		}
+
+		retryC = nil
+		if err := l.flush(); err != nil {
+			if lastErr == nil || err.Error() != lastErr.Error() {
+				fmt.Fprintf(OrigStderr, "%v; will retry\n", err)
+			}
+			lastErr = err
+			retryC = time.After(l.retryInterval)
+		} else if lastErr != nil {
+			fmt.Fprintf(OrigStderr, "%s: connection to network logger resumed\n", l.child)
+			lastErr = nil
+		}
+	}
+}
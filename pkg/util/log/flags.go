@@ -131,6 +131,9 @@ func ApplyConfig(config logconfig.Config) (cleanupFn func(), err error) {
 		}
 		for _, l := range sinkInfos {
 			allSinkInfos.del(l)
+			if c, ok := l.sink.(closableSink); ok {
+				c.close()
+			}
 		}
 	}
 
@@ -279,6 +282,48 @@ func ApplyConfig(config logconfig.Config) (cleanupFn func(), err error) {
 		}
 	}
 
+	// Create the fluent sinks.
+	for name, fc := range config.Sinks.FluentServers {
+		if fc.Filter == severity.NONE {
+			continue
+		}
+		fluentSinkInfo, err := newFluentSinkInfo(name, *fc)
+		if err != nil {
+			cleanupFn()
+			return nil, err
+		}
+		attachBufferSink(secLoggersCtx, fluentSinkInfo, fc.NetworkSinkConfig)
+		sinkInfos = append(sinkInfos, fluentSinkInfo)
+		allSinkInfos.put(fluentSinkInfo)
+
+		// Connect the channels for this sink.
+		for _, ch := range fc.Channels.Channels {
+			l := chans[ch]
+			l.sinkInfos = append(l.sinkInfos, fluentSinkInfo)
+		}
+	}
+
+	// Create the HTTP sinks.
+	for name, hc := range config.Sinks.HTTPServers {
+		if hc.Filter == severity.NONE {
+			continue
+		}
+		httpSinkInfo, err := newHTTPSinkInfo(name, *hc)
+		if err != nil {
+			cleanupFn()
+			return nil, err
+		}
+		attachBufferSink(secLoggersCtx, httpSinkInfo, hc.NetworkSinkConfig)
+		sinkInfos = append(sinkInfos, httpSinkInfo)
+		allSinkInfos.put(httpSinkInfo)
+
+		// Connect the channels for this sink.
+		for _, ch := range hc.Channels.Channels {
+			l := chans[ch]
+			l.sinkInfos = append(l.sinkInfos, httpSinkInfo)
+		}
+	}
+
 	logging.setChannelLoggers(chans, &stderrSinkInfo)
 	setActive()
 
@@ -303,6 +348,41 @@ func newFileSinkInfo(fileNamePrefix string, c logconfig.FileConfig) (*sinkInfo,
 	return info, fileSink, nil
 }
 
+// newFluentSinkInfo creates a new fluentSink and its accompanying
+// sinkInfo from the provided configuration.
+func newFluentSinkInfo(name string, c logconfig.FluentConfig) (*sinkInfo, error) {
+	info := &sinkInfo{}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, err
+	}
+	info.sink = newFluentSink(name, c.Net, c.Address)
+	return info, nil
+}
+
+// newHTTPSinkInfo creates a new httpSink and its accompanying
+// sinkInfo from the provided configuration.
+func newHTTPSinkInfo(name string, c logconfig.HTTPConfig) (*sinkInfo, error) {
+	info := &sinkInfo{}
+	if err := info.applyConfig(c.CommonSinkConfig); err != nil {
+		return nil, err
+	}
+	info.sink = newHTTPSink(name, c.Address,
+		*c.Timeout, *c.UnsafeTLS, *c.DisableKeepAlives)
+	return info, nil
+}
+
+// attachBufferSink interposes a bufferSink between the sinkInfo and
+// its network sink if buffering is enabled in the configuration. The
+// buffer is flushed asynchronously until the context is canceled.
+func attachBufferSink(ctx context.Context, info *sinkInfo, c logconfig.NetworkSinkConfig) {
+	if c.MaxBufferSize == nil || *c.MaxBufferSize == 0 {
+		return
+	}
+	bs := newBufferSink(info.sink, int(*c.MaxBufferSize))
+	info.sink = bs
+	go bs.flushDaemon(ctx)
+}
+
 // applyConfig applies a common sink configuration to a sinkInfo.
 func (l *sinkInfo) applyConfig(c logconfig.CommonSinkConfig) error {
 	l.threshold = c.Filter
@@ -426,6 +506,49 @@ func DescribeAppliedConfig() string {
 		return nil
 	})
 
+	// Describe the network sinks.
+	config.Sinks.FluentServers = make(map[string]*logconfig.FluentConfig)
+	config.Sinks.HTTPServers = make(map[string]*logconfig.HTTPConfig)
+	_ = allSinkInfos.iter(func(l *sinkInfo) error {
+		sink := l.sink
+		// Unwrap the buffering, if any.
+		var maxBufferSize logconfig.ByteSize
+		if bs, ok := sink.(*bufferSink); ok {
+			sink = bs.child
+			maxBufferSize = logconfig.ByteSize(bs.maxSize)
+		}
+		var netConfig logconfig.NetworkSinkConfig
+		netConfig.CommonSinkConfig = l.describeAppliedConfig()
+		netConfig.MaxBufferSize = &maxBufferSize
+
+		switch s := sink.(type) {
+		case *fluentSink:
+			fc := &logconfig.FluentConfig{
+				Net:               s.network,
+				Address:           s.addr,
+				NetworkSinkConfig: netConfig,
+			}
+			// Describe the connections to this fluent sink.
+			for ch, logger := range chans {
+				describeConnections(logger, ch, l, &fc.Channels)
+			}
+			config.Sinks.FluentServers[s.name] = fc
+
+		case *httpSink:
+			hc := &logconfig.HTTPConfig{Address: s.address}
+			hc.Timeout = &s.timeout
+			hc.UnsafeTLS = &s.unsafeTLS
+			hc.DisableKeepAlives = &s.disableKeepAlives
+			hc.NetworkSinkConfig = netConfig
+			// Describe the connections to this HTTP sink.
+			for ch, logger := range chans {
+				describeConnections(logger, ch, l, &hc.Channels)
+			}
+			config.Sinks.HTTPServers[s.name] = hc
+		}
+		return nil
+	})
+
 	// Note: we cannot return 'config' directly, because this captures
 	// certain variables from the loggers by reference and thus could be
 	// invalidated by concurrent uses of ApplyConfig().
@@ -0,0 +1,141 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// fluentSink represents a Fluentd-compatible network collector. The
+// formatted log entries are written as-is to a TCP, UDP or unix
+// socket connection.
+type fluentSink struct {
+	// The name of the sink in the configuration.
+	name string
+
+	// The network address of the fluentd collector.
+	network string
+	addr    string
+
+	mu struct {
+		syncutil.Mutex
+
+		// good indicates that the connection can be used.
+		good bool
+		conn net.Conn
+	}
+}
+
+// fluentTimeout bounds the duration of connection attempts and writes
+// to fluent collectors.
+const fluentTimeout = 2 * time.Second
+
+func newFluentSink(name, network, addr string) *fluentSink {
+	return &fluentSink{
+		name:    name,
+		network: network,
+		addr:    addr,
+	}
+}
+
+func (l *fluentSink) String() string {
+	return fmt.Sprintf("fluent:%s://%s", l.network, l.addr)
+}
+
+// active implements the logSink interface.
+func (l *fluentSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *fluentSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *fluentSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *fluentSink) output(_ bool, b []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Try to write and reconnect immediately if the first write fails.
+	if err := l.tryWriteLocked(b); err == nil {
+		return nil
+	}
+	if err := l.ensureConnLocked(); err != nil {
+		return err
+	}
+	return l.tryWriteLocked(b)
+}
+
+// emergencyOutput implements the logSink interface.
+func (l *fluentSink) emergencyOutput(b []byte) {
+	_ = l.output(false /* extraSync */, b)
+}
+
+// close closes the connection to the collector, if any. The
+// connection is re-established by the next write.
+func (l *fluentSink) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closeLocked()
+}
+
+func (l *fluentSink) closeLocked() {
+	l.mu.good = false
+	if l.mu.conn != nil {
+		if err := l.mu.conn.Close(); err != nil {
+			fmt.Fprintf(OrigStderr, "%s: error closing network logger: %v\n", l, err)
+		}
+		l.mu.conn = nil
+	}
+}
+
+func (l *fluentSink) ensureConnLocked() error {
+	if l.mu.good {
+		return nil
+	}
+	l.closeLocked()
+	conn, err := net.DialTimeout(l.network, l.addr, fluentTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "%s: error dialing network logger", l)
+	}
+	l.mu.conn = conn
+	l.mu.good = true
+	return nil
+}
+
+var errNoConn = errors.New("no connection opened")
+
+func (l *fluentSink) tryWriteLocked(b []byte) error {
+	if !l.mu.good {
+		return errNoConn
+	}
+	if err := l.mu.conn.SetWriteDeadline(timeutil.Now().Add(fluentTimeout)); err != nil {
+		l.closeLocked()
+		return err
+	}
+	n, err := l.mu.conn.Write(b)
+	if err != nil {
+		l.closeLocked()
+		return errors.Wrapf(err, "%s: logging error (short write %d/%d)", l, n, len(b))
+	}
+	return nil
+}
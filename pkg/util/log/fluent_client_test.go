@@ -0,0 +1,140 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFluentClient(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	testCases := []struct {
+		name      string
+		bufConfig string
+	}{
+		{"unbuffered", "max-buffer-size: 0"},
+		{"buffered", "max-buffer-size: 1KiB"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			serverAddr, lines, cleanup := serveFluentTCP(t)
+			defer cleanup()
+
+			h := logconfig.Holder{Config: logconfig.DefaultConfig()}
+			require.NoError(t, h.Set(fmt.Sprintf(
+				"sinks: {fluent-servers: {ops: {channels: OPS, address: %s, %s}}}",
+				serverAddr, tc.bufConfig)))
+			require.NoError(t, h.Config.Validate(&sc.logDir))
+
+			TestingResetActive()
+			cleanupFn, err := ApplyConfig(h.Config)
+			require.NoError(t, err)
+			defer cleanupFn()
+
+			Ops.Infof(context.Background(), "hello world")
+			// Entries on other channels are not sent to the collector.
+			Dev.Infof(context.Background(), "not for fluent")
+			Ops.Infof(context.Background(), "hello again")
+
+			for _, expected := range []string{"hello world", "hello again"} {
+				select {
+				case line := <-lines:
+					require.Contains(t, line, expected)
+				case <-time.After(10 * time.Second):
+					t.Fatalf("timed out waiting for %q", expected)
+				}
+			}
+		})
+	}
+}
+
+func TestFluentClientReconnect(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	serverAddr, lines, cleanup := serveFluentTCP(t)
+	defer cleanup()
+
+	s := newFluentSink("test", "tcp", serverAddr)
+	defer s.close()
+
+	require.NoError(t, s.output(false, []byte("first\n")))
+	require.Equal(t, "first\n", <-lines)
+
+	// Break the connection. The next write reconnects.
+	s.mu.Lock()
+	require.NoError(t, s.mu.conn.Close())
+	s.mu.Unlock()
+
+	require.NoError(t, s.output(false, []byte("second\n")))
+	require.Equal(t, "second\n", <-lines)
+}
+
+// serveFluentTCP starts a TCP server which reports the lines it
+// receives on the returned channel.
+func serveFluentTCP(t *testing.T) (addr string, lines <-chan string, cleanup func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	lc := make(chan string, 10)
+	var wg sync.WaitGroup
+	var mu syncutil.Mutex
+	var conns []net.Conn
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					lc <- line
+				}
+			}()
+		}
+	}()
+
+	cleanup = func() {
+		_ = l.Close()
+		mu.Lock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}
+	return l.Addr().String(), lc, cleanup
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/ttycolor"
 )
 
@@ -242,10 +243,60 @@ type EntryDecoder struct {
 	scanner            *bufio.Scanner
 	sensitiveEditor    redactEditor
 	truncatedLastEntry bool
+
+	// jsonDecoder, if set, is used to decode entries emitted by the
+	// JSON formats instead of the crdb-v1 formats.
+	jsonDecoder *entryDecoderJSON
 }
 
 // NewEntryDecoder creates a new instance of EntryDecoder.
+//
+// The log format is detected from the first byte of the input. Use
+// NewEntryDecoderWithFormat when the input may not start at the
+// beginning of a log entry.
 func NewEntryDecoder(in io.Reader, editMode EditSensitiveData) *EntryDecoder {
+	r := bufio.NewReader(in)
+	if b, err := r.Peek(1); err == nil && b[0] == '{' {
+		return &EntryDecoder{jsonDecoder: newEntryDecoderJSON(r, editMode)}
+	}
+	return newEntryDecoderV1(r, editMode)
+}
+
+// NewEntryDecoderWithFormat creates a new instance of EntryDecoder for
+// the given log format. The crdb-v1 and JSON format families are
+// supported. The variants within a family share the same decoder.
+func NewEntryDecoderWithFormat(
+	in io.Reader, editMode EditSensitiveData, format string,
+) (*EntryDecoder, error) {
+	switch {
+	case strings.HasPrefix(format, "crdb-v1"):
+		return newEntryDecoderV1(in, editMode), nil
+	case strings.HasPrefix(format, "json"):
+		return &EntryDecoder{jsonDecoder: newEntryDecoderJSON(in, editMode)}, nil
+	default:
+		return nil, errors.Newf("unsupported log format: %q", format)
+	}
+}
+
+// DetectEntryFormat returns the family of the log format used for the
+// entries in the input, either "crdb-v1" or "json". The input must
+// start at the beginning of a log entry.
+func DetectEntryFormat(in io.Reader) (string, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(in, b[:]); err != nil {
+		if err == io.EOF {
+			// An empty input is decoded as crdb-v1.
+			return "crdb-v1", nil
+		}
+		return "", err
+	}
+	if b[0] == '{' {
+		return "json", nil
+	}
+	return "crdb-v1", nil
+}
+
+func newEntryDecoderV1(in io.Reader, editMode EditSensitiveData) *EntryDecoder {
 	d := &EntryDecoder{
 		re:              entryRE,
 		scanner:         bufio.NewScanner(in),
@@ -261,6 +312,9 @@ const MessageTimeFormat = "060102 15:04:05.999999"
 
 // Decode decodes the next log entry into the provided protobuf message.
 func (d *EntryDecoder) Decode(entry *logpb.Entry) error {
+	if d.jsonDecoder != nil {
+		return d.jsonDecoder.decode(entry)
+	}
 	for {
 		if !d.scanner.Scan() {
 			if err := d.scanner.Err(); err != nil {
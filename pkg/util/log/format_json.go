@@ -0,0 +1,469 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/util/jsonbytes"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+// formatJSONFull emits each log entry as a JSON object on a single
+// line, using descriptive field names.
+type formatJSONFull struct{}
+
+func (formatJSONFull) formatterName() string { return "json" }
+
+func (formatJSONFull) formatEntry(entry logEntry) *buffer {
+	return formatJSON(entry, false /* fluent */, &jsonKeysFull)
+}
+
+// formatJSONCompact is like formatJSONFull but uses abbreviated
+// field names.
+type formatJSONCompact struct{}
+
+func (formatJSONCompact) formatterName() string { return "json-compact" }
+
+func (formatJSONCompact) formatEntry(entry logEntry) *buffer {
+	return formatJSON(entry, false /* fluent */, &jsonKeysCompact)
+}
+
+// formatFluentJSONFull is like formatJSONFull and also includes a
+// "tag" field suitable for routing by Fluentd-compatible collectors.
+type formatFluentJSONFull struct{}
+
+func (formatFluentJSONFull) formatterName() string { return "json-fluent" }
+
+func (formatFluentJSONFull) formatEntry(entry logEntry) *buffer {
+	return formatJSON(entry, true /* fluent */, &jsonKeysFull)
+}
+
+// formatFluentJSONCompact is like formatJSONCompact and also
+// includes a "tag" field suitable for routing by Fluentd-compatible
+// collectors.
+type formatFluentJSONCompact struct{}
+
+func (formatFluentJSONCompact) formatterName() string { return "json-fluent-compact" }
+
+func (formatFluentJSONCompact) formatEntry(entry logEntry) *buffer {
+	return formatJSON(entry, true /* fluent */, &jsonKeysCompact)
+}
+
+// jsonKeys defines the names of the fields of a JSON log entry. An
+// empty name indicates the field is omitted.
+type jsonKeys struct {
+	channelNumeric  string
+	channel         string
+	timestamp       string
+	severityNumeric string
+	severity        string
+	goroutine       string
+	file            string
+	line            string
+	counter         string
+	redactable      string
+	tags            string
+	message         string
+	event           string
+	stacks          string
+}
+
+var jsonKeysFull = jsonKeys{
+	channelNumeric:  "channel_numeric",
+	channel:         "channel",
+	timestamp:       "timestamp",
+	severityNumeric: "severity_numeric",
+	severity:        "severity",
+	goroutine:       "goroutine",
+	file:            "file",
+	line:            "line",
+	counter:         "entry_counter",
+	redactable:      "redactable",
+	tags:            "tags",
+	message:         "message",
+	event:           "event",
+	stacks:          "stacks",
+}
+
+// jsonKeysCompact omits the channel and severity names, which can be
+// derived from their numeric counterparts.
+var jsonKeysCompact = jsonKeys{
+	channelNumeric:  "c",
+	timestamp:       "t",
+	severityNumeric: "s",
+	goroutine:       "g",
+	file:            "f",
+	line:            "l",
+	counter:         "n",
+	redactable:      "r",
+	tags:            "tags",
+	message:         "message",
+	event:           "event",
+	stacks:          "stacks",
+}
+
+// fluentTagPrefix is the application part of the "tag" field in the
+// fluent variants of the JSON formats. The category part is the
+// lowercase name of the channel.
+const fluentTagPrefix = "cockroach."
+
+// formatJSON renders a log entry as a JSON object, followed by a
+// newline. It uses a newly allocated *buffer. The caller is
+// responsible for calling putBuffer() afterwards.
+//
+// The payload of structured entries is emitted as a JSON object in
+// the "event" field; the message of unstructured entries as a JSON
+// string in the "message" field. When the entry is redactable, the
+// message and the tag values contain redaction markers.
+func formatJSON(entry logEntry, forFluent bool, keys *jsonKeys) *buffer {
+	buf := getBuffer()
+	buf.WriteByte('{')
+	if forFluent {
+		buf.WriteString(`"tag":"`)
+		buf.WriteString(fluentTagPrefix)
+		escapeString(buf, strings.ToLower(entry.ch.String()))
+		buf.WriteString(`",`)
+	}
+
+	writeKey := func(key string) {
+		buf.WriteByte('"')
+		buf.WriteString(key)
+		buf.WriteString(`":`)
+	}
+	writeInt := func(key string, v int64) {
+		writeKey(key)
+		n := buf.someDigits(0, int(v))
+		buf.Write(buf.tmp[:n])
+		buf.WriteByte(',')
+	}
+	writeString := func(key string, v string) {
+		writeKey(key)
+		buf.WriteByte('"')
+		escapeString(buf, v)
+		buf.WriteString(`",`)
+	}
+
+	writeInt(keys.channelNumeric, int64(entry.ch))
+	if keys.channel != "" {
+		writeString(keys.channel, entry.ch.String())
+	}
+	// Note: the timestamp is formatted as a string containing the
+	// number of seconds since the Unix epoch, followed by the
+	// nanoseconds. This is the format preferred by Fluentd, and it
+	// does not lose precision when parsed as a floating-point number.
+	writeString(keys.timestamp, fmt.Sprintf("%d.%09d", entry.ts/1e9, entry.ts%1e9))
+	writeInt(keys.severityNumeric, int64(entry.sev))
+	if keys.severity != "" {
+		writeString(keys.severity, entry.sev.String())
+	}
+	writeInt(keys.goroutine, entry.gid)
+	writeString(keys.file, entry.file)
+	writeInt(keys.line, int64(entry.line))
+	writeInt(keys.counter, int64(entry.counter))
+	redactable := 0
+	if entry.payload.redactable {
+		redactable = 1
+	}
+	writeInt(keys.redactable, int64(redactable))
+
+	if entry.tags != nil {
+		writeKey(keys.tags)
+		buf.WriteByte('{')
+		comma := ""
+		for _, t := range entry.tags.Get() {
+			buf.WriteString(comma)
+			buf.WriteByte('"')
+			escapeString(buf, t.Key())
+			buf.WriteString(`":"`)
+			if v := t.Value(); v != nil && v != "" {
+				var r string
+				if entry.payload.redactable {
+					r = string(redact.Sprint(v))
+				} else {
+					r = fmt.Sprint(v)
+				}
+				escapeString(buf, r)
+			}
+			buf.WriteByte('"')
+			comma = ","
+		}
+		buf.WriteString("},")
+	}
+
+	if entry.structured {
+		// The payload already contains the JSON fields of the event,
+		// without the enclosing braces.
+		writeKey(keys.event)
+		buf.WriteByte('{')
+		buf.WriteString(entry.payload.message)
+		buf.WriteByte('}')
+	} else {
+		writeKey(keys.message)
+		buf.WriteByte('"')
+		escapeString(buf, entry.payload.message)
+		buf.WriteByte('"')
+	}
+
+	if entry.stacks != nil {
+		buf.WriteByte(',')
+		writeKey(keys.stacks)
+		buf.WriteByte('"')
+		escapeString(buf, string(entry.stacks))
+		buf.WriteByte('"')
+	}
+
+	buf.WriteString("}\n")
+	return buf
+}
+
+// escapeString appends the JSON encoding of s to buf, without the
+// enclosing quotes.
+func escapeString(buf *buffer, s string) {
+	b := buf.Bytes()
+	b = jsonbytes.EncodeString(b, s)
+	buf.Buffer = *bytes.NewBuffer(b)
+}
+
+// maxJSONEntrySize is the maximum size of a JSON log entry that can
+// be decoded. Larger entries are skipped.
+const maxJSONEntrySize = 16 << 20
+
+// entryDecoderJSON decodes log entries emitted by the JSON formats.
+// It accepts both the full and compact field names.
+type entryDecoderJSON struct {
+	scanner         *bufio.Scanner
+	sensitiveEditor redactEditor
+}
+
+func newEntryDecoderJSON(in io.Reader, editMode EditSensitiveData) *entryDecoderJSON {
+	d := &entryDecoderJSON{
+		scanner:         bufio.NewScanner(in),
+		sensitiveEditor: getEditor(editMode),
+	}
+	d.scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxJSONEntrySize)
+	return d
+}
+
+// decode decodes the next log entry into the provided protobuf
+// message. The entry is converted to the representation used by the
+// crdb-v1 format: for example, the payload of structured entries is
+// reported in the message, prefixed by "Structured entry:".
+//
+// Lines that cannot be decoded as a JSON log entry are skipped. This
+// makes it possible to start decoding from an arbitrary position in
+// the input.
+func (d *entryDecoderJSON) decode(entry *logpb.Entry) error {
+	for {
+		if !d.scanner.Scan() {
+			if err := d.scanner.Err(); err != nil {
+				return err
+			}
+			return io.EOF
+		}
+		ok, err := d.decodeLine(d.scanner.Bytes(), entry)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+}
+
+func (d *entryDecoderJSON) decodeLine(b []byte, entry *logpb.Entry) (ok bool, err error) {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '{' {
+		return false, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		// Not a log entry; for example, the decoder started reading in
+		// the middle of an entry.
+		return false, nil //nolint:returnerrcheck
+	}
+	get := func(full, compact string) json.RawMessage {
+		if v, ok := fields[full]; ok {
+			return v
+		}
+		return fields[compact]
+	}
+	ts := get(jsonKeysFull.timestamp, jsonKeysCompact.timestamp)
+	if ts == nil {
+		return false, nil
+	}
+
+	// Erase all the fields, to be sure.
+	*entry = logpb.Entry{}
+
+	if entry.Time, err = decodeJSONTimestamp(ts); err != nil {
+		return false, err
+	}
+	getInt := func(full, compact string) (int64, error) {
+		v := get(full, compact)
+		if v == nil {
+			return 0, nil
+		}
+		var i int64
+		if err := json.Unmarshal(v, &i); err != nil {
+			return 0, errors.Wrapf(err, "decoding field %q", full)
+		}
+		return i, nil
+	}
+	getString := func(full, compact string) (string, error) {
+		v := get(full, compact)
+		if v == nil {
+			return "", nil
+		}
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return "", errors.Wrapf(err, "decoding field %q", full)
+		}
+		return s, nil
+	}
+
+	var i int64
+	if i, err = getInt(jsonKeysFull.channelNumeric, jsonKeysCompact.channelNumeric); err != nil {
+		return false, err
+	}
+	entry.Channel = Channel(i)
+	if i, err = getInt(jsonKeysFull.severityNumeric, jsonKeysCompact.severityNumeric); err != nil {
+		return false, err
+	}
+	entry.Severity = Severity(i)
+	if entry.Goroutine, err = getInt(jsonKeysFull.goroutine, jsonKeysCompact.goroutine); err != nil {
+		return false, err
+	}
+	if entry.File, err = getString(jsonKeysFull.file, jsonKeysCompact.file); err != nil {
+		return false, err
+	}
+	if entry.Line, err = getInt(jsonKeysFull.line, jsonKeysCompact.line); err != nil {
+		return false, err
+	}
+	if i, err = getInt(jsonKeysFull.counter, jsonKeysCompact.counter); err != nil {
+		return false, err
+	}
+	entry.Counter = uint64(i)
+	if i, err = getInt(jsonKeysFull.redactable, jsonKeysCompact.redactable); err != nil {
+		return false, err
+	}
+	redactable := i != 0
+
+	// Process the context tags.
+	if v := get(jsonKeysFull.tags, jsonKeysCompact.tags); v != nil {
+		tags, err := decodeJSONTags(v)
+		if err != nil {
+			return false, err
+		}
+		r := redactablePackage{
+			msg:        []byte(tags),
+			redactable: redactable,
+		}
+		r = d.sensitiveEditor(r)
+		entry.Tags = string(r.msg)
+	}
+
+	// Process the payload.
+	var msg string
+	if v := get(jsonKeysFull.event, jsonKeysCompact.event); v != nil {
+		var ev bytes.Buffer
+		if err := json.Compact(&ev, v); err != nil {
+			return false, err
+		}
+		msg = "Structured entry: " + ev.String()
+	} else if msg, err = getString(jsonKeysFull.message, jsonKeysCompact.message); err != nil {
+		return false, err
+	}
+	stacks, err := getString(jsonKeysFull.stacks, jsonKeysCompact.stacks)
+	if err != nil {
+		return false, err
+	}
+	if stacks != "" {
+		msg += "\n" + stacks
+	}
+	r := redactablePackage{
+		msg:        trimFinalNewLines([]byte(msg)),
+		redactable: redactable,
+	}
+	r = d.sensitiveEditor(r)
+	entry.Message = string(r.msg)
+	entry.Redactable = r.redactable
+	return true, nil
+}
+
+// decodeJSONTimestamp decodes a timestamp emitted by formatJSON into
+// a number of nanoseconds since the Unix epoch.
+func decodeJSONTimestamp(v json.RawMessage) (int64, error) {
+	var s string
+	if err := json.Unmarshal(v, &s); err != nil {
+		return 0, errors.Wrap(err, "decoding timestamp")
+	}
+	secs, nanos := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		secs, nanos = s[:i], s[i+1:]
+	}
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return 0, errors.Wrap(err, "decoding timestamp")
+	}
+	var nsec int64
+	if nanos != "" {
+		if len(nanos) > 9 {
+			nanos = nanos[:9]
+		}
+		nanos += strings.Repeat("0", 9-len(nanos))
+		if nsec, err = strconv.ParseInt(nanos, 10, 64); err != nil {
+			return 0, errors.Wrap(err, "decoding timestamp")
+		}
+	}
+	return sec*1e9 + nsec, nil
+}
+
+// decodeJSONTags converts the JSON object containing the logging tags
+// into the representation used by the crdb-v1 format, preserving the
+// order of the tags.
+func decodeJSONTags(v json.RawMessage) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(v))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return "", errors.Newf("invalid tags: %s", v)
+	}
+	var buf strings.Builder
+	for dec.More() {
+		kt, err := dec.Token()
+		if err != nil {
+			return "", errors.Wrap(err, "decoding tags")
+		}
+		var val string
+		if err := dec.Decode(&val); err != nil {
+			return "", errors.Wrap(err, "decoding tags")
+		}
+		key, _ := kt.(string)
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(key)
+		if val != "" {
+			if len(key) > 1 {
+				buf.WriteByte('=')
+			}
+			buf.WriteString(val)
+		}
+	}
+	return buf.String(), nil
+}
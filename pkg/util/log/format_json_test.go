@@ -0,0 +1,193 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/channel"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log/severity"
+	"github.com/cockroachdb/logtags"
+	"github.com/stretchr/testify/require"
+)
+
+func testJSONEntries() []logEntry {
+	tags := logtags.SingleTagBuffer("noval", nil).
+		Add("n", 1).
+		Add("client", "127.0.0.1")
+	return []logEntry{
+		{
+			ts:      1614592800123456789,
+			sev:     severity.INFO,
+			ch:      channel.DEV,
+			gid:     11,
+			file:    "util/log/format_json_test.go",
+			line:    123,
+			counter: 1,
+			tags:    tags,
+			payload: makeRedactablePayload("hello ‹world›"),
+		},
+		{
+			ts:         1614592800200000000,
+			sev:        severity.WARNING,
+			ch:         channel.OPS,
+			gid:        12,
+			file:       "util/log/event_log.go",
+			line:       45,
+			counter:    2,
+			structured: true,
+			payload:    makeRedactablePayload(`"Timestamp":123,"EventType":"node_restart","Info":"‹a \"quoted\" string›"`),
+		},
+		{
+			ts:      1614592800300000000,
+			sev:     severity.FATAL,
+			ch:      channel.SESSIONS,
+			gid:     13,
+			file:    "util/log/clog.go",
+			line:    67,
+			counter: 3,
+			stacks:  []byte("goroutine 13 [running]:\nmain.main()"),
+			payload: makeRedactablePayload("some\tmulti-line\nmessage"),
+		},
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	entries := testJSONEntries()
+	testCases := []struct {
+		format   logFormatter
+		expected []string
+	}{
+		{formatJSONFull{}, []string{
+			`{"channel_numeric":0,"channel":"DEV","timestamp":"1614592800.123456789","severity_numeric":1,"severity":"INFO","goroutine":11,"file":"util/log/format_json_test.go","line":123,"entry_counter":1,"redactable":1,"tags":{"noval":"","n":"1","client":"‹127.0.0.1›"},"message":"hello ‹world›"}`,
+			`{"channel_numeric":1,"channel":"OPS","timestamp":"1614592800.200000000","severity_numeric":2,"severity":"WARNING","goroutine":12,"file":"util/log/event_log.go","line":45,"entry_counter":2,"redactable":1,"event":{"Timestamp":123,"EventType":"node_restart","Info":"‹a \"quoted\" string›"}}`,
+			`{"channel_numeric":4,"channel":"SESSIONS","timestamp":"1614592800.300000000","severity_numeric":4,"severity":"FATAL","goroutine":13,"file":"util/log/clog.go","line":67,"entry_counter":3,"redactable":1,"message":"some\tmulti-line\nmessage","stacks":"goroutine 13 [running]:\nmain.main()"}`,
+		}},
+		{formatJSONCompact{}, []string{
+			`{"c":0,"t":"1614592800.123456789","s":1,"g":11,"f":"util/log/format_json_test.go","l":123,"n":1,"r":1,"tags":{"noval":"","n":"1","client":"‹127.0.0.1›"},"message":"hello ‹world›"}`,
+			`{"c":1,"t":"1614592800.200000000","s":2,"g":12,"f":"util/log/event_log.go","l":45,"n":2,"r":1,"event":{"Timestamp":123,"EventType":"node_restart","Info":"‹a \"quoted\" string›"}}`,
+			`{"c":4,"t":"1614592800.300000000","s":4,"g":13,"f":"util/log/clog.go","l":67,"n":3,"r":1,"message":"some\tmulti-line\nmessage","stacks":"goroutine 13 [running]:\nmain.main()"}`,
+		}},
+		{formatFluentJSONCompact{}, []string{
+			`{"tag":"cockroach.dev","c":0,"t":"1614592800.123456789","s":1,"g":11,"f":"util/log/format_json_test.go","l":123,"n":1,"r":1,"tags":{"noval":"","n":"1","client":"‹127.0.0.1›"},"message":"hello ‹world›"}`,
+			`{"tag":"cockroach.ops","c":1,"t":"1614592800.200000000","s":2,"g":12,"f":"util/log/event_log.go","l":45,"n":2,"r":1,"event":{"Timestamp":123,"EventType":"node_restart","Info":"‹a \"quoted\" string›"}}`,
+			`{"tag":"cockroach.sessions","c":4,"t":"1614592800.300000000","s":4,"g":13,"f":"util/log/clog.go","l":67,"n":3,"r":1,"message":"some\tmulti-line\nmessage","stacks":"goroutine 13 [running]:\nmain.main()"}`,
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format.formatterName(), func(t *testing.T) {
+			for i, e := range entries {
+				buf := tc.format.formatEntry(e)
+				actual := buf.String()
+				putBuffer(buf)
+				require.Equal(t, tc.expected[i]+"\n", actual)
+			}
+		})
+	}
+}
+
+func TestFormatJSONIsValid(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, f := range []logFormatter{
+		formatJSONFull{}, formatJSONCompact{}, formatFluentJSONFull{}, formatFluentJSONCompact{},
+	} {
+		for _, e := range testJSONEntries() {
+			// Also check that non-redactable entries are valid.
+			for _, redactable := range []bool{true, false} {
+				e.payload.redactable = redactable
+				buf := f.formatEntry(e)
+				var v map[string]interface{}
+				require.NoError(t, json.Unmarshal(buf.Bytes(), &v), "%s: %s", f.formatterName(), buf)
+				putBuffer(buf)
+			}
+		}
+	}
+}
+
+func TestEntryDecoderJSON(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	entries := testJSONEntries()
+	var expected []logpb.Entry
+	for _, e := range entries {
+		expected = append(expected, e.convertToLegacy())
+	}
+
+	for _, f := range []logFormatter{
+		formatJSONFull{}, formatJSONCompact{}, formatFluentJSONFull{}, formatFluentJSONCompact{},
+	} {
+		t.Run(f.formatterName(), func(t *testing.T) {
+			var contents strings.Builder
+			for _, e := range entries {
+				buf := f.formatEntry(e)
+				contents.Write(buf.Bytes())
+				putBuffer(buf)
+			}
+
+			readAllEntries := func(d *EntryDecoder) (res []logpb.Entry) {
+				for {
+					var e logpb.Entry
+					if err := d.Decode(&e); err != nil {
+						if err == io.EOF {
+							return res
+						}
+						t.Fatal(err)
+					}
+					res = append(res, e)
+				}
+			}
+
+			// The format is detected automatically.
+			d := NewEntryDecoder(strings.NewReader(contents.String()), WithMarkedSensitiveData)
+			require.Equal(t, expected, readAllEntries(d))
+
+			// Incomplete entries are skipped when the input does not start
+			// at the beginning of an entry.
+			s := contents.String()
+			d, err := NewEntryDecoderWithFormat(
+				strings.NewReader(s[strings.Index(s, `{"Timestamp"`):]), WithMarkedSensitiveData, f.formatterName())
+			require.NoError(t, err)
+			require.Equal(t, expected[2:], readAllEntries(d))
+
+			// Redaction is applied to the tags and the message.
+			d = NewEntryDecoder(strings.NewReader(contents.String()), WithoutSensitiveDataNorMarkers)
+			res := readAllEntries(d)
+			require.Equal(t, "noval,n1,client=×", res[0].Tags)
+			require.Equal(t, "hello ×", res[0].Message)
+			require.False(t, res[0].Redactable)
+		})
+	}
+}
+
+func TestDetectEntryFormat(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{``, "crdb-v1"},
+		{`I210301 10:00:00.123456 11 util/log/clog.go:67  hello`, "crdb-v1"},
+		{`{"c":0,"t":"1614592800.123456789"}`, "json"},
+	} {
+		f, err := DetectEntryFormat(strings.NewReader(tc.input))
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, f)
+	}
+}
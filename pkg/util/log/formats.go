@@ -26,5 +26,9 @@ var formatters = func() map[string]logFormatter {
 	r(formatCrdbV1WithCounter{})
 	r(formatCrdbV1TTY{})
 	r(formatCrdbV1TTYWithCounter{})
+	r(formatJSONFull{})
+	r(formatJSONCompact{})
+	r(formatFluentJSONFull{})
+	r(formatFluentJSONCompact{})
 	return m
 }()
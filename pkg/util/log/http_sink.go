@@ -0,0 +1,114 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/cli/exit"
+	"github.com/cockroachdb/errors"
+)
+
+// httpSink sends each formatted log entry as the body of a POST
+// request to an HTTP server. An entry rejected by the server with a
+// client error other than 408 or 429 is not retried by a bufferSink.
+type httpSink struct {
+	// The name of the sink in the configuration.
+	name string
+
+	address           string
+	timeout           time.Duration
+	unsafeTLS         bool
+	disableKeepAlives bool
+
+	client    *http.Client
+	transport *http.Transport
+}
+
+// httpContentType is the content type of the requests sent by HTTP
+// sinks.
+const httpContentType = "application/octet-stream"
+
+func newHTTPSink(
+	name, address string, timeout time.Duration, unsafeTLS, disableKeepAlives bool,
+) *httpSink {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = disableKeepAlives
+	if unsafeTLS {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &httpSink{
+		name:              name,
+		address:           address,
+		timeout:           timeout,
+		unsafeTLS:         unsafeTLS,
+		disableKeepAlives: disableKeepAlives,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+		transport: transport,
+	}
+}
+
+func (l *httpSink) String() string {
+	return fmt.Sprintf("http:%s", l.address)
+}
+
+// active implements the logSink interface.
+func (l *httpSink) active() bool { return true }
+
+// attachHints implements the logSink interface.
+func (l *httpSink) attachHints(stacks []byte) []byte {
+	return stacks
+}
+
+// exitCode implements the logSink interface.
+func (l *httpSink) exitCode() exit.Code {
+	return exit.LoggingNetCollectorUnavailable()
+}
+
+// output implements the logSink interface.
+func (l *httpSink) output(_ bool, b []byte) error {
+	resp, err := l.client.Post(l.address, httpContentType, bytes.NewReader(b))
+	if err != nil {
+		return errors.Wrapf(err, "%s: logging error", l)
+	}
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		err := errors.Newf("%s: logging error: server responded with %s", l, resp.Status)
+		// Client errors are permanent, except for timeouts and rate limiting.
+		if resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout &&
+			resp.StatusCode != http.StatusTooManyRequests {
+			return errors.Mark(err, errEntryRejected)
+		}
+		return err
+	}
+	return nil
+}
+
+// emergencyOutput implements the logSink interface.
+func (l *httpSink) emergencyOutput(b []byte) {
+	_ = l.output(false /* extraSync */, b)
+}
+
+// close releases the idle connections to the server.
+func (l *httpSink) close() {
+	l.transport.CloseIdleConnections()
+}
@@ -0,0 +1,179 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
+	"github.com/stretchr/testify/require"
+)
+
+// testHTTPServer starts an HTTP server which reports the bodies of the
+// requests it receives on the returned channel. The first numFailures
+// requests are rejected with a 503 status.
+func testHTTPServer(t *testing.T, numFailures int32) (*httptest.Server, <-chan string) {
+	bodies := make(chan string, 10)
+	var calls int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != httpContentType {
+			t.Errorf("unexpected content type: %s", ct)
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if atomic.AddInt32(&calls, 1) <= numFailures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		bodies <- string(body)
+	}))
+	return s, bodies
+}
+
+func TestHTTPSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	sc := ScopeWithoutShowLogs(t)
+	defer sc.Close(t)
+
+	defer func(prev time.Duration) { bufferRetryInterval = prev }(bufferRetryInterval)
+	bufferRetryInterval = 10 * time.Millisecond
+
+	testCases := []struct {
+		name        string
+		bufConfig   string
+		numFailures int32
+	}{
+		{"unbuffered", "max-buffer-size: 0", 0},
+		{"buffered", "max-buffer-size: 1KiB", 0},
+		// The entries are retained in the buffer while the server is
+		// unavailable, and delivered in order once it recovers.
+		{"buffered-retry", "max-buffer-size: 1KiB", 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, bodies := testHTTPServer(t, tc.numFailures)
+			defer s.Close()
+
+			h := logconfig.Holder{Config: logconfig.DefaultConfig()}
+			require.NoError(t, h.Set(fmt.Sprintf(
+				"sinks: {http-servers: {ops: {channels: OPS, address: %s, %s}}}",
+				s.URL, tc.bufConfig)))
+			require.NoError(t, h.Config.Validate(&sc.logDir))
+
+			TestingResetActive()
+			cleanupFn, err := ApplyConfig(h.Config)
+			require.NoError(t, err)
+			defer cleanupFn()
+
+			Ops.Infof(context.Background(), "hello world")
+			Ops.Infof(context.Background(), "hello again")
+
+			for _, expected := range []string{"hello world", "hello again"} {
+				select {
+				case body := <-bodies:
+					require.Contains(t, body, expected)
+				case <-time.After(10 * time.Second):
+					t.Fatalf("timed out waiting for %q", expected)
+				}
+			}
+		})
+	}
+}
+
+func TestBufferSinkDropsOldestEntries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	s, bodies := testHTTPServer(t, 0)
+	defer s.Close()
+
+	child := newHTTPSink("test", s.URL, time.Second, false, false)
+	bs := newBufferSink(child, 10)
+	defer bs.close()
+
+	// Without the flush daemon, the entries stay in the buffer.
+	bs.enqueue([]byte("aaaa"))
+	bs.enqueue([]byte("bbbb"))
+	bs.enqueue([]byte("cccc"))
+	bs.mu.Lock()
+	require.Equal(t, 2, len(bs.mu.entries))
+	require.Equal(t, 8, bs.mu.size)
+	require.Equal(t, 1, bs.mu.dropped)
+	bs.mu.Unlock()
+
+	require.NoError(t, bs.flush())
+	require.Equal(t, "bbbb", <-bodies)
+	require.Equal(t, "cccc", <-bodies)
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	require.Equal(t, 0, len(bs.mu.entries))
+	require.Equal(t, 0, bs.mu.size)
+	require.Equal(t, 0, bs.mu.dropped)
+}
+
+// TestBufferSinkDropsRejectedEntries verifies that an entry rejected by the
+// server with a client error is dropped instead of blocking the entries
+// queued after it, while rate limited entries are retried.
+func TestBufferSinkDropsRejectedEntries(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	bodies := make(chan string, 10)
+	var rateLimited int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		switch string(body) {
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		case "limited":
+			if atomic.AddInt32(&rateLimited, 1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		}
+		bodies <- string(body)
+	}))
+	defer s.Close()
+
+	child := newHTTPSink("test", s.URL, time.Second, false, false)
+	bs := newBufferSink(child, 1024)
+	defer bs.close()
+
+	bs.enqueue([]byte("bad"))
+	bs.enqueue([]byte("limited"))
+	bs.enqueue([]byte("good"))
+
+	// The rejected entry is dropped, and the rate limited one is kept.
+	require.Error(t, bs.flush())
+	bs.mu.Lock()
+	require.Equal(t, 2, len(bs.mu.entries))
+	bs.mu.Unlock()
+
+	require.NoError(t, bs.flush())
+	require.Equal(t, "limited", <-bodies)
+	require.Equal(t, "good", <-bodies)
+}
@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/util/log/logpb"
 	"github.com/cockroachdb/errors"
@@ -30,6 +31,23 @@ const DefaultFileFormat = `crdb-v1`
 // when not specified in a configuration.
 const DefaultStderrFormat = `crdb-v1-tty`
 
+// DefaultFluentFormat is the entry format for fluent sinks
+// when not specified in a configuration.
+const DefaultFluentFormat = `json-fluent-compact`
+
+// DefaultHTTPFormat is the entry format for HTTP sinks
+// when not specified in a configuration.
+const DefaultHTTPFormat = `json-compact`
+
+// DefaultMaxBufferSize is the default amount of log entries, in bytes,
+// retained in memory by network sinks while the collector is
+// unavailable.
+const DefaultMaxBufferSize = 1 << 20 // 1 MiB
+
+// DefaultHTTPTimeout is the default timeout of requests sent by HTTP
+// sinks.
+const DefaultHTTPTimeout = 2 * time.Second
+
 // DefaultConfig returns a suitable default configuration when logging
 // is meant to primarily go to files.
 func DefaultConfig() (c Config) {
@@ -86,6 +104,16 @@ type Config struct {
 	// configuration value.
 	FileDefaults FileDefaults `yaml:"file-defaults,omitempty"`
 
+	// FluentDefaults represents the default configuration for fluent sinks,
+	// inherited when a specific fluent sink config does not provide a
+	// configuration value.
+	FluentDefaults FluentDefaults `yaml:"fluent-defaults,omitempty"`
+
+	// HTTPDefaults represents the default configuration for HTTP sinks,
+	// inherited when a specific HTTP sink config does not provide a
+	// configuration value.
+	HTTPDefaults HTTPDefaults `yaml:"http-defaults,omitempty"`
+
 	// Sinks represents the sink configurations.
 	Sinks SinkConfig `yaml:",omitempty"`
 
@@ -115,12 +143,19 @@ type CaptureFd2Config struct {
 type SinkConfig struct {
 	// FileGroups represents the list of configured file sinks.
 	FileGroups map[string]*FileConfig `yaml:"file-groups,omitempty"`
+	// FluentServers represents the list of configured fluent sinks.
+	FluentServers map[string]*FluentConfig `yaml:"fluent-servers,omitempty"`
+	// HTTPServers represents the list of configured HTTP sinks.
+	HTTPServers map[string]*HTTPConfig `yaml:"http-servers,omitempty"`
 	// Stderr represents the configuration for the stderr sink.
 	Stderr StderrConfig `yaml:",omitempty"`
 
-	// sortedFileGroupNames is used internally to
-	// make the Export() function deterministic.
-	sortedFileGroupNames []string
+	// sortedFileGroupNames, sortedFluentServerNames and
+	// sortedHTTPServerNames are used internally to make the Export()
+	// function deterministic.
+	sortedFileGroupNames    []string
+	sortedFluentServerNames []string
+	sortedHTTPServerNames   []string
 }
 
 // StderrConfig represents the configuration for the stderr sink.
@@ -234,6 +269,85 @@ type FileConfig struct {
 	prefix string
 }
 
+// NetworkSinkConfig represents the configuration shared by the sinks
+// which send log entries over the network.
+type NetworkSinkConfig struct {
+	// MaxBufferSize indicates the maximum amount of log entries, in
+	// bytes, that are retained in memory while the collector is
+	// unavailable. Entries are sent asynchronously, and the oldest
+	// entries are dropped when the buffer is full. If zero, entries
+	// are sent synchronously and are not retained.
+	MaxBufferSize *ByteSize `yaml:"max-buffer-size,omitempty"`
+
+	// CommonSinkConfig is the configuration common to all sinks. Note
+	// that although the idiom in Go is to place embedded fields at the
+	// beginning of a struct, we purposefully deviate from the idiom
+	// here to ensure that "general" options appear after the
+	// sink-specific options in YAML config dumps.
+	CommonSinkConfig `yaml:",inline"`
+}
+
+// FluentDefaults represent configuration defaults for fluent sinks.
+type FluentDefaults struct {
+	NetworkSinkConfig `yaml:",inline"`
+}
+
+// FluentConfig represents the configuration for one fluent sink.
+//
+// A fluent sink writes the formatted log entries over a TCP, UDP or
+// unix socket connection, as expected by the "tcp" and "udp" inputs of
+// Fluentd and Fluent Bit.
+type FluentConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelList `yaml:",omitempty"`
+
+	// Net is the protocol used to connect to the fluent server:
+	// tcp, tcp4, tcp6, udp, udp4, udp6 or unix. Defaults to tcp.
+	Net string `yaml:",omitempty"`
+
+	// Address is the network address of the fluent server, e.g.
+	// 127.0.0.1:5170 for TCP, or a socket path for unix.
+	Address string `yaml:",omitempty"`
+
+	// NetworkSinkConfig is inherited from FluentDefaults when not
+	// specified.
+	NetworkSinkConfig `yaml:",inline"`
+}
+
+// HTTPDefaults represent configuration defaults for HTTP sinks.
+type HTTPDefaults struct {
+	// Timeout is the timeout of the HTTP requests. If zero, requests
+	// do not time out.
+	Timeout *time.Duration `yaml:",omitempty"`
+
+	// UnsafeTLS disables the verification of the certificate of the
+	// HTTPS server.
+	UnsafeTLS *bool `yaml:"unsafe-tls,omitempty"`
+
+	// DisableKeepAlives prevents the reuse of the connection to the
+	// HTTP server across requests.
+	DisableKeepAlives *bool `yaml:"disable-keep-alives,omitempty"`
+
+	NetworkSinkConfig `yaml:",inline"`
+}
+
+// HTTPConfig represents the configuration for one HTTP sink.
+//
+// An HTTP sink sends each formatted log entry as the body of a POST
+// request to the configured URL.
+type HTTPConfig struct {
+	// Channels is the list of logging channels that use this sink.
+	Channels ChannelList `yaml:",omitempty"`
+
+	// Address is the URL of the HTTP server, e.g.
+	// https://collector.example.com:8080/logs.
+	Address string `yaml:",omitempty"`
+
+	// HTTPDefaults holds the parameters inherited from the
+	// http-defaults section when not specified.
+	HTTPDefaults `yaml:",inline"`
+}
+
 // IterateDirectories calls the provided fn on every directory linked to
 // by the configuration.
 func (c *Config) IterateDirectories(fn func(d string) error) error {
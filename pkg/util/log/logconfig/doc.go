@@ -24,6 +24,19 @@
 //       sync-writes: <bool>   # whether to sync each write, default false
 //       <common sink parameters>
 //
+//     fluent-defaults: #optional
+//       max-buffer-size: <sz> # max buffered entries while the server is down,
+//                             # default 1MiB, 0 for synchronous writes
+//       <common sink parameters>
+//
+//     http-defaults: #optional
+//       timeout: <duration>         # timeout of HTTP requests, default 2s
+//       unsafe-tls: <bool>          # skip verification of the server cert, default false
+//       disable-keep-alives: <bool> # open a new connection per request, default false
+//       max-buffer-size: <sz>       # max buffered entries while the server is down,
+//                                   # default 1MiB, 0 for synchronous writes
+//       <common sink parameters>
+//
 //     sinks: #optional
 //      stderr: #optional
 //       channels: <chans>        # channel selection for stderr output, default ALL
@@ -39,6 +52,28 @@
 //
 //        ... repeat ...
 //
+//      fluent-servers: #optional
+//        <sinkname>:
+//          channels: <chans>        # channel selection for this sink, mandatory
+//          net: <protocol>          # tcp (default), udp or unix
+//          address: <addr>          # network address of the server, mandatory
+//          max-buffer-size: <sz>    # defaults to fluent-defaults.max-buffer-size
+//          <common sink parameters> # if not specified, inherit from fluent-defaults
+//
+//        ... repeat ...
+//
+//      http-servers: #optional
+//        <sinkname>:
+//          channels: <chans>        # channel selection for this sink, mandatory
+//          address: <url>           # URL to POST the entries to, mandatory
+//          timeout: <duration>      # defaults to http-defaults.timeout
+//          unsafe-tls: <bool>       # defaults to http-defaults.unsafe-tls
+//          disable-keep-alives: <bool> # defaults to http-defaults.disable-keep-alives
+//          max-buffer-size: <sz>    # defaults to http-defaults.max-buffer-size
+//          <common sink parameters> # if not specified, inherit from http-defaults
+//
+//        ... repeat ...
+//
 //     capture-stray-errors: #optional
 //       enable: <bool>       # whether to enable internal fd2 capture
 //       dir: <optional>      # output directory, defaults to file-defaults.dir
@@ -49,11 +84,16 @@
 //       redact: <bool>        # whether to remove sensitive info, default false
 //       redactable: <bool>    # whether to strip redaction markers, default false
 //       format: <fmt>         # format to use for log enries, default
-//                             # crdb-v1 for files, crdb-v1-tty for stderr
+//                             # crdb-v1 for files, crdb-v1-tty for stderr,
+//                             # json-fluent-compact for fluent sinks,
+//                             # json-compact for http sinks
 //       exit-on-error: <bool> # whether to terminate upon a write error
-//                             # default true for file+stderr sinks
+//                             # default true for file+stderr sinks,
+//                             # false for network sinks; requires
+//                             # max-buffer-size: 0 for network sinks
 //       auditable: <bool>     # if true, activates sink-specific features
 //                             # that enhance non-repudiability.
-//                             # also implies exit-on-error: true.
+//                             # also implies exit-on-error: true,
+//                             # and max-buffer-size: 0 for network sinks.
 //
 package logconfig
@@ -189,6 +189,53 @@ func (c *Config) Export(onlyChans ChannelList) (string, string) {
 		}
 	}
 
+	// Export the network sinks.
+	//
+	// servers collects the declarations of the network servers.
+	servers := []string{}
+	serverNum := 1
+	exportServer := func(label string, chans ChannelList, nc NetworkSinkConfig) {
+		serverKey := fmt.Sprintf("s%d", serverNum)
+		serverNum++
+		target := serverKey
+
+		// Introduce a "buffer" box if the entries are sent
+		// asynchronously.
+		var bufproc, buflink []string
+		if *nc.MaxBufferSize > 0 {
+			bkey := fmt.Sprintf("buffer%d", serverNum)
+			serverNum++
+			bufproc = append(bufproc, fmt.Sprintf("card %s as \"buffer:%s\"", bkey, *nc.MaxBufferSize))
+			buflink = append(buflink, fmt.Sprintf("%s --> %s", bkey, target))
+			target = bkey
+		}
+
+		target, thisprocs, thislinks := process(target, nc.CommonSinkConfig)
+		hasLink := false
+		for _, ch := range chans.Channels {
+			if !chanSel.HasChannel(ch) {
+				continue
+			}
+			hasLink = true
+			links = append(links, fmt.Sprintf("%s --> %s", ch, target))
+		}
+		if hasLink {
+			processing = append(processing, bufproc...)
+			processing = append(processing, thisprocs...)
+			links = append(links, thislinks...)
+			links = append(links, buflink...)
+			servers = append(servers, fmt.Sprintf("queue %s as \"%s\"", serverKey, label))
+		}
+	}
+	for _, fn := range c.Sinks.sortedFluentServerNames {
+		fc := c.Sinks.FluentServers[fn]
+		exportServer(fmt.Sprintf("fluent:%s:%s", fc.Net, fc.Address), fc.Channels, fc.NetworkSinkConfig)
+	}
+	for _, hn := range c.Sinks.sortedHTTPServerNames {
+		hc := c.Sinks.HTTPServers[hn]
+		exportServer(fmt.Sprintf("http:%s", hc.Address), hc.Channels, hc.NetworkSinkConfig)
+	}
+
 	// Represent the processing stages, if any.
 	if len(processing) > 0 {
 		for _, p := range processing {
@@ -210,6 +257,15 @@ func (c *Config) Export(onlyChans ChannelList) (string, string) {
 		buf.WriteString("}\n")
 	}
 
+	// Represent the network servers, if any.
+	if len(servers) > 0 {
+		buf.WriteString("cloud network {\n")
+		for _, s := range servers {
+			fmt.Fprintf(&buf, " %s\n", s)
+		}
+		buf.WriteString("}\n")
+	}
+
 	// Export the relationships.
 	for _, l := range links {
 		fmt.Fprintf(&buf, "%s\n", l)
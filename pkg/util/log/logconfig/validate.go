@@ -13,6 +13,7 @@ package logconfig
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -68,6 +69,20 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		c.FileDefaults.Criticality = &bt
 	}
 
+	// Defaults for network sinks.
+	c.validateNetworkDefaults(&c.FluentDefaults.NetworkSinkConfig, DefaultFluentFormat)
+	c.validateNetworkDefaults(&c.HTTPDefaults.NetworkSinkConfig, DefaultHTTPFormat)
+	if c.HTTPDefaults.Timeout == nil {
+		t := DefaultHTTPTimeout
+		c.HTTPDefaults.Timeout = &t
+	}
+	if c.HTTPDefaults.UnsafeTLS == nil {
+		c.HTTPDefaults.UnsafeTLS = &bf
+	}
+	if c.HTTPDefaults.DisableKeepAlives == nil {
+		c.HTTPDefaults.DisableKeepAlives = &bf
+	}
+
 	// Validate and fill in defaults for file sinks.
 	for prefix, fc := range c.Sinks.FileGroups {
 		if fc == nil {
@@ -80,6 +95,40 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		}
 	}
 
+	// Validate and fill in defaults for network sinks. We process
+	// them in a deterministic order so that errors are reported
+	// consistently.
+	fluentServerNames := make([]string, 0, len(c.Sinks.FluentServers))
+	for serverName := range c.Sinks.FluentServers {
+		fluentServerNames = append(fluentServerNames, serverName)
+	}
+	sort.Strings(fluentServerNames)
+	for _, serverName := range fluentServerNames {
+		fc := c.Sinks.FluentServers[serverName]
+		if fc == nil {
+			fc = &FluentConfig{}
+			c.Sinks.FluentServers[serverName] = fc
+		}
+		if err := c.validateFluentConfig(fc); err != nil {
+			fmt.Fprintf(&errBuf, "fluent server %q: %v\n", serverName, err)
+		}
+	}
+	httpServerNames := make([]string, 0, len(c.Sinks.HTTPServers))
+	for serverName := range c.Sinks.HTTPServers {
+		httpServerNames = append(httpServerNames, serverName)
+	}
+	sort.Strings(httpServerNames)
+	for _, serverName := range httpServerNames {
+		hc := c.Sinks.HTTPServers[serverName]
+		if hc == nil {
+			hc = &HTTPConfig{}
+			c.Sinks.HTTPServers[serverName] = hc
+		}
+		if err := c.validateHTTPConfig(hc); err != nil {
+			fmt.Fprintf(&errBuf, "http server %q: %v\n", serverName, err)
+		}
+	}
+
 	// Defaults for stderr.
 	c.inheritCommonDefaults(&c.Sinks.Stderr.CommonSinkConfig, &c.FileDefaults.CommonSinkConfig)
 	if c.Sinks.Stderr.Filter == logpb.Severity_UNKNOWN {
@@ -121,6 +170,37 @@ func (c *Config) Validate(defaultLogDir *string) (resErr error) {
 		}
 	}
 
+	// Network sinks can share channels with other sinks, but they must
+	// capture at least one channel. Also elide the network sinks with
+	// severity set to NONE, and remember the names of the remaining
+	// ones for export.
+	c.Sinks.sortedFluentServerNames = nil
+	for _, serverName := range fluentServerNames {
+		fc := c.Sinks.FluentServers[serverName]
+		if len(fc.Channels.Channels) == 0 {
+			fmt.Fprintf(&errBuf, "fluent server %q: no channel selected\n", serverName)
+		}
+		fc.Channels.Sort()
+		if fc.Filter == logpb.Severity_NONE {
+			delete(c.Sinks.FluentServers, serverName)
+		} else {
+			c.Sinks.sortedFluentServerNames = append(c.Sinks.sortedFluentServerNames, serverName)
+		}
+	}
+	c.Sinks.sortedHTTPServerNames = nil
+	for _, serverName := range httpServerNames {
+		hc := c.Sinks.HTTPServers[serverName]
+		if len(hc.Channels.Channels) == 0 {
+			fmt.Fprintf(&errBuf, "http server %q: no channel selected\n", serverName)
+		}
+		hc.Channels.Sort()
+		if hc.Filter == logpb.Severity_NONE {
+			delete(c.Sinks.HTTPServers, serverName)
+		} else {
+			c.Sinks.sortedHTTPServerNames = append(c.Sinks.sortedHTTPServerNames, serverName)
+		}
+	}
+
 	// If capture-stray-errors was enabled, then perform some additional
 	// validation on it.
 	if c.CaptureFd2.Enable {
@@ -266,6 +346,102 @@ func (c *Config) validateFileConfig(fc *FileConfig, defaultLogDir *string) error
 	return nil
 }
 
+// validateNetworkDefaults fills in the defaults shared by the network
+// sinks of a given type.
+func (c *Config) validateNetworkDefaults(nc *NetworkSinkConfig, defaultFormat string) {
+	// No format -> use the default for this type of sink.
+	if nc.Format == nil {
+		s := defaultFormat
+		nc.Format = &s
+	}
+	// No criticality -> default false for network sinks, since
+	// collectors are expected to be occasionally unavailable.
+	if nc.Criticality == nil {
+		bf := false
+		nc.Criticality = &bf
+	}
+	// No buffer size -> default buffering.
+	if nc.MaxBufferSize == nil {
+		sz := ByteSize(DefaultMaxBufferSize)
+		nc.MaxBufferSize = &sz
+	}
+	// Inherit the remaining parameters from file-defaults.
+	c.inheritCommonDefaults(&nc.CommonSinkConfig, &c.FileDefaults.CommonSinkConfig)
+}
+
+func (c *Config) validateNetworkSinkConfig(nc, defaults *NetworkSinkConfig) error {
+	c.inheritCommonDefaults(&nc.CommonSinkConfig, &defaults.CommonSinkConfig)
+	if nc.MaxBufferSize == nil {
+		nc.MaxBufferSize = defaults.MaxBufferSize
+	}
+
+	// Apply the auditable flag if set. Buffered entries can be lost, so
+	// auditable sinks send their entries synchronously.
+	if *nc.Auditable {
+		bt := true
+		nc.Criticality = &bt
+		var noBuffer ByteSize
+		nc.MaxBufferSize = &noBuffer
+	}
+	nc.Auditable = nil
+
+	// Errors are not reported to the logger when sending entries
+	// asynchronously.
+	if *nc.Criticality && *nc.MaxBufferSize != 0 {
+		return errors.WithHint(errors.New("exit-on-error cannot be used with buffering"),
+			"Set max-buffer-size to 0 to disable buffering.")
+	}
+	return nil
+}
+
+func (c *Config) validateFluentConfig(fc *FluentConfig) error {
+	fc.Net = strings.ToLower(strings.TrimSpace(fc.Net))
+	switch fc.Net {
+	case "":
+		fc.Net = "tcp"
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix":
+	default:
+		return errors.Newf("unknown protocol: %q", fc.Net)
+	}
+	fc.Address = strings.TrimSpace(fc.Address)
+	if fc.Address == "" {
+		return errors.New("address cannot be empty")
+	}
+	return c.validateNetworkSinkConfig(&fc.NetworkSinkConfig, &c.FluentDefaults.NetworkSinkConfig)
+}
+
+func (c *Config) validateHTTPConfig(hc *HTTPConfig) error {
+	// Inherit HTTP-specific defaults.
+	if hc.Timeout == nil {
+		hc.Timeout = c.HTTPDefaults.Timeout
+	}
+	if hc.UnsafeTLS == nil {
+		hc.UnsafeTLS = c.HTTPDefaults.UnsafeTLS
+	}
+	if hc.DisableKeepAlives == nil {
+		hc.DisableKeepAlives = c.HTTPDefaults.DisableKeepAlives
+	}
+
+	hc.Address = strings.TrimSpace(hc.Address)
+	if hc.Address == "" {
+		return errors.New("address cannot be empty")
+	}
+	u, err := url.Parse(hc.Address)
+	if err != nil {
+		return errors.Wrap(err, "invalid address")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Newf("unsupported URL scheme: %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.Newf("missing host in address: %q", hc.Address)
+	}
+	if *hc.Timeout < 0 {
+		return errors.Newf("timeout cannot be negative: %s", *hc.Timeout)
+	}
+	return c.validateNetworkSinkConfig(&hc.NetworkSinkConfig, &c.HTTPDefaults.NetworkSinkConfig)
+}
+
 func normalizeDir(dir **string) error {
 	if *dir == nil {
 		return nil
@@ -47,5 +47,20 @@ type logSink interface {
 	emergencyOutput([]byte)
 }
 
+// closableSink is implemented by the sinks that hold resources
+// (e.g. network connections) which must be released when the logging
+// configuration is torn down.
+type closableSink interface {
+	logSink
+	close()
+}
+
 var _ logSink = (*stderrSink)(nil)
 var _ logSink = (*fileSink)(nil)
+var _ logSink = (*fluentSink)(nil)
+var _ logSink = (*httpSink)(nil)
+var _ logSink = (*bufferSink)(nil)
+
+var _ closableSink = (*fluentSink)(nil)
+var _ closableSink = (*httpSink)(nil)
+var _ closableSink = (*bufferSink)(nil)
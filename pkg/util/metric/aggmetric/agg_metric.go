@@ -43,6 +43,11 @@ func (b Builder) Counter(metadata metric.Metadata) *AggCounter {
 	return NewCounter(metadata, b.labels...)
 }
 
+// CounterFloat64 constructs a new AggCounterFloat64 with the Builder's labels.
+func (b Builder) CounterFloat64(metadata metric.Metadata) *AggCounterFloat64 {
+	return NewCounterFloat64(metadata, b.labels...)
+}
+
 type childSet struct {
 	labels []string
 	mu     struct {
@@ -0,0 +1,129 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package aggmetric
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/gogo/protobuf/proto"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+)
+
+// AggCounterFloat64 maintains a float64 value as the sum of its children. It
+// is the floating point counterpart of AggCounter.
+type AggCounterFloat64 struct {
+	g metric.CounterFloat64
+	childSet
+}
+
+var _ metric.Iterable = (*AggCounterFloat64)(nil)
+var _ metric.PrometheusIterable = (*AggCounterFloat64)(nil)
+var _ metric.PrometheusExportable = (*AggCounterFloat64)(nil)
+
+// NewCounterFloat64 constructs a new AggCounterFloat64.
+func NewCounterFloat64(metadata metric.Metadata, childLabels ...string) *AggCounterFloat64 {
+	c := &AggCounterFloat64{g: *metric.NewCounterFloat64(metadata)}
+	c.init(childLabels)
+	return c
+}
+
+// GetName is part of the metric.Iterable interface.
+func (c *AggCounterFloat64) GetName() string { return c.g.GetName() }
+
+// GetHelp is part of the metric.Iterable interface.
+func (c *AggCounterFloat64) GetHelp() string { return c.g.GetHelp() }
+
+// GetMeasurement is part of the metric.Iterable interface.
+func (c *AggCounterFloat64) GetMeasurement() string { return c.g.GetMeasurement() }
+
+// GetUnit is part of the metric.Iterable interface.
+func (c *AggCounterFloat64) GetUnit() metric.Unit { return c.g.GetUnit() }
+
+// GetMetadata is part of the metric.Iterable interface.
+func (c *AggCounterFloat64) GetMetadata() metric.Metadata { return c.g.GetMetadata() }
+
+// Inspect is part of the metric.Iterable interface.
+func (c *AggCounterFloat64) Inspect(f func(interface{})) { f(c) }
+
+// GetType is part of the metric.PrometheusExportable interface.
+func (c *AggCounterFloat64) GetType() *io_prometheus_client.MetricType {
+	return c.g.GetType()
+}
+
+// GetLabels is part of the metric.PrometheusExportable interface.
+func (c *AggCounterFloat64) GetLabels() []*io_prometheus_client.LabelPair {
+	return c.g.GetLabels()
+}
+
+// ToPrometheusMetric is part of the metric.PrometheusExportable interface.
+func (c *AggCounterFloat64) ToPrometheusMetric() *io_prometheus_client.Metric {
+	return c.g.ToPrometheusMetric()
+}
+
+// Count returns the aggregate count of all of its current and past children.
+func (c *AggCounterFloat64) Count() float64 {
+	return c.g.Count()
+}
+
+// AddChild adds a CounterFloat64 to this AggCounterFloat64. This method panics
+// if a CounterFloat64 already exists for this set of labelVals.
+func (c *AggCounterFloat64) AddChild(labelVals ...string) *CounterFloat64 {
+	child := &CounterFloat64{
+		parent:           c,
+		labelValuesSlice: labelValuesSlice(labelVals),
+	}
+	c.add(child)
+	return child
+}
+
+// CounterFloat64 is a child of a AggCounterFloat64. When it is incremented, so
+// too is the parent. When metrics are collected by prometheus, each of the
+// children will appear with a distinct label, however, when cockroach
+// internally collects metrics, only the parent is collected.
+type CounterFloat64 struct {
+	parent *AggCounterFloat64
+	labelValuesSlice
+	bits uint64
+}
+
+// ToPrometheusMetric constructs a prometheus metric for this CounterFloat64.
+func (g *CounterFloat64) ToPrometheusMetric() *io_prometheus_client.Metric {
+	return &io_prometheus_client.Metric{
+		Counter: &io_prometheus_client.Counter{
+			Value: proto.Float64(g.Value()),
+		},
+	}
+}
+
+// Destroy disconnects this CounterFloat64 from its parents. Like
+// Counter.Destroy, it does not decrement its value from its parent.
+func (g *CounterFloat64) Destroy() {
+	g.parent.remove(g)
+}
+
+// Value returns the CounterFloat64's current value.
+func (g *CounterFloat64) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Inc increments the CounterFloat64's value.
+func (g *CounterFloat64) Inc(i float64) {
+	g.parent.g.Inc(i)
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		updated := math.Float64bits(math.Float64frombits(old) + i)
+		if atomic.CompareAndSwapUint64(&g.bits, old, updated) {
+			return
+		}
+	}
+}
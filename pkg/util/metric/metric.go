@@ -375,6 +375,61 @@ func (c *Counter) GetMetadata() Metadata {
 	return baseMetadata
 }
 
+// A CounterFloat64 holds a single, monotonically increasing float64 value.
+type CounterFloat64 struct {
+	Metadata
+	bits uint64
+}
+
+// NewCounterFloat64 creates a CounterFloat64.
+func NewCounterFloat64(metadata Metadata) *CounterFloat64 {
+	return &CounterFloat64{Metadata: metadata}
+}
+
+// Inc atomically increments the counter by the given (non-negative) value.
+func (c *CounterFloat64) Inc(v float64) {
+	for {
+		old := atomic.LoadUint64(&c.bits)
+		updated := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(&c.bits, old, updated) {
+			return
+		}
+	}
+}
+
+// Count returns the counter's current value.
+func (c *CounterFloat64) Count() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&c.bits))
+}
+
+// GetType returns the prometheus type enum for this metric.
+func (c *CounterFloat64) GetType() *prometheusgo.MetricType {
+	return prometheusgo.MetricType_COUNTER.Enum()
+}
+
+// Inspect calls the given closure with itself.
+func (c *CounterFloat64) Inspect(f func(interface{})) { f(c) }
+
+// MarshalJSON marshals to JSON.
+func (c *CounterFloat64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.Count())
+}
+
+// ToPrometheusMetric returns a filled-in prometheus metric of the right type.
+func (c *CounterFloat64) ToPrometheusMetric() *prometheusgo.Metric {
+	return &prometheusgo.Metric{
+		Counter: &prometheusgo.Counter{Value: proto.Float64(c.Count())},
+	}
+}
+
+// GetMetadata returns the metric's metadata including the Prometheus
+// MetricType.
+func (c *CounterFloat64) GetMetadata() Metadata {
+	baseMetadata := c.Metadata
+	baseMetadata.MetricType = prometheusgo.MetricType_COUNTER
+	return baseMetadata
+}
+
 // A Gauge atomically stores a single integer value.
 type Gauge struct {
 	Metadata
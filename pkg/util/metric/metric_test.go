@@ -90,6 +90,17 @@ func TestCounter(t *testing.T) {
 	testMarshal(t, c, "90")
 }
 
+func TestCounterFloat64(t *testing.T) {
+	c := NewCounterFloat64(emptyMetadata)
+	c.Inc(0.5)
+	c.Inc(89.75)
+	if v := c.Count(); v != 90.25 {
+		t.Fatalf("unexpected value: %f", v)
+	}
+
+	testMarshal(t, c, "90.25")
+}
+
 func setNow(d time.Duration) {
 	now = func() time.Time {
 		return time.Time{}.Add(d)
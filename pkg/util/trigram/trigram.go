@@ -0,0 +1,197 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package trigram implements the trigram functions of the Postgres pg_trgm
+// extension. See https://www.postgresql.org/docs/current/pgtrgm.html.
+//
+// A trigram is a group of three consecutive characters of a word. The words
+// of a string are its maximal sequences of letters and digits, in lowercase.
+// Each word is padded with two spaces at its start and one space at its end
+// before its trigrams are extracted, so the trigrams of "Cat" are "  c",
+// " ca", "cat" and "at ".
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MakeTrigrams returns the sorted set of the trigrams of the given string. If
+// pad is false, the words are not padded, so a trigram only ever contains the
+// characters of a word.
+func MakeTrigrams(s string, pad bool) []string {
+	return sortAndDedup(makeTrigramSequence(s, pad))
+}
+
+// sortAndDedup sorts the given trigrams and removes the duplicates.
+func sortAndDedup(trigrams []string) []string {
+	if len(trigrams) == 0 {
+		return nil
+	}
+	sort.Strings(trigrams)
+	n := 1
+	for i := 1; i < len(trigrams); i++ {
+		if trigrams[i] != trigrams[n-1] {
+			trigrams[n] = trigrams[i]
+			n++
+		}
+	}
+	return trigrams[:n]
+}
+
+// makeTrigramSequence returns the trigrams of the given string in the order
+// in which they appear in the string, with duplicates.
+func makeTrigramSequence(s string, pad bool) []string {
+	var trigrams []string
+	for _, word := range splitWords(s) {
+		trigrams = appendWordTrigrams(trigrams, word, pad, pad)
+	}
+	return trigrams
+}
+
+// splitWords returns the lowercase words of the given string.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+// isWordRune returns true if the given character is part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// appendWordTrigrams appends the trigrams of the given word to trigrams,
+// padding the start and the end of the word as requested.
+func appendWordTrigrams(trigrams []string, word string, padStart, padEnd bool) []string {
+	if padStart {
+		word = "  " + word
+	}
+	if padEnd {
+		word = word + " "
+	}
+	runes := []rune(word)
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams = append(trigrams, string(runes[i:i+3]))
+	}
+	return trigrams
+}
+
+// Similarity returns a number between 0 and 1 that indicates how similar the
+// two given strings are: the number of trigrams they share divided by the
+// number of distinct trigrams of the two strings.
+func Similarity(l, r string) float64 {
+	lTrigrams, rTrigrams := MakeTrigrams(l, true /* pad */), MakeTrigrams(r, true /* pad */)
+	// Both sets are sorted, so their intersection is computed with a merge.
+	shared := 0
+	for i, j := 0, 0; i < len(lTrigrams) && j < len(rTrigrams); {
+		switch {
+		case lTrigrams[i] < rTrigrams[j]:
+			i++
+		case lTrigrams[i] > rTrigrams[j]:
+			j++
+		default:
+			shared++
+			i++
+			j++
+		}
+	}
+	return ratio(shared, len(lTrigrams)+len(rTrigrams)-shared)
+}
+
+// WordSimilarity returns a number between 0 and 1 that indicates the greatest
+// similarity between the set of trigrams of l and any continuous extent of
+// the ordered sequence of trigrams of r. It is highest when l is similar to a
+// word or to a sequence of words of r.
+func WordSimilarity(l, r string) float64 {
+	lTrigrams := MakeTrigrams(l, true /* pad */)
+	if len(lTrigrams) == 0 {
+		return 0
+	}
+	lSet := make(map[string]struct{}, len(lTrigrams))
+	for _, t := range lTrigrams {
+		lSet[t] = struct{}{}
+	}
+	rSequence := makeTrigramSequence(r, true /* pad */)
+	best := 0.0
+	extent := make(map[string]struct{}, len(rSequence))
+	for start := range rSequence {
+		for t := range extent {
+			delete(extent, t)
+		}
+		shared := 0
+		for _, t := range rSequence[start:] {
+			if _, ok := extent[t]; ok {
+				continue
+			}
+			extent[t] = struct{}{}
+			if _, ok := lSet[t]; ok {
+				shared++
+			}
+			if sim := ratio(shared, len(lTrigrams)+len(extent)-shared); sim > best {
+				best = sim
+			}
+		}
+	}
+	return best
+}
+
+// ratio returns n/d, or 0 if d is 0.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// MakeLikePatternTrigrams returns the sorted set of the trigrams which any
+// string matching the given LIKE pattern must contain, once padded. The
+// pattern is matched case-insensitively, so the trigrams are also valid for
+// ILIKE. The pattern uses the default escape character, the backslash. No
+// trigram is returned if the pattern does not contain any word of at least
+// three characters which is not interrupted by a wildcard.
+func MakeLikePatternTrigrams(pattern string) []string {
+	var trigrams []string
+	var word []rune
+	// padStart is true if the current word is known to be at the start of a
+	// word of the matching strings.
+	padStart := true
+	// endWord appends the trigrams of the current word. padEnd is true if the
+	// word is known to be at the end of a word of the matching strings.
+	endWord := func(padEnd bool) {
+		if len(word) > 0 {
+			trigrams = appendWordTrigrams(trigrams, strings.ToLower(string(word)), padStart, padEnd)
+			word = word[:0]
+		}
+	}
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '%' || r == '_':
+			// A wildcard can extend the word on either side.
+			endWord(false /* padEnd */)
+			padStart = false
+			continue
+		case r == '\\' && i+1 < len(runes):
+			i++
+			r = runes[i]
+		}
+		if isWordRune(r) {
+			word = append(word, r)
+		} else {
+			endWord(true /* padEnd */)
+			padStart = true
+		}
+	}
+	endWord(true /* padEnd */)
+	return sortAndDedup(trigrams)
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	tt := []struct {
+		Source   string
+		Pad      bool
+		Expected []string
+	}{
+		{
+			Source:   "Cat",
+			Pad:      true,
+			Expected: []string{"  c", " ca", "at ", "cat"},
+		},
+		{
+			Source:   "Cat",
+			Pad:      false,
+			Expected: []string{"cat"},
+		},
+		{
+			Source:   "a",
+			Pad:      true,
+			Expected: []string{"  a", " a "},
+		},
+		{
+			Source:   "foo|Foo foo",
+			Pad:      true,
+			Expected: []string{"  f", " fo", "foo", "oo "},
+		},
+		{
+			Source:   "ab",
+			Pad:      false,
+			Expected: nil,
+		},
+		{
+			Source:   "!?",
+			Pad:      true,
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range tt {
+		got := MakeTrigrams(tc.Source, tc.Pad)
+		if !reflect.DeepEqual(got, tc.Expected) {
+			t.Fatalf("error making trigrams of %q: got %q, expected %q", tc.Source, got, tc.Expected)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tt := []struct {
+		Left     string
+		Right    string
+		Expected string
+	}{
+		{Left: "word", Right: "two words", Expected: "0.363636"},
+		{Left: "word", Right: "WORD", Expected: "1.000000"},
+		{Left: "cat", Right: "dog", Expected: "0.000000"},
+		{Left: "", Right: "", Expected: "0.000000"},
+	}
+
+	for _, tc := range tt {
+		got := fmt.Sprintf("%f", Similarity(tc.Left, tc.Right))
+		if got != tc.Expected {
+			t.Fatalf("error computing similarity(%q, %q): got %s, expected %s",
+				tc.Left, tc.Right, got, tc.Expected)
+		}
+	}
+}
+
+func TestWordSimilarity(t *testing.T) {
+	tt := []struct {
+		Left     string
+		Right    string
+		Expected string
+	}{
+		{Left: "word", Right: "two words", Expected: "0.800000"},
+		{Left: "two words", Right: "word", Expected: "0.400000"},
+		{Left: "word", Right: "word", Expected: "1.000000"},
+		{Left: "", Right: "word", Expected: "0.000000"},
+	}
+
+	for _, tc := range tt {
+		got := fmt.Sprintf("%f", WordSimilarity(tc.Left, tc.Right))
+		if got != tc.Expected {
+			t.Fatalf("error computing word_similarity(%q, %q): got %s, expected %s",
+				tc.Left, tc.Right, got, tc.Expected)
+		}
+	}
+}
+
+func TestMakeLikePatternTrigrams(t *testing.T) {
+	tt := []struct {
+		Pattern  string
+		Expected []string
+	}{
+		{
+			Pattern:  "%foo%",
+			Expected: []string{"foo"},
+		},
+		{
+			Pattern:  "Foo%",
+			Expected: []string{"  f", " fo", "foo"},
+		},
+		{
+			Pattern:  "%foo",
+			Expected: []string{"foo", "oo "},
+		},
+		{
+			Pattern:  "%ab_cd%",
+			Expected: nil,
+		},
+		{
+			Pattern:  "%ab cd%",
+			Expected: []string{"  c", " cd", "ab "},
+		},
+		{
+			Pattern:  `%a\%bcd%`,
+			Expected: []string{"  b", " bc", "bcd"},
+		},
+	}
+
+	for _, tc := range tt {
+		got := MakeLikePatternTrigrams(tc.Pattern)
+		if !reflect.DeepEqual(got, tc.Expected) {
+			t.Fatalf("error making trigrams of pattern %q: got %q, expected %q", tc.Pattern, got, tc.Expected)
+		}
+	}
+}